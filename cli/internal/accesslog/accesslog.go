@@ -0,0 +1,381 @@
+// Package accesslog reads the proxy's JSON access logs and summarizes
+// traffic per host: request counts, status code classes, and latency.
+package accesslog
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Entry is the subset of a Caddy access log line that grove uses
+type Entry struct {
+	Time     time.Time
+	Host     string
+	Method   string
+	URI      string
+	Status   int
+	Duration time.Duration
+}
+
+// caddyEntry mirrors Caddy's JSON access log format
+type caddyEntry struct {
+	TS      float64 `json:"ts"`
+	Request struct {
+		Host   string `json:"host"`
+		Method string `json:"method"`
+		URI    string `json:"uri"`
+	} `json:"request"`
+	Duration float64 `json:"duration"` // seconds
+	Status   int     `json:"status"`
+}
+
+// HostStats summarizes requests to a single host
+type HostStats struct {
+	Host     string         `json:"host"`
+	Requests int            `json:"requests"`
+	Status   map[string]int `json:"status"` // "2xx", "3xx", "4xx", "5xx"
+	P50      time.Duration  `json:"p50_ns"`
+	P95      time.Duration  `json:"p95_ns"`
+	Last     time.Time      `json:"last_request,omitempty"`
+
+	durations []time.Duration
+}
+
+// Path returns the access log file for a server
+func Path(dir, server string) string {
+	return filepath.Join(dir, server+".log")
+}
+
+// Parse reads access log entries from r, skipping lines it can't decode
+func Parse(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var ce caddyEntry
+		if err := json.Unmarshal(scanner.Bytes(), &ce); err != nil || ce.Request.Host == "" {
+			continue
+		}
+
+		sec, frac := math.Modf(ce.TS)
+		entries = append(entries, Entry{
+			Time:     time.Unix(int64(sec), int64(frac*1e9)),
+			Host:     stripPort(ce.Request.Host),
+			Method:   ce.Request.Method,
+			URI:      ce.Request.URI,
+			Status:   ce.Status,
+			Duration: time.Duration(ce.Duration * float64(time.Second)),
+		})
+	}
+
+	return entries, scanner.Err()
+}
+
+// ReadFile parses a single access log, decompressing it if it's gzipped
+// like the logs Caddy rolls; a missing file has no entries
+func ReadFile(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	if !isGzip(path) {
+		return Parse(f)
+	}
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	return Parse(gz)
+}
+
+func isGzip(path string) bool {
+	return strings.HasSuffix(path, ".gz")
+}
+
+// logFiles returns the access logs in dir, rolled ones included
+func logFiles(dir string) ([]string, error) {
+	return filepath.Glob(filepath.Join(dir, "*.log*"))
+}
+
+// ReadDir parses every access log in dir. Rolled files, gzipped or not,
+// are included so stats cover everything that's still on disk.
+func ReadDir(dir string) ([]Entry, error) {
+	files, err := logFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, file := range files {
+		fileEntries, err := ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, fileEntries...)
+	}
+	return entries, nil
+}
+
+// Summarize groups entries at or after since by host, busiest host first
+func Summarize(entries []Entry, since time.Time) []*HostStats {
+	byHost := make(map[string]*HostStats)
+	for _, e := range entries {
+		if e.Time.Before(since) {
+			continue
+		}
+
+		s, ok := byHost[e.Host]
+		if !ok {
+			s = &HostStats{Host: e.Host, Status: make(map[string]int)}
+			byHost[e.Host] = s
+		}
+		s.Requests++
+		s.Status[StatusClass(e.Status)]++
+		s.durations = append(s.durations, e.Duration)
+		if e.Time.After(s.Last) {
+			s.Last = e.Time
+		}
+	}
+
+	stats := make([]*HostStats, 0, len(byHost))
+	for _, s := range byHost {
+		sort.Slice(s.durations, func(i, j int) bool { return s.durations[i] < s.durations[j] })
+		s.P50 = percentile(s.durations, 0.50)
+		s.P95 = percentile(s.durations, 0.95)
+		stats = append(stats, s)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Requests != stats[j].Requests {
+			return stats[i].Requests > stats[j].Requests
+		}
+		return stats[i].Host < stats[j].Host
+	})
+	return stats
+}
+
+// SummarizeServers is like Summarize but groups hosts by the worktree
+// server they route to, so subdomains count toward their server. The
+// returned stats' Host field holds the server name.
+func SummarizeServers(entries []Entry, since time.Time, tld string) []*HostStats {
+	byServer := make([]Entry, 0, len(entries))
+	for _, e := range entries {
+		if name := ServerName(e.Host, tld); name != "" {
+			e.Host = name
+			byServer = append(byServer, e)
+		}
+	}
+	return Summarize(byServer, since)
+}
+
+// ServerName returns the worktree server a proxied host routes to, e.g.
+// "feature-auth" for both feature-auth.localhost and api.feature-auth.localhost
+func ServerName(host, tld string) string {
+	rest, ok := strings.CutSuffix(host, "."+tld)
+	if !ok || rest == "" {
+		return ""
+	}
+	if i := strings.LastIndex(rest, "."); i >= 0 {
+		return rest[i+1:]
+	}
+	return rest
+}
+
+// StatusClass returns the status code class (e.g. "2xx")
+func StatusClass(status int) string {
+	if status < 100 || status > 599 {
+		return "other"
+	}
+	return string(rune('0'+status/100)) + "xx"
+}
+
+// percentile returns the nearest-rank percentile of sorted durations
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[max(rank, 0)]
+}
+
+func stripPort(host string) string {
+	if i := strings.LastIndex(host, ":"); i > 0 && !strings.Contains(host[i:], "]") {
+		return host[:i]
+	}
+	return host
+}
+
+// Follower reads what's appended to access logs, for live request rates
+type Follower struct {
+	offsets map[string]int64
+}
+
+// NewFollower creates a Follower that hasn't read any logs yet
+func NewFollower() *Follower {
+	return &Follower{offsets: make(map[string]int64)}
+}
+
+// Read returns the entries appended to the access log at path since the
+// last Read of it. The first Read of a log only notes where it ends; a
+// log that shrank (was rotated) is read from the start.
+func (f *Follower) Read(path string) ([]Entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			delete(f.offsets, path)
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset, seen := f.offsets[path]
+	if !seen {
+		f.offsets[path] = info.Size()
+		return nil, nil
+	}
+	if info.Size() < offset {
+		offset = 0
+	}
+
+	entries, next, err := readFrom(file, offset)
+	if err != nil {
+		return nil, err
+	}
+	f.offsets[path] = next
+	return entries, nil
+}
+
+// readFrom parses the complete lines of file after offset and returns the
+// offset to read from next, leaving a partly written line for then
+func readFrom(file *os.File, offset int64) ([]Entry, int64, error) {
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, offset, err
+	}
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, offset, err
+	}
+	end := bytes.LastIndexByte(data, '\n') + 1
+	entries, err := Parse(bytes.NewReader(data[:end]))
+	return entries, offset + int64(end), err
+}
+
+// DirReader reads a directory of access logs again and again, parsing only
+// what changed since the last Read: lines appended to a log, and logs that
+// are new or were replaced. Entries older than the window it's asked for
+// are dropped, so it holds about that much traffic.
+type DirReader struct {
+	dir   string
+	files map[string]*dirFile
+}
+
+// dirFile is what a DirReader read of one log
+type dirFile struct {
+	info    os.FileInfo
+	offset  int64
+	entries []Entry
+}
+
+// NewDirReader creates a DirReader for the access logs in dir
+func NewDirReader(dir string) *DirReader {
+	return &DirReader{dir: dir, files: make(map[string]*dirFile)}
+}
+
+// Read returns the entries at or after since in every access log in the
+// directory, rolled ones included
+func (d *DirReader) Read(since time.Time) ([]Entry, error) {
+	paths, err := logFiles(d.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(paths))
+	var entries []Entry
+	for _, path := range paths {
+		seen[path] = true
+		f, err := d.readFile(path, d.files[path])
+		if err != nil {
+			return nil, err
+		}
+		if f == nil {
+			delete(d.files, path)
+			continue
+		}
+		f.entries = slices.DeleteFunc(f.entries, func(e Entry) bool { return e.Time.Before(since) })
+		d.files[path] = f
+		entries = append(entries, f.entries...)
+	}
+	for path := range d.files {
+		if !seen[path] {
+			delete(d.files, path)
+		}
+	}
+	return entries, nil
+}
+
+// readFile brings prev, what was read of the log at path before, up to
+// date. It returns nil if the log is gone.
+func (d *DirReader) readFile(path string, prev *dirFile) (*dirFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	same := prev != nil && os.SameFile(prev.info, info)
+	if same && info.Size() == prev.info.Size() && info.ModTime().Equal(prev.info.ModTime()) {
+		return prev, nil
+	}
+
+	// Caddy only appends to the live log and rolls it by renaming, so a
+	// log that's the same file and hasn't shrunk just grew
+	if same && !isGzip(path) && info.Size() >= prev.offset {
+		entries, offset, err := readFrom(file, prev.offset)
+		if err != nil {
+			return nil, err
+		}
+		return &dirFile{info: info, offset: offset, entries: append(prev.entries, entries...)}, nil
+	}
+
+	if isGzip(path) {
+		entries, err := ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return &dirFile{info: info, offset: info.Size(), entries: entries}, nil
+	}
+	entries, offset, err := readFrom(file, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &dirFile{info: info, offset: offset, entries: entries}, nil
+}
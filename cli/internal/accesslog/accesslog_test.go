@@ -0,0 +1,202 @@
+package accesslog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleLog = `{"level":"info","ts":1700000000.5,"logger":"http.log.access","msg":"handled request","request":{"remote_ip":"127.0.0.1","proto":"HTTP/2.0","method":"GET","host":"feature.localhost","uri":"/"},"duration":0.010,"size":12,"status":200}
+{"level":"info","ts":1700000001,"logger":"http.log.access","msg":"handled request","request":{"method":"GET","host":"feature.localhost","uri":"/missing"},"duration":0.020,"status":404}
+{"level":"info","ts":1700000002,"logger":"http.log.access","msg":"handled request","request":{"method":"POST","host":"api.feature.localhost:443","uri":"/graphql"},"duration":0.300,"status":502}
+not json
+{"level":"info","ts":1700000003,"logger":"http.log.access","msg":"handled request","request":{"method":"GET","host":"other.localhost","uri":"/"},"duration":0.001,"status":301}
+`
+
+func TestParse(t *testing.T) {
+	entries, err := Parse(strings.NewReader(sampleLog))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("got %d entries, want 4", len(entries))
+	}
+
+	e := entries[0]
+	if e.Host != "feature.localhost" || e.Status != 200 || e.Duration != 10*time.Millisecond {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+	if e.Time.UnixMilli() != 1700000000500 {
+		t.Errorf("time = %v, want fractional timestamp preserved", e.Time)
+	}
+	if entries[2].Host != "api.feature.localhost" {
+		t.Errorf("port should be stripped from host, got %q", entries[2].Host)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	entries, _ := Parse(strings.NewReader(sampleLog))
+
+	stats := Summarize(entries, time.Time{})
+	if len(stats) != 3 {
+		t.Fatalf("got %d hosts, want 3", len(stats))
+	}
+
+	top := stats[0]
+	if top.Host != "feature.localhost" || top.Requests != 2 {
+		t.Errorf("busiest host = %s (%d), want feature.localhost (2)", top.Host, top.Requests)
+	}
+	if top.Status["2xx"] != 1 || top.Status["4xx"] != 1 {
+		t.Errorf("status classes = %v", top.Status)
+	}
+	if top.P50 != 10*time.Millisecond || top.P95 != 20*time.Millisecond {
+		t.Errorf("p50/p95 = %v/%v, want 10ms/20ms", top.P50, top.P95)
+	}
+
+	// Entries before since are excluded
+	recent := Summarize(entries, time.Unix(1700000002, 0))
+	if len(recent) != 2 {
+		t.Errorf("got %d hosts since cutoff, want 2", len(recent))
+	}
+}
+
+func TestSummarizeServers(t *testing.T) {
+	entries, _ := Parse(strings.NewReader(sampleLog))
+
+	stats := SummarizeServers(entries, time.Time{}, "localhost")
+	if len(stats) != 2 {
+		t.Fatalf("got %d servers, want 2", len(stats))
+	}
+	if stats[0].Host != "feature" || stats[0].Requests != 3 || stats[0].Status["5xx"] != 1 {
+		t.Errorf("unexpected feature stats: %+v", stats[0])
+	}
+}
+
+func TestServerName(t *testing.T) {
+	tests := map[string]string{
+		"feature.localhost":          "feature",
+		"api.feature.localhost":      "feature",
+		"a.b.feature.test":           "",
+		"feature.test":               "",
+		"localhost":                  "",
+		"tenant.feature-x.localhost": "feature-x",
+	}
+	for host, want := range tests {
+		if got := ServerName(host, "localhost"); got != want {
+			t.Errorf("ServerName(%q) = %q, want %q", host, got, want)
+		}
+	}
+}
+
+func TestFollower(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api.log")
+	line := func(ts float64) string {
+		return fmt.Sprintf(`{"ts":%f,"request":{"host":"api.localhost","method":"GET","uri":"/"},"duration":0.01,"status":200}`+"\n", ts)
+	}
+	if err := os.WriteFile(path, []byte(line(1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	f := NewFollower()
+	if entries, err := f.Read(path); err != nil || len(entries) != 0 {
+		t.Fatalf("first Read = %d entries, %v; want none", len(entries), err)
+	}
+
+	file, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	file.WriteString(line(2) + line(3)[:20]) //nolint:errcheck
+	entries, err := f.Read(path)
+	if err != nil || len(entries) != 1 || entries[0].Time.Unix() != 2 {
+		t.Fatalf("Read = %+v, %v; want the one complete new line", entries, err)
+	}
+	file.WriteString(line(3)[20:]) //nolint:errcheck
+	file.Close()
+	if entries, _ := f.Read(path); len(entries) != 1 || entries[0].Time.Unix() != 3 {
+		t.Errorf("Read = %+v, want the line finished since", entries)
+	}
+
+	// Rotated: read from the start
+	if err := os.WriteFile(path, []byte(line(4)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := f.Read(path); len(entries) != 1 || entries[0].Time.Unix() != 4 {
+		t.Errorf("Read after rotation = %+v, want the new log's line", entries)
+	}
+}
+
+func TestReadDir_Gzipped(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "feature.log"), []byte(sampleLog), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Caddy gzips the logs it rolls
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte(sampleLog)) //nolint:errcheck
+	gz.Close()
+	if err := os.WriteFile(filepath.Join(dir, "feature-2023-11-14T22-13-20.000.log.gz"), buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 8 {
+		t.Errorf("ReadDir = %d entries, want 8 (4 from each log)", len(entries))
+	}
+}
+
+func TestDirReader(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "api.log")
+	line := func(ts float64) string {
+		return fmt.Sprintf(`{"ts":%f,"request":{"host":"api.localhost","method":"GET","uri":"/"},"duration":0.01,"status":200}`+"\n", ts)
+	}
+	times := func(entries []Entry) []int64 {
+		var ts []int64
+		for _, e := range entries {
+			ts = append(ts, e.Time.Unix())
+		}
+		slices.Sort(ts)
+		return ts
+	}
+	if err := os.WriteFile(path, []byte(line(1)+line(2)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewDirReader(dir)
+	entries, err := r.Read(time.Unix(0, 0))
+	if err != nil || !slices.Equal(times(entries), []int64{1, 2}) {
+		t.Fatalf("Read = %v, %v; want entries at 1, 2", times(entries), err)
+	}
+
+	file, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	file.WriteString(line(3) + line(4)[:20]) //nolint:errcheck
+	file.Close()
+	if entries, _ := r.Read(time.Unix(2, 0)); !slices.Equal(times(entries), []int64{2, 3}) {
+		t.Errorf("Read after append = %v, want 2, 3: older entries dropped, partial line left", times(entries))
+	}
+
+	// Rolled: the old log is renamed and a new one started
+	if err := os.Rename(path, filepath.Join(dir, "api-1.log")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(line(5)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := r.Read(time.Unix(2, 0)); !slices.Equal(times(entries), []int64{2, 3, 5}) {
+		t.Errorf("Read after roll = %v, want 2, 3, 5", times(entries))
+	}
+
+	os.Remove(filepath.Join(dir, "api-1.log"))
+	if entries, _ := r.Read(time.Unix(2, 0)); !slices.Equal(times(entries), []int64{5}) {
+		t.Errorf("Read after removing the rolled log = %v, want 5", times(entries))
+	}
+}
@@ -0,0 +1,248 @@
+// Package api serves grove's local API: a small, versioned JSON interface
+// to the registry for the menubar app and other local clients, so they
+// don't have to run the CLI and parse its output.
+//
+// Endpoints:
+//
+//	GET  /v1/health               {"status":"ok","version":"...","timestamp":"..."}
+//	GET  /v1/servers              {"servers":[Server...]}
+//	GET  /v1/servers/{name}       Server
+//	POST /v1/servers/{name}/start Server, once started
+//	POST /v1/servers/{name}/stop  Server, once stopped
+//	GET  /v1/events               Server-sent events: a "servers" event with
+//	                              the list whenever it changes
+//
+// Server is output.Server, the same shape 'grove start --json' prints.
+// Errors are {"error":"..."} with a 4xx or 5xx status.
+//
+// Over TCP, requests must be addressed to localhost so a DNS rebinding page
+// can't reach the API, and requests from web pages of another origin are
+// refused everywhere, since a cross-origin POST needs no preflight and
+// starting a server runs the project's command.
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/iheanyi/grove/internal/output"
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+// ErrNotFound is returned by Actions for names that aren't registered
+var ErrNotFound = errors.New("not found")
+
+// Actions starts and stops servers for the API
+type Actions interface {
+	Start(ctx context.Context, name string) error
+	Stop(ctx context.Context, name string) error
+}
+
+// Server handles API requests
+type Server struct {
+	actions Actions
+	load    func(ctx context.Context) (*registry.Registry, error)
+	version string
+
+	// PollInterval is how often /v1/events checks the registry for changes
+	PollInterval time.Duration
+}
+
+// NewServer returns an API server reading the registry with load
+func NewServer(actions Actions, load func(ctx context.Context) (*registry.Registry, error), version string) *Server {
+	return &Server{
+		actions:      actions,
+		load:         load,
+		version:      version,
+		PollInterval: 500 * time.Millisecond,
+	}
+}
+
+// HealthResponse is the response of GET /v1/health
+type HealthResponse struct {
+	Status    string `json:"status"`
+	Version   string `json:"version"`
+	Timestamp string `json:"timestamp"`
+}
+
+// ServerList is the response of GET /v1/servers and the payload of
+// "servers" events
+type ServerList struct {
+	Servers []output.Server `json:"servers"`
+}
+
+// ErrorResponse is the body of failed requests
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Handler returns the API's routes
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/health", s.handleHealth)
+	mux.HandleFunc("GET /v1/servers", s.handleList)
+	mux.HandleFunc("GET /v1/servers/{name}", s.handleGet)
+	mux.HandleFunc("POST /v1/servers/{name}/start", s.handleAction(s.actions.Start))
+	mux.HandleFunc("POST /v1/servers/{name}/stop", s.handleAction(s.actions.Stop))
+	mux.HandleFunc("GET /v1/events", s.handleEvents)
+	return localOnly(mux)
+}
+
+// localOnly refuses requests that could come from a web page: ones sent
+// over TCP to a host name other than localhost, and ones with an Origin
+// other than the API's own
+func localOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		addr, _ := r.Context().Value(http.LocalAddrContextKey).(net.Addr)
+		if (addr == nil || addr.Network() != "unix") && !isLocalHost(r.Host) {
+			writeError(w, http.StatusForbidden, fmt.Errorf("host %s is not localhost", r.Host))
+			return
+		}
+		if origin := r.Header.Get("Origin"); origin != "" {
+			u, err := url.Parse(origin)
+			if err != nil || u.Host != r.Host {
+				writeError(w, http.StatusForbidden, fmt.Errorf("cross-origin request from %s", origin))
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isLocalHost reports whether a Host header names the loopback interface
+func isLocalHost(hostport string) bool {
+	host, _, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host = hostport
+	}
+	host = strings.Trim(host, "[]")
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, HealthResponse{
+		Status:    "ok",
+		Version:   s.version,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	list, err := s.list(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) handleGet(w http.ResponseWriter, r *http.Request) {
+	s.writeServer(w, r.Context(), r.PathValue("name"))
+}
+
+// handleAction runs a start or stop action and responds with the server's
+// state afterwards
+func (s *Server) handleAction(action func(ctx context.Context, name string) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+		if err := action(r.Context(), name); err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, ErrNotFound) {
+				status = http.StatusNotFound
+			}
+			writeError(w, status, err)
+			return
+		}
+		s.writeServer(w, r.Context(), name)
+	}
+}
+
+func (s *Server) writeServer(w http.ResponseWriter, ctx context.Context, name string) {
+	reg, err := s.load(ctx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	server, ok := reg.Get(name)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("%w: no server registered for '%s'", ErrNotFound, name))
+		return
+	}
+	writeJSON(w, http.StatusOK, output.NewServer(server))
+}
+
+// handleEvents streams a "servers" event with the current list, then
+// another each time it changes, until the client goes away
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("streaming not supported"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(s.PollInterval)
+	defer ticker.Stop()
+
+	var last []byte
+	for {
+		if list, err := s.list(r.Context()); err == nil {
+			data, _ := json.Marshal(list)
+			if string(data) != string(last) {
+				if _, err := fmt.Fprintf(w, "event: servers\ndata: %s\n\n", data); err != nil {
+					return
+				}
+				flusher.Flush()
+				last = data
+			}
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// list returns the registered servers sorted by name
+func (s *Server) list(ctx context.Context) (ServerList, error) {
+	reg, err := s.load(ctx)
+	if err != nil {
+		return ServerList{}, err
+	}
+	servers := reg.List()
+	sort.Slice(servers, func(i, j int) bool { return servers[i].Name < servers[j].Name })
+
+	list := ServerList{Servers: make([]output.Server, 0, len(servers))}
+	for _, server := range servers {
+		list.Servers = append(list.Servers, output.NewServer(server))
+	}
+	return list, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v) //nolint:errcheck
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, ErrorResponse{Error: err.Error()})
+}
@@ -0,0 +1,234 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+// fakeActions flips a server's status in the registry file
+type fakeActions struct {
+	path string
+}
+
+func (a fakeActions) Start(ctx context.Context, name string) error {
+	return a.set(name, registry.StatusRunning)
+}
+
+func (a fakeActions) Stop(ctx context.Context, name string) error {
+	return a.set(name, registry.StatusStopped)
+}
+
+func (a fakeActions) set(name string, status registry.ServerStatus) error {
+	reg, err := registry.LoadFrom(a.path)
+	if err != nil {
+		return err
+	}
+	server, ok := reg.Get(name)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	server.Status = status
+	return reg.Set(server)
+}
+
+func newTestServer(t *testing.T) (*httptest.Server, fakeActions) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "registry.json")
+	reg, err := registry.LoadFrom(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"web", "api"} {
+		if err := reg.Set(&registry.Server{Name: name, Port: 3000, Path: "/src/" + name, Status: registry.StatusStopped}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	actions := fakeActions{path: path}
+	load := func(context.Context) (*registry.Registry, error) { return registry.LoadFrom(path) }
+	s := NewServer(actions, load, "test")
+	s.PollInterval = 10 * time.Millisecond
+
+	ts := httptest.NewServer(s.Handler())
+	t.Cleanup(ts.Close)
+	return ts, actions
+}
+
+func TestList(t *testing.T) {
+	ts, _ := newTestServer(t)
+
+	resp, err := http.Get(ts.URL + "/v1/servers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var list ServerList
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Servers) != 2 || list.Servers[0].Name != "api" || list.Servers[1].Name != "web" {
+		t.Fatalf("servers = %+v, want api and web sorted by name", list.Servers)
+	}
+}
+
+func TestStartAndStop(t *testing.T) {
+	ts, _ := newTestServer(t)
+
+	resp, err := http.Post(ts.URL+"/v1/servers/web/start", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	var server struct{ Name, Status string }
+	if err := json.NewDecoder(resp.Body).Decode(&server); err != nil {
+		t.Fatal(err)
+	}
+	if server.Name != "web" || server.Status != string(registry.StatusRunning) {
+		t.Errorf("server = %+v, want web running", server)
+	}
+
+	resp, err = http.Post(ts.URL+"/v1/servers/missing/stop", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("stopping an unknown server: status = %d, want 404", resp.StatusCode)
+	}
+
+	resp, err = http.Get(ts.URL + "/v1/servers/web/start")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("GET of an action: status = %d, want 405", resp.StatusCode)
+	}
+}
+
+func TestLocalOnly(t *testing.T) {
+	ts, _ := newTestServer(t)
+
+	tests := []struct {
+		name   string
+		host   string
+		origin string
+		want   int
+	}{
+		{"loopback", "", "", http.StatusOK},
+		{"localhost", "localhost:3098", "", http.StatusOK},
+		{"same origin", "", ts.URL, http.StatusOK},
+		{"rebound host", "evil.example:3098", "", http.StatusForbidden},
+		{"foreign origin", "", "https://evil.example", http.StatusForbidden},
+		{"opaque origin", "", "null", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, ts.URL+"/v1/servers/web/start", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.host != "" {
+				req.Host = tt.host
+			}
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalOnly_UnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "registry.json")
+	load := func(context.Context) (*registry.Registry, error) { return registry.LoadFrom(path) }
+	listener, err := net.Listen("unix", filepath.Join(t.TempDir(), "api.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewUnstartedServer(NewServer(fakeActions{path: path}, load, "test").Handler())
+	ts.Listener = listener
+	ts.Start()
+	t.Cleanup(ts.Close)
+
+	// Socket clients may name any host, as curl --unix-socket does
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", listener.Addr().String())
+		},
+	}}
+	resp, err := client.Get("http://grove/v1/health")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestEventsOnChange(t *testing.T) {
+	ts, actions := newTestServer(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/v1/events", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	events := make(chan ServerList)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+			if !ok {
+				continue
+			}
+			var list ServerList
+			if err := json.Unmarshal([]byte(data), &list); err == nil {
+				events <- list
+			}
+		}
+		close(events)
+	}()
+
+	// The current list comes first, then one per change
+	if list := <-events; len(list.Servers) != 2 {
+		t.Fatalf("first event has %d servers, want 2", len(list.Servers))
+	}
+	if err := actions.Start(ctx, "api"); err != nil {
+		t.Fatal(err)
+	}
+	list, ok := <-events
+	if !ok {
+		t.Fatal("event stream ended before the change")
+	}
+	if list.Servers[0].Name != "api" || list.Servers[0].Status != string(registry.StatusRunning) {
+		t.Errorf("servers after start = %+v, want api running", list.Servers)
+	}
+}
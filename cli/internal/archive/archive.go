@@ -0,0 +1,197 @@
+// Package archive removes idle worktrees while keeping what's needed to
+// bring them back: the branch, the commit it pointed at and a stash of
+// uncommitted changes.
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/iheanyi/grove/internal/execx"
+	"github.com/iheanyi/grove/internal/git"
+)
+
+// Archive is an archived worktree
+type Archive struct {
+	Name     string   `json:"name"`
+	Path     string   `json:"path"`
+	Branch   string   `json:"branch"`
+	Commit   string   `json:"commit"`
+	MainRepo string   `json:"main_repo"`
+	Tags     []string `json:"tags,omitempty"`
+
+	// Stash is the stash commit holding uncommitted changes, kept alive by
+	// StashRef in the main repo
+	Stash      string    `json:"stash,omitempty"`
+	StashRef   string    `json:"stash_ref,omitempty"`
+	ArchivedAt time.Time `json:"archived_at"`
+}
+
+// New records the state of a worktree before it's removed. Uncommitted
+// changes, including untracked files, are stashed and the stash is moved to
+// refs/grove/archive/<name> so it survives stash drops and gc.
+func New(name, path, mainRepo string) (*Archive, error) {
+	branch, err := runGit(path, "symbolic-ref", "--short", "-q", "HEAD")
+	if err != nil || branch == "" {
+		return nil, fmt.Errorf("worktree '%s' is not on a branch", name)
+	}
+	commit, err := runGit(path, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	a := &Archive{
+		Name:       name,
+		Path:       path,
+		Branch:     branch,
+		Commit:     commit,
+		MainRepo:   mainRepo,
+		ArchivedAt: time.Now(),
+	}
+
+	status, err := runGit(path, "status", "--porcelain")
+	if err != nil {
+		return nil, err
+	}
+	if status == "" {
+		return a, nil
+	}
+
+	if _, err := runGit(path, "stash", "push", "--include-untracked", "-m", "grove archive "+name); err != nil {
+		return nil, err
+	}
+	if a.Stash, err = runGit(path, "rev-parse", "stash@{0}"); err != nil {
+		return nil, err
+	}
+	a.StashRef = "refs/grove/archive/" + name
+	if _, err := runGit(path, "update-ref", a.StashRef, a.Stash); err != nil {
+		return nil, err
+	}
+	if _, err := runGit(path, "stash", "drop", "-q", "stash@{0}"); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Restore recreates the worktree at its old path and reapplies the stashed
+// changes. If the branch was deleted meanwhile, it's recreated at the
+// archived commit.
+func (a *Archive) Restore() error {
+	if _, err := os.Stat(a.Path); err == nil {
+		return fmt.Errorf("%s already exists", a.Path)
+	}
+
+	opts := git.AddOptions{Commit: a.Branch}
+	if _, err := runGit(a.MainRepo, "rev-parse", "--verify", "-q", "refs/heads/"+a.Branch); err != nil {
+		opts = git.AddOptions{NewBranch: a.Branch, Commit: a.Commit}
+	}
+	if err := git.New(execx.OS).AddWorktree(context.Background(), a.MainRepo, a.Path, opts); err != nil {
+		return fmt.Errorf("git worktree add: %w", err)
+	}
+
+	if err := a.Unstash(); err != nil {
+		return fmt.Errorf("worktree restored, but %w", err)
+	}
+	return nil
+}
+
+// Unstash reapplies the stashed changes to the worktree and deletes the
+// stash's ref
+func (a *Archive) Unstash() error {
+	if a.Stash == "" {
+		return nil
+	}
+	if _, err := runGit(a.Path, "stash", "apply", a.Stash); err != nil {
+		return fmt.Errorf("failed to apply uncommitted changes (still saved as %s): %w", a.StashRef, err)
+	}
+	_, err := runGit(a.MainRepo, "update-ref", "-d", a.StashRef)
+	return err
+}
+
+// RestoreCommand returns the commands that restore the worktree by hand
+func (a *Archive) RestoreCommand() string {
+	cmd := fmt.Sprintf("git -C %s worktree add %s %s", a.MainRepo, a.Path, a.Branch)
+	if a.Stash != "" {
+		cmd += fmt.Sprintf(" && git -C %s stash apply %s", a.Path, a.Stash)
+	}
+	return cmd
+}
+
+func runGit(dir string, args ...string) (string, error) {
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(out)))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// Store records archived worktrees by name
+type Store struct {
+	path     string
+	Archives map[string]*Archive `json:"archives"`
+}
+
+// LoadStore reads the store at path; a missing file is an empty store
+func LoadStore(path string) (*Store, error) {
+	s := &Store{path: path, Archives: make(map[string]*Archive)}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+	if s.Archives == nil {
+		s.Archives = make(map[string]*Archive)
+	}
+	return s, nil
+}
+
+// Get returns an archived worktree
+func (s *Store) Get(name string) (*Archive, bool) {
+	a, ok := s.Archives[name]
+	return a, ok
+}
+
+// List returns the archived worktrees, most recently archived first
+func (s *Store) List() []*Archive {
+	archives := make([]*Archive, 0, len(s.Archives))
+	for _, a := range s.Archives {
+		archives = append(archives, a)
+	}
+	sort.Slice(archives, func(i, j int) bool { return archives[i].ArchivedAt.After(archives[j].ArchivedAt) })
+	return archives
+}
+
+// Set records an archived worktree
+func (s *Store) Set(a *Archive) error {
+	s.Archives[a.Name] = a
+	return s.save()
+}
+
+// Remove forgets an archived worktree
+func (s *Store) Remove(name string) error {
+	delete(s.Archives, name)
+	return s.save()
+}
+
+func (s *Store) save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0600)
+}
@@ -0,0 +1,93 @@
+package archive
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func run(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=grove", "GIT_AUTHOR_EMAIL=grove@example.com",
+		"GIT_COMMITTER_NAME=grove", "GIT_COMMITTER_EMAIL=grove@example.com")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func TestArchiveAndRestore(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	dir := t.TempDir()
+	repo := filepath.Join(dir, "repo")
+	wt := filepath.Join(dir, "feature")
+	if err := os.MkdirAll(repo, 0755); err != nil {
+		t.Fatal(err)
+	}
+	run(t, repo, "init", "-q")
+	run(t, repo, "commit", "-q", "--allow-empty", "-m", "initial")
+	run(t, repo, "worktree", "add", "-q", "-b", "feature", wt)
+
+	// Uncommitted changes, including an untracked file
+	if err := os.WriteFile(filepath.Join(wt, "notes.txt"), []byte("wip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := New("feature", wt, repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Branch != "feature" || a.Stash == "" {
+		t.Fatalf("archive = %+v", a)
+	}
+	run(t, repo, "worktree", "remove", wt)
+
+	// The stash is kept by its ref, not the stash list
+	run(t, repo, "rev-parse", "--verify", a.StashRef)
+	if out, _ := exec.Command("git", "-C", repo, "stash", "list").Output(); len(out) > 0 {
+		t.Errorf("stash list should be empty, got %s", out)
+	}
+
+	// Restore recreates a deleted branch from the archived commit
+	run(t, repo, "branch", "-D", "feature")
+	if err := a.Restore(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(wt, "notes.txt"))
+	if err != nil || string(data) != "wip" {
+		t.Errorf("notes.txt = %q, %v", data, err)
+	}
+	if err := exec.Command("git", "-C", repo, "rev-parse", "--verify", "-q", a.StashRef).Run(); err == nil {
+		t.Error("stash ref should be deleted after restoring")
+	}
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "archives.json")
+	s, err := LoadStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set(&Archive{Name: "feature", Branch: "feature"}); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = LoadStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a, ok := s.Get("feature"); !ok || a.Branch != "feature" {
+		t.Fatalf("Get = %v, %v", a, ok)
+	}
+	if err := s.Remove("feature"); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.List()) != 0 {
+		t.Error("expected no archives after Remove")
+	}
+}
@@ -0,0 +1,181 @@
+// Package bench is a small HTTP load generator: it sends requests to a URL
+// at a fixed rate and reports their latency percentiles and error rate.
+package bench
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Options configures a run
+type Options struct {
+	URL string
+	// Duration is how long requests are sent for
+	Duration time.Duration
+	// RPS is the requests started per second
+	RPS int
+	// Concurrency caps the requests in flight; when reached, the rate
+	// drops until one finishes
+	Concurrency int
+}
+
+// Result is the outcome of a run. Latencies are in milliseconds.
+type Result struct {
+	URL      string  `json:"url"`
+	Requests int     `json:"requests"`
+	Errors   int     `json:"errors"`
+	Duration float64 `json:"duration_seconds"`
+	// RPS is the requests completed per second
+	RPS   float64 `json:"rps"`
+	P50Ms float64 `json:"p50_ms"`
+	P90Ms float64 `json:"p90_ms"`
+	P99Ms float64 `json:"p99_ms"`
+	MaxMs float64 `json:"max_ms"`
+	// Statuses counts responses by status code; transport errors, e.g.
+	// refused connections and timeouts, count under "error"
+	Statuses map[string]int `json:"statuses"`
+}
+
+// ErrorRate returns the fraction of requests that failed
+func (r Result) ErrorRate() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+	return float64(r.Errors) / float64(r.Requests)
+}
+
+// sample is one request's outcome
+type sample struct {
+	latency time.Duration
+	status  int
+	err     error
+}
+
+// Run sends requests until the duration is up or ctx is canceled, and
+// waits for those in flight. Responses with a 5xx status count as errors,
+// like transport errors.
+func Run(ctx context.Context, client *http.Client, opts Options) (Result, error) {
+	if opts.RPS <= 0 {
+		return Result{}, fmt.Errorf("requests per second must be positive")
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = opts.RPS
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, opts.URL, nil)
+	if err != nil {
+		return Result{}, err
+	}
+
+	var (
+		mu      sync.Mutex
+		samples []sample
+		wg      sync.WaitGroup
+	)
+	slots := make(chan struct{}, opts.Concurrency)
+	send := func() {
+		defer wg.Done()
+		defer func() { <-slots }()
+		s := do(client, req.Clone(ctx))
+		mu.Lock()
+		samples = append(samples, s)
+		mu.Unlock()
+	}
+
+	start := time.Now()
+	ticker := time.NewTicker(time.Second / time.Duration(opts.RPS))
+	defer ticker.Stop()
+	deadline := time.NewTimer(opts.Duration)
+	defer deadline.Stop()
+
+	wg.Add(1)
+	slots <- struct{}{}
+	go send()
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break loop
+		case <-deadline.C:
+			break loop
+		case <-ticker.C:
+			select {
+			case slots <- struct{}{}:
+				wg.Add(1)
+				go send()
+			case <-ctx.Done():
+				break loop
+			}
+		}
+	}
+	wg.Wait()
+
+	result := summarize(samples, time.Since(start))
+	result.URL = opts.URL
+	return result, nil
+}
+
+// do sends one request, reading the whole response as a browser would
+func do(client *http.Client, req *http.Request) sample {
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return sample{latency: time.Since(start), err: err}
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return sample{latency: time.Since(start), status: resp.StatusCode}
+}
+
+// summarize computes a result from the samples of a run that took elapsed
+func summarize(samples []sample, elapsed time.Duration) Result {
+	result := Result{
+		Requests: len(samples),
+		Duration: elapsed.Seconds(),
+		Statuses: make(map[string]int),
+	}
+	if elapsed > 0 {
+		result.RPS = float64(len(samples)) / elapsed.Seconds()
+	}
+
+	latencies := make([]time.Duration, 0, len(samples))
+	for _, s := range samples {
+		latencies = append(latencies, s.latency)
+		if s.err != nil {
+			result.Errors++
+			result.Statuses["error"]++
+			continue
+		}
+		if s.status >= 500 {
+			result.Errors++
+		}
+		result.Statuses[fmt.Sprint(s.status)]++
+	}
+	if len(latencies) == 0 {
+		return result
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	result.P50Ms = ms(percentile(latencies, 50))
+	result.P90Ms = ms(percentile(latencies, 90))
+	result.P99Ms = ms(percentile(latencies, 99))
+	result.MaxMs = ms(latencies[len(latencies)-1])
+	return result
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,58 @@
+package bench
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSummarize(t *testing.T) {
+	var samples []sample
+	for i := 1; i <= 100; i++ {
+		samples = append(samples, sample{latency: time.Duration(i) * time.Millisecond, status: 200})
+	}
+	samples[0].status = 503
+	samples[1] = sample{latency: 2 * time.Millisecond, err: errors.New("connection refused")}
+
+	r := summarize(samples, 2*time.Second)
+	if r.Requests != 100 || r.Errors != 2 || r.ErrorRate() != 0.02 {
+		t.Errorf("requests = %d, errors = %d, want 100 and 2", r.Requests, r.Errors)
+	}
+	if r.P50Ms != 50 || r.P90Ms != 90 || r.P99Ms != 99 || r.MaxMs != 100 {
+		t.Errorf("percentiles = %v/%v/%v/%v, want 50/90/99/100", r.P50Ms, r.P90Ms, r.P99Ms, r.MaxMs)
+	}
+	if r.RPS != 50 {
+		t.Errorf("rps = %v, want 50", r.RPS)
+	}
+	if r.Statuses["200"] != 98 || r.Statuses["503"] != 1 || r.Statuses["error"] != 1 {
+		t.Errorf("statuses = %v", r.Statuses)
+	}
+}
+
+func TestRun(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	r, err := Run(context.Background(), srv.Client(), Options{URL: srv.URL, Duration: 300 * time.Millisecond, RPS: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Requests == 0 || int(hits.Load()) != r.Requests || r.Errors != 0 || r.Statuses["204"] != r.Requests {
+		t.Errorf("result = %+v, server saw %d requests", r, hits.Load())
+	}
+	if r.Requests > 10 {
+		t.Errorf("sent %d requests in 300ms at 20/s", r.Requests)
+	}
+
+	if _, err := Run(context.Background(), srv.Client(), Options{URL: srv.URL, Duration: time.Second}); err == nil {
+		t.Error("a zero rate should fail")
+	}
+}
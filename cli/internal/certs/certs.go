@@ -0,0 +1,152 @@
+// Package certs manages locally-trusted TLS certificates for the proxy,
+// generated with mkcert so custom TLDs work without browser warnings.
+package certs
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/iheanyi/grove/internal/config"
+)
+
+// Cert is a certificate/key pair generated for a TLD
+type Cert struct {
+	// CertFile is the path to the PEM-encoded certificate
+	CertFile string
+
+	// KeyFile is the path to the PEM-encoded private key
+	KeyFile string
+
+	// DNSNames are the hostnames (and wildcards) the certificate covers
+	DNSNames []string
+
+	// NotAfter is when the certificate expires
+	NotAfter time.Time
+}
+
+// Dir returns the directory where grove stores generated certificates
+func Dir() string {
+	return filepath.Join(config.ConfigDir(), "certs")
+}
+
+// Paths returns the certificate and key paths for a TLD
+func Paths(tld string) (certFile, keyFile string) {
+	base := filepath.Join(Dir(), tld)
+	return base + ".pem", base + "-key.pem"
+}
+
+// Load reads the certificate generated for a TLD. It returns an error
+// satisfying os.IsNotExist if no certificate has been generated.
+func Load(tld string) (*Cert, error) {
+	certFile, keyFile := Paths(tld)
+	if _, err := os.Stat(keyFile); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data in %s", certFile)
+	}
+
+	parsed, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", certFile, err)
+	}
+
+	return &Cert{
+		CertFile: certFile,
+		KeyFile:  keyFile,
+		DNSNames: parsed.DNSNames,
+		NotAfter: parsed.NotAfter,
+	}, nil
+}
+
+// Expired returns true if the certificate is past its expiry date
+func (c *Cert) Expired() bool {
+	return time.Now().After(c.NotAfter)
+}
+
+// Covers returns true if the certificate is valid for host. The host may
+// itself be a wildcard (e.g., *.feature.test), which is only covered by the
+// identical wildcard entry.
+func (c *Cert) Covers(host string) bool {
+	host = strings.ToLower(host)
+	for _, name := range c.DNSNames {
+		name = strings.ToLower(name)
+		if name == host {
+			return true
+		}
+		if strings.HasPrefix(name, "*.") && !strings.HasPrefix(host, "*.") {
+			// A wildcard matches exactly one label
+			if i := strings.IndexByte(host, '.'); i > 0 && host[i:] == name[1:] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Names returns the hostnames to include in a certificate for the TLD
+// and the given server names: each server's domain and its subdomains.
+func Names(tld string, serverNames []string) []string {
+	names := []string{"*." + tld}
+	for _, name := range serverNames {
+		names = append(names, fmt.Sprintf("%s.%s", name, tld), fmt.Sprintf("*.%s.%s", name, tld))
+	}
+	return names
+}
+
+// MkcertAvailable returns true if mkcert is installed
+func MkcertAvailable() bool {
+	_, err := exec.LookPath("mkcert")
+	return err == nil
+}
+
+// InstallCA installs mkcert's local CA into the system trust stores
+func InstallCA() error {
+	cmd := exec.Command("mkcert", "-install")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("mkcert -install failed: %w\nOutput: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// Generate creates (or replaces) the certificate for a TLD covering names
+func Generate(tld string, names []string) (*Cert, error) {
+	if err := os.MkdirAll(Dir(), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create certs directory: %w", err)
+	}
+
+	certFile, keyFile := Paths(tld)
+	args := append([]string{"-cert-file", certFile, "-key-file", keyFile}, names...)
+	cmd := exec.Command("mkcert", args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("mkcert failed: %w\nOutput: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	return Load(tld)
+}
+
+// Remove deletes the certificate generated for a TLD
+func Remove(tld string) error {
+	certFile, keyFile := Paths(tld)
+	for _, path := range []string{certFile, keyFile} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package certs
+
+import "testing"
+
+func TestCovers(t *testing.T) {
+	c := &Cert{DNSNames: []string{"*.test", "feature.test", "*.feature.test"}}
+
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"feature.test", true},
+		{"other.test", true},
+		{"api.feature.test", true},
+		{"*.feature.test", true},
+		{"api.other.test", false},
+		{"*.other.test", false},
+		{"deep.api.feature.test", false},
+		{"feature.localhost", false},
+	}
+
+	for _, tt := range tests {
+		if got := c.Covers(tt.host); got != tt.want {
+			t.Errorf("Covers(%q) = %v, want %v", tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestNames(t *testing.T) {
+	got := Names("test", []string{"main", "feature"})
+	want := []string{"*.test", "main.test", "*.main.test", "feature.test", "*.feature.test"}
+
+	if len(got) != len(want) {
+		t.Fatalf("Names() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Names()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
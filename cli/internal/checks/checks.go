@@ -0,0 +1,140 @@
+// Package checks runs a project's check commands (lint, test, typecheck
+// and so on from .grove.yaml) in a worktree and records whether they pass,
+// so branches can be known green before they're reviewed.
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Result is the outcome of one check
+type Result struct {
+	Name       string `json:"name"`
+	Command    string `json:"command"`
+	Passed     bool   `json:"passed"`
+	ExitCode   int    `json:"exit_code"`
+	DurationMs int64  `json:"duration_ms"`
+	// Error says why a check that didn't exit with a status failed, e.g.
+	// a timeout
+	Error string `json:"error,omitempty"`
+}
+
+// Record is a run of a worktree's checks
+type Record struct {
+	// Head is the commit the checks ran against
+	Head string `json:"head,omitempty"`
+	// Dirty is set when the worktree had uncommitted changes, which the
+	// checks ran against too
+	Dirty   bool      `json:"dirty,omitempty"`
+	RanAt   time.Time `json:"ran_at"`
+	Results []Result  `json:"results"`
+}
+
+// Passed reports whether every check passed
+func (r *Record) Passed() bool {
+	for _, result := range r.Results {
+		if !result.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// Failed returns the names of the checks that failed
+func (r *Record) Failed() []string {
+	var names []string
+	for _, result := range r.Results {
+		if !result.Passed {
+			names = append(names, result.Name)
+		}
+	}
+	return names
+}
+
+// Duration returns how long the checks took together
+func (r *Record) Duration() time.Duration {
+	var ms int64
+	for _, result := range r.Results {
+		ms += result.DurationMs
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// Current reports whether the checks ran against head, so new commits
+// haven't made their results out of date
+func (r *Record) Current(head string) bool {
+	return r.Head != "" && r.Head == head
+}
+
+// Summary returns a short status, e.g. "✓ 3/3" or "✗ 1/3 (lint, test)"
+func (r *Record) Summary() string {
+	failed := r.Failed()
+	passed := len(r.Results) - len(failed)
+	if len(failed) == 0 {
+		return fmt.Sprintf("✓ %d/%d", passed, len(r.Results))
+	}
+	return fmt.Sprintf("✗ %d/%d (%s)", passed, len(r.Results), strings.Join(failed, ", "))
+}
+
+// Names returns the names of the configured checks in the order they run:
+// sorted by name
+func Names(checks map[string]string) []string {
+	names := make([]string, 0, len(checks))
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Run runs a check's command with sh in dir, writing its output to out.
+// A timeout of zero waits for it however long it takes.
+func Run(ctx context.Context, dir, name, command string, timeout time.Duration, out io.Writer) Result {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = dir
+	cmd.Stdout = out
+	cmd.Stderr = out
+	// Kill the whole group on timeout, so the command's children don't
+	// keep it running (and its output open)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+
+	start := time.Now()
+	err := cmd.Run()
+	result := Result{
+		Name:       name,
+		Command:    command,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		result.Passed = true
+	case ctx.Err() == context.DeadlineExceeded:
+		result.ExitCode = -1
+		result.Error = fmt.Sprintf("timed out after %s", timeout)
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	default:
+		result.ExitCode = -1
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,72 @@
+package checks
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	var out bytes.Buffer
+
+	r := Run(context.Background(), dir, "lint", "echo ok; pwd", 0, &out)
+	if !r.Passed || r.ExitCode != 0 || r.Name != "lint" {
+		t.Errorf("Run(passing) = %+v", r)
+	}
+	if !strings.Contains(out.String(), "ok") {
+		t.Errorf("output = %q, want the command's output", out.String())
+	}
+
+	r = Run(context.Background(), dir, "test", "echo boom >&2; exit 3", 0, &out)
+	if r.Passed || r.ExitCode != 3 || r.Error != "" {
+		t.Errorf("Run(failing) = %+v, want exit code 3", r)
+	}
+	if !strings.Contains(out.String(), "boom") {
+		t.Errorf("output = %q, want stderr too", out.String())
+	}
+
+	r = Run(context.Background(), dir, "slow", "sleep 5", 50*time.Millisecond, &out)
+	if r.Passed || !strings.Contains(r.Error, "timed out") {
+		t.Errorf("Run(slow) = %+v, want a timeout", r)
+	}
+}
+
+func TestRecord(t *testing.T) {
+	rec := &Record{Head: "abc", Results: []Result{
+		{Name: "lint", Passed: true, DurationMs: 1500},
+		{Name: "test", DurationMs: 500},
+		{Name: "typecheck", DurationMs: 1000},
+	}}
+	if rec.Passed() {
+		t.Error("Passed() = true with failures")
+	}
+	if got := rec.Summary(); got != "✗ 1/3 (test, typecheck)" {
+		t.Errorf("Summary() = %q", got)
+	}
+	if got := rec.Duration(); got != 3*time.Second {
+		t.Errorf("Duration() = %s, want 3s", got)
+	}
+
+	rec.Results = rec.Results[:1]
+	if !rec.Passed() || rec.Summary() != "✓ 1/1" {
+		t.Errorf("Passed() = %v, Summary() = %q", rec.Passed(), rec.Summary())
+	}
+
+	if !rec.Current("abc") {
+		t.Error("Current() = false for the same commit")
+	}
+	if rec.Current("def") {
+		t.Error("Current() = true after a new commit")
+	}
+}
+
+func TestNames(t *testing.T) {
+	got := Names(map[string]string{"typecheck": "tsc", "lint": "eslint .", "test": "npm test"})
+	if want := []string{"lint", "test", "typecheck"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+}
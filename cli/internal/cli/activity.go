@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/events"
+	"github.com/iheanyi/grove/internal/notify"
+	"github.com/iheanyi/grove/internal/output"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/spf13/cobra"
+)
+
+var activityCmd = &cobra.Command{
+	Use:   "activity [name]",
+	Short: "Show the timeline of server and agent events",
+	Long: `Show the activity timeline: servers starting, stopping, crashing and
+changing health, and agent sessions going over agent_limits.
+
+Every grove command that starts, stops or watches something logs its events
+to ~/.config/grove/activity.jsonl, or to registry.db with
+registry_backend: sqlite.
+
+Examples:
+  grove activity                         # Latest 50 events
+  grove activity feature-auth            # Events of one worktree
+  grove activity --type crash --since 7d # Servers that crashed this week
+  grove activity -n 200
+  grove activity --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runActivity,
+}
+
+func init() {
+	activityCmd.Flags().IntP("limit", "n", 50, "Events to show (0 for all)")
+	activityCmd.Flags().String("type", "", "Only events of a type: start, stop, crash, idle_stop, health, agent_limit")
+	activityCmd.Flags().String("since", "", "Only events since a time, or a duration before now (e.g. 2h, 7d)")
+	addOutputFlags(activityCmd)
+}
+
+func runActivity(cmd *cobra.Command, args []string) error {
+	limit, _ := cmd.Flags().GetInt("limit")
+	eventType, _ := cmd.Flags().GetString("type")
+	since, _ := cmd.Flags().GetString("since")
+
+	q := events.Query{Type: events.Type(eventType), Limit: limit}
+	if len(args) > 0 {
+		q.Server = args[0]
+	}
+	if since != "" {
+		t, err := parseSince(since, time.Now())
+		if err != nil {
+			return err
+		}
+		q.Since = t
+	}
+
+	var timeline []events.Event
+	var err error
+	if registry.Backend() == registry.BackendSQLite {
+		timeline, err = registry.QueryEvents(cmd.Context(), q)
+	} else {
+		timeline, err = events.ReadLog(config.ActivityLogPath(), q)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read activity: %w", err)
+	}
+	if timeline == nil {
+		timeline = []events.Event{}
+	}
+
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	if format.IsMachine() {
+		return output.Write(os.Stdout, format, output.ActivityLog{Events: timeline})
+	}
+
+	if len(timeline) == 0 {
+		fmt.Println("No activity recorded")
+		return nil
+	}
+	for _, e := range timeline {
+		fmt.Printf("%s  %-12s %s\n", e.Time.Local().Format("2006-01-02 15:04:05"), e.Type, notify.Summary(e))
+	}
+	return nil
+}
@@ -1,13 +1,15 @@
 package cli
 
 import (
+	"context"
 	"fmt"
-	"os/exec"
 	"regexp"
 	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/x/ansi"
+	"github.com/iheanyi/grove/internal/execx"
+	"github.com/iheanyi/grove/internal/output"
 	"github.com/iheanyi/grove/internal/registry"
 	"github.com/iheanyi/grove/internal/styles"
 	"github.com/spf13/cobra"
@@ -32,6 +34,7 @@ Examples:
 func init() {
 	adoptCmd.Flags().Bool("dry-run", false, "Show what would be adopted without making changes")
 	adoptCmd.Flags().Bool("all", false, "Show all detected servers, including unmatched ones")
+	addOutputFlags(adoptCmd)
 	adoptCmd.GroupID = "server"
 	rootCmd.AddCommand(adoptCmd)
 }
@@ -113,21 +116,39 @@ func runAdopt(cmd *cobra.Command, args []string) error {
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
 	showAll, _ := cmd.Flags().GetBool("all")
 
+	return runWithOutput(cmd, func() (any, error) {
+		result, err := adoptServers(dryRun, showAll)
+		if result == nil {
+			return nil, err
+		}
+		return result, err
+	})
+}
+
+// adoptServers matches running dev servers to registered worktrees and,
+// unless dryRun, records them in the registry
+func adoptServers(dryRun, showAll bool) (*output.AdoptResult, error) {
+	result := &output.AdoptResult{
+		Matched:   []output.AdoptedServer{},
+		Unmatched: []output.DetectedServer{},
+		DryRun:    dryRun,
+	}
+
 	// Load registry
 	reg, err := registry.Load()
 	if err != nil {
-		return fmt.Errorf("failed to load registry: %w", err)
+		return nil, fmt.Errorf("failed to load registry: %w", err)
 	}
 
 	// Detect running servers
 	servers, err := detectRunningServers()
 	if err != nil {
-		return fmt.Errorf("failed to detect servers: %w", err)
+		return nil, fmt.Errorf("failed to detect servers: %w", err)
 	}
 
 	if len(servers) == 0 {
 		fmt.Println("No running dev servers detected.")
-		return nil
+		return result, nil
 	}
 
 	// Match servers to worktrees
@@ -180,6 +201,31 @@ func runAdopt(cmd *cobra.Command, args []string) error {
 		matched = append(matched, m)
 	}
 
+	for _, m := range matched {
+		state := "new"
+		if m.isRunning && m.oldPort == m.server.Port {
+			state = "already_adopted"
+		} else if m.oldPort > 0 {
+			state = "port_change"
+		}
+		result.Matched = append(result.Matched, output.AdoptedServer{
+			Worktree: m.worktree,
+			Port:     m.server.Port,
+			OldPort:  m.oldPort,
+			PID:      m.server.PID,
+			Type:     m.server.Type,
+			State:    state,
+		})
+	}
+	for _, u := range unmatched {
+		result.Unmatched = append(result.Unmatched, output.DetectedServer{
+			Port:    u.server.Port,
+			PID:     u.server.PID,
+			Type:    u.server.Type,
+			WorkDir: u.server.WorkDir,
+		})
+	}
+
 	// Display results
 	if len(matched) > 0 {
 		fmt.Printf("Found %d running dev servers matching registered worktrees:\n\n", len(matched))
@@ -231,19 +277,19 @@ func runAdopt(cmd *cobra.Command, args []string) error {
 		if !showAll && len(unmatched) > 0 {
 			fmt.Printf("\n%d servers found but not matched. Use --all to see them.\n", len(unmatched))
 		}
-		return nil
+		return result, nil
 	}
 
 	if dryRun {
 		fmt.Println("\n--dry-run specified, no changes made.")
-		return nil
+		return result, nil
 	}
 
 	// Adopt the servers
 	fmt.Println("\nAdopting servers...")
 	adopted := 0
 
-	for _, m := range matched {
+	for i, m := range matched {
 		// Skip if already adopted with same port
 		if m.isRunning && m.oldPort == m.server.Port {
 			continue
@@ -269,23 +315,25 @@ func runAdopt(cmd *cobra.Command, args []string) error {
 
 		if err := reg.Set(server); err != nil {
 			fmt.Printf("  ✗ %s: %v\n", m.worktree, err)
+			result.Matched[i].Error = err.Error()
 			continue
 		}
 
 		fmt.Printf("  ✓ %s (port %d)\n", m.worktree, m.server.Port)
+		result.Matched[i].Adopted = true
 		adopted++
 	}
 
 	fmt.Printf("\nAdopted %d servers.\n", adopted)
-	return nil
+	result.Adopted = adopted
+	return result, nil
 }
 
 // detectRunningServers finds processes that look like dev servers
 func detectRunningServers() ([]detectedServer, error) {
 	// Use lsof to find listening TCP connections on dev ports (3000-49151)
 	// We exclude ephemeral ports (49152-65535) which are typically background tools
-	cmd := exec.Command("lsof", "-iTCP", "-sTCP:LISTEN", "-P", "-n")
-	output, err := cmd.Output()
+	output, err := runner.Output(context.Background(), execx.Query("lsof", "-iTCP", "-sTCP:LISTEN", "-P", "-n"))
 	if err != nil {
 		return nil, fmt.Errorf("failed to run lsof: %w", err)
 	}
@@ -398,8 +446,7 @@ func extractPort(nameField string) int {
 
 // getProcessCommand gets the full command line for a process
 func getProcessCommand(pid int) string {
-	cmd := exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "command=")
-	output, err := cmd.Output()
+	output, err := runner.Output(context.Background(), execx.Query("ps", "-p", strconv.Itoa(pid), "-o", "command="))
 	if err != nil {
 		return ""
 	}
@@ -408,8 +455,7 @@ func getProcessCommand(pid int) string {
 
 // getProcessWorkDir gets the working directory for a process
 func getProcessWorkDir(pid int) string {
-	cmd := exec.Command("lsof", "-p", strconv.Itoa(pid))
-	output, err := cmd.Output()
+	output, err := runner.Output(context.Background(), execx.Query("lsof", "-p", strconv.Itoa(pid)))
 	if err != nil {
 		return ""
 	}
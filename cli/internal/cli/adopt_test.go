@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/iheanyi/grove/internal/execx"
+)
+
+// useFakeRunner swaps the package's runner for a fake for the test
+func useFakeRunner(t *testing.T) *execx.Fake {
+	t.Helper()
+	fake := execx.NewFake()
+	orig := runner
+	runner = fake
+	t.Cleanup(func() { runner = orig })
+	return fake
+}
+
+func readFixture(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "adopt", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestDetectRunningServers(t *testing.T) {
+	fake := useFakeRunner(t)
+	fake.On("lsof -iTCP -sTCP:LISTEN -P -n", readFixture(t, "lsof-listen.txt"), nil)
+	for pid, command := range map[string]string{
+		"3101": "puma 6.4.2 (tcp://127.0.0.1:3179) [app-feature-auth]",
+		"4202": "node /Users/iheanyi/src/web/node_modules/.bin/vite",
+		"4310": "node /Users/iheanyi/src/web/node_modules/vite/node_modules/esbuild/bin/esbuild --service=0.21.5 --ping --watch",
+		"5001": "python3 manage.py runserver 8000",
+	} {
+		fake.On("ps -p "+pid+" -o command=", command+"\n", nil)
+		fake.On("lsof -p "+pid, readFixture(t, "lsof-p-"+pid+".txt"), nil)
+	}
+
+	servers, err := detectRunningServers()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// postgres isn't a dev process, 4310 is an excluded bundler, 4400 is on
+	// an ephemeral port, 5001 runs from / and 3101's IPv6 socket is a duplicate
+	want := []detectedServer{
+		{PID: 3101, Port: 3179, Command: "puma 6.4.2 (tcp://127.0.0.1:3179) [app-feature-auth]", WorkDir: "/Users/iheanyi/src/app-feature-auth", Type: "rails"},
+		{PID: 4202, Port: 5173, Command: "node /Users/iheanyi/src/web/node_modules/.bin/vite", WorkDir: "/Users/iheanyi/src/web", Type: "node"},
+	}
+	if !reflect.DeepEqual(servers, want) {
+		t.Errorf("detectRunningServers() =\n%+v\nwant\n%+v", servers, want)
+	}
+}
+
+func TestDetectRunningServers_LsofFails(t *testing.T) {
+	fake := useFakeRunner(t)
+	fake.On("lsof -iTCP -sTCP:LISTEN -P -n", "", errors.New("exit status 1"))
+
+	if _, err := detectRunningServers(); err == nil {
+		t.Error("detectRunningServers() = nil error, want lsof's")
+	}
+}
@@ -0,0 +1,332 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/discovery"
+	"github.com/iheanyi/grove/internal/project"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/tasks"
+	"github.com/iheanyi/grove/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var agentCmd = &cobra.Command{
+	Use:   "agent",
+	Short: "Launch AI agents in worktrees",
+}
+
+var agentLaunchCmd = &cobra.Command{
+	Use:   "launch [name]",
+	Short: "Start an AI agent in a worktree with grove context",
+	Long: `Start an AI agent in a worktree, in the foreground, with its grove context.
+
+The agent gets GROVE_WORKTREE, GROVE_URL and PORT (when the worktree has a
+server), and GROVE_TASK and GROVE_TASK_TITLE for its task: --task, the task
+it was created for with 'grove new --task', or the one in progress.
+
+claude and gemini also get a first prompt describing the worktree. Set
+agent_prompt in .grove.yaml to write your own; it may use {name}, {branch},
+{path}, {url}, {port}, {task} and {task_title}:
+
+  agent_prompt: |
+    Work on {task}: {task_title}. The app runs at {url}; check your
+    changes there before you finish.
+
+The session is recorded, so 'grove agents', the TUI and the dashboard show
+it as soon as it starts, including agents they don't detect on their own
+(like aider). Run from a terminal, the agent's output is also captured with
+script(1), and the dashboard's Agents page can show it, read-only.
+
+Examples:
+  grove agent launch                       # Configured agent (tmux.agent) here
+  grove agent launch feature-auth --agent gemini
+  grove agent launch --task TK-42          # Work on a specific task
+  grove agent launch --agent aider --no-prompt`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runAgentLaunch,
+}
+
+func init() {
+	agentLaunchCmd.Flags().String("agent", "", "Agent to run: claude, gemini, aider or a command (default: tmux.agent)")
+	agentLaunchCmd.Flags().String("task", "", "Task ID to work on")
+	agentLaunchCmd.Flags().String("prompt", "", "First prompt (default: agent_prompt from .grove.yaml, or a summary of the worktree)")
+	agentLaunchCmd.Flags().Bool("no-prompt", false, "Start the agent without a first prompt")
+	agentCmd.AddCommand(agentLaunchCmd)
+	agentCmd.GroupID = "monitoring"
+	rootCmd.AddCommand(agentCmd)
+}
+
+// agentPromptArgs turn a prompt into the arguments an agent starts an
+// interactive session with, by agent executable. Other agents get the
+// prompt in GROVE_AGENT_PROMPT only.
+var agentPromptArgs = map[string]func(prompt string) []string{
+	"claude": func(prompt string) []string { return []string{prompt} },
+	"gemini": func(prompt string) []string { return []string{"--prompt-interactive", prompt} },
+}
+
+// agentLaunch is a worktree an agent is launched in, and its grove context
+type agentLaunch struct {
+	Name     string
+	Path     string
+	Branch   string
+	MainRepo string
+	URL      string
+	Port     int
+	Task     *tasks.Task
+}
+
+func runAgentLaunch(cmd *cobra.Command, args []string) error {
+	agent, _ := cmd.Flags().GetString("agent")
+	taskID, _ := cmd.Flags().GetString("task")
+	prompt, _ := cmd.Flags().GetString("prompt")
+	noPrompt, _ := cmd.Flags().GetBool("no-prompt")
+
+	var name string
+	if len(args) > 0 {
+		name = args[0]
+	} else {
+		wt, err := worktree.Detect()
+		if err != nil {
+			return fmt.Errorf("failed to detect worktree: %w", err)
+		}
+		name = wt.Name
+	}
+
+	launch, err := newAgentLaunch(name)
+	if err != nil {
+		return err
+	}
+	if taskID != "" {
+		launch.Task = launch.findTask(taskID)
+	}
+
+	if agent == "" {
+		agent = cfg.Tmux.Agent
+	}
+	if noPrompt {
+		prompt = ""
+	} else if prompt == "" {
+		prompt = launch.Prompt()
+	}
+	return launch.Run(agent, prompt)
+}
+
+// newAgentLaunch looks up a registered worktree for an agent launch. Its
+// task is the one it was created for, or the one in progress.
+func newAgentLaunch(name string) (*agentLaunch, error) {
+	reg, err := registry.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load registry: %w", err)
+	}
+	ws, ok := reg.GetWorkspace(name)
+	if !ok {
+		return nil, fmt.Errorf("worktree '%s' not found", name)
+	}
+
+	launch := &agentLaunch{Name: ws.Name, Path: ws.Path, Branch: ws.Branch, MainRepo: ws.MainRepo}
+	if ws.Server != nil {
+		launch.URL = ws.Server.URL
+		launch.Port = ws.Server.Port
+	}
+	if ws.Task != "" {
+		launch.Task = launch.findTask(ws.Task)
+	} else {
+		launch.Task = tasks.Active(ws.Path)
+	}
+	return launch, nil
+}
+
+// findTask looks a task up in the worktree, then its main repository, where
+// 'grove new --task' found it. An unknown task is kept by its ID.
+func (l *agentLaunch) findTask(id string) *tasks.Task {
+	paths := []string{l.Path}
+	if l.MainRepo != "" && l.MainRepo != l.Path {
+		paths = append(paths, l.MainRepo)
+	}
+	if task, _, err := findTask(paths, id); err == nil {
+		return task
+	}
+	return &tasks.Task{ID: id}
+}
+
+// vars returns the values of the placeholders agent_prompt may use
+func (l *agentLaunch) vars() map[string]string {
+	vars := map[string]string{
+		"name":   l.Name,
+		"branch": l.Branch,
+		"path":   l.Path,
+		"url":    l.URL,
+		"port":   "",
+	}
+	if l.Port > 0 {
+		vars["port"] = strconv.Itoa(l.Port)
+	}
+	if l.Task != nil {
+		vars["task"] = l.Task.ID
+		vars["task_title"] = l.Task.Title
+	}
+	return vars
+}
+
+// Prompt returns the first prompt for the agent: agent_prompt from
+// .grove.yaml, or a summary of the worktree
+func (l *agentLaunch) Prompt() string {
+	if projConfig, err := project.Load(l.Path); err == nil && projConfig.AgentPrompt != "" {
+		return strings.TrimSpace(expandTmuxCommand(projConfig.AgentPrompt, l.vars()))
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "You're working in the grove worktree %s", l.Name)
+	if l.Branch != "" {
+		fmt.Fprintf(&sb, " on branch %s", l.Branch)
+	}
+	sb.WriteString(".")
+	if l.URL != "" {
+		fmt.Fprintf(&sb, " Its dev server runs at %s; 'grove logs %s' shows its output.", l.URL, l.Name)
+	}
+	if l.Task != nil {
+		fmt.Fprintf(&sb, " Your task is %s.", l.Task)
+	}
+	return sb.String()
+}
+
+// Env returns the variables the agent gets on top of grove's environment
+func (l *agentLaunch) Env(prompt string) []string {
+	env := []string{"GROVE_WORKTREE=" + l.Name}
+	if l.URL != "" {
+		env = append(env, "GROVE_URL="+l.URL)
+	}
+	if l.Port > 0 {
+		env = append(env, fmt.Sprintf("PORT=%d", l.Port))
+	}
+	if l.Task != nil {
+		env = append(env, "GROVE_TASK="+l.Task.ID, "GROVE_TASK_TITLE="+l.Task.Title)
+	}
+	if prompt != "" {
+		env = append(env, "GROVE_AGENT_PROMPT="+prompt)
+	}
+	return env
+}
+
+// Run runs an agent command in the worktree in the foreground, recording
+// the session while it runs. From a terminal, the agent runs under
+// script(1) so the dashboard can show its output.
+func (l *agentLaunch) Run(agent, prompt string) error {
+	if agent == "" {
+		return fmt.Errorf("no agent configured (use --agent, or set tmux.agent in ~/.config/grove/config.yaml)")
+	}
+
+	kind := agentType(agent)
+	var promptArgs []string
+	if toArgs, ok := agentPromptArgs[kind]; ok && prompt != "" {
+		promptArgs = toArgs(prompt)
+	}
+
+	var execCmd *exec.Cmd
+	var transcript, pidFile string
+	if _, err := exec.LookPath("script"); err == nil && isInteractive() {
+		transcript = filepath.Join(config.AgentTerminalsDir(), fmt.Sprintf("%s-%d.log", worktree.Sanitize(l.Name), time.Now().UnixNano()))
+		pidFile = transcript + ".pid"
+		if err := os.MkdirAll(filepath.Dir(transcript), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(transcript), err)
+		}
+		defer os.Remove(transcript)
+		defer os.Remove(pidFile)
+		execCmd = agentTerminalCommand(agent, promptArgs, transcript, pidFile)
+	} else {
+		// The agent command may be a shell snippet; the prompt is passed as
+		// arguments so it needs no quoting
+		execCmd = exec.Command("sh", append([]string{"-c", agent + ` "$@"`, "sh"}, promptArgs...)...)
+	}
+	execCmd.Dir = l.Path
+	execCmd.Env = append(os.Environ(), l.Env(prompt)...)
+	execCmd.Stdin = os.Stdin
+	execCmd.Stdout = os.Stdout
+	execCmd.Stderr = os.Stderr
+
+	// Ctrl+C is for the agent
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt)
+	defer signal.Stop(sigChan)
+
+	fmt.Printf("Launching %s in %s...\n", agent, l.Name)
+	if err := execCmd.Start(); err != nil {
+		return fmt.Errorf("failed to start %s: %w", agent, err)
+	}
+
+	session := &discovery.AgentInfo{
+		Type:      kind,
+		PID:       execCmd.Process.Pid,
+		Path:      l.Path,
+		StartTime: time.Now(),
+		Command:   agent,
+		OutputLog: transcript,
+	}
+	if pidFile != "" {
+		// The shell script(1) starts leads the agent's process group, so
+		// it's what signals like agent_limits' interrupt are sent to
+		if pid := waitForPIDFile(pidFile, 2*time.Second); pid > 0 {
+			session.PID = pid
+		}
+	}
+	if l.Task != nil {
+		session.ActiveTask = l.Task.ID
+		session.TaskSummary = l.Task.Summary()
+	}
+	if err := discovery.RecordLaunchedAgent(session); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record agent session: %v\n", err)
+	}
+
+	return execCmd.Wait()
+}
+
+// agentTerminalCommand runs an agent command in a pseudo-terminal with
+// script(1), copying its output to transcript as it's written. The shell
+// running the agent writes its PID to pidFile.
+func agentTerminalCommand(agent string, args []string, transcript, pidFile string) *exec.Cmd {
+	shellCmd := fmt.Sprintf("echo $$ > %s; %s", shellQuoteArgs([]string{pidFile}), agent)
+	if len(args) > 0 {
+		shellCmd += " " + shellQuoteArgs(args)
+	}
+
+	if runtime.GOOS == "darwin" {
+		return exec.Command("script", "-q", "-F", transcript, "sh", "-c", shellCmd)
+	}
+	// util-linux script runs the command with $SHELL, which may not be sh
+	return exec.Command("script", "-q", "-f", "-e", "-c", "exec sh -c "+shellQuoteArgs([]string{shellCmd}), transcript)
+}
+
+// waitForPIDFile returns the PID written to a file, once it has been, or 0
+// after timeout
+func waitForPIDFile(path string, timeout time.Duration) int {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if data, err := os.ReadFile(path); err == nil {
+			if pid, err := strconv.Atoi(strings.TrimSpace(string(data))); err == nil {
+				return pid
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	return 0
+}
+
+// agentType returns the name of an agent command's executable, e.g.
+// "claude" for "claude --continue"
+func agentType(agent string) string {
+	fields := strings.Fields(agent)
+	if len(fields) == 0 {
+		return ""
+	}
+	return filepath.Base(fields[0])
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iheanyi/grove/internal/tasks"
+)
+
+func TestAgentType(t *testing.T) {
+	tests := map[string]string{
+		"claude":                      "claude",
+		"claude --continue":           "claude",
+		"/opt/homebrew/bin/gemini -y": "gemini",
+		"":                            "",
+	}
+	for agent, want := range tests {
+		if got := agentType(agent); got != want {
+			t.Errorf("agentType(%q) = %q, want %q", agent, got, want)
+		}
+	}
+}
+
+func TestAgentLaunchPrompt(t *testing.T) {
+	dir := t.TempDir()
+	launch := &agentLaunch{
+		Name:   "feature-auth",
+		Path:   dir,
+		Branch: "feature/auth",
+		URL:    "https://feature-auth.localhost",
+		Port:   3100,
+		Task:   &tasks.Task{ID: "TK-42", Title: "Add OAuth login"},
+	}
+
+	want := "You're working in the grove worktree feature-auth on branch feature/auth. " +
+		"Its dev server runs at https://feature-auth.localhost; 'grove logs feature-auth' shows its output. " +
+		"Your task is TK-42 - Add OAuth login."
+	if got := launch.Prompt(); got != want {
+		t.Errorf("Prompt() = %q, want %q", got, want)
+	}
+
+	config := "agent_prompt: |\n  Work on {task} ({task_title}) and check {url} on port {port}.\n"
+	if err := os.WriteFile(filepath.Join(dir, ".grove.yaml"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	want = "Work on TK-42 (Add OAuth login) and check https://feature-auth.localhost on port 3100."
+	if got := launch.Prompt(); got != want {
+		t.Errorf("Prompt() with agent_prompt = %q, want %q", got, want)
+	}
+
+	env := launch.Env("hi")
+	for _, kv := range []string{"GROVE_WORKTREE=feature-auth", "GROVE_URL=https://feature-auth.localhost", "PORT=3100", "GROVE_TASK=TK-42", "GROVE_AGENT_PROMPT=hi"} {
+		if !slices.Contains(env, kv) {
+			t.Errorf("Env() = %v, missing %s", env, kv)
+		}
+	}
+}
+
+func TestAgentTerminalCommand(t *testing.T) {
+	if _, err := exec.LookPath("script"); err != nil {
+		t.Skip("script not installed")
+	}
+
+	dir := t.TempDir()
+	transcript := filepath.Join(dir, "agent.log")
+	pidFile := transcript + ".pid"
+	cmd := agentTerminalCommand("printf '%s\\n'", []string{"it's working"}, transcript, pidFile)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("script failed: %v\n%s", err, output)
+	}
+
+	data, err := os.ReadFile(transcript)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "it's working") {
+		t.Errorf("transcript = %q, want the agent's output", data)
+	}
+	if pid := waitForPIDFile(pidFile, time.Second); pid <= 0 {
+		t.Errorf("no PID written to %s", pidFile)
+	}
+}
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -11,7 +12,9 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/lipgloss/table"
 	"github.com/charmbracelet/x/ansi"
+	"github.com/iheanyi/grove/internal/config"
 	"github.com/iheanyi/grove/internal/discovery"
+	"github.com/iheanyi/grove/internal/notify"
 	"github.com/iheanyi/grove/internal/registry"
 	"github.com/iheanyi/grove/internal/styles"
 	"github.com/spf13/cobra"
@@ -27,13 +30,33 @@ Shows agent type, working directory, duration, and process info.
 Examples:
   grove agents              # List all active agents
   grove agents --json       # Output as JSON
-  grove agents --watch      # Continuously update (every 2s)`,
+  grove agents --watch      # Continuously update (every 2s)
+  grove agents --notify     # Ping Slack/Discord about long-running agents
+
+--notify runs in the foreground and checks every 30s for agents that have
+been running longer than notifications.agents.long_running. Each session is
+reported once. Configure the webhooks in config.yaml:
+
+  notifications:
+    agents:
+      slack_webhook: https://hooks.slack.com/services/...
+      long_running: 2h
+
+It also enforces agent_limits. A session over max_runtime, or a Claude Code
+session over max_tokens, is logged to 'grove activity' and reported to the
+webhooks; with action: interrupt the agent is sent SIGINT as well:
+
+  agent_limits:
+    max_runtime: 3h
+    max_tokens: 2000000
+    action: interrupt`,
 	RunE: runAgents,
 }
 
 func init() {
 	agentsCmd.Flags().Bool("json", false, "Output in JSON format")
 	agentsCmd.Flags().Bool("watch", false, "Continuously update the list")
+	agentsCmd.Flags().Bool("notify", false, "Ping Slack/Discord about long-running agent sessions and enforce agent_limits")
 	agentsCmd.GroupID = "monitoring"
 	rootCmd.AddCommand(agentsCmd)
 }
@@ -41,7 +64,11 @@ func init() {
 func runAgents(cmd *cobra.Command, args []string) error {
 	jsonOutput, _ := cmd.Flags().GetBool("json")
 	watchMode, _ := cmd.Flags().GetBool("watch")
+	notifyMode, _ := cmd.Flags().GetBool("notify")
 
+	if notifyMode {
+		return runAgentsNotify()
+	}
 	if watchMode {
 		return runAgentsWatch(jsonOutput)
 	}
@@ -60,7 +87,7 @@ func runAgentsOnce(jsonOutput bool) error {
 	worktrees := reg.ListWorktrees()
 
 	// Use batch detection for all agents at once (much faster)
-	allAgents := discovery.DetectAllAgents()
+	allAgents := discovery.DetectAllAgents(context.Background())
 
 	// Match agents to worktrees, de-duplicating by PID
 	// Multiple worktrees can share the same path (different branches), but we only want to show each agent once
@@ -74,12 +101,8 @@ func runAgentsOnce(jsonOutput bool) error {
 			}
 			seenPIDs[agent.PID] = true
 
-			// Check for active Tasuku task
-			taskID, taskDesc := discovery.GetActiveTask(wt.Path)
-			if taskID != "" {
-				agent.ActiveTask = taskID
-				agent.TaskSummary = taskDesc
-			}
+			// Check for the task it's working on
+			discovery.SetAgentTask(agent, wt.Path)
 
 			agents = append(agents, &agentView{
 				Worktree: wt.Name,
@@ -123,6 +146,86 @@ func runAgentsWatch(jsonOutput bool) error {
 	}
 }
 
+func runAgentsNotify() error {
+	agentCfg := cfg.Notifications.Agents
+	limits := cfg.AgentLimits
+	pingLongRunning := agentCfg.Configured() && agentCfg.LongRunning > 0
+	if !pingLongRunning && !limits.Enabled() {
+		return fmt.Errorf("nothing to watch for (set notifications.agents.slack_webhook or discord_webhook, or agent_limits)")
+	}
+
+	watcher := notify.NewAgentWatcher(agentCfg, config.AgentNotifyStatePath())
+	limitWatcher := notify.NewLimitWatcher(limits, agentCfg, config.AgentLimitsStatePath())
+	if pingLongRunning {
+		fmt.Printf("Watching for agents running longer than %s (press Ctrl+C to exit)\n", agentCfg.LongRunning)
+	}
+	if limits.Enabled() {
+		fmt.Printf("Enforcing agent limits: %s (press Ctrl+C to exit)\n", describeAgentLimits(limits))
+	}
+
+	for {
+		sessions := activeAgentSessions()
+		now := time.Now()
+
+		sent, err := watcher.Check(sessions, now)
+		for _, msg := range sent {
+			fmt.Printf("[%s] Sent: %s\n", now.Format("15:04:05"), msg)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+
+		over, err := limitWatcher.Check(sessions, now)
+		for _, msg := range over {
+			fmt.Printf("[%s] Limit: %s\n", now.Format("15:04:05"), msg)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		time.Sleep(30 * time.Second)
+	}
+}
+
+// describeAgentLimits summarizes agent_limits, e.g. "2h0m0s runtime, 500000
+// tokens, then interrupt"
+func describeAgentLimits(limits config.AgentLimitsConfig) string {
+	var parts []string
+	if limits.MaxRuntime > 0 {
+		parts = append(parts, limits.MaxRuntime.String()+" runtime")
+	}
+	if limits.MaxTokens > 0 {
+		parts = append(parts, fmt.Sprintf("%d tokens", limits.MaxTokens))
+	}
+	action := limits.Action
+	if action == "" {
+		action = config.AgentLimitNotify
+	}
+	return strings.Join(parts, ", ") + ", then " + action
+}
+
+// activeAgentSessions returns the agents running in registered worktrees
+func activeAgentSessions() []notify.AgentSession {
+	reg, err := registry.Load()
+	if err != nil {
+		return nil
+	}
+
+	allAgents := discovery.DetectAllAgents(context.Background())
+	seenPIDs := make(map[int]bool)
+	var sessions []notify.AgentSession
+	for _, wt := range reg.ListWorktrees() {
+		agent, ok := allAgents[wt.Path]
+		if !ok || seenPIDs[agent.PID] {
+			continue
+		}
+		seenPIDs[agent.PID] = true
+
+		discovery.SetAgentTask(agent, wt.Path)
+		sessions = append(sessions, notify.AgentSession{Worktree: wt.Name, Agent: agent})
+	}
+	return sessions
+}
+
 type agentView struct {
 	Worktree string
 	Path     string
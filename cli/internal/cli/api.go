@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/iheanyi/grove/internal/api"
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/execx"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/service"
+	"github.com/spf13/cobra"
+)
+
+var apiCmd = &cobra.Command{
+	Use:   "api",
+	Short: "Serve the local JSON API used by the menubar app",
+	Long: `Serve grove's local API over a Unix socket (or a localhost TCP port with
+--addr), so the menubar app and other tools can list, start and stop
+servers and get notified of changes without parsing CLI output. Over TCP,
+only requests addressed to localhost are served, and requests from web
+pages of other origins are refused.
+
+Endpoints:
+  GET  /v1/health                Status and grove version
+  GET  /v1/servers               {"servers": [...]}, as in 'grove start --json'
+  GET  /v1/servers/{name}        One server
+  POST /v1/servers/{name}/start  Start a worktree's server
+  POST /v1/servers/{name}/stop   Stop a server
+  GET  /v1/events                Server-sent "servers" events on every change
+
+Examples:
+  grove api                                          # Listen on the default socket
+  grove api --addr 127.0.0.1:3098                    # Listen on a TCP port
+  curl --unix-socket "$(grove api --print-socket)" http://grove/v1/servers`,
+	RunE: runAPI,
+}
+
+func init() {
+	apiCmd.Flags().String("socket", "", "Unix socket to listen on (default: the state directory's, see --print-socket)")
+	apiCmd.Flags().String("addr", "", "Listen on this TCP address instead (localhost only, e.g. 127.0.0.1:3098)")
+	apiCmd.Flags().Bool("print-socket", false, "Print the socket path and exit")
+	apiCmd.Flags().DurationP("timeout", "t", 10*time.Second, "Timeout for graceful shutdown of stopped servers")
+}
+
+func runAPI(cmd *cobra.Command, args []string) error {
+	socket, _ := cmd.Flags().GetString("socket")
+	addr, _ := cmd.Flags().GetString("addr")
+	printSocket, _ := cmd.Flags().GetBool("print-socket")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	if socket == "" {
+		// Resolved now rather than as the flag's default, after
+		// --state-dir has been applied
+		socket = config.SocketPath()
+	}
+
+	if printSocket {
+		fmt.Println(socket)
+		return nil
+	}
+
+	listener, err := listenAPI(socket, addr)
+	if err != nil {
+		return err
+	}
+
+	// Requests read the registry through a cache that reloads it only when
+	// it changes
+	load := registry.LoadContext
+	if cache, err := registry.NewCache(cmd.Context()); err == nil {
+		defer cache.Close()
+		load = cache.Get
+	}
+
+	server := api.NewServer(apiActions{timeout: timeout}, load, Version)
+	httpServer := &http.Server{
+		Handler:           server.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	go watchResume(ctx)
+	go watchFatalLogs(ctx)
+	go watchAgentUsage(ctx)
+	go watchFreeze(ctx, service.API)
+	go func() {
+		<-ctx.Done()
+		httpServer.Close() //nolint:errcheck
+	}()
+
+	fmt.Printf("grove API listening on %s\n", listener.Addr())
+	if err := httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// listenAPI listens on addr if set, which must be a loopback address, or
+// else on the Unix socket, replacing a stale one left by a crashed listener
+func listenAPI(socket, addr string) (net.Listener, error) {
+	if addr != "" {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --addr: %w", err)
+		}
+		if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+			return nil, fmt.Errorf("--addr must be a localhost address, got %s", addr)
+		}
+		return net.Listen("tcp", addr)
+	}
+
+	if conn, err := net.Dial("unix", socket); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("grove API is already listening on %s", socket)
+	}
+	if err := os.Remove(socket); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to remove stale socket: %w", err)
+	}
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", socket, err)
+	}
+	// Only this user may start and stop their servers
+	if err := os.Chmod(socket, 0600); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to secure socket: %w", err)
+	}
+	return listener, nil
+}
+
+// apiActions starts and stops servers for the API like the CLI does
+type apiActions struct {
+	timeout time.Duration
+}
+
+// Start runs 'grove start' in the worktree, as the menubar app did
+func (a apiActions) Start(ctx context.Context, name string) error {
+	reg, err := registry.LoadContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+	ws, ok := reg.GetWorkspace(name)
+	if !ok {
+		return fmt.Errorf("%w: no worktree registered for '%s'", api.ErrNotFound, name)
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to get executable: %w", err)
+	}
+	var stderr bytes.Buffer
+	start := execx.Command(executable, "start")
+	start.Dir = ws.Path
+	start.Stderr = &stderr
+	if err := runner.Run(ctx, start); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return errors.New(msg)
+		}
+		return fmt.Errorf("failed to start '%s': %w", name, err)
+	}
+	return nil
+}
+
+// Stop stops the server like 'grove stop <name>'
+func (a apiActions) Stop(ctx context.Context, name string) error {
+	reg, err := registry.LoadContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+	if _, ok := reg.Get(name); !ok {
+		return fmt.Errorf("%w: no server registered for '%s'", api.ErrNotFound, name)
+	}
+	return stopServer(reg, name, a.timeout)
+}
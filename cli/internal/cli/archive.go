@@ -0,0 +1,207 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/iheanyi/grove/internal/archive"
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/discovery"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var archiveCmd = &cobra.Command{
+	Use:   "archive [name]",
+	Short: "Remove an idle worktree, keeping its branch and changes to restore",
+	Long: `Archive a worktree you aren't using: stop its server and remove it, but
+keep its branch and uncommitted changes so 'grove restore' can bring it back
+at the same path.
+
+Uncommitted changes, including untracked files, are stashed and kept under
+refs/grove/archive/<name> in the main repo. Otherwise removal works like
+'grove delete': logs are deleted and a provisioned database is dropped.
+
+With no name, lists archived worktrees. Use 'grove ls --stale 14d' to find
+candidates.
+
+Examples:
+  grove archive                 # List archived worktrees
+  grove archive feature-auth    # Archive a worktree
+  grove restore feature-auth    # Bring it back`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runArchive,
+}
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore <name>",
+	Short: "Restore an archived worktree",
+	Long: `Recreate an archived worktree at its old path, check out its branch and
+reapply its uncommitted changes. A branch deleted since archiving is
+recreated at the archived commit.
+
+Examples:
+  grove restore feature-auth`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRestore,
+}
+
+func init() {
+	archiveCmd.Flags().Bool("force", false, "Skip the confirmation prompt")
+}
+
+func runArchive(cmd *cobra.Command, args []string) error {
+	store, err := archive.LoadStore(config.ArchivesPath())
+	if err != nil {
+		return fmt.Errorf("failed to load archives: %w", err)
+	}
+
+	if len(args) == 0 {
+		return listArchives(store)
+	}
+
+	name := args[0]
+	force, _ := cmd.Flags().GetBool("force")
+
+	reg, err := registry.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+
+	ws, ok := reg.GetWorkspace(name)
+	if !ok {
+		return fmt.Errorf("worktree '%s' is not registered (run 'grove discover --register' in its repo)", name)
+	}
+
+	mainRepo := ws.MainRepo
+	if info, err := worktree.DetectAt(ws.Path); err == nil && info.IsWorktree && info.MainWorktreePath != "" {
+		mainRepo = info.MainWorktreePath
+	}
+	if mainRepo == "" || mainRepo == ws.Path {
+		return fmt.Errorf("cannot archive the main worktree")
+	}
+	if _, exists := store.Get(name); exists {
+		return fmt.Errorf("a worktree named '%s' is already archived (restore it with 'grove restore %s')", name, name)
+	}
+
+	fmt.Printf("Worktree: %s\n", name)
+	fmt.Printf("Path:     %s\n", ws.Path)
+	if activity := lastActivity(ws); !activity.IsZero() {
+		fmt.Printf("Idle:     %s\n", formatAge(time.Since(activity)))
+	}
+	if checkGitDirty(ws.Path) {
+		fmt.Println("\nUncommitted changes will be stashed and reapplied on restore.")
+	}
+	fmt.Println()
+
+	if !force && !confirm(fmt.Sprintf("Archive '%s'?", name)) {
+		fmt.Println("Canceled")
+		return nil
+	}
+
+	a, err := archive.New(name, ws.Path, mainRepo)
+	if err != nil {
+		return fmt.Errorf("failed to save worktree state: %w", err)
+	}
+	a.Tags = ws.Tags
+
+	if _, err := removeWorktree(reg, name, ws.Path, mainRepo, false); err != nil {
+		if a.Stash != "" {
+			fmt.Println("Reapplying uncommitted changes...")
+			if err := a.Unstash(); err != nil {
+				fmt.Printf("Warning: %v\n", err)
+			}
+		}
+		return err
+	}
+
+	if err := store.Set(a); err != nil {
+		return fmt.Errorf("worktree removed, but failed to record the archive: %w\nRestore by hand with: %s", err, a.RestoreCommand())
+	}
+
+	fmt.Printf("\nArchived '%s' (branch %s)\n", name, a.Branch)
+	fmt.Printf("Restore with: grove restore %s\n", name)
+	return nil
+}
+
+func listArchives(store *archive.Store) error {
+	archives := store.List()
+	if len(archives) == 0 {
+		fmt.Println("No archived worktrees")
+		return nil
+	}
+
+	for _, a := range archives {
+		changes := ""
+		if a.Stash != "" {
+			changes = ", uncommitted changes saved"
+		}
+		fmt.Printf("%-24s %s, archived %s ago%s\n", a.Name, a.Branch, formatAge(time.Since(a.ArchivedAt)), changes)
+	}
+	fmt.Println("\nRestore with: grove restore <name>")
+	return nil
+}
+
+func runRestore(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	store, err := archive.LoadStore(config.ArchivesPath())
+	if err != nil {
+		return fmt.Errorf("failed to load archives: %w", err)
+	}
+	a, ok := store.Get(name)
+	if !ok {
+		return fmt.Errorf("no archived worktree named '%s' (run 'grove archive' to list them)", name)
+	}
+
+	reg, err := registry.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+	if _, exists := reg.GetWorkspace(name); exists {
+		return fmt.Errorf("a worktree named '%s' is already registered", name)
+	}
+
+	fmt.Printf("Restoring %s at %s...\n", a.Branch, a.Path)
+	if err := a.Restore(); err != nil {
+		if _, statErr := os.Stat(a.Path); statErr != nil {
+			return err
+		}
+		// The worktree is back, only the changes failed to apply
+		fmt.Printf("Warning: %v\n", err)
+	}
+
+	if err := registerRestored(reg, name, a.Path, a.Branch, a.MainRepo); err != nil {
+		fmt.Printf("Warning: failed to register worktree: %v\n", err)
+	}
+	if ws, ok := reg.GetWorkspace(name); ok && len(a.Tags) > 0 {
+		ws.Tags = a.Tags
+		if err := reg.SetWorkspace(ws); err != nil {
+			fmt.Printf("Warning: failed to restore tags: %v\n", err)
+		}
+	}
+
+	if err := store.Remove(name); err != nil {
+		return fmt.Errorf("failed to update archives: %w", err)
+	}
+
+	fmt.Printf("Restored '%s'\n", name)
+	fmt.Printf("cd %s\n", a.Path)
+	return nil
+}
+
+// registerRestored registers a worktree brought back by 'grove restore' or
+// 'grove trash restore'
+func registerRestored(reg *registry.Registry, name, path, branch, mainRepo string) error {
+	now := time.Now()
+	return reg.SetWorktree(&discovery.Worktree{
+		Name:         name,
+		Path:         path,
+		Branch:       branch,
+		MainRepo:     mainRepo,
+		DiscoveredAt: now,
+		LastActivity: now,
+	})
+}
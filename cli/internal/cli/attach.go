@@ -151,8 +151,8 @@ func runAttach(cmd *cobra.Command, args []string) error {
 		fmt.Println("  PID:    unknown (server won't be tracked for lifecycle)")
 	}
 
-	// Check if proxy is running (only relevant in subdomain mode)
-	if cfg.IsSubdomainMode() {
+	// Check if proxy is running (only relevant in subdomain and path mode)
+	if cfg.UsesProxy() {
 		proxy := reg.GetProxy()
 		if !proxy.IsRunning() || !isProcessRunning(proxy.PID) {
 			fmt.Println()
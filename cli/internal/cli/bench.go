@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/iheanyi/grove/internal/bench"
+	"github.com/iheanyi/grove/internal/output"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var benchCmd = &cobra.Command{
+	Use:   "bench [name]",
+	Short: "Load test a server and report latency and errors",
+	Long: `Send requests to a running server at a fixed rate and report the latency
+percentiles (p50, p90, p99, max) and error rate. Requests go to the
+server's port directly, so the proxy doesn't skew the numbers. Responses
+with a 5xx status and failed requests count as errors.
+
+With --compare and two names, each server is benchmarked in turn with the
+same load, and the results are shown side by side with the second's
+difference from the first, e.g. to check a branch against main.
+
+Examples:
+  grove bench                                   # Current worktree's server, GET /
+  grove bench feature-auth --duration 10s --rps 50 --path /api/health
+  grove bench --compare main feature-auth       # Side by side
+  grove bench feature-auth --json`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runBench,
+}
+
+func init() {
+	benchCmd.Flags().Duration("duration", 10*time.Second, "How long to send requests for")
+	benchCmd.Flags().Int("rps", 20, "Requests per second")
+	benchCmd.Flags().String("path", "/", "Path to request")
+	benchCmd.Flags().Int("concurrency", 0, "Maximum requests in flight (default: the rate)")
+	benchCmd.Flags().Duration("timeout", 10*time.Second, "Timeout for each request")
+	benchCmd.Flags().Bool("compare", false, "Benchmark two servers and compare them")
+	addOutputFlags(benchCmd)
+}
+
+func runBench(cmd *cobra.Command, args []string) error {
+	duration, _ := cmd.Flags().GetDuration("duration")
+	rps, _ := cmd.Flags().GetInt("rps")
+	path, _ := cmd.Flags().GetString("path")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	compare, _ := cmd.Flags().GetBool("compare")
+
+	if compare && len(args) != 2 {
+		return fmt.Errorf("--compare needs two servers, e.g. grove bench --compare main feature-auth")
+	}
+	if !compare && len(args) > 1 {
+		return fmt.Errorf("use --compare to benchmark two servers")
+	}
+	if rps <= 0 || duration <= 0 {
+		return fmt.Errorf("--rps and --duration must be positive")
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	reg, err := registry.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+	names := args
+	if len(names) == 0 {
+		wt, err := worktree.Detect()
+		if err != nil {
+			return fmt.Errorf("failed to detect worktree: %w", err)
+		}
+		names = []string{wt.Name}
+	}
+	// Check every server before spending time on the first
+	servers := make([]*registry.Server, 0, len(names))
+	for _, name := range names {
+		server, err := runningServer(reg, name)
+		if err != nil {
+			return err
+		}
+		servers = append(servers, server)
+	}
+
+	return runWithOutput(cmd, func() (any, error) {
+		client := &http.Client{Timeout: timeout}
+		report := output.BenchReport{Runs: []output.BenchRun{}}
+		for _, server := range servers {
+			url := fmt.Sprintf("http://localhost:%d%s", server.Port, path)
+			fmt.Printf("Benchmarking %s (%s): %s at %d requests/s...\n", server.Name, url, duration, rps)
+			result, err := bench.Run(cmd.Context(), client, bench.Options{
+				URL:         url,
+				Duration:    duration,
+				RPS:         rps,
+				Concurrency: concurrency,
+			})
+			if err != nil {
+				return nil, err
+			}
+			report.Runs = append(report.Runs, output.BenchRun{Server: server.Name, Result: result})
+		}
+		fmt.Println()
+
+		if len(report.Runs) == 2 {
+			printBenchComparison(report.Runs[0], report.Runs[1])
+		} else {
+			printBenchResult(report.Runs[0].Result)
+		}
+		return report, nil
+	})
+}
+
+// runningServer returns a registered server that's running
+func runningServer(reg *registry.Registry, name string) (*registry.Server, error) {
+	server, ok := reg.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("no server registered for '%s'\nUse 'grove start' to start a server first", name)
+	}
+	if !server.IsRunning() {
+		return nil, fmt.Errorf("server '%s' is not running\nUse 'grove start' to start it", name)
+	}
+	return server, nil
+}
+
+func printBenchResult(r bench.Result) {
+	fmt.Printf("Requests:  %d (%.1f/s)\n", r.Requests, r.RPS)
+	fmt.Printf("Errors:    %d (%.1f%%)\n", r.Errors, r.ErrorRate()*100)
+	fmt.Printf("Latency:   p50 %s  p90 %s  p99 %s  max %s\n", formatMs(r.P50Ms), formatMs(r.P90Ms), formatMs(r.P99Ms), formatMs(r.MaxMs))
+	fmt.Printf("Statuses:  %s\n", formatStatuses(r.Statuses))
+}
+
+// printBenchComparison shows two results side by side, with b's change
+// from a
+func printBenchComparison(a, b output.BenchRun) {
+	width := max(len(a.Server), len(b.Server), 10)
+	fmt.Printf("%-10s  %*s  %*s  %s\n", "", width, a.Server, width, b.Server, "change")
+	row := func(label, av, bv, change string) {
+		fmt.Printf("%-10s  %*s  %*s  %s\n", label, width, av, width, bv, change)
+	}
+	latency := func(label string, av, bv float64) {
+		row(label, formatMs(av), formatMs(bv), formatChange(av, bv))
+	}
+
+	row("requests", fmt.Sprint(a.Result.Requests), fmt.Sprint(b.Result.Requests), "")
+	row("rps", fmt.Sprintf("%.1f", a.Result.RPS), fmt.Sprintf("%.1f", b.Result.RPS), formatChange(a.Result.RPS, b.Result.RPS))
+	row("errors", fmt.Sprintf("%.1f%%", a.Result.ErrorRate()*100), fmt.Sprintf("%.1f%%", b.Result.ErrorRate()*100), "")
+	latency("p50", a.Result.P50Ms, b.Result.P50Ms)
+	latency("p90", a.Result.P90Ms, b.Result.P90Ms)
+	latency("p99", a.Result.P99Ms, b.Result.P99Ms)
+	latency("max", a.Result.MaxMs, b.Result.MaxMs)
+}
+
+// formatMs formats milliseconds, e.g. 3.2ms or 1.25s
+func formatMs(ms float64) string {
+	if ms >= 1000 {
+		return fmt.Sprintf("%.2fs", ms/1000)
+	}
+	return fmt.Sprintf("%.1fms", ms)
+}
+
+// formatChange formats the relative change from a to b, e.g. +12%
+func formatChange(a, b float64) string {
+	if a == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%+.0f%%", (b-a)/a*100)
+}
+
+// formatStatuses formats status counts, e.g. "200 ×480, 503 ×20"
+func formatStatuses(statuses map[string]int) string {
+	if len(statuses) == 0 {
+		return "none"
+	}
+	codes := make([]string, 0, len(statuses))
+	for code := range statuses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	parts := make([]string, 0, len(codes))
+	for _, code := range codes {
+		parts = append(parts, fmt.Sprintf("%s ×%d", code, statuses[code]))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+
+	"github.com/iheanyi/grove/internal/discovery"
+	"github.com/iheanyi/grove/internal/editor"
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+// stopWorktreeProcesses stops the AI agents working in a worktree and, once
+// confirmEditor agrees, closes the editors that have it open, so a finished
+// branch can be cleaned up with one command. It returns the PIDs it
+// signaled.
+func stopWorktreeProcesses(ctx context.Context, path string, confirmEditor func(prompt string) bool) []int {
+	var stopped []int
+
+	for _, agent := range discovery.AgentsIn(ctx, path) {
+		fmt.Printf("Stopping %s agent (PID: %d)... ", agent.Type, agent.PID)
+		if err := terminateProcess(agent.PID); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+			continue
+		}
+		fmt.Println("done")
+		stopped = append(stopped, agent.PID)
+	}
+
+	var processes []string
+	for _, e := range editor.Known() {
+		processes = append(processes, e.Process)
+	}
+	for _, ed := range discovery.EditorsIn(ctx, path, processes) {
+		// Editors can hold unsaved work, and closing the process may close
+		// other windows with it
+		if !confirmEditor(fmt.Sprintf("Close %s (PID: %d), which has the worktree open?", ed.Process, ed.PID)) {
+			continue
+		}
+		if err := terminateProcess(ed.PID); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+			continue
+		}
+		stopped = append(stopped, ed.PID)
+	}
+
+	return stopped
+}
+
+// editorConfirm returns how closing an editor is confirmed: not at all
+// with force, and by leaving it open with machine-readable output, where
+// the prompt would go unseen
+func editorConfirm(force, machine bool) func(prompt string) bool {
+	switch {
+	case force:
+		return func(string) bool { return true }
+	case machine:
+		return func(string) bool { return false }
+	}
+	return confirm
+}
+
+// terminateProcess asks a process to exit with SIGTERM
+func terminateProcess(pid int) error {
+	process, err := runner.FindProcess(pid)
+	if err != nil {
+		return fmt.Errorf("failed to find process %d: %w", pid, err)
+	}
+	if err := process.Signal(syscall.SIGTERM); err != nil {
+		return fmt.Errorf("failed to stop process %d: %w", pid, err)
+	}
+	return nil
+}
+
+// worktreePath returns the path of a registered server or worktree, or ""
+func worktreePath(reg *registry.Registry, name string) string {
+	if ws, ok := reg.GetWorkspace(name); ok {
+		return ws.Path
+	}
+	if wt, ok := reg.GetWorktree(name); ok {
+		return wt.Path
+	}
+	return ""
+}
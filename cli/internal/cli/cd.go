@@ -1,11 +1,10 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"strings"
 
 	"github.com/iheanyi/grove/internal/registry"
 	"github.com/iheanyi/grove/internal/worktree"
@@ -32,23 +31,30 @@ Examples:
 }
 
 func runCd(cmd *cobra.Command, args []string) error {
-	name := args[0]
+	path, err := resolveWorktreePath(args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(path)
+	return nil
+}
 
-	// First, try to find in registry (servers)
+// resolveWorktreePath returns the path of a worktree by name, checking the
+// registry's servers and worktrees before git worktree list
+func resolveWorktreePath(name string) (string, error) {
 	reg, err := registry.Load()
 	if err == nil {
 		if server, ok := reg.Get(name); ok {
 			if _, err := os.Stat(server.Path); err == nil {
-				fmt.Println(server.Path)
-				return nil
+				return server.Path, nil
 			}
 		}
 
 		// Try worktrees in registry
 		if wt, ok := reg.GetWorktree(name); ok {
 			if _, err := os.Stat(wt.Path); err == nil {
-				fmt.Println(wt.Path)
-				return nil
+				return wt.Path, nil
 			}
 		}
 	}
@@ -56,11 +62,9 @@ func runCd(cmd *cobra.Command, args []string) error {
 	// Fallback: try to find via git worktree list
 	path, err := findWorktreeByName(name)
 	if err != nil {
-		return fmt.Errorf("worktree '%s' not found", name)
+		return "", fmt.Errorf("worktree '%s' not found", name)
 	}
-
-	fmt.Println(path)
-	return nil
+	return path, nil
 }
 
 // findWorktreeByName searches for a worktree by name using git worktree list
@@ -119,28 +123,16 @@ func findWorktreeByName(name string) (string, error) {
 
 // searchWorktreesFromRepo searches for a worktree by name within a git repository
 func searchWorktreesFromRepo(repoPath string, name string) (string, error) {
-	cmd := exec.Command("git", "worktree", "list", "--porcelain")
-	cmd.Dir = repoPath
-	output, err := cmd.Output()
+	worktrees, err := gitOps().Worktrees(context.Background(), repoPath)
 	if err != nil {
 		return "", err
 	}
 
-	lines := strings.Split(string(output), "\n")
-	var currentPath string
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "worktree ") {
-			currentPath = strings.TrimPrefix(line, "worktree ")
-
-			// Check if the base name matches
-			baseName := filepath.Base(currentPath)
-			if baseName == name {
-				// Verify the path exists
-				if _, err := os.Stat(currentPath); err == nil {
-					return currentPath, nil
-				}
+	for _, wt := range worktrees {
+		// Check the base name matches and the path exists
+		if filepath.Base(wt.Path) == name {
+			if _, err := os.Stat(wt.Path); err == nil {
+				return wt.Path, nil
 			}
 		}
 	}
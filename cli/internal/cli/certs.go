@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/iheanyi/grove/internal/certs"
+	"github.com/iheanyi/grove/internal/output"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/spf13/cobra"
+)
+
+var certsCmd = &cobra.Command{
+	Use:   "certs",
+	Short: "Manage trusted HTTPS certificates for the proxy",
+	Long: `Manage locally-trusted HTTPS certificates for the proxy using mkcert.
+
+Caddy's built-in local certificates cause browser warnings for custom TLDs
+(anything other than 'localhost'). 'grove certs install' installs mkcert's
+local CA into your trust stores and generates wildcard certificates for the
+configured TLD and every registered worktree. The proxy uses them
+automatically.
+
+Examples:
+  grove certs install   # Trust the local CA and generate certificates
+  grove certs status    # Show certificate coverage and expiry
+  grove certs remove    # Remove generated certificates`,
+}
+
+var certsInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Generate trusted certificates for the configured TLD",
+	Long: `Generate trusted certificates for the configured TLD with mkcert.
+
+The certificate covers *.<tld> plus <name>.<tld> and *.<name>.<tld> for every
+registered worktree. Re-run after creating new worktrees to cover them;
+uncovered domains fall back to Caddy's internal certificates.`,
+	RunE: runCertsInstall,
+}
+
+var certsStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show certificate status",
+	RunE:  runCertsStatus,
+}
+
+var certsRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Remove generated certificates",
+	RunE:  runCertsRemove,
+}
+
+func init() {
+	certsInstallCmd.Flags().Bool("skip-trust", false, "Don't install the mkcert CA into system trust stores")
+
+	certsCmd.AddCommand(certsInstallCmd)
+	certsCmd.AddCommand(certsStatusCmd)
+	certsCmd.AddCommand(certsRemoveCmd)
+
+	certsCmd.GroupID = "proxy"
+	rootCmd.AddCommand(certsCmd)
+}
+
+func runCertsInstall(cmd *cobra.Command, args []string) error {
+	if !certs.MkcertAvailable() {
+		return fmt.Errorf("mkcert not found in PATH. Install with: brew install mkcert")
+	}
+
+	skipTrust, _ := cmd.Flags().GetBool("skip-trust")
+	if !skipTrust {
+		fmt.Println("Installing local CA (you may be prompted for your password)...")
+		if err := certs.InstallCA(); err != nil {
+			return err
+		}
+	}
+
+	reg, err := registry.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+
+	names := certs.Names(cfg.TLD, registeredNames(reg))
+	fmt.Printf("Generating certificate for %d name(s) on .%s...\n", len(names), cfg.TLD)
+
+	cert, err := certs.Generate(cfg.TLD, names)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Certificate: %s\n", cert.CertFile)
+	fmt.Printf("Key:         %s\n", cert.KeyFile)
+	fmt.Printf("Expires:     %s\n", cert.NotAfter.Format("2006-01-02"))
+
+	if err := ReloadProxy(); err != nil {
+		fmt.Printf("Warning: failed to reload proxy: %v\n", err)
+	}
+
+	return nil
+}
+
+func runCertsStatus(cmd *cobra.Command, args []string) error {
+	reg, err := registry.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+
+	printCertStatus(reg)
+	return nil
+}
+
+func runCertsRemove(cmd *cobra.Command, args []string) error {
+	if err := certs.Remove(cfg.TLD); err != nil {
+		return fmt.Errorf("failed to remove certificates: %w", err)
+	}
+	fmt.Printf("Removed certificates for .%s\n", cfg.TLD)
+
+	if err := ReloadProxy(); err != nil {
+		fmt.Printf("Warning: failed to reload proxy: %v\n", err)
+	}
+	return nil
+}
+
+// printCertStatus prints which certificate the proxy uses and which
+// registered domains it doesn't cover
+func printCertStatus(reg *registry.Registry) {
+	status := certStatus(reg)
+	switch {
+	case status.Error != "":
+		fmt.Printf("Certs:      error: %s\n", status.Error)
+		return
+	case status.Source == "caddy":
+		fmt.Println("Certs:      Caddy internal (run 'grove certs install' for trusted certificates)")
+		return
+	}
+
+	state := status.State
+	switch state {
+	case "expired":
+		state = "EXPIRED"
+	case "expiring":
+		state = "expiring soon"
+	}
+	fmt.Printf("Certs:      mkcert (%s, expires %s)\n", state, status.ExpiresAt.Format("2006-01-02"))
+	fmt.Printf("Cert File:  %s\n", status.CertFile)
+
+	if len(status.Uncovered) > 0 {
+		fmt.Printf("Uncovered:  %d domain(s) use Caddy internal certs; run 'grove certs install' to update\n", len(status.Uncovered))
+		for _, host := range status.Uncovered {
+			fmt.Printf("            %s\n", host)
+		}
+	}
+}
+
+// certStatus returns which certificate the proxy uses and which registered
+// domains it doesn't cover
+func certStatus(reg *registry.Registry) output.CertStatus {
+	cert, err := certs.Load(cfg.TLD)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return output.CertStatus{Source: "caddy"}
+		}
+		return output.CertStatus{Source: "mkcert", Error: err.Error()}
+	}
+
+	status := output.CertStatus{
+		Source:    "mkcert",
+		State:     "valid",
+		CertFile:  cert.CertFile,
+		ExpiresAt: output.TimePtr(cert.NotAfter),
+	}
+	if cert.Expired() {
+		status.State = "expired"
+	} else if time.Until(cert.NotAfter) < 30*24*time.Hour {
+		status.State = "expiring"
+	}
+
+	for _, name := range registeredNames(reg) {
+		host := fmt.Sprintf("%s.%s", name, cfg.TLD)
+		if !cert.Covers(host) || !cert.Covers("*."+host) {
+			status.Uncovered = append(status.Uncovered, host)
+		}
+	}
+	return status
+}
+
+// registeredNames returns the names of all registered servers, sorted
+func registeredNames(reg *registry.Registry) []string {
+	var names []string
+	for _, s := range reg.List() {
+		names = append(names, s.Name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,260 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/iheanyi/grove/internal/checks"
+	"github.com/iheanyi/grove/internal/output"
+	"github.com/iheanyi/grove/internal/project"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+// ciOutputLines is how much of a failed check's output is shown
+const ciOutputLines = 20
+
+var ciCmd = &cobra.Command{
+	Use:   "ci [name]",
+	Short: "Run a worktree's checks and record the results",
+	Long: `Run the check commands defined under 'checks:' in .grove.yaml (lint, test,
+typecheck...) in a worktree, one at a time in name order, and record whether
+each passed and how long it took. 'grove ls' shows the results in a CHECKS
+column and the review queue shows them on each item, so you know which
+branches are green before reviewing them.
+
+A failed check's last lines of output are shown; use --verbose to see all
+of every check's output as it runs.
+
+Example .grove.yaml:
+  checks:
+    lint: npm run lint
+    test: npm test
+    typecheck: npx tsc --noEmit
+
+Examples:
+  grove ci                     # Check the current worktree
+  grove ci feature-auth        # Check a named worktree
+  grove ci --all               # Every worktree with checks
+  grove ci --timeout 10m       # Fail checks that run longer
+  grove ci --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCI,
+}
+
+func init() {
+	ciCmd.Flags().Bool("all", false, "Run the checks of every registered worktree that has them")
+	ciCmd.Flags().Duration("timeout", 0, "Fail checks that run longer than this (default: no limit)")
+	ciCmd.Flags().BoolP("verbose", "v", false, "Show every check's output as it runs")
+	addOutputFlags(ciCmd)
+}
+
+// ciTarget is a worktree to run checks in
+type ciTarget struct {
+	name, path string
+}
+
+func runCI(cmd *cobra.Command, args []string) error {
+	all, _ := cmd.Flags().GetBool("all")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	if all && len(args) > 0 {
+		return fmt.Errorf("use either a worktree name or --all")
+	}
+
+	reg, err := registry.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+	targets, err := ciTargets(reg, args, all)
+	if err != nil {
+		return err
+	}
+
+	// Interrupting stops the running check and records nothing
+	ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	return runWithOutput(cmd, func() (any, error) {
+		result := output.CIResult{Runs: []output.CIRun{}}
+		var failed []string
+		for _, target := range targets {
+			configured, err := loadChecks(target.path)
+			if err != nil {
+				return result, err
+			}
+			if len(configured) == 0 {
+				if all {
+					continue
+				}
+				return nil, fmt.Errorf("no checks configured for '%s'\nAdd commands under 'checks:' in its .grove.yaml, e.g.\n  checks:\n    test: npm test", target.name)
+			}
+
+			run, err := runChecks(ctx, reg, target, configured, timeout, verbose)
+			if err != nil {
+				return result, err
+			}
+			result.Runs = append(result.Runs, run)
+			if !run.Passed {
+				failed = append(failed, run.Name)
+			}
+		}
+
+		if all && len(result.Runs) == 0 {
+			fmt.Println("No worktrees have checks configured.")
+			return result, nil
+		}
+		if len(failed) > 0 {
+			return result, fmt.Errorf("checks failed in %s", strings.Join(failed, ", "))
+		}
+		return result, nil
+	})
+}
+
+// ciTargets returns the worktrees to check: the named one, every
+// registered one for --all, or the current one
+func ciTargets(reg *registry.Registry, args []string, all bool) ([]ciTarget, error) {
+	switch {
+	case all:
+		var targets []ciTarget
+		for _, ws := range reg.ListWorkspaces() {
+			if _, err := os.Stat(ws.Path); err == nil {
+				targets = append(targets, ciTarget{name: ws.Name, path: ws.Path})
+			}
+		}
+		sort.Slice(targets, func(i, j int) bool { return targets[i].name < targets[j].name })
+		return targets, nil
+	case len(args) > 0:
+		path, err := resolveWorktreePath(args[0])
+		if err != nil {
+			return nil, err
+		}
+		return []ciTarget{{name: args[0], path: path}}, nil
+	default:
+		wt, err := worktree.Detect()
+		if err != nil {
+			return nil, fmt.Errorf("failed to detect worktree: %w", err)
+		}
+		return []ciTarget{{name: wt.Name, path: wt.Path}}, nil
+	}
+}
+
+// loadChecks returns the checks configured in a worktree's .grove.yaml
+func loadChecks(path string) (map[string]string, error) {
+	projConfig, err := project.Load(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load .grove.yaml: %w", err)
+	}
+	return projConfig.Checks, nil
+}
+
+// runChecks runs a worktree's checks in name order and records the results
+// in the registry
+func runChecks(ctx context.Context, reg *registry.Registry, target ciTarget, configured map[string]string, timeout time.Duration, verbose bool) (output.CIRun, error) {
+	record := &checks.Record{
+		Head:  getGitHead(target.path),
+		Dirty: checkGitDirty(target.path),
+		RanAt: time.Now(),
+	}
+	fmt.Printf("Checks for %s\n\n", target.name)
+
+	for _, name := range checks.Names(configured) {
+		command := configured[name]
+		fmt.Printf("▸ %s: %s\n", name, command)
+
+		var buf bytes.Buffer
+		var out io.Writer = &buf
+		if verbose {
+			out = os.Stdout
+		}
+		r := checks.Run(ctx, target.path, name, command, timeout, out)
+		if ctx.Err() != nil {
+			return output.CIRun{}, fmt.Errorf("interrupted; no results recorded")
+		}
+		record.Results = append(record.Results, r)
+
+		duration := formatCheckDuration(r.DurationMs)
+		switch {
+		case r.Passed:
+			fmt.Printf("  ✓ %s (%s)\n", name, duration)
+		case r.Error != "":
+			fmt.Printf("  ✗ %s %s (%s)\n", name, r.Error, duration)
+		default:
+			fmt.Printf("  ✗ %s exited with %d (%s)\n", name, r.ExitCode, duration)
+		}
+		if !r.Passed && !verbose {
+			for _, line := range lastLines(buf.String(), ciOutputLines) {
+				fmt.Printf("    %s\n", line)
+			}
+		}
+	}
+
+	fmt.Println()
+	if record.Passed() {
+		fmt.Printf("All %d checks passed in %s\n", len(record.Results), record.Duration().Round(time.Second))
+	} else {
+		fmt.Printf("%d of %d checks failed\n", len(record.Failed()), len(record.Results))
+	}
+
+	err := ensureWorkspace(reg, target.name, target.path)
+	if err == nil {
+		err = reg.RecordChecks(target.name, record)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record results: %v\n", err)
+	}
+
+	return output.CIRun{
+		Name:   target.name,
+		Path:   target.path,
+		Head:   record.Head,
+		Dirty:  record.Dirty,
+		Passed: record.Passed(),
+		Checks: record.Results,
+	}, nil
+}
+
+// formatCheckDuration formats a check's duration, e.g. 850ms or 1m12.4s
+func formatCheckDuration(ms int64) string {
+	d := time.Duration(ms) * time.Millisecond
+	if d < time.Second {
+		return d.String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
+
+// lastLines returns the last n lines of s, ignoring a trailing newline
+func lastLines(s string, n int) []string {
+	s = strings.TrimRight(s, "\n")
+	if s == "" {
+		return nil
+	}
+	lines := strings.Split(s, "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
+
+// formatChecks returns a worktree's recorded checks for a column, e.g.
+// "✓ 3/3", or "-" if they've never run
+func formatChecks(record *checks.Record) string {
+	if record == nil || len(record.Results) == 0 {
+		return "-"
+	}
+	return record.Summary()
+}
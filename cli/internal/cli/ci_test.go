@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/iheanyi/grove/internal/checks"
+)
+
+func TestRunChecks(t *testing.T) {
+	reg := useTestEnv(t)
+	dir := t.TempDir()
+	gitRun(t, dir, "init", "-q", "-b", "main")
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("one\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, dir, "add", ".")
+	gitRun(t, dir, "commit", "-q", "-m", "init")
+	head := gitRun(t, dir, "rev-parse", "HEAD")
+
+	configured := map[string]string{
+		"test": "echo failing >&2; exit 1",
+		"lint": "true",
+	}
+	run, err := runChecks(context.Background(), reg, ciTarget{name: "feature", path: dir}, configured, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Passed || run.Head != head || run.Dirty {
+		t.Errorf("runChecks() = %+v, want a failed run at %s", run, head)
+	}
+	var names []string
+	for _, r := range run.Checks {
+		names = append(names, r.Name)
+	}
+	if want := []string{"lint", "test"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("checks ran in order %v, want %v", names, want)
+	}
+
+	// The results are recorded on the worktree, registering it
+	ws, ok := reg.GetWorkspace("feature")
+	if !ok || ws.Checks == nil {
+		t.Fatal("checks not recorded")
+	}
+	if got := formatChecks(ws.Checks); got != "✗ 1/2 (test)" {
+		t.Errorf("formatChecks() = %q", got)
+	}
+	if !ws.Checks.Current(head) {
+		t.Error("recorded checks should be current for HEAD")
+	}
+}
+
+func TestFormatChecks(t *testing.T) {
+	if got := formatChecks(nil); got != "-" {
+		t.Errorf("formatChecks(nil) = %q, want -", got)
+	}
+	rec := &checks.Record{Results: []checks.Result{{Name: "lint", Passed: true}}}
+	if got := formatChecks(rec); got != "✓ 1/1" {
+		t.Errorf("formatChecks() = %q, want ✓ 1/1", got)
+	}
+}
+
+func TestLastLines(t *testing.T) {
+	if got := lastLines("a\nb\nc\n", 2); !reflect.DeepEqual(got, []string{"b", "c"}) {
+		t.Errorf("lastLines() = %q", got)
+	}
+	if got := lastLines("\n", 2); got != nil {
+		t.Errorf("lastLines(empty) = %q, want nil", got)
+	}
+}
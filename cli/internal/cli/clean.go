@@ -0,0 +1,363 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/iheanyi/grove/internal/diskusage"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/styles"
+	"github.com/iheanyi/grove/internal/usage"
+	"github.com/iheanyi/grove/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var cleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Delete stale worktrees and worktrees with merged branches",
+	Long: `Find worktrees that are safe to clean up and offer to delete them.
+
+A worktree is a candidate when:
+  --merged       its branch is merged into the default branch, or its
+                 upstream branch was deleted (e.g. after a squash merge)
+  --older-than   it has had no commits or activity for the given duration
+  --larger-than  it takes more disk space than the given size
+
+With no flags, --merged is used. When several are given, worktrees matching
+any are listed, with the disk space each takes. Main worktrees are never
+cleaned. 'grove du' shows the space every worktree takes.
+
+Each deletion follows 'grove delete': the server is stopped, the worktree is
+removed with 'git worktree remove', and registry entries and logs are
+cleaned up. Worktrees with uncommitted changes are skipped unless
+--include-dirty; --force only skips the confirmation.
+
+Examples:
+  grove clean                       # List merged worktrees and prompt
+  grove clean --older-than 30d      # Worktrees idle for 30 days
+  grove clean --merged --older-than 2w
+  grove clean --larger-than 2G      # Worktrees taking over 2 GB
+  grove clean --dry-run             # Show candidates without deleting
+  grove clean --force               # Delete without prompting
+  grove clean --include-dirty       # Also delete worktrees with uncommitted changes`,
+	RunE: runClean,
+}
+
+func init() {
+	cleanCmd.Flags().Bool("merged", false, "Include worktrees whose branch is merged or deleted upstream")
+	cleanCmd.Flags().String("older-than", "", "Include worktrees with no activity for this long (e.g. 30d, 2w, 48h)")
+	cleanCmd.Flags().String("larger-than", "", "Include worktrees taking more disk space than this (e.g. 500M, 2G)")
+	cleanCmd.Flags().Bool("dry-run", false, "Show what would be deleted without making changes")
+	cleanCmd.Flags().Bool("force", false, "Skip confirmation")
+	cleanCmd.Flags().Bool("include-dirty", false, "Delete worktrees with uncommitted changes too")
+	cleanCmd.GroupID = "worktree"
+	rootCmd.AddCommand(cleanCmd)
+}
+
+// cleanCandidate is a worktree that matched one of the clean criteria
+type cleanCandidate struct {
+	Name         string
+	Path         string
+	Branch       string
+	MainRepo     string
+	Reasons      []string
+	LastActivity time.Time
+	Dirty        bool
+	// Size is the disk space the worktree takes, in bytes
+	Size uint64
+}
+
+func runClean(cmd *cobra.Command, args []string) error {
+	merged, _ := cmd.Flags().GetBool("merged")
+	olderThanStr, _ := cmd.Flags().GetString("older-than")
+	largerThanStr, _ := cmd.Flags().GetString("larger-than")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	force, _ := cmd.Flags().GetBool("force")
+	includeDirty, _ := cmd.Flags().GetBool("include-dirty")
+
+	var olderThan time.Duration
+	if olderThanStr != "" {
+		d, err := parseAge(olderThanStr)
+		if err != nil {
+			return err
+		}
+		olderThan = d
+	}
+	var largerThan uint64
+	if largerThanStr != "" {
+		size, err := diskusage.ParseSize(largerThanStr)
+		if err != nil {
+			return err
+		}
+		largerThan = size
+	}
+	if olderThan == 0 && largerThan == 0 {
+		merged = true
+	}
+
+	reg, err := registry.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+
+	candidates := findCleanCandidates(reg, merged, olderThan, largerThan)
+	if len(candidates) == 0 {
+		fmt.Println("Nothing to clean - no matching worktrees found.")
+		return nil
+	}
+
+	printCleanCandidates(candidates)
+
+	if dryRun {
+		fmt.Println("\n(Dry run - no changes made)")
+		return nil
+	}
+
+	fmt.Println()
+	if !force && !confirm(fmt.Sprintf("Delete %d worktree(s)?", len(candidates))) {
+		fmt.Println("Canceled")
+		return nil
+	}
+
+	deleted := 0
+	for _, c := range candidates {
+		fmt.Printf("\n%s\n", styles.NameStyle.Render(c.Name))
+		if c.Dirty && !includeDirty {
+			fmt.Println("Skipped: uncommitted changes (use --include-dirty to delete anyway)")
+			continue
+		}
+		if _, err := removeWorktree(reg, c.Name, c.Path, c.MainRepo, c.Dirty); err != nil {
+			fmt.Printf("Failed: %v\n", err)
+			continue
+		}
+		deleted++
+	}
+
+	fmt.Printf("\nDeleted %d of %d worktree(s)\n", deleted, len(candidates))
+	return nil
+}
+
+// findCleanCandidates returns registered worktrees matching the criteria,
+// oldest activity first
+func findCleanCandidates(reg *registry.Registry, merged bool, olderThan time.Duration, largerThan uint64) []*cleanCandidate {
+	var candidates []*cleanCandidate
+	defaultBranches := make(map[string]string)
+
+	for _, ws := range reg.ListWorkspaces() {
+		if ws.Branch == "" {
+			continue
+		}
+		if _, err := os.Stat(ws.Path); err != nil {
+			// Missing paths are handled by 'grove prune --orphaned'
+			continue
+		}
+
+		mainRepo := ws.MainRepo
+		if mainRepo == "" {
+			info, err := worktree.DetectAt(ws.Path)
+			if err != nil {
+				continue
+			}
+			mainRepo = info.Path
+			if info.IsWorktree && info.MainWorktreePath != "" {
+				mainRepo = info.MainWorktreePath
+			}
+		}
+		if ws.Path == mainRepo {
+			continue
+		}
+
+		c := &cleanCandidate{
+			Name:         ws.Name,
+			Path:         ws.Path,
+			Branch:       ws.Branch,
+			MainRepo:     mainRepo,
+			LastActivity: lastActivity(ws),
+		}
+
+		if merged {
+			base, ok := defaultBranches[mainRepo]
+			if !ok {
+				base, _ = detectDefaultBranch(mainRepo)
+				defaultBranches[mainRepo] = base
+			}
+			if base != "" && ws.Branch != base && hasOwnCommits(mainRepo, ws.Branch, base) {
+				if ok, err := isBranchMerged(mainRepo, ws.Branch, base); err == nil && ok {
+					c.Reasons = append(c.Reasons, "merged into "+base)
+				}
+			}
+			if isUpstreamGone(mainRepo, ws.Branch) {
+				c.Reasons = append(c.Reasons, "upstream deleted")
+			}
+		}
+
+		if olderThan > 0 && !c.LastActivity.IsZero() && time.Since(c.LastActivity) > olderThan {
+			c.Reasons = append(c.Reasons, "idle "+formatAge(time.Since(c.LastActivity)))
+		}
+
+		// Only candidates are measured, unless size is a criterion:
+		// scanning every worktree is slow
+		if largerThan > 0 || len(c.Reasons) > 0 {
+			if u, err := diskusage.Scan(ws.Path); err == nil {
+				c.Size = u.Total
+			}
+		}
+		if largerThan > 0 && c.Size > largerThan {
+			c.Reasons = append(c.Reasons, "uses "+usage.FormatBytes(c.Size))
+		}
+
+		if len(c.Reasons) == 0 {
+			continue
+		}
+
+		c.Dirty = checkGitDirty(ws.Path)
+		candidates = append(candidates, c)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].LastActivity.Before(candidates[j].LastActivity)
+	})
+
+	return candidates
+}
+
+// printCleanCandidates prints the candidates as a table
+func printCleanCandidates(candidates []*cleanCandidate) {
+	rows := make([][]string, 0, len(candidates))
+	for _, c := range candidates {
+		activity := "-"
+		if !c.LastActivity.IsZero() {
+			activity = formatAge(time.Since(c.LastActivity)) + " ago"
+		}
+		gitStatus := "clean"
+		if c.Dirty {
+			gitStatus = "dirty"
+		}
+		size := "-"
+		if c.Size > 0 {
+			size = usage.FormatBytes(c.Size)
+		}
+		rows = append(rows, []string{c.Name, c.Branch, strings.Join(c.Reasons, ", "), activity, size, gitStatus})
+	}
+
+	t := table.New().
+		Border(lipgloss.NormalBorder()).
+		BorderRow(false).
+		BorderColumn(false).
+		BorderTop(false).
+		BorderBottom(false).
+		BorderLeft(false).
+		BorderRight(false).
+		Headers("NAME", "BRANCH", "REASON", "LAST ACTIVITY", "SIZE", "GIT").
+		Rows(rows...).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			if row == table.HeaderRow {
+				return styles.HeaderStyle
+			}
+			if col == 5 && rows[row][5] == "dirty" {
+				return styles.CellStyle.Foreground(styles.Warning)
+			}
+			return styles.CellStyle
+		})
+
+	fmt.Println(t)
+
+	var total uint64
+	for _, c := range candidates {
+		total += c.Size
+	}
+	if total > 0 {
+		fmt.Printf("\nDeleting them frees about %s\n", usage.FormatBytes(total))
+	}
+}
+
+// lastActivity returns the later of the registry's recorded activity and
+// the worktree's last commit time
+func lastActivity(ws *registry.Workspace) time.Time {
+	latest := ws.LastActivity
+	if ws.Server != nil && ws.Server.StoppedAt.After(latest) {
+		latest = ws.Server.StoppedAt
+	}
+
+	output, err := exec.Command("git", "-C", ws.Path, "log", "-1", "--format=%ct").Output()
+	if err == nil {
+		if ts, err := strconv.ParseInt(strings.TrimSpace(string(output)), 10, 64); err == nil {
+			if t := time.Unix(ts, 0); t.After(latest) {
+				latest = t
+			}
+		}
+	}
+
+	return latest
+}
+
+// hasOwnCommits returns true if commits were ever made on branch. Freshly
+// created branches are technically "merged" into base, but shouldn't be
+// cleaned; fast-forward merged branches point at base but have a reflog.
+func hasOwnCommits(repoPath, branch, base string) bool {
+	output, err := exec.Command("git", "-C", repoPath, "rev-parse", branch, base).Output()
+	if err != nil {
+		return false
+	}
+	if shas := strings.Fields(string(output)); len(shas) == 2 && shas[0] != shas[1] {
+		return true
+	}
+
+	output, err = exec.Command("git", "-C", repoPath, "reflog", "show", "--format=%H", "refs/heads/"+branch).Output()
+	if err != nil {
+		return false
+	}
+	return len(strings.Fields(string(output))) > 1
+}
+
+// isUpstreamGone returns true if the branch tracked a remote branch that has
+// since been deleted (as of the last fetch)
+func isUpstreamGone(repoPath, branch string) bool {
+	output, err := exec.Command("git", "-C", repoPath, "for-each-ref",
+		"--format=%(upstream:track)", "refs/heads/"+branch).Output()
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(string(output)) == "[gone]"
+}
+
+// parseAge parses a duration that also accepts days and weeks (e.g. 30d, 2w)
+func parseAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			v, err := strconv.Atoi(n)
+			if err != nil || v <= 0 {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			return time.Duration(v) * unit, nil
+		}
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid duration %q (use e.g. 30d, 2w, 48h)", s)
+	}
+	return d, nil
+}
+
+// formatAge formats a duration in the largest whole unit (e.g. 3d, 5h, 10m)
+func formatAge(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	case d < time.Minute:
+		return "<1m"
+	default:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+}
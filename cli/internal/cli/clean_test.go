@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"30d", 30 * 24 * time.Hour, false},
+		{"2w", 14 * 24 * time.Hour, false},
+		{"48h", 48 * time.Hour, false},
+		{"90m", 90 * time.Minute, false},
+		{"0d", 0, true},
+		{"-1h", 0, true},
+		{"xd", 0, true},
+		{"soon", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseAge(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseAge(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("parseAge(%q) = %v, want %v", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	tests := []struct {
+		input    time.Duration
+		expected string
+	}{
+		{45 * 24 * time.Hour, "45d"},
+		{5*time.Hour + 30*time.Minute, "5h"},
+		{12 * time.Minute, "12m"},
+		{30 * time.Second, "<1m"},
+	}
+
+	for _, tt := range tests {
+		if got := formatAge(tt.input); got != tt.expected {
+			t.Errorf("formatAge(%v) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
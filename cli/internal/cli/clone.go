@@ -1,12 +1,14 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 
+	"github.com/iheanyi/grove/internal/git"
 	"github.com/spf13/cobra"
 )
 
@@ -108,17 +110,8 @@ func runClone(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Step 3/3: Creating main worktree (%s)...\n", branch)
 	mainWorktree := filepath.Join(absDir, "main")
 
-	worktreeArgs := []string{"worktree", "add", mainWorktree, branch}
-	if noCheckout {
-		worktreeArgs = append(worktreeArgs, "--no-checkout")
-	}
-
-	gitWorktree := exec.Command("git", worktreeArgs...)
-	gitWorktree.Dir = bareDir
-	gitWorktree.Stdout = os.Stdout
-	gitWorktree.Stderr = os.Stderr
-
-	if err := gitWorktree.Run(); err != nil {
+	addOpts := git.AddOptions{Commit: branch, NoCheckout: noCheckout}
+	if err := gitOps().AddWorktree(context.Background(), bareDir, mainWorktree, addOpts); err != nil {
 		// Don't fail completely - the bare clone succeeded
 		fmt.Printf("Warning: failed to create main worktree: %v\n", err)
 		fmt.Println("You can create it manually with: git worktree add main <branch>")
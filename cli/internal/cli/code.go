@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/iheanyi/grove/internal/discovery"
+	"github.com/iheanyi/grove/internal/editor"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var codeCmd = &cobra.Command{
+	Use:     "code [name]",
+	Aliases: []string{"edit"},
+	Short:   "Open a worktree in your editor",
+	Long: `Open a worktree in an editor or IDE via its command-line launcher.
+
+The editor is chosen from --editor, then 'editor' in
+~/.config/grove/config.yaml, then the editor the worktree was last opened
+in, then the first of these found in PATH:
+
+  vscode (code), cursor, zed, and the JetBrains IDEs: idea, goland,
+  webstorm, pycharm, rubymine, phpstorm, rustrover, clion, rider
+
+Any other value is run as a launcher with the worktree path. The editor is
+recorded for the worktree, so 'grove ls' shows it as open like VS Code.
+
+Examples:
+  grove code                      # Open the current worktree
+  grove code feature-auth         # Open a named worktree
+  grove code feature-auth -e zed  # Open in a specific editor`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCode,
+}
+
+func init() {
+	codeCmd.Flags().StringP("editor", "e", "", "Editor to open the worktree in")
+}
+
+func runCode(cmd *cobra.Command, args []string) error {
+	editorFlag, _ := cmd.Flags().GetString("editor")
+
+	var name, path string
+	if len(args) > 0 {
+		name = args[0]
+		p, err := resolveWorktreePath(name)
+		if err != nil {
+			return err
+		}
+		path = p
+	} else {
+		wt, err := worktree.Detect()
+		if err != nil {
+			return fmt.Errorf("failed to detect worktree: %w", err)
+		}
+		name, path = wt.Name, wt.Path
+	}
+
+	reg, err := registry.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+
+	var recorded string
+	if ws, ok := reg.GetWorkspace(name); ok {
+		recorded = ws.Editor
+	}
+
+	ed, err := editor.Resolve(editorFlag, cfg.Editor, recorded)
+	if err != nil {
+		return err
+	}
+	if err := ed.Open(path); err != nil {
+		return err
+	}
+	fmt.Printf("Opened %s in %s\n", name, ed.Name)
+
+	if err := recordEditor(reg, name, path, ed.Name); err != nil {
+		fmt.Printf("Warning: failed to record editor: %v\n", err)
+	}
+	return nil
+}
+
+// recordEditor records the editor a worktree was opened in, registering the
+// worktree first if discovery hasn't seen it
+func recordEditor(reg *registry.Registry, name, path, editorName string) error {
+	if err := ensureWorkspace(reg, name, path); err != nil {
+		return err
+	}
+	return reg.RecordEditor(name, editorName)
+}
+
+// ensureWorkspace registers a worktree that discovery hasn't seen, so
+// state can be recorded on it
+func ensureWorkspace(reg *registry.Registry, name, path string) error {
+	if _, ok := reg.GetWorkspace(name); ok {
+		return nil
+	}
+	now := time.Now()
+	wtEntry := &discovery.Worktree{
+		Name:         name,
+		Path:         path,
+		DiscoveredAt: now,
+		LastActivity: now,
+	}
+	if wt, err := worktree.DetectAt(path); err == nil {
+		wtEntry.Branch = wt.Branch
+		wtEntry.MainRepo = wt.MainWorktreePath
+	}
+	return reg.SetWorktree(wtEntry)
+}
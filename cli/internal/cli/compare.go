@@ -0,0 +1,235 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/pkg/browser"
+	"github.com/spf13/cobra"
+)
+
+var compareCmd = &cobra.Command{
+	Use:   "compare <a> <b>",
+	Short: "Open two worktrees' servers side by side",
+	Long: `Start both worktrees' servers if they aren't running, then open a page with
+the two side by side, for reviewing a branch against main by eye.
+
+The page is a local HTML file with each server in a frame, its branch and
+uncommitted changes above it, and the diff between the two worktrees'
+commits. A path bar navigates both frames at once. Apps that forbid being
+framed (X-Frame-Options or a frame-ancestors policy) show a blank frame;
+use the links above it instead.
+
+Examples:
+  grove compare main feature-auth
+  grove compare main feature-auth --path /settings
+  grove compare main feature-auth --print   # Write the page, don't open it`,
+	Args: cobra.ExactArgs(2),
+	RunE: runCompare,
+}
+
+func init() {
+	compareCmd.Flags().String("path", "/", "Path to open on both servers")
+	compareCmd.Flags().Bool("no-start", false, "Fail instead of starting servers that aren't running")
+	compareCmd.Flags().BoolP("print", "p", false, "Print the page's path instead of opening it")
+}
+
+// compareSide is one worktree on the compare page
+type compareSide struct {
+	Name   string
+	Branch string
+	URL    string
+	// Src is the URL the frame opens first
+	Src string
+	// Uncommitted changes
+	Files, Added, Removed int
+}
+
+// comparePage is the data of the compare page
+type comparePage struct {
+	A, B compareSide
+	Path string
+	// Diff is the change from A's commit to B's, when they share a
+	// repository
+	Diff                              bool
+	DiffFiles, DiffAdded, DiffRemoved int
+	Generated                         string
+}
+
+func runCompare(cmd *cobra.Command, args []string) error {
+	path, _ := cmd.Flags().GetString("path")
+	noStart, _ := cmd.Flags().GetBool("no-start")
+	printOnly, _ := cmd.Flags().GetBool("print")
+	if args[0] == args[1] {
+		return fmt.Errorf("compare two different worktrees")
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	servers := make([]*registry.Server, 2)
+	for i, name := range args {
+		server, err := ensureRunning(name, noStart)
+		if err != nil {
+			return err
+		}
+		servers[i] = server
+	}
+
+	page := comparePage{
+		A:         compareSideOf(servers[0], path),
+		B:         compareSideOf(servers[1], path),
+		Path:      path,
+		Generated: time.Now().Format("2006-01-02 15:04"),
+	}
+	if added, removed, files, ok := diffBetween(servers[0].Path, servers[1].Path); ok {
+		page.Diff = true
+		page.DiffAdded, page.DiffRemoved, page.DiffFiles = added, removed, files
+	}
+
+	file := filepath.Join(os.TempDir(), fmt.Sprintf("grove-compare-%s-%s.html", args[0], args[1]))
+	f, err := os.Create(file)
+	if err != nil {
+		return fmt.Errorf("failed to write compare page: %w", err)
+	}
+	if err := compareTemplate.Execute(f, page); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write compare page: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write compare page: %w", err)
+	}
+
+	if printOnly {
+		fmt.Println(file)
+		return nil
+	}
+	fmt.Printf("Opening %s vs %s...\n", args[0], args[1])
+	return browser.Open("file://" + file)
+}
+
+// ensureRunning returns a registered server, starting it first if it isn't
+// running (unless noStart)
+func ensureRunning(name string, noStart bool) (*registry.Server, error) {
+	reg, err := registry.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load registry: %w", err)
+	}
+	server, ok := reg.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("no server registered for '%s'\nUse 'grove start' in its worktree first", name)
+	}
+	if server.IsRunning() {
+		return server, nil
+	}
+	if noStart {
+		return nil, fmt.Errorf("server '%s' is not running\nUse 'grove start' to start it", name)
+	}
+
+	port, err := plannedPort(server, reg.GetUsedPorts())
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("Starting %s...\n", name)
+	if err := startRegistered(server, port, nil); err != nil {
+		return nil, fmt.Errorf("failed to start '%s': %w", name, err)
+	}
+
+	// The start updated the registry with its URL
+	if reg, err = registry.Load(); err != nil {
+		return nil, fmt.Errorf("failed to load registry: %w", err)
+	}
+	if server, ok = reg.Get(name); !ok || !server.IsRunning() {
+		return nil, fmt.Errorf("server '%s' didn't start; check 'grove logs %s'", name, name)
+	}
+	return server, nil
+}
+
+func compareSideOf(server *registry.Server, path string) compareSide {
+	added, removed, files := getGitDiffStats(context.Background(), server.Path)
+	url := strings.TrimSuffix(server.URL, "/")
+	return compareSide{
+		Name:    server.Name,
+		Branch:  server.Branch,
+		URL:     url,
+		Src:     url + path,
+		Files:   files,
+		Added:   added,
+		Removed: removed,
+	}
+}
+
+// diffBetween returns the diff stats from the commit checked out at aPath to
+// the one at bPath. Worktrees of one repository share its objects; for
+// others, ok is false.
+func diffBetween(aPath, bPath string) (added, removed, files int, ok bool) {
+	ctx := context.Background()
+	g := gitOps()
+	head, err := g.Head(ctx, aPath)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	stat, err := g.DiffStat(ctx, bPath, head, "HEAD")
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	return stat.Added, stat.Removed, stat.Files, true
+}
+
+var compareTemplate = template.Must(template.New("compare").Parse(`<!doctype html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.A.Name}} vs {{.B.Name}} · grove</title>
+<style>
+body { font-family: -apple-system, system-ui, sans-serif; background: #0f172a; color: #e2e8f0; margin: 0; display: flex; flex-direction: column; height: 100vh; }
+header { display: flex; align-items: center; gap: 1rem; padding: 0.5rem 1rem; border-bottom: 1px solid #1e293b; }
+header form { display: flex; gap: 0.5rem; flex: 1; }
+input { flex: 1; background: #1e293b; color: inherit; border: 1px solid #334155; border-radius: 0.25rem; padding: 0.25rem 0.5rem; font-family: ui-monospace, monospace; }
+button { background: #334155; color: inherit; border: 0; border-radius: 0.25rem; padding: 0.25rem 0.75rem; cursor: pointer; }
+main { display: flex; flex: 1; min-height: 0; }
+section { flex: 1; display: flex; flex-direction: column; min-width: 0; }
+section + section { border-left: 1px solid #1e293b; }
+.info { padding: 0.5rem 1rem; font-size: 0.85rem; }
+.info a { color: #7dd3fc; }
+.muted { color: #94a3b8; }
+.add { color: #4ade80; }
+.del { color: #f87171; }
+iframe { flex: 1; border: 0; background: #fff; }
+</style>
+</head>
+<body>
+<header>
+<strong>{{.A.Name}} vs {{.B.Name}}</strong>
+<span class="muted">{{if .Diff}}{{.DiffFiles}} files changed, <span class="add">+{{.DiffAdded}}</span> <span class="del">-{{.DiffRemoved}}</span> between their commits{{else}}different repositories{{end}}</span>
+<form id="nav"><input id="path" value="{{.Path}}" aria-label="Path"><button>Go</button></form>
+<span class="muted">{{.Generated}}</span>
+</header>
+<main>
+{{template "side" .A}}
+{{template "side" .B}}
+</main>
+<script>
+document.getElementById("nav").addEventListener("submit", function (e) {
+  e.preventDefault();
+  var path = document.getElementById("path").value;
+  if (path.charAt(0) !== "/") path = "/" + path;
+  document.querySelectorAll("iframe").forEach(function (frame) { frame.src = frame.dataset.base + path; });
+});
+</script>
+</body>
+</html>
+{{define "side"}}<section>
+<div class="info">
+<strong>{{.Name}}</strong>{{if .Branch}} <span class="muted">on {{.Branch}}</span>{{end}} · <a href="{{.URL}}" target="_blank">{{.URL}}</a><br>
+<span class="muted">{{if .Files}}{{.Files}} uncommitted files, <span class="add">+{{.Added}}</span> <span class="del">-{{.Removed}}</span>{{else}}no uncommitted changes{{end}}</span>
+</div>
+<iframe data-base="{{.URL}}" src="{{.Src}}"></iframe>
+</section>{{end}}
+`))
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiffBetween(t *testing.T) {
+	dir := t.TempDir()
+	main := filepath.Join(dir, "main")
+	if err := os.Mkdir(main, 0755); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, main, "init", "-q", "-b", "main")
+	if err := os.WriteFile(filepath.Join(main, "a.txt"), []byte("one\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, main, "add", ".")
+	gitRun(t, main, "commit", "-q", "-m", "init")
+
+	feature := filepath.Join(dir, "feature")
+	gitRun(t, main, "worktree", "add", "-q", "-b", "feature", feature)
+	if err := os.WriteFile(filepath.Join(feature, "a.txt"), []byte("two\nthree\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, feature, "commit", "-q", "-am", "change")
+
+	added, removed, files, ok := diffBetween(main, feature)
+	if !ok || files != 1 || added != 2 || removed != 1 {
+		t.Errorf("diffBetween = +%d -%d in %d files, %v; want +2 -1 in 1 file", added, removed, files, ok)
+	}
+
+	if _, _, _, ok := diffBetween(main, t.TempDir()); ok {
+		t.Error("diffBetween should fail outside the repository")
+	}
+}
+
+func TestCompareTemplate(t *testing.T) {
+	page := comparePage{
+		A:    compareSide{Name: "main", URL: "https://main.localhost", Src: "https://main.localhost/settings"},
+		B:    compareSide{Name: "feature<x>", Branch: "feature/x", URL: "https://feature.localhost", Src: "https://feature.localhost/settings", Files: 2, Added: 10, Removed: 3},
+		Path: "/settings",
+		Diff: true, DiffFiles: 4, DiffAdded: 40, DiffRemoved: 12,
+	}
+	var sb strings.Builder
+	if err := compareTemplate.Execute(&sb, page); err != nil {
+		t.Fatal(err)
+	}
+	got := sb.String()
+	for _, want := range []string{
+		`<iframe data-base="https://main.localhost" src="https://main.localhost/settings">`,
+		"feature&lt;x&gt;",
+		"2 uncommitted files",
+		"4 files changed",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("compare page is missing %q:\n%s", want, got)
+		}
+	}
+}
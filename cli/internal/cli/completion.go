@@ -2,8 +2,14 @@ package cli
 
 import (
 	"os"
+	"sort"
 
+	"github.com/iheanyi/grove/internal/archive"
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/editor"
+	"github.com/iheanyi/grove/internal/project"
 	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/trash"
 	"github.com/spf13/cobra"
 )
 
@@ -123,6 +129,109 @@ func setupDynamicCompletions() {
 		return getAllServerNames(), cobra.ShellCompDirectiveNoFileComp
 	}
 
+	// For 'grove search <pattern> [server...]' - complete with all server names
+	searchCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return getAllServerNames(), cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// For 'grove crashes <name>' - complete with all server names
+	crashesCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return getAllServerNames(), cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// For 'grove tasks <name>' - complete with all server names
+	tasksCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return getAllServerNames(), cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// For 'grove ci <name>' - complete with worktree names
+	ciCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return getWorktreeNames(), cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// For 'grove du <name>' - complete with worktree names
+	duCmd.ValidArgsFunction = ciCmd.ValidArgsFunction
+
+	// For 'grove archive <name>' - complete with all server names
+	archiveCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return getAllServerNames(), cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// For 'grove move-changes <from> <to>' - complete with all server names
+	moveChangesCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) >= 2 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return getAllServerNames(), cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// For 'grove rename <old> <new>' - complete the old name
+	renameCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return getWorktreeNames(), cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// For 'grove diff-env <from> <to>' - complete with all server names
+	diffEnvCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) >= 2 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return getAllServerNames(), cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// For 'grove restore <name>' - complete with archived worktrees
+	restoreCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		store, err := archive.LoadStore(config.ArchivesPath())
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		var names []string
+		for _, a := range store.List() {
+			names = append(names, a.Name)
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// For 'grove trash restore <id|name>' - complete with trash entries
+	trashRestoreCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		entries, err := trash.List(config.TrashDir())
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		var ids []string
+		for _, e := range entries {
+			ids = append(ids, e.ID)
+		}
+		return ids, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// For 'grove context use|delete <name>' - complete with context names
+	contextUseCmd.ValidArgsFunction = completeContexts
+	contextDeleteCmd.ValidArgsFunction = completeContexts
+
 	// For 'grove restart <name>' - complete with server names
 	restartCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) != 0 {
@@ -147,6 +256,60 @@ func setupDynamicCompletions() {
 		return getRunningServerNames(), cobra.ShellCompDirectiveNoFileComp
 	}
 
+	// For 'grove share <name>' - complete with running server names
+	shareCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return getRunningServerNames(), cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// For 'grove bench <name>' and 'grove bench --compare <a> <b>' - complete
+	// with running server names
+	benchCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) >= 2 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return getRunningServerNames(), cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// For 'grove compare <a> <b>' - complete with all server names
+	compareCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) >= 2 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return getAllServerNames(), cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// For 'grove vrt <a> <b>' - complete with all server names
+	vrtCmd.ValidArgsFunction = compareCmd.ValidArgsFunction
+
+	// For 'grove lan <name>' - complete with running server names
+	lanCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return getRunningServerNames(), cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// For 'grove share stop <name>' - complete with shared server names
+	shareStopCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		reg, err := registry.Load()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		var names []string
+		for _, ws := range reg.ListWorkspaces() {
+			if ws.Share != nil {
+				names = append(names, ws.Name)
+			}
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+
 	// For 'grove switch <name>' - complete with worktree names
 	switchCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		if len(args) != 0 {
@@ -154,6 +317,39 @@ func setupDynamicCompletions() {
 		}
 		return getWorktreeNames(), cobra.ShellCompDirectiveNoFileComp
 	}
+
+	// For 'grove init <template>' and 'grove templates show <name>' - complete
+	// with template names
+	completeTemplate := func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return getTemplateNames(), cobra.ShellCompDirectiveNoFileComp
+	}
+	initCmd.ValidArgsFunction = completeTemplate
+	templatesShowCmd.ValidArgsFunction = completeTemplate
+
+	// For 'grove code <name>' - complete with worktree names, and --editor
+	// with known editors
+	codeCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return getWorktreeNames(), cobra.ShellCompDirectiveNoFileComp
+	}
+	_ = codeCmd.RegisterFlagCompletionFunc("editor", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return getEditorNames(), cobra.ShellCompDirectiveNoFileComp
+	})
+
+	// For 'grove group start|stop|status <group>' - complete with group names
+	for _, c := range []*cobra.Command{groupStartCmd, groupStopCmd, groupStatusCmd} {
+		c.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) != 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return getGroupNames(), cobra.ShellCompDirectiveNoFileComp
+		}
+	}
 }
 
 // getRunningServerNames returns a list of running server names for completion
@@ -200,3 +396,41 @@ func getWorktreeNames() []string {
 	}
 	return names
 }
+
+// getTemplateNames returns built-in, user and team template names with
+// their descriptions for completion
+func getTemplateNames() []string {
+	templates, err := project.ListTemplates(templateDirs()...)
+	if err != nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(templates))
+	for _, t := range templates {
+		names = append(names, t.Name+"\t"+t.Description)
+	}
+	return names
+}
+
+// getEditorNames returns known editor names for completion
+func getEditorNames() []string {
+	editors := editor.Known()
+	names := make([]string, 0, len(editors))
+	for _, e := range editors {
+		names = append(names, e.Name)
+	}
+	return names
+}
+
+// getGroupNames returns the configured group names for completion
+func getGroupNames() []string {
+	if cfg == nil {
+		return nil
+	}
+	names := make([]string, 0, len(cfg.Groups))
+	for name := range cfg.Groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
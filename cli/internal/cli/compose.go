@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/iheanyi/grove/internal/compose"
+	"github.com/iheanyi/grove/internal/events"
+	"github.com/iheanyi/grove/internal/project"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/pkg/browser"
+)
+
+// composeProject returns the compose project for a server. Each worktree
+// gets its own project so their containers don't collide.
+func composeProject(server *registry.Server, projConfig *project.Config) *compose.Project {
+	p := &compose.Project{
+		Name: compose.ProjectName(server.Name),
+		Dir:  server.Path,
+	}
+	if projConfig != nil {
+		p.Files = projConfig.Compose.Files
+	}
+	return p
+}
+
+// runCompose starts a compose-backed server with 'docker compose up -d'.
+// PORT, GROVE_URL, the project env and env_files are available for interpolation in
+// the compose file; the server's port is then read back from the published
+// ports. A 'docker compose logs -f' follower writes to the server's log file
+// and its PID is recorded, so the server looks like any other to grove.
+func runCompose(server *registry.Server, reg *registry.Registry, projConfig *project.Config, foreground, openBrowser bool) error {
+	proj := composeProject(server, projConfig)
+	proj.Env = append(proj.Env, serverEnv(server, projConfig, server.Port)...)
+
+	server.Backend = registry.BackendCompose
+	server.Command = append([]string{"docker"}, proj.Args("up")...)
+
+	up := proj.Command("up", "-d")
+	up.Stdout = os.Stdout
+	up.Stderr = os.Stderr
+	if err := up.Run(); err != nil {
+		return fmt.Errorf("docker compose up failed: %w", err)
+	}
+
+	// The compose file may publish a fixed port rather than $PORT
+	if containers, err := proj.Ps(); err == nil {
+		if published := compose.PublishedPort(containers, projConfig.Compose.Service); published > 0 && published != server.Port {
+			server.Port = published
+			server.URL = cfg.ServerURL(server.Name, published)
+		}
+	} else {
+		fmt.Printf("Warning: could not read published ports: %v\n", err)
+	}
+
+	logFile, err := os.OpenFile(server.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	defer logFile.Close()
+
+	follower := proj.Command("logs", "-f", "--no-color", "--since", "0s")
+	follower.Stdout = logFile
+	follower.Stderr = logFile
+	follower.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := follower.Start(); err != nil {
+		return fmt.Errorf("failed to follow compose logs: %w", err)
+	}
+
+	server.PID = follower.Process.Pid
+	server.Status = registry.StatusRunning
+	if err := reg.Set(server); err != nil {
+		return fmt.Errorf("failed to save to registry: %w", err)
+	}
+	registerWorktree(reg, server)
+
+	if !foreground {
+		if err := follower.Process.Release(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to release process: %v\n", err)
+		}
+	}
+
+	if cfg.UsesProxy() {
+		if err := ReloadProxy(); err != nil {
+			fmt.Printf("Warning: failed to reload proxy: %v\n", err)
+			fmt.Println("Run 'grove proxy stop && grove proxy start' to update routes manually")
+		}
+	}
+
+	fmt.Printf("Server running at: %s\n", server.URL)
+	fmt.Printf("Compose project: %s\n", proj.Name)
+	fmt.Printf("Logs: %s\n", server.LogFile)
+
+	events.Publish(server.Event(events.ServerStarted))
+
+	if len(projConfig.Hooks.AfterStart) > 0 {
+		fmt.Println("Running after_start hooks...")
+		for _, hook := range projConfig.Hooks.AfterStart {
+			if err := runHook(hook, server.Path); err != nil {
+				fmt.Printf("Warning: after_start hook failed: %v\n", err)
+			}
+		}
+	}
+	runPostStartHooks(server)
+
+	if openBrowser {
+		fmt.Printf("Opening %s in browser...\n", server.URL)
+		if err := browser.Open(server.URL); err != nil {
+			fmt.Printf("Warning: failed to open browser: %v\n", err)
+		}
+	}
+
+	if !foreground {
+		return nil
+	}
+
+	// In the foreground, stream logs until interrupted, then stop the project
+	fmt.Println("Press Ctrl+C to stop...")
+	attached := proj.Command("logs", "-f", "--since", "0s")
+	attached.Stdout = os.Stdout
+	attached.Stderr = os.Stderr
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	done := make(chan error, 1)
+	go func() { done <- attached.Run() }()
+
+	select {
+	case <-sigChan:
+		fmt.Println("\nStopping server...")
+		return stopServer(reg, server.Name, 10*time.Second)
+	case <-done:
+		// All containers exited; the registry catches up via the follower
+		return nil
+	}
+}
+
+// stopComposeServer stops a compose-backed server's containers and its log
+// follower
+func stopComposeServer(reg *registry.Registry, server *registry.Server, projConfig *project.Config) error {
+	if err := composeProject(server, projConfig).Stop(); err != nil {
+		return err
+	}
+
+	// The follower exits once the containers stop; make sure of it
+	if server.PID > 0 {
+		_ = syscall.Kill(-server.PID, syscall.SIGTERM)
+	}
+
+	server.Status = registry.StatusStopped
+	server.PID = 0
+	server.StoppedAt = time.Now()
+	if err := reg.Set(server); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to update registry: %v\n", err)
+	}
+	events.Publish(server.Event(events.ServerStopped))
+	return nil
+}
+
+// signalCompose maps pause/resume signals to 'docker compose pause/unpause'
+func signalCompose(server *registry.Server, sig syscall.Signal) error {
+	projConfig, _ := project.Load(server.Path)
+	proj := composeProject(server, projConfig)
+
+	switch sig {
+	case syscall.SIGSTOP:
+		return proj.Pause()
+	case syscall.SIGCONT:
+		return proj.Unpause()
+	}
+	return fmt.Errorf("signal %v is not supported for compose servers", sig)
+}
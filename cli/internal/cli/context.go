@@ -0,0 +1,298 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/output"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var contextCmd = &cobra.Command{
+	Use:   "context",
+	Short: "Switch between named registries, each with its own config and ports",
+	Long: `Contexts keep separate sets of worktrees apart, e.g. work and personal
+projects: each has its own registry, config.yaml, logs and port range. The
+default context is ~/.config/grove itself; others live in
+~/.config/grove/contexts/<name>.
+
+'grove context use' switches the context later commands use. --context (or
+GROVE_CONTEXT) picks one for a single command. Servers keep running when
+you switch; the processes grove starts stay in the context they were
+started in. GROVE_HOME overrides contexts entirely.
+
+Examples:
+  grove context                       # List contexts, marking the current one
+  grove context create work           # New context with the next free port range
+  grove context create oss --port-min 6000 --port-max 6499
+  grove context use work
+  grove --context personal ls         # One command in another context
+  grove context delete oss`,
+	Args: cobra.NoArgs,
+	RunE: runContextList,
+}
+
+var contextListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List contexts",
+	Args:  cobra.NoArgs,
+	RunE:  runContextList,
+}
+
+var contextCurrentCmd = &cobra.Command{
+	Use:   "current",
+	Short: "Print the current context",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkContextsApply(); err != nil {
+			return err
+		}
+		fmt.Println(config.CurrentContext())
+		return nil
+	},
+}
+
+var contextCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a context",
+	Long: `Create a context with its own registry, logs and config.yaml. The config
+starts as a copy of the default context's, with a port range of its own:
+the next one after every other context's, unless --port-min and
+--port-max are given.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runContextCreate,
+}
+
+var contextUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Switch to a context",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runContextUse,
+}
+
+var contextDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a context and its registry, config and logs",
+	Long: `Delete a context's directory: its registry, config.yaml and logs. The
+worktrees themselves are left alone. The current context can't be deleted,
+nor one with servers running.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runContextDelete,
+}
+
+func init() {
+	contextCreateCmd.Flags().Int("port-min", 0, "First port of the context's range (default: after the other contexts')")
+	contextCreateCmd.Flags().Int("port-max", 0, "Last port of the context's range")
+	contextCreateCmd.Flags().Bool("use", false, "Switch to the context once created")
+	contextDeleteCmd.Flags().BoolP("force", "f", false, "Don't ask for confirmation")
+
+	contextCmd.AddCommand(contextListCmd)
+	contextCmd.AddCommand(contextCurrentCmd)
+	contextCmd.AddCommand(contextCreateCmd)
+	contextCmd.AddCommand(contextUseCmd)
+	contextCmd.AddCommand(contextDeleteCmd)
+	addOutputFlags(contextCmd)
+	addOutputFlags(contextListCmd)
+}
+
+// completeContexts completes context names
+func completeContexts(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names, _ := config.Contexts()
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// checkContextsApply fails when GROVE_HOME makes contexts moot
+func checkContextsApply() error {
+	if os.Getenv(config.HomeEnv) != "" {
+		return fmt.Errorf("contexts don't apply while GROVE_HOME (or --state-dir) is set")
+	}
+	return nil
+}
+
+// contextPorts returns a context's port range from its config
+func contextPorts(name string) (int, int) {
+	c, err := config.Load(filepath.Join(config.ContextDir(name), "config.yaml"))
+	if err != nil {
+		c = config.Default()
+	}
+	return c.PortMin, c.PortMax
+}
+
+func runContextList(cmd *cobra.Command, args []string) error {
+	if err := checkContextsApply(); err != nil {
+		return err
+	}
+	return runWithOutput(cmd, func() (any, error) {
+		names, err := config.Contexts()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list contexts: %w", err)
+		}
+
+		current := config.CurrentContext()
+		result := output.ContextList{Current: current}
+		for _, name := range names {
+			c := output.Context{Name: name, Dir: config.ContextDir(name), Current: name == current}
+			c.PortMin, c.PortMax = contextPorts(name)
+			result.Contexts = append(result.Contexts, c)
+
+			marker := " "
+			if c.Current {
+				marker = "*"
+			}
+			fmt.Printf("%s %-16s ports %d-%d  %s\n", marker, name, c.PortMin, c.PortMax, c.Dir)
+		}
+		return result, nil
+	})
+}
+
+func runContextCreate(cmd *cobra.Command, args []string) error {
+	if err := checkContextsApply(); err != nil {
+		return err
+	}
+	name := args[0]
+	if err := config.ValidateContextName(name); err != nil {
+		return err
+	}
+	if config.ContextExists(name) {
+		return fmt.Errorf("context '%s' already exists", name)
+	}
+
+	portMin, _ := cmd.Flags().GetInt("port-min")
+	portMax, _ := cmd.Flags().GetInt("port-max")
+	if portMin == 0 || portMax == 0 {
+		var err error
+		if portMin, portMax, err = nextPortRange(portMin, portMax); err != nil {
+			return err
+		}
+	}
+	if portMin < 1024 || portMax > 65535 || portMin > portMax {
+		return fmt.Errorf("invalid port range %d-%d", portMin, portMax)
+	}
+
+	dir := config.ContextDir(name)
+	if err := os.MkdirAll(filepath.Join(dir, "logs"), 0755); err != nil {
+		return fmt.Errorf("failed to create context: %w", err)
+	}
+	if err := writeContextConfig(filepath.Join(dir, "config.yaml"), portMin, portMax); err != nil {
+		os.RemoveAll(dir)
+		return fmt.Errorf("failed to create context: %w", err)
+	}
+	fmt.Printf("Created context '%s' (ports %d-%d) in %s\n", name, portMin, portMax, dir)
+
+	if use, _ := cmd.Flags().GetBool("use"); use {
+		return runContextUse(cmd, args)
+	}
+	fmt.Printf("Switch to it with: grove context use %s\n", name)
+	return nil
+}
+
+// nextPortRange returns a port range starting after every context's, as
+// wide as the default context's, for the bounds not given
+func nextPortRange(portMin, portMax int) (int, int, error) {
+	names, err := config.Contexts()
+	if err != nil {
+		return 0, 0, err
+	}
+	defaultMin, defaultMax := contextPorts(config.DefaultContext)
+	highest := 0
+	for _, name := range names {
+		_, last := contextPorts(name)
+		highest = max(highest, last)
+	}
+
+	width := defaultMax - defaultMin
+	if portMin == 0 && portMax == 0 {
+		portMin = highest + 1
+	}
+	if portMin == 0 {
+		portMin = portMax - width
+	}
+	if portMax == 0 {
+		portMax = portMin + width
+	}
+	if portMax > 65535 {
+		return 0, 0, fmt.Errorf("no free port range left after %d; pass --port-min and --port-max", highest)
+	}
+	return portMin, portMax, nil
+}
+
+// writeContextConfig writes a new context's config.yaml: the default
+// context's settings with the context's port range. log_dir is dropped so
+// the context keeps its logs in its own directory.
+func writeContextConfig(path string, portMin, portMax int) error {
+	settings := make(map[string]any)
+	data, err := os.ReadFile(filepath.Join(config.ContextDir(config.DefaultContext), "config.yaml"))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return fmt.Errorf("invalid default config: %w", err)
+	}
+	if settings == nil {
+		settings = make(map[string]any)
+	}
+	delete(settings, "log_dir")
+	settings["port_min"] = portMin
+	settings["port_max"] = portMax
+
+	out, err := yaml.Marshal(settings)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, out, 0644)
+}
+
+func runContextUse(cmd *cobra.Command, args []string) error {
+	if err := checkContextsApply(); err != nil {
+		return err
+	}
+	if err := config.UseContext(args[0]); err != nil {
+		return fmt.Errorf("%w (see 'grove context list')", err)
+	}
+	fmt.Printf("Switched to context '%s'\n", args[0])
+	return nil
+}
+
+func runContextDelete(cmd *cobra.Command, args []string) error {
+	if err := checkContextsApply(); err != nil {
+		return err
+	}
+	name := args[0]
+	force, _ := cmd.Flags().GetBool("force")
+
+	if name == config.DefaultContext {
+		return fmt.Errorf("the default context can't be deleted")
+	}
+	if !config.ContextExists(name) {
+		return fmt.Errorf("no context named '%s'", name)
+	}
+	if name == config.CurrentContext() {
+		return fmt.Errorf("'%s' is the current context; switch to another one first", name)
+	}
+
+	dir := config.ContextDir(name)
+	if reg, err := registry.LoadFrom(filepath.Join(dir, "registry.json")); err == nil {
+		if running := reg.ListRunning(); len(running) > 0 {
+			return fmt.Errorf("%d server(s) are running in '%s'; stop them first with 'grove --context %s stop --all'", len(running), name, name)
+		}
+	}
+
+	if !force && !confirm(fmt.Sprintf("Delete context '%s' and its registry, config and logs in %s?", name, dir)) {
+		fmt.Println("Cancelled")
+		return nil
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to delete context: %w", err)
+	}
+	fmt.Printf("Deleted context '%s'\n", name)
+	return nil
+}
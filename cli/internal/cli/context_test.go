@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iheanyi/grove/internal/config"
+)
+
+func TestContextCreate(t *testing.T) {
+	useTestEnv(t)
+	if err := os.MkdirAll(config.ConfigDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	defaults := "tld: test\nlog_dir: /var/log/grove\nport_min: 3000\nport_max: 3499\n"
+	if err := os.WriteFile(config.ConfigPath(), []byte(defaults), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"work", "personal"} {
+		cmd := contextCreateCmd
+		t.Cleanup(func() { cmd.Flags().Set("use", "false") }) //nolint:errcheck
+		if err := cmd.Flags().Set("use", "true"); err != nil {
+			t.Fatal(err)
+		}
+		if err := runContextCreate(cmd, []string{name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := config.CurrentContext(); got != "personal" {
+		t.Errorf("current context = %s, want personal", got)
+	}
+
+	// Each context gets the next port range, as wide as the default's, and
+	// keeps the default's settings but not its log_dir
+	work, err := config.Load(filepath.Join(config.ContextDir("work"), "config.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if work.PortMin != 3500 || work.PortMax != 3999 || work.TLD != "test" {
+		t.Errorf("work = ports %d-%d, tld %s; want 3500-3999, test", work.PortMin, work.PortMax, work.TLD)
+	}
+	if strings.HasPrefix(work.LogDir, "/var/log") {
+		t.Errorf("work logs to %s, the default context's log_dir", work.LogDir)
+	}
+	if lo, hi := contextPorts("personal"); lo != 4000 || hi != 4499 {
+		t.Errorf("personal = ports %d-%d, want 4000-4499", lo, hi)
+	}
+
+	if err := runContextCreate(contextCreateCmd, []string{"work"}); err == nil {
+		t.Error("created work twice")
+	}
+	if err := runContextDelete(contextDeleteCmd, []string{"personal"}); err == nil {
+		t.Error("deleted the current context")
+	}
+}
@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/crash"
+	"github.com/iheanyi/grove/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var crashesCmd = &cobra.Command{
+	Use:   "crashes [name]",
+	Short: "List and inspect server crash reports",
+	Long: `List and inspect the reports grove saves when a server crashes.
+
+Each report has the exit code or signal, how long the server ran and its
+last 200 log lines, with the lines that matched a fatal log pattern (e.g.
+EADDRINUSE) first. Servers started in the background are only found dead
+later, so their reports have the log but no exit code.
+
+Examples:
+  grove crashes                 # List recent crashes of all servers
+  grove crashes feature-auth    # Show the latest crash of a server
+  grove crashes feature-auth -n 20
+  grove crashes feature-auth --list
+  grove crashes --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCrashes,
+}
+
+func init() {
+	crashesCmd.Flags().Bool("list", false, "List a server's crashes instead of showing the latest")
+	crashesCmd.Flags().IntP("lines", "n", 50, "Log lines to show from the report (0 for all)")
+	addOutputFlags(crashesCmd)
+}
+
+func runCrashes(cmd *cobra.Command, args []string) error {
+	listOnly, _ := cmd.Flags().GetBool("list")
+	lines, _ := cmd.Flags().GetInt("lines")
+
+	var name string
+	if len(args) > 0 {
+		name = args[0]
+	}
+
+	reports, err := crash.List(config.CrashesDir(), name)
+	if err != nil {
+		return fmt.Errorf("failed to read crash reports: %w", err)
+	}
+
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	if format.IsMachine() {
+		result := output.CrashList{Crashes: []crash.Report{}}
+		for _, r := range reports {
+			result.Crashes = append(result.Crashes, *r)
+		}
+		return output.Write(os.Stdout, format, result)
+	}
+
+	if len(reports) == 0 {
+		if name != "" {
+			fmt.Printf("No crashes recorded for '%s'\n", name)
+		} else {
+			fmt.Println("No crashes recorded")
+		}
+		return nil
+	}
+
+	if name == "" || listOnly {
+		for _, r := range reports {
+			fmt.Printf("%-10s %-24s %s\n", formatDuration(time.Since(r.Time))+" ago", r.Server, r.Summary())
+		}
+		return nil
+	}
+
+	printCrashReport(reports[0], lines)
+	if len(reports) > 1 {
+		fmt.Printf("\n%d earlier crashes (grove crashes %s --list)\n", len(reports)-1, name)
+	}
+	return nil
+}
+
+// printCrashReport prints a report with its last lines of log
+func printCrashReport(r *crash.Report, lines int) {
+	fmt.Printf("Server:      %s\n", r.Server)
+	if r.Process != "" {
+		fmt.Printf("Process:     %s\n", r.Process)
+	}
+	fmt.Printf("Crashed At:  %s (%s ago)\n", r.Time.Local().Format("2006-01-02 15:04:05"), formatDuration(time.Since(r.Time)))
+	fmt.Printf("Reason:      %s\n", r.Reason)
+	if r.Uptime != "" {
+		fmt.Printf("Uptime:      %s\n", r.Uptime)
+	}
+	if r.LogFile != "" {
+		fmt.Printf("Log File:    %s\n", r.LogFile)
+	}
+	for i, line := range r.Fatal {
+		label := ""
+		if i == 0 {
+			label = "Fatal Log:"
+		}
+		fmt.Printf("%-12s %s\n", label, line)
+	}
+
+	log := r.Log
+	if lines > 0 && len(log) > lines {
+		log = log[len(log)-lines:]
+	}
+	if len(log) == 0 {
+		return
+	}
+	fmt.Printf("\nLast %d log lines:\n", len(log))
+	fmt.Println(strings.Join(log, "\n"))
+}
+
+// saveCrashReport saves a report for a crash grove saw happen
+func saveCrashReport(r *crash.Report) {
+	path, err := crash.Save(config.CrashesDir(), r)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Crash report: %s (grove crashes %s)\n", path, r.Server)
+}
@@ -8,6 +8,7 @@ import (
 	"syscall"
 
 	"github.com/iheanyi/grove/internal/dashboard"
+	"github.com/iheanyi/grove/internal/github"
 	"github.com/spf13/cobra"
 )
 
@@ -46,13 +47,19 @@ func runDashboard(cmd *cobra.Command, args []string) error {
 	devMode, _ := cmd.Flags().GetBool("dev")
 	devURL, _ := cmd.Flags().GetString("dev-url")
 
-	cfg := dashboard.Config{
-		Port:    port,
-		DevMode: devMode,
-		DevURL:  devURL,
+	github.SetToken(cfg.GitHubToken)
+	dashCfg := dashboard.Config{
+		Port:       port,
+		DevMode:    devMode,
+		DevURL:     devURL,
+		PRCacheTTL: cfg.PRCacheTTL,
+		TLD:        cfg.TLD,
+	}
+	if cfg.ProxyAccessLog {
+		dashCfg.AccessLogDir = cfg.AccessLogDir()
 	}
 
-	server, err := dashboard.NewServer(cfg)
+	server, err := dashboard.NewServer(dashCfg)
 	if err != nil {
 		return fmt.Errorf("failed to create dashboard server: %w", err)
 	}
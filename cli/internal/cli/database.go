@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/database"
+	"github.com/iheanyi/grove/internal/project"
+)
+
+// provisionDatabase clones the template database configured in .grove.yaml
+// for a new worktree. The worktree's own config is used if it has one,
+// otherwise the main repo's. Failures are reported but don't fail 'grove new'.
+func provisionDatabase(worktreePath, mainRepoPath, branch string) {
+	projConfig, err := project.Load(worktreePath)
+	if err != nil {
+		projConfig, err = project.Load(mainRepoPath)
+	}
+	if err != nil || !projConfig.Database.Enabled() {
+		return
+	}
+
+	db, err := database.New(projConfig.Database, branch)
+	if err != nil {
+		fmt.Printf("Warning: database not provisioned: %v\n", err)
+		return
+	}
+
+	fmt.Printf("Creating database %s from %s... ", db.Name, db.Template)
+	if err := db.Create(); err != nil {
+		fmt.Printf("failed\nWarning: %v\n", err)
+		return
+	}
+	fmt.Println("done")
+
+	db.CreatedAt = time.Now()
+	store, err := database.LoadStore(config.DatabasesPath())
+	if err == nil {
+		err = store.Set(worktreePath, db)
+	}
+	if err != nil {
+		fmt.Printf("Warning: failed to record database: %v\n", err)
+		return
+	}
+	fmt.Printf("%s=%s\n", db.Env, db.URL())
+}
+
+// applyDatabaseEnv sets the worktree database's URL in the project env, so
+// it overrides the template database configured there
+func applyDatabaseEnv(worktreePath string, projConfig *project.Config) *project.Config {
+	db, ok := worktreeDatabase(worktreePath)
+	if !ok {
+		return projConfig
+	}
+
+	if projConfig == nil {
+		projConfig = &project.Config{}
+	}
+	if projConfig.Env == nil {
+		projConfig.Env = make(map[string]string)
+	}
+	projConfig.Env[db.Env] = db.URL()
+	return projConfig
+}
+
+// worktreeDatabase returns the database provisioned for a worktree, if any
+func worktreeDatabase(worktreePath string) (*database.Database, bool) {
+	store, err := database.LoadStore(config.DatabasesPath())
+	if err != nil {
+		return nil, false
+	}
+	return store.Get(worktreePath)
+}
+
+// dropDatabase drops the database provisioned for a worktree
+func dropDatabase(worktreePath string) error {
+	store, err := database.LoadStore(config.DatabasesPath())
+	if err != nil {
+		return err
+	}
+	db, ok := store.Get(worktreePath)
+	if !ok {
+		return nil
+	}
+
+	if err := db.Drop(); err != nil {
+		return err
+	}
+	return store.Remove(worktreePath)
+}
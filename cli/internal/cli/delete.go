@@ -2,15 +2,18 @@ package cli
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/output"
+	"github.com/iheanyi/grove/internal/project"
 	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/trash"
 	"github.com/iheanyi/grove/internal/worktree"
 	"github.com/spf13/cobra"
 )
@@ -26,11 +29,30 @@ This command performs the following steps:
 3. Removes the worktree using 'git worktree remove'
 4. Removes the worktree from the registry
 5. Deletes associated log files
+6. Drops the worktree's database, if one was provisioned
+
+With --force, a worktree with uncommitted changes is deleted anyway, but
+its changes, untracked files and branch are put in the trash first, for
+'grove trash restore' (see trash_retention).
+
+With --cascade, AI agents working in the worktree are stopped too, and
+you're asked whether to close editors that have it open. --force closes
+them without asking; with --json or --format, they're left open.
+
+With --merge-check, the worktree's branch must be merged into the repo's
+default branch (origin/HEAD, or main or master): merged into it locally or
+on origin as of the last fetch, or its upstream branch deleted, as after a
+squash merge. --with-branch also deletes the local branch, and
+--with-remote the remote branch; both check it's merged first. Unmerged
+branches are refused unless --force is given.
 
 Examples:
   grove delete feature-auth         # Delete with safety prompts
   grove delete feature-auth --force # Skip confirmation prompts
-  grove delete feature-auth --dry-run # Show what would be deleted`,
+  grove delete feature-auth --dry-run # Show what would be deleted
+  grove delete feature-auth --cascade # Also stop its agents and editors
+  grove delete feature-auth --merge-check # Only if its branch is merged
+  grove delete feature-auth --with-branch --with-remote # Delete its branches too`,
 	Args: cobra.ExactArgs(1),
 	RunE: runDelete,
 }
@@ -38,22 +60,67 @@ Examples:
 func init() {
 	deleteCmd.Flags().Bool("force", false, "Skip confirmation prompts and force deletion")
 	deleteCmd.Flags().Bool("dry-run", false, "Show what would be deleted without making changes")
+	deleteCmd.Flags().Bool("cascade", false, "Also stop AI agents working in the worktree and offer to close its editors")
+	deleteCmd.Flags().Bool("merge-check", false, "Refuse to delete unless the worktree's branch is merged into the default branch")
+	deleteCmd.Flags().Bool("with-branch", false, "Also delete the worktree's local branch (implies --merge-check)")
+	deleteCmd.Flags().Bool("with-remote", false, "Also delete the branch on its remote (implies --merge-check)")
+	addOutputFlags(deleteCmd)
+}
+
+// deleteOptions are the flags of 'grove delete'
+type deleteOptions struct {
+	force   bool
+	dryRun  bool
+	cascade bool
+	// machine is set for machine-readable output, which can't prompt
+	machine bool
+	// mergeCheck refuses to delete unless the branch is merged
+	mergeCheck bool
+	withBranch bool
+	withRemote bool
 }
 
 func runDelete(cmd *cobra.Command, args []string) error {
-	name := args[0]
-	force, _ := cmd.Flags().GetBool("force")
-	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	var opts deleteOptions
+	opts.force, _ = cmd.Flags().GetBool("force")
+	opts.dryRun, _ = cmd.Flags().GetBool("dry-run")
+	opts.cascade, _ = cmd.Flags().GetBool("cascade")
+	opts.mergeCheck, _ = cmd.Flags().GetBool("merge-check")
+	opts.withBranch, _ = cmd.Flags().GetBool("with-branch")
+	opts.withRemote, _ = cmd.Flags().GetBool("with-remote")
+	if format, err := outputFormat(cmd); err == nil {
+		opts.machine = format.IsMachine()
+	}
+	if opts.withBranch || opts.withRemote {
+		opts.mergeCheck = true
+	}
+
+	return runWithOutput(cmd, func() (any, error) {
+		result, err := deleteWorktree(cmd.Context(), args[0], opts)
+		if result == nil {
+			return nil, err
+		}
+		return result, err
+	})
+}
+
+// deleteWorktree runs the safety checks, asks for confirmation unless
+// forced, and removes the worktree. With cascade, the worktree's agents and
+// (once confirmed) editors are stopped first; with withBranch and
+// withRemote, its branches are deleted after it.
+func deleteWorktree(ctx context.Context, name string, opts deleteOptions) (*output.DeleteResult, error) {
+	force, dryRun, cascade := opts.force, opts.dryRun, opts.cascade
 
 	// Load registry
 	reg, err := registry.Load()
 	if err != nil {
-		return fmt.Errorf("failed to load registry: %w", err)
+		return nil, fmt.Errorf("failed to load registry: %w", err)
 	}
 
 	// Find the worktree path - check registry first, then git worktree list
 	var worktreePath string
 	var mainRepoPath string
+	var branch string
 
 	// Check if we have a server registered for this name
 	if server, ok := reg.Get(name); ok {
@@ -65,6 +132,7 @@ func runDelete(cmd *cobra.Command, args []string) error {
 		if wt, ok := reg.GetWorktree(name); ok {
 			worktreePath = wt.Path
 			mainRepoPath = wt.MainRepo
+			branch = wt.Branch
 		}
 	}
 
@@ -73,7 +141,7 @@ func runDelete(cmd *cobra.Command, args []string) error {
 		// Detect current repo to find worktrees
 		currentWt, err := worktree.Detect()
 		if err != nil {
-			return fmt.Errorf("failed to detect git repository: %w", err)
+			return nil, fmt.Errorf("failed to detect git repository: %w", err)
 		}
 
 		mainRepoPath = currentWt.Path
@@ -84,27 +152,34 @@ func runDelete(cmd *cobra.Command, args []string) error {
 		// Search for the worktree
 		worktreePath, err = findWorktree(mainRepoPath, name)
 		if err != nil {
-			return fmt.Errorf("worktree '%s' not found", name)
+			return nil, fmt.Errorf("worktree '%s' not found", name)
 		}
 	}
 
 	// Get main repo path if we don't have it
-	if mainRepoPath == "" {
+	if mainRepoPath == "" || (opts.mergeCheck && branch == "") {
 		wtInfo, err := worktree.DetectAt(worktreePath)
 		if err != nil {
-			return fmt.Errorf("failed to detect worktree info: %w", err)
+			return nil, fmt.Errorf("failed to detect worktree info: %w", err)
 		}
-		mainRepoPath = wtInfo.Path
-		if wtInfo.IsWorktree && wtInfo.MainWorktreePath != "" {
-			mainRepoPath = wtInfo.MainWorktreePath
+		if mainRepoPath == "" {
+			mainRepoPath = wtInfo.Path
+			if wtInfo.IsWorktree && wtInfo.MainWorktreePath != "" {
+				mainRepoPath = wtInfo.MainWorktreePath
+			}
+		}
+		if branch == "" {
+			branch = wtInfo.Branch
 		}
 	}
 
 	// Check if trying to delete the main worktree
 	if worktreePath == mainRepoPath {
-		return fmt.Errorf("cannot delete the main worktree; use 'rm -rf' to remove the entire repository")
+		return nil, fmt.Errorf("cannot delete the main worktree; use 'rm -rf' to remove the entire repository")
 	}
 
+	result := &output.DeleteResult{Name: name, Path: worktreePath, DryRun: dryRun, Warnings: []string{}}
+
 	fmt.Printf("Worktree: %s\n", name)
 	fmt.Printf("Path: %s\n", worktreePath)
 	fmt.Println()
@@ -113,13 +188,38 @@ func runDelete(cmd *cobra.Command, args []string) error {
 	var warnings []string
 
 	// Check for uncommitted changes
-	hasChanges, err := checkUncommittedChanges(worktreePath)
+	hasChanges, err := checkUncommittedChanges(ctx, worktreePath)
 	if err != nil {
 		fmt.Printf("Warning: could not check for uncommitted changes: %v\n", err)
 	} else if hasChanges {
 		warnings = append(warnings, "Worktree has uncommitted changes")
 	}
 
+	// Check the branch is merged
+	var remote, remoteName string
+	if opts.mergeCheck {
+		if branch == "" {
+			return nil, fmt.Errorf("worktree '%s' has no branch checked out to check", name)
+		}
+		merge, err := checkBranchMerged(mainRepoPath, branch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check whether '%s' is merged: %w", branch, err)
+		}
+		result.Branch, result.BaseBranch = branch, merge.Base
+		merged := merge.Merged
+		result.Merged = &merged
+		if merged {
+			fmt.Printf("Branch %s: %s\n\n", branch, merge.Reason)
+		} else if !force {
+			return result, fmt.Errorf("branch '%s' is not merged into %s (use --force to delete it anyway)", branch, merge.Base)
+		} else {
+			warnings = append(warnings, fmt.Sprintf("Branch %s is not merged into %s", branch, merge.Base))
+		}
+		if opts.withRemote {
+			remote, remoteName = remoteBranch(mainRepoPath, branch)
+		}
+	}
+
 	// Check if server is running
 	var serverRunning bool
 	if server, ok := reg.Get(name); ok && server.IsRunning() {
@@ -134,6 +234,8 @@ func runDelete(cmd *cobra.Command, args []string) error {
 		hasLogs = true
 	}
 
+	result.Warnings = append(result.Warnings, warnings...)
+
 	// Display warnings
 	if len(warnings) > 0 {
 		fmt.Println("Warnings:")
@@ -148,16 +250,30 @@ func runDelete(cmd *cobra.Command, args []string) error {
 	if serverRunning {
 		fmt.Println("  - Stop the running server")
 	}
+	if cascade {
+		fmt.Println("  - Stop AI agents working in the worktree and offer to close its editors")
+	}
 	fmt.Printf("  - Remove worktree at %s\n", worktreePath)
 	fmt.Println("  - Remove from registry")
+	if opts.withBranch {
+		fmt.Printf("  - Delete branch %s\n", branch)
+	}
+	if remote != "" {
+		fmt.Printf("  - Delete remote branch %s/%s\n", remote, remoteName)
+	}
 	if hasLogs {
 		fmt.Printf("  - Delete log file: %s\n", logPath)
+		result.LogFile = logPath
+	}
+	if db, ok := worktreeDatabase(worktreePath); ok {
+		fmt.Printf("  - Drop database: %s\n", db.Name)
+		result.Database = db.Name
 	}
 	fmt.Println()
 
 	if dryRun {
 		fmt.Println("(Dry run - no changes made)")
-		return nil
+		return result, nil
 	}
 
 	// Confirm deletion
@@ -171,23 +287,84 @@ func runDelete(cmd *cobra.Command, args []string) error {
 		reader := bufio.NewReader(os.Stdin)
 		response, err := reader.ReadString('\n')
 		if err != nil {
-			return fmt.Errorf("failed to read input: %w", err)
+			return nil, fmt.Errorf("failed to read input: %w", err)
 		}
 
 		response = strings.ToLower(strings.TrimSpace(response))
 		if response != "y" && response != "yes" {
 			fmt.Println("Canceled")
-			return nil
+			return result, nil
 		}
 		fmt.Println()
 	}
 
+	if cascade {
+		result.Processes = stopWorktreeProcesses(ctx, worktreePath, editorConfirm(force, opts.machine))
+	}
+
+	trashed, err := removeWorktree(reg, name, worktreePath, mainRepoPath, force)
+	if err != nil {
+		return nil, err
+	}
+	result.Deleted = true
+	if trashed != nil {
+		result.Trash = trashed.ID
+	}
+
+	// The branch can only be deleted once no worktree has it checked out
+	if opts.withBranch {
+		fmt.Printf("Deleting branch %s... ", branch)
+		if err := deleteBranch(mainRepoPath, branch); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		} else {
+			fmt.Println("done")
+			result.DeletedBranch = true
+		}
+	}
+	if remote != "" {
+		fmt.Printf("Deleting remote branch %s/%s... ", remote, remoteName)
+		if err := deleteRemoteBranch(mainRepoPath, remote, remoteName); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		} else {
+			fmt.Println("done")
+			result.DeletedRemoteBranch = remote + "/" + remoteName
+		}
+	}
+
+	fmt.Printf("\nSuccessfully deleted worktree '%s'\n", name)
+	if trashed != nil {
+		fmt.Printf("Its uncommitted changes are in the trash; restore it with 'grove trash restore %s'\n", trashed.ID)
+	}
+
+	return result, nil
+}
+
+// removeWorktree stops the worktree's server, removes the worktree with git,
+// and cleans up its registry entries, log file, and git metadata. With force,
+// failures are reported as warnings and removal continues, and uncommitted
+// changes, which git would otherwise discard, are put in the trash first;
+// failing to save them stops removal. trashed is the trash entry, if any.
+func removeWorktree(reg *registry.Registry, name, worktreePath, mainRepoPath string, force bool) (trashed *trash.Entry, err error) {
+	target := hookTarget{Name: name, Path: worktreePath}
+	if wt, ok := reg.GetWorktree(name); ok {
+		target.Branch = wt.Branch
+	}
+	if server, ok := reg.Get(name); ok {
+		target.Port, target.URL = server.Port, server.URL
+	}
+	if err := runGlobalHooks(hookPreDelete, target, os.Stdout); err != nil {
+		if !force {
+			return nil, fmt.Errorf("pre-delete hook failed: %w (use --force to continue anyway)", err)
+		}
+		fmt.Printf("Warning: pre-delete hook failed: %v\n", err)
+	}
+
 	// Stop server if running
-	if serverRunning {
+	if server, ok := reg.Get(name); ok && server.IsRunning() {
 		fmt.Print("Stopping server... ")
 		if err := stopServer(reg, name, 10*time.Second); err != nil {
 			if !force {
-				return fmt.Errorf("failed to stop server: %w (use --force to continue anyway)", err)
+				return nil, fmt.Errorf("failed to stop server: %w (use --force to continue anyway)", err)
 			}
 			fmt.Printf("Warning: %v\n", err)
 		} else {
@@ -195,19 +372,30 @@ func runDelete(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Remove the worktree's compose containers and networks
+	if server, ok := reg.Get(name); ok && server.IsCompose() {
+		fmt.Print("Removing compose project... ")
+		projConfig, _ := project.Load(worktreePath)
+		if err := composeProject(server, projConfig).Down(); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		} else {
+			fmt.Println("done")
+		}
+	}
+
+	if force && checkGitDirty(worktreePath) {
+		if trashed, err = saveToTrash(name, worktreePath, mainRepoPath); err != nil {
+			return nil, fmt.Errorf("failed to save uncommitted changes to the trash: %w (set trash_retention: \"0\" to delete without saving)", err)
+		}
+	}
+
 	// Remove worktree using git
 	fmt.Print("Removing worktree... ")
-	gitArgs := []string{"worktree", "remove", worktreePath}
-	if force {
-		gitArgs = append(gitArgs, "--force")
-	}
-	gitCmd := exec.Command("git", gitArgs...)
-	gitCmd.Dir = mainRepoPath
-	if output, err := gitCmd.CombinedOutput(); err != nil {
+	if err := gitOps().RemoveWorktree(context.Background(), mainRepoPath, worktreePath, force); err != nil {
 		if !force {
-			return fmt.Errorf("failed to remove worktree: %s", strings.TrimSpace(string(output)))
+			return trashed, fmt.Errorf("failed to remove worktree: %w", err)
 		}
-		fmt.Printf("Warning: %s\n", strings.TrimSpace(string(output)))
+		fmt.Printf("Warning: %v\n", err)
 	} else {
 		fmt.Println("done")
 	}
@@ -227,7 +415,8 @@ func runDelete(cmd *cobra.Command, args []string) error {
 	fmt.Println("done")
 
 	// Delete log files
-	if hasLogs {
+	logPath := getLogPath(name)
+	if _, err := os.Stat(logPath); err == nil {
 		fmt.Print("Deleting log files... ")
 		if err := os.Remove(logPath); err != nil {
 			fmt.Printf("Warning: %v\n", err)
@@ -236,29 +425,31 @@ func runDelete(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Drop the worktree's database
+	if db, ok := worktreeDatabase(worktreePath); ok {
+		fmt.Printf("Dropping database %s... ", db.Name)
+		if err := dropDatabase(worktreePath); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		} else {
+			fmt.Println("done")
+		}
+	}
+
 	// Clean up worktree metadata
 	fmt.Print("Cleaning up git worktree metadata... ")
-	pruneCmd := exec.Command("git", "worktree", "prune")
-	pruneCmd.Dir = mainRepoPath
-	if err := pruneCmd.Run(); err != nil {
+	if err := gitOps().PruneWorktrees(context.Background(), mainRepoPath); err != nil {
 		fmt.Printf("Warning: %v\n", err)
 	} else {
 		fmt.Println("done")
 	}
 
-	fmt.Printf("\nSuccessfully deleted worktree '%s'\n", name)
-
-	return nil
+	return trashed, nil
 }
 
 // checkUncommittedChanges checks if a worktree has uncommitted changes
-func checkUncommittedChanges(path string) (bool, error) {
-	cmd := exec.Command("git", "-C", path, "status", "--porcelain")
-	output, err := cmd.Output()
-	if err != nil {
-		return false, err
-	}
-	return len(strings.TrimSpace(string(output))) > 0, nil
+func checkUncommittedChanges(ctx context.Context, path string) (bool, error) {
+	n, err := gitOps().DirtyFiles(ctx, path)
+	return n > 0, err
 }
 
 // getLogPath returns the path to the log file for a server
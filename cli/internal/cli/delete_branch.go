@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// branchMerge is whether a worktree's branch is merged into the repo's
+// default branch
+type branchMerge struct {
+	Branch string
+	Base   string
+	Merged bool
+	// Reason says why the branch counts as merged, e.g. "merged into
+	// origin/main"
+	Reason string
+}
+
+// checkBranchMerged checks whether branch is merged into the default
+// branch, locally or on origin as of the last fetch. A branch whose
+// upstream was deleted counts as merged, since that's what's left of a
+// squash merge, and so does one without commits of its own.
+func checkBranchMerged(repoPath, branch string) (*branchMerge, error) {
+	base, err := detectDefaultBranch(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	if branch == base {
+		return nil, fmt.Errorf("'%s' is the default branch", branch)
+	}
+
+	m := &branchMerge{Branch: branch, Base: base}
+	if !hasOwnCommits(repoPath, branch, base) {
+		m.Merged, m.Reason = true, "no commits of its own"
+		return m, nil
+	}
+	for _, ref := range []string{base, "origin/" + base} {
+		if verifyRefExists(repoPath, ref) != nil {
+			continue
+		}
+		if ok, err := isBranchMerged(repoPath, branch, ref); err == nil && ok {
+			m.Merged, m.Reason = true, "merged into "+ref
+			return m, nil
+		}
+	}
+	if isUpstreamGone(repoPath, branch) {
+		m.Merged, m.Reason = true, "upstream branch deleted"
+	}
+	return m, nil
+}
+
+// remoteBranch returns the remote and name of the branch's upstream, or
+// of the branch of the same name on origin if it doesn't track one. remote
+// is empty when there's no remote branch to delete.
+func remoteBranch(repoPath, branch string) (remote, name string) {
+	if isUpstreamGone(repoPath, branch) {
+		return "", ""
+	}
+	remote = gitConfigValue(repoPath, "branch."+branch+".remote")
+	merge := gitConfigValue(repoPath, "branch."+branch+".merge")
+	if remote != "" && remote != "." && merge != "" {
+		return remote, strings.TrimPrefix(merge, "refs/heads/")
+	}
+	if verifyRefExists(repoPath, "refs/remotes/origin/"+branch) == nil {
+		return "origin", branch
+	}
+	return "", ""
+}
+
+// gitConfigValue returns a git config value of the repo, or "" if it isn't
+// set
+func gitConfigValue(repoPath, key string) string {
+	output, err := exec.Command("git", "-C", repoPath, "config", "--get", key).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// deleteBranch deletes a local branch. Whether it's merged was checked (or
+// forced) already, so git isn't asked to check again.
+func deleteBranch(repoPath, branch string) error {
+	return gitOps().DeleteBranch(context.Background(), repoPath, branch, true)
+}
+
+// deleteRemoteBranch deletes a branch on a remote
+func deleteRemoteBranch(repoPath, remote, branch string) error {
+	output, err := exec.Command("git", "-C", repoPath, "push", remote, "--delete", branch).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
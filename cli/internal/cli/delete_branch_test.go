@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func TestCheckBranchMerged(t *testing.T) {
+	origin, repo := t.TempDir(), t.TempDir()
+	gitRun(t, origin, "init", "--bare", "-b", "main")
+	gitRun(t, repo, "init", "-b", "main")
+	gitRun(t, repo, "commit", "--allow-empty", "-m", "initial")
+	gitRun(t, repo, "remote", "add", "origin", origin)
+	gitRun(t, repo, "push", "-u", "origin", "main")
+	gitRun(t, repo, "remote", "set-head", "origin", "main")
+
+	gitRun(t, repo, "checkout", "-b", "done")
+	gitRun(t, repo, "commit", "--allow-empty", "-m", "done")
+	gitRun(t, repo, "checkout", "main")
+	gitRun(t, repo, "merge", "--no-ff", "-m", "merge done", "done")
+	gitRun(t, repo, "branch", "fresh")
+	gitRun(t, repo, "checkout", "-b", "wip")
+	gitRun(t, repo, "commit", "--allow-empty", "-m", "wip")
+	gitRun(t, repo, "push", "-u", "origin", "wip")
+	gitRun(t, repo, "checkout", "main")
+
+	tests := []struct {
+		branch string
+		merged bool
+		reason string
+	}{
+		{"done", true, "merged into main"},
+		{"fresh", true, "no commits of its own"},
+		{"wip", false, ""},
+	}
+	for _, tt := range tests {
+		m, err := checkBranchMerged(repo, tt.branch)
+		if err != nil {
+			t.Fatalf("checkBranchMerged(%s): %v", tt.branch, err)
+		}
+		if m.Base != "main" || m.Merged != tt.merged || m.Reason != tt.reason {
+			t.Errorf("checkBranchMerged(%s) = %+v, want merged %v (%q) into main", tt.branch, m, tt.merged, tt.reason)
+		}
+	}
+	if _, err := checkBranchMerged(repo, "main"); err == nil {
+		t.Error("the default branch shouldn't be checked against itself")
+	}
+
+	remote, name := remoteBranch(repo, "wip")
+	if remote != "origin" || name != "wip" {
+		t.Fatalf("remoteBranch(wip) = %s/%s, want origin/wip", remote, name)
+	}
+	if remote, _ := remoteBranch(repo, "done"); remote != "" {
+		t.Errorf("remoteBranch(done) = %s, want none", remote)
+	}
+	if err := deleteRemoteBranch(repo, remote, name); err != nil {
+		t.Fatal(err)
+	}
+	if exec.Command("git", "-C", origin, "rev-parse", "--verify", "refs/heads/wip").Run() == nil {
+		t.Error("wip still exists on origin")
+	}
+
+	// Once its upstream is gone (and pruned), a squash-merged branch counts
+	// as merged
+	gitRun(t, repo, "fetch", "--prune")
+	if m, err := checkBranchMerged(repo, "wip"); err != nil || !m.Merged || m.Reason != "upstream branch deleted" {
+		t.Errorf("checkBranchMerged(wip) = %+v, %v; want merged with its upstream deleted", m, err)
+	}
+}
+
+func TestDetectDefaultBranchWithSlash(t *testing.T) {
+	repo := t.TempDir()
+	gitRun(t, repo, "init", "-b", "main")
+	gitRun(t, repo, "commit", "--allow-empty", "-m", "initial")
+	gitRun(t, repo, "update-ref", "refs/remotes/origin/release/v2", "HEAD")
+	gitRun(t, repo, "symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/release/v2")
+
+	if got, err := detectDefaultBranch(repo); err != nil || got != "release/v2" {
+		t.Errorf("detectDefaultBranch = %q, %v; want release/v2", got, err)
+	}
+}
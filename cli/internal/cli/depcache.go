@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/depcache"
+	"github.com/iheanyi/grove/internal/project"
+	"github.com/iheanyi/grove/internal/usage"
+)
+
+// provisionCache seeds a new worktree's dependencies from the main worktree
+// as configured under 'cache:' in .grove.yaml, runs the install command and
+// reports the time saved against the last full install. The worktree's own
+// config is used if it has one, otherwise the main repo's. Failures are
+// reported but don't fail 'grove new'.
+func provisionCache(worktreePath, mainRepoPath string) {
+	projConfig, err := project.Load(worktreePath)
+	if err != nil {
+		projConfig, err = project.Load(mainRepoPath)
+	}
+	if err != nil || !projConfig.Cache.Enabled() {
+		return
+	}
+
+	plan, err := depcache.Resolve(projConfig.Cache, mainRepoPath)
+	if err != nil {
+		fmt.Printf("Warning: dependencies not seeded: %v\n", err)
+		return
+	}
+
+	start := time.Now()
+	var seeded []string
+	if len(plan.Paths) > 0 {
+		fmt.Printf("Seeding dependencies from the main worktree (%s)... ", plan.Mode)
+		var stats depcache.Stats
+		seeded, stats, err = depcache.Seed(plan, mainRepoPath, worktreePath)
+		switch {
+		case err != nil:
+			fmt.Printf("failed\nWarning: %v\n", err)
+		case len(seeded) == 0:
+			fmt.Println("nothing to seed")
+		default:
+			fmt.Printf("done\n%s: %d files, %s in %s\n", strings.Join(seeded, ", "), stats.Files,
+				usage.FormatBytes(stats.Bytes), time.Since(start).Round(100*time.Millisecond))
+		}
+	}
+
+	if plan.Install != "" {
+		fmt.Printf("Installing dependencies: %s\n", plan.Install)
+		if err := runHook(plan.Install, worktreePath); err != nil {
+			fmt.Printf("Warning: install failed: %v\n", err)
+			return
+		}
+	}
+	elapsed := time.Since(start)
+
+	timings, err := depcache.LoadTimings(config.DepCachePath())
+	if err != nil {
+		fmt.Printf("Dependencies ready in %s\n", elapsed.Round(100*time.Millisecond))
+		return
+	}
+	fmt.Println(cacheSummary(timings, mainRepoPath, len(plan.Paths) > 0, len(seeded) > 0, plan.Install != "", elapsed))
+}
+
+// cacheSummary records a full install (nothing could be seeded) and
+// describes how long the dependencies took, compared with the last full
+// install if there is one
+func cacheSummary(timings *depcache.Timings, repoPath string, seeds, seeded, installed bool, elapsed time.Duration) string {
+	ready := fmt.Sprintf("Dependencies ready in %s", elapsed.Round(100*time.Millisecond))
+	if seeds && !seeded && installed {
+		if err := timings.SetFullInstall(repoPath, elapsed); err != nil {
+			return ready
+		}
+		return ready + " (a full install; later worktrees will report the time saved against it)"
+	}
+
+	full, ok := timings.FullInstall(repoPath)
+	if !ok || !seeded {
+		return ready
+	}
+	if saved := full - elapsed; saved > 0 {
+		return fmt.Sprintf("%s, about %s faster than a full install (%s)", ready, saved.Round(time.Second), full.Round(time.Second))
+	}
+	return ready
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/depcache"
+)
+
+func TestProvisionCache(t *testing.T) {
+	useTestEnv(t)
+	fake := useFakeRunner(t)
+	fake.On("sh -c npm install --prefer-offline --no-audit --no-fund", "", nil)
+
+	main, wt := t.TempDir(), t.TempDir()
+	if err := os.WriteFile(filepath.Join(main, ".grove.yaml"), []byte("cache:\n  preset: npm\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(main, "node_modules", "react"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(main, "node_modules", "react", "index.js"), []byte("react\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	provisionCache(wt, main)
+	if _, err := os.Stat(filepath.Join(wt, "node_modules", "react", "index.js")); err != nil {
+		t.Errorf("node_modules not seeded: %v", err)
+	}
+	if len(fake.Calls()) != 1 {
+		t.Errorf("install should run once, ran %v", fake.Calls())
+	}
+}
+
+func TestCacheSummary(t *testing.T) {
+	useTestEnv(t)
+	timings, err := depcache.LoadTimings(config.DepCachePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing to seed: the install is a full one and is recorded
+	got := cacheSummary(timings, "/src/app", true, false, true, 3*time.Minute)
+	if !strings.Contains(got, "a full install") {
+		t.Errorf("cacheSummary(full) = %q", got)
+	}
+	if d, ok := timings.FullInstall("/src/app"); !ok || d != 3*time.Minute {
+		t.Errorf("full install = %v, %v, want 3m", d, ok)
+	}
+
+	got = cacheSummary(timings, "/src/app", true, true, true, 20*time.Second)
+	if want := "Dependencies ready in 20s, about 2m40s faster than a full install (3m0s)"; got != want {
+		t.Errorf("cacheSummary(seeded) = %q, want %q", got, want)
+	}
+
+	// Without a recorded full install there's nothing to compare with
+	if got := cacheSummary(timings, "/src/other", true, true, true, 20*time.Second); got != "Dependencies ready in 20s" {
+		t.Errorf("cacheSummary(unknown repo) = %q", got)
+	}
+}
@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/iheanyi/grove/internal/health"
+	"github.com/iheanyi/grove/internal/port"
+	"github.com/iheanyi/grove/internal/project"
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+// findDependencyCycle walks depends_on from root and returns an error naming
+// the first cycle or missing dependency. deps returns a worktree's
+// dependencies, or false if it isn't registered.
+func findDependencyCycle(root string, deps func(name string) ([]string, bool)) error {
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int)
+	path := []string{root}
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		next, ok := deps(name)
+		if !ok {
+			return fmt.Errorf("%s depends on '%s', which is not a registered worktree (run 'grove discover' in its repo)",
+				path[len(path)-2], name)
+		}
+		state[name] = visiting
+		for _, dep := range next {
+			switch state[dep] {
+			case visiting:
+				cycle := append(slices.Clone(path[slices.Index(path, dep):]), dep)
+				return fmt.Errorf("dependency cycle: %s", strings.Join(cycle, " -> "))
+			case done:
+				continue
+			}
+			path = append(path, dep)
+			if err := visit(dep); err != nil {
+				return err
+			}
+			path = path[:len(path)-1]
+		}
+		state[name] = done
+		return nil
+	}
+	return visit(root)
+}
+
+// startDependencies starts the servers in a worktree's depends_on that aren't
+// running, and waits until each one is ready. Dependencies are started with
+// 'grove start', so theirs are brought up the same way.
+func startDependencies(name string, projConfig *project.Config) error {
+	reg, err := registry.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+
+	err = findDependencyCycle(name, func(n string) ([]string, bool) {
+		if n == name {
+			return projConfig.DependsOn, true
+		}
+		server, ok := reg.Get(n)
+		if !ok {
+			return nil, false
+		}
+		if depConfig, _ := project.Load(server.Path); depConfig != nil {
+			return depConfig.DependsOn, true
+		}
+		return nil, true
+	})
+	if err != nil {
+		return err
+	}
+
+	used := reg.GetUsedPorts()
+	for _, dep := range projConfig.DependsOn {
+		server, _ := reg.Get(dep)
+		depConfig, _ := project.Load(server.Path)
+
+		if !server.IsRunning() {
+			serverPort, err := plannedPort(server, used)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Starting dependency '%s'...\n", dep)
+			if err := startRegistered(server, serverPort, nil); err != nil {
+				return fmt.Errorf("failed to start dependency '%s': %w", dep, err)
+			}
+			if reg, err = registry.Load(); err != nil {
+				return fmt.Errorf("failed to load registry: %w", err)
+			}
+			server, _ = reg.Get(dep)
+		}
+
+		if err := waitReady(server, depConfig); err != nil {
+			return fmt.Errorf("dependency '%s' is not ready: %w", dep, err)
+		}
+	}
+	return nil
+}
+
+// waitReady waits for a server's port, then for its health check to pass
+// if .grove.yaml configures one
+func waitReady(server *registry.Server, projConfig *project.Config) error {
+	timeout := cfg.HealthCheckTimeout
+	var check project.HealthCheckConfig
+	if projConfig != nil {
+		check = projConfig.HealthCheck
+		if check.Timeout > 0 {
+			timeout = check.Timeout
+		}
+	}
+
+	deadline := time.Now().Add(timeout)
+	if !port.IsListening(server.Port) {
+		fmt.Printf("Waiting for '%s' on port %d...\n", server.Name, server.Port)
+	}
+	if err := port.WaitForPort(server.Port, timeout); err != nil {
+		return err
+	}
+	if check.Probe() == project.HealthCheckHTTP && check.Path == "" {
+		return nil
+	}
+
+	target := health.Target{Name: server.Name, Port: server.Port, Dir: server.Path}
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		err := health.Check(ctx, check, target)
+		cancel()
+		if err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("health check failed (%s): %w", health.Describe(check, target), err)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+// applyDependencyEnv sets each depends_on server's URL in the project env as
+// <NAME>_URL, unless the env already sets it
+func applyDependencyEnv(projConfig *project.Config, reg *registry.Registry) {
+	if projConfig == nil || len(projConfig.DependsOn) == 0 {
+		return
+	}
+	if projConfig.Env == nil {
+		projConfig.Env = make(map[string]string)
+	}
+	for _, dep := range projConfig.DependsOn {
+		server, ok := reg.Get(dep)
+		if !ok || server.URL == "" {
+			continue
+		}
+		if _, set := projConfig.Env[urlEnvVar(dep)]; !set {
+			projConfig.Env[urlEnvVar(dep)] = server.URL
+		}
+	}
+}
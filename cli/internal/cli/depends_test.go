@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFindDependencyCycle(t *testing.T) {
+	tests := []struct {
+		name    string
+		graph   map[string][]string
+		wantErr string
+	}{
+		{"chain", map[string][]string{"web": {"api"}, "api": {"auth"}, "auth": nil}, ""},
+		{"diamond", map[string][]string{"web": {"api", "auth"}, "api": {"auth"}, "auth": nil}, ""},
+		{"cycle", map[string][]string{"web": {"api"}, "api": {"auth"}, "auth": {"api"}}, "dependency cycle: api -> auth -> api"},
+		{"self", map[string][]string{"web": {"web"}}, "dependency cycle: web -> web"},
+		{"missing", map[string][]string{"web": {"api"}, "api": {"auth"}}, "api depends on 'auth', which is not a registered worktree"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := findDependencyCycle("web", func(name string) ([]string, bool) {
+				deps, ok := tt.graph[name]
+				return deps, ok
+			})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/iheanyi/grove/internal/output"
+	"github.com/iheanyi/grove/internal/project"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/styles"
+	"github.com/spf13/cobra"
+)
+
+var diffEnvCmd = &cobra.Command{
+	Use:   "diff-env <from> <to>",
+	Short: "Compare the environment of two worktrees' servers",
+	Long: `Compare the environment 'grove start' gives two worktrees' servers, to
+debug something that works in one worktree but not the other.
+
+Each variable is resolved the way 'grove start' sets it, with its source:
+
+  grove        PORT and GROVE_URL (or url_var)
+  .grove.yaml  env
+  database     the worktree's database URL (database in .grove.yaml)
+  depends_on   <NAME>_URL of each dependency
+  <file>       env_files in .grove.yaml
+
+Env files the app loads itself can be compared too with --env-file, which
+is read relative to each worktree. Variables set by 'grove start' take
+precedence over them, as with most dotenv loaders.
+
+Only differing variables are shown; use --all to list every variable.
+
+Examples:
+  grove diff-env main feature-auth
+  grove diff-env main feature-auth --env-file .env --env-file .env.local
+  grove diff-env main feature-auth --json`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDiffEnv,
+}
+
+func init() {
+	diffEnvCmd.Flags().StringSlice("env-file", nil, "Env file to compare, relative to each worktree (repeatable)")
+	diffEnvCmd.Flags().Bool("all", false, "List variables that are the same in both worktrees too")
+	addOutputFlags(diffEnvCmd)
+}
+
+func runDiffEnv(cmd *cobra.Command, args []string) error {
+	from, to := args[0], args[1]
+	envFiles, _ := cmd.Flags().GetStringSlice("env-file")
+	all, _ := cmd.Flags().GetBool("all")
+
+	reg, err := registry.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+
+	fromEnv, err := worktreeEnv(reg, from, envFiles)
+	if err != nil {
+		return err
+	}
+	toEnv, err := worktreeEnv(reg, to, envFiles)
+	if err != nil {
+		return err
+	}
+
+	result := output.EnvDiff{From: from, To: to, Vars: diffEnv(fromEnv, toEnv, all)}
+
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	if format.IsMachine() {
+		return output.Write(os.Stdout, format, result)
+	}
+
+	if len(result.Vars) == 0 {
+		fmt.Printf("'%s' and '%s' have the same environment\n", from, to)
+		return nil
+	}
+
+	value := func(v *output.EnvValue) string {
+		if v == nil {
+			return styles.MutedStyle.Render("(unset)")
+		}
+		return ansi.Truncate(v.Value, styles.TruncateDefault, styles.TruncateTail) + styles.MutedStyle.Render(" ("+v.Source+")")
+	}
+	for _, v := range result.Vars {
+		fmt.Println(v.Name)
+		fmt.Printf("  %-*s %s\n", max(len(from), len(to)), from, value(v.From))
+		fmt.Printf("  %-*s %s\n", max(len(from), len(to)), to, value(v.To))
+	}
+	return nil
+}
+
+// worktreeEnv resolves the variables 'grove start' would set for a worktree,
+// over those in the given env files, in the order they take precedence:
+// env files, env_files, .grove.yaml env, database and depends_on URLs, then
+// PORT and the URL variable
+func worktreeEnv(reg *registry.Registry, name string, envFiles []string) (map[string]output.EnvValue, error) {
+	path, err := resolveWorktreePath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	env := make(map[string]output.EnvValue)
+	set := func(vars map[string]string, source string) {
+		for k, v := range vars {
+			env[k] = output.EnvValue{Value: v, Source: source}
+		}
+	}
+
+	for _, file := range envFiles {
+		vars, err := project.ReadEnvFile(filepath.Join(path, file))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read %s in '%s': %w", file, name, err)
+		}
+		set(vars, file)
+	}
+
+	projConfig, _ := project.Load(path)
+	if projConfig == nil {
+		projConfig = &project.Config{}
+	}
+
+	vars, origin, err := projConfig.EnvFileVars(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read env files of '%s': %w", name, err)
+	}
+	for k, v := range vars {
+		env[k] = output.EnvValue{Value: v, Source: origin[k]}
+	}
+
+	set(projConfig.Env, project.ConfigFileName)
+	if db, ok := worktreeDatabase(path); ok {
+		set(map[string]string{db.Env: db.URL()}, "database")
+	}
+	for _, dep := range projConfig.DependsOn {
+		if _, ok := projConfig.Env[urlEnvVar(dep)]; ok {
+			continue
+		}
+		if server, ok := reg.Get(dep); ok && server.URL != "" {
+			set(map[string]string{urlEnvVar(dep): server.URL}, "depends_on")
+		}
+	}
+
+	// The port and URL of the last run, or those the next one would get
+	var serverPort int
+	var url string
+	if server, ok := reg.Get(name); ok && server.Port > 0 {
+		serverPort, url = server.Port, server.URL
+	} else if projConfig.Port > 0 {
+		serverPort = projConfig.Port
+	} else {
+		serverPort, err = allocatePort(name, path, reg.GetUsedPorts())
+		if err != nil {
+			return nil, fmt.Errorf("failed to allocate port: %w", err)
+		}
+	}
+	if url == "" {
+		url = cfg.ServerURL(name, serverPort)
+	}
+	urlVarName := "GROVE_URL"
+	if projConfig.URLVar != "" {
+		urlVarName = projConfig.URLVar
+	}
+	set(map[string]string{"PORT": strconv.Itoa(serverPort), urlVarName: url}, "grove")
+
+	return env, nil
+}
+
+// diffEnv returns the variables that differ between two environments, or
+// every variable with all, sorted by name
+func diffEnv(from, to map[string]output.EnvValue, all bool) []output.EnvDiffVar {
+	names := make(map[string]bool)
+	for k := range from {
+		names[k] = true
+	}
+	for k := range to {
+		names[k] = true
+	}
+
+	vars := []output.EnvDiffVar{}
+	for name := range names {
+		v := output.EnvDiffVar{Name: name}
+		if f, ok := from[name]; ok {
+			v.From = &f
+		}
+		if t, ok := to[name]; ok {
+			v.To = &t
+		}
+		same := v.From != nil && v.To != nil && v.From.Value == v.To.Value
+		if all || !same {
+			vars = append(vars, v)
+		}
+	}
+	sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+	return vars
+}
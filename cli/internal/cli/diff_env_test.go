@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/iheanyi/grove/internal/output"
+)
+
+func TestDiffEnv(t *testing.T) {
+	from := map[string]output.EnvValue{
+		"PORT":      {Value: "3000", Source: "grove"},
+		"APP_ENV":   {Value: "dev", Source: ".grove.yaml"},
+		"LOG_LEVEL": {Value: "debug", Source: ".env"},
+	}
+	to := map[string]output.EnvValue{
+		"PORT":         {Value: "3100", Source: "grove"},
+		"APP_ENV":      {Value: "dev", Source: ".env"},
+		"DATABASE_URL": {Value: "postgres://localhost/feature", Source: "database"},
+	}
+
+	vars := diffEnv(from, to, false)
+	var names []string
+	for _, v := range vars {
+		names = append(names, v.Name)
+	}
+	if got, want := len(names), 3; got != want {
+		t.Fatalf("diffEnv() returned %v, want DATABASE_URL, LOG_LEVEL and PORT", names)
+	}
+	if names[0] != "DATABASE_URL" || names[1] != "LOG_LEVEL" || names[2] != "PORT" {
+		t.Errorf("expected differing variables sorted by name, got %v", names)
+	}
+	if vars[0].From != nil || vars[0].To.Source != "database" {
+		t.Errorf("expected DATABASE_URL to be unset in from, got %+v", vars[0])
+	}
+	if vars[1].To != nil {
+		t.Errorf("expected LOG_LEVEL to be unset in to, got %+v", vars[1])
+	}
+
+	// Values that match are the same, whatever sets them
+	if vars := diffEnv(from, to, true); len(vars) != 4 {
+		t.Errorf("expected every variable with all, got %d", len(vars))
+	}
+}
@@ -1,14 +1,24 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/charmbracelet/x/ansi"
-	"github.com/iheanyi/grove/internal/port"
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/discovery"
+	"github.com/iheanyi/grove/internal/metrics"
+	"github.com/iheanyi/grove/internal/output"
+	"github.com/iheanyi/grove/internal/project"
 	"github.com/iheanyi/grove/internal/registry"
 	"github.com/iheanyi/grove/internal/styles"
 	"github.com/iheanyi/grove/internal/worktree"
@@ -21,14 +31,23 @@ var discoverCmd = &cobra.Command{
 	Long: `Scan a directory for git repositories and worktrees.
 
 By default, scans the current directory and its subdirectories (1 level deep).
-Use --depth to scan deeper, or --recursive for unlimited depth.
+Use --depth to scan deeper, or --recursive for unlimited depth. Hidden
+directories, node_modules, vendor, __pycache__ and venv are skipped, along
+with directories matching scan_ignore in config.yaml. Directory listings
+are cached, so repeat scans only re-read directories that changed.
+
+With --watch, keeps running after the scan and registers new worktrees as
+they appear (e.g. created by agents or scripts), in the scanned directory
+and in worktrees_dir, and unregisters stopped ones whose directory is
+deleted.
 
 Examples:
   grove discover                    # Scan current directory
   grove discover ~/development      # Scan specific directory
   grove discover --depth 2          # Scan 2 levels deep
   grove discover --register         # Register all discovered worktrees
-  grove discover --register --start # Register and start all with default command`,
+  grove discover --register --start # Register and start all with default command
+  grove discover ~/development --watch  # Keep registering new worktrees`,
 	RunE: runDiscover,
 }
 
@@ -38,6 +57,8 @@ func init() {
 	discoverCmd.Flags().Bool("register", false, "Register all discovered worktrees")
 	discoverCmd.Flags().Bool("start", false, "Start all discovered worktrees (implies --register)")
 	discoverCmd.Flags().StringP("command", "c", "", "Command to use when starting (default: from .grove.yaml or prompt)")
+	discoverCmd.Flags().BoolP("watch", "w", false, "Keep watching for new and deleted worktrees (implies --register)")
+	addOutputFlags(discoverCmd)
 	discoverCmd.GroupID = "worktree"
 	rootCmd.AddCommand(discoverCmd)
 }
@@ -51,6 +72,9 @@ type discoveredWorktree struct {
 	Registered bool // true if already in registry
 	Running    bool // true if currently running
 	Port       int  // allocated port if registered
+
+	// Repo is the repository ID (see worktree.RepoID)
+	Repo string
 }
 
 func runDiscover(cmd *cobra.Command, args []string) error {
@@ -69,8 +93,9 @@ func runDiscover(cmd *cobra.Command, args []string) error {
 	register, _ := cmd.Flags().GetBool("register")
 	start, _ := cmd.Flags().GetBool("start")
 	command, _ := cmd.Flags().GetString("command")
+	watch, _ := cmd.Flags().GetBool("watch")
 
-	if start {
+	if start || watch {
 		register = true
 	}
 
@@ -78,20 +103,73 @@ func runDiscover(cmd *cobra.Command, args []string) error {
 		depth = -1 // unlimited
 	}
 
+	// Ctrl+C stops a long scan, and --watch
+	ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if watch {
+		format, err := outputFormat(cmd)
+		if err != nil {
+			return err
+		}
+		if format.IsMachine() {
+			return fmt.Errorf("--watch can't be used with --json or --yaml")
+		}
+		if _, err := discoverAndRegister(ctx, absPath, depth, register, start, command); err != nil {
+			return err
+		}
+		return watchDiscover(ctx, absPath, depth, start, command)
+	}
+
+	return runWithOutput(cmd, func() (any, error) {
+		result, err := discoverAndRegister(ctx, absPath, depth, register, start, command)
+		if result == nil {
+			return nil, err
+		}
+		return result, err
+	})
+}
+
+// discoverAndRegister scans absPath for repositories and optionally
+// registers (and starts) the new ones
+func discoverAndRegister(ctx context.Context, absPath string, depth int, register, start bool, command string) (*output.DiscoverResult, error) {
 	fmt.Printf("Scanning %s for git repositories...\n\n", absPath)
 
 	// Load registry to check existing entries
 	reg, err := registry.Load()
 	if err != nil {
-		return fmt.Errorf("failed to load registry: %w", err)
+		return nil, fmt.Errorf("failed to load registry: %w", err)
 	}
 
 	// Discover worktrees
-	discovered := discoverWorktrees(absPath, depth, reg)
+	scanStart := time.Now()
+	discovered := discoverWorktrees(ctx, absPath, depth, reg)
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("scan cancelled")
+	}
+	metrics.RecordDiscoveryScan(time.Since(scanStart))
+
+	result := &output.DiscoverResult{
+		Path:       absPath,
+		Worktrees:  []output.DiscoveredWorktree{},
+		Registered: []string{},
+	}
+	for _, wt := range discovered {
+		result.Worktrees = append(result.Worktrees, output.DiscoveredWorktree{
+			Name:       wt.Name,
+			Path:       wt.Path,
+			Branch:     wt.Branch,
+			IsWorktree: wt.IsWorktree,
+			HasConfig:  wt.HasConfig,
+			Registered: wt.Registered,
+			Running:    wt.Running,
+			Port:       wt.Port,
+		})
+	}
 
 	if len(discovered) == 0 {
 		fmt.Println("No git repositories found.")
-		return nil
+		return result, nil
 	}
 
 	// Display results
@@ -149,33 +227,25 @@ func runDiscover(cmd *cobra.Command, args []string) error {
 
 	if newCount == 0 {
 		fmt.Println("All discovered repositories are already registered.")
-		return nil
+		return result, nil
 	}
 
 	fmt.Printf("Found %d new repositories.\n", newCount)
+	result.New = newCount
 
 	if !register {
 		fmt.Println("\nRun with --register to add them to grove, or --register --start to also start them.")
-		return nil
+		return result, nil
 	}
 
 	// Register new worktrees
 	fmt.Println("\nRegistering new repositories...")
 
-	allocator := port.NewAllocator(cfg.PortMin, cfg.PortMax)
-
-	for _, wt := range discovered {
+	for i, wt := range discovered {
 		if wt.Registered {
 			continue
 		}
 
-		// Allocate port
-		serverPort, err := allocator.AllocateWithFallback(wt.Name, reg.GetUsedPorts())
-		if err != nil {
-			fmt.Printf("  ✗ %s: failed to allocate port: %v\n", wt.Name, err)
-			continue
-		}
-
 		// Determine command
 		cmdToUse := command
 		if cmdToUse == "" && wt.HasConfig {
@@ -184,22 +254,16 @@ func runDiscover(cmd *cobra.Command, args []string) error {
 			cmdToUse = "" // Will be loaded when starting
 		}
 
-		// Create server entry
-		server := &registry.Server{
-			Name:   wt.Name,
-			Port:   serverPort,
-			Path:   wt.Path,
-			URL:    cfg.ServerURL(wt.Name, serverPort),
-			Status: registry.StatusStopped,
-			Branch: wt.Branch,
-		}
-
-		if err := reg.Set(server); err != nil {
-			fmt.Printf("  ✗ %s: failed to register: %v\n", wt.Name, err)
+		serverPort, err := registerDiscovered(reg, wt)
+		if err != nil {
+			fmt.Printf("  ✗ %s: %v\n", wt.Name, err)
 			continue
 		}
 
 		fmt.Printf("  ✓ %s (port %d)\n", wt.Name, serverPort)
+		result.Worktrees[i].Registered = true
+		result.Worktrees[i].Port = serverPort
+		result.Registered = append(result.Registered, wt.Name)
 
 		if start && cmdToUse != "" {
 			// Start the server
@@ -213,78 +277,254 @@ func runDiscover(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Println("\nDone!")
-	return nil
+	return result, nil
 }
 
-func discoverWorktrees(basePath string, maxDepth int, reg *registry.Registry) []discoveredWorktree {
-	var discovered []discoveredWorktree
-	seen := make(map[string]bool)
+// registerDiscovered registers a discovered worktree as a stopped server
+// with a newly allocated port, which it returns
+func registerDiscovered(reg *registry.Registry, wt discoveredWorktree) (int, error) {
+	serverPort, err := allocatePort(wt.Name, wt.Path, reg.GetUsedPorts())
+	if err != nil {
+		return 0, fmt.Errorf("failed to allocate port: %w", err)
+	}
 
-	var scan func(path string, depth int)
-	scan = func(path string, depth int) {
-		if maxDepth >= 0 && depth > maxDepth {
-			return
+	server := &registry.Server{
+		Name:   wt.Name,
+		Port:   serverPort,
+		Path:   wt.Path,
+		URL:    cfg.ServerURL(wt.Name, serverPort),
+		Status: registry.StatusStopped,
+		Branch: wt.Branch,
+	}
+	if err := reg.Set(server); err != nil {
+		return 0, fmt.Errorf("failed to register: %w", err)
+	}
+	if wt.Repo != "" {
+		reg.RecordRepo(wt.Name, wt.Repo) //nolint:errcheck // The repo only tells same-named worktrees apart
+	}
+	return serverPort, nil
+}
+
+// watchRoot is a directory scanned for worktrees, to depth levels
+type watchRoot struct {
+	Path  string
+	Depth int
+}
+
+// watchRoots are the directories 'grove discover --watch' scans: the one
+// given, and worktrees_dir (<worktrees_dir>/<project>/<branch>)
+func watchRoots(absPath string, depth int) []watchRoot {
+	roots := []watchRoot{{Path: absPath, Depth: depth}}
+	if cfg.WorktreesDir != "" {
+		dir := expandPath(cfg.WorktreesDir)
+		if dir != absPath {
+			roots = append(roots, watchRoot{Path: dir, Depth: 2})
 		}
+	}
+	return roots
+}
+
+// watchDiscover registers new worktrees in the watch roots as they appear,
+// and unregisters stopped ones whose directory is deleted, until
+// ctx is done
+func watchDiscover(ctx context.Context, absPath string, depth int, start bool, command string) error {
+	roots := watchRoots(absPath, depth)
+	fmt.Println("\nWatching for new worktrees (Ctrl+C to stop)...")
+
+	return discovery.Watch(ctx, func() []string { return watchDirs(roots) }, 500*time.Millisecond, func() {
+		if err := reconcileDiscovered(ctx, roots, start, command); err != nil {
+			fmt.Printf("  ✗ %v\n", err)
+		}
+	})
+}
 
-		// Check if this is a git repository
+// watchDirs returns the directories to watch for worktrees appearing in the
+// roots: the directories scanned, plus each repository's .git/worktrees,
+// where 'git worktree add' records worktrees created anywhere
+func watchDirs(roots []watchRoot) []string {
+	var dirs []string
+	var walk func(path string, depth, maxDepth int)
+	walk = func(path string, depth, maxDepth int) {
 		gitPath := filepath.Join(path, ".git")
-		info, err := os.Stat(gitPath)
-		if err == nil {
-			// Found a git repo
-			wt := analyzeGitRepo(path, info.IsDir(), reg)
-			if wt != nil && !seen[wt.Path] {
-				seen[wt.Path] = true
-				discovered = append(discovered, *wt)
-
-				// If it's a main repo, also check for linked worktrees
-				if info.IsDir() {
-					linkedWorktrees := findLinkedWorktrees(path)
-					for _, linked := range linkedWorktrees {
-						if !seen[linked.Path] {
-							seen[linked.Path] = true
-							// Check registry status for linked worktree
-							if server, ok := reg.Get(linked.Name); ok {
-								linked.Registered = true
-								linked.Running = server.IsRunning()
-								linked.Port = server.Port
-							}
-							discovered = append(discovered, linked)
-						}
-					}
+		if info, err := os.Stat(gitPath); err == nil {
+			if info.IsDir() {
+				dirs = append(dirs, gitPath)
+				if fileExists(filepath.Join(gitPath, "worktrees")) {
+					dirs = append(dirs, filepath.Join(gitPath, "worktrees"))
 				}
 			}
-
-			// Don't descend into git repos
 			return
 		}
 
-		// Not a git repo, scan subdirectories
+		dirs = append(dirs, path)
+		if maxDepth >= 0 && depth >= maxDepth {
+			return
+		}
 		entries, err := os.ReadDir(path)
 		if err != nil {
 			return
 		}
-
 		for _, entry := range entries {
-			if !entry.IsDir() {
+			name := entry.Name()
+			if !entry.IsDir() || strings.HasPrefix(name, ".") || name == "node_modules" || name == "vendor" {
 				continue
 			}
+			walk(filepath.Join(path, name), depth+1, maxDepth)
+		}
+	}
 
-			// Skip hidden directories and common non-project dirs
-			name := entry.Name()
-			if strings.HasPrefix(name, ".") ||
-				name == "node_modules" ||
-				name == "vendor" ||
-				name == "__pycache__" ||
-				name == "venv" ||
-				name == ".venv" {
+	for _, root := range roots {
+		walk(root.Path, 0, root.Depth)
+	}
+	return dirs
+}
+
+// reconcileDiscovered registers the worktrees in the roots that aren't
+// registered yet, and unregisters stopped ones under the roots whose
+// directory no longer exists
+func reconcileDiscovered(ctx context.Context, roots []watchRoot, start bool, command string) error {
+	reg, err := registry.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+
+	for _, root := range roots {
+		for _, wt := range discoverWorktrees(ctx, root.Path, root.Depth, reg) {
+			if wt.Registered {
+				continue
+			}
+			serverPort, err := registerDiscovered(reg, wt)
+			if err != nil {
+				fmt.Printf("  ✗ %s: %v\n", wt.Name, err)
 				continue
 			}
+			fmt.Printf("  + %s (port %d)\n", wt.Name, serverPort)
+
+			if start && command != "" {
+				startCmd := exec.Command("grove", "start", command)
+				startCmd.Dir = wt.Path
+				if err := startCmd.Run(); err != nil {
+					fmt.Printf("    ✗ Failed to start: %v\n", err)
+				}
+			}
+		}
+	}
+
+	for _, server := range reg.List() {
+		if server.IsRunning() || fileExists(server.Path) || !underAny(server.Path, roots) {
+			continue
+		}
+		if err := reg.Remove(server.Name); err != nil {
+			fmt.Printf("  ✗ %s: failed to unregister: %v\n", server.Name, err)
+			continue
+		}
+		fmt.Printf("  - %s (deleted)\n", server.Name)
+	}
+	return nil
+}
 
-			scan(filepath.Join(path, name), depth+1)
+// underAny reports whether path is inside one of the roots
+func underAny(path string, roots []watchRoot) bool {
+	for _, root := range roots {
+		if rel, err := filepath.Rel(root.Path, path); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+			return true
 		}
 	}
+	return false
+}
 
-	scan(basePath, 0)
+// discoverWorktrees finds the repositories and worktrees under basePath. If
+// ctx is done it stops early, returning what it found so far.
+func discoverWorktrees(ctx context.Context, basePath string, maxDepth int, reg *registry.Registry) []discoveredWorktree {
+	cache := discovery.LoadCache(scanCachePath())
+	repos, err := discovery.FindRepos(ctx, basePath, discovery.ScanOptions{
+		MaxDepth: maxDepth,
+		Ignore:   cfg.ScanIgnore,
+		Cache:    cache,
+	})
+	if err != nil {
+		return nil
+	}
+
+	// Analyze repositories in parallel; each runs a few git commands
+	type analyzed struct {
+		wt     *discoveredWorktree
+		linked []discoveredWorktree
+	}
+	results := make([]analyzed, len(repos))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, runtime.NumCPU())
+	for i, repo := range repos {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+
+			wt := analyzeGitRepo(repo.Path, !repo.Linked, reg)
+			results[i].wt = wt
+			// If it's a main repo, also check for linked worktrees
+			if wt != nil && !repo.Linked {
+				results[i].linked = findLinkedWorktrees(ctx, repo.Path, wt.Repo, cache)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// An interrupted scan didn't see everything, and saving would drop what
+	// it missed from the cache
+	if ctx.Err() == nil {
+		if err := cache.Save(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to save scan cache: %v\n", err)
+		}
+	}
+
+	var discovered []discoveredWorktree
+	seen := make(map[string]bool)
+	for _, r := range results {
+		if r.wt == nil || seen[r.wt.Path] {
+			continue
+		}
+		seen[r.wt.Path] = true
+		discovered = append(discovered, *r.wt)
+
+		for _, linked := range r.linked {
+			if !seen[linked.Path] {
+				seen[linked.Path] = true
+				// Check registry status for linked worktree
+				if server, ok := reg.Get(linked.Name); ok {
+					linked.Registered = true
+					linked.Running = server.IsRunning()
+					linked.Port = server.Port
+				}
+				discovered = append(discovered, linked)
+			}
+		}
+	}
+
+	// Branches can sanitize to the same name (user/feature_x, user/feature-x),
+	// and different repositories can have the same branches
+	names := make(map[string]string) // name -> repo
+	for i := range discovered {
+		wt := &discovered[i]
+		if otherRepo, ok := names[wt.Name]; ok {
+			wt.Name = worktree.DistinctName(wt.Name, wt.Path, wt.Repo, otherRepo, func(n string) bool {
+				_, taken := names[n]
+				return taken
+			})
+			wt.Registered, wt.Running, wt.Port = false, false, 0
+			if server, ok := reg.Get(wt.Name); ok {
+				wt.Registered = true
+				wt.Running = server.IsRunning()
+				wt.Port = server.Port
+			}
+		}
+		names[wt.Name] = wt.Repo
+	}
 	return discovered
 }
 
@@ -295,20 +535,17 @@ func analyzeGitRepo(path string, isMainRepo bool, reg *registry.Registry) *disco
 		return nil
 	}
 
-	// For main repos (not linked worktrees), use directory name as the server name
-	// This avoids conflicts when multiple projects are on the same branch (e.g., "main")
+	// Main repos (not linked worktrees) are named after their directory,
+	// which avoids conflicts when multiple projects are on the same branch (e.g., "main")
 	name := wt.Name
-	if !wt.IsWorktree {
-		dirName := filepath.Base(path)
-		name = worktree.Sanitize(dirName)
-	}
 
 	discovered := &discoveredWorktree{
 		Path:       wt.Path,
 		Name:       name,
 		Branch:     wt.Branch,
+		Repo:       wt.Repo,
 		IsWorktree: wt.IsWorktree,
-		HasConfig:  fileExists(filepath.Join(path, ".grove.yaml")),
+		HasConfig:  project.Exists(path),
 	}
 
 	// Check if already registered
@@ -321,48 +558,38 @@ func analyzeGitRepo(path string, isMainRepo bool, reg *registry.Registry) *disco
 	return discovered
 }
 
-func findLinkedWorktrees(mainRepoPath string) []discoveredWorktree {
+// findLinkedWorktrees lists the linked worktrees of the main repo at
+// mainRepoPath, whose repository ID is repo
+func findLinkedWorktrees(ctx context.Context, mainRepoPath, repo string, cache *discovery.Cache) []discoveredWorktree {
 	var worktrees []discoveredWorktree
 
-	// Use git worktree list to find linked worktrees
-	cmd := exec.Command("git", "worktree", "list", "--porcelain")
-	cmd.Dir = mainRepoPath
-	output, err := cmd.Output()
+	list, err := cache.Worktrees(ctx, mainRepoPath)
 	if err != nil {
 		return worktrees
 	}
 
-	lines := strings.Split(string(output), "\n")
-	var currentPath, currentBranch string
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-
-		if strings.HasPrefix(line, "worktree ") {
-			currentPath = strings.TrimPrefix(line, "worktree ")
-		} else if strings.HasPrefix(line, "branch ") {
-			currentBranch = strings.TrimPrefix(line, "branch refs/heads/")
-		} else if line == "" && currentPath != "" {
-			// End of entry
-			if currentPath != mainRepoPath {
-				// This is a linked worktree
-				name := worktree.Sanitize(currentBranch)
-				worktrees = append(worktrees, discoveredWorktree{
-					Path:       currentPath,
-					Name:       name,
-					Branch:     currentBranch,
-					IsWorktree: true,
-					HasConfig:  fileExists(filepath.Join(currentPath, ".grove.yaml")),
-				})
-			}
-			currentPath = ""
-			currentBranch = ""
+	for _, entry := range list {
+		if entry.Path == mainRepoPath {
+			continue
 		}
+		worktrees = append(worktrees, discoveredWorktree{
+			Path:       entry.Path,
+			Name:       worktree.NameFor(mainRepoPath, entry.Branch, entry.Path),
+			Branch:     entry.Branch,
+			Repo:       repo,
+			IsWorktree: true,
+			HasConfig:  project.Exists(entry.Path),
+		})
 	}
 
 	return worktrees
 }
 
+// scanCachePath is where discover caches directory listings between scans
+func scanCachePath() string {
+	return filepath.Join(config.ConfigDir(), "scan-cache.json")
+}
+
 func fileExists(path string) bool {
 	_, err := os.Stat(path)
 	return err == nil
@@ -0,0 +1,258 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/dns"
+	"github.com/spf13/cobra"
+)
+
+var dnsCmd = &cobra.Command{
+	Use:   "dns",
+	Short: "Resolve the configured TLD locally",
+	Long: `Configure local DNS so every name under the configured TLD resolves to
+127.0.0.1, without editing /etc/hosts.
+
+grove runs a small DNS responder on 127.0.0.1:<dns_port> (default 5354) and
+points the system resolver at it for the TLD only:
+  macOS:  /etc/resolver/<tld>
+  Linux:  systemd-resolved drop-in in /etc/systemd/resolved.conf.d/
+
+Not needed for the default 'localhost' TLD, which always resolves locally.
+
+Examples:
+  sudo grove dns setup      # Configure the system resolver (once)
+  grove dns start           # Start the DNS responder
+  grove dns status          # Check resolver config and responder
+  sudo grove dns teardown   # Remove the system resolver config`,
+}
+
+var dnsSetupCmd = &cobra.Command{
+	Use:   "setup",
+	Short: "Configure the system resolver for the TLD (requires sudo)",
+	RunE:  runDNSSetup,
+}
+
+var dnsTeardownCmd = &cobra.Command{
+	Use:   "teardown",
+	Short: "Remove the system resolver configuration (requires sudo)",
+	RunE:  runDNSTeardown,
+}
+
+var dnsStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start the DNS responder",
+	RunE:  runDNSStart,
+}
+
+var dnsStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the DNS responder",
+	RunE:  runDNSStop,
+}
+
+var dnsStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show DNS resolver status",
+	RunE:  runDNSStatus,
+}
+
+func init() {
+	dnsStartCmd.Flags().BoolP("foreground", "f", false, "Run in foreground")
+
+	dnsCmd.AddCommand(dnsSetupCmd)
+	dnsCmd.AddCommand(dnsTeardownCmd)
+	dnsCmd.AddCommand(dnsStartCmd)
+	dnsCmd.AddCommand(dnsStopCmd)
+	dnsCmd.AddCommand(dnsStatusCmd)
+
+	dnsCmd.GroupID = "proxy"
+	rootCmd.AddCommand(dnsCmd)
+}
+
+// dnsPIDFile returns the path of the DNS responder's PID file
+func dnsPIDFile() string {
+	return filepath.Join(config.ConfigDir(), "dns.pid")
+}
+
+// dnsResponderPID returns the PID of the running DNS responder, or 0
+func dnsResponderPID() int {
+	data, err := os.ReadFile(dnsPIDFile())
+	if err != nil {
+		return 0
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || !isProcessRunning(pid) {
+		return 0
+	}
+	return pid
+}
+
+func runDNSSetup(cmd *cobra.Command, args []string) error {
+	if cfg.TLD == "localhost" {
+		fmt.Println("The 'localhost' TLD already resolves to 127.0.0.1; no DNS setup needed.")
+		return nil
+	}
+
+	path, content, err := dns.ResolverConfig(runtime.GOOS, cfg.TLD, cfg.DNSPort)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return dnsPermissionError(err, "setup")
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return dnsPermissionError(err, "setup")
+	}
+	fmt.Printf("Wrote %s\n", path)
+
+	reloadResolver()
+
+	fmt.Printf("\n*.%s will resolve to 127.0.0.1 once the responder is running.\n", cfg.TLD)
+	fmt.Println("Start it with: grove dns start")
+	return nil
+}
+
+func runDNSTeardown(cmd *cobra.Command, args []string) error {
+	path, _, err := dns.ResolverConfig(runtime.GOOS, cfg.TLD, cfg.DNSPort)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			fmt.Printf("No resolver configuration at %s\n", path)
+			return nil
+		}
+		return dnsPermissionError(err, "teardown")
+	}
+	fmt.Printf("Removed %s\n", path)
+
+	reloadResolver()
+	return nil
+}
+
+// dnsPermissionError adds a sudo hint to permission errors
+func dnsPermissionError(err error, subcommand string) error {
+	if os.IsPermission(err) {
+		return fmt.Errorf("%w\nWriting system resolver config requires root: sudo grove dns %s", err, subcommand)
+	}
+	return err
+}
+
+// reloadResolver makes the system resolver pick up config changes
+func reloadResolver() {
+	reload := dns.ReloadCommand(runtime.GOOS)
+	if reload == nil {
+		return
+	}
+	if output, err := exec.Command(reload[0], reload[1:]...).CombinedOutput(); err != nil {
+		fmt.Printf("Warning: failed to reload resolver (%s): %v %s\n", strings.Join(reload, " "), err, strings.TrimSpace(string(output)))
+	}
+}
+
+func runDNSStart(cmd *cobra.Command, args []string) error {
+	if pid := dnsResponderPID(); pid > 0 {
+		return fmt.Errorf("DNS responder is already running (PID: %d)", pid)
+	}
+
+	addr := fmt.Sprintf("127.0.0.1:%d", cfg.DNSPort)
+	foreground, _ := cmd.Flags().GetBool("foreground")
+
+	if foreground {
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+
+		if err := os.WriteFile(dnsPIDFile(), []byte(strconv.Itoa(os.Getpid())), 0644); err != nil {
+			return fmt.Errorf("failed to write PID file: %w", err)
+		}
+		defer os.Remove(dnsPIDFile())
+
+		fmt.Printf("Resolving *.%s on %s (Ctrl+C to stop)...\n", cfg.TLD, addr)
+		return dns.Serve(ctx, addr, cfg.TLD)
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to get executable: %w", err)
+	}
+
+	logFile, err := os.OpenFile(filepath.Join(config.ConfigDir(), "dns.log"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open DNS log: %w", err)
+	}
+	defer logFile.Close()
+
+	daemon := exec.Command(executable, "dns", "start", "--foreground")
+	daemon.Stdout = logFile
+	daemon.Stderr = logFile
+	daemon.SysProcAttr = &syscall.SysProcAttr{
+		Setpgid: true,
+	}
+
+	if err := daemon.Start(); err != nil {
+		return fmt.Errorf("failed to start DNS responder: %w", err)
+	}
+	if err := daemon.Process.Release(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to release DNS responder: %v\n", err)
+	}
+
+	fmt.Printf("DNS responder started (PID: %d) on %s\n", daemon.Process.Pid, addr)
+	return nil
+}
+
+func runDNSStop(cmd *cobra.Command, args []string) error {
+	pid := dnsResponderPID()
+	if pid == 0 {
+		fmt.Println("DNS responder is not running")
+		return nil
+	}
+
+	if err := syscall.Kill(pid, syscall.SIGTERM); err != nil {
+		return fmt.Errorf("failed to stop DNS responder: %w", err)
+	}
+	fmt.Println("DNS responder stopped")
+	return nil
+}
+
+func runDNSStatus(cmd *cobra.Command, args []string) error {
+	fmt.Printf("TLD:        %s\n", cfg.TLD)
+
+	if path, content, err := dns.ResolverConfig(runtime.GOOS, cfg.TLD, cfg.DNSPort); err != nil {
+		fmt.Printf("Resolver:   %v\n", err)
+	} else if existing, err := os.ReadFile(path); err != nil {
+		fmt.Printf("Resolver:   not configured (run 'sudo grove dns setup')\n")
+	} else if string(existing) != content {
+		fmt.Printf("Resolver:   %s (out of date, re-run 'sudo grove dns setup')\n", path)
+	} else {
+		fmt.Printf("Resolver:   %s\n", path)
+	}
+
+	if pid := dnsResponderPID(); pid > 0 {
+		fmt.Printf("Responder:  running (PID: %d) on 127.0.0.1:%d\n", pid, cfg.DNSPort)
+	} else {
+		fmt.Println("Responder:  stopped (run 'grove dns start')")
+	}
+
+	// End-to-end check through the system resolver
+	probe := "grove-check." + cfg.TLD
+	if addrs, err := net.LookupHost(probe); err == nil && len(addrs) > 0 {
+		fmt.Printf("Lookup:     %s -> %s\n", probe, strings.Join(addrs, ", "))
+	} else {
+		fmt.Printf("Lookup:     %s does not resolve\n", probe)
+	}
+
+	return nil
+}
@@ -2,9 +2,11 @@ package cli
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
 
 	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/output"
 	"github.com/iheanyi/grove/internal/port"
 	"github.com/iheanyi/grove/internal/registry"
 	"github.com/spf13/cobra"
@@ -24,119 +26,137 @@ This command checks:
 	RunE: runDoctor,
 }
 
+func init() {
+	addOutputFlags(doctorCmd)
+}
+
 func runDoctor(cmd *cobra.Command, args []string) error {
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	result := doctorChecks()
+	if format.IsMachine() {
+		return output.Write(os.Stdout, format, result)
+	}
+
 	fmt.Println("grove doctor")
 	fmt.Println("=========")
 	fmt.Println()
 
-	allGood := true
-	needsProxy := cfg.IsSubdomainMode()
+	for _, c := range result.Checks {
+		fmt.Printf("%s... %s\n", c.Name, c.Result)
+		if c.Hint != "" {
+			fmt.Printf("  %s\n", c.Hint)
+		}
+	}
+
+	if len(result.Servers) > 0 {
+		fmt.Println()
+		fmt.Println("Running servers:")
+		for _, c := range result.Servers {
+			fmt.Printf("  %s... %s\n", c.Name, c.Result)
+		}
+	}
+
+	fmt.Println()
+	if result.OK {
+		fmt.Println("All checks passed!")
+	} else {
+		fmt.Println("Some issues found. See above for details.")
+	}
+
+	return nil
+}
+
+// doctorChecks runs the diagnostics
+func doctorChecks() output.DoctorResult {
+	result := output.DoctorResult{OK: true, Checks: []output.Check{}, Servers: []output.Check{}}
+	check := func(name, status, res, hint string) {
+		result.Checks = append(result.Checks, output.Check{Name: name, Status: status, Result: res, Hint: hint})
+		if status == "fail" {
+			result.OK = false
+		}
+	}
+	needsProxy := cfg.UsesProxy()
 
 	// Check 1: Config directory
-	fmt.Print("Config directory... ")
 	if err := config.EnsureDirectories(); err != nil {
-		fmt.Printf("FAIL (%v)\n", err)
-		allGood = false
+		check("Config directory", "fail", fmt.Sprintf("FAIL (%v)", err), "")
 	} else {
-		fmt.Printf("OK (%s)\n", config.ConfigDir())
+		check("Config directory", "ok", fmt.Sprintf("OK (%s)", config.ConfigDir()), "")
 	}
 
 	// Check 2: Caddy installed (only relevant in subdomain mode)
 	if needsProxy {
-		fmt.Print("Caddy installed... ")
 		caddyPath, err := exec.LookPath("caddy")
 		if err != nil {
-			fmt.Println("NOT FOUND")
-			fmt.Println("  Run: brew install caddy (macOS) or apt install caddy (Linux)")
-			allGood = false
+			check("Caddy installed", "fail", "NOT FOUND", "Run: brew install caddy (macOS) or apt install caddy (Linux)")
 		} else {
-			fmt.Printf("OK (%s)\n", caddyPath)
+			check("Caddy installed", "ok", fmt.Sprintf("OK (%s)", caddyPath), "")
 		}
 	} else {
-		fmt.Println("Caddy installed... SKIPPED (not needed in port mode)")
+		check("Caddy installed", "skipped", "SKIPPED (not needed in port mode)", "")
 	}
 
 	// Check 3: Registry loadable
-	fmt.Print("Registry... ")
 	reg, err := registry.Load()
 	if err != nil {
-		fmt.Printf("FAIL (%v)\n", err)
-		allGood = false
+		check("Registry", "fail", fmt.Sprintf("FAIL (%v)", err), "")
 	} else {
-		fmt.Printf("OK (%d servers registered)\n", len(reg.List()))
+		check("Registry", "ok", fmt.Sprintf("OK (%d servers registered)", len(reg.List())), "")
 	}
 
 	// Check 4: Proxy status (only relevant in subdomain mode)
 	if needsProxy {
-		fmt.Print("Proxy... ")
 		if reg != nil {
 			proxy := reg.GetProxy()
 			if proxy.IsRunning() && isProcessRunning(proxy.PID) {
-				fmt.Printf("RUNNING (PID: %d)\n", proxy.PID)
+				check("Proxy", "ok", fmt.Sprintf("RUNNING (PID: %d)", proxy.PID), "")
 			} else {
-				fmt.Println("NOT RUNNING")
-				fmt.Println("  Run: grove proxy start")
-				allGood = false
+				check("Proxy", "fail", "NOT RUNNING", "Run: grove proxy start")
 			}
 		} else {
-			fmt.Println("UNKNOWN (registry not loaded)")
+			check("Proxy", "warn", "UNKNOWN (registry not loaded)", "")
 		}
 
-		// Check 5: HTTP port available (or in use by proxy)
-		fmt.Printf("HTTP port (%d)... ", cfg.ProxyHTTPPort)
-		if port.IsAvailable(cfg.ProxyHTTPPort) {
-			fmt.Println("AVAILABLE")
-		} else if reg != nil && reg.GetProxy().IsRunning() {
-			fmt.Println("IN USE (by proxy)")
-		} else {
-			fmt.Println("IN USE (by another process)")
-			fmt.Printf("  Another process is using this port. Check with: lsof -i :%d\n", cfg.ProxyHTTPPort)
-			allGood = false
-		}
-
-		// Check 6: HTTPS port available (or in use by proxy)
-		fmt.Printf("HTTPS port (%d)... ", cfg.ProxyHTTPSPort)
-		if port.IsAvailable(cfg.ProxyHTTPSPort) {
-			fmt.Println("AVAILABLE")
-		} else if reg != nil && reg.GetProxy().IsRunning() {
-			fmt.Println("IN USE (by proxy)")
-		} else {
-			fmt.Println("IN USE (by another process)")
-			fmt.Printf("  Another process is using this port. Check with: lsof -i :%d\n", cfg.ProxyHTTPSPort)
-			allGood = false
+		// Checks 5 and 6: HTTP and HTTPS ports available (or in use by proxy)
+		for _, p := range []struct {
+			name string
+			port int
+		}{{"HTTP", cfg.ProxyHTTPPort}, {"HTTPS", cfg.ProxyHTTPSPort}} {
+			name := fmt.Sprintf("%s port (%d)", p.name, p.port)
+			if port.IsAvailable(p.port) {
+				check(name, "ok", "AVAILABLE", "")
+			} else if reg != nil && reg.GetProxy().IsRunning() {
+				check(name, "ok", "IN USE (by proxy)", "")
+			} else {
+				check(name, "fail", "IN USE (by another process)",
+					fmt.Sprintf("Another process is using this port. Check with: lsof -i :%d", p.port))
+			}
 		}
 	} else {
-		fmt.Println("Proxy... SKIPPED (not needed in port mode)")
+		check("Proxy", "skipped", "SKIPPED (not needed in port mode)", "")
 	}
 
 	// Check 7: Running servers health
 	if reg != nil {
-		running := reg.ListRunning()
-		if len(running) > 0 {
-			fmt.Println()
-			fmt.Println("Running servers:")
-			for _, s := range running {
-				fmt.Printf("  %s (port %d)... ", s.Name, s.Port)
-				if isProcessRunning(s.PID) {
-					if port.IsListening(s.Port) {
-						fmt.Println("HEALTHY")
-					} else {
-						fmt.Println("PROCESS RUNNING, PORT NOT LISTENING")
-					}
+		for _, s := range reg.ListRunning() {
+			c := output.Check{Name: fmt.Sprintf("%s (port %d)", s.Name, s.Port)}
+			if isProcessRunning(s.PID) {
+				if port.IsListening(s.Port) {
+					c.Status, c.Result = "ok", "HEALTHY"
 				} else {
-					fmt.Println("PROCESS NOT RUNNING (stale entry)")
-					allGood = false
+					c.Status, c.Result = "warn", "PROCESS RUNNING, PORT NOT LISTENING"
 				}
+			} else {
+				c.Status, c.Result = "fail", "PROCESS NOT RUNNING (stale entry)"
+				result.OK = false
 			}
+			result.Servers = append(result.Servers, c)
 		}
 	}
 
-	fmt.Println()
-	if allGood {
-		fmt.Println("All checks passed!")
-	} else {
-		fmt.Println("Some issues found. See above for details.")
-	}
-
-	return nil
+	return result
 }
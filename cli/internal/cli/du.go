@@ -0,0 +1,272 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/iheanyi/grove/internal/diskusage"
+	"github.com/iheanyi/grove/internal/output"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/styles"
+	"github.com/iheanyi/grove/internal/usage"
+	"github.com/iheanyi/grove/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// duScanners is how many worktrees are scanned at once
+	duScanners = 4
+	// Worktrees at least duLargeSize and idle for duIdleAfter are
+	// suggested for 'grove clean'
+	duLargeSize = 500 << 20
+	duIdleAfter = 30 * 24 * time.Hour
+)
+
+var duCmd = &cobra.Command{
+	Use:   "du [name]",
+	Short: "Show the disk space worktrees use",
+	Long: `Show the disk space each worktree uses, with how much of it is dependency
+directories (node_modules, Rust/Maven target, vendor, .venv), and totals for
+each repository.
+
+Below the table are suggestions: ways to share the dependencies that several
+worktrees of a repository install separately (e.g. pnpm's store or a shared
+Cargo target directory), and large worktrees idle for a month that
+'grove clean' could remove.
+
+Files hardlinked within a worktree count once; hardlinks between worktrees
+count in each.
+
+Examples:
+  grove du                  # Every registered worktree
+  grove du feature-auth     # One worktree
+  grove du --repo myapp     # One repository's worktrees
+  grove du --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDu,
+}
+
+func init() {
+	duCmd.Flags().String("repo", "", "Only worktrees of this repository")
+	addOutputFlags(duCmd)
+}
+
+// duWorktree is a worktree to scan
+type duWorktree struct {
+	ws       *registry.Workspace
+	repo     string
+	repoPath string
+	usage    diskusage.Usage
+	activity time.Time
+	err      error
+}
+
+func runDu(cmd *cobra.Command, args []string) error {
+	repoFilter, _ := cmd.Flags().GetString("repo")
+
+	reg, err := registry.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+
+	var workspaces []*registry.Workspace
+	if len(args) > 0 {
+		ws, ok := reg.GetWorkspace(args[0])
+		if !ok {
+			return fmt.Errorf("worktree '%s' not found", args[0])
+		}
+		workspaces = []*registry.Workspace{ws}
+	} else {
+		workspaces = reg.ListWorkspaces()
+	}
+
+	var worktrees []*duWorktree
+	for _, ws := range workspaces {
+		if _, err := os.Stat(ws.Path); err != nil {
+			continue
+		}
+		wt := &duWorktree{ws: ws, repo: duRepoName(ws), repoPath: ws.MainRepo}
+		if wt.repoPath == "" {
+			wt.repoPath = ws.Path
+		}
+		if repoFilter != "" && wt.repo != repoFilter && ws.Repo != repoFilter {
+			continue
+		}
+		worktrees = append(worktrees, wt)
+	}
+	if len(worktrees) == 0 {
+		return fmt.Errorf("no worktrees found")
+	}
+
+	return runWithOutput(cmd, func() (any, error) {
+		fmt.Fprintf(os.Stderr, "Scanning %d worktree(s)...\n", len(worktrees))
+		scanWorktrees(worktrees)
+
+		report := duReport(worktrees)
+		printDuReport(report)
+		return report, nil
+	})
+}
+
+// duRepoName returns the name of a workspace's repository, e.g. "myapp"
+func duRepoName(ws *registry.Workspace) string {
+	switch {
+	case ws.Repo != "":
+		return worktree.RepoName(ws.Repo)
+	case ws.MainRepo != "":
+		return filepath.Base(ws.MainRepo)
+	default:
+		return filepath.Base(ws.Path)
+	}
+}
+
+// scanWorktrees measures the worktrees, a few at a time
+func scanWorktrees(worktrees []*duWorktree) {
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, duScanners)
+	for _, wt := range worktrees {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+			wt.usage, wt.err = diskusage.Scan(wt.ws.Path)
+			wt.activity = lastActivity(wt.ws)
+		}()
+	}
+	wg.Wait()
+}
+
+// duReport groups the scanned worktrees by repository, largest first, and
+// collects advice
+func duReport(worktrees []*duWorktree) output.DiskUsageReport {
+	report := output.DiskUsageReport{Repos: []output.RepoDiskUsage{}, Advice: []string{}}
+
+	byRepo := make(map[string][]*duWorktree)
+	for _, wt := range worktrees {
+		if wt.err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s: %v\n", wt.ws.Name, wt.err)
+			continue
+		}
+		byRepo[wt.repo] = append(byRepo[wt.repo], wt)
+	}
+
+	var stale []string
+	for repo, wts := range byRepo {
+		sort.Slice(wts, func(i, j int) bool { return wts[i].usage.Total > wts[j].usage.Total })
+		r := output.RepoDiskUsage{Repo: repo, Worktrees: []output.WorktreeDiskUsage{}}
+		usages := make([]diskusage.Usage, 0, len(wts))
+		for _, wt := range wts {
+			r.TotalBytes += wt.usage.Total
+			r.Worktrees = append(r.Worktrees, output.WorktreeDiskUsage{
+				Name:         wt.ws.Name,
+				Path:         wt.ws.Path,
+				Usage:        wt.usage,
+				LastActivity: output.TimePtr(wt.activity),
+			})
+			usages = append(usages, wt.usage)
+
+			if wt.usage.Total >= duLargeSize && wt.ws.Path != wt.repoPath && !wt.activity.IsZero() && time.Since(wt.activity) > duIdleAfter {
+				stale = append(stale, fmt.Sprintf("%s (%s, idle %s)", wt.ws.Name, usage.FormatBytes(wt.usage.Total), formatAge(time.Since(wt.activity))))
+			}
+		}
+		report.TotalBytes += r.TotalBytes
+		report.Repos = append(report.Repos, r)
+
+		for _, advice := range diskusage.Advice(wts[0].repoPath, usages) {
+			report.Advice = append(report.Advice, repo+": "+advice)
+		}
+	}
+	sort.Slice(report.Repos, func(i, j int) bool { return report.Repos[i].TotalBytes > report.Repos[j].TotalBytes })
+	sort.Strings(report.Advice)
+
+	if len(stale) > 0 {
+		sort.Strings(stale)
+		report.Advice = append(report.Advice, fmt.Sprintf("Large and idle for %s: %s; remove them with 'grove clean --older-than %s'",
+			formatAge(duIdleAfter), strings.Join(stale, ", "), formatAge(duIdleAfter)))
+	}
+	return report
+}
+
+func printDuReport(report output.DiskUsageReport) {
+	// Only the dependency kinds some worktree has get a column
+	var kinds []string
+	for _, kind := range diskusage.Kinds {
+		for _, r := range report.Repos {
+			found := false
+			for _, wt := range r.Worktrees {
+				if wt.Usage.Deps[kind] > 0 {
+					found = true
+					break
+				}
+			}
+			if found {
+				kinds = append(kinds, kind)
+				break
+			}
+		}
+	}
+
+	for i, r := range report.Repos {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s  %s\n", styles.NameStyle.Render(r.Repo), styles.StatsStyle.Render(usage.FormatBytes(r.TotalBytes)))
+
+		rows := make([][]string, 0, len(r.Worktrees))
+		for _, wt := range r.Worktrees {
+			row := []string{wt.Name, usage.FormatBytes(wt.Usage.Total)}
+			for _, kind := range kinds {
+				size := "-"
+				if wt.Usage.Deps[kind] > 0 {
+					size = usage.FormatBytes(wt.Usage.Deps[kind])
+				}
+				row = append(row, size)
+			}
+			idle := "-"
+			if wt.LastActivity != nil {
+				idle = formatAge(time.Since(*wt.LastActivity))
+			}
+			rows = append(rows, append(row, idle))
+		}
+
+		headers := []string{"NAME", "TOTAL"}
+		for _, kind := range kinds {
+			headers = append(headers, strings.ToUpper(kind))
+		}
+		t := table.New().
+			Border(lipgloss.NormalBorder()).
+			BorderRow(false).
+			BorderColumn(false).
+			BorderTop(false).
+			BorderBottom(false).
+			BorderLeft(false).
+			BorderRight(false).
+			Headers(append(headers, "IDLE")...).
+			Rows(rows...).
+			StyleFunc(func(row, col int) lipgloss.Style {
+				if row == table.HeaderRow {
+					return styles.HeaderStyle
+				}
+				return styles.CellStyle
+			})
+		fmt.Println(t)
+	}
+
+	if len(report.Repos) > 1 {
+		fmt.Printf("\nTotal: %s\n", usage.FormatBytes(report.TotalBytes))
+	}
+	if len(report.Advice) > 0 {
+		fmt.Println("\nSuggestions:")
+		for _, advice := range report.Advice {
+			fmt.Printf("  • %s\n", advice)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iheanyi/grove/internal/diskusage"
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+func TestDuReport(t *testing.T) {
+	main := t.TempDir()
+	if err := os.WriteFile(filepath.Join(main, "package-lock.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	npm := func(size uint64) diskusage.Usage {
+		return diskusage.Usage{Total: size, Deps: map[string]uint64{diskusage.NodeModules: size / 2}}
+	}
+	worktrees := []*duWorktree{
+		{ws: &registry.Workspace{Name: "myapp", Path: main}, repo: "myapp", repoPath: main, usage: npm(300 << 20), activity: time.Now()},
+		{ws: &registry.Workspace{Name: "old", Path: "/src/old"}, repo: "myapp", repoPath: main, usage: npm(1 << 30), activity: time.Now().Add(-60 * 24 * time.Hour)},
+		{ws: &registry.Workspace{Name: "tool", Path: "/src/tool"}, repo: "tool", repoPath: "/src/tool", usage: diskusage.Usage{Total: 1 << 20}},
+	}
+
+	report := duReport(worktrees)
+	if len(report.Repos) != 2 || report.Repos[0].Repo != "myapp" {
+		t.Fatalf("repos = %+v, want myapp first", report.Repos)
+	}
+	if got := report.Repos[0].Worktrees[0].Name; got != "old" {
+		t.Errorf("largest worktree = %s, want old", got)
+	}
+	if want := uint64(1<<30 + 300<<20 + 1<<20); report.TotalBytes != want {
+		t.Errorf("total = %d, want %d", report.TotalBytes, want)
+	}
+	if len(report.Advice) != 2 ||
+		!strings.HasPrefix(report.Advice[0], "myapp: node_modules takes") ||
+		!strings.Contains(report.Advice[1], "old (1.0 GB, idle 60d)") {
+		t.Errorf("advice = %q", report.Advice)
+	}
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/iheanyi/grove/internal/output"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/styles"
+	"github.com/iheanyi/grove/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var envCmd = &cobra.Command{
+	Use:   "env [name]",
+	Short: "Show the environment grove gives a worktree's server",
+	Long: `Show the variables 'grove start' sets for a worktree's server, on top of
+the environment grove itself runs in.
+
+Variables are resolved in order of precedence, highest first:
+
+  grove        PORT and GROVE_URL (or url_var)
+  .grove.yaml  env, then the database and depends_on URLs
+  <file>       env_files in .grove.yaml, later files over earlier ones
+               (missing files are skipped, so .env.local can override
+               .env in only some worktrees)
+
+Use --origin to see where each variable comes from.
+
+Examples:
+  grove env                    # Variables for the current worktree
+  grove env feature-auth --origin
+  grove env --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runEnv,
+}
+
+func init() {
+	envCmd.Flags().Bool("origin", false, "Show where each variable is set")
+	addOutputFlags(envCmd)
+}
+
+func runEnv(cmd *cobra.Command, args []string) error {
+	origin, _ := cmd.Flags().GetBool("origin")
+
+	var name string
+	if len(args) > 0 {
+		name = args[0]
+	} else {
+		wt, err := worktree.Detect()
+		if err != nil {
+			return fmt.Errorf("failed to detect worktree: %w", err)
+		}
+		name = wt.Name
+	}
+
+	reg, err := registry.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+
+	env, err := worktreeEnv(reg, name, nil)
+	if err != nil {
+		return err
+	}
+
+	result := output.EnvResult{Name: name, Vars: []output.EnvVar{}}
+	for k, v := range env {
+		result.Vars = append(result.Vars, output.EnvVar{Name: k, Value: v.Value, Source: v.Source})
+	}
+	sort.Slice(result.Vars, func(i, j int) bool { return result.Vars[i].Name < result.Vars[j].Name })
+
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	if format.IsMachine() {
+		return output.Write(os.Stdout, format, result)
+	}
+
+	for _, v := range result.Vars {
+		if origin {
+			fmt.Printf("%s=%s %s\n", v.Name, v.Value, styles.MutedStyle.Render("# "+v.Source))
+		} else {
+			fmt.Printf("%s=%s\n", v.Name, v.Value)
+		}
+	}
+	return nil
+}
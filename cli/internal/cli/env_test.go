@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/iheanyi/grove/internal/project"
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+func TestServerEnv_Precedence(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("PORT=1\nAPP_ENV=file\nFROM_FILE=yes\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	server := &registry.Server{Name: "feature", Path: dir, Port: 3100, URL: "http://localhost:3100"}
+	projConfig := &project.Config{
+		EnvFiles: []string{".env"},
+		Env:      map[string]string{"APP_ENV": "yaml", "GROVE_URL": "http://wrong"},
+	}
+	env := serverEnv(server, projConfig, server.Port)
+
+	// exec keeps the last value of a duplicated variable
+	last := func(name string) string {
+		value := ""
+		for _, kv := range env {
+			if v, ok := strings.CutPrefix(kv, name+"="); ok {
+				value = v
+			}
+		}
+		return value
+	}
+	want := map[string]string{
+		"PORT":      "3100",
+		"GROVE_URL": "http://localhost:3100",
+		"APP_ENV":   "yaml",
+		"FROM_FILE": "yes",
+	}
+	for name, value := range want {
+		if got := last(name); got != value {
+			t.Errorf("%s = %q, want %q (env %v)", name, got, value, env)
+		}
+	}
+
+	// Workers without a port don't get PORT from grove
+	if env := serverEnv(server, &project.Config{}, 0); slices.ContainsFunc(env, func(kv string) bool { return strings.HasPrefix(kv, "PORT=") }) {
+		t.Errorf("expected no PORT without a port, got %v", env)
+	}
+}
@@ -0,0 +1,412 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export grove's config, registry and templates to a file",
+	Long: `Export grove's state to a single JSON file, to move it to another machine
+with 'grove import' or share a team baseline:
+
+  - config.yaml
+  - the registry: worktrees, their ports, tags and server commands (without
+    PIDs, logs or other runtime state)
+  - user templates
+
+Examples:
+  grove export -o grove-state.json
+  grove export --no-registry -o team.json   # Config and templates only
+  grove export | ssh newlaptop grove import -`,
+	Args: cobra.NoArgs,
+	RunE: runExport,
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import config, registry and templates from 'grove export'",
+	Long: `Import grove's state from a file written by 'grove export' ('-' reads stdin).
+
+Paths are remapped for this machine: the exporting user's home directory
+becomes yours, and --map replaces other prefixes. Worktrees that still
+don't exist are prompted for (e.g. ~/code moved to ~/src), unless --yes.
+
+Existing config, templates and registry entries are kept unless --force.
+Imported servers are stopped; ports already in use here are reallocated.
+
+Examples:
+  grove import grove-state.json
+  grove import grove-state.json --map /Users/me/code=/home/me/src
+  grove import team.json --dry-run`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImport,
+}
+
+func init() {
+	exportCmd.Flags().StringP("output", "o", "", "File to write (default: stdout)")
+	exportCmd.Flags().Bool("no-config", false, "Leave out config.yaml")
+	exportCmd.Flags().Bool("no-registry", false, "Leave out the registry")
+	exportCmd.Flags().Bool("no-templates", false, "Leave out user templates")
+
+	importCmd.Flags().StringArray("map", nil, "Replace a path prefix, as old=new (repeatable)")
+	importCmd.Flags().BoolP("yes", "y", false, "Don't prompt for missing paths")
+	importCmd.Flags().Bool("force", false, "Overwrite existing config, templates and registry entries")
+	importCmd.Flags().Bool("dry-run", false, "Show what would be imported without changing anything")
+}
+
+// stateVersion is the version of the export format
+const stateVersion = 1
+
+// stateBundle is grove's exported state
+type stateBundle struct {
+	Version    int                   `json:"version"`
+	ExportedAt time.Time             `json:"exported_at"`
+	Home       string                `json:"home,omitempty"`
+	Config     string                `json:"config,omitempty"`
+	Workspaces []*registry.Workspace `json:"workspaces,omitempty"`
+	Templates  map[string]string     `json:"templates,omitempty"`
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	outputPath, _ := cmd.Flags().GetString("output")
+	noConfig, _ := cmd.Flags().GetBool("no-config")
+	noRegistry, _ := cmd.Flags().GetBool("no-registry")
+	noTemplates, _ := cmd.Flags().GetBool("no-templates")
+
+	home, _ := os.UserHomeDir()
+	bundle := &stateBundle{Version: stateVersion, ExportedAt: time.Now(), Home: home}
+
+	if !noConfig {
+		data, err := os.ReadFile(configFilePath())
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read config: %w", err)
+		}
+		bundle.Config = string(data)
+	}
+
+	if !noRegistry {
+		reg, err := registry.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load registry: %w", err)
+		}
+		for _, ws := range reg.ListWorkspaces() {
+			if ws.Server != nil && ws.Server.Ephemeral {
+				continue
+			}
+			bundle.Workspaces = append(bundle.Workspaces, exportedWorkspace(ws))
+		}
+		sort.Slice(bundle.Workspaces, func(i, j int) bool { return bundle.Workspaces[i].Name < bundle.Workspaces[j].Name })
+	}
+
+	if !noTemplates {
+		paths, _ := filepath.Glob(filepath.Join(config.TemplatesDir(), "*.yaml"))
+		for _, path := range paths {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to read template: %w", err)
+			}
+			if bundle.Templates == nil {
+				bundle.Templates = make(map[string]string)
+			}
+			bundle.Templates[filepath.Base(path)] = string(data)
+		}
+	}
+
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if outputPath == "" || outputPath == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(outputPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", outputPath, err)
+	}
+	fmt.Printf("Exported %d worktrees and %d templates to %s\n", len(bundle.Workspaces), len(bundle.Templates), outputPath)
+	return nil
+}
+
+// configFilePath is the config file in use (--config or the default)
+func configFilePath() string {
+	if cfgFile != "" {
+		return cfgFile
+	}
+	return config.ConfigPath()
+}
+
+// exportedWorkspace returns a copy of ws without runtime state: PIDs, logs,
+// health, shares and detected activity
+func exportedWorkspace(ws *registry.Workspace) *registry.Workspace {
+	exported := &registry.Workspace{
+		Name:         ws.Name,
+		Path:         ws.Path,
+		Branch:       ws.Branch,
+		MainRepo:     ws.MainRepo,
+		Repo:         ws.Repo,
+		Editor:       ws.Editor,
+		Tags:         ws.Tags,
+		CreatedAt:    ws.CreatedAt,
+		DiscoveredAt: ws.DiscoveredAt,
+	}
+	if ws.Server != nil {
+		exported.Server = &registry.ServerState{
+			Port:       ws.Server.Port,
+			Status:     registry.StatusStopped,
+			URL:        ws.Server.URL,
+			Command:    ws.Server.Command,
+			Backend:    ws.Server.Backend,
+			Subdomains: ws.Server.Subdomains,
+		}
+	}
+	return exported
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	mapFlags, _ := cmd.Flags().GetStringArray("map")
+	yes, _ := cmd.Flags().GetBool("yes")
+	force, _ := cmd.Flags().GetBool("force")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	bundle, err := readStateBundle(args[0])
+	if err != nil {
+		return err
+	}
+
+	var mappings []pathMapping
+	for _, m := range mapFlags {
+		from, to, ok := strings.Cut(m, "=")
+		if !ok || from == "" || to == "" {
+			return fmt.Errorf("invalid --map %q (use old=new)", m)
+		}
+		mappings = append(mappings, pathMapping{From: filepath.Clean(expandPath(from)), To: filepath.Clean(expandPath(to))})
+	}
+	if home, err := os.UserHomeDir(); err == nil && bundle.Home != "" && bundle.Home != home {
+		mappings = append(mappings, pathMapping{From: bundle.Home, To: home})
+	}
+
+	// Ask where worktrees that don't exist here moved to, one parent
+	// directory at a time (stdin is the export with '-')
+	if !yes && args[0] != "-" && isInteractive() && len(bundle.Workspaces) > 0 {
+		mappings = promptMappings(bundle.Workspaces, mappings, os.Stdin, os.Stdout)
+	}
+
+	if bundle.Config != "" {
+		if err := importConfig(remapText(bundle.Config, mappings), force, dryRun); err != nil {
+			return err
+		}
+	}
+	if len(bundle.Templates) > 0 {
+		if err := importTemplates(bundle.Templates, force, dryRun); err != nil {
+			return err
+		}
+	}
+	if len(bundle.Workspaces) > 0 {
+		if err := importWorkspaces(bundle.Workspaces, mappings, force, dryRun); err != nil {
+			return err
+		}
+	}
+
+	if dryRun {
+		fmt.Println("\nDry run: nothing was changed.")
+	}
+	return nil
+}
+
+// promptMappings asks where worktrees that don't exist here, even after
+// the mappings, moved to, one parent directory at a time. Answers map the
+// exported directory, so they're applied instead of the other mappings
+// rather than after them.
+func promptMappings(workspaces []*registry.Workspace, mappings []pathMapping, in io.Reader, out io.Writer) []pathMapping {
+	reader := bufio.NewReader(in)
+	asked := make(map[string]bool)
+	for _, ws := range workspaces {
+		path := remapPath(ws.Path, mappings)
+		exportedDir := filepath.Dir(ws.Path)
+		if path == "" || fileExists(path) || asked[exportedDir] {
+			continue
+		}
+		asked[exportedDir] = true
+		fmt.Fprintf(out, "Worktrees under %s don't exist here. New location (Enter to keep): ", filepath.Dir(path))
+		answer, _ := reader.ReadString('\n')
+		if answer = strings.TrimSpace(answer); answer != "" {
+			mappings = append([]pathMapping{{From: exportedDir, To: filepath.Clean(expandPath(answer))}}, mappings...)
+		}
+	}
+	return mappings
+}
+
+func readStateBundle(path string) (*stateBundle, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", path, err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var bundle stateBundle
+	if err := json.NewDecoder(r).Decode(&bundle); err != nil {
+		return nil, fmt.Errorf("failed to read export: %w", err)
+	}
+	if bundle.Version == 0 || bundle.Version > stateVersion {
+		return nil, fmt.Errorf("unsupported export version %d (this grove reads version %d)", bundle.Version, stateVersion)
+	}
+	return &bundle, nil
+}
+
+func importConfig(data string, force, dryRun bool) error {
+	path := configFilePath()
+	if fileExists(path) && !force {
+		fmt.Printf("Config: keeping %s (use --force to replace it)\n", path)
+		return nil
+	}
+	fmt.Printf("Config: %s\n", path)
+	if dryRun {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	// Imported worktrees get URLs from the imported config
+	if loaded, err := config.Load(path); err == nil {
+		cfg = loaded
+	}
+	return nil
+}
+
+func importTemplates(templates map[string]string, force, dryRun bool) error {
+	dir := config.TemplatesDir()
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		// Names come from the export; keep them inside the templates directory
+		path := filepath.Join(dir, filepath.Base(name))
+		if fileExists(path) && !force {
+			fmt.Printf("Template %s: keeping existing\n", strings.TrimSuffix(filepath.Base(name), ".yaml"))
+			continue
+		}
+		fmt.Printf("Template %s\n", strings.TrimSuffix(filepath.Base(name), ".yaml"))
+		if dryRun {
+			continue
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create templates directory: %w", err)
+		}
+		if err := os.WriteFile(path, []byte(templates[name]), 0644); err != nil {
+			return fmt.Errorf("failed to write template: %w", err)
+		}
+	}
+	return nil
+}
+
+func importWorkspaces(workspaces []*registry.Workspace, mappings []pathMapping, force, dryRun bool) error {
+	reg, err := registry.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+
+	fmt.Println("Worktrees:")
+	imported := 0
+	for _, exported := range workspaces {
+		ws := *exported
+		ws.Path = remapPath(ws.Path, mappings)
+		ws.MainRepo = remapPath(ws.MainRepo, mappings)
+		if filepath.IsAbs(ws.Repo) {
+			ws.Repo = remapPath(ws.Repo, mappings)
+		}
+
+		if existing, ok := reg.GetWorkspace(ws.Name); ok {
+			if !force || existing.IsRunning() {
+				fmt.Printf("  = %s (already registered)\n", ws.Name)
+				continue
+			}
+		}
+
+		note := ""
+		if !fileExists(ws.Path) {
+			note = " (path doesn't exist yet)"
+		}
+		if ws.Server != nil {
+			server := *ws.Server
+			reg.RemoveWorkspaceWithoutSave(ws.Name)
+			if server.Port > 0 && reg.GetUsedPorts()[server.Port] {
+				if server.Port, err = allocatePort(ws.Name, ws.Path, reg.GetUsedPorts()); err != nil {
+					return fmt.Errorf("failed to allocate port for %s: %w", ws.Name, err)
+				}
+				note += fmt.Sprintf(" (port %d was taken)", exported.Server.Port)
+			}
+			if server.Port > 0 {
+				server.URL = cfg.ServerURL(ws.Name, server.Port)
+			}
+			ws.Server = &server
+		}
+
+		fmt.Printf("  + %s → %s%s\n", ws.Name, ws.Path, note)
+		imported++
+		if !dryRun {
+			reg.SetWorkspaceWithoutSave(&ws)
+		}
+	}
+
+	if dryRun || imported == 0 {
+		return nil
+	}
+	if err := reg.Save(); err != nil {
+		return fmt.Errorf("failed to save registry: %w", err)
+	}
+	fmt.Printf("Imported %d worktrees\n", imported)
+	return nil
+}
+
+// pathMapping replaces a path prefix when importing
+type pathMapping struct {
+	From string
+	To   string
+}
+
+// remapPath applies the first mapping whose prefix matches path
+func remapPath(path string, mappings []pathMapping) string {
+	for _, m := range mappings {
+		if path == m.From {
+			return m.To
+		}
+		if rest, ok := strings.CutPrefix(path, m.From+string(filepath.Separator)); ok {
+			return filepath.Join(m.To, rest)
+		}
+	}
+	return path
+}
+
+// remapText applies the mappings to paths in text, such as the config file
+func remapText(text string, mappings []pathMapping) string {
+	for _, m := range mappings {
+		text = strings.ReplaceAll(text, m.From+string(filepath.Separator), m.To+string(filepath.Separator))
+	}
+	return text
+}
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+func TestExportedWorkspace(t *testing.T) {
+	ws := &registry.Workspace{
+		Name:         "feature",
+		Path:         "/Users/me/code/app-feature",
+		Branch:       "feature",
+		Tags:         []string{"frontend"},
+		HasClaude:    true,
+		LastActivity: time.Now(),
+		Server: &registry.ServerState{
+			Port:      3042,
+			PID:       1234,
+			Status:    registry.StatusRunning,
+			URL:       "http://localhost:3042",
+			Command:   []string{"bin/dev"},
+			LogFile:   "/tmp/feature.log",
+			StartedAt: time.Now(),
+			Health:    registry.HealthHealthy,
+			Processes: []registry.Process{{Name: "web", PID: 1235}},
+		},
+		Share: &registry.Share{PID: 99},
+	}
+
+	got := exportedWorkspace(ws)
+	if got.Name != "feature" || got.Path != ws.Path || len(got.Tags) != 1 {
+		t.Errorf("exportedWorkspace() lost identity: %+v", got)
+	}
+	if got.HasClaude || !got.LastActivity.IsZero() || got.Share != nil {
+		t.Errorf("exportedWorkspace() kept activity or share: %+v", got)
+	}
+	s := got.Server
+	if s.Port != 3042 || len(s.Command) != 1 {
+		t.Errorf("exportedWorkspace() lost server config: %+v", s)
+	}
+	if s.PID != 0 || s.Status != registry.StatusStopped || s.LogFile != "" || !s.StartedAt.IsZero() || s.Health != "" || s.Processes != nil {
+		t.Errorf("exportedWorkspace() kept runtime state: %+v", s)
+	}
+	if ws.Server.PID != 1234 {
+		t.Error("exportedWorkspace() modified the workspace")
+	}
+}
+
+func TestRemapPath(t *testing.T) {
+	mappings := []pathMapping{
+		{From: "/Users/me/code/app", To: "/srv/app"},
+		{From: "/Users/me", To: "/home/me"},
+	}
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/Users/me/code/app", "/srv/app"},
+		{"/Users/me/code/app/feature", "/srv/app/feature"},
+		{"/Users/me/code/application", "/home/me/code/application"},
+		{"/Users/me", "/home/me"},
+		{"/Users/meg/code", "/Users/meg/code"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := remapPath(tt.path, mappings); got != tt.want {
+			t.Errorf("remapPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestPromptMappings(t *testing.T) {
+	home := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(home, "src", "web"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	workspaces := []*registry.Workspace{
+		{Name: "app-feature", Path: "/Users/old/code/app-feature"},
+		{Name: "app-fix", Path: "/Users/old/code/app-fix"},
+		{Name: "web", Path: "/Users/old/src/web"},
+	}
+	homeMapping := []pathMapping{{From: "/Users/old", To: home}}
+
+	var out strings.Builder
+	mappings := promptMappings(workspaces, homeMapping, strings.NewReader("/srv/code\n"), &out)
+
+	// Asked once about the remapped directory, for the worktrees that
+	// don't exist there
+	if want := "Worktrees under " + filepath.Join(home, "code") + " don't exist here"; strings.Count(out.String(), "don't exist here") != 1 || !strings.Contains(out.String(), want) {
+		t.Errorf("prompted %q, want one prompt about %s", out.String(), filepath.Join(home, "code"))
+	}
+	// The answer applies on top of the home directory's mapping
+	for path, want := range map[string]string{
+		"/Users/old/code/app-feature": "/srv/code/app-feature",
+		"/Users/old/code/app-fix":     "/srv/code/app-fix",
+		"/Users/old/src/web":          filepath.Join(home, "src", "web"),
+	} {
+		if got := remapPath(path, mappings); got != want {
+			t.Errorf("remapPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestRemapText(t *testing.T) {
+	text := "worktrees_dir: /Users/me/worktrees\nlog_dir: /Users/meg/logs\n"
+	got := remapText(text, []pathMapping{{From: "/Users/me", To: "/home/me"}})
+	want := "worktrees_dir: /home/me/worktrees\nlog_dir: /Users/meg/logs\n"
+	if got != want {
+		t.Errorf("remapText() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/iheanyi/grove/internal/crash"
+	"github.com/iheanyi/grove/internal/events"
+	"github.com/iheanyi/grove/internal/project"
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+// fatalPollInterval is how often the long-lived processes read new output
+// from the logs of servers running in the background
+const fatalPollInterval = 2 * time.Second
+
+// fatalPatterns returns the fatal log patterns of a project: its
+// fatal_log_patterns, or the defaults
+func fatalPatterns(projConfig *project.Config) ([]*regexp.Regexp, error) {
+	if projConfig == nil {
+		return crash.CompileFatal(nil)
+	}
+	return crash.CompileFatal(projConfig.FatalLogPatterns)
+}
+
+// fatalWatcher returns a writer that flags the server unhealthy on its
+// first fatal log line. process names the process of a multi-process
+// server.
+func fatalWatcher(server *registry.Server, process string, patterns []*regexp.Regexp) *crash.FatalWatcher {
+	name, pid := server.Name, server.PID
+	return crash.NewFatalWatcher(patterns, func(line string) {
+		// The PID is only known once the process has started
+		if pid == 0 {
+			pid = server.PID
+		}
+		flagFatal(name, pid, process, line)
+	})
+}
+
+// flagFatal marks a server unhealthy because its output had a fatal line,
+// and publishes the health change so it's notified. Only the first fatal
+// line of a run counts; pid tells runs apart.
+func flagFatal(name string, pid int, process, line string) {
+	reg, err := registry.Load()
+	if err != nil {
+		return
+	}
+	server, ok := reg.Get(name)
+	if !ok || server.PID != pid || server.FatalLog != "" {
+		return
+	}
+
+	if process != "" {
+		line = process + ": " + line
+	}
+	fmt.Fprintf(os.Stderr, "grove: %s logged a fatal error, marking it unhealthy: %s\n", name, line)
+
+	previous := server.Health
+	server.Health = registry.HealthUnhealthy
+	server.FatalLog = line
+	server.LastHealthCheck = time.Now()
+	if err := reg.Set(server); err != nil {
+		return
+	}
+	if previous != registry.HealthUnhealthy {
+		e := server.Event(events.HealthChanged)
+		e.Process = process
+		e.Message = "fatal log line: " + line
+		events.Publish(e)
+	}
+}
+
+// watchFatalLogs follows the logs of servers running in the background for
+// fatal lines until ctx is done. Servers in the foreground, and the
+// processes of multi-process servers, are watched by the grove process
+// running them instead. It runs in grove's long-lived processes (the
+// foreground proxy and the API); only the first to notice a line flags it.
+func watchFatalLogs(ctx context.Context) {
+	followers := make(map[string]*logFollower)
+	ticker := time.NewTicker(fatalPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		reg, err := registry.LoadContext(ctx)
+		if err != nil {
+			continue
+		}
+		running := make(map[string]bool)
+		for _, server := range reg.ListRunning() {
+			if server.LogFile == "" || server.IsMultiProcess() || server.IsCompose() || server.FatalLog != "" {
+				continue
+			}
+			running[server.Name] = true
+
+			f, ok := followers[server.Name]
+			if !ok || f.pid != server.PID {
+				f, err = newLogFollower(server)
+				if err != nil {
+					log.Printf("Not watching %s's log: %v", server.Name, err)
+					followers[server.Name] = &logFollower{pid: server.PID}
+					continue
+				}
+				followers[server.Name] = f
+			}
+			f.poll()
+		}
+		for name := range followers {
+			if !running[name] {
+				delete(followers, name)
+			}
+		}
+	}
+}
+
+// logFollower reads what's appended to a server's log into a FatalWatcher
+type logFollower struct {
+	pid     int
+	path    string
+	offset  int64
+	watcher *crash.FatalWatcher
+}
+
+// newLogFollower follows a server's log from its current end
+func newLogFollower(server *registry.Server) (*logFollower, error) {
+	projConfig, _ := project.Load(server.Path)
+	patterns, err := fatalPatterns(projConfig)
+	if err != nil {
+		return nil, err
+	}
+	f := &logFollower{pid: server.PID, path: server.LogFile, watcher: fatalWatcher(server, "", patterns)}
+	if info, err := os.Stat(server.LogFile); err == nil {
+		f.offset = info.Size()
+	}
+	return f, nil
+}
+
+// poll reads the log's new output into the watcher
+func (f *logFollower) poll() {
+	if f.watcher == nil {
+		return
+	}
+	f.copyTo(f.watcher)
+}
+
+// copyTo writes what was appended to the log since the last read to w,
+// starting over if it was truncated
+func (f *logFollower) copyTo(w io.Writer) {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+	if info, err := file.Stat(); err == nil && info.Size() < f.offset {
+		f.offset = 0
+	}
+	if _, err := file.Seek(f.offset, io.SeekStart); err != nil {
+		return
+	}
+	n, _ := io.Copy(w, file)
+	f.offset += n
+}
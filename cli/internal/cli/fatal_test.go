@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+func TestFlagFatal(t *testing.T) {
+	useTestEnv(t)
+	reg, err := registry.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	logFile := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(logFile, []byte("old: EADDRINUSE\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	server := &registry.Server{Name: "app", Path: t.TempDir(), PID: 4242, Status: registry.StatusRunning, Health: registry.HealthHealthy, LogFile: logFile}
+	if err := reg.Set(server); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only output written after the follower starts counts
+	f, err := newLogFollower(server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.poll()
+	if s, _ := mustReload(t).Get("app"); s.FatalLog != "" {
+		t.Fatalf("old log output flagged: %q", s.FatalLog)
+	}
+
+	file, err := os.OpenFile(logFile, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString("Segmentation fault (core dumped)\n") //nolint:errcheck
+	file.Close()
+	f.poll()
+
+	s, _ := mustReload(t).Get("app")
+	if s.Health != registry.HealthUnhealthy || s.FatalLog != "Segmentation fault (core dumped)" {
+		t.Errorf("server = %s, %q; want unhealthy with the fatal line", s.Health, s.FatalLog)
+	}
+
+	// Lines from another run of the server are ignored
+	flagFatal("app", 9999, "", "EADDRINUSE")
+	if s, _ := mustReload(t).Get("app"); s.FatalLog != "Segmentation fault (core dumped)" {
+		t.Errorf("FatalLog = %q, want the first run's line", s.FatalLog)
+	}
+}
+
+func mustReload(t *testing.T) *registry.Registry {
+	t.Helper()
+	reg, err := registry.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reg
+}
@@ -0,0 +1,278 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/freeze"
+	"github.com/iheanyi/grove/internal/output"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/service"
+	"github.com/spf13/cobra"
+)
+
+// freezeInterval is how often the login services refresh the snapshot
+// with restore_on_login
+const freezeInterval = time.Minute
+
+var freezeCmd = &cobra.Command{
+	Use:   "freeze",
+	Short: "Save which servers are running, to restore with 'grove thaw'",
+	Long: `Save a snapshot of the running servers (their names, commands and ports)
+and whether the proxy is running, replacing the last one. 'grove thaw'
+starts the same set again, e.g. after a reboot.
+
+With 'restore_on_login: true' in ~/.config/grove/config.yaml, the proxy and
+API services (see 'grove service') keep the snapshot current every minute
+and thaw it when they start at login.
+
+Examples:
+  grove freeze
+  grove freeze --json`,
+	Args: cobra.NoArgs,
+	RunE: runFreeze,
+}
+
+var thawCmd = &cobra.Command{
+	Use:   "thaw",
+	Short: "Start the servers saved by 'grove freeze'",
+	Long: `Start the servers in the snapshot saved by 'grove freeze' on the ports they
+had, with the commands they ran, and the proxy if it was running. Servers
+that are already running are left alone.
+
+Examples:
+  grove thaw
+  grove thaw --json`,
+	Args: cobra.NoArgs,
+	RunE: runThaw,
+}
+
+func init() {
+	addOutputFlags(freezeCmd)
+	addOutputFlags(thawCmd)
+}
+
+func runFreeze(cmd *cobra.Command, args []string) error {
+	return runWithOutput(cmd, func() (any, error) {
+		snapshot, err := takeSnapshot()
+		if err != nil {
+			return nil, err
+		}
+		path := config.FrozenPath()
+		if err := snapshot.Save(path); err != nil {
+			return nil, fmt.Errorf("failed to save snapshot: %w", err)
+		}
+
+		proxy := ""
+		if snapshot.Proxy {
+			proxy = " and the proxy"
+		}
+		fmt.Printf("Froze %d running server(s)%s\n", len(snapshot.Servers), proxy)
+		for _, s := range snapshot.Servers {
+			fmt.Printf("  %s on port %d\n", s.Name, s.Port)
+		}
+		fmt.Println("\nRestore them with 'grove thaw'")
+		if !cfg.RestoreOnLogin {
+			fmt.Println("Set 'restore_on_login: true' to restore them at login (with 'grove service install')")
+		}
+
+		return output.FreezeResult{
+			Path:     path,
+			FrozenAt: snapshot.FrozenAt,
+			Proxy:    snapshot.Proxy,
+			Servers:  snapshot.Servers,
+		}, nil
+	})
+}
+
+func runThaw(cmd *cobra.Command, args []string) error {
+	snapshot, err := freeze.Load(config.FrozenPath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("nothing is frozen\nSave the running servers with 'grove freeze' first")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load snapshot: %w", err)
+	}
+
+	return runWithOutput(cmd, func() (any, error) {
+		fmt.Printf("Thawing %d server(s) frozen %s ago\n", len(snapshot.Servers), formatAge(time.Since(snapshot.FrozenAt)))
+		result, err := thaw(snapshot, true)
+		if err != nil {
+			return nil, err
+		}
+
+		var failed int
+		for _, s := range result.Servers {
+			if s.Status == "failed" {
+				failed++
+			}
+		}
+		if failed > 0 {
+			return result, fmt.Errorf("%d of %d server(s) failed to start", failed, len(result.Servers))
+		}
+		fmt.Printf("All %d server(s) running\n", len(result.Servers))
+		return result, nil
+	})
+}
+
+// takeSnapshot snapshots the running servers. Servers whose processes
+// exited are marked stopped first, so a registry left over from before a
+// reboot doesn't count as running.
+func takeSnapshot() (*freeze.Snapshot, error) {
+	reg, err := registry.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load registry: %w", err)
+	}
+	if _, err := reg.Cleanup(); err != nil {
+		return nil, fmt.Errorf("failed to clean up registry: %w", err)
+	}
+	proxy := reg.GetProxy()
+	return freeze.Take(reg, proxy.IsRunning() && isProcessRunning(proxy.PID)), nil
+}
+
+// thaw starts the snapshot's servers that aren't running, and the proxy
+// if it was frozen running and startProxy is set
+func thaw(snapshot *freeze.Snapshot, startProxy bool) (output.ThawResult, error) {
+	result := output.ThawResult{Servers: []output.ThawedServer{}}
+	reg, err := registry.Load()
+	if err != nil {
+		return result, fmt.Errorf("failed to load registry: %w", err)
+	}
+	reg.Cleanup() //nolint:errcheck // Best effort
+
+	if snapshot.Proxy && startProxy && cfg.UsesProxy() {
+		result.Proxy = "started"
+		if proxy := reg.GetProxy(); proxy.IsRunning() && isProcessRunning(proxy.PID) {
+			result.Proxy = "running"
+		} else if err := runProxyDaemon(); err != nil {
+			result.Proxy = "failed"
+			fmt.Printf("Warning: proxy: %v\n", err)
+		} else if fresh, err := registry.Load(); err == nil {
+			// Pick up what the proxy recorded, so saving doesn't undo it
+			reg = fresh
+		}
+	}
+
+	used := reg.GetUsedPorts()
+	for _, frozen := range snapshot.Servers {
+		thawed := output.ThawedServer{Name: frozen.Name, Port: frozen.Port, Status: "started"}
+		server, ok := reg.Get(frozen.Name)
+		if ok && server.IsRunning() {
+			thawed.Status = "running"
+			thawed.Port = server.Port
+		} else if err := thawServer(server, frozen, used); err != nil {
+			thawed.Status = "failed"
+			thawed.Error = err.Error()
+			fmt.Printf("  ✗ %s: %v\n", frozen.Name, err)
+		}
+		result.Servers = append(result.Servers, thawed)
+	}
+	return result, nil
+}
+
+// thawServer starts a frozen server with its frozen command and port.
+// server is its registry entry, or nil if it's no longer registered.
+func thawServer(server *registry.Server, frozen freeze.Server, used map[int]bool) error {
+	if _, err := os.Stat(frozen.Path); err != nil {
+		return fmt.Errorf("worktree %s no longer exists", frozen.Path)
+	}
+	if server == nil {
+		server = &registry.Server{Name: frozen.Name, Path: frozen.Path}
+	}
+	server.Command = frozen.Command
+
+	port := frozen.Port
+	if port == 0 {
+		var err error
+		if port, err = plannedPort(server, used); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("Starting %s on port %d...\n", frozen.Name, port)
+	return startRegistered(server, port, nil)
+}
+
+// watchFreeze thaws the snapshot when the login service s starts, then
+// keeps it current until ctx is done. It does nothing without
+// restore_on_login, or when s is running but not installed as a service
+// (e.g. 'grove proxy start').
+func watchFreeze(ctx context.Context, s service.Service) {
+	if !cfg.RestoreOnLogin || !serviceInstalled(s) {
+		return
+	}
+	restoreOnLogin()
+
+	ticker := time.NewTicker(freezeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			refreshSnapshot()
+		}
+	}
+}
+
+// restoreOnLogin thaws the snapshot unless some of its servers are
+// running, which means the service restarted mid-session rather than at
+// login. The proxy and API services both do this; a lock lets the first
+// thaw while the other waits and then finds the servers running.
+func restoreOnLogin() {
+	path := config.FrozenPath()
+	lock, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return
+	}
+	defer lock.Close()
+	if err := syscall.Flock(int(lock.Fd()), syscall.LOCK_EX); err != nil {
+		return
+	}
+	defer syscall.Flock(int(lock.Fd()), syscall.LOCK_UN) //nolint:errcheck
+
+	snapshot, err := freeze.Load(path)
+	if err != nil || len(snapshot.Servers) == 0 {
+		return
+	}
+	reg, err := registry.Load()
+	if err != nil {
+		log.Printf("Failed to load registry: %v", err)
+		return
+	}
+	reg.Cleanup() //nolint:errcheck // Best effort
+	for _, frozen := range snapshot.Servers {
+		if server, ok := reg.Get(frozen.Name); ok && server.IsRunning() {
+			return
+		}
+	}
+
+	log.Printf("Restoring %d server(s) frozen %s ago", len(snapshot.Servers), formatAge(time.Since(snapshot.FrozenAt)))
+	// The proxy service starts the proxy itself
+	if _, err := thaw(snapshot, !serviceInstalled(service.Proxy)); err != nil {
+		log.Printf("Failed to restore servers: %v", err)
+	}
+}
+
+// refreshSnapshot saves the running set if it changed since the last
+// snapshot
+func refreshSnapshot() {
+	snapshot, err := takeSnapshot()
+	if err != nil {
+		log.Printf("Failed to snapshot servers: %v", err)
+		return
+	}
+	path := config.FrozenPath()
+	if last, err := freeze.Load(path); err == nil && snapshot.Same(last) {
+		return
+	}
+	if err := snapshot.Save(path); err != nil {
+		log.Printf("Failed to save snapshot: %v", err)
+	}
+}
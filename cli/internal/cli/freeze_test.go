@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/iheanyi/grove/internal/freeze"
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+func TestThaw(t *testing.T) {
+	useTestEnv(t)
+	reg, err := registry.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	running := &registry.Server{Name: "app", Path: t.TempDir(), Port: 3005, PID: os.Getpid(), Status: registry.StatusRunning, StartedAt: time.Now()}
+	if err := reg.Set(running); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot, err := takeSnapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshot.Servers) != 1 || snapshot.Servers[0].Name != "app" || snapshot.Servers[0].Port != 3005 {
+		t.Fatalf("takeSnapshot() = %+v, want app on 3005", snapshot.Servers)
+	}
+
+	// A worktree removed since the freeze can't be started
+	snapshot.Servers = append(snapshot.Servers, freeze.Server{Name: "gone", Path: filepath.Join(t.TempDir(), "gone"), Port: 3006})
+	result, err := thaw(snapshot, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Servers) != 2 || result.Servers[0].Status != "running" || result.Servers[1].Status != "failed" {
+		t.Errorf("thaw() = %+v, want app running and gone failed", result.Servers)
+	}
+	if result.Proxy != "" {
+		t.Errorf("thaw() proxy = %q, want untouched", result.Proxy)
+	}
+}
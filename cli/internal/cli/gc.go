@@ -0,0 +1,227 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/crash"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/usage"
+	"github.com/spf13/cobra"
+)
+
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Reclaim space from stale registry entries, logs and crash reports",
+	Long: `Garbage-collect what grove leaves behind:
+
+  - Registry entries for worktrees whose directory no longer exists
+  - Log files (server, share and access logs) of servers grove doesn't know
+  - Crash reports older than log_retention (default 7d)
+
+Running servers and logs written in the last few minutes are left alone.
+
+A light version, removing only orphaned logs and expired crash reports,
+runs at most once a day when a server is started in the background.
+
+Examples:
+  grove gc             # Remove everything stale and print what was reclaimed
+  grove gc --dry-run   # Show what would be removed`,
+	Args: cobra.NoArgs,
+	RunE: runGC,
+}
+
+func init() {
+	gcCmd.Flags().Bool("dry-run", false, "Show what would be removed without removing it")
+}
+
+// logGracePeriod protects the logs of servers that are starting, which are
+// created before the server is registered
+const logGracePeriod = 10 * time.Minute
+
+// autoGCInterval is how often the light gc runs automatically
+const autoGCInterval = 24 * time.Hour
+
+// gcPlan is what 'grove gc' removes
+type gcPlan struct {
+	Entries []*registry.Workspace // Registry entries whose path no longer exists
+	Logs    []string              // Log files of servers grove doesn't know
+	Crashes []string              // Crash reports past retention
+}
+
+func (p *gcPlan) empty() bool {
+	return len(p.Entries) == 0 && len(p.Logs) == 0 && len(p.Crashes) == 0
+}
+
+// files returns the files the plan removes
+func (p *gcPlan) files() []string {
+	return append(append([]string{}, p.Logs...), p.Crashes...)
+}
+
+func runGC(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	reg, err := registry.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+
+	retention, err := gcRetention()
+	if err != nil {
+		return err
+	}
+
+	plan, err := planGC(reg.ListWorkspaces(), cfg.LogDir, config.CrashesDir(), retention, true)
+	if err != nil {
+		return err
+	}
+	if plan.empty() {
+		fmt.Println("Nothing to clean up")
+		return nil
+	}
+
+	if len(plan.Entries) > 0 {
+		fmt.Printf("Registry entries (path no longer exists):\n")
+		for _, ws := range plan.Entries {
+			fmt.Printf("  - %s (%s)\n", ws.Name, ws.Path)
+		}
+	}
+	if len(plan.Logs) > 0 {
+		fmt.Printf("Logs of unknown servers:\n")
+		for _, path := range plan.Logs {
+			fmt.Printf("  - %s (%s)\n", path, usage.FormatBytes(fileSize(path)))
+		}
+	}
+	if len(plan.Crashes) > 0 {
+		fmt.Printf("Crash reports older than %s:\n", formatAge(retention))
+		for _, path := range plan.Crashes {
+			fmt.Printf("  - %s\n", path)
+		}
+	}
+	fmt.Println()
+
+	if dryRun {
+		var size uint64
+		for _, path := range plan.files() {
+			size += fileSize(path)
+		}
+		fmt.Printf("Would reclaim %s (%d registry entries, %d files)\n", usage.FormatBytes(size), len(plan.Entries), len(plan.files()))
+		fmt.Println("Run without --dry-run to remove them.")
+		return nil
+	}
+
+	for _, ws := range plan.Entries {
+		reg.RemoveWorkspaceWithoutSave(ws.Name)
+	}
+	if len(plan.Entries) > 0 {
+		if err := reg.Save(); err != nil {
+			return fmt.Errorf("failed to save registry: %w", err)
+		}
+	}
+	reclaimed, removed := applyGC(plan)
+
+	fmt.Printf("Reclaimed %s (%d registry entries, %d files)\n", usage.FormatBytes(reclaimed), len(plan.Entries), removed)
+	return nil
+}
+
+// gcRetention is how long crash reports are kept (log_retention)
+func gcRetention() (time.Duration, error) {
+	retention, err := parseAge(cfg.LogRetention)
+	if err != nil {
+		return 0, fmt.Errorf("invalid log_retention: %w", err)
+	}
+	return retention, nil
+}
+
+// planGC finds what to garbage-collect. Registry entries are only collected
+// with entries, and the logs of collected entries only then.
+func planGC(workspaces []*registry.Workspace, logDir, crashesDir string, retention time.Duration, entries bool) (*gcPlan, error) {
+	plan := &gcPlan{}
+
+	known := make(map[string]bool)
+	for _, ws := range workspaces {
+		if entries && ws.Path != "" && !ws.IsRunning() && !fileExists(ws.Path) {
+			plan.Entries = append(plan.Entries, ws)
+			continue
+		}
+		known[ws.Name] = true
+	}
+
+	for _, dir := range []string{logDir, filepath.Join(logDir, "access")} {
+		paths, err := filepath.Glob(filepath.Join(dir, "*.log"))
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			name := strings.TrimSuffix(filepath.Base(path), ".log")
+			if known[name] || known[strings.TrimSuffix(name, "-share")] {
+				continue
+			}
+			info, err := os.Stat(path)
+			if err != nil || info.IsDir() || time.Since(info.ModTime()) < logGracePeriod {
+				continue
+			}
+			plan.Logs = append(plan.Logs, path)
+		}
+	}
+
+	crashes, err := crash.Expired(crashesDir, time.Now().Add(-retention))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read crash reports: %w", err)
+	}
+	plan.Crashes = crashes
+
+	return plan, nil
+}
+
+// applyGC removes the plan's files, and crash report directories left
+// empty, returning the bytes reclaimed and the number of files removed
+func applyGC(plan *gcPlan) (uint64, int) {
+	var reclaimed uint64
+	var removed int
+	for _, path := range plan.files() {
+		size := fileSize(path)
+		if err := os.Remove(path); err != nil {
+			continue
+		}
+		reclaimed += size
+		removed++
+	}
+	for _, path := range plan.Crashes {
+		os.Remove(filepath.Dir(path)) //nolint:errcheck // Only succeeds once the directory is empty
+	}
+	return reclaimed, removed
+}
+
+// autoGC runs the light gc, removing orphaned logs and expired crash
+// reports, if it hasn't run within autoGCInterval. Registry entries are left
+// to 'grove gc' and 'grove cleanup'.
+func autoGC(reg *registry.Registry) {
+	stamp := filepath.Join(config.ConfigDir(), "gc.stamp")
+	if info, err := os.Stat(stamp); err == nil && time.Since(info.ModTime()) < autoGCInterval {
+		return
+	}
+	if err := os.WriteFile(stamp, nil, 0644); err != nil {
+		return
+	}
+
+	retention, err := gcRetention()
+	if err != nil {
+		return
+	}
+	if plan, err := planGC(reg.ListWorkspaces(), cfg.LogDir, config.CrashesDir(), retention, false); err == nil {
+		applyGC(plan)
+	}
+}
+
+func fileSize(path string) uint64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return uint64(info.Size())
+}
@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/iheanyi/grove/internal/crash"
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+func TestPlanGC(t *testing.T) {
+	logDir := t.TempDir()
+	crashesDir := t.TempDir()
+	old := time.Now().Add(-time.Hour)
+
+	writeLog := func(name string, modTime time.Time) string {
+		t.Helper()
+		path := filepath.Join(logDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("log output\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	writeLog("feature.log", old)
+	writeLog("feature-share.log", old)
+	writeLog("access/feature.log", old)
+	gone := writeLog("gone.log", old)
+	orphan := writeLog("orphan.log", old)
+	orphanAccess := writeLog("access/orphan.log", old)
+	writeLog("starting.log", time.Now())
+
+	expired, err := crash.Save(crashesDir, crash.New("orphan", "", time.Time{}, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	month := time.Now().Add(-30 * 24 * time.Hour)
+	if err := os.Chtimes(expired, month, month); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := crash.Save(crashesDir, crash.New("feature", "", time.Time{}, nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	workspaces := []*registry.Workspace{
+		{Name: "feature", Path: t.TempDir()},
+		{Name: "gone", Path: filepath.Join(t.TempDir(), "deleted")},
+	}
+
+	plan, err := planGC(workspaces, logDir, crashesDir, 7*24*time.Hour, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Entries) != 1 || plan.Entries[0].Name != "gone" {
+		t.Errorf("Entries = %v, want [gone]", plan.Entries)
+	}
+	assertPaths(t, "Logs", plan.Logs, gone, orphan, orphanAccess)
+	assertPaths(t, "Crashes", plan.Crashes, expired)
+
+	// The light version keeps registry entries and their logs
+	light, err := planGC(workspaces, logDir, crashesDir, 7*24*time.Hour, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(light.Entries) != 0 {
+		t.Errorf("light Entries = %v, want none", light.Entries)
+	}
+	assertPaths(t, "light Logs", light.Logs, orphan, orphanAccess)
+
+	reclaimed, removed := applyGC(plan)
+	if removed != 4 || reclaimed == 0 {
+		t.Errorf("applyGC = %d bytes, %d files; want 4 files", reclaimed, removed)
+	}
+	if fileExists(orphan) || fileExists(filepath.Dir(expired)) {
+		t.Error("orphaned log and empty crash directory should be removed")
+	}
+	if !fileExists(filepath.Join(logDir, "feature.log")) || !fileExists(filepath.Join(crashesDir, "feature")) {
+		t.Error("known server's log and crash reports should be kept")
+	}
+}
+
+func assertPaths(t *testing.T, what string, got []string, want ...string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s = %v, want %v", what, got, want)
+	}
+	seen := make(map[string]bool)
+	for _, path := range got {
+		seen[path] = true
+	}
+	for _, path := range want {
+		if !seen[path] {
+			t.Errorf("%s = %v, missing %s", what, got, path)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/project"
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+// Global hooks run for every worktree of every project. Each is an
+// executable in the hooks directory named after the hook, or a directory of
+// that name whose executables run in name order. Team hooks committed to a
+// project's .grove/hooks work the same way and run after them.
+const (
+	hookPreStart   = "pre-start"
+	hookPostStart  = "post-start"
+	hookPreDelete  = "pre-delete"
+	hookPostCreate = "post-create"
+)
+
+// hookTarget is the worktree a global hook runs for. It's passed to the hook
+// as GROVE_* environment variables.
+type hookTarget struct {
+	Name   string
+	Path   string
+	Branch string
+	Port   int
+	URL    string
+}
+
+func (t hookTarget) env(hook string) []string {
+	env := []string{
+		"GROVE_HOOK=" + hook,
+		"GROVE_NAME=" + t.Name,
+		"GROVE_PATH=" + t.Path,
+	}
+	if t.Branch != "" {
+		env = append(env, "GROVE_BRANCH="+t.Branch)
+	}
+	if t.Port > 0 {
+		env = append(env, "GROVE_PORT="+strconv.Itoa(t.Port))
+	}
+	if t.URL != "" {
+		env = append(env, "GROVE_URL="+t.URL)
+	}
+	return env
+}
+
+// globalHookScripts returns the executables of a global hook in dir
+func globalHookScripts(dir, hook string) []string {
+	path := filepath.Join(dir, hook)
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+	if !info.IsDir() {
+		if isExecutable(info) {
+			return []string{path}
+		}
+		return nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil
+	}
+	var scripts []string
+	for _, entry := range entries {
+		if info, err := entry.Info(); err == nil && !info.IsDir() && isExecutable(info) {
+			scripts = append(scripts, filepath.Join(path, entry.Name()))
+		}
+	}
+	sort.Strings(scripts)
+	return scripts
+}
+
+func isExecutable(info os.FileInfo) bool {
+	return info.Mode()&0111 != 0
+}
+
+// runGlobalHooks runs a hook's global executables, then the team's in the
+// worktree's .grove/hooks, in the worktree, writing their output to w. It
+// stops at the first one that fails.
+func runGlobalHooks(hook string, t hookTarget, w io.Writer) error {
+	for _, source := range []struct{ kind, dir string }{
+		{"global", config.HooksDir()},
+		{"team", project.TeamHooksDir(t.Path)},
+	} {
+		scripts := globalHookScripts(source.dir, hook)
+		if len(scripts) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(w, "Running %s %s hooks...\n", source.kind, hook)
+		for _, script := range scripts {
+			cmd := exec.Command(script)
+			cmd.Dir = t.Path
+			cmd.Env = append(os.Environ(), t.env(hook)...)
+			cmd.Stdout = w
+			cmd.Stderr = w
+			if err := cmd.Run(); err != nil {
+				return fmt.Errorf("%s: %w", filepath.Base(script), err)
+			}
+		}
+	}
+	return nil
+}
+
+// runPostStartHooks runs the global post-start hooks for a started server
+func runPostStartHooks(server *registry.Server) {
+	target := hookTarget{Name: server.Name, Path: server.Path, Branch: server.Branch, Port: server.Port, URL: server.URL}
+	if err := runGlobalHooks(hookPostStart, target, os.Stdout); err != nil {
+		fmt.Printf("Warning: post-start hook failed: %v\n", err)
+	}
+}
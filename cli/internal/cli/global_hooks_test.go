@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestGlobalHookScripts(t *testing.T) {
+	dir := t.TempDir()
+	write := func(path string, mode os.FileMode) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("#!/bin/sh\n"), mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A single executable named after the hook
+	write(filepath.Join(dir, hookPreStart), 0755)
+	// A directory of executables, run in name order; others are skipped
+	write(filepath.Join(dir, hookPostCreate, "20-direnv"), 0755)
+	write(filepath.Join(dir, hookPostCreate, "10-passwords"), 0755)
+	write(filepath.Join(dir, hookPostCreate, "README"), 0644)
+	// Not executable
+	write(filepath.Join(dir, hookPreDelete), 0644)
+
+	if got, want := globalHookScripts(dir, hookPreStart), []string{filepath.Join(dir, hookPreStart)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("pre-start scripts = %v, want %v", got, want)
+	}
+	want := []string{filepath.Join(dir, hookPostCreate, "10-passwords"), filepath.Join(dir, hookPostCreate, "20-direnv")}
+	if got := globalHookScripts(dir, hookPostCreate); !reflect.DeepEqual(got, want) {
+		t.Errorf("post-create scripts = %v, want %v", got, want)
+	}
+	if got := globalHookScripts(dir, hookPreDelete); len(got) != 0 {
+		t.Errorf("expected non-executable hook to be skipped, got %v", got)
+	}
+	if got := globalHookScripts(dir, hookPostStart); len(got) != 0 {
+		t.Errorf("expected no post-start scripts, got %v", got)
+	}
+}
+
+func TestHookTargetEnv(t *testing.T) {
+	target := hookTarget{Name: "app-feature", Path: "/src/app-feature", Branch: "feature", Port: 3100, URL: "http://localhost:3100"}
+	want := []string{
+		"GROVE_HOOK=post-start",
+		"GROVE_NAME=app-feature",
+		"GROVE_PATH=/src/app-feature",
+		"GROVE_BRANCH=feature",
+		"GROVE_PORT=3100",
+		"GROVE_URL=http://localhost:3100",
+	}
+	if got := target.env(hookPostStart); !reflect.DeepEqual(got, want) {
+		t.Errorf("env = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,311 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iheanyi/grove/internal/output"
+	"github.com/iheanyi/grove/internal/project"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/spf13/cobra"
+)
+
+var groupCmd = &cobra.Command{
+	Use:   "group",
+	Short: "Start and stop groups of servers that run together",
+	Long: `Manage named groups of worktrees that run together, such as a frontend
+and a backend in separate repos.
+
+Groups are defined in the global config as lists of worktree names:
+
+  groups:
+    shop:
+      - storefront-checkout
+      - api-checkout
+
+'grove group start' starts every member and gives each one the others' URLs
+as <NAME>_URL, e.g. API_CHECKOUT_URL=http://localhost:3142, so a
+frontend on a branch talks to the backend on the same branch.
+
+Examples:
+  grove group                # List groups
+  grove group start shop     # Start all members
+  grove group status shop    # Show member status
+  grove group stop shop      # Stop all members`,
+	Args: cobra.NoArgs,
+	RunE: runGroupList,
+}
+
+var groupStartCmd = &cobra.Command{
+	Use:   "start <group>",
+	Short: "Start every server in a group",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runGroupStart,
+}
+
+var groupStopCmd = &cobra.Command{
+	Use:   "stop <group>",
+	Short: "Stop every server in a group",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runGroupStop,
+}
+
+var groupStatusCmd = &cobra.Command{
+	Use:   "status <group>",
+	Short: "Show the status of a group's servers",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runGroupStatus,
+}
+
+func init() {
+	groupStopCmd.Flags().DurationP("timeout", "t", 10*time.Second, "Timeout for graceful shutdown")
+	addOutputFlags(groupStopCmd)
+	addOutputFlags(groupStatusCmd)
+
+	groupCmd.AddCommand(groupStartCmd)
+	groupCmd.AddCommand(groupStopCmd)
+	groupCmd.AddCommand(groupStatusCmd)
+}
+
+func runGroupList(cmd *cobra.Command, args []string) error {
+	if len(cfg.Groups) == 0 {
+		fmt.Println("No groups configured")
+		fmt.Println("\nAdd groups to the 'groups' section of the grove config")
+		return nil
+	}
+
+	names := make([]string, 0, len(cfg.Groups))
+	for name := range cfg.Groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("%s: %s\n", name, strings.Join(cfg.Groups[name], ", "))
+	}
+	return nil
+}
+
+// groupMembers returns a configured group's worktree names
+func groupMembers(group string) ([]string, error) {
+	members, ok := cfg.Groups[group]
+	if !ok {
+		return nil, fmt.Errorf("no group named '%s' (run 'grove group' to list groups)", group)
+	}
+	if len(members) == 0 {
+		return nil, fmt.Errorf("group '%s' has no members", group)
+	}
+	return members, nil
+}
+
+// urlEnvVar returns the variable a server's URL is given to other servers
+// as, e.g. "api-checkout" -> API_CHECKOUT_URL
+func urlEnvVar(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(name) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	return b.String() + "_URL"
+}
+
+// plannedPort returns the port a server will start on, allocating one (and
+// marking it used) if it has never run, so its URL is known up front
+func plannedPort(server *registry.Server, used map[int]bool) (int, error) {
+	if projConfig, _ := project.Load(server.Path); projConfig != nil && projConfig.Port > 0 {
+		return projConfig.Port, nil
+	}
+	if server.Port > 0 {
+		return server.Port, nil
+	}
+	p, err := allocatePort(server.Name, server.Path, used)
+	if err != nil {
+		return 0, fmt.Errorf("failed to allocate port for '%s': %w", server.Name, err)
+	}
+	used[p] = true
+	return p, nil
+}
+
+// startRegistered starts a registered server by running 'grove start' in its
+// worktree, with extra environment variables for the server. The stored
+// command is only used when .grove.yaml doesn't say how to start it.
+func startRegistered(server *registry.Server, serverPort int, env []string) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	args := []string{"start", "--port", strconv.Itoa(serverPort)}
+	projConfig, _ := project.Load(server.Path)
+	configured := projConfig != nil && (projConfig.Command != "" || projConfig.HasProcesses() || projConfig.IsCompose())
+	if !configured && len(server.Command) > 0 && !server.IsMultiProcess() && !server.IsCompose() {
+		args = append(append(args, "--"), server.Command...)
+	}
+
+	cmd := exec.Command(executable, args...)
+	cmd.Dir = server.Path
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func runGroupStart(cmd *cobra.Command, args []string) error {
+	group := args[0]
+	members, err := groupMembers(group)
+	if err != nil {
+		return err
+	}
+
+	reg, err := registry.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+
+	// Resolve every member's URL first, so each one starts knowing the others
+	servers := make([]*registry.Server, len(members))
+	ports := make([]int, len(members))
+	used := reg.GetUsedPorts()
+	for i, name := range members {
+		server, ok := reg.Get(name)
+		if !ok {
+			return fmt.Errorf("group '%s': '%s' is not a registered worktree (run 'grove discover' in its repo)", group, name)
+		}
+		if server.IsRunning() {
+			ports[i] = server.Port
+		} else if ports[i], err = plannedPort(server, used); err != nil {
+			return err
+		}
+		servers[i] = server
+	}
+
+	urls := make([]string, len(members))
+	for i, server := range servers {
+		if server.IsRunning() {
+			urls[i] = server.URL
+		} else {
+			urls[i] = cfg.ServerURL(server.Name, ports[i])
+		}
+	}
+
+	failed := 0
+	for i, server := range servers {
+		if server.IsRunning() {
+			fmt.Printf("✓ %s already running at %s\n", server.Name, server.URL)
+			continue
+		}
+
+		var env []string
+		for j, other := range servers {
+			if j != i {
+				env = append(env, fmt.Sprintf("%s=%s", urlEnvVar(other.Name), urls[j]))
+			}
+		}
+
+		if err := startRegistered(server, ports[i], env); err != nil {
+			fmt.Printf("✗ %s: failed to start: %v\n", server.Name, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d servers in group '%s' failed to start", failed, len(servers), group)
+	}
+	return nil
+}
+
+func runGroupStop(cmd *cobra.Command, args []string) error {
+	group := args[0]
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	members, err := groupMembers(group)
+	if err != nil {
+		return err
+	}
+
+	reg, err := registry.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+
+	return runWithOutput(cmd, func() (any, error) {
+		result := output.StopResult{Stopped: []output.Server{}}
+
+		var lastErr error
+		for _, name := range members {
+			server, ok := reg.Get(name)
+			if !ok || !server.IsRunning() {
+				continue
+			}
+			fmt.Printf("Stopping %s...\n", name)
+			if err := stopServerNoReload(reg, name, timeout); err != nil {
+				fmt.Printf("Error stopping '%s': %v\n", name, err)
+				result.Errors = append(result.Errors, output.ItemError{Name: name, Error: err.Error()})
+				lastErr = err
+				continue
+			}
+			if stopped, ok := reg.Get(name); ok {
+				result.Stopped = append(result.Stopped, output.NewServer(stopped))
+			}
+		}
+
+		if len(result.Stopped) == 0 && len(result.Errors) == 0 {
+			fmt.Printf("No servers running in group '%s'\n", group)
+		}
+
+		// Reload proxy once after all servers are stopped (only when proxied)
+		if cfg.UsesProxy() && len(result.Stopped) > 0 {
+			if err := ReloadProxy(); err != nil {
+				fmt.Printf("Warning: failed to reload proxy: %v\n", err)
+			}
+		}
+
+		return result, lastErr
+	})
+}
+
+func runGroupStatus(cmd *cobra.Command, args []string) error {
+	group := args[0]
+	members, err := groupMembers(group)
+	if err != nil {
+		return err
+	}
+
+	reg, err := registry.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+
+	result := output.GroupStatus{Group: group, Members: []output.Server{}}
+	for _, name := range members {
+		if server, ok := reg.Get(name); ok {
+			result.Members = append(result.Members, output.NewServer(server))
+		} else {
+			result.Missing = append(result.Missing, name)
+		}
+	}
+
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	if format.IsMachine() {
+		return output.Write(os.Stdout, format, result)
+	}
+
+	fmt.Printf("Group: %s\n\n", group)
+	for _, member := range result.Members {
+		fmt.Printf("  %-24s %-12s %s\n", member.Name, formatStatus(registry.ServerStatus(member.Status)), member.URL)
+	}
+	for _, name := range result.Missing {
+		fmt.Printf("  %-24s %-12s %s\n", name, "? missing", "not a registered worktree")
+	}
+	return nil
+}
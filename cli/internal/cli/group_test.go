@@ -0,0 +1,15 @@
+package cli
+
+import "testing"
+
+func TestURLEnvVar(t *testing.T) {
+	for name, want := range map[string]string{
+		"api":                "API_URL",
+		"api-checkout":       "API_CHECKOUT_URL",
+		"storefront.feature": "STOREFRONT_FEATURE_URL",
+	} {
+		if got := urlEnvVar(name); got != want {
+			t.Errorf("urlEnvVar(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -32,7 +33,18 @@ This creates hooks in .claude/settings.json that:
 - Intercept git worktree add commands
 - Remind about documentation updates
 
-The hooks are project-local and won't affect other projects.`,
+By default, intercepted commands get a suggestion and still run. With
+--mode block (or claude_hooks.mode: block in the grove config) they're
+denied, so agents must use 'grove start' and 'grove new'. More commands can
+be intercepted with claude_hooks.dev_server_patterns and
+claude_hooks.worktree_patterns. Both are written into the hook scripts;
+reinstall after changing them.
+
+The hooks are project-local and won't affect other projects.
+
+Examples:
+  grove hooks install
+  grove hooks install --mode block`,
 	RunE: runHooksInstall,
 }
 
@@ -48,6 +60,8 @@ func init() {
 	rootCmd.AddCommand(hooksCmd)
 	hooksCmd.AddCommand(hooksInstallCmd)
 	hooksCmd.AddCommand(hooksUninstallCmd)
+
+	hooksInstallCmd.Flags().String("mode", "", "What to do with intercepted commands: warn or block (default: claude_hooks.mode, else warn)")
 }
 
 // Hook script content
@@ -104,6 +118,11 @@ const groveDevServerHook = `#!/bin/bash
 # Grove PreToolUse hook - intercepts direct dev server commands
 set -e
 
+# Set by 'grove hooks install' from --mode and claude_hooks in the grove
+# config; reinstall to change
+mode={{mode}}
+pattern={{pattern}}
+
 input=$(cat)
 tool_name=$(echo "$input" | jq -r '.tool_name // ""')
 command=$(echo "$input" | jq -r '.tool_input.command // ""')
@@ -113,8 +132,19 @@ if [ "$tool_name" != "Bash" ]; then
   exit 0
 fi
 
+# Commands already run through grove are fine
+if echo "$command" | grep -qE '^[[:space:]]*grove[[:space:]]'; then
+  exit 0
+fi
+
 # Check for common dev server commands
-if echo "$command" | grep -qE '(npm run dev|yarn dev|pnpm dev|rails s|rails server|bin/dev|python.*manage\.py.*runserver|go run|cargo run.*server)'; then
+if echo "$command" | grep -qE "$pattern"; then
+  if [ "$mode" = "block" ]; then
+    reason="Start dev servers with 'grove start $command' instead of running them directly. Grove sets PORT, allocates a consistent port per worktree and manages logs."
+    jq -n --arg reason "$reason" '{hookSpecificOutput: {hookEventName: "PreToolUse", permissionDecision: "deny", permissionDecisionReason: $reason}}'
+    exit 0
+  fi
+
   echo "💡 Consider using 'grove start $command' instead."
   echo "   Grove automatically:"
   echo "   - Sets PORT env var (your server should use process.env.PORT or ENV['PORT'])"
@@ -133,6 +163,11 @@ const groveWorktreeHook = `#!/bin/bash
 # Grove PreToolUse hook - intercepts git worktree commands
 set -e
 
+# Set by 'grove hooks install' from --mode and claude_hooks in the grove
+# config; reinstall to change
+mode={{mode}}
+pattern={{pattern}}
+
 input=$(cat)
 tool_name=$(echo "$input" | jq -r '.tool_name // ""')
 command=$(echo "$input" | jq -r '.tool_input.command // ""')
@@ -143,7 +178,13 @@ if [ "$tool_name" != "Bash" ]; then
 fi
 
 # Check for git worktree add
-if echo "$command" | grep -qE 'git worktree add'; then
+if echo "$command" | grep -qE "$pattern"; then
+  if [ "$mode" = "block" ]; then
+    reason="Create worktrees with 'grove new <branch-name>' instead of 'git worktree add', so they're created in the configured location and registered with grove."
+    jq -n --arg reason "$reason" '{hookSpecificOutput: {hookEventName: "PreToolUse", permissionDecision: "deny", permissionDecisionReason: $reason}}'
+    exit 0
+  fi
+
   echo "💡 Consider using 'grove new <branch-name>' instead of 'git worktree add'."
   echo "   Grove automatically:"
   echo "   - Creates worktrees in a consistent location (configurable via worktrees_dir)"
@@ -159,6 +200,24 @@ fi
 exit 0
 `
 
+// Commands the PreToolUse hooks match, before the configured patterns
+var (
+	defaultDevServerPatterns = []string{
+		`npm run dev`, `yarn dev`, `pnpm dev`, `rails s`, `rails server`, `bin/dev`,
+		`python.*manage\.py.*runserver`, `go run`, `cargo run.*server`,
+	}
+	defaultWorktreePatterns = []string{`git worktree add`}
+)
+
+// renderPolicyHook fills in a PreToolUse hook's mode and the regexp of the
+// commands it matches
+func renderPolicyHook(script, mode string, patterns []string) string {
+	return strings.NewReplacer(
+		"{{mode}}", shellQuoteArgs([]string{mode}),
+		"{{pattern}}", shellQuoteArgs([]string{"(" + strings.Join(patterns, "|") + ")"}),
+	).Replace(script)
+}
+
 const groveDocReminderHook = `#!/bin/bash
 # Grove Stop hook - reminds about documentation and task status updates
 set -e
@@ -229,6 +288,17 @@ type Hook struct {
 }
 
 func runHooksInstall(cmd *cobra.Command, args []string) error {
+	mode, _ := cmd.Flags().GetString("mode")
+	if mode == "" {
+		mode = cfg.ClaudeHooks.Mode
+	}
+	if mode == "" {
+		mode = "warn"
+	}
+	if mode != "warn" && mode != "block" {
+		return fmt.Errorf("invalid mode '%s' (use warn or block)", mode)
+	}
+
 	// Ensure .claude directory exists
 	claudeDir := ".claude"
 	if err := os.MkdirAll(claudeDir, 0755); err != nil {
@@ -244,8 +314,8 @@ func runHooksInstall(cmd *cobra.Command, args []string) error {
 	// Write hook scripts
 	hookScripts := map[string]string{
 		"grove-session-start.sh": groveSessionStartHook,
-		"grove-dev-server.sh":    groveDevServerHook,
-		"grove-worktree.sh":      groveWorktreeHook,
+		"grove-dev-server.sh":    renderPolicyHook(groveDevServerHook, mode, append(defaultDevServerPatterns, cfg.ClaudeHooks.DevServerPatterns...)),
+		"grove-worktree.sh":      renderPolicyHook(groveWorktreeHook, mode, append(defaultWorktreePatterns, cfg.ClaudeHooks.WorktreePatterns...)),
 		"grove-doc-reminder.sh":  groveDocReminderHook,
 	}
 
@@ -338,8 +408,13 @@ func runHooksInstall(cmd *cobra.Command, args []string) error {
 	fmt.Println()
 	fmt.Println("Hooks installed:")
 	fmt.Println("  - SessionStart: Shows grove server status")
-	fmt.Println("  - PreToolUse:   Suggests 'grove start' for dev server commands")
-	fmt.Println("  - PreToolUse:   Suggests 'grove new' for git worktree commands")
+	if mode == "block" {
+		fmt.Println("  - PreToolUse:   Blocks dev server commands not run with 'grove start'")
+		fmt.Println("  - PreToolUse:   Blocks git worktree commands in favor of 'grove new'")
+	} else {
+		fmt.Println("  - PreToolUse:   Suggests 'grove start' for dev server commands")
+		fmt.Println("  - PreToolUse:   Suggests 'grove new' for git worktree commands")
+	}
 	fmt.Println("  - Stop:         Reminds about documentation updates")
 	fmt.Println()
 	fmt.Println("Files created:")
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runPolicyHook runs a rendered PreToolUse hook on a Bash command
+func runPolicyHook(t *testing.T, script, command string) string {
+	t.Helper()
+	for _, tool := range []string{"bash", "jq"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			t.Skipf("%s not installed", tool)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "hook.sh")
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	input, _ := json.Marshal(map[string]any{"tool_name": "Bash", "tool_input": map[string]string{"command": command}})
+	cmd := exec.Command(path)
+	cmd.Stdin = strings.NewReader(string(input))
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("hook failed: %v", err)
+	}
+	return string(out)
+}
+
+func TestDevServerHook_Block(t *testing.T) {
+	script := renderPolicyHook(groveDevServerHook, "block", append(defaultDevServerPatterns, `mix phx\.server`))
+
+	for _, command := range []string{"npm run dev", "cd web && mix phx.server"} {
+		var out struct {
+			HookSpecificOutput struct {
+				PermissionDecision string `json:"permissionDecision"`
+			} `json:"hookSpecificOutput"`
+		}
+		if err := json.Unmarshal([]byte(runPolicyHook(t, script, command)), &out); err != nil {
+			t.Fatalf("%q: expected a JSON decision: %v", command, err)
+		}
+		if out.HookSpecificOutput.PermissionDecision != "deny" {
+			t.Errorf("%q: decision = %q, want deny", command, out.HookSpecificOutput.PermissionDecision)
+		}
+	}
+
+	for _, command := range []string{"grove start npm run dev", "npm test"} {
+		if out := runPolicyHook(t, script, command); out != "" {
+			t.Errorf("%q: expected no output, got %q", command, out)
+		}
+	}
+}
+
+func TestWorktreeHook_Warn(t *testing.T) {
+	script := renderPolicyHook(groveWorktreeHook, "warn", defaultWorktreePatterns)
+
+	out := runPolicyHook(t, script, "git worktree add ../feature -b feature")
+	if !strings.Contains(out, "grove new") || strings.Contains(out, "permissionDecision") {
+		t.Errorf("expected a suggestion without a decision, got %q", out)
+	}
+}
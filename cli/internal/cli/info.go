@@ -1,11 +1,10 @@
 package cli
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"os/exec"
 	"path/filepath"
-	"strings"
 
 	"github.com/iheanyi/grove/internal/config"
 	"github.com/iheanyi/grove/internal/registry"
@@ -149,7 +148,7 @@ func outputInfoText(wt *worktree.Info, reg *registry.Registry) error {
 	fmt.Println("CONFIGURATION")
 	fmt.Printf("  TLD:       %s\n", cfg.TLD)
 	fmt.Printf("  Config:    %s/config.yaml\n", config.ConfigDir())
-	fmt.Printf("  Registry:  %s/registry.json\n", config.ConfigDir())
+	fmt.Printf("  Registry:  %s\n", registry.Files()[0])
 
 	fmt.Println()
 
@@ -237,45 +236,19 @@ type worktreeListEntry struct {
 
 // listAllWorktrees lists all worktrees for a repository
 func listAllWorktrees(mainRepoPath string) ([]worktreeListEntry, error) {
-	cmd := exec.Command("git", "worktree", "list", "--porcelain")
-	cmd.Dir = mainRepoPath
-	output, err := cmd.Output()
+	list, err := gitOps().Worktrees(context.Background(), mainRepoPath)
 	if err != nil {
 		return nil, err
 	}
 
-	var worktrees []worktreeListEntry
-	var current worktreeListEntry
-
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-
-		if strings.HasPrefix(line, "worktree ") {
-			if current.Path != "" {
-				current.Name = filepath.Base(current.Path)
-				worktrees = append(worktrees, current)
-			}
-			current = worktreeListEntry{
-				Path: strings.TrimPrefix(line, "worktree "),
-			}
-		} else if strings.HasPrefix(line, "branch ") {
-			branchRef := strings.TrimPrefix(line, "branch ")
-			parts := strings.Split(branchRef, "/")
-			if len(parts) >= 3 {
-				current.Branch = strings.Join(parts[2:], "/")
-			}
-		} else if line == "detached" {
-			current.Branch = "(detached)"
+	worktrees := make([]worktreeListEntry, 0, len(list))
+	for _, wt := range list {
+		entry := worktreeListEntry{Name: filepath.Base(wt.Path), Path: wt.Path, Branch: wt.Branch}
+		if wt.Detached {
+			entry.Branch = "(detached)"
 		}
+		worktrees = append(worktrees, entry)
 	}
-
-	// Add the last entry
-	if current.Path != "" {
-		current.Name = filepath.Base(current.Path)
-		worktrees = append(worktrees, current)
-	}
-
 	return worktrees, nil
 }
 
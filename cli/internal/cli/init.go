@@ -1,9 +1,12 @@
 package cli
 
 import (
+	"bufio"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/iheanyi/grove/internal/project"
 	"github.com/iheanyi/grove/internal/worktree"
@@ -15,26 +18,53 @@ var initCmd = &cobra.Command{
 	Short: "Create a .grove.yaml configuration file",
 	Long: `Create a .grove.yaml configuration file in the current directory.
 
-Available templates:
+Without a template, grove inspects the project (Procfile, Gemfile,
+manage.py, package.json scripts, go.mod) and generates a config with the
+command, health check path, and env placeholders from .env.example. When
+several candidates are found you're asked to pick one.
+
+Built-in templates:
   rails   - Ruby on Rails project
+  nextjs  - Next.js project
+  django  - Django project
   node    - Node.js project
-  python  - Python project
   go      - Go project
 
+Your own templates can be added with 'grove templates add' and are listed
+by 'grove templates list'.
+
+Projects with a team config (.grove/config.yaml) don't need a .grove.yaml;
+without --template, the one created only names the worktree, for your own
+overrides.
+
 Examples:
-  grove init           # Create basic .grove.yaml
-  grove init rails     # Create Rails-specific .grove.yaml
-  grove init node      # Create Node.js-specific .grove.yaml`,
-	RunE:      runInit,
-	ValidArgs: []string{"rails", "node", "python", "go"},
+  grove init                   # Detect the stack and create .grove.yaml
+  grove init --yes             # Use the first detected candidate
+  grove init --template rails  # Create Rails-specific .grove.yaml
+  grove init node              # Create Node.js-specific .grove.yaml`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runInit,
 }
 
 func init() {
 	initCmd.Flags().BoolP("force", "f", false, "Overwrite existing .grove.yaml")
+	initCmd.Flags().StringP("template", "t", "", "Template to generate the config from")
+	initCmd.Flags().BoolP("yes", "y", false, "Use the first detected candidate without asking")
+	_ = initCmd.RegisterFlagCompletionFunc("template", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return getTemplateNames(), cobra.ShellCompDirectiveNoFileComp
+	})
 }
 
 func runInit(cmd *cobra.Command, args []string) error {
 	force, _ := cmd.Flags().GetBool("force")
+	yes, _ := cmd.Flags().GetBool("yes")
+	templateName, _ := cmd.Flags().GetString("template")
+	if len(args) > 0 {
+		if templateName != "" && templateName != args[0] {
+			return fmt.Errorf("template given as both argument (%s) and --template (%s)", args[0], templateName)
+		}
+		templateName = args[0]
+	}
 
 	// Check if .grove.yaml already exists
 	cwd, err := os.Getwd()
@@ -54,97 +84,85 @@ func runInit(cmd *cobra.Command, args []string) error {
 		name = wt.Name
 	}
 
-	// Get template
-	template := ""
-	if len(args) > 0 {
-		template = args[0]
+	// Generate config based on template, or on what the project looks like
+	projConfig := &project.Config{Name: name}
+	var detected *project.DetectedStack
+	if templateName != "" {
+		t, err := project.FindTemplateIn(templateDirs(), templateName)
+		if err != nil {
+			return fmt.Errorf("%w\nRun 'grove templates list' to see available templates", err)
+		}
+		projConfig = t.NewConfig(name)
+	} else if fileExists(project.TeamConfigPath(cwd)) {
+		// The team config has the command; .grove.yaml is for overrides
+	} else if stacks := project.DetectStacks(cwd); len(stacks) > 0 {
+		detected = stacks[0]
+		if len(stacks) > 1 && !yes && isInteractive() {
+			detected, err = chooseStack(stacks)
+			if err != nil {
+				return err
+			}
+		}
+		projConfig = detected.Config
+		projConfig.Name = name
 	}
 
-	// Generate config based on template
-	cfg := generateConfig(template, name)
-
 	// Save config
-	if err := cfg.Save(cwd); err != nil {
+	if err := projConfig.Save(cwd); err != nil {
 		return fmt.Errorf("failed to write .grove.yaml: %w", err)
 	}
 
 	fmt.Printf("Created %s\n", configPath)
-	if template != "" {
-		fmt.Printf("Using template: %s\n", template)
+	if templateName != "" {
+		fmt.Printf("Using template: %s\n", templateName)
+	} else if fileExists(project.TeamConfigPath(cwd)) {
+		fmt.Printf("Using the team config in %s; add your own overrides to .grove.yaml\n", project.TeamConfigPath(cwd))
+	} else if detected != nil {
+		fmt.Printf("Detected %s (from %s): %s\n", detected.Stack, detected.Source, describeStack(detected))
+	} else {
+		fmt.Println("No known stack detected; set 'command' in .grove.yaml")
 	}
 
 	return nil
 }
 
-func generateConfig(template, name string) *project.Config {
-	switch template {
-	case "rails":
-		return &project.Config{
-			Name:    name,
-			Command: "bin/dev",
-			Env: map[string]string{
-				"RAILS_ENV": "development",
-			},
-			HealthCheck: project.HealthCheckConfig{
-				Path: "/up",
-			},
-			Hooks: project.HooksConfig{
-				BeforeStart: []string{
-					"bundle install",
-					"rails db:migrate",
-				},
-			},
+// chooseStack asks which of several detected stacks to use
+func chooseStack(stacks []*project.DetectedStack) (*project.DetectedStack, error) {
+	fmt.Println("Found several ways to run this project:")
+	for i, s := range stacks {
+		fmt.Printf("  %d. %-8s %s  (%s)\n", i+1, s.Stack, describeStack(s), s.Source)
+	}
+	fmt.Println()
+
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Printf("Choose [1-%d] (default 1): ", len(stacks))
+		input, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("failed to read input: %w", err)
 		}
 
-	case "node":
-		return &project.Config{
-			Name:    name,
-			Command: "npm run dev",
-			Env: map[string]string{
-				"NODE_ENV": "development",
-			},
-			Hooks: project.HooksConfig{
-				BeforeStart: []string{
-					"npm install",
-				},
-			},
+		input = strings.TrimSpace(input)
+		if input == "" {
+			return stacks[0], nil
 		}
-
-	case "python":
-		return &project.Config{
-			Name:    name,
-			Command: "python manage.py runserver 0.0.0.0:$PORT",
-			Env: map[string]string{
-				"DJANGO_SETTINGS_MODULE": "config.settings.development",
-			},
-			Hooks: project.HooksConfig{
-				BeforeStart: []string{
-					"pip install -r requirements.txt",
-					"python manage.py migrate",
-				},
-			},
-		}
-
-	case "go":
-		return &project.Config{
-			Name:    name,
-			Command: "go run .",
-			Env: map[string]string{
-				"GO_ENV": "development",
-			},
-			Hooks: project.HooksConfig{
-				BeforeStart: []string{
-					"go mod download",
-				},
-			},
+		if n, err := strconv.Atoi(input); err == nil && n >= 1 && n <= len(stacks) {
+			return stacks[n-1], nil
 		}
+		fmt.Println("Invalid choice")
+	}
+}
 
-	default:
-		// Basic config
-		return &project.Config{
-			Name:    name,
-			Command: "",
-			Env:     map[string]string{},
-		}
+// describeStack summarizes what a detected stack runs
+func describeStack(s *project.DetectedStack) string {
+	if s.Config.HasProcesses() {
+		return strings.Join(s.Config.ProcessNames(), ", ")
 	}
+	return s.Config.Command
+}
+
+// isInteractive returns true if stdin is a terminal
+func isInteractive() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
 }
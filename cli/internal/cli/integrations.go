@@ -0,0 +1,342 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var integrationsCmd = &cobra.Command{
+	Use:   "integrations",
+	Short: "Set up grove in AI coding tools",
+	Long: `Set up grove in the AI coding tools on this machine: the MCP server, plus
+each tool's way of telling agents to use grove.
+
+With no subcommand, lists the supported tools and which are installed.`,
+	Args: cobra.NoArgs,
+	RunE: runIntegrationsList,
+}
+
+var integrationsInstallCmd = &cobra.Command{
+	Use:   "install [tool]",
+	Short: "Install grove's integration for an AI coding tool",
+	Long: `Install grove's integration for an AI coding tool:
+
+  claude-code  MCP server, and hooks steering agents to grove ('grove hooks install')
+  gemini       MCP server in settings.json, and a grove extension with agent guidance
+  opencode     MCP server in opencode.json, and agent guidance in its instructions
+  cursor       MCP server in mcp.json, and an always-applied rule with agent guidance
+  copilot      MCP server
+  codex        MCP server
+
+Files are written to the current project, or to the tool's user config with
+--global (Claude Code's MCP server and Codex are always user-wide).
+
+Examples:
+  grove integrations install gemini
+  grove integrations install cursor --global
+  grove integrations install --all       # Every tool found on this machine`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runIntegrationsInstall,
+}
+
+func init() {
+	integrationsInstallCmd.Flags().BoolP("global", "g", false, "Install in the tool's user config instead of the current project")
+	integrationsInstallCmd.Flags().Bool("all", false, "Install for every tool found on this machine")
+
+	integrationsCmd.GroupID = "config"
+	rootCmd.AddCommand(integrationsCmd)
+	integrationsCmd.AddCommand(integrationsInstallCmd)
+}
+
+// integration is an AI coding tool grove can be set up in
+type integration struct {
+	Name  string
+	Title string
+
+	// Binaries and Dirs (relative to the home directory) detect the tool
+	Binaries []string
+	Dirs     []string
+
+	Install func(grovePath string, global bool) error
+}
+
+var integrations = []integration{
+	{
+		Name: "claude-code", Title: "Claude Code",
+		Binaries: []string{"claude"}, Dirs: []string{".claude"},
+		Install: func(grovePath string, global bool) error {
+			if err := installForClaudeCode(grovePath); err != nil {
+				return err
+			}
+			if global {
+				fmt.Println("\nSkipping hooks: they're installed per project (run 'grove hooks install' in one)")
+				return nil
+			}
+			fmt.Println()
+			return runHooksInstall(hooksInstallCmd, nil)
+		},
+	},
+	{
+		Name: "gemini", Title: "Gemini CLI",
+		Binaries: []string{"gemini"}, Dirs: []string{".gemini"},
+		Install: func(grovePath string, global bool) error {
+			if err := installForGemini(grovePath, global); err != nil {
+				return err
+			}
+			return installGeminiExtension(global)
+		},
+	},
+	{
+		Name: "opencode", Title: "OpenCode",
+		Binaries: []string{"opencode"}, Dirs: []string{".config/opencode"},
+		Install: func(grovePath string, global bool) error {
+			if err := installForOpenCode(grovePath, global); err != nil {
+				return err
+			}
+			return installOpenCodeInstructions(global)
+		},
+	},
+	{
+		Name: "cursor", Title: "Cursor",
+		Binaries: []string{"cursor", "cursor-agent"}, Dirs: []string{".cursor"},
+		Install: func(grovePath string, global bool) error {
+			if err := installForCursor(grovePath, global); err != nil {
+				return err
+			}
+			return installCursorRule(global)
+		},
+	},
+	{
+		Name: "copilot", Title: "GitHub Copilot CLI",
+		Binaries: []string{"copilot"}, Dirs: []string{".copilot"},
+		Install: installForCopilot,
+	},
+	{
+		Name: "codex", Title: "Codex",
+		Binaries: []string{"codex"}, Dirs: []string{".codex"},
+		Install: func(grovePath string, global bool) error {
+			return installForCodex(grovePath)
+		},
+	},
+}
+
+// detected reports whether the tool is installed: one of its binaries is
+// on PATH or its config directory exists
+func (i integration) detected(home string) bool {
+	for _, bin := range i.Binaries {
+		if _, err := exec.LookPath(bin); err == nil {
+			return true
+		}
+	}
+	for _, dir := range i.Dirs {
+		if info, err := os.Stat(filepath.Join(home, dir)); err == nil && info.IsDir() {
+			return true
+		}
+	}
+	return false
+}
+
+func findIntegration(name string) (integration, error) {
+	for _, i := range integrations {
+		if i.Name == name {
+			return i, nil
+		}
+	}
+	names := make([]string, len(integrations))
+	for n, i := range integrations {
+		names[n] = i.Name
+	}
+	return integration{}, fmt.Errorf("unknown tool '%s' (supported: %s)", name, strings.Join(names, ", "))
+}
+
+func runIntegrationsList(cmd *cobra.Command, args []string) error {
+	home, _ := os.UserHomeDir()
+
+	fmt.Printf("%-12s %-20s %s\n", "TOOL", "NAME", "INSTALLED")
+	for _, i := range integrations {
+		installed := "no"
+		if i.detected(home) {
+			installed = "yes"
+		}
+		fmt.Printf("%-12s %-20s %s\n", i.Name, i.Title, installed)
+	}
+	fmt.Println("\nRun 'grove integrations install <tool>' to set one up, or --all for every installed tool")
+	return nil
+}
+
+func runIntegrationsInstall(cmd *cobra.Command, args []string) error {
+	global, _ := cmd.Flags().GetBool("global")
+	all, _ := cmd.Flags().GetBool("all")
+
+	var selected []integration
+	switch {
+	case all && len(args) > 0:
+		return fmt.Errorf("--all can't be used with a tool name")
+	case all:
+		home, _ := os.UserHomeDir()
+		for _, i := range integrations {
+			if i.detected(home) {
+				selected = append(selected, i)
+			}
+		}
+		if len(selected) == 0 {
+			return fmt.Errorf("no supported tools found (run 'grove integrations' to list them)")
+		}
+	case len(args) == 1:
+		i, err := findIntegration(args[0])
+		if err != nil {
+			return err
+		}
+		selected = []integration{i}
+	default:
+		return fmt.Errorf("name a tool or use --all (run 'grove integrations' to list them)")
+	}
+
+	grovePath, err := groveBinaryPath()
+	if err != nil {
+		return err
+	}
+
+	for n, i := range selected {
+		if len(selected) > 1 {
+			if n > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("== %s ==\n", i.Title)
+		}
+		if err := i.Install(grovePath, global); err != nil {
+			return fmt.Errorf("%s: %w", i.Title, err)
+		}
+	}
+	return nil
+}
+
+// agentGuidance tells agents how to work with grove. It's installed where
+// a tool reads standing instructions.
+const agentGuidance = `# Grove
+
+This project uses grove (https://github.com/iheanyi/grove) to manage git
+worktrees and their dev servers.
+
+- Start dev servers with 'grove start <command>' (e.g. 'grove start npm run dev')
+  instead of running them directly. Grove sets PORT, gives each worktree a
+  consistent port and URL, and keeps logs ('grove logs').
+- Create worktrees with 'grove new <branch>' instead of 'git worktree add'.
+- Use 'grove ls' to see worktrees and servers, 'grove url' for the current
+  worktree's URL, and 'grove stop' to stop its server.
+- The grove MCP server (grove_list, grove_start, grove_stop, grove_url,
+  grove_status, grove_restart, grove_new) does the same from tools.
+`
+
+// installGeminiExtension installs a Gemini CLI extension whose context file
+// is the agent guidance. The MCP server is configured in settings.json.
+func installGeminiExtension(global bool) error {
+	dir := filepath.Join(".gemini", "extensions", "grove")
+	if global {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("failed to get home directory: %w", err)
+		}
+		dir = filepath.Join(home, dir)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create extension directory: %w", err)
+	}
+
+	manifest, err := json.MarshalIndent(map[string]interface{}{
+		"name":            "grove",
+		"version":         strings.TrimPrefix(Version, "v"),
+		"contextFileName": "GROVE.md",
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "gemini-extension.json"), manifest, 0644); err != nil {
+		return fmt.Errorf("failed to write extension: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "GROVE.md"), []byte(agentGuidance), 0644); err != nil {
+		return fmt.Errorf("failed to write extension: %w", err)
+	}
+
+	fmt.Printf("\n✓ Installed grove extension for Gemini CLI\n")
+	fmt.Printf("  Extension: %s\n", dir)
+	return nil
+}
+
+// installOpenCodeInstructions writes the agent guidance and adds it to
+// opencode.json's instructions
+func installOpenCodeInstructions(global bool) error {
+	configPath := "opencode.json"
+	guidancePath := filepath.Join(".opencode", "grove.md")
+	if global {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("failed to get home directory: %w", err)
+		}
+		configPath = filepath.Join(home, ".config", "opencode", "opencode.json")
+		guidancePath = filepath.Join(home, ".config", "opencode", "grove.md")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(guidancePath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(guidancePath, []byte(agentGuidance), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", guidancePath, err)
+	}
+
+	config := make(map[string]interface{})
+	if data, err := os.ReadFile(configPath); err == nil {
+		if err := json.Unmarshal(data, &config); err != nil {
+			return fmt.Errorf("failed to parse existing config at %s: %w", configPath, err)
+		}
+	}
+	var instructions []interface{}
+	if existing, ok := config["instructions"].([]interface{}); ok {
+		instructions = existing
+	}
+	if !slices.Contains(instructions, interface{}(guidancePath)) {
+		config["instructions"] = append(instructions, guidancePath)
+		data, err := json.MarshalIndent(config, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal config: %w", err)
+		}
+		if err := os.WriteFile(configPath, data, 0644); err != nil {
+			return fmt.Errorf("failed to write config to %s: %w", configPath, err)
+		}
+	}
+
+	fmt.Printf("\n✓ Added grove instructions for OpenCode\n")
+	fmt.Printf("  Instructions: %s\n", guidancePath)
+	return nil
+}
+
+// installCursorRule writes an always-applied project rule with the agent
+// guidance. Cursor's user rules live in its settings, not in files.
+func installCursorRule(global bool) error {
+	if global {
+		fmt.Println("\nSkipping the grove rule: Cursor's user rules can't be installed from files.")
+		fmt.Println("Run 'grove integrations install cursor' in a project to add it there.")
+		return nil
+	}
+
+	dir := filepath.Join(".cursor", "rules")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create rules directory: %w", err)
+	}
+	path := filepath.Join(dir, "grove.mdc")
+	rule := "---\ndescription: Use grove for dev servers and worktrees\nalwaysApply: true\n---\n\n" + agentGuidance
+	if err := os.WriteFile(path, []byte(rule), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	fmt.Printf("\n✓ Added grove rule for Cursor\n")
+	fmt.Printf("  Rule: %s\n", path)
+	return nil
+}
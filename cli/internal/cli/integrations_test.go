@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIntegrationDetected(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("PATH", t.TempDir())
+	if err := os.MkdirAll(filepath.Join(home, ".config", "opencode"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"opencode", "gemini"} {
+		i, err := findIntegration(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := i.detected(home), name == "opencode"; got != want {
+			t.Errorf("%s detected = %v, want %v", name, got, want)
+		}
+	}
+
+	if _, err := findIntegration("vim"); err == nil {
+		t.Error("expected an error for an unknown tool")
+	}
+}
+
+func TestInstallOpenCodeInstructions(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.WriteFile("opencode.json", []byte(`{"instructions": ["AGENTS.md"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Installing twice adds the instructions once
+	for range 2 {
+		if err := installOpenCodeInstructions(false); err != nil {
+			t.Fatalf("installOpenCodeInstructions() error = %v", err)
+		}
+	}
+
+	data, err := os.ReadFile("opencode.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var config struct {
+		Instructions []string `json:"instructions"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"AGENTS.md", filepath.Join(".opencode", "grove.md")}
+	if len(config.Instructions) != 2 || config.Instructions[0] != want[0] || config.Instructions[1] != want[1] {
+		t.Errorf("instructions = %v, want %v", config.Instructions, want)
+	}
+	if _, err := os.Stat(want[1]); err != nil {
+		t.Errorf("expected guidance file: %v", err)
+	}
+}
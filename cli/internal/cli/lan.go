@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/lan"
+	"github.com/iheanyi/grove/internal/output"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/skip2/go-qrcode"
+	"github.com/spf13/cobra"
+)
+
+var lanCmd = &cobra.Command{
+	Use:   "lan [name]",
+	Short: "Show servers' URLs on the local network, with QR codes",
+	Long: `Print the URL each running server is reachable at from other devices on
+the local network, e.g. to test a branch on your phone, with a QR code to
+scan. With a name, only that server is shown.
+
+In subdomain and path mode the proxy serves each server to the network
+over plain HTTP at this machine's IP address, on the server's port plus
+lan_port_offset (10000 by default), once it listens beyond this machine:
+
+  proxy_listen_address: 0.0.0.0
+
+Restart the proxy after changing it. Those ports answer to the IP address
+and, with mDNS on, the servers' .local names; requests for other host
+names are refused. In port mode the URLs go straight to
+the servers' ports, so the servers must listen on every interface (e.g.
+HOST=0.0.0.0), not just localhost.
+
+Examples:
+  grove lan                  # Every running server
+  grove lan feature-auth     # One server
+  grove lan --no-qr          # URLs only`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runLAN,
+}
+
+func init() {
+	lanCmd.Flags().Bool("no-qr", false, "Don't print QR codes")
+	addOutputFlags(lanCmd)
+}
+
+func runLAN(cmd *cobra.Command, args []string) error {
+	noQR, _ := cmd.Flags().GetBool("no-qr")
+
+	if cfg.UsesProxy() && !cfg.ServesLAN() {
+		return fmt.Errorf("the proxy isn't serving the network; set proxy_listen_address: 0.0.0.0 in %s and restart the proxy ('grove proxy stop' then 'grove proxy start')", config.ConfigPath())
+	}
+
+	reg, err := registry.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	var servers []*registry.Server
+	if len(args) > 0 {
+		server, ok := reg.Get(args[0])
+		if !ok {
+			return fmt.Errorf("no server registered for '%s'", args[0])
+		}
+		servers = append(servers, server)
+	} else {
+		for _, server := range reg.List() {
+			if server.IsRunning() {
+				servers = append(servers, server)
+			}
+		}
+	}
+
+	ip, err := lan.IP()
+	if err != nil {
+		return err
+	}
+	result := collectLAN(servers, ip)
+	if format.IsMachine() {
+		return output.Write(os.Stdout, format, result)
+	}
+
+	if len(result.Servers) == 0 {
+		fmt.Println("No running servers. Start one with 'grove start', or name a server.")
+		return nil
+	}
+
+	for i, server := range result.Servers {
+		if i > 0 {
+			fmt.Println()
+		}
+		status := ""
+		if server.Status != string(registry.StatusRunning) {
+			status = fmt.Sprintf(" (%s)", server.Status)
+		}
+		fmt.Printf("%s%s\n  %s\n", server.Name, status, server.URL)
+		if server.MDNSURL != "" {
+			fmt.Printf("  %s\n", server.MDNSURL)
+		}
+		fmt.Printf("  locally %s\n", server.LocalURL)
+		if !noQR {
+			qr, err := qrcode.New(server.URL, qrcode.Low)
+			if err != nil {
+				return fmt.Errorf("failed to generate QR code: %w", err)
+			}
+			fmt.Print(qr.ToSmallString(false))
+		}
+	}
+
+	if !cfg.UsesProxy() {
+		fmt.Println("\nThese are the servers' own ports: they must listen on every interface (e.g. HOST=0.0.0.0), not just localhost.")
+	} else if proxy := reg.GetProxy(); !proxy.IsRunning() || !isProcessRunning(proxy.PID) {
+		fmt.Println("\nThe proxy isn't running; start it with 'grove proxy start'.")
+	}
+	return nil
+}
+
+// collectLAN returns the URLs servers are reachable at from the network
+// through ip, sorted by name. Servers whose LAN port would be out of range
+// are left out.
+func collectLAN(servers []*registry.Server, ip net.IP) output.LANAccess {
+	result := output.LANAccess{IP: ip.String(), Servers: []output.LANServer{}}
+	for _, server := range servers {
+		entry := output.LANServer{
+			Name:     server.Name,
+			LocalURL: server.URL,
+			Status:   string(server.Status),
+		}
+		if cfg.UsesProxy() {
+			p := cfg.LANPort(server.Port)
+			if p == 0 {
+				continue
+			}
+			entry.URL = lan.URL(ip, p)
+			if cfg.MDNS.Enabled {
+				entry.MDNSURL = fmt.Sprintf("http://%s:%d", strings.TrimSuffix(mdnsHost(server.Name), "."), p)
+			}
+		} else {
+			entry.URL = lan.URL(ip, server.Port)
+			entry.Direct = true
+		}
+		result.Servers = append(result.Servers, entry)
+	}
+	sort.Slice(result.Servers, func(i, j int) bool { return result.Servers[i].Name < result.Servers[j].Name })
+	return result
+}
@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"net"
+	"testing"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+func TestCollectLAN(t *testing.T) {
+	useTestEnv(t)
+	ip := net.ParseIP("192.168.1.20")
+	servers := []*registry.Server{
+		{Name: "web", Port: 3042, URL: "https://web.localhost", Status: registry.StatusRunning},
+		{Name: "api", Port: 3001, URL: "https://api.localhost", Status: registry.StatusRunning},
+		{Name: "high", Port: 60000, URL: "https://high.localhost", Status: registry.StatusRunning},
+	}
+
+	cfg.URLMode = config.URLModeSubdomain
+	result := collectLAN(servers, ip)
+	if len(result.Servers) != 2 {
+		t.Fatalf("servers = %+v, want api and web; high's LAN port is out of range", result.Servers)
+	}
+	if got := result.Servers[0]; got.Name != "api" || got.URL != "http://192.168.1.20:13001" || got.Direct {
+		t.Errorf("api = %+v, want the proxy's LAN port", got)
+	}
+
+	cfg.URLMode = config.URLModePort
+	result = collectLAN(servers, ip)
+	if got := result.Servers[2]; got.Name != "web" || got.URL != "http://192.168.1.20:3042" || !got.Direct {
+		t.Errorf("web = %+v, want its own port in port mode", got)
+	}
+}
@@ -2,14 +2,17 @@ package cli
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
 	"github.com/iheanyi/grove/internal/loghighlight"
+	"github.com/iheanyi/grove/internal/output"
 	"github.com/iheanyi/grove/internal/registry"
 	"github.com/iheanyi/grove/internal/worktree"
 	"github.com/spf13/cobra"
@@ -26,26 +29,61 @@ Logs are syntax-highlighted with colors for:
   - Status codes (2xx green, 4xx orange, 5xx red)
   - Timestamps, durations, Rails patterns
 
+--since and --grep query the log: lines are timestamped with the last date
+and time seen in the log, so continuation lines like stack traces go with
+the line before them. With either, all matching lines are shown unless -n
+is given.
+
+--json writes one JSON object per line, {ts, server, line, level}, for
+scripts and agents (see 'grove schema logs').
+
 Examples:
   grove logs              # Stream logs for current worktree
   grove logs feature-auth # Stream logs for named server
   grove logs -n 50        # Show last 50 lines
   grove logs -f           # Follow logs (stream new lines)
-  grove logs --no-color   # Disable syntax highlighting`,
+  grove logs --no-color   # Disable syntax highlighting
+  grove logs --since 10m --grep ERROR        # Errors in the last 10 minutes
+  grove logs api --json --grep '(?i)timeout' # Matching lines as JSON`,
 	RunE: runLogs,
 }
 
-var logsNoColor bool
-
 func init() {
 	logsCmd.Flags().IntP("lines", "n", 20, "Number of lines to show")
 	logsCmd.Flags().BoolP("follow", "f", false, "Follow logs (stream new lines)")
-	logsCmd.Flags().BoolVar(&logsNoColor, "no-color", false, "Disable syntax highlighting")
+	logsCmd.Flags().Bool("no-color", false, "Disable syntax highlighting")
+	logsCmd.Flags().Bool("json", false, "Output one JSON object per line")
+	logsCmd.Flags().String("since", "", "Only lines since a duration ago (e.g. 10m, 2h, 1d) or an RFC 3339 time")
+	logsCmd.Flags().String("grep", "", "Only lines matching a regular expression")
 }
 
 func runLogs(cmd *cobra.Command, args []string) error {
 	lines, _ := cmd.Flags().GetInt("lines")
 	follow, _ := cmd.Flags().GetBool("follow")
+	noColor, _ := cmd.Flags().GetBool("no-color")
+	asJSON, _ := cmd.Flags().GetBool("json")
+	sinceFlag, _ := cmd.Flags().GetString("since")
+	grepFlag, _ := cmd.Flags().GetString("grep")
+
+	printer := &logPrinter{out: os.Stdout, json: asJSON, color: !noColor && !asJSON}
+	if sinceFlag != "" {
+		since, err := parseSince(sinceFlag, time.Now())
+		if err != nil {
+			return err
+		}
+		printer.since = since
+	}
+	if grepFlag != "" {
+		re, err := regexp.Compile(grepFlag)
+		if err != nil {
+			return fmt.Errorf("invalid --grep: %w", err)
+		}
+		printer.grep = re
+	}
+	// A query shows everything that matches unless asked for fewer
+	if (sinceFlag != "" || grepFlag != "") && !cmd.Flags().Changed("lines") {
+		lines = -1
+	}
 
 	// Load registry
 	reg, err := registry.Load()
@@ -80,24 +118,94 @@ func runLogs(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("log file does not exist: %s", server.LogFile)
 	}
 
+	printer.server = name
+
 	if follow {
-		return tailFollow(server.LogFile, name)
+		// Show what's already there when asked for a time range
+		if sinceFlag != "" {
+			if err := tailLines(server.LogFile, lines, printer); err != nil {
+				return err
+			}
+		}
+		// New lines are all recent
+		printer.since = time.Time{}
+		return tailFollow(server.LogFile, name, printer)
 	}
 
-	return tailLines(server.LogFile, lines)
+	return tailLines(server.LogFile, lines, printer)
 }
 
-// printLine prints a log line with optional highlighting
-func printLine(line string) {
-	if logsNoColor {
-		fmt.Println(line)
-	} else {
-		fmt.Println(loghighlight.Highlight(line))
+// logPrinter prints the log lines that match --since and --grep,
+// highlighted or as JSON
+type logPrinter struct {
+	out    io.Writer
+	server string
+	since  time.Time
+	grep   *regexp.Regexp
+	json   bool
+	color  bool
+
+	// last is the last timestamp seen, which lines without one inherit
+	last time.Time
+}
+
+// match returns the record for a log line, and whether it matches the
+// query. Lines must be passed in order.
+func (p *logPrinter) match(line string) (output.LogLine, bool) {
+	if ts, ok := loghighlight.Timestamp(line); ok {
+		p.last = ts
+	}
+	rec := output.LogLine{
+		Time:   output.TimePtr(p.last),
+		Server: p.server,
+		Line:   line,
+		Level:  loghighlight.Level(line),
+	}
+	if !p.since.IsZero() && p.last.Before(p.since) {
+		return rec, false
+	}
+	if p.grep != nil && !p.grep.MatchString(line) {
+		return rec, false
+	}
+	return rec, true
+}
+
+// write prints a matching record
+func (p *logPrinter) write(rec output.LogLine) {
+	switch {
+	case p.json:
+		enc := json.NewEncoder(p.out)
+		enc.SetEscapeHTML(false)
+		enc.Encode(rec) //nolint:errcheck // Nothing to do about a closed stdout
+	case p.color:
+		fmt.Fprintln(p.out, loghighlight.Highlight(rec.Line))
+	default:
+		fmt.Fprintln(p.out, rec.Line)
+	}
+}
+
+// printLine prints a log line if it matches
+func (p *logPrinter) printLine(line string) {
+	if rec, ok := p.match(line); ok {
+		p.write(rec)
 	}
 }
 
-// tailLines shows the last n lines of a file
-func tailLines(path string, n int) error {
+// parseSince parses --since: a duration before now (10m, 2h, 1d) or a time
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	d, err := parseAge(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --since %q (use e.g. 10m, 2h, 1d or 2006-01-02T15:04:05Z)", s)
+	}
+	return now.Add(-d), nil
+}
+
+// tailLines shows the last n matching lines of a file, or all of them if n
+// is negative
+func tailLines(path string, n int, p *logPrinter) error {
 	file, err := os.Open(path)
 	if err != nil {
 		return err
@@ -105,31 +213,37 @@ func tailLines(path string, n int) error {
 	defer file.Close()
 
 	// Read all lines (simple implementation)
-	var allLines []string
+	var matched []output.LogLine
 	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
 	for scanner.Scan() {
-		allLines = append(allLines, scanner.Text())
+		if rec, ok := p.match(scanner.Text()); ok {
+			matched = append(matched, rec)
+		}
 	}
 
 	if err := scanner.Err(); err != nil {
 		return err
 	}
+	if !p.since.IsZero() && p.last.IsZero() {
+		fmt.Fprintf(os.Stderr, "Warning: %s has no timestamps, so no lines can be matched with --since\n", path)
+	}
 
 	// Get last n lines
 	start := 0
-	if len(allLines) > n {
-		start = len(allLines) - n
+	if n >= 0 && len(matched) > n {
+		start = len(matched) - n
 	}
 
-	for _, line := range allLines[start:] {
-		printLine(line)
+	for _, rec := range matched[start:] {
+		p.write(rec)
 	}
 
 	return nil
 }
 
 // tailFollow follows the log file and prints new lines using file watching
-func tailFollow(path string, serverName string) error {
+func tailFollow(path string, serverName string, p *logPrinter) error {
 	file, err := os.Open(path)
 	if err != nil {
 		return err
@@ -137,10 +251,12 @@ func tailFollow(path string, serverName string) error {
 	defer file.Close()
 
 	// Print header so user knows what's happening
-	fmt.Printf("\n  Streaming logs for \033[1m%s\033[0m\n", serverName)
-	fmt.Printf("  Press \033[1mCtrl+C\033[0m to exit\n")
-	fmt.Println("  " + strings.Repeat("─", 40))
-	fmt.Println()
+	if !p.json {
+		fmt.Printf("\n  Streaming logs for \033[1m%s\033[0m\n", serverName)
+		fmt.Printf("  Press \033[1mCtrl+C\033[0m to exit\n")
+		fmt.Println("  " + strings.Repeat("─", 40))
+		fmt.Println()
+	}
 
 	// Seek to end to only show new content
 	offset, err := file.Seek(0, io.SeekEnd)
@@ -152,19 +268,19 @@ func tailFollow(path string, serverName string) error {
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
 		// Fall back to polling if fsnotify fails
-		return tailFollowPoll(file, offset)
+		return tailFollowPoll(file, offset, p)
 	}
 	defer watcher.Close()
 
 	if err := watcher.Add(path); err != nil {
 		// Fall back to polling
-		return tailFollowPoll(file, offset)
+		return tailFollowPoll(file, offset, p)
 	}
 
 	reader := bufio.NewReader(file)
 
 	// Print any lines that appeared since we opened the file
-	readAndPrintLines(reader)
+	readAndPrintLines(reader, p)
 
 	// Watch for changes
 	for {
@@ -174,7 +290,7 @@ func tailFollow(path string, serverName string) error {
 				return nil
 			}
 			if event.Has(fsnotify.Write) {
-				readAndPrintLines(reader)
+				readAndPrintLines(reader, p)
 			}
 		case err, ok := <-watcher.Errors:
 			if !ok {
@@ -186,7 +302,7 @@ func tailFollow(path string, serverName string) error {
 }
 
 // readAndPrintLines reads and prints all available lines from the reader
-func readAndPrintLines(reader *bufio.Reader) {
+func readAndPrintLines(reader *bufio.Reader, p *logPrinter) {
 	for {
 		line, err := reader.ReadString('\n')
 		if err != nil {
@@ -194,7 +310,7 @@ func readAndPrintLines(reader *bufio.Reader) {
 				// No more data available right now
 				// Print partial line if any
 				if len(line) > 0 {
-					printLine(line)
+					p.printLine(line)
 				}
 				return
 			}
@@ -205,12 +321,12 @@ func readAndPrintLines(reader *bufio.Reader) {
 		if len(line) > 0 && line[len(line)-1] == '\n' {
 			line = line[:len(line)-1]
 		}
-		printLine(line)
+		p.printLine(line)
 	}
 }
 
 // tailFollowPoll is a fallback that uses polling instead of file watching
-func tailFollowPoll(file *os.File, offset int64) error {
+func tailFollowPoll(file *os.File, offset int64, p *logPrinter) error {
 	reader := bufio.NewReader(file)
 
 	for {
@@ -227,6 +343,6 @@ func tailFollowPoll(file *os.File, offset int64) error {
 		if len(line) > 0 && line[len(line)-1] == '\n' {
 			line = line[:len(line)-1]
 		}
-		printLine(line)
+		p.printLine(line)
 	}
 }
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iheanyi/grove/internal/output"
+)
+
+func TestTailLines_Query(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	log := `booting
+2024-01-15T10:00:00Z INFO listening on :3000
+2024-01-15T10:05:00Z ERROR request failed
+  at handler.go:42
+2024-01-15T10:10:00Z WARN slow query <users>
+2024-01-15T10:12:00Z ERROR request failed again
+`
+	if err := os.WriteFile(path, []byte(log), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(p *logPrinter, n int) []output.LogLine {
+		t.Helper()
+		var buf bytes.Buffer
+		p.out, p.json, p.server = &buf, true, "app"
+		if err := tailLines(path, n, p); err != nil {
+			t.Fatal(err)
+		}
+		var recs []output.LogLine
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			if line == "" {
+				continue
+			}
+			var rec output.LogLine
+			if err := json.Unmarshal([]byte(line), &rec); err != nil {
+				t.Fatalf("invalid JSON line %q: %v", line, err)
+			}
+			recs = append(recs, rec)
+		}
+		return recs
+	}
+
+	all := run(&logPrinter{}, -1)
+	if len(all) != 6 {
+		t.Fatalf("got %d lines, want 6", len(all))
+	}
+	if all[0].Time != nil || all[0].Level != "" {
+		t.Errorf("line before any timestamp = %+v", all[0])
+	}
+	if all[3].Line != "  at handler.go:42" || all[3].Time == nil || !all[3].Time.Equal(*all[2].Time) {
+		t.Errorf("continuation line = %+v, want the previous line's time", all[3])
+	}
+	if all[4].Line != "2024-01-15T10:10:00Z WARN slow query <users>" || all[4].Level != "warn" || all[4].Server != "app" {
+		t.Errorf("warn line = %+v", all[4])
+	}
+
+	since := time.Date(2024, 1, 15, 10, 4, 0, 0, time.UTC)
+	got := run(&logPrinter{since: since, grep: regexp.MustCompile(`ERROR|handler`)}, -1)
+	if len(got) != 3 || got[0].Level != "error" || got[1].Line != "  at handler.go:42" || got[2].Line != "2024-01-15T10:12:00Z ERROR request failed again" {
+		t.Errorf("since + grep = %+v", got)
+	}
+
+	if got := run(&logPrinter{grep: regexp.MustCompile(`ERROR`)}, 1); len(got) != 1 || !strings.Contains(got[0].Line, "again") {
+		t.Errorf("grep -n 1 = %+v", got)
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"10m", now.Add(-10 * time.Minute)},
+		{"1d", now.Add(-24 * time.Hour)},
+		{"2024-01-15T10:00:00Z", time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseSince(tt.in, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("parseSince(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := parseSince("yesterday", now); err == nil {
+		t.Error("expected error for invalid --since")
+	}
+}
@@ -1,19 +1,28 @@
 package cli
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/lipgloss/table"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/iheanyi/grove/internal/checks"
+	"github.com/iheanyi/grove/internal/config"
 	"github.com/iheanyi/grove/internal/discovery"
 	"github.com/iheanyi/grove/internal/github"
+	"github.com/iheanyi/grove/internal/metrics"
 	"github.com/iheanyi/grove/internal/registry"
 	"github.com/iheanyi/grove/internal/styles"
+	"github.com/iheanyi/grove/internal/tasks"
+	"github.com/iheanyi/grove/internal/tunnel"
+	"github.com/iheanyi/grove/internal/usage"
 	"github.com/iheanyi/grove/internal/worktree"
 	"github.com/spf13/cobra"
 )
@@ -30,11 +39,20 @@ Examples:
   grove ls --servers            # Only show worktrees with servers
   grove ls --active             # Only show worktrees with any activity
   grove ls --tag frontend       # Filter by tag
+  grove ls --repo myapp         # Only worktrees of one repository
   grove ls --group activity     # Group by: active, recent, stale
   grove ls --group status       # Group by: running, stopped, error
+  grove ls --group tag          # Group by tag (tagged in each of its tags)
   grove ls --group none         # No grouping (flat list)
-  grove ls --full               # Show GitHub info (PR, CI, review status)
-  grove ls --all                # Show all discovered worktrees (default)`,
+  grove ls --prs                # Show PR number, CI, and review status
+  grove ls --full               # Show activity and PR info
+  grove ls --wide               # Show CPU and memory of running servers
+  grove ls --stale 14d          # Worktrees idle for 14 days (see 'grove archive')
+  grove ls --all                # Show all discovered worktrees (default)
+  grove ls --watch              # Refresh every 2s, including agent activity
+  grove ls --servers -w -n 5s   # Watch servers only, refreshing every 5s
+  grove ls --remote devbox      # List servers of grove on another machine (over SSH)
+  grove ls --network            # Servers advertised over mDNS on the local network`,
 	RunE: runLs,
 }
 
@@ -46,12 +64,53 @@ func init() {
 	lsCmd.Flags().Bool("running", false, "Only show running servers (deprecated, use --servers)")
 	lsCmd.Flags().Bool("fast", false, "Skip activity detection (deprecated, now default behavior)")
 	lsCmd.Flags().Bool("detect-activity", false, "Detect Claude, VS Code, and git status (slower)")
-	lsCmd.Flags().Bool("full", false, "Show full info including GitHub PR/CI/review status (implies --detect-activity)")
+	lsCmd.Flags().Bool("prs", false, "Show GitHub/GitLab PR, CI, and review status (cached, see pr_cache_ttl)")
+	lsCmd.Flags().Bool("full", false, "Show full info including GitHub PR/CI/review status (implies --detect-activity and --prs)")
+	lsCmd.Flags().Bool("wide", false, "Show CPU and memory use of running servers (and their child processes) and active tasks")
+	lsCmd.Flags().String("stale", "", "Only show worktrees with no commits or activity for this long (e.g. 14d, 2w)")
 	lsCmd.Flags().StringSlice("tag", nil, "Filter by tag (can be specified multiple times, uses OR logic)")
-	lsCmd.Flags().String("group", "mainRepo", "Group by: mainRepo (default), activity, status, none")
+	lsCmd.Flags().String("repo", "", "Only show worktrees of this repository (name, e.g. myapp, or origin like github.com/org/myapp)")
+	lsCmd.Flags().String("group", "mainRepo", "Group by: mainRepo (default), activity, status, tag, none")
+	lsCmd.Flags().BoolP("watch", "w", false, "Continuously refresh the list")
+	lsCmd.Flags().DurationP("interval", "n", 2*time.Second, "Refresh interval for --watch")
+	lsCmd.Flags().String("remote", "", "List the worktrees of grove on this SSH host instead (see 'grove tunnel')")
+	lsCmd.Flags().Bool("network", false, "List the grove servers advertised on the local network over mDNS instead")
 }
 
 func runLs(cmd *cobra.Command, args []string) error {
+	watch, _ := cmd.Flags().GetBool("watch")
+	interval, _ := cmd.Flags().GetDuration("interval")
+	outputJSON, _ := cmd.Flags().GetBool("json")
+	remote, _ := cmd.Flags().GetString("remote")
+
+	if remote != "" {
+		if watch {
+			return fmt.Errorf("--watch cannot be used with --remote")
+		}
+		return runRemote(cmd, remote, args)
+	}
+	if network, _ := cmd.Flags().GetBool("network"); network {
+		if watch {
+			return fmt.Errorf("--watch cannot be used with --network")
+		}
+		return runLsNetwork(cmd)
+	}
+
+	// Shared across refreshes so --watch shows CPU since the last refresh
+	sampler := usage.NewSampler(1)
+
+	if watch {
+		if outputJSON {
+			return fmt.Errorf("--watch cannot be used with --json")
+		}
+		return runWatch(interval, func() error { return printWorktreeList(cmd, true, sampler) })
+	}
+	return printWorktreeList(cmd, false, sampler)
+}
+
+// printWorktreeList prints the worktree list. When watching, agent activity is
+// detected with a single batch scan so each refresh stays fast.
+func printWorktreeList(cmd *cobra.Command, watching bool, sampler *usage.Sampler) error {
 	outputJSON, _ := cmd.Flags().GetBool("json")
 	onlyRunning, _ := cmd.Flags().GetBool("running")
 	onlyServers, _ := cmd.Flags().GetBool("servers")
@@ -59,13 +118,31 @@ func runLs(cmd *cobra.Command, args []string) error {
 	showAll, _ := cmd.Flags().GetBool("all")
 	detectActivity, _ := cmd.Flags().GetBool("detect-activity")
 	fullMode, _ := cmd.Flags().GetBool("full")
+	showPRs, _ := cmd.Flags().GetBool("prs")
+	ctx := cmd.Context()
 	tagFilters, _ := cmd.Flags().GetStringSlice("tag")
+	repoFilter, _ := cmd.Flags().GetString("repo")
 	groupBy, _ := cmd.Flags().GetString("group")
+	wide, _ := cmd.Flags().GetBool("wide")
+	staleStr, _ := cmd.Flags().GetString("stale")
+	for i, tag := range tagFilters {
+		tagFilters[i] = normalizeTag(tag)
+	}
 	_ = showAll // Reserved for future use
 
 	// --full implies --detect-activity (need activity data for full output)
 	if fullMode {
 		detectActivity = true
+		showPRs = true
+	}
+
+	var staleAfter time.Duration
+	if staleStr != "" {
+		d, err := parseAge(staleStr)
+		if err != nil {
+			return err
+		}
+		staleAfter = d
 	}
 
 	// Fast mode is now the default - activity detection only when explicitly requested
@@ -89,13 +166,13 @@ func runLs(cmd *cobra.Command, args []string) error {
 
 	// Auto-discover worktrees from current repo (fast operation)
 	if !fastMode {
-		autoDiscoverCurrentRepo(reg)
+		autoDiscoverCurrentRepo(ctx, reg)
 	}
 
 	// Update worktree activities (non-critical, continue on error)
 	// Skip in fast mode - this is the slow part (ps, lsof, git status for each worktree)
 	if !fastMode {
-		if err := reg.UpdateWorktreeActivities(); err != nil {
+		if err := reg.UpdateWorktreeActivities(ctx); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to update worktree activities: %v\n", err)
 		}
 	}
@@ -106,15 +183,17 @@ func runLs(cmd *cobra.Command, args []string) error {
 	// Add all registered servers
 	for _, server := range reg.List() {
 		// Try to get main_repo from worktree registry
-		var mainRepo string
+		var mainRepo, repo string
 		if wt, exists := reg.GetWorktree(server.Name); exists {
 			mainRepo = wt.MainRepo
+			repo = wt.Repo
 		}
 		views[server.Name] = &WorktreeView{
 			Name:      server.Name,
 			Path:      server.Path,
 			Branch:    server.Branch,
 			MainRepo:  mainRepo,
+			Repo:      repo,
 			Server:    server,
 			HasServer: true,
 			Tags:      server.Tags,
@@ -128,18 +207,32 @@ func runLs(cmd *cobra.Command, args []string) error {
 			view.HasClaude = wt.HasClaude
 			view.HasVSCode = wt.HasVSCode
 			view.GitDirty = wt.GitDirty
+			view.DirtyFiles = wt.DirtyFiles
 			view.MainRepo = wt.MainRepo
+			view.Repo = wt.Repo
 		} else {
 			// New worktree without server
 			views[wt.Name] = &WorktreeView{
-				Name:      wt.Name,
-				Path:      wt.Path,
-				Branch:    wt.Branch,
-				MainRepo:  wt.MainRepo,
-				HasServer: false,
-				HasClaude: wt.HasClaude,
-				HasVSCode: wt.HasVSCode,
-				GitDirty:  wt.GitDirty,
+				Name:       wt.Name,
+				Path:       wt.Path,
+				Branch:     wt.Branch,
+				MainRepo:   wt.MainRepo,
+				Repo:       wt.Repo,
+				HasServer:  false,
+				HasClaude:  wt.HasClaude,
+				HasVSCode:  wt.HasVSCode,
+				GitDirty:   wt.GitDirty,
+				DirtyFiles: wt.DirtyFiles,
+			}
+		}
+	}
+
+	// Cheap agent detection so the watch view shows agent activity
+	if watching && fastMode {
+		agents := discovery.DetectAllAgents(ctx)
+		for _, view := range views {
+			if _, ok := agents[view.Path]; ok {
+				view.HasClaude = true
 			}
 		}
 	}
@@ -156,6 +249,9 @@ func runLs(cmd *cobra.Command, args []string) error {
 		if onlyActive && !view.HasServer && !view.HasClaude && !view.HasVSCode && !view.GitDirty {
 			continue
 		}
+		if repoFilter != "" && !view.InRepo(repoFilter) {
+			continue
+		}
 		// Tag filtering (OR logic - match any of the specified tags)
 		if len(tagFilters) > 0 {
 			hasMatchingTag := false
@@ -174,11 +270,29 @@ func runLs(cmd *cobra.Command, args []string) error {
 				continue
 			}
 		}
+		// Staleness: idle since the last commit or recorded activity
+		if staleAfter > 0 {
+			if view.Server != nil && view.Server.IsRunning() {
+				continue
+			}
+			ws, ok := reg.GetWorkspace(view.Name)
+			if !ok {
+				continue
+			}
+			view.LastActivity = lastActivity(ws)
+			if view.LastActivity.IsZero() || time.Since(view.LastActivity) < staleAfter {
+				continue
+			}
+		}
 		filtered = append(filtered, view)
 	}
 
-	// Sort: running servers first, then by name (stable sort order)
+	// Sort: running servers first, then by name (stable sort order), or
+	// longest idle first for --stale
 	sort.Slice(filtered, func(i, j int) bool {
+		if staleAfter > 0 {
+			return filtered[i].LastActivity.Before(filtered[j].LastActivity)
+		}
 		// Running servers come first
 		iRunning := filtered[i].Server != nil && filtered[i].Server.IsRunning()
 		jRunning := filtered[j].Server != nil && filtered[j].Server.IsRunning()
@@ -189,23 +303,77 @@ func runLs(cmd *cobra.Command, args []string) error {
 		return filtered[i].Name < filtered[j].Name
 	})
 
-	// Fetch GitHub info for all worktrees if --full is set
+	if wide {
+		sampleUsage(filtered, sampler)
+	}
+	// Only the TASK column and JSON show tasks, which are read from disk
+	showTasks := wide || fullMode || outputJSON
+	for _, view := range filtered {
+		if showTasks {
+			view.Task = tasks.Active(view.Path)
+		}
+		if ws, ok := reg.GetWorkspace(view.Name); ok {
+			view.Share = ws.Share
+			view.Checks = ws.Checks
+		}
+	}
+
+	// Fetch PR info (keyed by worktree path) if --prs or --full is set
 	var githubInfoMap map[string]*github.BranchInfo
-	if fullMode {
-		branches := make([]string, 0, len(filtered))
+	if showPRs {
+		lookups := make([]github.Lookup, 0, len(filtered))
 		for _, view := range filtered {
-			if view.Branch != "" {
-				branches = append(branches, view.Branch)
-			}
+			lookups = append(lookups, github.Lookup{Dir: view.Path, Branch: view.Branch})
 		}
-		githubInfoMap = github.GetBranchInfoBatch(branches)
+		githubInfoMap = fetchPRStatus(lookups)
 	}
 
 	if outputJSON {
-		return outputJSONFormatNew(filtered, reg.GetProxy(), fullMode, githubInfoMap, groupBy)
+		return outputJSONFormatNew(filtered, reg.GetProxy(), showPRs, githubInfoMap, groupBy)
 	}
 
-	return outputTableFormatNew(filtered, reg.GetProxy(), fullMode, githubInfoMap, groupBy)
+	if err := outputTableFormatNew(filtered, reg.GetProxy(), fullMode, showPRs, wide, githubInfoMap, groupBy); err != nil {
+		return err
+	}
+	printShares(filtered)
+	if staleAfter > 0 && len(filtered) > 0 {
+		fmt.Println("\nArchive idle worktrees with 'grove archive <name>' (branch and changes are kept for 'grove restore')")
+	}
+	return nil
+}
+
+// printShares lists the views shared with 'grove share' below the table
+func printShares(views []*WorktreeView) {
+	first := true
+	for _, view := range views {
+		if view.Share == nil {
+			continue
+		}
+		if first {
+			fmt.Println()
+			first = false
+		}
+		fmt.Printf("Shared: %s → %s\n", view.Name, describeShare(view.Share))
+	}
+}
+
+// sampleUsage records the CPU and memory of each running server's process
+// tree on its view. Failing to sample (e.g. no ps) leaves the columns empty.
+func sampleUsage(views []*WorktreeView, sampler *usage.Sampler) {
+	pids := make(map[string]int)
+	for _, view := range views {
+		if view.Server != nil && view.Server.IsRunning() && view.Server.PID > 0 {
+			pids[view.Name] = view.Server.PID
+		}
+	}
+	if len(pids) == 0 || sampler.Sample(pids) != nil {
+		return
+	}
+	for _, view := range views {
+		if u, ok := sampler.Latest(view.Name); ok {
+			view.Usage = &u
+		}
+	}
 }
 
 type jsonProxy struct {
@@ -215,6 +383,13 @@ type jsonProxy struct {
 	PID       int    `json:"pid,omitempty"`
 }
 
+// jsonShare is a share in 'grove ls --json'
+type jsonShare struct {
+	URL      string `json:"url"`
+	Provider string `json:"provider"`
+	Tailnet  bool   `json:"tailnet"`
+}
+
 func formatStatus(status registry.ServerStatus) string {
 	switch status {
 	case registry.StatusRunning:
@@ -227,6 +402,8 @@ func formatStatus(status registry.ServerStatus) string {
 		return "◑ stopping"
 	case registry.StatusCrashed:
 		return "✗ crashed"
+	case registry.StatusPaused:
+		return "⏸ paused"
 	default:
 		return string(status)
 	}
@@ -238,12 +415,38 @@ type WorktreeView struct {
 	Path      string
 	Branch    string
 	MainRepo  string
+	Repo      string
 	Server    *registry.Server
 	HasServer bool
 	HasClaude bool
 	HasVSCode bool
 	GitDirty  bool
-	Tags      []string
+	// DirtyFiles is how many files have uncommitted changes
+	DirtyFiles int
+	Tags       []string
+	Usage      *usage.Usage
+	Task       *tasks.Task
+	Share      *registry.Share
+	// Checks are the results of the last 'grove ci'
+	Checks *checks.Record
+
+	// LastActivity is set for --stale
+	LastActivity time.Time
+}
+
+// InRepo reports whether the worktree belongs to a repository, given by
+// name (e.g. "myapp") or ID (e.g. "github.com/org/myapp"). Worktrees
+// registered before repositories were recorded match by their main repo's
+// directory.
+func (v *WorktreeView) InRepo(repo string) bool {
+	if v.Repo != "" {
+		return v.Repo == repo || worktree.RepoName(v.Repo) == repo
+	}
+	mainRepo := v.MainRepo
+	if mainRepo == "" {
+		mainRepo = v.Path
+	}
+	return mainRepo == repo || filepath.Base(mainRepo) == repo
 }
 
 // DisplayName returns a name that includes branch info when not obvious from the name.
@@ -276,7 +479,7 @@ func (v *WorktreeView) DisplayName() string {
 	return fmt.Sprintf("%s (%s)", v.Name, v.Branch)
 }
 
-func outputJSONFormatNew(views []*WorktreeView, proxy *registry.ProxyInfo, fullMode bool, githubInfoMap map[string]*github.BranchInfo, groupBy string) error {
+func outputJSONFormatNew(views []*WorktreeView, proxy *registry.ProxyInfo, showPRs bool, githubInfoMap map[string]*github.BranchInfo, groupBy string) error {
 	type jsonGitHubInfo struct {
 		PRNumber     int    `json:"pr_number,omitempty"`
 		PRStatus     string `json:"pr_status,omitempty"`
@@ -286,23 +489,30 @@ func outputJSONFormatNew(views []*WorktreeView, proxy *registry.ProxyInfo, fullM
 	}
 
 	type jsonWorktreeView struct {
-		Name      string          `json:"name"`
-		Path      string          `json:"path"`
-		Branch    string          `json:"branch,omitempty"`
-		MainRepo  string          `json:"main_repo,omitempty"`
-		URL       string          `json:"url,omitempty"`
-		Port      int             `json:"port,omitempty"`
-		Status    string          `json:"status,omitempty"`
-		HasServer bool            `json:"has_server"`
-		HasClaude bool            `json:"has_claude"`
-		HasVSCode bool            `json:"has_vscode"`
-		GitDirty  bool            `json:"git_dirty"`
-		PID       int             `json:"pid,omitempty"`
-		Uptime    string          `json:"uptime,omitempty"`
-		LogFile   string          `json:"log_file,omitempty"`
-		Tags      []string        `json:"tags,omitempty"`
-		Group     string          `json:"group,omitempty"`
-		GitHub    *jsonGitHubInfo `json:"github,omitempty"`
+		Name         string          `json:"name"`
+		Path         string          `json:"path"`
+		Branch       string          `json:"branch,omitempty"`
+		MainRepo     string          `json:"main_repo,omitempty"`
+		Repo         string          `json:"repo,omitempty"`
+		URL          string          `json:"url,omitempty"`
+		Port         int             `json:"port,omitempty"`
+		Status       string          `json:"status,omitempty"`
+		HasServer    bool            `json:"has_server"`
+		HasClaude    bool            `json:"has_claude"`
+		HasVSCode    bool            `json:"has_vscode"`
+		GitDirty     bool            `json:"git_dirty"`
+		DirtyFiles   int             `json:"dirty_files,omitempty"`
+		PID          int             `json:"pid,omitempty"`
+		Uptime       string          `json:"uptime,omitempty"`
+		LogFile      string          `json:"log_file,omitempty"`
+		Tags         []string        `json:"tags,omitempty"`
+		Group        string          `json:"group,omitempty"`
+		Usage        *usage.Usage    `json:"usage,omitempty"`
+		Task         *tasks.Task     `json:"task,omitempty"`
+		Share        *jsonShare      `json:"share,omitempty"`
+		Checks       *checks.Record  `json:"checks,omitempty"`
+		LastActivity *time.Time      `json:"last_activity,omitempty"`
+		GitHub       *jsonGitHubInfo `json:"github,omitempty"`
 	}
 
 	type output struct {
@@ -310,16 +520,18 @@ func outputJSONFormatNew(views []*WorktreeView, proxy *registry.ProxyInfo, fullM
 		Proxy     *jsonProxy          `json:"proxy,omitempty"`
 		URLMode   string              `json:"url_mode"`
 		GroupBy   string              `json:"group_by,omitempty"`
+		Context   string              `json:"context,omitempty"`
 	}
 
 	out := output{
 		Worktrees: make([]*jsonWorktreeView, 0, len(views)),
 		URLMode:   string(cfg.URLMode),
 		GroupBy:   groupBy,
+		Context:   config.NamedContext(),
 	}
 
-	// Only include proxy info if in subdomain mode
-	if cfg.IsSubdomainMode() {
+	// Only include proxy info if servers are proxied
+	if cfg.UsesProxy() {
 		out.Proxy = &jsonProxy{
 			HTTPPort:  proxy.HTTPPort,
 			HTTPSPort: proxy.HTTPSPort,
@@ -334,16 +546,31 @@ func outputJSONFormatNew(views []*WorktreeView, proxy *registry.ProxyInfo, fullM
 
 	for _, view := range views {
 		jv := &jsonWorktreeView{
-			Name:      view.Name,
-			Path:      view.Path,
-			Branch:    view.Branch,
-			MainRepo:  view.MainRepo,
-			HasServer: view.HasServer,
-			HasClaude: view.HasClaude,
-			HasVSCode: view.HasVSCode,
-			GitDirty:  view.GitDirty,
-			Tags:      view.Tags,
-			Group:     getGroupForView(view, groupBy),
+			Name:       view.Name,
+			Path:       view.Path,
+			Branch:     view.Branch,
+			MainRepo:   view.MainRepo,
+			Repo:       view.Repo,
+			HasServer:  view.HasServer,
+			HasClaude:  view.HasClaude,
+			HasVSCode:  view.HasVSCode,
+			GitDirty:   view.GitDirty,
+			DirtyFiles: view.DirtyFiles,
+			Tags:       view.Tags,
+			Group:      getGroupForView(view, groupBy),
+			Usage:      view.Usage,
+			Task:       view.Task,
+			Checks:     view.Checks,
+		}
+		if view.Share != nil {
+			jv.Share = &jsonShare{
+				URL:      view.Share.URL,
+				Provider: view.Share.Provider,
+				Tailnet:  view.Share.Provider == tunnel.TailscaleServe.Name,
+			}
+		}
+		if !view.LastActivity.IsZero() {
+			jv.LastActivity = &view.LastActivity
 		}
 
 		if view.Server != nil {
@@ -355,9 +582,9 @@ func outputJSONFormatNew(views []*WorktreeView, proxy *registry.ProxyInfo, fullM
 			jv.LogFile = view.Server.LogFile
 		}
 
-		// Add GitHub info if --full is set
-		if fullMode && view.Branch != "" {
-			if info, ok := githubInfoMap[view.Branch]; ok && info != nil {
+		// Add GitHub info if --prs or --full is set
+		if showPRs {
+			if info, ok := githubInfoMap[view.Path]; ok && info != nil {
 				ghInfo := &jsonGitHubInfo{}
 				if info.PR != nil {
 					ghInfo.PRNumber = info.PR.Number
@@ -383,7 +610,10 @@ func outputJSONFormatNew(views []*WorktreeView, proxy *registry.ProxyInfo, fullM
 	return enc.Encode(out)
 }
 
-func outputTableFormatNew(views []*WorktreeView, proxy *registry.ProxyInfo, fullMode bool, githubInfoMap map[string]*github.BranchInfo, groupBy string) error {
+func outputTableFormatNew(views []*WorktreeView, proxy *registry.ProxyInfo, fullMode, showPRs, wide bool, githubInfoMap map[string]*github.BranchInfo, groupBy string) error {
+	if name := config.NamedContext(); name != "" {
+		fmt.Printf("Context: %s\n\n", name)
+	}
 	if len(views) == 0 {
 		fmt.Println("No worktrees discovered")
 		fmt.Println("\nUse 'grove discover' to scan for git worktrees, or 'grove start <command>' to start a server")
@@ -403,26 +633,29 @@ func outputTableFormatNew(views []*WorktreeView, proxy *registry.ProxyInfo, full
 
 			// Print group header
 			fmt.Printf("\n=== %s ===\n", strings.ToUpper(groupName))
-			printViewsTable(groupViews, fullMode, githubInfoMap)
+			printViewsTable(groupViews, fullMode, showPRs, wide, githubInfoMap)
 		}
 	} else {
 		// No grouping, print flat list
-		printViewsTable(views, fullMode, githubInfoMap)
+		printViewsTable(views, fullMode, showPRs, wide, githubInfoMap)
 	}
 
 	// Legend
 	fmt.Println()
 	if fullMode {
-		fmt.Println("Legend: running  stopped  Claude  clean  dirty")
+		fmt.Println("Legend: running  paused  stopped  Claude  clean  dirty")
 		fmt.Println("PR: open/draft/merged/closed  CI: success  failure  pending")
 		fmt.Println("Review: approved/changes/pending")
 	} else {
-		fmt.Println("Legend: running  stopped  Claude  VS Code  clean  dirty")
+		fmt.Println("Legend: running  paused  stopped  Claude  VS Code  clean  dirty")
+		if showPRs {
+			fmt.Println("PR: open/draft/merged/closed  CI: success  failure  pending")
+		}
 	}
 
-	// Proxy status (only relevant in subdomain mode)
+	// Proxy status (only relevant in subdomain and path mode)
 	fmt.Println()
-	if cfg.IsSubdomainMode() {
+	if cfg.UsesProxy() {
 		if proxy.IsRunning() {
 			fmt.Printf("Proxy: running on :%d/:%d (PID: %d)\n",
 				proxy.HTTPPort, proxy.HTTPSPort, proxy.PID)
@@ -436,15 +669,32 @@ func outputTableFormatNew(views []*WorktreeView, proxy *registry.ProxyInfo, full
 	return nil
 }
 
-// printViewsTable prints a table of views
-func printViewsTable(views []*WorktreeView, fullMode bool, githubInfoMap map[string]*github.BranchInfo) {
+// printViewsTable prints a table of views, with CPU and MEM columns when
+// wide, an IDLE column for --stale, a CHECKS column once 'grove ci' has run
+// in any of them and a TASK column when wide or full
+func printViewsTable(views []*WorktreeView, fullMode, showPRs, wide bool, githubInfoMap map[string]*github.BranchInfo) {
+	showChecks := false
+	for _, view := range views {
+		if view.Checks != nil {
+			showChecks = true
+			break
+		}
+	}
+
 	var rows [][]string
 	for _, view := range views {
+		name := view.DisplayName()
+		if len(view.Tags) > 0 {
+			name += " " + registry.FormatTags(view.Tags)
+		}
+
 		// Server status with emoji
 		status := "○"
 		port := "-"
 		if view.Server != nil {
-			if view.Server.IsRunning() {
+			if view.Server.IsPaused() {
+				status = "⏸"
+			} else if view.Server.IsRunning() {
 				status = "●"
 			}
 			port = fmt.Sprintf("%d", view.Server.Port)
@@ -464,7 +714,9 @@ func printViewsTable(views []*WorktreeView, fullMode bool, githubInfoMap map[str
 
 		// Git status
 		gitStatus := "✓"
-		if view.GitDirty {
+		if view.DirtyFiles > 0 {
+			gitStatus = fmt.Sprintf("📝 %d", view.DirtyFiles)
+		} else if view.GitDirty {
 			gitStatus = "📝"
 		}
 
@@ -476,26 +728,23 @@ func printViewsTable(views []*WorktreeView, fullMode bool, githubInfoMap map[str
 			}
 		}
 
-		if fullMode {
-			// Full mode: include GitHub info columns
-			prStatus := "-"
-			ciStatus := "-"
-			reviewStatus := "-"
-
-			if view.Branch != "" {
-				if info, ok := githubInfoMap[view.Branch]; ok && info != nil {
-					if info.PR != nil {
-						prStatus = github.FormatPRStatus(info.PR)
-						reviewStatus = github.FormatReviewStatus(info.PR)
-					}
-					if info.CI != nil {
-						ciStatus = github.FormatCIStatus(info.CI)
-					}
-				}
+		// GitHub info columns (--prs or --full)
+		prStatus := "-"
+		ciStatus := "-"
+		reviewStatus := "-"
+		if info, ok := githubInfoMap[view.Path]; ok && info != nil {
+			if info.PR != nil {
+				prStatus = github.FormatPRInfo(info.PR) + " " + github.FormatPRStatus(info.PR)
+				reviewStatus = github.FormatReviewStatus(info.PR)
 			}
+			if info.CI != nil {
+				ciStatus = github.FormatCIStatus(info.CI)
+			}
+		}
 
+		if fullMode {
 			rows = append(rows, []string{
-				view.DisplayName(),
+				name,
 				status,
 				port,
 				prStatus,
@@ -504,9 +753,20 @@ func printViewsTable(views []*WorktreeView, fullMode bool, githubInfoMap map[str
 				claudeStatus,
 				gitStatus,
 			})
+		} else if showPRs {
+			rows = append(rows, []string{
+				name,
+				status,
+				port,
+				prStatus,
+				ciStatus,
+				reviewStatus,
+				gitStatus,
+				displayPath,
+			})
 		} else {
 			rows = append(rows, []string{
-				view.DisplayName(),
+				name,
 				status,
 				port,
 				claudeStatus,
@@ -515,12 +775,58 @@ func printViewsTable(views []*WorktreeView, fullMode bool, githubInfoMap map[str
 				displayPath,
 			})
 		}
+
+		// Optional columns go after PORT so they line up in every mode
+		var extra []string
+		if wide {
+			cpu, mem := "-", "-"
+			if view.Usage != nil {
+				cpu = fmt.Sprintf("%.1f%%", view.Usage.CPU)
+				mem = usage.FormatBytes(view.Usage.RSS)
+			}
+			extra = append(extra, cpu, mem)
+		}
+		if !view.LastActivity.IsZero() {
+			extra = append(extra, formatAge(time.Since(view.LastActivity)))
+		}
+		if showChecks {
+			extra = append(extra, formatChecks(view.Checks))
+		}
+		if len(extra) > 0 {
+			row := rows[len(rows)-1]
+			rows[len(rows)-1] = append(row[:3:3], append(extra, row[3:]...)...)
+		}
+		if wide || fullMode {
+			task := "-"
+			if view.Task != nil {
+				task = ansi.Truncate(view.Task.Summary(), styles.TruncateShort, styles.TruncateTail)
+			}
+			rows[len(rows)-1] = append(rows[len(rows)-1], task)
+		}
 	}
 
 	// Style definitions
 	headerStyle := styles.HeaderStyle
 	cellStyle := styles.CellStyle
 
+	var extraHeaders []string
+	if wide {
+		extraHeaders = append(extraHeaders, "CPU", "MEM")
+	}
+	if len(views) > 0 && !views[0].LastActivity.IsZero() {
+		extraHeaders = append(extraHeaders, "IDLE")
+	}
+	if showChecks {
+		extraHeaders = append(extraHeaders, "CHECKS")
+	}
+	withExtra := func(headers ...string) []string {
+		headers = append(headers[:3:3], append(extraHeaders, headers[3:]...)...)
+		if wide || fullMode {
+			headers = append(headers, "TASK")
+		}
+		return headers
+	}
+
 	var t *table.Table
 	if fullMode {
 		// Full mode table with GitHub columns
@@ -532,7 +838,7 @@ func printViewsTable(views []*WorktreeView, fullMode bool, githubInfoMap map[str
 			BorderBottom(false).
 			BorderLeft(false).
 			BorderRight(false).
-			Headers("NAME", "SERVER", "PORT", "PR", "CI", "REVIEW", "CLAUDE", "GIT").
+			Headers(withExtra("NAME", "SERVER", "PORT", "PR", "CI", "REVIEW", "CLAUDE", "GIT")...).
 			Rows(rows...).
 			StyleFunc(func(row, col int) lipgloss.Style {
 				if row == table.HeaderRow {
@@ -541,7 +847,11 @@ func printViewsTable(views []*WorktreeView, fullMode bool, githubInfoMap map[str
 				return cellStyle
 			})
 	} else {
-		// Default table
+		// Default table, with PR columns replacing activity when --prs is set
+		headers := []string{"NAME", "STATUS", "PORT", "CLAUDE", "VSCODE", "GIT", "PATH"}
+		if showPRs {
+			headers = []string{"NAME", "STATUS", "PORT", "PR", "CI", "REVIEW", "GIT", "PATH"}
+		}
 		t = table.New().
 			Border(lipgloss.NormalBorder()).
 			BorderRow(false).
@@ -550,7 +860,7 @@ func printViewsTable(views []*WorktreeView, fullMode bool, githubInfoMap map[str
 			BorderBottom(false).
 			BorderLeft(false).
 			BorderRight(false).
-			Headers("NAME", "STATUS", "PORT", "CLAUDE", "VSCODE", "GIT", "PATH").
+			Headers(withExtra(headers...)...).
 			Rows(rows...).
 			StyleFunc(func(row, col int) lipgloss.Style {
 				if row == table.HeaderRow {
@@ -565,7 +875,7 @@ func printViewsTable(views []*WorktreeView, fullMode bool, githubInfoMap map[str
 
 // autoDiscoverCurrentRepo discovers worktrees from the current git repo and registers them.
 // This is a fast operation that only runs `git worktree list` for the current repo.
-func autoDiscoverCurrentRepo(reg *registry.Registry) {
+func autoDiscoverCurrentRepo(ctx context.Context, reg *registry.Registry) {
 	// Try to detect current worktree
 	wt, err := worktree.Detect()
 	if err != nil {
@@ -574,10 +884,12 @@ func autoDiscoverCurrentRepo(reg *registry.Registry) {
 	}
 
 	// Discover all worktrees for this repo
-	worktrees, err := discovery.Discover(wt.Path)
+	scanStart := time.Now()
+	worktrees, err := discovery.Discover(ctx, wt.Path)
 	if err != nil {
 		return
 	}
+	metrics.RecordDiscoveryScan(time.Since(scanStart))
 
 	// Register any new worktrees
 	for _, discovered := range worktrees {
@@ -618,7 +930,7 @@ func getGroupForView(view *WorktreeView, groupBy string) string {
 			return "no-server"
 		}
 		switch view.Server.Status {
-		case registry.StatusRunning, registry.StatusStarting:
+		case registry.StatusRunning, registry.StatusStarting, registry.StatusPaused:
 			return "running"
 		case registry.StatusStopped, registry.StatusStopping:
 			return "stopped"
@@ -627,6 +939,12 @@ func getGroupForView(view *WorktreeView, groupBy string) string {
 		default:
 			return "stopped"
 		}
+	case "tag":
+		// Views with several tags are listed under each (see groupViewsMap)
+		if len(view.Tags) > 0 {
+			return view.Tags[0]
+		}
+		return "untagged"
 	case "none":
 		return ""
 	default:
@@ -638,6 +956,12 @@ func getGroupForView(view *WorktreeView, groupBy string) string {
 func groupViewsMap(views []*WorktreeView, groupBy string) map[string][]*WorktreeView {
 	groups := make(map[string][]*WorktreeView)
 	for _, view := range views {
+		if groupBy == "tag" && len(view.Tags) > 1 {
+			for _, tag := range view.Tags {
+				groups[tag] = append(groups[tag], view)
+			}
+			continue
+		}
 		group := getGroupForView(view, groupBy)
 		groups[group] = append(groups[group], view)
 	}
@@ -665,6 +989,19 @@ func getGroupOrder(groupBy string, groups map[string][]*WorktreeView) []string {
 			}
 		}
 		return order
+	case "tag":
+		// Tags alphabetically, untagged last
+		order := make([]string, 0, len(groups))
+		for g := range groups {
+			if g != "untagged" {
+				order = append(order, g)
+			}
+		}
+		sort.Strings(order)
+		if _, ok := groups["untagged"]; ok {
+			order = append(order, "untagged")
+		}
+		return order
 	case "mainRepo":
 		// Sort alphabetically
 		order := make([]string, 0, len(groups))
@@ -2,20 +2,28 @@ package cli
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/charmbracelet/x/ansi"
-	"github.com/iheanyi/grove/internal/discovery"
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/crash"
+	"github.com/iheanyi/grove/internal/git"
+	"github.com/iheanyi/grove/internal/metrics"
 	"github.com/iheanyi/grove/internal/port"
+	"github.com/iheanyi/grove/internal/project"
 	"github.com/iheanyi/grove/internal/registry"
 	"github.com/iheanyi/grove/internal/styles"
+	"github.com/iheanyi/grove/internal/tasks"
 	"github.com/iheanyi/grove/internal/worktree"
 	"github.com/spf13/cobra"
 )
@@ -38,11 +46,18 @@ Claude Code to use this command as an MCP server:
   }
 
 Available tools:
+  - grove_overview: Servers, health, worktrees, agents and proxy in one call
   - grove_list: List all registered dev servers
   - grove_start: Start a dev server for a git worktree
   - grove_stop: Stop a running dev server
   - grove_url: Get the URL for a worktree's dev server
-  - grove_status: Get detailed status of a dev server`,
+  - grove_status: Get detailed status of a dev server
+
+Prompts (guided workflows, filled in with the current grove state):
+  - start_and_verify: Start this project's dev server and check it responds
+  - new_worktree: Create a worktree for a branch and set it up
+  - review_queue: Decide which worktrees are ready for review
+  - diagnose_crash: Find and fix why a server crashed`,
 	Run: func(cmd *cobra.Command, args []string) {
 		runMCPServer()
 	},
@@ -95,20 +110,9 @@ func init() {
 }
 
 func runMCPInstall(cmd *cobra.Command, args []string) error {
-	// Find grove binary path
-	grovePath, err := exec.LookPath("grove")
-	if err != nil {
-		// Fall back to current executable
-		grovePath, err = os.Executable()
-		if err != nil {
-			return fmt.Errorf("failed to find grove binary: %w", err)
-		}
-	}
-
-	// Resolve symlinks to get actual path
-	grovePath, err = filepath.EvalSymlinks(grovePath)
+	grovePath, err := groveBinaryPath()
 	if err != nil {
-		return fmt.Errorf("failed to resolve grove path: %w", err)
+		return err
 	}
 
 	switch mcpInstallProvider {
@@ -129,6 +133,26 @@ func runMCPInstall(cmd *cobra.Command, args []string) error {
 	}
 }
 
+// groveBinaryPath returns the path of the grove binary that providers should
+// run as an MCP server
+func groveBinaryPath() (string, error) {
+	grovePath, err := exec.LookPath("grove")
+	if err != nil {
+		// Fall back to current executable
+		grovePath, err = os.Executable()
+		if err != nil {
+			return "", fmt.Errorf("failed to find grove binary: %w", err)
+		}
+	}
+
+	// Resolve symlinks to get actual path
+	grovePath, err = filepath.EvalSymlinks(grovePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve grove path: %w", err)
+	}
+	return grovePath, nil
+}
+
 func installForClaudeCode(grovePath string) error {
 	// Use claude mcp add command to properly register the MCP server
 	claudeCmd := exec.Command("claude", "mcp", "add", "-s", "user", "-t", "stdio", "grove", grovePath, "mcp")
@@ -549,7 +573,8 @@ type serverInfo struct {
 }
 
 type capabilities struct {
-	Tools *toolsCapability `json:"tools,omitempty"`
+	Tools   *toolsCapability   `json:"tools,omitempty"`
+	Prompts *promptsCapability `json:"prompts,omitempty"`
 }
 
 type toolsCapability struct{}
@@ -578,6 +603,7 @@ type toolsListResult struct {
 type callToolParams struct {
 	Name      string                 `json:"name"`
 	Arguments map[string]interface{} `json:"arguments"`
+	Meta      *requestMeta           `json:"_meta,omitempty"`
 }
 
 type callToolResult struct {
@@ -591,7 +617,12 @@ type toolContent struct {
 }
 
 // MCP Server
-type mcpServer struct{}
+type mcpServer struct {
+	// out is where responses and notifications are written, stdout
+	// when nil
+	out io.Writer
+	mu  sync.Mutex
+}
 
 func runMCPServer() {
 	server := &mcpServer{}
@@ -628,6 +659,10 @@ func (s *mcpServer) handleRequest(req *jsonRPCRequest) {
 		s.handleToolsList(req)
 	case "tools/call":
 		s.handleToolsCall(req)
+	case "prompts/list":
+		s.handlePromptsList(req)
+	case "prompts/get":
+		s.handlePromptsGet(req)
 	default:
 		// Don't send errors for notifications (no ID means it's a notification)
 		if req.ID != nil && !strings.HasPrefix(req.Method, "notifications/") {
@@ -644,7 +679,8 @@ func (s *mcpServer) handleInitialize(req *jsonRPCRequest) {
 			Version: Version,
 		},
 		Capabilities: capabilities{
-			Tools: &toolsCapability{},
+			Tools:   &toolsCapability{},
+			Prompts: &promptsCapability{},
 		},
 	}
 	s.sendResult(req.ID, result)
@@ -652,6 +688,14 @@ func (s *mcpServer) handleInitialize(req *jsonRPCRequest) {
 
 func (s *mcpServer) handleToolsList(req *jsonRPCRequest) {
 	tools := []tool{
+		{
+			Name:        "grove_overview",
+			Description: "Get a compact overview of everything in one call: each worktree's branch, server status, health and URL, uncommitted changes and active AI agent, plus proxy status. Use this first instead of calling grove_list, grove_status and grove_url one by one.",
+			InputSchema: inputSchema{
+				Type:       "object",
+				Properties: map[string]property{},
+			},
+		},
 		{
 			Name:        "grove_list",
 			Description: "List all registered dev servers and their URLs. Shows server names, URLs, ports, and running status. Use to see what development servers are running or available.",
@@ -662,7 +706,7 @@ func (s *mcpServer) handleToolsList(req *jsonRPCRequest) {
 		},
 		{
 			Name:        "grove_start",
-			Description: "Start a dev server for a git worktree. Run development server commands like bin/dev, rails s, npm run dev. Server accessible via URL based on port or subdomain mode.",
+			Description: "Start a dev server for a git worktree. Run development server commands like bin/dev, rails s, npm run dev. Server accessible via URL based on port or subdomain mode. Waits until the server is ready (its ready_log_pattern matches, or its port listens and health check passes), streaming startup log lines and health probes as progress, and returns the last startup log lines, including why it failed if it exits.",
 			InputSchema: inputSchema{
 				Type: "object",
 				Properties: map[string]property{
@@ -674,6 +718,10 @@ func (s *mcpServer) handleToolsList(req *jsonRPCRequest) {
 						Type:        "string",
 						Description: "Path to the project directory or git worktree (defaults to current directory)",
 					},
+					"wait": {
+						Type:        "boolean",
+						Description: "Wait until the server is ready, streaming its startup log as progress (optional, defaults to true; false returns as soon as the process is running)",
+					},
 				},
 				Required: []string{"command"},
 			},
@@ -707,7 +755,7 @@ func (s *mcpServer) handleToolsList(req *jsonRPCRequest) {
 		},
 		{
 			Name:        "grove_status",
-			Description: "Check detailed status of a dev server including running state, health, uptime, port, process ID, and logs file path.",
+			Description: "Check detailed status of a dev server including running state, health, uptime, port, process ID, logs file path, and the reason and log of its last crash.",
 			InputSchema: inputSchema{
 				Type: "object",
 				Properties: map[string]property{
@@ -754,6 +802,32 @@ func (s *mcpServer) handleToolsList(req *jsonRPCRequest) {
 				Required: []string{"branch"},
 			},
 		},
+		{
+			Name:        "grove_smoke",
+			Description: "Run the HTTP smoke checks defined in a project's .grove.yaml (paths with expected status codes and body substrings) against its running dev server. Use it to validate changes before asking for review. Reports each check as passed or failed.",
+			InputSchema: inputSchema{
+				Type: "object",
+				Properties: map[string]property{
+					"name": {
+						Type:        "string",
+						Description: "Name of the dev server to check (optional, defaults to current worktree)",
+					},
+				},
+			},
+		},
+		{
+			Name:        "grove_review",
+			Description: "Get the review queue: worktrees with uncommitted changes or unpushed commits, as a JSON array (empty when there's nothing to review). Each item has its branch, diff stats (files changed, lines added and removed), task summary, server URL if running, whether it was already reviewed at its current commit, and its PR and CI status. Use it to decide which worktrees are ready for human review or a pull request.",
+			InputSchema: inputSchema{
+				Type: "object",
+				Properties: map[string]property{
+					"pr_status": {
+						Type:        "boolean",
+						Description: "Look up each item's PR and CI status with gh or glab (optional, defaults to true; false is faster)",
+					},
+				},
+			},
+		},
 	}
 
 	s.sendResult(req.ID, toolsListResult{Tools: tools})
@@ -769,10 +843,12 @@ func (s *mcpServer) handleToolsCall(req *jsonRPCRequest) {
 	var result callToolResult
 
 	switch params.Name {
+	case "grove_overview":
+		result = s.toolOverview()
 	case "grove_list":
 		result = s.toolList()
 	case "grove_start":
-		result = s.toolStart(params.Arguments)
+		result = s.toolStart(params.Arguments, s.progress(params.Meta))
 	case "grove_stop":
 		result = s.toolStop(params.Arguments)
 	case "grove_url":
@@ -780,9 +856,13 @@ func (s *mcpServer) handleToolsCall(req *jsonRPCRequest) {
 	case "grove_status":
 		result = s.toolStatus(params.Arguments)
 	case "grove_restart":
-		result = s.toolRestart(params.Arguments)
+		result = s.toolRestart(params.Arguments, s.progress(params.Meta))
 	case "grove_new":
 		result = s.toolNew(params.Arguments)
+	case "grove_smoke":
+		result = s.toolSmoke(params.Arguments)
+	case "grove_review":
+		result = s.toolReview(params.Arguments)
 	default:
 		result = callToolResult{
 			Content: []toolContent{{Type: "text", Text: fmt.Sprintf("Unknown tool: %s", params.Name)}},
@@ -809,10 +889,10 @@ func (s *mcpServer) toolList() callToolResult {
 
 	// Add task nudge if there's an active task
 	cwd, _ := os.Getwd()
-	if taskID, taskDesc := discovery.GetActiveTask(cwd); taskID != "" {
-		sb.WriteString(fmt.Sprintf("📋 **Current Task:** %s\n", taskID))
-		if taskDesc != "" {
-			desc := ansi.Truncate(taskDesc, styles.TruncateDefault, styles.TruncateTail)
+	if task := tasks.Active(cwd); task != nil {
+		sb.WriteString(fmt.Sprintf("📋 **Current Task:** %s\n", task.ID))
+		if task.Title != "" {
+			desc := ansi.Truncate(task.Title, styles.TruncateDefault, styles.TruncateTail)
 			sb.WriteString(fmt.Sprintf("   %s\n", desc))
 		}
 		sb.WriteString("\n")
@@ -839,6 +919,9 @@ func (s *mcpServer) toolList() callToolResult {
 		if cfg.IsSubdomainMode() {
 			sb.WriteString(fmt.Sprintf("  Subdomains: %s\n", cfg.SubdomainURL(server.Name)))
 		}
+		if ws, ok := reg.GetWorkspace(server.Name); ok && ws.Share != nil {
+			sb.WriteString(fmt.Sprintf("  Shared: %s\n", describeShare(ws.Share)))
+		}
 		sb.WriteString(fmt.Sprintf("  Port: %d\n", server.Port))
 		if server.IsRunning() {
 			sb.WriteString(fmt.Sprintf("  PID: %d\n", server.PID))
@@ -849,7 +932,7 @@ func (s *mcpServer) toolList() callToolResult {
 	return mcpTextResult(sb.String())
 }
 
-func (s *mcpServer) toolStart(args map[string]interface{}) callToolResult {
+func (s *mcpServer) toolStart(args map[string]interface{}, progress *mcpProgress) callToolResult {
 	command, ok := args["command"].(string)
 	if !ok || command == "" {
 		return mcpErrorResult("command is required")
@@ -859,6 +942,10 @@ func (s *mcpServer) toolStart(args map[string]interface{}) callToolResult {
 	if p, ok := args["path"].(string); ok && p != "" {
 		path = p
 	}
+	wait := true
+	if v, ok := args["wait"].(bool); ok {
+		wait = v
+	}
 
 	// Make path absolute
 	absPath, err := filepath.Abs(path)
@@ -884,8 +971,7 @@ func (s *mcpServer) toolStart(args map[string]interface{}) callToolResult {
 	}
 
 	// Allocate port
-	allocator := port.NewAllocator(cfg.PortMin, cfg.PortMax)
-	serverPort, err := allocator.AllocateWithFallback(wt.Name, reg.GetUsedPorts())
+	serverPort, err := allocatePort(wt.Name, wt.Path, reg.GetUsedPorts())
 	if err != nil {
 		return mcpErrorResult(fmt.Sprintf("Failed to allocate port: %v", err))
 	}
@@ -905,6 +991,11 @@ func (s *mcpServer) toolStart(args map[string]interface{}) callToolResult {
 	if err != nil {
 		return mcpErrorResult(fmt.Sprintf("Failed to open log file: %v", err))
 	}
+	// Startup output is followed from the end of the previous runs' logs
+	var logOffset int64
+	if info, err := logFH.Stat(); err == nil {
+		logOffset = info.Size()
+	}
 
 	// Start the process via shell with stdin kept open
 	cmdParts := strings.Fields(command)
@@ -926,16 +1017,18 @@ func (s *mcpServer) toolStart(args map[string]interface{}) callToolResult {
 
 	pid := cmd.Process.Pid
 
+	exited := make(chan error, 1)
 	go func() {
 		// Wait for process to exit, close log file regardless of outcome
-		cmd.Wait() //nolint:errcheck // Process cleanup, error doesn't affect outcome
+		exited <- cmd.Wait()
 		logFH.Close()
 	}()
 
-	time.Sleep(100 * time.Millisecond)
-
-	if !mcpIsProcessRunning(pid) {
-		return mcpErrorResult(fmt.Sprintf("Server process exited immediately. Check logs at: %s", logFile))
+	if !wait {
+		time.Sleep(100 * time.Millisecond)
+		if !mcpIsProcessRunning(pid) {
+			return mcpErrorResult(fmt.Sprintf("Server process exited immediately. Check logs at: %s", logFile))
+		}
 	}
 
 	// Save to registry
@@ -951,6 +1044,9 @@ func (s *mcpServer) toolStart(args map[string]interface{}) callToolResult {
 		Branch:    wt.Branch,
 		LogFile:   logFile,
 	}
+	if wait {
+		server.Status = registry.StatusStarting
+	}
 
 	if err := reg.Set(server); err != nil {
 		return mcpErrorResult(fmt.Sprintf("Failed to save to registry: %v", err))
@@ -958,13 +1054,29 @@ func (s *mcpServer) toolStart(args map[string]interface{}) callToolResult {
 
 	var result string
 	if cfg.IsSubdomainMode() {
-		result = fmt.Sprintf("Server started successfully!\n\n- Name: %s\n- URL: %s\n- Subdomains: %s\n- Port: %d\n- PID: %d\n- Logs: %s",
+		result = fmt.Sprintf("- Name: %s\n- URL: %s\n- Subdomains: %s\n- Port: %d\n- PID: %d\n- Logs: %s",
 			wt.Name, url, cfg.SubdomainURL(wt.Name), serverPort, pid, logFile)
 	} else {
-		result = fmt.Sprintf("Server started successfully!\n\n- Name: %s\n- URL: %s\n- Port: %d\n- PID: %d\n- Logs: %s",
+		result = fmt.Sprintf("- Name: %s\n- URL: %s\n- Port: %d\n- PID: %d\n- Logs: %s",
 			wt.Name, url, serverPort, pid, logFile)
 	}
-	return mcpTextResult(result)
+	if !wait {
+		return mcpTextResult("Server started successfully!\n\n" + result)
+	}
+
+	projConfig, _ := project.Load(absPath)
+	startup, err := awaitStartup(server, projConfig, logOffset, exited, progress)
+	if err != nil {
+		return mcpErrorResult(fmt.Sprintf("%v\n\n%s%s", err, result, startup.logExcerpt()))
+	}
+	if !startup.ready {
+		markStarted(server.Name, pid, false) //nolint:errcheck // Best effort status update
+		return mcpTextResult(fmt.Sprintf("Server started but wasn't ready within %s; it may still be starting. Check it with grove_status.\n\n%s%s",
+			startup.elapsed.Round(time.Second), result, startup.logExcerpt()))
+	}
+	markStarted(server.Name, pid, true) //nolint:errcheck // Best effort status update
+	return mcpTextResult(fmt.Sprintf("Server started and ready in %s!\n\n%s%s",
+		startup.elapsed.Round(100*time.Millisecond), result, startup.logExcerpt()))
 }
 
 func (s *mcpServer) toolStop(args map[string]interface{}) callToolResult {
@@ -1003,6 +1115,67 @@ func (s *mcpServer) toolStop(args map[string]interface{}) callToolResult {
 	return mcpTextResult(fmt.Sprintf("Server '%s' stopped", name))
 }
 
+func (s *mcpServer) toolSmoke(args map[string]interface{}) callToolResult {
+	name, _ := args["name"].(string)
+
+	result, err := smokeServer(context.Background(), name, 10*time.Second)
+	if err != nil {
+		return mcpErrorResult(err.Error())
+	}
+
+	var sb strings.Builder
+	failed := 0
+	sb.WriteString(fmt.Sprintf("Smoke checks for %s (%s):\n\n", result.Server, result.URL))
+	for _, c := range result.Checks {
+		if c.Passed {
+			sb.WriteString(fmt.Sprintf("- PASS %s (status %d, %dms)\n", c.Name, c.Status, c.DurationMs))
+			continue
+		}
+		failed++
+		sb.WriteString(fmt.Sprintf("- FAIL %s: %s\n", c.Name, c.Error))
+	}
+
+	if failed > 0 {
+		sb.WriteString(fmt.Sprintf("\n%d of %d checks failed.", failed, len(result.Checks)))
+		return mcpErrorResult(sb.String())
+	}
+	sb.WriteString(fmt.Sprintf("\nAll %d checks passed.", len(result.Checks)))
+	return mcpTextResult(sb.String())
+}
+
+func (s *mcpServer) toolReview(args map[string]interface{}) callToolResult {
+	prStatus := true
+	if v, ok := args["pr_status"].(bool); ok {
+		prStatus = v
+	}
+
+	reg, err := registry.Load()
+	if err != nil {
+		return mcpErrorResult(fmt.Sprintf("Failed to load registry: %v", err))
+	}
+	// Best effort, as in grove_list
+	_, _ = reg.Cleanup()
+
+	items := collectReviewItems(context.Background(), reg)
+	if prStatus && len(items) > 0 {
+		enrichReviewItems(items)
+	}
+	return reviewResult(items)
+}
+
+// reviewResult returns the review queue as a bare JSON array, so clients
+// can parse it
+func reviewResult(items []*ReviewItem) callToolResult {
+	if items == nil {
+		items = []*ReviewItem{}
+	}
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return mcpErrorResult(fmt.Sprintf("Failed to encode review items: %v", err))
+	}
+	return mcpTextResult(string(data))
+}
+
 func (s *mcpServer) toolURL(args map[string]interface{}) callToolResult {
 	var name string
 
@@ -1027,6 +1200,9 @@ func (s *mcpServer) toolURL(args map[string]interface{}) callToolResult {
 		if cfg.IsSubdomainMode() {
 			return mcpTextResult(fmt.Sprintf("Server '%s' is not registered, but would be available at:\n\n- URL: %s\n- Subdomains: %s\n\nUse grove_start to start the server.", name, cfg.ServerURL(name, 0), cfg.SubdomainURL(name)))
 		}
+		if cfg.IsPathMode() {
+			return mcpTextResult(fmt.Sprintf("Server '%s' is not registered, but would be available at:\n\n- URL: %s\n\nUse grove_start to start the server.", name, cfg.ServerURL(name, 0)))
+		}
 		return mcpTextResult(fmt.Sprintf("Server '%s' is not registered.\n\nUse grove_start to start the server. It will be available at http://localhost:PORT", name))
 	}
 
@@ -1038,12 +1214,17 @@ func (s *mcpServer) toolURL(args map[string]interface{}) callToolResult {
 	// Use URL based on configured mode
 	url := cfg.ServerURL(server.Name, server.Port)
 
+	shared := ""
+	if ws, ok := reg.GetWorkspace(name); ok && ws.Share != nil {
+		shared = fmt.Sprintf("\n- Shared: %s", describeShare(ws.Share))
+	}
+
 	if cfg.IsSubdomainMode() {
-		return mcpTextResult(fmt.Sprintf("Server: %s (%s)\n\n- URL: %s\n- Subdomains: %s\n- Port: %d",
-			server.Name, status, url, cfg.SubdomainURL(server.Name), server.Port))
+		return mcpTextResult(fmt.Sprintf("Server: %s (%s)\n\n- URL: %s\n- Subdomains: %s\n- Port: %d%s",
+			server.Name, status, url, cfg.SubdomainURL(server.Name), server.Port, shared))
 	}
-	return mcpTextResult(fmt.Sprintf("Server: %s (%s)\n\n- URL: %s\n- Port: %d",
-		server.Name, status, url, server.Port))
+	return mcpTextResult(fmt.Sprintf("Server: %s (%s)\n\n- URL: %s\n- Port: %d%s",
+		server.Name, status, url, server.Port, shared))
 }
 
 func (s *mcpServer) toolStatus(args map[string]interface{}) callToolResult {
@@ -1076,6 +1257,9 @@ func (s *mcpServer) toolStatus(args map[string]interface{}) callToolResult {
 	sb.WriteString(fmt.Sprintf("Server: %s\n\n", server.Name))
 	sb.WriteString(fmt.Sprintf("- Status: %s\n", server.Status))
 	sb.WriteString(fmt.Sprintf("- URL: %s\n", url))
+	if ws, ok := reg.GetWorkspace(name); ok && ws.Share != nil {
+		sb.WriteString(fmt.Sprintf("- Shared: %s\n", describeShare(ws.Share)))
+	}
 	sb.WriteString(fmt.Sprintf("- Port: %d\n", server.Port))
 	sb.WriteString(fmt.Sprintf("- Path: %s\n", server.Path))
 
@@ -1098,10 +1282,18 @@ func (s *mcpServer) toolStatus(args map[string]interface{}) callToolResult {
 		sb.WriteString(fmt.Sprintf("- Log File: %s\n", server.LogFile))
 	}
 
+	// The last crash, with enough log to see why
+	if r, ok := crash.Latest(config.CrashesDir(), server.Name); ok {
+		sb.WriteString(fmt.Sprintf("- Last Crash: %s ago, %s\n", formatDuration(time.Since(r.Time)), r.Summary()))
+		if tail := r.Log[max(0, len(r.Log)-20):]; len(tail) > 0 {
+			sb.WriteString("\nLog before the crash:\n```\n" + strings.Join(tail, "\n") + "\n```\n")
+		}
+	}
+
 	return mcpTextResult(sb.String())
 }
 
-func (s *mcpServer) toolRestart(args map[string]interface{}) callToolResult {
+func (s *mcpServer) toolRestart(args map[string]interface{}, progress *mcpProgress) callToolResult {
 	name, ok := args["name"].(string)
 	if !ok || name == "" {
 		return mcpErrorResult("name is required")
@@ -1117,6 +1309,11 @@ func (s *mcpServer) toolRestart(args map[string]interface{}) callToolResult {
 		return mcpErrorResult(fmt.Sprintf("No server registered for '%s'", name))
 	}
 
+	// Multi-process servers are supervised by grove itself
+	if server.IsMultiProcess() {
+		return mcpErrorResult(fmt.Sprintf("Server '%s' runs multiple processes; use 'grove restart %s' instead", name, name))
+	}
+
 	// Stop if running
 	if server.IsRunning() {
 		process, err := os.FindProcess(server.PID)
@@ -1131,13 +1328,15 @@ func (s *mcpServer) toolRestart(args map[string]interface{}) callToolResult {
 		return mcpErrorResult(fmt.Sprintf("Server '%s' has no command recorded", name))
 	}
 
+	metrics.RecordRestart(name)
+
 	// Re-use the start logic
 	startArgs := map[string]interface{}{
 		"command": strings.Join(server.Command, " "),
 		"path":    server.Path,
 	}
 
-	return s.toolStart(startArgs)
+	return s.toolStart(startArgs, progress)
 }
 
 func (s *mcpServer) toolNew(args map[string]interface{}) callToolResult {
@@ -1226,13 +1425,13 @@ func (s *mcpServer) toolNew(args map[string]interface{}) callToolResult {
 	}
 
 	// Create the worktree
-	cmd := exec.Command("git", "worktree", "add", "-b", branch, worktreePath, baseBranch)
-	cmd.Dir = mainRepoPath
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return mcpErrorResult(fmt.Sprintf("Failed to create worktree: %v\nOutput: %s", err, string(output)))
+	if err := gitOps().AddWorktree(context.Background(), mainRepoPath, worktreePath, git.AddOptions{NewBranch: branch, Commit: baseBranch}); err != nil {
+		return mcpErrorResult(fmt.Sprintf("Failed to create worktree: %v", err))
 	}
 
+	// Stdout carries the MCP protocol, so hook output goes to stderr
+	hookErr := runGlobalHooks(hookPostCreate, hookTarget{Name: worktreeName, Path: worktreePath, Branch: branch}, os.Stderr)
+
 	var sb strings.Builder
 	sb.WriteString("Worktree created successfully!\n\n")
 	sb.WriteString(fmt.Sprintf("- Name: %s\n", worktreeName))
@@ -1242,6 +1441,9 @@ func (s *mcpServer) toolNew(args map[string]interface{}) callToolResult {
 	sb.WriteString("\nTo start working:\n")
 	sb.WriteString(fmt.Sprintf("  cd %s\n", worktreePath))
 	sb.WriteString("  grove start <command>\n")
+	if hookErr != nil {
+		sb.WriteString(fmt.Sprintf("\nWarning: post-create hook failed: %v\n", hookErr))
+	}
 
 	return mcpTextResult(sb.String())
 }
@@ -1301,7 +1503,13 @@ func (s *mcpServer) sendError(id interface{}, code int, message string, data int
 	s.send(resp)
 }
 
-func (s *mcpServer) send(resp jsonRPCResponse) {
-	data, _ := json.Marshal(resp)
-	fmt.Println(string(data))
+func (s *mcpServer) send(msg any) {
+	data, _ := json.Marshal(msg)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := s.out
+	if out == nil {
+		out = os.Stdout
+	}
+	fmt.Fprintln(out, string(data))
 }
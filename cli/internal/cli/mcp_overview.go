@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/iheanyi/grove/internal/discovery"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/worktree"
+)
+
+// overviewLimit caps the worktrees grove_overview lists, so its result
+// stays small however many are registered
+const overviewLimit = 40
+
+// overviewState is what grove_overview summarizes
+type overviewState struct {
+	workspaces []*registry.Workspace
+	// agents are the AI agents running in worktrees, by worktree path
+	agents map[string]*discovery.AgentInfo
+	proxy  *registry.ProxyInfo
+	// proxyRunning is whether the proxy process is alive
+	proxyRunning bool
+	// current is the worktree the MCP server runs in, if any
+	current string
+}
+
+func (s *mcpServer) toolOverview() callToolResult {
+	reg, err := registry.Load()
+	if err != nil {
+		return mcpErrorResult(fmt.Sprintf("Failed to load registry: %v", err))
+	}
+	// Best effort, as in grove_list
+	_, _ = reg.Cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	proxy := reg.GetProxy()
+	state := overviewState{
+		workspaces:   reg.ListWorkspaces(),
+		agents:       discovery.DetectAllAgents(ctx),
+		proxy:        proxy,
+		proxyRunning: proxy.IsRunning() && isProcessRunning(proxy.PID),
+	}
+	if wt, err := worktree.Detect(); err == nil {
+		state.current = wt.Name
+	}
+	for _, ws := range state.workspaces {
+		if agent, ok := state.agents[ws.Path]; ok {
+			discovery.SetAgentTask(agent, ws.Path)
+		}
+	}
+	return mcpTextResult(renderOverview(state, overviewLimit))
+}
+
+// renderOverview summarizes servers, worktrees, agents and the proxy in a
+// line per worktree: busiest first, at most limit of them
+func renderOverview(state overviewState, limit int) string {
+	var sb strings.Builder
+
+	switch {
+	case !cfg.UsesProxy():
+		sb.WriteString(fmt.Sprintf("Proxy: not used (%s mode, servers at http://localhost:PORT)\n", cfg.URLMode))
+	case state.proxyRunning:
+		sb.WriteString(fmt.Sprintf("Proxy: running (%s mode, ports %d/%d)\n", cfg.URLMode, state.proxy.HTTPPort, state.proxy.HTTPSPort))
+	default:
+		sb.WriteString(fmt.Sprintf("Proxy: stopped (%s mode needs it; run 'grove proxy start')\n", cfg.URLMode))
+	}
+	if state.current != "" {
+		sb.WriteString(fmt.Sprintf("Current worktree: %s\n", state.current))
+	}
+
+	workspaces := append([]*registry.Workspace(nil), state.workspaces...)
+	running, dirty, agents := 0, 0, 0
+	for _, ws := range workspaces {
+		if ws.IsRunning() {
+			running++
+		}
+		if ws.GitDirty || ws.DirtyFiles > 0 {
+			dirty++
+		}
+		if state.agents[ws.Path] != nil {
+			agents++
+		}
+	}
+	sb.WriteString(fmt.Sprintf("\n%d worktrees: %d running a server, %d with uncommitted changes, %d with an agent\n", len(workspaces), running, dirty, agents))
+	if len(workspaces) == 0 {
+		sb.WriteString("No worktrees registered. Use grove_start or grove_new to add one.")
+		return sb.String()
+	}
+
+	// Running servers, then agents, then changes, then the rest
+	rank := func(ws *registry.Workspace) int {
+		switch {
+		case ws.IsRunning():
+			return 0
+		case state.agents[ws.Path] != nil:
+			return 1
+		case ws.GitDirty || ws.DirtyFiles > 0:
+			return 2
+		default:
+			return 3
+		}
+	}
+	sort.SliceStable(workspaces, func(i, j int) bool {
+		if ri, rj := rank(workspaces[i]), rank(workspaces[j]); ri != rj {
+			return ri < rj
+		}
+		return workspaces[i].Name < workspaces[j].Name
+	})
+
+	for i, ws := range workspaces {
+		if i == limit {
+			sb.WriteString(fmt.Sprintf("- ... and %d more (see grove_list)\n", len(workspaces)-limit))
+			break
+		}
+		sb.WriteString("- " + overviewLine(ws, state.agents[ws.Path]) + "\n")
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// overviewLine describes a worktree: its branch, server, changes and agent
+func overviewLine(ws *registry.Workspace, agent *discovery.AgentInfo) string {
+	var parts []string
+
+	name := ws.Name
+	if ws.Branch != "" && ws.Branch != ws.Name {
+		name += " [" + ws.Branch + "]"
+	}
+
+	if server := ws.Server; server != nil && server.Status != "" {
+		desc := "server " + string(server.Status)
+		if ws.IsRunning() {
+			if server.Health != "" && server.Health != registry.HealthUnknown {
+				desc += " " + string(server.Health)
+			}
+			desc += fmt.Sprintf(" at %s, up %s", ws.GetURL(), formatDuration(ws.Uptime()))
+		}
+		parts = append(parts, desc)
+	} else {
+		parts = append(parts, "no server")
+	}
+
+	switch {
+	case ws.DirtyFiles > 0:
+		parts = append(parts, fmt.Sprintf("%d changed files", ws.DirtyFiles))
+	case ws.GitDirty:
+		parts = append(parts, "uncommitted changes")
+	}
+
+	if agent != nil {
+		desc := "agent " + agent.Type
+		if agent.ActiveTask != "" {
+			desc += " on " + agent.ActiveTask
+		}
+		if !agent.StartTime.IsZero() {
+			desc += ", " + formatDuration(time.Since(agent.StartTime))
+		}
+		parts = append(parts, desc)
+	}
+
+	return name + ": " + strings.Join(parts, "; ")
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iheanyi/grove/internal/discovery"
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+func TestRenderOverview(t *testing.T) {
+	useTestEnv(t)
+
+	state := overviewState{
+		workspaces: []*registry.Workspace{
+			{Name: "idle", Path: "/code/idle", Branch: "idle"},
+			{Name: "feature-auth", Path: "/code/feature-auth", Branch: "feature/auth", DirtyFiles: 3},
+			{Name: "api", Path: "/code/api", Branch: "main", Server: &registry.ServerState{
+				Port: 3100, PID: os.Getpid(), Status: registry.StatusRunning, URL: "http://localhost:3100",
+				Health: registry.HealthHealthy, StartedAt: time.Now().Add(-time.Hour),
+			}},
+		},
+		agents: map[string]*discovery.AgentInfo{
+			"/code/feature-auth": {Type: "claude", ActiveTask: "TK-7"},
+		},
+		proxy:   &registry.ProxyInfo{},
+		current: "api",
+	}
+
+	got := renderOverview(state, 2)
+	for _, want := range []string{
+		"Proxy: not used",
+		"Current worktree: api",
+		"3 worktrees: 1 running a server, 1 with uncommitted changes, 1 with an agent",
+		"- api [main]: server running healthy at http://localhost:3100, up 1h",
+		"- feature-auth [feature/auth]: no server; 3 changed files; agent claude on TK-7",
+		"- ... and 1 more",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("overview is missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Index(got, "- api") > strings.Index(got, "- feature-auth") {
+		t.Errorf("running servers should be listed first:\n%s", got)
+	}
+}
@@ -0,0 +1,269 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/iheanyi/grove/internal/crash"
+	"github.com/iheanyi/grove/internal/health"
+	"github.com/iheanyi/grove/internal/port"
+	"github.com/iheanyi/grove/internal/project"
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+const (
+	// startupPoll is how often grove_start reads the new server's log and
+	// checks whether it exited
+	startupPoll = 250 * time.Millisecond
+
+	// startupProbeInterval is how often grove_start probes the new server
+	startupProbeInterval = time.Second
+
+	// startupStatusInterval is how often grove_start repeats what it's
+	// waiting for when nothing else happens
+	startupStatusInterval = 5 * time.Second
+
+	// startupExcerptLines is how many of the last startup log lines the
+	// grove_start result includes
+	startupExcerptLines = 20
+
+	// maxStartupLine is how much of an unterminated line grove_start keeps
+	maxStartupLine = 64 * 1024
+
+	// maxProgressMessage is how much of a log line a progress notification
+	// carries
+	maxProgressMessage = 200
+)
+
+type requestMeta struct {
+	ProgressToken any `json:"progressToken,omitempty"`
+}
+
+type jsonRPCNotification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
+type progressParams struct {
+	ProgressToken any     `json:"progressToken"`
+	Progress      float64 `json:"progress"`
+	Message       string  `json:"message,omitempty"`
+}
+
+// mcpProgress sends progress notifications for a tool call whose client
+// asked for them with a progress token. Without one it does nothing.
+type mcpProgress struct {
+	s     *mcpServer
+	token any
+	n     int
+}
+
+// progress returns the progress reporter for a tool call
+func (s *mcpServer) progress(meta *requestMeta) *mcpProgress {
+	p := &mcpProgress{s: s}
+	if meta != nil {
+		p.token = meta.ProgressToken
+	}
+	return p
+}
+
+// report sends a progress notification with a message
+func (p *mcpProgress) report(message string) {
+	if p == nil || p.token == nil {
+		return
+	}
+	p.n++
+	p.s.send(jsonRPCNotification{
+		JSONRPC: "2.0",
+		Method:  "notifications/progress",
+		Params:  progressParams{ProgressToken: p.token, Progress: float64(p.n), Message: message},
+	})
+}
+
+// startupLog is a writer that reports each line of a starting server's
+// output as progress and keeps the last ones for the tool result
+type startupLog struct {
+	progress *mcpProgress
+	partial  []byte
+	lines    []string
+}
+
+func (l *startupLog) Write(p []byte) (int, error) {
+	data := append(l.partial, p...)
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		l.line(string(data[:i]))
+		data = data[i+1:]
+	}
+	if len(data) > maxStartupLine {
+		data = data[len(data)-maxStartupLine:]
+	}
+	l.partial = append([]byte(nil), data...)
+	return len(p), nil
+}
+
+func (l *startupLog) line(line string) {
+	line = strings.TrimSpace(ansi.Strip(line))
+	if line == "" {
+		return
+	}
+	l.lines = append(l.lines, line)
+	if len(l.lines) > startupExcerptLines {
+		l.lines = l.lines[len(l.lines)-startupExcerptLines:]
+	}
+	l.progress.report(ansi.Truncate(line, maxProgressMessage, "…"))
+}
+
+// startup is how a server's startup went
+type startup struct {
+	ready   bool
+	elapsed time.Duration
+	lines   []string
+}
+
+// logExcerpt returns the last startup log lines for a tool result
+func (st startup) logExcerpt() string {
+	if len(st.lines) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\n\nStartup log (last %d lines):\n```\n%s\n```", len(st.lines), strings.Join(st.lines, "\n"))
+}
+
+// awaitStartup follows a server started by grove_start until it's ready,
+// reporting its log lines and health probes as progress. It's ready when
+// its ready_log_pattern matches, or else when its port is listening and
+// its health check passes. The server is marked crashed if it exits
+// first, and unhealthy if it logs a fatal line. When neither happens
+// within the ready timeout, ready is false.
+func awaitStartup(server *registry.Server, projConfig *project.Config, offset int64, exited <-chan error, progress *mcpProgress) (startup, error) {
+	began := time.Now()
+	out := &startupLog{progress: progress}
+	result := func(ready bool) startup {
+		return startup{ready: ready, elapsed: time.Since(began), lines: out.lines}
+	}
+
+	ready, err := readyPattern(projConfig)
+	if err != nil {
+		return result(false), err
+	}
+	patterns, err := fatalPatterns(projConfig)
+	if err != nil {
+		return result(false), err
+	}
+	var fatal string
+	fatalWatcher := crash.NewFatalWatcher(patterns, func(line string) { fatal = line })
+	writers := []io.Writer{out, fatalWatcher}
+	var matcher *health.LogMatcher
+	if ready != nil {
+		matcher = health.NewLogMatcher(ready)
+		writers = append(writers, matcher)
+	}
+	log := &logFollower{path: server.LogFile, offset: offset}
+
+	var check project.HealthCheckConfig
+	if projConfig != nil {
+		check = projConfig.HealthCheck
+	}
+	target := health.Target{Name: server.Name, Port: server.Port, URL: server.URL, Dir: server.Path}
+
+	timeout := readyTimeout(projConfig)
+	if ready != nil {
+		progress.report(fmt.Sprintf("Started '%s' (PID %d), waiting up to %s for output matching '%s'", server.Name, server.PID, timeout, ready))
+	} else {
+		progress.report(fmt.Sprintf("Started '%s' (PID %d), waiting up to %s for it to be ready", server.Name, server.PID, timeout))
+	}
+
+	deadline := began.Add(timeout)
+	var lastProbe, lastStatus time.Time
+	var status string
+	for {
+		log.copyTo(io.MultiWriter(writers...))
+
+		if fatal != "" {
+			markStarted(server.Name, server.PID, false) //nolint:errcheck // Best effort status update
+			flagFatal(server.Name, server.PID, "", fatal)
+			return result(false), fmt.Errorf("server logged a fatal error while starting: %s", fatal)
+		}
+		select {
+		case err := <-exited:
+			// Pick up the output it logged on its way out
+			log.copyTo(out)
+			markExited(server.Name, server.PID)
+			if err == nil {
+				err = errors.New("exit status 0")
+			}
+			return result(false), fmt.Errorf("server exited before it was ready (%v)", err)
+		default:
+		}
+
+		if matcher != nil {
+			select {
+			case <-matcher.Matched():
+				progress.report("Ready: matched ready_log_pattern")
+				return result(true), nil
+			default:
+			}
+		} else if time.Since(lastProbe) >= startupProbeInterval {
+			lastProbe = time.Now()
+			probeStatus, ok := probeStartup(check, target)
+			if ok {
+				progress.report("Ready: " + probeStatus)
+				return result(true), nil
+			}
+			if probeStatus != status || time.Since(lastStatus) >= startupStatusInterval {
+				status, lastStatus = probeStatus, time.Now()
+				progress.report(fmt.Sprintf("%s (%s)", probeStatus, time.Since(began).Round(time.Second)))
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return result(false), nil
+		}
+		time.Sleep(startupPoll)
+	}
+}
+
+// probeStartup probes a starting server once, returning whether it's
+// ready and what it found. Like depends_on, an open port is enough unless
+// the health check says more.
+func probeStartup(check project.HealthCheckConfig, target health.Target) (string, bool) {
+	probe := check.Probe()
+	if probe != project.HealthCheckCommand && !port.IsListening(target.Port) {
+		return fmt.Sprintf("Waiting for port %d", target.Port), false
+	}
+	if probe == project.HealthCheckHTTP && check.Path == "" {
+		return fmt.Sprintf("port %d is listening", target.Port), true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := health.Check(ctx, check, target); err != nil {
+		return fmt.Sprintf("Health check (%s) failing: %v", health.Describe(check, target), err), false
+	}
+	return fmt.Sprintf("health check (%s) passed", health.Describe(check, target)), true
+}
+
+// markExited marks a server that exited while starting as crashed, unless
+// it was stopped or restarted meanwhile
+func markExited(name string, pid int) {
+	reg, err := registry.Load()
+	if err != nil {
+		return
+	}
+	if s, ok := reg.Get(name); ok && s.PID == pid {
+		s.Status = registry.StatusCrashed
+		s.PID = 0
+		s.StoppedAt = time.Now()
+		reg.Set(s) //nolint:errcheck // Best effort status update
+	}
+}
@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+// progressMessages returns the messages of the progress notifications an
+// MCP server wrote
+func progressMessages(t *testing.T, out *bytes.Buffer) []string {
+	t.Helper()
+	var messages []string
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var n struct {
+			Method string         `json:"method"`
+			Params progressParams `json:"params"`
+		}
+		if err := json.Unmarshal([]byte(line), &n); err != nil {
+			t.Fatalf("invalid notification %q: %v", line, err)
+		}
+		if n.Method != "notifications/progress" || n.Params.ProgressToken != "start-1" {
+			t.Fatalf("notification = %+v, want progress for start-1", n)
+		}
+		messages = append(messages, n.Params.Message)
+	}
+	return messages
+}
+
+func TestAwaitStartup(t *testing.T) {
+	useTestEnv(t)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	logFile := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(logFile, []byte("last run: EADDRINUSE\n\x1b[32mListening\x1b[0m on :3000\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	server := &registry.Server{Name: "app", PID: 4242, Port: ln.Addr().(*net.TCPAddr).Port, LogFile: logFile}
+
+	var out bytes.Buffer
+	s := &mcpServer{out: &out}
+	// The last run's output is skipped
+	offset := int64(len("last run: EADDRINUSE\n"))
+	st, err := awaitStartup(server, nil, offset, make(chan error), s.progress(&requestMeta{ProgressToken: "start-1"}))
+	if err != nil || !st.ready {
+		t.Fatalf("awaitStartup = %v, %v; want ready", st.ready, err)
+	}
+	if got := st.logExcerpt(); !strings.Contains(got, "Listening on :3000") || strings.Contains(got, "EADDRINUSE") {
+		t.Errorf("log excerpt = %q", got)
+	}
+
+	messages := progressMessages(t, &out)
+	if len(messages) != 3 || messages[1] != "Listening on :3000" || !strings.HasPrefix(messages[2], "Ready: port") {
+		t.Errorf("progress = %q, want the start, the log line and ready", messages)
+	}
+}
+
+func TestAwaitStartupExited(t *testing.T) {
+	useTestEnv(t)
+	reg := mustReload(t)
+	logFile := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(logFile, []byte("Error: Cannot find module 'express'\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	server := &registry.Server{Name: "app", Path: t.TempDir(), PID: 4242, Port: 1, Status: registry.StatusStarting, LogFile: logFile}
+	if err := reg.Set(server); err != nil {
+		t.Fatal(err)
+	}
+
+	exited := make(chan error, 1)
+	exited <- errors.New("exit status 1")
+	// Without a progress token nothing is sent
+	var out bytes.Buffer
+	s := &mcpServer{out: &out}
+	st, err := awaitStartup(server, nil, 0, exited, s.progress(nil))
+	if err == nil || !strings.Contains(err.Error(), "exit status 1") {
+		t.Fatalf("awaitStartup error = %v, want the exit status", err)
+	}
+	if !strings.Contains(st.logExcerpt(), "Cannot find module 'express'") {
+		t.Errorf("log excerpt = %q, want why it exited", st.logExcerpt())
+	}
+	if out.Len() != 0 {
+		t.Errorf("sent %q without a progress token", out.String())
+	}
+	if s, _ := mustReload(t).Get("app"); s.Status != registry.StatusCrashed {
+		t.Errorf("status = %s, want crashed", s.Status)
+	}
+}
@@ -0,0 +1,288 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/crash"
+	"github.com/iheanyi/grove/internal/project"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/worktree"
+)
+
+// MCP prompt types
+type promptsCapability struct{}
+
+type prompt struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Arguments   []promptArgument `json:"arguments,omitempty"`
+}
+
+type promptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+type promptsListResult struct {
+	Prompts []prompt `json:"prompts"`
+}
+
+type getPromptParams struct {
+	Name      string            `json:"name"`
+	Arguments map[string]string `json:"arguments"`
+}
+
+type getPromptResult struct {
+	Description string          `json:"description,omitempty"`
+	Messages    []promptMessage `json:"messages"`
+}
+
+type promptMessage struct {
+	Role    string      `json:"role"`
+	Content toolContent `json:"content"`
+}
+
+// mcpPrompt is a workflow template: its listing, and what fills it in
+// with the current grove state
+type mcpPrompt struct {
+	prompt
+	render func(args map[string]string) (string, error)
+}
+
+// mcpPrompts are the workflows offered by prompts/list
+var mcpPrompts = []mcpPrompt{
+	{
+		prompt: prompt{
+			Name:        "start_and_verify",
+			Description: "Start the dev server for this project and verify it responds",
+			Arguments: []promptArgument{
+				{Name: "path", Description: "Project or worktree directory (defaults to the current directory)"},
+				{Name: "command", Description: "Dev server command, if .grove.yaml doesn't set one"},
+			},
+		},
+		render: renderStartPrompt,
+	},
+	{
+		prompt: prompt{
+			Name:        "new_worktree",
+			Description: "Create a worktree for a branch and set it up to run",
+			Arguments: []promptArgument{
+				{Name: "branch", Description: "Branch to create, e.g. feature/auth", Required: true},
+				{Name: "base", Description: "Branch to create it from (defaults to main or master)"},
+				{Name: "path", Description: "Repository directory (defaults to the current directory)"},
+			},
+		},
+		render: renderNewWorktreePrompt,
+	},
+	{
+		prompt: prompt{
+			Name:        "review_queue",
+			Description: "Go through the worktrees with changes and say which are ready for review or a pull request",
+		},
+		render: renderReviewPrompt,
+	},
+	{
+		prompt: prompt{
+			Name:        "diagnose_crash",
+			Description: "Find out why a dev server crashed or is unhealthy, fix it and restart it",
+			Arguments: []promptArgument{
+				{Name: "name", Description: "Server name (defaults to the current worktree)"},
+			},
+		},
+		render: renderCrashPrompt,
+	},
+}
+
+func (s *mcpServer) handlePromptsList(req *jsonRPCRequest) {
+	prompts := make([]prompt, 0, len(mcpPrompts))
+	for _, p := range mcpPrompts {
+		prompts = append(prompts, p.prompt)
+	}
+	s.sendResult(req.ID, promptsListResult{Prompts: prompts})
+}
+
+func (s *mcpServer) handlePromptsGet(req *jsonRPCRequest) {
+	var params getPromptParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		s.sendError(req.ID, -32602, "Invalid params", err.Error())
+		return
+	}
+
+	result, err := getPrompt(params.Name, params.Arguments)
+	if err != nil {
+		s.sendError(req.ID, -32602, "Invalid params", err.Error())
+		return
+	}
+	s.sendResult(req.ID, result)
+}
+
+// getPrompt fills in a prompt with its arguments and the current state
+func getPrompt(name string, args map[string]string) (getPromptResult, error) {
+	for _, p := range mcpPrompts {
+		if p.Name != name {
+			continue
+		}
+		for _, arg := range p.Arguments {
+			if arg.Required && strings.TrimSpace(args[arg.Name]) == "" {
+				return getPromptResult{}, fmt.Errorf("argument %q is required", arg.Name)
+			}
+		}
+		text, err := p.render(args)
+		if err != nil {
+			return getPromptResult{}, err
+		}
+		return getPromptResult{
+			Description: p.Description,
+			Messages:    []promptMessage{{Role: "user", Content: toolContent{Type: "text", Text: text}}},
+		}, nil
+	}
+	return getPromptResult{}, fmt.Errorf("unknown prompt %q", name)
+}
+
+// promptWorktree detects the worktree at path, or the current directory
+func promptWorktree(path string) (*worktree.Info, error) {
+	if path == "" {
+		var err error
+		if path, err = os.Getwd(); err != nil {
+			return nil, err
+		}
+	}
+	return worktree.DetectAt(path)
+}
+
+func renderStartPrompt(args map[string]string) (string, error) {
+	wt, err := promptWorktree(args["path"])
+	if err != nil {
+		return "", fmt.Errorf("failed to detect worktree: %w", err)
+	}
+	projConfig, _ := project.Load(wt.Path)
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Start the dev server for the worktree '%s' (%s, branch %s) with grove and verify it responds.\n\n", wt.Name, wt.Path, wt.Branch))
+
+	if reg, err := registry.Load(); err == nil {
+		if server, ok := reg.Get(wt.Name); ok && server.IsRunning() {
+			sb.WriteString(fmt.Sprintf("It's already running at %s (port %d), so skip starting it and go straight to verifying it.\n\n", server.URL, server.Port))
+		}
+	}
+
+	command := args["command"]
+	switch {
+	case command != "":
+		sb.WriteString(fmt.Sprintf("1. Start it with grove_start, command %q and path %q.\n", command, wt.Path))
+	case projConfig != nil && projConfig.Command != "":
+		sb.WriteString(fmt.Sprintf("1. Start it with grove_start, path %q. Its .grove.yaml runs %q, so pass that as the command.\n", wt.Path, projConfig.Command))
+	default:
+		sb.WriteString(fmt.Sprintf("1. Work out the dev server command from the project (package.json scripts, Procfile.dev, bin/dev, Makefile, ...), then start it with grove_start and path %q. The server must listen on the PORT environment variable.\n", wt.Path))
+	}
+	sb.WriteString("2. Check it with grove_status. If it crashed or is unhealthy, read the log excerpt it returns, fix the cause and use grove_restart.\n")
+	if projConfig != nil && len(projConfig.Smoke) > 0 {
+		sb.WriteString(fmt.Sprintf("3. Run grove_smoke: .grove.yaml defines %d smoke checks. Fix any that fail.\n", len(projConfig.Smoke)))
+	} else {
+		sb.WriteString("3. Request the server's URL (from grove_url) and check that it returns a successful response rather than an error page.\n")
+	}
+	sb.WriteString("\nFinish by reporting the URL and whether the server responds correctly.")
+	return sb.String(), nil
+}
+
+func renderNewWorktreePrompt(args map[string]string) (string, error) {
+	branch, base := args["branch"], args["base"]
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Create a git worktree for the branch %q with grove and get it ready to run.\n\n", branch))
+
+	path := args["path"]
+	if wt, err := promptWorktree(path); err == nil {
+		repo := wt.Path
+		if wt.MainWorktreePath != "" {
+			repo = wt.MainWorktreePath
+		}
+		sb.WriteString(fmt.Sprintf("The repository is %s (currently on %s).\n\n", repo, wt.Branch))
+		path = repo
+	}
+
+	newArgs := fmt.Sprintf("branch %q", branch)
+	if base != "" {
+		newArgs += fmt.Sprintf(", base %q", base)
+	}
+	if path != "" {
+		newArgs += fmt.Sprintf(", path %q", path)
+	}
+	sb.WriteString(fmt.Sprintf("1. Create it with grove_new (%s). Note the worktree name and path it returns.\n", newArgs))
+	sb.WriteString("2. Set it up in the new path: install dependencies with the project's package manager and copy any untracked local config (e.g. .env files) the main worktree needs to run.\n")
+
+	projConfig, _ := project.Load(path)
+	switch {
+	case projConfig != nil && projConfig.Command != "":
+		sb.WriteString(fmt.Sprintf("3. Start its dev server with grove_start and the new path; .grove.yaml runs %q.\n", projConfig.Command))
+	default:
+		sb.WriteString("3. Start its dev server with grove_start, the new path and the project's dev command.\n")
+	}
+	sb.WriteString("4. Confirm it's healthy with grove_status.\n")
+	sb.WriteString("\nFinish by reporting the worktree's name, path and URL.")
+	return sb.String(), nil
+}
+
+func renderReviewPrompt(args map[string]string) (string, error) {
+	var sb strings.Builder
+	sb.WriteString("Go through grove's review queue and decide which worktrees are ready for human review or a pull request.\n\n")
+
+	if reg, err := registry.Load(); err == nil {
+		sb.WriteString(fmt.Sprintf("grove has %d worktrees registered, %d with a running server.\n\n", len(reg.ListWorkspaces()), len(reg.ListRunning())))
+	}
+
+	sb.WriteString("1. Call grove_review for the queue: each worktree with uncommitted changes or unpushed commits, its diff stats, task, URL and PR and CI status.\n")
+	sb.WriteString("2. Skip items already reviewed at their current commit.\n")
+	sb.WriteString("3. For items with a running server, run grove_smoke and note failures.\n")
+	sb.WriteString("4. Judge each remaining item: is the work complete for its task, are there uncommitted changes left, is CI passing?\n")
+	sb.WriteString("\nFinish with a list of worktrees ready for review or a PR, and for the rest, what's missing. Don't push or open pull requests without asking.")
+	return sb.String(), nil
+}
+
+func renderCrashPrompt(args map[string]string) (string, error) {
+	name := args["name"]
+	if name == "" {
+		wt, err := promptWorktree("")
+		if err != nil {
+			return "", fmt.Errorf("failed to detect worktree: %w; pass a name", err)
+		}
+		name = wt.Name
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Find out why the dev server '%s' crashed or is unhealthy, fix the cause and get it running again.\n\n", name))
+
+	if reg, err := registry.Load(); err == nil {
+		if server, ok := reg.Get(name); ok {
+			sb.WriteString(fmt.Sprintf("It's %s", server.Status))
+			if server.Health != "" && server.Health != registry.HealthUnknown {
+				sb.WriteString(fmt.Sprintf(" (health: %s)", server.Health))
+			}
+			sb.WriteString(fmt.Sprintf(", in %s.", server.Path))
+			if server.LogFile != "" {
+				sb.WriteString(fmt.Sprintf(" Its log is %s.", server.LogFile))
+			}
+			sb.WriteString("\n\n")
+		}
+	}
+	if r, ok := crash.Latest(config.CrashesDir(), name); ok {
+		sb.WriteString(fmt.Sprintf("Its last crash was %s ago: %s\n", formatDuration(time.Since(r.Time)), r.Summary()))
+		if tail := r.Log[max(0, len(r.Log)-30):]; len(tail) > 0 {
+			sb.WriteString("\nLog before the crash:\n```\n" + strings.Join(tail, "\n") + "\n```\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("1. Call grove_status for its current state and recent crash log.\n")
+	sb.WriteString("2. Find the root cause in the log and the code (a missing dependency, a bad config value, a port or database problem, a code error, ...).\n")
+	sb.WriteString("3. Fix it, then use grove_restart, or grove_start if it isn't registered as running.\n")
+	sb.WriteString("4. Confirm it stays up with grove_status.\n")
+	sb.WriteString("\nFinish by explaining the cause and the fix.")
+	return sb.String(), nil
+}
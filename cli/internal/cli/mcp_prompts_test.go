@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGetPrompt(t *testing.T) {
+	useTestEnv(t)
+
+	seen := make(map[string]bool)
+	for _, p := range mcpPrompts {
+		if seen[p.Name] {
+			t.Errorf("duplicate prompt %q", p.Name)
+		}
+		seen[p.Name] = true
+	}
+
+	if _, err := getPrompt("missing", nil); err == nil {
+		t.Error("unknown prompts should fail")
+	}
+	if _, err := getPrompt("new_worktree", map[string]string{}); err == nil || !strings.Contains(err.Error(), "branch") {
+		t.Errorf("new_worktree without a branch: got %v, want a required argument error", err)
+	}
+
+	result, err := getPrompt("new_worktree", map[string]string{"branch": "feature/auth", "base": "develop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Messages) != 1 || result.Messages[0].Role != "user" {
+		t.Fatalf("messages = %+v, want one user message", result.Messages)
+	}
+	if text := result.Messages[0].Content.Text; !strings.Contains(text, `branch "feature/auth", base "develop"`) {
+		t.Errorf("new_worktree prompt doesn't pass the arguments to grove_new:\n%s", text)
+	}
+}
+
+func TestStartPromptUsesProjectCommand(t *testing.T) {
+	useTestEnv(t)
+
+	dir := t.TempDir()
+	gitRun(t, dir, "init", "-q", "-b", "main")
+	gitRun(t, dir, "commit", "-q", "--allow-empty", "-m", "init")
+	if err := os.WriteFile(filepath.Join(dir, ".grove.yaml"), []byte("command: bin/dev\nsmoke:\n  - path: /\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := getPrompt("start_and_verify", map[string]string{"path": dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := result.Messages[0].Content.Text
+	for _, want := range []string{`runs "bin/dev"`, "grove_smoke"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("start_and_verify prompt is missing %q:\n%s", want, text)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+// reviewQueue runs grove_review and parses its result
+func reviewQueue(t *testing.T) []ReviewItem {
+	t.Helper()
+	result := (&mcpServer{}).toolReview(map[string]interface{}{"pr_status": false})
+	if result.IsError || len(result.Content) != 1 {
+		t.Fatalf("toolReview() = %+v", result)
+	}
+	var items []ReviewItem
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &items); err != nil {
+		t.Fatalf("toolReview() isn't a JSON array: %v\n%s", err, result.Content[0].Text)
+	}
+	return items
+}
+
+func TestMCPReview(t *testing.T) {
+	useTestEnv(t)
+
+	if items := reviewQueue(t); len(items) != 0 {
+		t.Errorf("empty registry: items = %+v, want none", items)
+	}
+
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", dir).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v\n%s", err, out)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	reg, err := registry.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.SetWorkspace(&registry.Workspace{Name: "feature", Path: dir, Branch: "feature"}); err != nil {
+		t.Fatal(err)
+	}
+
+	items := reviewQueue(t)
+	if len(items) != 1 || items[0].Name != "feature" || !items[0].IsDirty || items[0].DirtyFiles != 1 {
+		t.Errorf("items = %+v, want the dirty feature worktree", items)
+	}
+}
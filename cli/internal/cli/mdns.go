@@ -0,0 +1,155 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/iheanyi/grove/internal/lan"
+	"github.com/iheanyi/grove/internal/mdns"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/spf13/cobra"
+)
+
+// networkBrowseTime is how long 'grove ls --network' waits for answers
+const networkBrowseTime = 2 * time.Second
+
+// mdnsHostname returns the name this machine's servers are advertised
+// under, e.g. laptop in feature-auth.laptop.local
+func mdnsHostname() string {
+	host := cfg.MDNS.Hostname
+	if host == "" {
+		host, _ = os.Hostname()
+		host, _, _ = strings.Cut(host, ".")
+	}
+	if host == "" {
+		host = "grove"
+	}
+	return mdns.Label(strings.ToLower(host))
+}
+
+// mdnsServices returns the mDNS services of the running servers the proxy
+// serves to the network
+func mdnsServices(servers []*registry.Server) []mdns.Service {
+	host := mdnsHostname()
+	var services []mdns.Service
+	for _, server := range servers {
+		lanPort := cfg.LANPort(server.Port)
+		if !server.IsRunning() || lanPort == 0 {
+			continue
+		}
+		txt := []string{"grove=1", "worktree=" + server.Name, "path=/"}
+		if server.Branch != "" {
+			txt = append(txt, "branch="+server.Branch)
+		}
+		services = append(services, mdns.Service{
+			Instance: fmt.Sprintf("%s (%s)", server.Name, host),
+			Host:     mdnsHost(server.Name),
+			Port:     lanPort,
+			TXT:      txt,
+		})
+	}
+	return services
+}
+
+// mdnsHost returns the name a server's address is advertised under
+func mdnsHost(name string) string {
+	return fmt.Sprintf("%s.%s.local.", mdns.Label(strings.ToLower(name)), mdnsHostname())
+}
+
+// advertiseServers advertises running servers over mDNS until ctx is done,
+// reading the registry for every answer so servers started and stopped
+// elsewhere are picked up
+func advertiseServers(ctx context.Context) {
+	if !cfg.ServesLAN() {
+		fmt.Println("Warning: mdns.enabled needs the proxy to serve the network; set proxy_listen_address: 0.0.0.0")
+		return
+	}
+	ip, err := lan.IP()
+	if err != nil {
+		fmt.Printf("Warning: not advertising servers over mDNS: %v\n", err)
+		return
+	}
+
+	responder := &mdns.Responder{
+		IP: ip,
+		Services: func() []mdns.Service {
+			reg, err := registry.Load()
+			if err != nil {
+				return nil
+			}
+			return mdnsServices(reg.List())
+		},
+	}
+	fmt.Printf("Advertising servers over mDNS as *.%s.local\n", mdnsHostname())
+	if err := responder.Serve(ctx); err != nil {
+		fmt.Printf("Warning: mDNS advertisement stopped: %v\n", err)
+	}
+}
+
+// jsonNetworkServer is a server in 'grove ls --network --json'
+type jsonNetworkServer struct {
+	Name     string `json:"name"`
+	Instance string `json:"instance"`
+	Host     string `json:"host"`
+	URL      string `json:"url"`
+	Branch   string `json:"branch,omitempty"`
+}
+
+// runLsNetwork lists the servers grove advertises over mDNS on the local
+// network, this machine's included
+func runLsNetwork(cmd *cobra.Command) error {
+	outputJSON, _ := cmd.Flags().GetBool("json")
+
+	entries, err := mdns.Browse(cmd.Context(), networkBrowseTime)
+	if err != nil {
+		return fmt.Errorf("failed to browse the network: %w", err)
+	}
+	servers := networkServers(entries)
+
+	if outputJSON {
+		data, err := json.MarshalIndent(servers, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(servers) == 0 {
+		fmt.Println("No grove servers found on the network.")
+		fmt.Println("Servers are advertised while the proxy runs with mdns.enabled and proxy_listen_address set.")
+		return nil
+	}
+	fmt.Printf("%-30s %-40s %s\n", "NAME", "URL", "BRANCH")
+	for _, s := range servers {
+		fmt.Printf("%-30s %-40s %s\n", s.Instance, s.URL, s.Branch)
+	}
+	return nil
+}
+
+// networkServers returns the grove servers among browsed services
+func networkServers(entries []mdns.Entry) []jsonNetworkServer {
+	servers := []jsonNetworkServer{}
+	for _, e := range entries {
+		if _, ok := e.TXT["grove"]; !ok {
+			continue
+		}
+		host := strings.TrimSuffix(e.Host, ".")
+		url := fmt.Sprintf("http://%s:%d", host, e.Port)
+		if e.IP != nil {
+			url = lan.URL(e.IP, e.Port)
+		}
+		servers = append(servers, jsonNetworkServer{
+			Name:     e.TXT["worktree"],
+			Instance: e.Instance,
+			Host:     host,
+			URL:      url,
+			Branch:   e.TXT["branch"],
+		})
+	}
+	return servers
+}
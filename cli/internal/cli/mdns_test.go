@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"net"
+	"testing"
+
+	"github.com/iheanyi/grove/internal/mdns"
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+func TestMDNSServices(t *testing.T) {
+	useTestEnv(t)
+	cfg.MDNS.Hostname = "Laptop"
+
+	servers := []*registry.Server{
+		{Name: "feature-auth", Branch: "feature/auth", Port: 3042, Status: registry.StatusRunning},
+		{Name: "stopped", Port: 3043, Status: registry.StatusStopped},
+	}
+	services := mdnsServices(servers)
+	if len(services) != 1 {
+		t.Fatalf("services = %+v, want only the running server", services)
+	}
+	s := services[0]
+	if s.Instance != "feature-auth (laptop)" || s.Host != "feature-auth.laptop.local." || s.Port != 13042 {
+		t.Errorf("service = %+v, want feature-auth at feature-auth.laptop.local on its LAN port", s)
+	}
+
+	entries := []mdns.Entry{
+		{Instance: "feature-auth (laptop)", Host: s.Host, IP: net.ParseIP("192.168.1.20"), Port: s.Port, TXT: map[string]string{"grove": "1", "worktree": "feature-auth", "branch": "feature/auth"}},
+		{Instance: "Printer", Host: "printer.local.", Port: 80, TXT: map[string]string{}},
+	}
+	got := networkServers(entries)
+	if len(got) != 1 || got[0].Name != "feature-auth" || got[0].URL != "http://192.168.1.20:13042" || got[0].Host != "feature-auth.laptop.local" {
+		t.Errorf("networkServers = %+v, want only grove's server", got)
+	}
+}
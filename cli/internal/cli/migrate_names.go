@@ -0,0 +1,304 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/iheanyi/grove/internal/accesslog"
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var migrateNamesCmd = &cobra.Command{
+	Use:   "migrate-names",
+	Short: "Rename registered worktrees to match worktree_name",
+	Long: `Rename registered worktrees to the names worktree_name gives them, e.g.
+after changing it to "{repo}-{branch}", or registered under an older spelling
+of their name (e.g. release-v100 for release/v1.0.0, now release-v1-0-0),
+which they keep until renamed here.
+
+Each rename updates the registry entry and its URL, and moves the server's
+log, share log, access log and crash reports to the new name. Worktrees
+whose new name is taken get a suffix derived from their path, so the same
+worktree always gets the same name.
+
+Running servers are skipped; stop them first. Entries whose directory no
+longer exists are left alone (see 'grove gc').
+
+Examples:
+  grove migrate-names --dry-run   # Show the renames
+  grove migrate-names`,
+	Args: cobra.NoArgs,
+	RunE: runMigrateNames,
+}
+
+func init() {
+	migrateNamesCmd.Flags().Bool("dry-run", false, "Show the renames without making them")
+}
+
+// resolveWorktreeName gives a worktree a distinct name when its name is
+// registered to another worktree that still exists: namespaced by
+// repository when the other is in a different one, otherwise suffixed
+func resolveWorktreeName(name, path, mainPath string) string {
+	workspaces, err := registeredWorkspaces()
+	if err != nil {
+		return name
+	}
+	return uniqueWorktreeName(workspaces, name, path, func() string {
+		return worktree.RepoID(path, mainPath)
+	})
+}
+
+// workspaceCache holds the registry's workspaces for resolveWorktreeName,
+// which runs for every worktree detected or listed, until the registry's
+// files change
+var workspaceCache struct {
+	mu         sync.Mutex
+	stamp      string
+	workspaces []*registry.Workspace
+}
+
+// registeredWorkspaces returns the registered workspaces, loading the
+// registry only if it changed since the last call
+func registeredWorkspaces() ([]*registry.Workspace, error) {
+	stamp := registryStamp()
+	workspaceCache.mu.Lock()
+	defer workspaceCache.mu.Unlock()
+	if workspaceCache.workspaces != nil && workspaceCache.stamp == stamp {
+		return workspaceCache.workspaces, nil
+	}
+
+	reg, err := registry.Load()
+	if err != nil {
+		return nil, err
+	}
+	workspaceCache.stamp = stamp
+	workspaceCache.workspaces = reg.ListWorkspaces()
+	return workspaceCache.workspaces, nil
+}
+
+// registryStamp identifies the registry's files and their state, changing
+// whenever they're written
+func registryStamp() string {
+	var sb strings.Builder
+	for _, file := range registry.Files() {
+		sb.WriteString(file)
+		if info, err := os.Stat(file); err == nil {
+			fmt.Fprintf(&sb, " %d %d", info.ModTime().UnixNano(), info.Size())
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// uniqueWorktreeName returns name, or a distinct name if another existing
+// worktree has it. A worktree registered under an older spelling of name
+// keeps it. repo returns the worktree's repository ID; it's only called on
+// a collision.
+func uniqueWorktreeName(workspaces []*registry.Workspace, name, path string, repo func() string) string {
+	// Names from before worktree names were sanitized as they are now, e.g.
+	// release-v100 for release/v1.0.0 rather than release-v1-0-0, are kept
+	// so upgrading doesn't orphan their registry entries, URLs and logs.
+	// 'grove migrate-names' renames them.
+	for _, ws := range workspaces {
+		if ws.Path == path && ws.Name != name && nameLetters(ws.Name) == nameLetters(name) {
+			return ws.Name
+		}
+	}
+
+	taken := func(n string) bool {
+		for _, ws := range workspaces {
+			if ws.Name == n && ws.Path != "" && ws.Path != path && fileExists(ws.Path) {
+				return true
+			}
+		}
+		return false
+	}
+	if !taken(name) {
+		return name
+	}
+
+	var otherRepo string
+	for _, ws := range workspaces {
+		if ws.Name == name {
+			otherRepo = ws.Repo
+		}
+	}
+	return worktree.DistinctName(name, path, repo(), otherRepo, taken)
+}
+
+// nameLetters returns a name without its separators, which is what
+// spellings of the same name have in common
+func nameLetters(name string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return -1
+	}, name)
+}
+
+// nameMigration renames one registered worktree
+type nameMigration struct {
+	From string
+	To   string
+	Path string
+}
+
+func runMigrateNames(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	reg, err := registry.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+
+	var running []string
+	migrations := planNameMigrations(reg.ListWorkspaces(), func(ws *registry.Workspace) (string, bool) {
+		info, err := worktree.DetectAt(ws.Path)
+		if err != nil || info.Path != ws.Path {
+			return "", false
+		}
+		name := worktree.Sanitize(filepath.Base(ws.Path))
+		if info.IsWorktree {
+			name = worktree.TemplateName(info.MainWorktreePath, info.Branch, info.Path)
+		}
+		if ws.IsRunning() && name != ws.Name {
+			running = append(running, ws.Name)
+			return "", false
+		}
+		return name, true
+	})
+
+	for _, name := range running {
+		fmt.Printf("Skipping %s: server is running (stop it first)\n", name)
+	}
+	if len(migrations) == 0 {
+		fmt.Println("All worktrees already match worktree_name")
+		return nil
+	}
+
+	for _, m := range migrations {
+		fmt.Printf("  %s → %s\n", m.From, m.To)
+	}
+	if dryRun {
+		fmt.Printf("\nWould rename %d worktrees. Run without --dry-run to rename them.\n", len(migrations))
+		return nil
+	}
+
+	if err := applyNameMigrations(reg, migrations, cfg.LogDir, config.CrashesDir()); err != nil {
+		return err
+	}
+	fmt.Printf("\nRenamed %d worktrees\n", len(migrations))
+	if cfg.UsesProxy() {
+		if err := ReloadProxy(); err != nil {
+			fmt.Printf("Warning: failed to reload proxy: %v\n", err)
+		}
+	}
+	return nil
+}
+
+// planNameMigrations returns the renames that give each workspace the name
+// from desired. Workspaces desired returns false for keep their names, and
+// no workspace takes another's current name. When several want the same
+// name, the first by path gets it and the others get
+// worktree.CollisionName.
+func planNameMigrations(workspaces []*registry.Workspace, desired func(*registry.Workspace) (string, bool)) []nameMigration {
+	sorted := append([]*registry.Workspace{}, workspaces...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Path < sorted[j].Path })
+
+	taken := make(map[string]bool)
+	for _, ws := range sorted {
+		taken[ws.Name] = true
+	}
+
+	var migrations []nameMigration
+	for _, ws := range sorted {
+		if ws.Server != nil && ws.Server.Ephemeral {
+			continue
+		}
+		name, ok := desired(ws)
+		if !ok || name == ws.Name {
+			continue
+		}
+		if taken[name] {
+			name = worktree.CollisionName(name, ws.Path)
+		}
+		if taken[name] {
+			continue
+		}
+		taken[name] = true
+		migrations = append(migrations, nameMigration{From: ws.Name, To: name, Path: ws.Path})
+	}
+	return migrations
+}
+
+// applyNameMigrations moves the renamed worktrees' files and renames their
+// registry entries. Files whose new path is taken are left in place.
+func applyNameMigrations(reg *registry.Registry, migrations []nameMigration, logDir, crashesDir string) error {
+	accessDir := filepath.Join(logDir, "access")
+	for _, m := range migrations {
+		for _, paths := range [][2]string{
+			{filepath.Join(logDir, m.From+".log"), filepath.Join(logDir, m.To+".log")},
+			{filepath.Join(logDir, m.From+"-share.log"), filepath.Join(logDir, m.To+"-share.log")},
+			{accesslog.Path(accessDir, m.From), accesslog.Path(accessDir, m.To)},
+			{filepath.Join(crashesDir, m.From), filepath.Join(crashesDir, m.To)},
+		} {
+			if !fileExists(paths[0]) {
+				continue
+			}
+			if fileExists(paths[1]) {
+				fmt.Printf("Warning: not moving %s: %s exists\n", paths[0], paths[1])
+				continue
+			}
+			if err := os.Rename(paths[0], paths[1]); err != nil {
+				return fmt.Errorf("failed to move %s: %w", paths[0], err)
+			}
+		}
+
+		ws, ok := reg.GetWorkspace(m.From)
+		if !ok {
+			continue
+		}
+		reg.RemoveWorkspaceWithoutSave(m.From)
+		reg.SetWorkspaceWithoutSave(renameWorkspace(ws, m.To, logDir))
+	}
+
+	if err := reg.Save(); err != nil {
+		return fmt.Errorf("failed to save registry: %w", err)
+	}
+	return nil
+}
+
+// renameWorkspace returns a copy of ws named name, with its URL and log
+// paths following the name
+func renameWorkspace(ws *registry.Workspace, name, logDir string) *registry.Workspace {
+	renamed := *ws
+	from := ws.Name
+	renamed.Name = name
+
+	if ws.Server != nil {
+		server := *ws.Server
+		if server.Port > 0 {
+			server.URL = cfg.ServerURL(name, server.Port)
+		}
+		if server.LogFile == filepath.Join(logDir, from+".log") {
+			server.LogFile = filepath.Join(logDir, name+".log")
+		}
+		renamed.Server = &server
+	}
+	if ws.Share != nil {
+		share := *ws.Share
+		if share.LogFile == filepath.Join(logDir, from+"-share.log") {
+			share.LogFile = filepath.Join(logDir, name+"-share.log")
+		}
+		renamed.Share = &share
+	}
+	return &renamed
+}
@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/worktree"
+)
+
+func TestUniqueWorktreeName(t *testing.T) {
+	existing := t.TempDir()
+	workspaces := []*registry.Workspace{
+		{Name: "feature-x", Path: existing, Repo: "github.com/org/api"},
+		{Name: "stale", Path: filepath.Join(t.TempDir(), "deleted")},
+		{Name: "legacy", Path: t.TempDir()},
+	}
+	repo := func(id string) func() string { return func() string { return id } }
+
+	if got := uniqueWorktreeName(workspaces, "feature-x", existing, repo("github.com/org/api")); got != "feature-x" {
+		t.Errorf("registered worktree: got %q, want feature-x", got)
+	}
+	other := "/code/api-feature_x"
+	if got := uniqueWorktreeName(workspaces, "feature-x", other, repo("github.com/org/api")); got != worktree.CollisionName("feature-x", other) {
+		t.Errorf("colliding worktree of the same repo: got %q, want the suffixed name", got)
+	}
+	if got := uniqueWorktreeName(workspaces, "feature-x", "/code/web-feature-x", repo("github.com/org/web")); got != "web-feature-x" {
+		t.Errorf("colliding worktree of another repo: got %q, want web-feature-x", got)
+	}
+	if got := uniqueWorktreeName(workspaces, "legacy", other, repo("github.com/org/web")); got != worktree.CollisionName("legacy", other) {
+		t.Errorf("colliding with an entry without a repo: got %q, want the suffixed name", got)
+	}
+	if got := uniqueWorktreeName(workspaces, "stale", other, repo("")); got != "stale" {
+		t.Errorf("name of a deleted worktree: got %q, want stale", got)
+	}
+}
+
+func TestUniqueWorktreeName_OlderSpelling(t *testing.T) {
+	release, spaced := t.TempDir(), t.TempDir()
+	workspaces := []*registry.Workspace{
+		{Name: "release-v100", Path: release, Branch: "release/v1.0.0"},
+		{Name: "feature-test-space", Path: spaced, Branch: "feature/test space"},
+	}
+	repo := func() string { return "" }
+
+	// Registered before names were sanitized as they are now
+	if got := uniqueWorktreeName(workspaces, "release-v1-0-0", release, repo); got != "release-v100" {
+		t.Errorf("got %q, want the registered release-v100", got)
+	}
+	if got := uniqueWorktreeName(workspaces, "feature-testspace", spaced, repo); got != "feature-test-space" {
+		t.Errorf("got %q, want the registered feature-test-space", got)
+	}
+	// A branch switched since is a different name
+	if got := uniqueWorktreeName(workspaces, "release-v2-0-0", release, repo); got != "release-v2-0-0" {
+		t.Errorf("got %q, want release-v2-0-0", got)
+	}
+	// Only the worktree registered under the old spelling keeps it
+	if got := uniqueWorktreeName(workspaces, "release-v1-0-0", t.TempDir(), repo); got != "release-v1-0-0" {
+		t.Errorf("another worktree got %q, want release-v1-0-0", got)
+	}
+}
+
+func TestRegisteredWorkspaces(t *testing.T) {
+	useTestEnv(t)
+	reg, err := registry.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.SetWorkspace(&registry.Workspace{Name: "api", Path: t.TempDir()}); err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := registeredWorkspaces()
+	if err != nil || len(first) != 1 {
+		t.Fatalf("registeredWorkspaces() = %v, %v", first, err)
+	}
+	// An unchanged registry isn't loaded again
+	if again, _ := registeredWorkspaces(); &again[0] != &first[0] {
+		t.Error("registeredWorkspaces() reloaded an unchanged registry")
+	}
+
+	if err := reg.SetWorkspace(&registry.Workspace{Name: "web", Path: t.TempDir()}); err != nil {
+		t.Fatal(err)
+	}
+	if workspaces, _ := registeredWorkspaces(); len(workspaces) != 2 {
+		t.Errorf("registeredWorkspaces() after a change = %d workspaces, want 2", len(workspaces))
+	}
+}
+
+func TestPlanNameMigrations(t *testing.T) {
+	workspaces := []*registry.Workspace{
+		{Name: "feature-x", Path: "/code/api-feature-x"},
+		{Name: "feature-x-1a2b", Path: "/code/web-feature-x"},
+		{Name: "web-login", Path: "/code/web-login"},
+		{Name: "api-login", Path: "/code/api-login"},
+		{Name: "web", Path: "/code/web"},
+		{Name: "gone", Path: "/code/gone"},
+		{Name: "feature-x-run", Path: "/code/api-feature-x", Server: &registry.ServerState{Ephemeral: true}},
+	}
+	desired := map[string]string{
+		"feature-x":      "api-feature-x",
+		"feature-x-1a2b": "web-feature-x",
+		"web-login":      "login",
+		"api-login":      "login",
+		"web":            "api-login", // Another worktree's current name
+	}
+
+	migrations := planNameMigrations(workspaces, func(ws *registry.Workspace) (string, bool) {
+		name, ok := desired[ws.Name]
+		return name, ok
+	})
+
+	got := make(map[string]string)
+	for _, m := range migrations {
+		got[m.From] = m.To
+	}
+	want := map[string]string{
+		"feature-x":      "api-feature-x",
+		"feature-x-1a2b": "web-feature-x",
+		"api-login":      "login",
+		"web-login":      worktree.CollisionName("login", "/code/web-login"),
+		"web":            worktree.CollisionName("api-login", "/code/web"),
+	}
+	if len(got) != len(want) {
+		t.Fatalf("migrations = %v, want %v", got, want)
+	}
+	for from, to := range want {
+		if got[from] != to {
+			t.Errorf("%s → %q, want %q", from, got[from], to)
+		}
+	}
+}
+
+func TestRenameWorkspace(t *testing.T) {
+	origCfg := cfg
+	defer func() { cfg = origCfg }()
+	cfg = config.Default()
+
+	logDir := t.TempDir()
+	ws := &registry.Workspace{
+		Name: "feature-x",
+		Path: "/code/api-feature-x",
+		Server: &registry.ServerState{
+			Port:    3100,
+			URL:     cfg.ServerURL("feature-x", 3100),
+			LogFile: filepath.Join(logDir, "feature-x.log"),
+		},
+	}
+
+	renamed := renameWorkspace(ws, "api-feature-x", logDir)
+	if renamed.Name != "api-feature-x" || ws.Name != "feature-x" {
+		t.Errorf("renameWorkspace should rename a copy, got %q (original %q)", renamed.Name, ws.Name)
+	}
+	if renamed.Server.LogFile != filepath.Join(logDir, "api-feature-x.log") {
+		t.Errorf("LogFile = %q", renamed.Server.LogFile)
+	}
+	if renamed.Server.URL != cfg.ServerURL("api-feature-x", 3100) {
+		t.Errorf("URL = %q", renamed.Server.URL)
+	}
+}
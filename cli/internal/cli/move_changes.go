@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/iheanyi/grove/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var moveChangesCmd = &cobra.Command{
+	Use:     "move-changes <from> <to>",
+	Aliases: []string{"stash-sync"},
+	Short:   "Move uncommitted changes from one worktree to another",
+	Long: `Move the uncommitted changes of one worktree to another, for when work
+landed in the wrong worktree.
+
+Staged and unstaged edits, deletions and untracked files are applied to the
+destination's files. If any file doesn't apply cleanly, nothing is changed
+and the conflicting files are listed; use --3way to merge them and leave
+conflict markers instead.
+
+Once applied, the changes are stashed in the source worktree, so they can be
+recovered from there with 'git stash pop'. Use --copy to leave the source
+untouched.
+
+Examples:
+  grove move-changes feature-a feature-b
+  grove move-changes feature-a feature-b --dry-run
+  grove move-changes feature-a feature-b --copy
+  grove move-changes feature-a feature-b --3way`,
+	Args: cobra.ExactArgs(2),
+	RunE: runMoveChanges,
+}
+
+func init() {
+	moveChangesCmd.Flags().Bool("copy", false, "Copy the changes, leaving the source worktree untouched")
+	moveChangesCmd.Flags().Bool("dry-run", false, "Show the files that would be moved")
+	moveChangesCmd.Flags().Bool("3way", false, "Merge files that don't apply cleanly, leaving conflict markers")
+}
+
+func runMoveChanges(cmd *cobra.Command, args []string) error {
+	from, to := args[0], args[1]
+	copyOnly, _ := cmd.Flags().GetBool("copy")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	threeWay, _ := cmd.Flags().GetBool("3way")
+
+	src, err := resolveWorktreePath(from)
+	if err != nil {
+		return err
+	}
+	dst, err := resolveWorktreePath(to)
+	if err != nil {
+		return err
+	}
+	if src == dst {
+		return fmt.Errorf("'%s' and '%s' are the same worktree", from, to)
+	}
+
+	patch, err := worktree.Changes(src)
+	if err != nil {
+		return fmt.Errorf("failed to read changes in '%s': %w", from, err)
+	}
+	files := worktree.ChangedFiles(patch)
+	if len(files) == 0 {
+		fmt.Printf("No uncommitted changes in '%s'\n", from)
+		return nil
+	}
+
+	verb := "Moving"
+	if copyOnly {
+		verb = "Copying"
+	}
+	fmt.Printf("%s %d changed file(s) from %s to %s:\n", verb, len(files), from, to)
+	for _, f := range files {
+		fmt.Printf("  %s\n", f)
+	}
+	if dryRun {
+		return nil
+	}
+
+	if !threeWay {
+		failed, err := worktree.CheckPatch(dst, patch)
+		if err != nil {
+			return fmt.Errorf("failed to apply changes to '%s': %w", to, err)
+		}
+		if len(failed) > 0 {
+			fmt.Printf("\nThese files conflict with '%s':\n", to)
+			for _, f := range failed {
+				fmt.Printf("  %s\n", f)
+			}
+			return fmt.Errorf("nothing was changed (retry with --3way to merge with conflict markers)")
+		}
+	}
+
+	conflicts, err := worktree.ApplyPatch(dst, patch, threeWay)
+	if err != nil {
+		return fmt.Errorf("failed to apply changes to '%s': %w", to, err)
+	}
+
+	if !copyOnly {
+		out, err := exec.Command("git", "-C", src, "stash", "push", "--include-untracked", "-m", "grove move-changes to "+to).CombinedOutput()
+		if err != nil {
+			fmt.Printf("Warning: changes were applied to '%s' but not removed from '%s': %s\n", to, from, strings.TrimSpace(string(out)))
+		} else {
+			fmt.Printf("\nStashed the changes in '%s' (recover with 'git stash pop' there)\n", from)
+		}
+	}
+
+	if len(conflicts) > 0 {
+		fmt.Printf("\nResolve conflicts in '%s':\n", to)
+		for _, f := range conflicts {
+			fmt.Printf("  %s\n", f)
+		}
+		return nil
+	}
+
+	if copyOnly {
+		fmt.Printf("\nCopied changes to '%s'\n", to)
+	} else {
+		fmt.Printf("Moved changes to '%s'\n", to)
+	}
+	return nil
+}
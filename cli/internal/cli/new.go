@@ -2,12 +2,19 @@ package cli
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"time"
 
+	"github.com/iheanyi/grove/internal/discovery"
+	"github.com/iheanyi/grove/internal/git"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/tasks"
 	"github.com/iheanyi/grove/internal/worktree"
 	"github.com/spf13/cobra"
 )
@@ -25,8 +32,25 @@ in a centralized location: <worktrees_dir>/<project>/<branch>
 
 If base-branch is not specified, it defaults to 'main' or 'master' (auto-detected).
 
+With --pr, the pull request's head is fetched (pull/<n>/head, or
+merge-requests/<n>/head for GitLab, so PRs from forks work too) and checked
+out on a new branch named pr-<n>-<title>, with the title looked up via gh.
+The worktree is registered right away, ready for 'grove start'.
+
+With --task, the task is looked up in the worktree's task tracker (see
+'grove tasks'), the branch is named after its ID and title, the task is
+marked in progress and its ID is recorded on the worktree. --agent then
+runs the configured agent (tmux.agent) in the new worktree, like 'grove
+agent launch'.
+
 When a directory conflict occurs, you'll be prompted with options to resolve it.
 
+If .grove.yaml configures a database, a database for the worktree is cloned
+from its template and injected as DATABASE_URL when the server starts. If
+it configures a cache, dependency directories such as node_modules are
+hardlinked from the main worktree and the install command runs to settle
+the differences; --no-cache skips this.
+
 Examples:
   grove new feature-auth              # Create worktree from main/master
   grove new feature-auth develop      # Create worktree from develop branch
@@ -36,7 +60,12 @@ Examples:
   grove new feature-auth --track      # Force tracking existing remote branch
   grove new feature-auth --no-track   # Force creating new branch (ignore remote)
   grove new --pick                    # Pick from available remote branches
-  grove new --pick --filter feat      # Pick from remote branches matching 'feat'`,
+  grove new --pick --filter feat      # Pick from remote branches matching 'feat'
+  grove new --pr 123                  # Check out PR #123 as pr-123-<title>
+  grove new --pr https://github.com/org/repo/pull/123
+  grove new --task TK-42              # Branch tk-42-<title> for a task
+  grove new --task TK-42 --agent      # ...and launch the agent in it
+  grove new feature-auth --no-cache   # Install dependencies yourself`,
 	Args: cobra.RangeArgs(0, 2),
 	RunE: runNew,
 }
@@ -48,6 +77,10 @@ func init() {
 	newCmd.Flags().Bool("no-track", false, "Force creating new branch even if remote exists")
 	newCmd.Flags().Bool("pick", false, "Interactively pick from remote branches")
 	newCmd.Flags().String("filter", "", "Filter remote branches by pattern (used with --pick)")
+	newCmd.Flags().String("pr", "", "Check out a pull request by number or URL")
+	newCmd.Flags().String("task", "", "Create the worktree for a task by ID")
+	newCmd.Flags().Bool("agent", false, "Launch the configured agent in the new worktree (with --task)")
+	newCmd.Flags().Bool("no-cache", false, "Don't seed dependencies from the main worktree")
 }
 
 func runNew(cmd *cobra.Command, args []string) error {
@@ -56,11 +89,69 @@ func runNew(cmd *cobra.Command, args []string) error {
 	filterPattern, _ := cmd.Flags().GetString("filter")
 	forceTrack, _ := cmd.Flags().GetBool("track")
 	forceNoTrack, _ := cmd.Flags().GetBool("no-track")
+	prRef, _ := cmd.Flags().GetString("pr")
+	taskID, _ := cmd.Flags().GetString("task")
+	withAgent, _ := cmd.Flags().GetBool("agent")
+	noCache, _ := cmd.Flags().GetBool("no-cache")
+
+	if withAgent && taskID == "" {
+		return fmt.Errorf("--agent requires --task")
+	}
 
 	var branchName string
+	var task *tasks.Task
+	var taskPath string
+
+	// Handle --pr mode
+	if prRef != "" {
+		if len(args) > 0 || pickMode || taskID != "" {
+			return fmt.Errorf("cannot specify a branch name, --pick or --task with --pr")
+		}
+		if forceTrack {
+			return fmt.Errorf("cannot use --track with --pr")
+		}
+
+		wt, err := worktree.Detect()
+		if err != nil {
+			return fmt.Errorf("failed to detect git repository: %w", err)
+		}
+		mainRepoPath := wt.Path
+		if wt.IsWorktree && wt.MainWorktreePath != "" {
+			mainRepoPath = wt.MainWorktreePath
+		}
+
+		branchName, err = resolvePR(mainRepoPath, prRef)
+		if err != nil {
+			return err
+		}
+
+		// Branch from the fetched head rather than the default branch
+		args = []string{branchName, "FETCH_HEAD"}
+		forceNoTrack = true
+	} else if taskID != "" {
+		if len(args) > 1 || pickMode {
+			return fmt.Errorf("cannot specify a branch name or --pick with --task")
+		}
+
+		wt, err := worktree.Detect()
+		if err != nil {
+			return fmt.Errorf("failed to detect git repository: %w", err)
+		}
+		paths := []string{wt.Path}
+		if wt.IsWorktree && wt.MainWorktreePath != "" {
+			paths = append(paths, wt.MainWorktreePath)
+		}
+
+		task, taskPath, err = findTask(paths, taskID)
+		if err != nil {
+			return err
+		}
+		branchName = taskBranchName(task)
+		fmt.Printf("Task %s\n", task)
 
-	// Handle --pick mode
-	if pickMode {
+		// An argument is the base branch
+		args = append([]string{branchName}, args...)
+	} else if pickMode {
 		if len(args) > 0 {
 			return fmt.Errorf("cannot specify branch name with --pick flag")
 		}
@@ -217,26 +308,42 @@ func runNew(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create the worktree
-	var gitCmd *exec.Cmd
+	var addOpts git.AddOptions
 	if trackRemote {
 		// Track existing remote branch
 		fmt.Printf("Creating worktree tracking 'origin/%s'...\n", branchName)
 		fmt.Printf("Location: %s\n", worktreePath)
-		gitCmd = exec.Command("git", "worktree", "add", worktreePath, "origin/"+branchName)
+		addOpts.Commit = "origin/" + branchName
 	} else {
 		// Create new branch from base
 		fmt.Printf("Creating worktree '%s' from '%s'...\n", branchName, baseBranch)
 		fmt.Printf("Location: %s\n", worktreePath)
-		gitCmd = exec.Command("git", "worktree", "add", "-b", branchName, worktreePath, baseBranch)
+		addOpts = git.AddOptions{NewBranch: branchName, Commit: baseBranch}
 	}
-	gitCmd.Dir = mainRepoPath
-	gitCmd.Stdout = os.Stdout
-	gitCmd.Stderr = os.Stderr
-
-	if err := gitCmd.Run(); err != nil {
+	if err := gitOps().AddWorktree(cmd.Context(), mainRepoPath, worktreePath, addOpts); err != nil {
 		return fmt.Errorf("failed to create worktree: %w", err)
 	}
 
+	provisionDatabase(worktreePath, mainRepoPath, branchName)
+	if !noCache {
+		provisionCache(worktreePath, mainRepoPath)
+	}
+
+	if prRef != "" || task != nil {
+		var id string
+		if task != nil {
+			id = task.ID
+			startTask(task, taskPath)
+		}
+		if name := registerNewWorktree(worktreePath, id); name != "" {
+			worktreeName = name
+		}
+	}
+
+	if err := runGlobalHooks(hookPostCreate, hookTarget{Name: worktreeName, Path: worktreePath, Branch: branchName}, os.Stdout); err != nil {
+		fmt.Printf("Warning: post-create hook failed: %v\n", err)
+	}
+
 	fmt.Printf("\nWorktree created successfully!\n")
 	fmt.Printf("Branch: %s\n", branchName)
 	if trackRemote {
@@ -247,9 +354,68 @@ func runNew(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  cd %s\n", worktreePath)
 	fmt.Printf("  # or use: grove switch %s\n", worktreeName)
 
+	if withAgent {
+		return launchTaskAgent(worktreeName, task)
+	}
 	return nil
 }
 
+// titleSeparators are the runs of characters a title's words are split on
+var titleSeparators = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// maxTitleSlugLen caps the part of a branch name made from a PR or task
+// title
+const maxTitleSlugLen = 40
+
+// titleSlug turns a PR or task title into a branch name part, cut at a word
+// boundary
+func titleSlug(title string) string {
+	slug := worktree.Sanitize(titleSeparators.ReplaceAllString(title, "-"))
+	if len(slug) > maxTitleSlugLen {
+		slug = slug[:maxTitleSlugLen]
+		if i := strings.LastIndex(slug, "-"); i > 0 {
+			slug = slug[:i]
+		}
+	}
+	return strings.Trim(slug, "-")
+}
+
+// registerNewWorktree adds a just-created worktree to the registry, so it's
+// listed before anything has run in it, recording the task it's for (if
+// any). It returns the worktree's name.
+func registerNewWorktree(path, taskID string) string {
+	wt, err := worktree.DetectAt(path)
+	if err != nil {
+		return ""
+	}
+	reg, err := registry.Load()
+	if err != nil {
+		fmt.Printf("Warning: failed to register worktree: %v\n", err)
+		return wt.Name
+	}
+
+	now := time.Now()
+	if err := reg.SetWorktree(&discovery.Worktree{
+		Name:         wt.Name,
+		Path:         wt.Path,
+		Branch:       wt.Branch,
+		MainRepo:     wt.MainWorktreePath,
+		Repo:         wt.Repo,
+		DiscoveredAt: now,
+		LastActivity: now,
+	}); err != nil {
+		fmt.Printf("Warning: failed to register worktree: %v\n", err)
+		return wt.Name
+	}
+	if ws, ok := reg.GetWorkspace(wt.Name); ok && taskID != "" {
+		ws.Task = taskID
+		if err := reg.SetWorkspace(ws); err != nil {
+			fmt.Printf("Warning: failed to record task: %v\n", err)
+		}
+	}
+	return wt.Name
+}
+
 // detectDefaultBranch attempts to detect the default branch (main or master)
 func detectDefaultBranch(repoPath string) (string, error) {
 	// Try to get the default branch from remote
@@ -257,11 +423,11 @@ func detectDefaultBranch(repoPath string) (string, error) {
 	cmd.Dir = repoPath
 	output, err := cmd.Output()
 	if err == nil {
-		// Output format: refs/remotes/origin/main
+		// Output format: refs/remotes/origin/main. Default branches can
+		// have slashes too (e.g. release/v2)
 		ref := strings.TrimSpace(string(output))
-		parts := strings.Split(ref, "/")
-		if len(parts) > 0 {
-			return parts[len(parts)-1], nil
+		if branch := strings.TrimPrefix(ref, "refs/remotes/origin/"); branch != "" && branch != ref {
+			return branch, nil
 		}
 	}
 
@@ -383,23 +549,13 @@ func listRemoteBranches(repoPath, filter string) ([]string, error) {
 func getExistingWorktreeBranches(repoPath string) map[string]bool {
 	result := make(map[string]bool)
 
-	cmd := exec.Command("git", "worktree", "list", "--porcelain")
-	cmd.Dir = repoPath
-	output, err := cmd.Output()
+	worktrees, err := gitOps().Worktrees(context.Background(), repoPath)
 	if err != nil {
 		return result
 	}
-
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
-		if strings.HasPrefix(line, "branch ") {
-			branchRef := strings.TrimPrefix(line, "branch ")
-			// Extract branch name from refs/heads/branch-name
-			parts := strings.Split(branchRef, "/")
-			if len(parts) >= 3 {
-				branchName := strings.Join(parts[2:], "/")
-				result[branchName] = true
-			}
+	for _, wt := range worktrees {
+		if wt.Branch != "" {
+			result[wt.Branch] = true
 		}
 	}
 
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/iheanyi/grove/internal/github"
+)
+
+// prURLPattern matches the number in a GitHub pull request or GitLab merge
+// request URL
+var prURLPattern = regexp.MustCompile(`/(?:pull|merge_requests)/(\d+)`)
+
+// parsePRRef returns the number of a pull request given as 123, #123 or its
+// URL
+func parsePRRef(ref string) (int, error) {
+	ref = strings.TrimPrefix(strings.TrimSpace(ref), "#")
+	if m := prURLPattern.FindStringSubmatch(ref); m != nil {
+		ref = m[1]
+	}
+	number, err := strconv.Atoi(ref)
+	if err != nil || number < 1 {
+		return 0, fmt.Errorf("invalid pull request %q (expected a number or URL)", ref)
+	}
+	return number, nil
+}
+
+// prBranchName returns the local branch a pull request is checked out on:
+// pr-<number>-<slug of its title>
+func prBranchName(number int, title string) string {
+	name := fmt.Sprintf("pr-%d", number)
+	if title == "" {
+		return name
+	}
+
+	return name + "-" + titleSlug(title)
+}
+
+// fetchPR fetches a pull request's head into FETCH_HEAD. GitHub and GitLab
+// both publish it under a ref of the base repository, so pull requests from
+// forks work without adding a remote.
+func fetchPR(repoPath string, number int) error {
+	ref := fmt.Sprintf("pull/%d/head", number)
+	if github.IsGitLabRemote(repoPath) {
+		ref = fmt.Sprintf("merge-requests/%d/head", number)
+	}
+
+	cmd := exec.Command("git", "fetch", "origin", ref)
+	cmd.Dir = repoPath
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to fetch %s: %w\n%s", ref, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// resolvePR fetches the pull request given to 'grove new --pr' and returns
+// the branch to create for it. Its title comes from gh when available.
+func resolvePR(repoPath, ref string) (string, error) {
+	number, err := parsePRRef(ref)
+	if err != nil {
+		return "", err
+	}
+
+	var title string
+	if !github.IsGitLabRemote(repoPath) {
+		pr, err := github.GetPullRequest(repoPath, number)
+		if err != nil {
+			fmt.Printf("Warning: could not look up PR #%d: %v\n", number, err)
+		} else {
+			title = pr.Title
+			fmt.Printf("PR #%d: %s (%s)\n", pr.Number, pr.Title, pr.HeadRefName)
+		}
+	}
+
+	fmt.Printf("Fetching PR #%d...\n", number)
+	if err := fetchPR(repoPath, number); err != nil {
+		return "", err
+	}
+
+	branch := prBranchName(number, title)
+	if verifyRefExists(repoPath, "refs/heads/"+branch) == nil {
+		return "", fmt.Errorf("branch '%s' already exists\nUse 'grove switch %s', or delete the branch to check out the PR again", branch, branch)
+	}
+	return branch, nil
+}
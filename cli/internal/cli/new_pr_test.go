@@ -0,0 +1,48 @@
+package cli
+
+import "testing"
+
+func TestParsePRRef(t *testing.T) {
+	tests := map[string]int{
+		"123":                                  123,
+		"#42":                                  42,
+		" 7 ":                                  7,
+		"https://github.com/org/repo/pull/123": 123,
+		"https://github.com/org/repo/pull/123/files":           123,
+		"https://gitlab.com/group/repo/-/merge_requests/9":     9,
+		"https://gitlab.com/group/repo/-/merge_requests/9#top": 9,
+	}
+	for ref, want := range tests {
+		got, err := parsePRRef(ref)
+		if err != nil {
+			t.Errorf("parsePRRef(%q) failed: %v", ref, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("parsePRRef(%q) = %d, want %d", ref, got, want)
+		}
+	}
+
+	for _, ref := range []string{"", "abc", "0", "https://github.com/org/repo"} {
+		if _, err := parsePRRef(ref); err == nil {
+			t.Errorf("parsePRRef(%q) should fail", ref)
+		}
+	}
+}
+
+func TestPRBranchName(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"", "pr-123"},
+		{"Fix login redirect", "pr-123-fix-login-redirect"},
+		{"feat(auth): Add OAuth_2 support!", "pr-123-feat-auth-add-oauth-2-support"},
+		{"Refactor the session store to use the new cache layer everywhere", "pr-123-refactor-the-session-store-to-use-the"},
+	}
+	for _, tt := range tests {
+		if got := prBranchName(123, tt.title); got != tt.want {
+			t.Errorf("prBranchName(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
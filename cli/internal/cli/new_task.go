@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/iheanyi/grove/internal/tasks"
+	"github.com/iheanyi/grove/internal/worktree"
+)
+
+// taskBranchName returns the branch a task is worked on: its ID and a slug
+// of its title. TODO.md items are named by their title alone, since their ID
+// is a line number.
+func taskBranchName(task *tasks.Task) string {
+	if task.Source == "todo" {
+		return titleSlug(task.Title)
+	}
+
+	name := worktree.Sanitize(task.ID)
+	if task.Title == "" || task.Title == task.ID {
+		return name
+	}
+	return name + "-" + titleSlug(task.Title)
+}
+
+// findTask looks a task up in the current worktree, then in the main
+// repository. It returns the task and the path it was found at.
+func findTask(paths []string, id string) (*tasks.Task, string, error) {
+	for _, path := range paths {
+		if task := tasks.Find(path, id); task != nil {
+			return task, path, nil
+		}
+	}
+	return nil, "", fmt.Errorf("task '%s' not found (see 'grove tasks --all')", id)
+}
+
+// startTask marks a task in progress in its tracker
+func startTask(task *tasks.Task, path string) {
+	if task.Status == tasks.StatusInProgress {
+		return
+	}
+	if err := tasks.SetStatus(path, task, tasks.StatusInProgress); err != nil {
+		fmt.Printf("Warning: failed to mark %s in progress: %v\n", task.ID, err)
+		return
+	}
+	fmt.Printf("Marked %s in progress\n", task.ID)
+}
+
+// launchTaskAgent runs the configured agent (tmux.agent) in a worktree
+// created for a task, as 'grove agent launch' would
+func launchTaskAgent(name string, task *tasks.Task) error {
+	launch, err := newAgentLaunch(name)
+	if err != nil {
+		return err
+	}
+	launch.Task = task
+	fmt.Println()
+	return launch.Run(cfg.Tmux.Agent, launch.Prompt())
+}
@@ -0,0 +1,23 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/iheanyi/grove/internal/tasks"
+)
+
+func TestTaskBranchName(t *testing.T) {
+	tests := []struct {
+		task tasks.Task
+		want string
+	}{
+		{tasks.Task{ID: "TK-42", Title: "Add OAuth login", Source: "tasuku"}, "tk-42-add-oauth-login"},
+		{tasks.Task{ID: "bd-7", Source: "beads"}, "bd-7"},
+		{tasks.Task{ID: "TODO.md:5", Title: "Refactor parser", Source: "todo"}, "refactor-parser"},
+	}
+	for _, tt := range tests {
+		if got := taskBranchName(&tt.task); got != tt.want {
+			t.Errorf("taskBranchName(%s) = %q, want %q", tt.task.ID, got, tt.want)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/iheanyi/grove/internal/registry"
 	"github.com/iheanyi/grove/internal/worktree"
@@ -10,28 +11,53 @@ import (
 )
 
 var openCmd = &cobra.Command{
-	Use:   "open [name]",
+	Use:   "open [name] [path]",
 	Short: "Open a server in the browser",
 	Long: `Open the current worktree's server or a named server in the default browser.
 
+An optional path is appended to the server URL. A single argument starting
+with '/' is treated as a path on the current worktree's server. Use
+--subdomain to open a subdomain of the server (e.g., a tenant).
+
 Examples:
-  grove open              # Open current worktree's server
-  grove open feature-auth # Open named server`,
+  grove open                       # Open current worktree's server
+  grove open feature-auth          # Open named server
+  grove open feature-auth /admin   # Open a path on a named server
+  grove open /admin                # Open a path on the current server
+  grove open --subdomain tenant1   # Open https://tenant1.<name>.<tld>
+  grove open --print /api/health   # Print the URL instead of opening it`,
 	RunE: runOpen,
+	Args: cobra.MaximumNArgs(2),
+}
+
+func init() {
+	openCmd.Flags().StringP("subdomain", "s", "", "Open a subdomain of the server")
+	openCmd.Flags().BoolP("print", "p", false, "Print the URL instead of opening the browser")
 }
 
 func runOpen(cmd *cobra.Command, args []string) error {
+	subdomain, _ := cmd.Flags().GetString("subdomain")
+	printOnly, _ := cmd.Flags().GetBool("print")
+
 	// Load registry
 	reg, err := registry.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load registry: %w", err)
 	}
 
-	// Determine which server
-	var name string
-	if len(args) > 0 {
+	// Split arguments into server name and path
+	var name, path string
+	switch {
+	case len(args) == 2:
+		name, path = args[0], args[1]
+	case len(args) == 1 && strings.HasPrefix(args[0], "/"):
+		path = args[0]
+	case len(args) == 1:
 		name = args[0]
-	} else {
+	}
+
+	// Determine which server
+	if name == "" {
 		// Use current worktree
 		wt, err := worktree.Detect()
 		if err != nil {
@@ -49,6 +75,30 @@ func runOpen(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("server '%s' is not running\nUse 'grove start' to start it", name)
 	}
 
-	fmt.Printf("Opening %s...\n", server.URL)
-	return browser.Open(server.URL)
+	url := buildOpenURL(server, subdomain, path)
+
+	if printOnly {
+		fmt.Println(url)
+		return nil
+	}
+
+	fmt.Printf("Opening %s...\n", url)
+	return browser.Open(url)
+}
+
+// buildOpenURL composes the URL for a server, optional subdomain and path
+// according to the configured URL mode
+func buildOpenURL(server *registry.Server, subdomain, path string) string {
+	url := server.URL
+	if subdomain != "" {
+		url = cfg.SubdomainServerURL(server.Name, server.Port, strings.Trim(subdomain, "."))
+	}
+
+	if path == "" {
+		return url
+	}
+	if !strings.HasPrefix(path, "/") && !strings.HasPrefix(path, "?") && !strings.HasPrefix(path, "#") {
+		path = "/" + path
+	}
+	return strings.TrimSuffix(url, "/") + path
 }
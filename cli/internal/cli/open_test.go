@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+func TestBuildOpenURL(t *testing.T) {
+	origCfg := cfg
+	defer func() { cfg = origCfg }()
+
+	tests := []struct {
+		name      string
+		mode      config.URLMode
+		subdomain string
+		path      string
+		expected  string
+	}{
+		{"port mode", config.URLModePort, "", "", "http://localhost:3042"},
+		{"port mode with path", config.URLModePort, "", "/admin", "http://localhost:3042/admin"},
+		{"path without slash", config.URLModePort, "", "admin?tab=1", "http://localhost:3042/admin?tab=1"},
+		{"port mode subdomain", config.URLModePort, "tenant1", "/admin", "http://tenant1.localhost:3042/admin"},
+		{"subdomain mode", config.URLModeSubdomain, "", "/admin", "https://feature-auth.localhost/admin"},
+		{"subdomain mode subdomain", config.URLModeSubdomain, "tenant1", "", "https://tenant1.feature-auth.localhost"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg = config.Default()
+			cfg.URLMode = tt.mode
+			server := &registry.Server{
+				Name: "feature-auth",
+				Port: 3042,
+				URL:  cfg.ServerURL("feature-auth", 3042),
+			}
+
+			if got := buildOpenURL(server, tt.subdomain, tt.path); got != tt.expected {
+				t.Errorf("buildOpenURL() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"github.com/iheanyi/grove/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// addOutputFlags adds --json and --format to a command with a typed
+// response in the output package
+func addOutputFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("json", false, "Output as JSON (same as --format json)")
+	cmd.Flags().String("format", "table", "Output format: table, json or yaml")
+}
+
+// outputFormat returns the format selected by --json/--format
+func outputFormat(cmd *cobra.Command) (output.Format, error) {
+	if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+		return output.FormatJSON, nil
+	}
+	format, _ := cmd.Flags().GetString("format")
+	return output.ParseFormat(format)
+}
+
+// runWithOutput runs a command and, in a machine format, writes the result
+// it returns to stdout, even alongside an error for partial failures. The
+// command's own messages go to stderr meanwhile, so stdout holds only the
+// document.
+func runWithOutput(cmd *cobra.Command, run func() (any, error)) error {
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	if !format.IsMachine() {
+		_, err := run()
+		return err
+	}
+
+	stdout, restore := output.Redirect()
+	result, err := run()
+	restore()
+	if result != nil {
+		if writeErr := output.Write(stdout, format, result); err == nil {
+			err = writeErr
+		}
+	}
+	return err
+}
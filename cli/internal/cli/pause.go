@@ -0,0 +1,265 @@
+package cli
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/iheanyi/grove/internal/accesslog"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var pauseCmd = &cobra.Command{
+	Use:   "pause [name]",
+	Short: "Pause a dev server to free up CPU",
+	Long: `Pause a dev server by sending SIGSTOP to its processes.
+
+A paused server keeps its port and memory but uses no CPU. Resume it with
+'grove resume'. In subdomain mode, the proxy resumes a paused server
+automatically on the next request to it.
+
+Set pause_after in config.yaml to have the proxy pause servers that have
+had no traffic for a while:
+
+  pause_after: 15m
+
+Examples:
+  grove pause              # Pause the current worktree's server
+  grove pause feature-auth # Pause a server by name
+  grove pause --all        # Pause every running server`,
+	RunE: runPause,
+}
+
+var resumeCmd = &cobra.Command{
+	Use:   "resume [name]",
+	Short: "Resume a paused dev server",
+	Long: `Resume a paused dev server by sending SIGCONT to its processes.
+
+Examples:
+  grove resume              # Resume the current worktree's server
+  grove resume feature-auth # Resume a server by name
+  grove resume --all        # Resume every paused server`,
+	RunE: runResume,
+}
+
+func init() {
+	pauseCmd.Flags().Bool("all", false, "Pause all running servers")
+	resumeCmd.Flags().Bool("all", false, "Resume all paused servers")
+	pauseCmd.GroupID = "server"
+	resumeCmd.GroupID = "server"
+	rootCmd.AddCommand(pauseCmd)
+	rootCmd.AddCommand(resumeCmd)
+}
+
+func runPause(cmd *cobra.Command, args []string) error {
+	all, _ := cmd.Flags().GetBool("all")
+	return forEachTargetServer(args, all, func(reg *registry.Registry, server *registry.Server) error {
+		if server.IsPaused() {
+			fmt.Printf("Server '%s' is already paused\n", server.Name)
+			return nil
+		}
+		if err := pauseServer(reg, server); err != nil {
+			return err
+		}
+		fmt.Printf("Paused '%s' (PID: %d)\n", server.Name, server.PID)
+		return nil
+	}, func(s *registry.Server) bool { return s.IsRunning() && !s.IsPaused() })
+}
+
+func runResume(cmd *cobra.Command, args []string) error {
+	all, _ := cmd.Flags().GetBool("all")
+	return forEachTargetServer(args, all, func(reg *registry.Registry, server *registry.Server) error {
+		if !server.IsPaused() {
+			fmt.Printf("Server '%s' is not paused\n", server.Name)
+			return nil
+		}
+		if err := resumeServer(reg, server); err != nil {
+			return err
+		}
+		fmt.Printf("Resumed '%s'\n", server.Name)
+		return nil
+	}, (*registry.Server).IsPaused)
+}
+
+// forEachTargetServer runs fn for the named server, the current worktree's
+// server, or (with all) every server matching include
+func forEachTargetServer(args []string, all bool, fn func(*registry.Registry, *registry.Server) error, include func(*registry.Server) bool) error {
+	reg, err := registry.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+
+	if all {
+		matched := 0
+		for _, server := range reg.List() {
+			if !include(server) {
+				continue
+			}
+			matched++
+			if err := fn(reg, server); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %s: %v\n", server.Name, err)
+			}
+		}
+		if matched == 0 {
+			fmt.Println("No matching servers")
+		}
+		return nil
+	}
+
+	var name string
+	if len(args) > 0 {
+		name = args[0]
+	} else {
+		wt, err := worktree.Detect()
+		if err != nil {
+			return fmt.Errorf("failed to detect worktree: %w", err)
+		}
+		name = wt.Name
+	}
+
+	server, ok := reg.Get(name)
+	if !ok {
+		return fmt.Errorf("no server registered for '%s'", name)
+	}
+	if !server.IsRunning() {
+		return fmt.Errorf("server '%s' is not running", name)
+	}
+	return fn(reg, server)
+}
+
+// pauseServer stops the server's processes with SIGSTOP and, in subdomain
+// mode, routes its hosts through the proxy's wake handler
+func pauseServer(reg *registry.Registry, server *registry.Server) error {
+	if err := signalServer(server, syscall.SIGSTOP); err != nil {
+		return fmt.Errorf("failed to pause: %w", err)
+	}
+
+	server.Status = registry.StatusPaused
+	server.PausedAt = time.Now()
+	if err := reg.Set(server); err != nil {
+		return fmt.Errorf("failed to update registry: %w", err)
+	}
+
+	if cfg.UsesProxy() {
+		if err := ReloadProxy(); err != nil {
+			fmt.Printf("Warning: failed to reload proxy: %v\n", err)
+		}
+	}
+	return nil
+}
+
+// resumeServer continues the server's processes with SIGCONT and restores
+// its proxy routes
+func resumeServer(reg *registry.Registry, server *registry.Server) error {
+	if err := signalServer(server, syscall.SIGCONT); err != nil {
+		return fmt.Errorf("failed to resume: %w", err)
+	}
+
+	server.Status = registry.StatusRunning
+	server.ResumedAt = time.Now()
+	if err := reg.Set(server); err != nil {
+		return fmt.Errorf("failed to update registry: %w", err)
+	}
+
+	if cfg.UsesProxy() {
+		if err := ReloadProxy(); err != nil {
+			fmt.Printf("Warning: failed to reload proxy: %v\n", err)
+		}
+	}
+	return nil
+}
+
+// signalServer sends sig to each of the server's process groups. Servers
+// are started in their own process group, so child processes (e.g. a
+// bundler spawned by npm) are signaled too. Multi-process servers signal
+// each process but not grove's supervisor.
+func signalServer(server *registry.Server, sig syscall.Signal) error {
+	if server.IsCompose() {
+		return signalCompose(server, sig)
+	}
+
+	pids := []int{server.PID}
+	if server.IsMultiProcess() {
+		pids = pids[:0]
+		for _, proc := range server.Processes {
+			if proc.PID > 0 {
+				pids = append(pids, proc.PID)
+			}
+		}
+	}
+
+	var lastErr error
+	signaled := 0
+	for _, pid := range pids {
+		if pid <= 0 {
+			continue
+		}
+		process, err := runner.FindProcess(pid)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if err := process.SignalGroup(sig); err != nil {
+			// Not a group leader (e.g. attached servers); signal the process
+			if err := process.Signal(sig); err != nil {
+				lastErr = err
+				continue
+			}
+		}
+		signaled++
+	}
+
+	if signaled == 0 {
+		if lastErr == nil {
+			lastErr = fmt.Errorf("no process to signal")
+		}
+		return lastErr
+	}
+	return nil
+}
+
+// pauseIdleServers periodically pauses servers whose access logs show no
+// requests for longer than idle. It runs in the proxy process.
+func pauseIdleServers(idle time.Duration) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		reg, err := registry.Load()
+		if err != nil {
+			continue
+		}
+
+		for _, server := range reg.ListRunning() {
+			if server.IsPaused() || server.Status == registry.StatusStarting {
+				continue
+			}
+			if time.Since(lastTraffic(server)) < idle {
+				continue
+			}
+
+			if err := pauseServer(reg, server); err != nil {
+				log.Printf("Failed to pause idle server '%s': %v", server.Name, err)
+				continue
+			}
+			log.Printf("Paused '%s' after %s without traffic", server.Name, idle)
+		}
+	}
+}
+
+// lastTraffic returns when the server last saw a proxied request, or when
+// it was started or resumed if that's more recent
+func lastTraffic(server *registry.Server) time.Time {
+	latest := server.StartedAt
+	if server.ResumedAt.After(latest) {
+		latest = server.ResumedAt
+	}
+	if info, err := os.Stat(accesslog.Path(cfg.AccessLogDir(), server.Name)); err == nil && info.ModTime().After(latest) {
+		latest = info.ModTime()
+	}
+	return latest
+}
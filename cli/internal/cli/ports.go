@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/iheanyi/grove/internal/output"
+	"github.com/iheanyi/grove/internal/port"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var portsCmd = &cobra.Command{
+	Use:   "ports",
+	Short: "Show registered worktrees' ports by repository",
+	Long: `Show the port of every registered worktree, grouped by the block of ports
+its repository gets from port_bases in the global config:
+
+  port_bases:
+    myapp: 3100   # myapp's worktrees get 3100, 3101, ...
+    api: 4100
+  port_block_size: 100
+
+A repository's worktrees get the lowest free ports in its block, in the
+order they're first started or registered, and keep them. Repositories
+without a base get a port derived from the worktree name, between port_min
+and port_max. Repositories are named after their origin remote (e.g.
+github.com/org/myapp is "myapp"), or their main worktree's directory.
+
+Ports outside their repository's block are flagged; 'grove start' gives
+them a port in the block.
+
+Examples:
+  grove ports
+  grove ports --json`,
+	Args: cobra.NoArgs,
+	RunE: runPorts,
+}
+
+func init() {
+	addOutputFlags(portsCmd)
+}
+
+func runPorts(cmd *cobra.Command, args []string) error {
+	reg, err := registry.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	result := collectPorts(reg.ListWorkspaces(), func(ws *registry.Workspace) string {
+		if ws.Repo != "" {
+			return worktree.RepoName(ws.Repo)
+		}
+		return repoNameAt(ws.Path)
+	})
+	if format.IsMachine() {
+		return output.Write(os.Stdout, format, result)
+	}
+
+	for i, block := range result.Blocks {
+		if i > 0 {
+			fmt.Println()
+		}
+		title := block.Repo
+		if title == "" {
+			title = "Other repositories"
+		}
+		fmt.Printf("%s (%d-%d)\n", title, block.First, block.Last)
+		if len(block.Worktrees) == 0 {
+			fmt.Println("  No worktrees registered")
+		}
+		for _, wt := range block.Worktrees {
+			note := ""
+			if wt.OutsideBlock {
+				note = "  outside the block, moves on next start"
+			}
+			fmt.Printf("  %-6d %-32s %s%s\n", wt.Port, wt.Name, wt.Status, note)
+		}
+	}
+	return nil
+}
+
+// collectPorts groups the workspaces with a port by their repository's
+// port_bases block, ordered by base, then the rest. repo names a
+// workspace's repository.
+func collectPorts(workspaces []*registry.Workspace, repo func(*registry.Workspace) string) output.PortMap {
+	blocks := make(map[string]*output.PortBlock)
+	for name := range cfg.PortBases {
+		if first, last, ok := cfg.PortBlock(name); ok {
+			blocks[name] = &output.PortBlock{Repo: name, First: first, Last: last, Worktrees: []output.PortAssignment{}}
+		}
+	}
+	other := &output.PortBlock{First: cfg.PortMin, Last: cfg.PortMax, Worktrees: []output.PortAssignment{}}
+
+	for _, ws := range workspaces {
+		if ws.Server == nil || ws.Server.Port == 0 {
+			continue
+		}
+		assignment := output.PortAssignment{Name: ws.Name, Port: ws.Server.Port, Status: string(ws.Server.Status)}
+		block := other
+		if len(blocks) > 0 {
+			if b, ok := blocks[repo(ws)]; ok {
+				block = b
+				assignment.OutsideBlock = ws.Server.Port < b.First || ws.Server.Port > b.Last
+			}
+		}
+		block.Worktrees = append(block.Worktrees, assignment)
+	}
+
+	var result output.PortMap
+	for _, block := range blocks {
+		result.Blocks = append(result.Blocks, *block)
+	}
+	sort.Slice(result.Blocks, func(i, j int) bool { return result.Blocks[i].First < result.Blocks[j].First })
+	if len(other.Worktrees) > 0 || len(result.Blocks) == 0 {
+		result.Blocks = append(result.Blocks, *other)
+	}
+	for _, block := range result.Blocks {
+		sort.Slice(block.Worktrees, func(i, j int) bool { return block.Worktrees[i].Port < block.Worktrees[j].Port })
+	}
+	return result
+}
+
+// repoNameAt returns the name of the repository of the worktree at path,
+// which port_bases is keyed by
+func repoNameAt(path string) string {
+	mainPath := path
+	if info, err := worktree.DetectAt(path); err == nil && info.MainWorktreePath != "" {
+		mainPath = info.MainWorktreePath
+	}
+	return worktree.RepoName(worktree.RepoID(path, mainPath))
+}
+
+// portBlockAt returns the port_bases block of the worktree at path
+func portBlockAt(path string) (first, last int, ok bool) {
+	if len(cfg.PortBases) == 0 {
+		return 0, 0, false
+	}
+	return cfg.PortBlock(repoNameAt(path))
+}
+
+// allocatePort allocates a port for the worktree name at path: the lowest
+// free one in its repository's port_bases block, or one derived from name
+// between port_min and port_max
+func allocatePort(name, path string, used map[int]bool) (int, error) {
+	if first, last, ok := portBlockAt(path); ok {
+		return port.NewAllocator(first, last).AllocateNext(used)
+	}
+	return port.NewAllocator(cfg.PortMin, cfg.PortMax).AllocateWithFallback(name, used)
+}
+
+// inPortBlock reports whether p is in the port_bases block of the worktree
+// at path, or true when its repository has none
+func inPortBlock(p int, path string) bool {
+	first, last, ok := portBlockAt(path)
+	return !ok || (p >= first && p <= last)
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+func TestAllocatePortInBlock(t *testing.T) {
+	useTestEnv(t)
+	cfg.PortBases = map[string]int{"myapp": 43100}
+	cfg.PortBlockSize = 10
+
+	// Without a remote, the repository is named after its directory
+	dir := filepath.Join(t.TempDir(), "myapp")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := allocatePort("myapp", dir, map[int]bool{43100: true})
+	if err != nil || got != 43101 {
+		t.Errorf("allocatePort = %d, %v, want the next port in the block, 43101", got, err)
+	}
+	if !inPortBlock(43109, dir) || inPortBlock(3042, dir) {
+		t.Error("inPortBlock should allow only the block")
+	}
+
+	full := make(map[int]bool)
+	for p := 43100; p <= 43109; p++ {
+		full[p] = true
+	}
+	if _, err := allocatePort("myapp", dir, full); err == nil {
+		t.Error("allocatePort should fail when the block is full")
+	}
+
+	other := t.TempDir()
+	if !inPortBlock(3042, other) {
+		t.Error("repositories without a block accept any port")
+	}
+	if got, err := allocatePort("other", other, nil); err != nil || got < cfg.PortMin || got > cfg.PortMax {
+		t.Errorf("allocatePort = %d, %v, want a port between port_min and port_max", got, err)
+	}
+}
+
+func TestCollectPorts(t *testing.T) {
+	useTestEnv(t)
+	cfg.PortBases = map[string]int{"api": 4100, "myapp": 3100}
+
+	workspaces := []*registry.Workspace{
+		{Name: "myapp-b", Repo: "myapp", Server: &registry.ServerState{Port: 3101, Status: registry.StatusStopped}},
+		{Name: "myapp", Repo: "myapp", Server: &registry.ServerState{Port: 3100, Status: registry.StatusRunning}},
+		{Name: "myapp-old", Repo: "myapp", Server: &registry.ServerState{Port: 3742, Status: registry.StatusStopped}},
+		{Name: "docs", Repo: "docs", Server: &registry.ServerState{Port: 3500}},
+		{Name: "no-server", Repo: "myapp"},
+	}
+	result := collectPorts(workspaces, func(ws *registry.Workspace) string { return ws.Repo })
+
+	if len(result.Blocks) != 3 {
+		t.Fatalf("blocks = %+v, want myapp, api and the rest", result.Blocks)
+	}
+	myapp, api, other := result.Blocks[0], result.Blocks[1], result.Blocks[2]
+	if myapp.Repo != "myapp" || myapp.First != 3100 || myapp.Last != 3199 {
+		t.Errorf("first block = %+v, want myapp 3100-3199", myapp)
+	}
+	if len(myapp.Worktrees) != 3 || myapp.Worktrees[0].Name != "myapp" || myapp.Worktrees[1].Name != "myapp-b" {
+		t.Errorf("myapp worktrees = %+v, want sorted by port", myapp.Worktrees)
+	}
+	if w := myapp.Worktrees[2]; w.Name != "myapp-old" || !w.OutsideBlock {
+		t.Errorf("myapp-old = %+v, want flagged outside the block", w)
+	}
+	if api.Repo != "api" || len(api.Worktrees) != 0 {
+		t.Errorf("api block = %+v, want listed without worktrees", api)
+	}
+	if other.Repo != "" || len(other.Worktrees) != 1 || other.Worktrees[0].Name != "docs" || other.Worktrees[0].OutsideBlock {
+		t.Errorf("other block = %+v, want docs", other)
+	}
+}
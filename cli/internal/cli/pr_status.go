@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/github"
+)
+
+// fetchPRStatus returns PR, CI, and review info for the given worktrees,
+// keyed by worktree path. Results are cached for cfg.PRCacheTTL.
+func fetchPRStatus(lookups []github.Lookup) map[string]*github.BranchInfo {
+	github.SetToken(cfg.GitHubToken)
+	cache := github.NewCache(config.PRCachePath(), cfg.PRCacheTTL)
+	return github.FetchAll(cache, lookups)
+}
+
+// formatPRSummary returns a one-line summary like "#42 open · CI ✓ · approved"
+func formatPRSummary(info *github.BranchInfo) string {
+	if info == nil {
+		return ""
+	}
+
+	var parts []string
+	if info.PR != nil {
+		parts = append(parts, github.FormatPRInfo(info.PR)+" "+github.FormatPRStatus(info.PR))
+	}
+	if ci := github.FormatCIStatus(info.CI); ci != "" {
+		parts = append(parts, "CI "+ci)
+	}
+	if info.PR != nil {
+		if review := github.FormatReviewStatus(info.PR); review != "-" {
+			parts = append(parts, review)
+		}
+	}
+	return strings.Join(parts, " · ")
+}
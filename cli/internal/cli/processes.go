@@ -0,0 +1,449 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/iheanyi/grove/internal/crash"
+	"github.com/iheanyi/grove/internal/events"
+	"github.com/iheanyi/grove/internal/execx"
+	"github.com/iheanyi/grove/internal/project"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/pkg/browser"
+)
+
+// supervisorStartTimeout is how long a daemonized start waits for the
+// process supervisor to register itself
+const supervisorStartTimeout = 10 * time.Second
+
+// prefixWriter writes each complete line of output with a fixed prefix,
+// so the output of several processes can be interleaved in one log
+type prefixWriter struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	prefix string
+	buf    []byte
+}
+
+func newPrefixWriter(out io.Writer, mu *sync.Mutex, prefix string) *prefixWriter {
+	return &prefixWriter{mu: mu, out: out, prefix: prefix}
+}
+
+// Write buffers p and emits every complete line with the prefix
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		if err := w.emit(w.buf[:i+1]); err != nil {
+			return 0, err
+		}
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush emits any trailing partial line
+func (w *prefixWriter) Flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	line := append(w.buf, '\n')
+	w.buf = nil
+	return w.emit(line)
+}
+
+func (w *prefixWriter) emit(line []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, err := fmt.Fprintf(w.out, "%s%s", w.prefix, line)
+	return err
+}
+
+// processPrefix returns the log prefix for a process, padded to width
+func processPrefix(name string, width int) string {
+	return fmt.Sprintf("%-*s | ", width, name)
+}
+
+// processEnv builds the environment for a single process. PORT is only set
+// when the process has a port (the web process or a subdomain target), so
+// workers don't try to bind the same port.
+func processEnv(server *registry.Server, projConfig *project.Config, processPort int) []string {
+	return append(os.Environ(), serverEnv(server, projConfig, processPort)...)
+}
+
+// saveServerState reloads the registry before saving so a long-running
+// supervisor doesn't overwrite changes made by other grove commands, such
+// as a process flagging the server for a fatal log line
+func saveServerState(server *registry.Server) error {
+	reg, err := registry.Load()
+	if err != nil {
+		return err
+	}
+	if current, ok := reg.Get(server.Name); ok && current.PID == server.PID && current.FatalLog != "" {
+		server.FatalLog = current.FatalLog
+		server.Health = current.Health
+	}
+	return reg.Set(server)
+}
+
+type processExit struct {
+	index int
+	err   error
+}
+
+// runProcesses starts every process defined in .grove.yaml under a single
+// server entry and supervises them until they exit or grove is signaled.
+// processPorts holds the PORT to export for each process that has one.
+// When supervised is true, grove was started by runProcessesDaemon and its
+// output is already going to the server's log file.
+func runProcesses(server *registry.Server, reg *registry.Registry, projConfig *project.Config, processPorts map[string]int, openBrowser, supervised bool) error {
+	names := projConfig.ProcessNames()
+	webProcess := projConfig.GetWebProcess()
+	fatal, err := fatalPatterns(projConfig)
+	if err != nil {
+		return err
+	}
+
+	width := 0
+	for _, name := range names {
+		if len(name) > width {
+			width = len(name)
+		}
+	}
+
+	// Handle signals
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+	var outMu sync.Mutex
+	procs := make([]execx.Process, len(names))
+	writers := make([]*prefixWriter, len(names))
+	tails := make([]*crash.Tail, len(names))
+	fatalLines := make([]*crash.FatalWatcher, len(names))
+	exited := make(chan processExit, len(names))
+	server.Processes = make([]registry.Process, len(names))
+
+	for i, name := range names {
+		command := projConfig.Processes[name]
+		isWeb := name == webProcess
+
+		w := newPrefixWriter(os.Stdout, &outMu, processPrefix(name, width))
+		tails[i] = crash.NewTail(crash.LogLines)
+		fatalLines[i] = fatalWatcher(server, name, fatal)
+		execCmd := execx.Command("/bin/sh", "-c", command)
+		execCmd.Dir = server.Path
+		execCmd.Stdout = io.MultiWriter(w, tails[i], fatalLines[i])
+		execCmd.Stderr = io.MultiWriter(w, tails[i], fatalLines[i])
+		execCmd.Env = processEnv(server, projConfig, processPorts[name])
+		execCmd.WaitDelay = time.Second
+
+		// Own process group so signals reach anything the process spawns
+		execCmd.Setpgid = true
+
+		// Keep stdin open so watchers like esbuild --watch don't exit.
+		// The write end is closed once the process exits.
+		stdin, keepOpen, err := os.Pipe()
+		if err != nil {
+			signalProcesses(server.Processes, syscall.SIGKILL)
+			return fmt.Errorf("failed to create stdin for process '%s': %w", name, err)
+		}
+		execCmd.Stdin = stdin
+
+		proc, err := runner.Start(execCmd)
+		stdin.Close()
+		if err != nil {
+			keepOpen.Close()
+			signalProcesses(server.Processes, syscall.SIGKILL)
+			return fmt.Errorf("failed to start process '%s': %w", name, err)
+		}
+
+		procs[i] = proc
+		writers[i] = w
+		server.Processes[i] = registry.Process{
+			Name:    name,
+			Command: command,
+			PID:     proc.Pid(),
+			Port:    processPorts[name],
+			Status:  registry.StatusRunning,
+			Web:     isWeb,
+		}
+
+		go func(index int) {
+			err := procs[index].Wait()
+			keepOpen.Close()
+			exited <- processExit{index: index, err: err}
+		}(i)
+	}
+
+	server.PID = os.Getpid()
+	server.Status = registry.StatusRunning
+
+	// Save to registry
+	if err := reg.Set(server); err != nil {
+		signalProcesses(server.Processes, syscall.SIGKILL)
+		return fmt.Errorf("failed to save to registry: %w", err)
+	}
+
+	// Auto-register worktree with main_repo for proper grouping
+	registerWorktree(reg, server)
+
+	events.Publish(server.Event(events.ServerStarted))
+
+	if !supervised {
+		// Reload proxy to pick up new route (only when proxied)
+		if cfg.UsesProxy() {
+			if err := ReloadProxy(); err != nil {
+				fmt.Printf("Warning: failed to reload proxy: %v\n", err)
+				fmt.Println("Run 'grove proxy stop && grove proxy start' to update routes manually")
+			}
+		}
+
+		printServerInfo(server)
+		fmt.Println("Press Ctrl+C to stop...")
+		runPostStartHooks(server)
+
+		// Open browser if requested
+		if openBrowser {
+			fmt.Printf("Opening %s in browser...\n", server.URL)
+			if err := browser.Open(server.URL); err != nil {
+				fmt.Printf("Warning: failed to open browser: %v\n", err)
+			}
+		}
+	}
+
+	stopping := false
+	markExited := func(e processExit) {
+		writers[e.index].Flush() //nolint:errcheck // Best-effort log output
+		proc := &server.Processes[e.index]
+		proc.PID = 0
+		outMu.Lock()
+		crashed := e.err != nil && !stopping
+		if crashed {
+			proc.Status = registry.StatusCrashed
+			fmt.Printf("%sexited: %v\n", processPrefix(proc.Name, width), e.err)
+		} else {
+			proc.Status = registry.StatusStopped
+			fmt.Printf("%sexited\n", processPrefix(proc.Name, width))
+		}
+		outMu.Unlock()
+
+		if crashed {
+			report := crash.New(server.Name, proc.Name, server.StartedAt, e.err)
+			if supervised {
+				report.LogFile = server.LogFile
+			}
+			report.Log = tails[e.index].Lines()
+			report.Fatal = fatalLines[e.index].Matches()
+			saveCrashReport(report)
+
+			event := server.Event(events.ServerCrashed)
+			event.Process = proc.Name
+			event.Message = e.err.Error()
+			event.ExitCode = report.ExitCode
+			events.Publish(event)
+		}
+	}
+
+	remaining := len(procs)
+	for remaining > 0 {
+		select {
+		case <-sigChan:
+			fmt.Println("\nStopping processes...")
+			stopping = true
+
+			// Run before_stop hooks while the processes are still up;
+			// grove stop runs them for daemonized servers
+			if !supervised && len(projConfig.Hooks.BeforeStop) > 0 {
+				for _, hook := range projConfig.Hooks.BeforeStop {
+					if err := runHook(hook, server.Path); err != nil {
+						fmt.Fprintf(os.Stderr, "Warning: before_stop hook failed: %v\n", err)
+					}
+				}
+			}
+
+			signalProcesses(server.Processes, syscall.SIGTERM)
+
+			// Wait a bit for graceful shutdown
+			timeout := time.After(5 * time.Second)
+			for remaining > 0 {
+				select {
+				case e := <-exited:
+					markExited(e)
+					remaining--
+				case <-timeout:
+					signalProcesses(server.Processes, syscall.SIGKILL)
+					timeout = nil
+				}
+			}
+		case e := <-exited:
+			markExited(e)
+			remaining--
+			if remaining > 0 {
+				// Record the per-process status change
+				if err := saveServerState(server); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: failed to update registry: %v\n", err)
+				}
+			}
+		}
+	}
+
+	// Update registry
+	server.Status = registry.StatusStopped
+	server.PID = 0
+	server.StoppedAt = time.Now()
+	if err := saveServerState(server); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to update registry: %v\n", err)
+	}
+
+	// grove stop publishes the stop event for daemonized servers it stops
+	if !supervised || !stopping {
+		events.Publish(server.Event(events.ServerStopped))
+	}
+
+	if supervised {
+		// grove stop handles hooks and proxy reloads for daemonized servers
+		return nil
+	}
+
+	// Reload proxy to remove route (only when proxied)
+	if cfg.UsesProxy() {
+		if err := ReloadProxy(); err != nil {
+			fmt.Printf("Warning: failed to reload proxy: %v\n", err)
+		}
+	}
+
+	return nil
+}
+
+// signalProcesses sends sig to the process group of every running process
+func signalProcesses(procs []registry.Process, sig syscall.Signal) {
+	for _, proc := range procs {
+		if proc.PID <= 0 {
+			continue
+		}
+		if process, err := runner.FindProcess(proc.PID); err == nil {
+			_ = process.SignalGroup(sig)
+		}
+	}
+}
+
+// runProcessesDaemon runs the process supervisor in the background by
+// re-executing grove with --supervise, logging to the server's log file
+func runProcessesDaemon(server *registry.Server, projConfig *project.Config, openBrowser bool) error {
+	logFile, err := os.OpenFile(server.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	defer logFile.Close()
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find grove executable: %w", err)
+	}
+
+	execCmd := execx.Command(executable, "start", "--supervise", "--port", fmt.Sprintf("%d", server.Port))
+	execCmd.Dir = server.Path
+	execCmd.Stdout = logFile
+	execCmd.Stderr = logFile
+
+	// Start as a new process group so it survives parent exit
+	execCmd.Setpgid = true
+
+	proc, err := runner.Start(execCmd)
+	if err != nil {
+		return fmt.Errorf("failed to start process supervisor: %w", err)
+	}
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- proc.Wait()
+	}()
+
+	// Wait for the supervisor to register itself
+	deadline := time.After(supervisorStartTimeout)
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	var registered *registry.Server
+	for registered == nil {
+		select {
+		case <-exited:
+			return fmt.Errorf("process supervisor exited during startup\nCheck logs: %s", server.LogFile)
+		case <-deadline:
+			return fmt.Errorf("timed out waiting for process supervisor to start\nCheck logs: %s", server.LogFile)
+		case <-ticker.C:
+			reg, err := registry.Load()
+			if err != nil {
+				continue
+			}
+			if s, ok := reg.Get(server.Name); ok && s.PID == proc.Pid() && s.IsRunning() {
+				registered = s
+			}
+		}
+	}
+
+	// Reload proxy to pick up new route (only when proxied)
+	if cfg.UsesProxy() {
+		if err := ReloadProxy(); err != nil {
+			fmt.Printf("Warning: failed to reload proxy: %v\n", err)
+			fmt.Println("Run 'grove proxy stop && grove proxy start' to update routes manually")
+		}
+	}
+
+	printServerInfo(registered)
+	fmt.Printf("Logs: %s\n", registered.LogFile)
+
+	// Run after_start hooks
+	if len(projConfig.Hooks.AfterStart) > 0 {
+		fmt.Println("Running after_start hooks...")
+		for _, hook := range projConfig.Hooks.AfterStart {
+			if err := runHook(hook, server.Path); err != nil {
+				fmt.Printf("Warning: after_start hook failed: %v\n", err)
+			}
+		}
+	}
+	runPostStartHooks(server)
+
+	// Open browser if requested
+	if openBrowser {
+		fmt.Printf("Opening %s in browser...\n", server.URL)
+		if err := browser.Open(server.URL); err != nil {
+			fmt.Printf("Warning: failed to open browser: %v\n", err)
+		}
+	}
+
+	return nil
+}
+
+// printServerInfo prints the URL, PID and processes of a started server
+func printServerInfo(server *registry.Server) {
+	fmt.Printf("Server running at: %s\n", server.URL)
+	if cfg.IsSubdomainMode() {
+		fmt.Printf("Subdomains available: %s\n", cfg.SubdomainURL(server.Name))
+	}
+	fmt.Printf("PID: %d\n", server.PID)
+	for _, proc := range server.Processes {
+		details := string(proc.Status)
+		if proc.PID > 0 {
+			details = fmt.Sprintf("pid %d", proc.PID)
+		}
+		if proc.Port > 0 {
+			details += fmt.Sprintf(", PORT=%d", proc.Port)
+		}
+		fmt.Printf("  %s: %s (%s)\n", proc.Name, proc.Command, details)
+	}
+	if cfg.IsSubdomainMode() {
+		for _, sub := range sortedSubdomains(server) {
+			fmt.Printf("  %s.%s.%s -> localhost:%d\n", sub, server.Name, cfg.TLD, server.Subdomains[sub])
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"slices"
+	"sync"
+	"syscall"
+	"testing"
+
+	"github.com/iheanyi/grove/internal/execx"
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+func TestPrefixWriter(t *testing.T) {
+	var out bytes.Buffer
+	var mu sync.Mutex
+	w := newPrefixWriter(&out, &mu, processPrefix("web", 6))
+
+	// Partial lines are buffered until a newline arrives
+	if _, err := w.Write([]byte("hello\nwor")); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	if got, want := out.String(), "web    | hello\n"; got != want {
+		t.Errorf("after first write got %q, want %q", got, want)
+	}
+
+	if _, err := w.Write([]byte("ld\npartial")); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() failed: %v", err)
+	}
+
+	want := "web    | hello\nweb    | world\nweb    | partial\n"
+	if got := out.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestPrefixWriter_SharedOutput(t *testing.T) {
+	var out bytes.Buffer
+	var mu sync.Mutex
+	web := newPrefixWriter(&out, &mu, processPrefix("web", 4))
+	jobs := newPrefixWriter(&out, &mu, processPrefix("jobs", 4))
+
+	web.Write([]byte("listening\n")) //nolint:errcheck
+	jobs.Write([]byte("working\n"))  //nolint:errcheck
+
+	want := "web  | listening\njobs | working\n"
+	if got := out.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSignalProcesses(t *testing.T) {
+	fake := useFakeRunner(t)
+	web, worker := fake.AddProcess(4242), fake.AddProcess(4343)
+
+	signalProcesses([]registry.Process{{Name: "web", PID: 4242}, {Name: "worker", PID: 4343}, {Name: "exited"}}, syscall.SIGTERM)
+
+	for _, proc := range []*execx.FakeProcess{web, worker} {
+		if got := proc.Signals(); !slices.Equal(got, []os.Signal{syscall.SIGTERM}) {
+			t.Errorf("signals = %v, want [SIGTERM]", got)
+		}
+	}
+}
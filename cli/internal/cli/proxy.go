@@ -1,17 +1,26 @@
 package cli
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"os"
 	"os/exec"
 	"os/signal"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
+	"github.com/iheanyi/grove/internal/accesslog"
+	"github.com/iheanyi/grove/internal/certs"
 	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/lan"
+	"github.com/iheanyi/grove/internal/output"
 	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/service"
 	"github.com/spf13/cobra"
 )
 
@@ -29,7 +38,9 @@ Examples:
   grove proxy start   # Start the proxy daemon
   grove proxy stop    # Stop the proxy daemon
   grove proxy status  # Check proxy status
-  grove proxy routes  # List all registered routes`,
+  grove proxy routes  # List all registered routes
+  grove proxy export --format nginx   # Routes as nginx/traefik/caddy config
+  grove proxy stats   # Request counts, status codes, and latency per host`,
 }
 
 var proxyStartCmd = &cobra.Command{
@@ -63,12 +74,14 @@ func init() {
 	proxyCmd.AddCommand(proxyRoutesCmd)
 
 	proxyStartCmd.Flags().BoolP("foreground", "f", false, "Run in foreground")
+	addOutputFlags(proxyStatusCmd)
+	addOutputFlags(proxyRoutesCmd)
 }
 
 func runProxyStart(cmd *cobra.Command, args []string) error {
 	// Warn if in port mode
-	if !cfg.IsSubdomainMode() {
-		fmt.Println("Note: URL mode is set to 'port'. The proxy is only needed for 'subdomain' mode.")
+	if !cfg.UsesProxy() {
+		fmt.Println("Note: URL mode is set to 'port'. The proxy is only needed for 'subdomain' and 'path' mode.")
 		fmt.Println("To use subdomain mode, set 'url_mode: subdomain' in ~/.config/wt/config.yaml")
 		fmt.Println()
 	}
@@ -86,18 +99,28 @@ func runProxyStart(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("proxy is already running (PID: %d)\nUse 'grove proxy stop' to stop it first", proxy.PID)
 	}
 
-	fmt.Printf("Starting proxy on :%d/:%d...\n", cfg.ProxyHTTPPort, cfg.ProxyHTTPSPort)
+	bind := cfg.ProxyListenAddress
+	fmt.Printf("Starting proxy on %s:%d/%s:%d...\n", bind, cfg.ProxyHTTPPort, bind, cfg.ProxyHTTPSPort)
+	if cfg.ServesLAN() {
+		fmt.Println("Servers are also served to the local network; run 'grove lan' for their URLs")
+	}
 
 	if foreground {
 		return runProxyForeground(reg)
 	}
 
-	return runProxyDaemon(reg)
+	return runProxyDaemon()
 }
 
 func runProxyForeground(reg *registry.Registry) error {
+	// Paused servers are routed to the wake handler, which resumes them
+	wakePort, err := startWakeServer()
+	if err != nil {
+		return fmt.Errorf("failed to start wake handler: %w", err)
+	}
+
 	// Generate Caddyfile
-	caddyfilePath, err := generateCaddyfile(reg)
+	caddyfilePath, err := generateCaddyfile(reg, wakePort)
 	if err != nil {
 		return fmt.Errorf("failed to generate Caddyfile: %w", err)
 	}
@@ -123,11 +146,31 @@ func runProxyForeground(reg *registry.Registry) error {
 		StartedAt: time.Now(),
 		HTTPPort:  cfg.ProxyHTTPPort,
 		HTTPSPort: cfg.ProxyHTTPSPort,
+		WakePort:  wakePort,
 	}
 	if err := reg.UpdateProxy(proxy); err != nil {
 		return fmt.Errorf("failed to update proxy in registry: %w", err)
 	}
 
+	if cfg.PauseAfter > 0 {
+		if cfg.ProxyAccessLog {
+			go pauseIdleServers(cfg.PauseAfter)
+		} else {
+			fmt.Println("Warning: pause_after requires proxy_access_log to detect traffic")
+		}
+	}
+
+	// Servers and routes can wedge while the machine sleeps
+	resumeCtx, stopResume := context.WithCancel(context.Background())
+	defer stopResume()
+	go watchResume(resumeCtx)
+	go watchFatalLogs(resumeCtx)
+	go watchAgentUsage(resumeCtx)
+	go watchFreeze(resumeCtx, service.Proxy)
+	if cfg.MDNS.Enabled {
+		go advertiseServers(resumeCtx)
+	}
+
 	fmt.Printf("Proxy running (PID: %d)\n", proxy.PID)
 	fmt.Println("Press Ctrl+C to stop...")
 
@@ -168,17 +211,11 @@ func runProxyForeground(reg *registry.Registry) error {
 	return nil
 }
 
-func generateCaddyfile(reg *registry.Registry) (string, error) {
+// generateCaddyfile writes the Caddyfile routing the registry's servers,
+// sending those that need waking to the wake handler on wakePort
+func generateCaddyfile(reg *registry.Registry, wakePort int) (string, error) {
 	caddyfilePath := filepath.Join(config.ConfigDir(), "Caddyfile")
 
-	var sb strings.Builder
-
-	// Global options
-	sb.WriteString("{\n")
-	sb.WriteString("\tlocal_certs\n")
-	sb.WriteString("\tauto_https disable_redirects\n")
-	sb.WriteString("}\n\n")
-
 	// Reload registry to get latest data
 	freshReg, err := registry.Load()
 	if err != nil {
@@ -187,37 +224,374 @@ func generateCaddyfile(reg *registry.Registry) (string, error) {
 		reg = freshReg
 	}
 
+	// Use mkcert certificates when generated via 'grove certs install'
+	cert, err := certs.Load(cfg.TLD)
+	if err != nil && !os.IsNotExist(err) {
+		fmt.Printf("Warning: failed to load certificates: %v\n", err)
+	}
+
+	opts := caddyfileOptions{
+		TLD:      cfg.TLD,
+		Cert:     cert,
+		WakePort: wakePort,
+		Cookies:  cfg.ProxyCookies,
+		Security: cfg.ProxySecurity,
+		Bind:     cfg.ProxyListenAddress,
+	}
+	if cfg.IsPathMode() {
+		opts.PathHost = cfg.PathHost()
+	}
+	if cfg.ProxyAccessLog && !cfg.IsPathMode() {
+		opts.AccessLogDir = cfg.AccessLogDir()
+		if err := os.MkdirAll(opts.AccessLogDir, 0755); err != nil {
+			return "", fmt.Errorf("failed to create access log directory: %w", err)
+		}
+	}
+
 	// Get all servers (both running and stopped - for routing)
 	servers := reg.List()
+	opts.Autostart = autostartServers(servers)
+	if cfg.ServesLAN() {
+		opts.LANPorts = lanPorts(servers)
+		opts.LANHosts = lanHosts(servers)
+	}
+	content := buildCaddyfile(servers, opts)
+
+	if err := os.WriteFile(caddyfilePath, []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("failed to write Caddyfile: %w", err)
+	}
+
+	return caddyfilePath, nil
+}
+
+// caddyfileOptions configures the generated Caddyfile
+type caddyfileOptions struct {
+	TLD string
+
+	// Cert is used for the sites it covers; the rest fall back to Caddy's
+	// local certs
+	Cert *certs.Cert
+
+	// AccessLogDir, when set, has each server's sites log requests to one
+	// JSON file
+	AccessLogDir string
+
+	// WakePort is the proxy's wake handler. Paused servers, and stopped
+	// servers named in Autostart, are routed to it when set.
+	WakePort  int
+	Autostart map[string]bool
+
+	// Cookies rewrites the Set-Cookie headers of every site
+	Cookies config.ProxyCookiesConfig
+
+	// Security sets the extra hosts served, the response to unknown ones,
+	// and each site's headers and request size limit
+	Security config.ProxySecurityConfig
+
+	// Bind is the address the proxy listens on; empty for every interface
+	Bind string
+
+	// LANPorts are the ports each server is also served on over plain
+	// HTTP, for devices on the network, by server name
+	LANPorts map[string]int
+
+	// LANHosts are the host names each server is served by on its LAN
+	// port, by server name
+	LANHosts map[string][]string
+
+	// PathHost, when set, serves every server under /<name>/ on this host
+	// instead of on its own domain (path URL mode)
+	PathHost string
+}
+
+// buildCaddyfile renders the Caddyfile routing each server's domain to its port.
+// Subdomains mapped in .grove.yaml get their own site block; Caddy matches exact
+// hosts before wildcards, so the wildcard catches every other subdomain.
+func buildCaddyfile(servers []*registry.Server, opts caddyfileOptions) string {
+	if opts.PathHost != "" {
+		return buildPathCaddyfile(servers, opts)
+	}
+
+	tld, cert := opts.TLD, opts.Cert
+	var sb strings.Builder
+
+	writeCaddyGlobals(&sb, opts)
 
 	if len(servers) == 0 {
 		// Default fallback when no servers
-		sb.WriteString(fmt.Sprintf("https://*.%s {\n", cfg.TLD))
+		sb.WriteString(fmt.Sprintf("https://*.%s {\n", tld))
 		sb.WriteString("\trespond \"No server registered for this domain\" 503\n")
 		sb.WriteString("}\n")
+		return sb.String()
+	}
+
+	site := func(server *registry.Server, host string, port int) {
+		sb.WriteString(fmt.Sprintf("https://%s {\n", host))
+		if cert != nil && cert.Covers(host) {
+			sb.WriteString(fmt.Sprintf("\ttls %s %s\n", cert.CertFile, cert.KeyFile))
+		}
+		var directives []string
+		wake := server.IsPaused() || (!server.IsRunning() && opts.Autostart[server.Name])
+		if wake && opts.WakePort > 0 {
+			directives = append(directives, wakeDirectives(server, port)...)
+			port = opts.WakePort
+		}
+		directives = append(directives, cookieDirectives(opts.Cookies, server.Name+"."+tld)...)
+		writeSecurityDirectives(&sb, opts.Security, "\t")
+		if len(directives) == 0 {
+			sb.WriteString(fmt.Sprintf("\treverse_proxy localhost:%d\n", port))
+		} else {
+			sb.WriteString(fmt.Sprintf("\treverse_proxy localhost:%d {\n", port))
+			for _, d := range directives {
+				sb.WriteString("\t\t" + d + "\n")
+			}
+			sb.WriteString("\t}\n")
+		}
+		if opts.AccessLogDir != "" {
+			sb.WriteString("\tlog {\n")
+			sb.WriteString(fmt.Sprintf("\t\toutput file %s {\n", accesslog.Path(opts.AccessLogDir, server.Name)))
+			sb.WriteString("\t\t\troll_size 10MiB\n\t\t\troll_keep 2\n\t\t}\n")
+			sb.WriteString("\t\tformat json\n")
+			sb.WriteString("\t}\n")
+		}
+		sb.WriteString("}\n\n")
+	}
+
+	// Generate route for each server
+	for _, server := range servers {
+		// Main domain
+		site(server, fmt.Sprintf("%s.%s", server.Name, tld), server.Port)
+
+		// Routed subdomains
+		for _, sub := range sortedSubdomains(server) {
+			site(server, fmt.Sprintf("%s.%s.%s", sub, server.Name, tld), server.Subdomains[sub])
+		}
+
+		// Wildcard subdomains
+		site(server, fmt.Sprintf("*.%s.%s", server.Name, tld), server.Port)
+	}
+
+	// Extra hosts routed to worktrees
+	byName := make(map[string]*registry.Server, len(servers))
+	for _, server := range servers {
+		byName[server.Name] = server
+	}
+	hosts := make([]string, 0, len(opts.Security.AllowedHosts))
+	for host := range opts.Security.AllowedHosts {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	for _, host := range hosts {
+		if server, ok := byName[opts.Security.AllowedHosts[host]]; ok {
+			site(server, host, server.Port)
+		}
+	}
+
+	writeLANSites(&sb, servers, opts)
+
+	// Every other host under the TLD, over HTTPS or plain HTTP, is refused
+	// rather than left to Caddy's empty default response
+	sb.WriteString(wildcardAddresses("https", tld) + " {\n")
+	if cert != nil && cert.Covers("*."+tld) {
+		sb.WriteString(fmt.Sprintf("\ttls %s %s\n", cert.CertFile, cert.KeyFile))
+	}
+	sb.WriteString(fmt.Sprintf("\trespond \"{host} is not a registered worktree\" %d\n", opts.Security.UnknownHostResponse()))
+	sb.WriteString("}\n\n")
+	sb.WriteString(wildcardAddresses("http", tld) + " {\n")
+	sb.WriteString(fmt.Sprintf("\trespond \"{host} is not a registered worktree (worktrees are served over https)\" %d\n", opts.Security.UnknownHostResponse()))
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+// unknownHostDepth is how many labels below the TLD the unknown host
+// blocks reach, e.g. a.b.feature.localhost; Caddy's wildcards match
+// exactly one label each
+const unknownHostDepth = 3
+
+// wildcardAddresses returns site addresses matching every host under the
+// TLD up to unknownHostDepth labels deep, e.g. "https://*.localhost,
+// https://*.*.localhost, https://*.*.*.localhost". Caddy tries more
+// specific sites first, so registered hosts still match their own.
+func wildcardAddresses(scheme, tld string) string {
+	addresses := make([]string, 0, unknownHostDepth)
+	for depth := 1; depth <= unknownHostDepth; depth++ {
+		addresses = append(addresses, scheme+"://"+strings.Repeat("*.", depth)+tld)
+	}
+	return strings.Join(addresses, ", ")
+}
+
+// writeCaddyGlobals writes the Caddyfile's global options
+func writeCaddyGlobals(sb *strings.Builder, opts caddyfileOptions) {
+	sb.WriteString("{\n")
+	if opts.Bind != "" {
+		sb.WriteString(fmt.Sprintf("\tdefault_bind %s\n", opts.Bind))
+	}
+	sb.WriteString("\tlocal_certs\n")
+	sb.WriteString("\tauto_https disable_redirects\n")
+	// Request counts per host, re-exported by the dashboard's /metrics
+	sb.WriteString("\tmetrics {\n\t\tper_host\n\t}\n")
+	// A Host that doesn't match the TLS server name gets 421, so a request
+	// can't reach one worktree through another's connection
+	sb.WriteString("\tservers {\n\t\tstrict_sni_host on\n\t}\n")
+	sb.WriteString("}\n\n")
+}
+
+// writeLANSites writes a plain HTTP site on each server's LAN port for the
+// host names devices on the network reach it by. Any other host on the
+// port is refused like unknown hosts under the TLD, so the port can't be
+// reached through a name pointed at this machine's IP address.
+func writeLANSites(sb *strings.Builder, servers []*registry.Server, opts caddyfileOptions) {
+	for _, server := range servers {
+		lanPort := opts.LANPorts[server.Name]
+		if lanPort == 0 {
+			continue
+		}
+		writeLANSite(sb, server, lanPort, opts)
+		sb.WriteString(fmt.Sprintf("http://:%d {\n", lanPort))
+		sb.WriteString(fmt.Sprintf("\trespond \"{host} is not a registered worktree\" %d\n", opts.Security.UnknownHostResponse()))
+		sb.WriteString("}\n\n")
+	}
+}
+
+// writeLANSite writes the site serving a server on its LAN port, if it has
+// host names on the network
+func writeLANSite(sb *strings.Builder, server *registry.Server, lanPort int, opts caddyfileOptions) {
+	hosts := opts.LANHosts[server.Name]
+	if len(hosts) == 0 {
+		return
+	}
+	addresses := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		addresses = append(addresses, "http://"+net.JoinHostPort(host, strconv.Itoa(lanPort)))
+	}
+	sb.WriteString(strings.Join(addresses, ", ") + " {\n")
+	writeSecurityDirectives(sb, opts.Security, "\t")
+	wake := server.IsPaused() || (!server.IsRunning() && opts.Autostart[server.Name])
+	if wake && opts.WakePort > 0 {
+		sb.WriteString(fmt.Sprintf("\treverse_proxy localhost:%d {\n", opts.WakePort))
+		for _, d := range wakeDirectives(server, server.Port) {
+			sb.WriteString("\t\t" + d + "\n")
+		}
+		sb.WriteString("\t}\n")
 	} else {
-		// Generate route for each server
-		for _, server := range servers {
-			// Main domain
-			sb.WriteString(fmt.Sprintf("https://%s.%s {\n", server.Name, cfg.TLD))
-			sb.WriteString(fmt.Sprintf("\treverse_proxy localhost:%d\n", server.Port))
-			sb.WriteString("}\n\n")
-
-			// Wildcard subdomains
-			sb.WriteString(fmt.Sprintf("https://*.%s.%s {\n", server.Name, cfg.TLD))
-			sb.WriteString(fmt.Sprintf("\treverse_proxy localhost:%d\n", server.Port))
-			sb.WriteString("}\n\n")
+		sb.WriteString(fmt.Sprintf("\treverse_proxy localhost:%d\n", server.Port))
+	}
+	sb.WriteString("}\n\n")
+}
+
+// lanPorts returns the port each server is served on to the network
+func lanPorts(servers []*registry.Server) map[string]int {
+	ports := make(map[string]int, len(servers))
+	for _, server := range servers {
+		if p := cfg.LANPort(server.Port); p != 0 {
+			ports[server.Name] = p
 		}
 	}
+	return ports
+}
 
-	if err := os.WriteFile(caddyfilePath, []byte(sb.String()), 0644); err != nil {
-		return "", fmt.Errorf("failed to write Caddyfile: %w", err)
+// lanHosts returns the host names devices on the network reach each server
+// by: this machine's IP address, and the server's mDNS name when mDNS is on
+func lanHosts(servers []*registry.Server) map[string][]string {
+	ip, err := lan.IP()
+	if err != nil {
+		fmt.Printf("Warning: %v\n", err)
 	}
+	hosts := make(map[string][]string, len(servers))
+	for _, server := range servers {
+		var names []string
+		if ip != nil {
+			names = append(names, ip.String())
+		}
+		if cfg.MDNS.Enabled {
+			names = append(names, strings.TrimSuffix(mdnsHost(server.Name), "."))
+		}
+		hosts[server.Name] = names
+	}
+	return hosts
+}
 
-	return caddyfilePath, nil
+// writeSecurityDirectives writes a site's request size limit and security
+// headers, indented by indent
+func writeSecurityDirectives(sb *strings.Builder, security config.ProxySecurityConfig, indent string) {
+	if security.MaxRequestBody != "" {
+		sb.WriteString(fmt.Sprintf("%srequest_body {\n%s\tmax_size %s\n%s}\n", indent, indent, security.MaxRequestBody, indent))
+	}
+
+	matcher := ""
+	switch security.HeadersMode() {
+	case config.SecurityHeadersOff:
+		return
+	case config.SecurityHeadersRemote:
+		// Requests from other machines, e.g. with the proxy on the LAN
+		sb.WriteString(fmt.Sprintf("%s@remote not remote_ip 127.0.0.0/8 ::1\n", indent))
+		matcher = "@remote "
+	}
+	sb.WriteString(fmt.Sprintf("%sheader %s{\n", indent, matcher))
+	sb.WriteString(fmt.Sprintf("%s\tX-Frame-Options SAMEORIGIN\n", indent))
+	sb.WriteString(fmt.Sprintf("%s\tX-Content-Type-Options nosniff\n", indent))
+	sb.WriteString(fmt.Sprintf("%s\tReferrer-Policy strict-origin-when-cross-origin\n", indent))
+	sb.WriteString(fmt.Sprintf("%s}\n", indent))
+}
+
+// printCookieStatus shows how cookies are rewritten, with a hint when
+// worktrees can share sessions
+func printCookieStatus() {
+	var rewrites []string
+	if cfg.ProxyCookies.Isolate {
+		rewrites = append(rewrites, "scoped per worktree")
+	}
+	if cfg.ProxyCookies.Secure {
+		rewrites = append(rewrites, "marked Secure")
+	}
+	if len(rewrites) == 0 {
+		fmt.Println("Cookies:    passed through (cookies set for the TLD are shared by every worktree; set proxy_cookies.isolate to scope them)")
+		return
+	}
+	fmt.Printf("Cookies:    %s\n", strings.Join(rewrites, ", "))
+}
+
+// printSecurityStatus shows which hosts are refused and the headers and
+// limits applied
+func printSecurityStatus() {
+	sec := cfg.ProxySecurity
+	parts := []string{fmt.Sprintf("unknown hosts get %d", sec.UnknownHostResponse())}
+	if n := len(sec.AllowedHosts); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d extra allowed hosts", n))
+	}
+	switch sec.HeadersMode() {
+	case config.SecurityHeadersRemote:
+		parts = append(parts, "security headers for remote clients")
+	case config.SecurityHeadersAlways:
+		parts = append(parts, "security headers on every response")
+	}
+	if sec.MaxRequestBody != "" {
+		parts = append(parts, "request bodies up to "+sec.MaxRequestBody)
+	}
+	fmt.Printf("Security:   %s\n", strings.Join(parts, ", "))
 }
 
-func runProxyDaemon(reg *registry.Registry) error {
+// cookieDirectives returns the reverse_proxy directives rewriting Set-Cookie
+// headers for a worktree's sites. Caddy applies each regex replacement to
+// every Set-Cookie value; the patterns avoid backslashes, which the
+// Caddyfile would unescape.
+func cookieDirectives(cookies config.ProxyCookiesConfig, domain string) []string {
+	var directives []string
+	if cookies.Isolate {
+		directives = append(directives, fmt.Sprintf(`header_down Set-Cookie "(?i);[ ]*domain=[^;]*" "; Domain=%s"`, domain))
+	}
+	if cookies.Secure {
+		// Drop any Secure attribute, then append one
+		directives = append(directives,
+			`header_down Set-Cookie "(?i);[ ]*secure[ ]*(;|$)" "$1"`,
+			`header_down Set-Cookie "$" "; Secure"`,
+		)
+	}
+	return directives
+}
+
+func runProxyDaemon() error {
 	// Start as a background process
 	executable, err := os.Executable()
 	if err != nil {
@@ -241,6 +615,7 @@ func runProxyDaemon(reg *registry.Registry) error {
 	cmd.Stdout = logFile
 	cmd.Stderr = logFile
 
+	startedAt := time.Now()
 	if err := cmd.Start(); err != nil {
 		logFile.Close()
 		return fmt.Errorf("failed to start proxy: %w", err)
@@ -252,15 +627,11 @@ func runProxyDaemon(reg *registry.Registry) error {
 	}
 	logFile.Close()
 
-	// Update registry
-	proxy := &registry.ProxyInfo{
-		PID:       cmd.Process.Pid,
-		StartedAt: time.Now(),
-		HTTPPort:  cfg.ProxyHTTPPort,
-		HTTPSPort: cfg.ProxyHTTPSPort,
-	}
-	if err := reg.UpdateProxy(proxy); err != nil {
-		return fmt.Errorf("failed to update proxy in registry: %w", err)
+	// The proxy records itself once its wake handler is listening and
+	// Caddy is running
+	proxy, ok := waitForProxyRecord(startedAt, 10*time.Second)
+	if !ok {
+		return fmt.Errorf("proxy did not start (PID: %d)\nCheck logs: %s/proxy.log", cmd.Process.Pid, config.ConfigDir())
 	}
 
 	fmt.Printf("Proxy started (PID: %d)\n", proxy.PID)
@@ -269,6 +640,23 @@ func runProxyDaemon(reg *registry.Registry) error {
 	return nil
 }
 
+// waitForProxyRecord waits for a proxy started at startedAt to record
+// itself in the registry and returns what it recorded
+func waitForProxyRecord(startedAt time.Time, timeout time.Duration) (*registry.ProxyInfo, bool) {
+	deadline := time.Now().Add(timeout)
+	for {
+		if reg, err := registry.Load(); err == nil {
+			if proxy := reg.GetProxy(); proxy.IsRunning() && !proxy.StartedAt.Before(startedAt) {
+				return proxy, true
+			}
+		}
+		if time.Now().After(deadline) {
+			return nil, false
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
 func runProxyStop(cmd *cobra.Command, args []string) error {
 	// Load registry
 	reg, err := registry.Load()
@@ -339,15 +727,40 @@ func runProxyStatus(cmd *cobra.Command, args []string) error {
 	}
 
 	proxy := reg.GetProxy()
+	running := proxy.IsRunning() && isProcessRunning(proxy.PID)
 
-	if proxy.IsRunning() && isProcessRunning(proxy.PID) {
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	if format.IsMachine() {
+		status := output.ProxyStatus{
+			Running:   running,
+			HTTPPort:  proxy.HTTPPort,
+			HTTPSPort: proxy.HTTPSPort,
+			Cert:      certStatus(reg),
+		}
+		if running {
+			status.PID = proxy.PID
+			status.StartedAt = output.TimePtr(proxy.StartedAt)
+		}
+		return output.Write(os.Stdout, format, status)
+	}
+
+	if running {
 		fmt.Printf("Status:     running\n")
 		fmt.Printf("PID:        %d\n", proxy.PID)
 		fmt.Printf("HTTP Port:  %d\n", proxy.HTTPPort)
 		fmt.Printf("HTTPS Port: %d\n", proxy.HTTPSPort)
 		fmt.Printf("Started At: %s\n", proxy.StartedAt.Format("2006-01-02 15:04:05"))
+		printCertStatus(reg)
+		printCookieStatus()
+		printSecurityStatus()
 	} else {
 		fmt.Println("Status: stopped")
+		printCertStatus(reg)
+		printCookieStatus()
+		printSecurityStatus()
 		fmt.Println("\nUse 'grove proxy start' to start the proxy")
 	}
 
@@ -361,8 +774,19 @@ func runProxyRoutes(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load registry: %w", err)
 	}
 
-	servers := reg.ListRunning()
-	if len(servers) == 0 {
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	routes := collectRoutes(reg.ListRunning(), cfg.TLD)
+	if cfg.IsPathMode() {
+		routes = collectPathRoutes(reg.ListRunning(), cfg.PathHost())
+	}
+	if format.IsMachine() {
+		return output.Write(os.Stdout, format, output.ProxyRoutes{TLD: cfg.TLD, Routes: routes})
+	}
+
+	if len(routes) == 0 {
 		fmt.Println("No routes registered")
 		fmt.Println("\nStart a server with 'grove start' to register routes")
 		return nil
@@ -371,10 +795,11 @@ func runProxyRoutes(cmd *cobra.Command, args []string) error {
 	fmt.Println("Registered Routes:")
 	fmt.Println()
 
-	for _, s := range servers {
-		fmt.Printf("  %s.%s -> localhost:%d\n", s.Name, cfg.TLD, s.Port)
-		fmt.Printf("  *.%s.%s -> localhost:%d\n", s.Name, cfg.TLD, s.Port)
-		fmt.Println()
+	for _, r := range routes {
+		fmt.Printf("  %s -> localhost:%d\n", r.Host, r.Port)
+		if r.Kind == "wildcard" {
+			fmt.Println()
+		}
 	}
 
 	return nil
@@ -389,11 +814,20 @@ func isProcessRunning(pid int) bool {
 	return err == nil
 }
 
+// proxyReloadTimeout bounds 'caddy reload', which waits on Caddy's admin
+// API and would otherwise hang start and stop when Caddy is wedged
+const proxyReloadTimeout = 10 * time.Second
+
 // ReloadProxy regenerates the Caddyfile and reloads Caddy to pick up new routes.
 // This should be called whenever servers are started or stopped.
 func ReloadProxy() error {
+	return ReloadProxyContext(context.Background())
+}
+
+// ReloadProxyContext is ReloadProxy, giving up when ctx is done
+func ReloadProxyContext(ctx context.Context) error {
 	// Load registry to check if proxy is running
-	reg, err := registry.Load()
+	reg, err := registry.LoadContext(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to load registry: %w", err)
 	}
@@ -405,7 +839,7 @@ func ReloadProxy() error {
 	}
 
 	// Regenerate Caddyfile with current servers
-	caddyfilePath, err := generateCaddyfile(reg)
+	caddyfilePath, err := generateCaddyfile(reg, proxy.WakePort)
 	if err != nil {
 		return fmt.Errorf("failed to generate Caddyfile: %w", err)
 	}
@@ -417,7 +851,9 @@ func ReloadProxy() error {
 	}
 
 	// Reload Caddy with new config
-	cmd := exec.Command(caddyPath, "reload", "--config", caddyfilePath)
+	ctx, cancel := context.WithTimeout(ctx, proxyReloadTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, caddyPath, "reload", "--config", caddyfilePath)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("failed to reload caddy: %w\nOutput: %s", err, string(output))
@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/iheanyi/grove/internal/output"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/spf13/cobra"
+)
+
+var proxyExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Render routes as nginx, Traefik or Caddy config",
+	Long: `Render the routes of running servers as config for your own proxy.
+
+Each server gets its main host, its .grove.yaml subdomains and a wildcard
+for every other subdomain, like grove's own proxy. Rerun it after servers
+start or stop.
+
+Formats:
+  nginx     server blocks (HTTP on port 80)
+  traefik   dynamic configuration for the file provider (Traefik v3)
+  caddy     Caddyfile site blocks
+
+Examples:
+  grove proxy export --format nginx > /etc/nginx/conf.d/grove.conf
+  grove proxy export --format traefik > ~/traefik/dynamic/grove.yml
+  grove proxy export --format caddy`,
+	RunE: runProxyExport,
+}
+
+func init() {
+	proxyExportCmd.Flags().String("format", "", "Config format: nginx, traefik or caddy (required)")
+	_ = proxyExportCmd.MarkFlagRequired("format")
+	_ = proxyExportCmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"nginx", "traefik", "caddy"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	proxyCmd.AddCommand(proxyExportCmd)
+}
+
+func runProxyExport(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+
+	var render func([]output.Route) string
+	switch format {
+	case "nginx":
+		render = renderNginx
+	case "traefik":
+		render = renderTraefik
+	case "caddy":
+		render = renderCaddy
+	default:
+		return fmt.Errorf("unknown format %q (expected nginx, traefik or caddy)", format)
+	}
+
+	reg, err := registry.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+
+	fmt.Print(render(collectRoutes(reg.ListRunning(), cfg.TLD)))
+	return nil
+}
+
+// collectRoutes returns the hosts routed to each server by name, in the
+// order buildCaddyfile writes them: main host, mapped subdomains, wildcard
+func collectRoutes(servers []*registry.Server, tld string) []output.Route {
+	servers = slices.Clone(servers)
+	sort.Slice(servers, func(i, j int) bool { return servers[i].Name < servers[j].Name })
+
+	routes := []output.Route{}
+	for _, s := range servers {
+		routes = append(routes, output.Route{Host: fmt.Sprintf("%s.%s", s.Name, tld), Server: s.Name, Port: s.Port, Kind: "main"})
+		for _, sub := range sortedSubdomains(s) {
+			routes = append(routes, output.Route{Host: fmt.Sprintf("%s.%s.%s", sub, s.Name, tld), Server: s.Name, Port: s.Subdomains[sub], Kind: "subdomain"})
+		}
+		routes = append(routes, output.Route{Host: fmt.Sprintf("*.%s.%s", s.Name, tld), Server: s.Name, Port: s.Port, Kind: "wildcard"})
+	}
+	return routes
+}
+
+// renderNginx renders a server block per route. nginx prefers exact names
+// over wildcards, so mapped subdomains win over the wildcard.
+func renderNginx(routes []output.Route) string {
+	var sb strings.Builder
+	sb.WriteString("# Generated by 'grove proxy export --format nginx'\n")
+	for _, r := range routes {
+		fmt.Fprintf(&sb, "\nserver {\n")
+		fmt.Fprintf(&sb, "    listen 80;\n")
+		fmt.Fprintf(&sb, "    server_name %s;\n\n", r.Host)
+		fmt.Fprintf(&sb, "    location / {\n")
+		fmt.Fprintf(&sb, "        proxy_pass http://127.0.0.1:%d;\n", r.Port)
+		fmt.Fprintf(&sb, "        proxy_http_version 1.1;\n")
+		fmt.Fprintf(&sb, "        proxy_set_header Host $host;\n")
+		fmt.Fprintf(&sb, "        proxy_set_header Upgrade $http_upgrade;\n")
+		fmt.Fprintf(&sb, "        proxy_set_header Connection \"upgrade\";\n")
+		fmt.Fprintf(&sb, "        proxy_set_header X-Forwarded-For $proxy_add_x_forwarded_for;\n")
+		fmt.Fprintf(&sb, "        proxy_set_header X-Forwarded-Proto $scheme;\n")
+		fmt.Fprintf(&sb, "    }\n")
+		fmt.Fprintf(&sb, "}\n")
+	}
+	return sb.String()
+}
+
+// renderTraefik renders a router and service per route. Wildcards get the
+// lowest priority so exact hosts match first.
+func renderTraefik(routes []output.Route) string {
+	if len(routes) == 0 {
+		return "# Generated by 'grove proxy export --format traefik'\n# No running servers\n"
+	}
+
+	var routers, services strings.Builder
+	for _, r := range routes {
+		name := traefikName(r)
+		fmt.Fprintf(&routers, "    %s:\n", name)
+		if r.Kind == "wildcard" {
+			pattern := `^[a-z0-9-]+\.` + regexp.QuoteMeta(strings.TrimPrefix(r.Host, "*.")) + `$`
+			fmt.Fprintf(&routers, "      rule: %q\n", "HostRegexp(`"+pattern+"`)")
+			fmt.Fprintf(&routers, "      priority: 1\n")
+		} else {
+			fmt.Fprintf(&routers, "      rule: %q\n", "Host(`"+r.Host+"`)")
+		}
+		fmt.Fprintf(&routers, "      service: %s\n", name)
+
+		fmt.Fprintf(&services, "    %s:\n", name)
+		fmt.Fprintf(&services, "      loadBalancer:\n")
+		fmt.Fprintf(&services, "        servers:\n")
+		fmt.Fprintf(&services, "          - url: \"http://127.0.0.1:%d\"\n", r.Port)
+	}
+
+	return "# Generated by 'grove proxy export --format traefik'\n" +
+		"http:\n  routers:\n" + routers.String() + "  services:\n" + services.String()
+}
+
+// traefikName returns a unique router/service name for a route
+func traefikName(r output.Route) string {
+	switch r.Kind {
+	case "subdomain":
+		return "grove-" + r.Server + "-sub-" + strings.SplitN(r.Host, ".", 2)[0]
+	case "wildcard":
+		return "grove-" + r.Server + "-wildcard"
+	}
+	return "grove-" + r.Server
+}
+
+// renderCaddy renders a site block per host; the wildcard shares the main
+// host's block. Caddy matches exact hosts before wildcards.
+func renderCaddy(routes []output.Route) string {
+	var sb strings.Builder
+	sb.WriteString("# Generated by 'grove proxy export --format caddy'\n")
+	for _, r := range routes {
+		if r.Kind == "wildcard" {
+			continue
+		}
+		hosts := r.Host
+		if r.Kind == "main" {
+			hosts += ", *." + r.Host
+		}
+		fmt.Fprintf(&sb, "\n%s {\n\treverse_proxy localhost:%d\n}\n", hosts, r.Port)
+	}
+	return sb.String()
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+var exportServers = []*registry.Server{
+	{Name: "web", Port: 3002},
+	{Name: "api", Port: 3001, Subdomains: map[string]int{"admin": 3101}},
+}
+
+func TestCollectRoutes(t *testing.T) {
+	routes := collectRoutes(exportServers, "localhost")
+
+	var hosts []string
+	for _, r := range routes {
+		hosts = append(hosts, r.Host)
+	}
+	want := "api.localhost admin.api.localhost *.api.localhost web.localhost *.web.localhost"
+	if got := strings.Join(hosts, " "); got != want {
+		t.Errorf("hosts = %s, want %s", got, want)
+	}
+	if routes[1].Port != 3101 || routes[1].Kind != "subdomain" {
+		t.Errorf("subdomain route = %+v", routes[1])
+	}
+}
+
+func TestRenderExports(t *testing.T) {
+	routes := collectRoutes(exportServers, "localhost")
+
+	nginx := renderNginx(routes)
+	for _, want := range []string{
+		"server_name admin.api.localhost;\n\n    location / {\n        proxy_pass http://127.0.0.1:3101;",
+		"server_name *.web.localhost;",
+	} {
+		if !strings.Contains(nginx, want) {
+			t.Errorf("nginx config missing %q:\n%s", want, nginx)
+		}
+	}
+
+	traefik := renderTraefik(routes)
+	for _, want := range []string{
+		"    grove-api-sub-admin:\n      rule: \"Host(`admin.api.localhost`)\"\n      service: grove-api-sub-admin\n",
+		"rule: \"HostRegexp(`^[a-z0-9-]+\\\\.api\\\\.localhost$`)\"\n      priority: 1\n",
+		"    grove-web:\n      loadBalancer:\n        servers:\n          - url: \"http://127.0.0.1:3002\"\n",
+	} {
+		if !strings.Contains(traefik, want) {
+			t.Errorf("traefik config missing %q:\n%s", want, traefik)
+		}
+	}
+
+	caddy := renderCaddy(routes)
+	for _, want := range []string{
+		"\napi.localhost, *.api.localhost {\n\treverse_proxy localhost:3001\n}\n",
+		"\nadmin.api.localhost {\n\treverse_proxy localhost:3101\n}\n",
+	} {
+		if !strings.Contains(caddy, want) {
+			t.Errorf("caddy config missing %q:\n%s", want, caddy)
+		}
+	}
+}
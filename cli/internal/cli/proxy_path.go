@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/output"
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+// buildPathCaddyfile renders the Caddyfile for path URL mode: one site on
+// opts.PathHost with each server under /<name>/. The prefix is stripped
+// before proxying and sent as X-Forwarded-Prefix, and redirects to absolute
+// paths get it put back. Apps that link to absolute paths (/assets/app.js)
+// still lose the prefix, so requests outside every server's path are
+// redirected into the server the Referer came from.
+func buildPathCaddyfile(servers []*registry.Server, opts caddyfileOptions) string {
+	host := opts.PathHost
+	servers = slices.Clone(servers)
+	sort.Slice(servers, func(i, j int) bool { return servers[i].Name < servers[j].Name })
+	var sb strings.Builder
+
+	writeCaddyGlobals(&sb, opts)
+
+	sb.WriteString(fmt.Sprintf("https://%s {\n", host))
+	if opts.Cert != nil && opts.Cert.Covers(host) {
+		sb.WriteString(fmt.Sprintf("\ttls %s %s\n", opts.Cert.CertFile, opts.Cert.KeyFile))
+	}
+	writeSecurityDirectives(&sb, opts.Security, "\t")
+
+	var prefixes []string
+	for _, server := range servers {
+		prefixes = append(prefixes, "/"+server.Name+"/*")
+	}
+
+	for i, server := range servers {
+		prefix := "/" + server.Name
+
+		// The bare prefix isn't under the server's path; add the slash so
+		// relative links resolve inside it
+		sb.WriteString(fmt.Sprintf("\tredir %s %s/ 308\n", prefix, prefix))
+
+		// Absolute paths the app emitted, from one of its pages
+		sb.WriteString(fmt.Sprintf("\t@referer%d {\n", i))
+		sb.WriteString(fmt.Sprintf("\t\theader_regexp Referer ^https?://%s(:[0-9]+)?%s/\n", caddyRegexpLiteral(host), caddyRegexpLiteral(prefix)))
+		sb.WriteString(fmt.Sprintf("\t\tnot path %s\n", strings.Join(prefixes, " ")))
+		sb.WriteString("\t}\n")
+		sb.WriteString(fmt.Sprintf("\tredir @referer%d %s{uri} 307\n", i, prefix))
+
+		port := server.Port
+		directives := []string{
+			"header_up X-Forwarded-Prefix " + prefix,
+			fmt.Sprintf("header_down Location ^/ %s/", prefix),
+			fmt.Sprintf("header_down Location ^https?://localhost:%d/ %s/", server.Port, prefix),
+		}
+		wake := server.IsPaused() || (!server.IsRunning() && opts.Autostart[server.Name])
+		if wake && opts.WakePort > 0 {
+			port = opts.WakePort
+			directives = append(directives, wakeDirectives(server, server.Port)...)
+		}
+		directives = append(directives, pathCookieDirectives(opts.Cookies, prefix)...)
+
+		sb.WriteString(fmt.Sprintf("\thandle_path %s/* {\n", prefix))
+		sb.WriteString(fmt.Sprintf("\t\treverse_proxy localhost:%d {\n", port))
+		for _, d := range directives {
+			sb.WriteString("\t\t\t" + d + "\n")
+		}
+		sb.WriteString("\t\t}\n")
+		sb.WriteString("\t}\n")
+	}
+
+	sb.WriteString("\thandle {\n")
+	sb.WriteString("\t\trespond \"No server registered for this path\" 404\n")
+	sb.WriteString("\t}\n")
+	sb.WriteString("}\n")
+
+	if len(opts.LANPorts) > 0 {
+		sb.WriteString("\n")
+		writeLANSites(&sb, servers, opts)
+	}
+	return sb.String()
+}
+
+// caddyRegexpLiteral matches s literally in a Caddyfile regexp. Host and
+// server names only have dots to escape, which is done with a character
+// class because the Caddyfile would unescape a backslash.
+func caddyRegexpLiteral(s string) string {
+	return strings.ReplaceAll(s, ".", "[.]")
+}
+
+// pathCookieDirectives returns the Set-Cookie rewrites for a server in path
+// mode. Every worktree shares one host, so cookies are isolated by scoping
+// their path to the server's prefix instead of their domain.
+func pathCookieDirectives(cookies config.ProxyCookiesConfig, prefix string) []string {
+	var directives []string
+	if cookies.Isolate {
+		directives = append(directives,
+			`header_down Set-Cookie "(?i);[ ]*path=[^;]*" ""`,
+			fmt.Sprintf(`header_down Set-Cookie "$" "; Path=%s/"`, prefix),
+		)
+	}
+	if cookies.Secure {
+		directives = append(directives, cookieDirectives(config.ProxyCookiesConfig{Secure: true}, "")...)
+	}
+	return directives
+}
+
+// collectPathRoutes returns the path each running server is routed under
+// in path mode, sorted by name
+func collectPathRoutes(servers []*registry.Server, host string) []output.Route {
+	routes := []output.Route{}
+	for _, r := range collectRoutes(servers, "") {
+		if r.Kind == "main" {
+			routes = append(routes, output.Route{Host: host + "/" + r.Server + "/", Server: r.Server, Port: r.Port, Kind: "path"})
+		}
+	}
+	return routes
+}
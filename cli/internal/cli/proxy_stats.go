@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/iheanyi/grove/internal/accesslog"
+	"github.com/iheanyi/grove/internal/styles"
+	"github.com/spf13/cobra"
+)
+
+var proxyStatsCmd = &cobra.Command{
+	Use:   "stats [name]",
+	Short: "Show request counts, status codes, and latency per host",
+	Long: `Show traffic through the proxy, summarized from its access logs.
+
+For each host this shows the request count, the distribution of status
+codes, and p50/p95 latency. Access logs are written per worktree to
+<log_dir>/access/<name>.log while proxy_access_log is enabled (the default).
+
+Examples:
+  grove proxy stats                  # All hosts
+  grove proxy stats feature-auth     # Hosts routed to one worktree
+  grove proxy stats --since 1h       # Only the last hour
+  grove proxy stats --by-server      # Group subdomains under their worktree
+  grove proxy stats --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runProxyStats,
+}
+
+func init() {
+	proxyStatsCmd.Flags().String("since", "", "Only include requests newer than this (e.g. 1h, 30m, 7d)")
+	proxyStatsCmd.Flags().Bool("by-server", false, "Group hosts by worktree server")
+	proxyStatsCmd.Flags().Bool("json", false, "Output as JSON")
+	proxyCmd.AddCommand(proxyStatsCmd)
+}
+
+func runProxyStats(cmd *cobra.Command, args []string) error {
+	sinceStr, _ := cmd.Flags().GetString("since")
+	byServer, _ := cmd.Flags().GetBool("by-server")
+	outputJSON, _ := cmd.Flags().GetBool("json")
+
+	var since time.Time
+	if sinceStr != "" {
+		d, err := parseAge(sinceStr)
+		if err != nil {
+			return err
+		}
+		since = time.Now().Add(-d)
+	}
+
+	if !cfg.ProxyAccessLog {
+		fmt.Println("Proxy access logs are disabled (set proxy_access_log: true and restart the proxy)")
+		return nil
+	}
+
+	entries, err := accesslog.ReadDir(cfg.AccessLogDir())
+	if err != nil {
+		return fmt.Errorf("failed to read access logs: %w", err)
+	}
+
+	// Narrow to the hosts routed to one worktree
+	if len(args) > 0 {
+		var filtered []accesslog.Entry
+		for _, e := range entries {
+			if accesslog.ServerName(e.Host, cfg.TLD) == args[0] {
+				filtered = append(filtered, e)
+			}
+		}
+		entries = filtered
+	}
+
+	var stats []*accesslog.HostStats
+	if byServer {
+		stats = accesslog.SummarizeServers(entries, since, cfg.TLD)
+	} else {
+		stats = accesslog.Summarize(entries, since)
+	}
+
+	if outputJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	}
+
+	if len(stats) == 0 {
+		fmt.Println("No proxied requests recorded")
+		if !cfg.IsSubdomainMode() {
+			fmt.Println("\nProxy stats are only recorded in subdomain mode (url_mode: subdomain)")
+		}
+		return nil
+	}
+
+	printProxyStats(stats, byServer)
+	return nil
+}
+
+// printProxyStats prints per-host stats as a table
+func printProxyStats(stats []*accesslog.HostStats, byServer bool) {
+	rows := make([][]string, 0, len(stats))
+	for _, s := range stats {
+		last := "-"
+		if !s.Last.IsZero() {
+			last = formatAge(time.Since(s.Last)) + " ago"
+		}
+		rows = append(rows, []string{
+			s.Host,
+			strconv.Itoa(s.Requests),
+			statusCount(s, "2xx"),
+			statusCount(s, "3xx"),
+			statusCount(s, "4xx"),
+			statusCount(s, "5xx"),
+			formatLatency(s.P50),
+			formatLatency(s.P95),
+			last,
+		})
+	}
+
+	first := "HOST"
+	if byServer {
+		first = "SERVER"
+	}
+
+	t := table.New().
+		Border(lipgloss.NormalBorder()).
+		BorderRow(false).
+		BorderColumn(false).
+		BorderTop(false).
+		BorderBottom(false).
+		BorderLeft(false).
+		BorderRight(false).
+		Headers(first, "REQUESTS", "2XX", "3XX", "4XX", "5XX", "P50", "P95", "LAST").
+		Rows(rows...).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			if row == table.HeaderRow {
+				return styles.HeaderStyle
+			}
+			if col == 5 && rows[row][5] != "-" {
+				return styles.CellStyle.Foreground(styles.Error)
+			}
+			return styles.CellStyle
+		})
+
+	fmt.Println(t)
+}
+
+func statusCount(s *accesslog.HostStats, class string) string {
+	if n := s.Status[class]; n > 0 {
+		return strconv.Itoa(n)
+	}
+	return "-"
+}
+
+// formatLatency formats a latency with millisecond precision
+func formatLatency(d time.Duration) string {
+	if d < time.Millisecond {
+		return "<1ms"
+	}
+	if d < time.Second {
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+	return fmt.Sprintf("%.2fs", d.Seconds())
+}
@@ -2,8 +2,18 @@ package cli
 
 import (
 	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
 	"strings"
+	"sync/atomic"
 	"testing"
+
+	"github.com/iheanyi/grove/internal/certs"
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/project"
+	"github.com/iheanyi/grove/internal/registry"
 )
 
 // TestBuildCaddyfileContent tests the Caddyfile content generation logic
@@ -136,3 +146,390 @@ func TestIsProcessRunning(t *testing.T) {
 		t.Error("expected isProcessRunning(999999999) to return false")
 	}
 }
+
+func TestBuildCaddyfile_SubdomainRoutes(t *testing.T) {
+	servers := []*registry.Server{
+		{
+			Name: "feature",
+			Port: 3100,
+			Subdomains: map[string]int{
+				"api": 3101,
+				"app": 3100,
+			},
+		},
+	}
+
+	content := buildCaddyfile(servers, caddyfileOptions{TLD: "localhost"})
+
+	expected := []string{
+		"https://feature.localhost {\n\treverse_proxy localhost:3100\n}",
+		"https://api.feature.localhost {\n\treverse_proxy localhost:3101\n}",
+		"https://app.feature.localhost {\n\treverse_proxy localhost:3100\n}",
+		"https://*.feature.localhost {\n\treverse_proxy localhost:3100\n}",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(content, exp) {
+			t.Errorf("expected content to contain %q, got:\n%s", exp, content)
+		}
+	}
+
+	// Routed subdomains are written before the wildcard fallback
+	if strings.Index(content, "https://api.feature") > strings.Index(content, "https://*.feature") {
+		t.Errorf("expected routed subdomain before wildcard, got:\n%s", content)
+	}
+}
+
+func TestBuildCaddyfile_Certs(t *testing.T) {
+	servers := []*registry.Server{
+		{Name: "feature", Port: 3100},
+		{Name: "newer", Port: 3200},
+	}
+	cert := &certs.Cert{
+		CertFile: "/certs/test.pem",
+		KeyFile:  "/certs/test-key.pem",
+		DNSNames: []string{"*.test", "*.feature.test"},
+	}
+
+	content := buildCaddyfile(servers, caddyfileOptions{TLD: "test", Cert: cert})
+
+	tlsLine := "\ttls /certs/test.pem /certs/test-key.pem\n"
+	covered := []string{"https://feature.test {\n" + tlsLine, "https://*.feature.test {\n" + tlsLine, "https://newer.test {\n" + tlsLine}
+	for _, exp := range covered {
+		if !strings.Contains(content, exp) {
+			t.Errorf("expected content to contain %q, got:\n%s", exp, content)
+		}
+	}
+
+	// Not covered by the certificate, so Caddy's local certs are used
+	if !strings.Contains(content, "https://*.newer.test {\n\treverse_proxy localhost:3200") {
+		t.Errorf("expected uncovered site without tls directive, got:\n%s", content)
+	}
+}
+
+func TestBuildCaddyfile_AccessLog(t *testing.T) {
+	servers := []*registry.Server{
+		{Name: "feature", Port: 3100, Subdomains: map[string]int{"api": 3101}},
+	}
+
+	content := buildCaddyfile(servers, caddyfileOptions{TLD: "localhost", AccessLogDir: "/logs/access"})
+
+	logBlock := "\tlog {\n\t\toutput file /logs/access/feature.log {"
+	if n := strings.Count(content, logBlock); n != 3 {
+		t.Errorf("expected every site to log to the server's access log, found %d:\n%s", n, content)
+	}
+	if !strings.Contains(content, "\t\tformat json\n") {
+		t.Errorf("expected JSON access log format, got:\n%s", content)
+	}
+}
+
+func TestBuildCaddyfile_PausedServer(t *testing.T) {
+	servers := []*registry.Server{
+		{Name: "paused", Port: 3100, Status: registry.StatusPaused},
+		{Name: "running", Port: 3200, Status: registry.StatusRunning},
+	}
+
+	content := buildCaddyfile(servers, caddyfileOptions{TLD: "localhost", WakePort: 41000})
+
+	wake := "https://paused.localhost {\n\treverse_proxy localhost:41000 {\n\t\theader_up X-Grove-Wake paused\n\t\theader_up X-Grove-Wake-Port 3100\n\t}\n}"
+	if !strings.Contains(content, wake) {
+		t.Errorf("expected paused server to route through the wake handler, got:\n%s", content)
+	}
+	if !strings.Contains(content, "https://running.localhost {\n\treverse_proxy localhost:3200\n}") {
+		t.Errorf("expected running server to route directly, got:\n%s", content)
+	}
+
+	// Without a wake handler, paused servers keep their direct route
+	content = buildCaddyfile(servers, caddyfileOptions{TLD: "localhost"})
+	if !strings.Contains(content, "https://paused.localhost {\n\treverse_proxy localhost:3100\n}") {
+		t.Errorf("expected direct route without wake port, got:\n%s", content)
+	}
+}
+
+func TestBuildCaddyfile_PausedServerRoutes(t *testing.T) {
+	servers := []*registry.Server{
+		{Name: "paused", Port: 3100, Status: registry.StatusPaused, Subdomains: map[string]int{"api": 3101}},
+	}
+
+	content := buildCaddyfile(servers, caddyfileOptions{TLD: "localhost", WakePort: 41000})
+
+	// Each host tells the wake handler which port it's for
+	api := "https://api.paused.localhost {\n\treverse_proxy localhost:41000 {\n\t\theader_up X-Grove-Wake paused\n\t\theader_up X-Grove-Wake-Port 3101\n\t}\n}"
+	if !strings.Contains(content, api) {
+		t.Errorf("expected routed subdomain to wake with its own port, got:\n%s", content)
+	}
+	wildcard := "https://*.paused.localhost {\n\treverse_proxy localhost:41000 {\n\t\theader_up X-Grove-Wake paused\n\t\theader_up X-Grove-Wake-Port 3100\n"
+	if !strings.Contains(content, wildcard) {
+		t.Errorf("expected wildcard to wake with the server's port, got:\n%s", content)
+	}
+}
+
+func TestWakeTarget(t *testing.T) {
+	server := &registry.Server{Name: "paused", Port: 3100, Subdomains: map[string]int{"api": 3101}}
+	tests := map[string]int{
+		"3101": 3101,
+		"3100": 3100,
+		"":     3100,
+		"bad":  3100,
+		// Only the server's own ports can be reached through the wake handler
+		"22": 3100,
+	}
+	for header, want := range tests {
+		if got := wakeTarget(server, header); got != want {
+			t.Errorf("wakeTarget(%q) = %d, want %d", header, got, want)
+		}
+	}
+}
+
+func TestWakeReady(t *testing.T) {
+	var healthy atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+	serverPort := srv.Listener.Addr().(*net.TCPAddr).Port
+	server := &registry.Server{Name: "app", Port: serverPort, Path: t.TempDir()}
+	check := project.HealthCheckConfig{Path: "/health"}
+
+	// Listening isn't enough while the health check fails
+	if wakeReady(server, serverPort, check) {
+		t.Error("wakeReady = true while the health check fails")
+	}
+	healthy.Store(true)
+	if !wakeReady(server, serverPort, check) {
+		t.Error("wakeReady = false once the health check passes")
+	}
+
+	// A routed subdomain's port must be listening too
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedPort := closed.Addr().(*net.TCPAddr).Port
+	closed.Close()
+	if wakeReady(server, closedPort, check) {
+		t.Error("wakeReady = true for a port that isn't listening")
+	}
+}
+
+func TestBuildCaddyfile_Autostart(t *testing.T) {
+	servers := []*registry.Server{
+		{Name: "auto", Port: 3100, Status: registry.StatusStopped},
+		{Name: "manual", Port: 3200, Status: registry.StatusStopped},
+		{Name: "live", Port: 3300, Status: registry.StatusRunning},
+	}
+
+	content := buildCaddyfile(servers, caddyfileOptions{
+		TLD:       "localhost",
+		WakePort:  41000,
+		Autostart: map[string]bool{"auto": true, "live": true},
+	})
+
+	if !strings.Contains(content, "https://auto.localhost {\n\treverse_proxy localhost:41000 {\n\t\theader_up X-Grove-Wake auto") {
+		t.Errorf("expected stopped autostart server to route through the wake handler, got:\n%s", content)
+	}
+	if !strings.Contains(content, "https://manual.localhost {\n\treverse_proxy localhost:3200\n}") {
+		t.Errorf("expected server without autostart to route directly, got:\n%s", content)
+	}
+	if !strings.Contains(content, "https://live.localhost {\n\treverse_proxy localhost:3300\n}") {
+		t.Errorf("expected running server to route directly, got:\n%s", content)
+	}
+}
+
+func TestBuildCaddyfile_Cookies(t *testing.T) {
+	servers := []*registry.Server{
+		{Name: "feature", Port: 3100, Status: registry.StatusRunning},
+	}
+
+	content := buildCaddyfile(servers, caddyfileOptions{TLD: "localhost"})
+	if strings.Contains(content, "Set-Cookie") {
+		t.Errorf("expected cookies to pass through by default, got:\n%s", content)
+	}
+
+	content = buildCaddyfile(servers, caddyfileOptions{
+		TLD:     "localhost",
+		Cookies: config.ProxyCookiesConfig{Isolate: true, Secure: true},
+	})
+	if n := strings.Count(content, "\t\theader_down Set-Cookie \"(?i);[ ]*domain=[^;]*\" \"; Domain=feature.localhost\"\n"); n != 2 {
+		t.Errorf("expected both sites to scope cookies to the worktree, found %d:\n%s", n, content)
+	}
+}
+
+func TestBuildCaddyfile_Security(t *testing.T) {
+	servers := []*registry.Server{
+		{Name: "feature", Port: 3100, Status: registry.StatusRunning},
+	}
+
+	content := buildCaddyfile(servers, caddyfileOptions{TLD: "localhost"})
+	for _, exp := range []string{
+		"\tservers {\n\t\tstrict_sni_host on\n\t}\n",
+		"https://*.localhost, https://*.*.localhost, https://*.*.*.localhost {\n\trespond \"{host} is not a registered worktree\" 421\n}",
+		"http://*.localhost, http://*.*.localhost, http://*.*.*.localhost {\n\trespond \"{host} is not a registered worktree (worktrees are served over https)\" 421\n}",
+	} {
+		if !strings.Contains(content, exp) {
+			t.Errorf("expected content to contain %q, got:\n%s", exp, content)
+		}
+	}
+	if strings.Contains(content, "X-Frame-Options") || strings.Contains(content, "request_body") {
+		t.Errorf("expected no security headers or body limit by default, got:\n%s", content)
+	}
+
+	content = buildCaddyfile(servers, caddyfileOptions{
+		TLD: "localhost",
+		Security: config.ProxySecurityConfig{
+			AllowedHosts:      map[string]string{"app.example.test": "feature", "gone.example.test": "missing"},
+			UnknownHostStatus: 403,
+			Headers:           config.SecurityHeadersRemote,
+			MaxRequestBody:    "10MB",
+		},
+	})
+	for _, exp := range []string{
+		"https://app.example.test {\n",
+		"\trequest_body {\n\t\tmax_size 10MB\n\t}\n",
+		"\t@remote not remote_ip 127.0.0.0/8 ::1\n\theader @remote {\n\t\tX-Frame-Options SAMEORIGIN\n",
+		"\" 403\n",
+	} {
+		if !strings.Contains(content, exp) {
+			t.Errorf("expected content to contain %q, got:\n%s", exp, content)
+		}
+	}
+	if strings.Contains(content, "gone.example.test") {
+		t.Errorf("expected hosts for unregistered worktrees to be skipped, got:\n%s", content)
+	}
+
+	content = buildCaddyfile(servers, caddyfileOptions{
+		TLD:      "localhost",
+		Security: config.ProxySecurityConfig{Headers: config.SecurityHeadersAlways},
+	})
+	if strings.Contains(content, "@remote") || !strings.Contains(content, "\theader {\n\t\tX-Frame-Options SAMEORIGIN\n") {
+		t.Errorf("expected security headers on every response, got:\n%s", content)
+	}
+}
+
+func TestBuildCaddyfile_LAN(t *testing.T) {
+	servers := []*registry.Server{
+		{Name: "feature", Port: 3100, Status: registry.StatusRunning},
+		{Name: "paused", Port: 3200, Status: registry.StatusPaused},
+	}
+
+	content := buildCaddyfile(servers, caddyfileOptions{TLD: "localhost"})
+	if strings.Contains(content, "default_bind") || strings.Contains(content, "http://:") {
+		t.Errorf("expected no bind address or LAN sites by default, got:\n%s", content)
+	}
+
+	opts := caddyfileOptions{
+		TLD:      "localhost",
+		Bind:     "0.0.0.0",
+		WakePort: 9999,
+		LANPorts: map[string]int{"feature": 13100, "paused": 13200},
+		LANHosts: map[string][]string{
+			"feature": {"192.168.1.20"},
+			"paused":  {"192.168.1.20", "paused.mac.local"},
+		},
+	}
+	for _, content := range []string{
+		buildCaddyfile(servers, opts),
+		buildPathCaddyfile(servers, opts),
+	} {
+		for _, exp := range []string{
+			"{\n\tdefault_bind 0.0.0.0\n",
+			"http://192.168.1.20:13100 {\n\treverse_proxy localhost:3100\n}",
+			"http://192.168.1.20:13200, http://paused.mac.local:13200 {\n\treverse_proxy localhost:9999 {\n\t\theader_up " + wakeHeader + " paused\n\t\theader_up " + wakePortHeader + " 3200\n",
+			// Other hosts pointed at the LAN ports are refused
+			"http://:13100 {\n\trespond \"{host} is not a registered worktree\" 421\n}",
+			"http://:13200 {\n\trespond \"{host} is not a registered worktree\" 421\n}",
+		} {
+			if !strings.Contains(content, exp) {
+				t.Errorf("expected content to contain %q, got:\n%s", exp, content)
+			}
+		}
+	}
+}
+
+func TestCookieDirectives_Rewrite(t *testing.T) {
+	directives := cookieDirectives(config.ProxyCookiesConfig{Isolate: true, Secure: true}, "feature.localhost")
+
+	// Apply the replacements as Caddy would
+	rewrite := func(cookie string) string {
+		for _, d := range directives {
+			parts := strings.Split(d, `"`)
+			cookie = regexp.MustCompile(parts[1]).ReplaceAllString(cookie, parts[3])
+		}
+		return cookie
+	}
+
+	tests := []struct {
+		cookie string
+		want   string
+	}{
+		{"sid=1; Path=/; Domain=.localhost; HttpOnly", "sid=1; Path=/; Domain=feature.localhost; HttpOnly; Secure"},
+		{"sid=1; domain=localhost", "sid=1; Domain=feature.localhost; Secure"},
+		{"sid=1; Secure; SameSite=None", "sid=1; SameSite=None; Secure"},
+		{"sid=1; Path=/; secure", "sid=1; Path=/; Secure"},
+		{"sid=1", "sid=1; Secure"},
+	}
+	for _, tt := range tests {
+		if got := rewrite(tt.cookie); got != tt.want {
+			t.Errorf("rewrite(%q) = %q, want %q", tt.cookie, got, tt.want)
+		}
+	}
+}
+
+func TestBuildCaddyfile_PathMode(t *testing.T) {
+	servers := []*registry.Server{
+		{Name: "feature.auth", Port: 3200, Status: registry.StatusPaused},
+		{Name: "main", Port: 3100, Status: registry.StatusRunning, Subdomains: map[string]int{"api": 3101}},
+	}
+
+	content := buildCaddyfile(servers, caddyfileOptions{TLD: "localhost", PathHost: "grove.localhost", WakePort: 41000})
+
+	expected := []string{
+		"https://grove.localhost {\n",
+		"\tredir /main /main/ 308\n",
+		"\thandle_path /main/* {\n\t\treverse_proxy localhost:3100 {\n\t\t\theader_up X-Forwarded-Prefix /main\n\t\t\theader_down Location ^/ /main/\n\t\t\theader_down Location ^https?://localhost:3100/ /main/\n\t\t}\n\t}\n",
+		"\t\theader_regexp Referer ^https?://grove[.]localhost(:[0-9]+)?/main/\n\t\tnot path /feature.auth/* /main/*\n",
+		"\tredir @referer1 /main{uri} 307\n",
+		"\t\treverse_proxy localhost:41000 {\n\t\t\theader_up X-Forwarded-Prefix /feature.auth\n",
+		"\t\t\theader_up X-Grove-Wake feature.auth\n",
+		"\t\theader_regexp Referer ^https?://grove[.]localhost(:[0-9]+)?/feature[.]auth/\n",
+		"\thandle {\n\t\trespond \"No server registered for this path\" 404\n\t}\n",
+	}
+	for _, exp := range expected {
+		if !strings.Contains(content, exp) {
+			t.Errorf("expected content to contain %q, got:\n%s", exp, content)
+		}
+	}
+
+	// Servers aren't given their own domains or subdomains
+	for _, notExp := range []string{"https://main.localhost", "api.main", "https://*."} {
+		if strings.Contains(content, notExp) {
+			t.Errorf("expected content NOT to contain %q, got:\n%s", notExp, content)
+		}
+	}
+}
+
+func TestPathCookieDirectives_Rewrite(t *testing.T) {
+	directives := pathCookieDirectives(config.ProxyCookiesConfig{Isolate: true}, "/feature")
+
+	rewrite := func(cookie string) string {
+		for _, d := range directives {
+			parts := strings.Split(d, `"`)
+			cookie = regexp.MustCompile(parts[1]).ReplaceAllString(cookie, parts[3])
+		}
+		return cookie
+	}
+
+	tests := []struct {
+		cookie string
+		want   string
+	}{
+		{"sid=1; Path=/; HttpOnly", "sid=1; HttpOnly; Path=/feature/"},
+		{"sid=1", "sid=1; Path=/feature/"},
+	}
+	for _, tt := range tests {
+		if got := rewrite(tt.cookie); got != tt.want {
+			t.Errorf("rewrite(%q) = %q, want %q", tt.cookie, got, tt.want)
+		}
+	}
+}
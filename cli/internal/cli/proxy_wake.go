@@ -0,0 +1,267 @@
+package cli
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iheanyi/grove/internal/health"
+	"github.com/iheanyi/grove/internal/port"
+	"github.com/iheanyi/grove/internal/project"
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+const (
+	// wakeHeader tells the wake handler which server a request is for
+	wakeHeader = "X-Grove-Wake"
+	// wakePortHeader tells it which of the server's ports the request is
+	// for: its own, or a routed subdomain's
+	wakePortHeader = "X-Grove-Wake-Port"
+)
+
+// wakeDirectives returns the reverse_proxy directives routing a request for
+// a server's port through the wake handler
+func wakeDirectives(server *registry.Server, port int) []string {
+	return []string{
+		fmt.Sprintf("header_up %s %s", wakeHeader, server.Name),
+		fmt.Sprintf("header_up %s %d", wakePortHeader, port),
+	}
+}
+
+// wakeTarget returns the port a woken request goes to: the one named in
+// the header if it's one of the server's, else the server's own
+func wakeTarget(server *registry.Server, header string) int {
+	if p, err := strconv.Atoi(header); err == nil {
+		if p == server.Port {
+			return p
+		}
+		for _, routed := range server.Subdomains {
+			if routed == p {
+				return p
+			}
+		}
+	}
+	return server.Port
+}
+
+// wakeServer runs in the proxy process. Caddy routes requests for paused
+// servers, and for stopped servers with autostart enabled, here. Paused
+// servers are resumed and the request proxied straight through; stopped
+// servers are started while browsers see a "starting" page.
+type wakeServer struct {
+	mu sync.Mutex
+
+	// starting tracks autostarts in progress, and errors from failed ones
+	starting map[string]bool
+	failed   map[string]string
+}
+
+// startWakeServer listens on a random loopback port and returns it
+func startWakeServer() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+
+	srv := &http.Server{
+		Handler: &wakeServer{
+			starting: make(map[string]bool),
+			failed:   make(map[string]string),
+		},
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go srv.Serve(listener) //nolint:errcheck // Lives as long as the proxy
+
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+func (w *wakeServer) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	name := r.Header.Get(wakeHeader)
+	portHeader := r.Header.Get(wakePortHeader)
+	r.Header.Del(wakeHeader)
+	r.Header.Del(wakePortHeader)
+
+	server, err := w.wake(name)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	target := wakeTarget(server, portHeader)
+	check, timeout := wakeCheck(server)
+	if !wakeReady(server, target, check) {
+		// Browsers get a page that refreshes until the server is up; other
+		// clients are held until it is
+		if acceptsHTML(r) {
+			w.serveStartingPage(rw, server.Name)
+			return
+		}
+		deadline := time.Now().Add(timeout)
+		for !wakeReady(server, target, check) {
+			if time.Now().After(deadline) || r.Context().Err() != nil {
+				http.Error(rw, fmt.Sprintf("%s did not start in time", name), http.StatusGatewayTimeout)
+				return
+			}
+			time.Sleep(500 * time.Millisecond)
+		}
+	}
+
+	upstream := &url.URL{Scheme: "http", Host: fmt.Sprintf("localhost:%d", target)}
+	httputil.NewSingleHostReverseProxy(upstream).ServeHTTP(rw, r)
+}
+
+// wakeCheck returns the health check that says a woken server is up, as
+// its .grove.yaml configures it, and how long to wait for it to pass
+func wakeCheck(server *registry.Server) (project.HealthCheckConfig, time.Duration) {
+	var check project.HealthCheckConfig
+	if projConfig, err := project.Load(server.Path); err == nil {
+		check = projConfig.HealthCheck
+	}
+	timeout := cfg.HealthCheckTimeout
+	if check.Timeout > 0 {
+		timeout = check.Timeout
+	}
+	return check, timeout
+}
+
+// wakeReady returns true once a woken server passes its health check, the
+// same one 'grove start' waits for, and the port a request is for is
+// listening
+func wakeReady(server *registry.Server, target int, check project.HealthCheckConfig) bool {
+	if _, ok := probeStartup(check, health.Target{Name: server.Name, Port: server.Port, URL: server.URL, Dir: server.Path}); !ok {
+		return false
+	}
+	return target == server.Port || port.IsListening(target)
+}
+
+// wake resumes the named server if it's paused, or starts it if it's
+// stopped, and returns it
+func (w *wakeServer) wake(name string) (*registry.Server, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	reg, err := registry.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load registry: %w", err)
+	}
+	server, ok := reg.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("no server registered for '%s'", name)
+	}
+
+	switch {
+	case server.IsPaused():
+		log.Printf("Resuming '%s' for incoming request", name)
+		if err := resumeServer(reg, server); err != nil {
+			return nil, err
+		}
+	case !server.IsRunning() && !w.starting[name]:
+		log.Printf("Starting '%s' for incoming request", name)
+		w.starting[name] = true
+		delete(w.failed, name)
+		go w.autostart(server)
+	}
+	return server, nil
+}
+
+// autostart starts a stopped server with its stored command by running
+// 'grove start' in its worktree
+func (w *wakeServer) autostart(server *registry.Server) {
+	executable, err := os.Executable()
+	if err != nil {
+		w.finishStart(server.Name, err)
+		return
+	}
+
+	args := []string{"start"}
+	if len(server.Command) > 0 && !server.IsMultiProcess() && !server.IsCompose() {
+		args = append(append(args, "--"), server.Command...)
+	}
+
+	cmd := exec.Command(executable, args...)
+	cmd.Dir = server.Path
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		err = fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+	w.finishStart(server.Name, err)
+}
+
+func (w *wakeServer) finishStart(name string, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.starting, name)
+	if err != nil {
+		log.Printf("Failed to start '%s': %v", name, err)
+		w.failed[name] = err.Error()
+	}
+}
+
+// serveStartingPage renders a page that reloads until the server is up
+func (w *wakeServer) serveStartingPage(rw http.ResponseWriter, name string) {
+	w.mu.Lock()
+	failure := w.failed[name]
+	w.mu.Unlock()
+
+	rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+	rw.Header().Set("Cache-Control", "no-store")
+
+	if failure != "" {
+		rw.WriteHeader(http.StatusBadGateway)
+		fmt.Fprintf(rw, startingPageHTML, "",
+			"Failed to start "+html.EscapeString(name),
+			"<pre>"+html.EscapeString(failure)+"</pre><p>Check <code>grove logs "+html.EscapeString(name)+"</code>, then reload to retry.</p>")
+		return
+	}
+
+	rw.Header().Set("Retry-After", "1")
+	rw.WriteHeader(http.StatusServiceUnavailable)
+	fmt.Fprintf(rw, startingPageHTML, `<meta http-equiv="refresh" content="1">`,
+		"Starting "+html.EscapeString(name)+"…",
+		"<p>grove is starting this server. The page will reload when it's ready.</p>")
+}
+
+const startingPageHTML = `<!doctype html>
+<html>
+<head>
+<meta charset="utf-8">
+%s
+<title>grove</title>
+<style>
+body { font-family: -apple-system, system-ui, sans-serif; background: #0f172a; color: #e2e8f0; display: flex; align-items: center; justify-content: center; min-height: 100vh; margin: 0; }
+main { max-width: 40rem; padding: 2rem; }
+pre { white-space: pre-wrap; background: #1e293b; padding: 1rem; border-radius: 0.5rem; }
+code { background: #1e293b; padding: 0.1rem 0.3rem; border-radius: 0.25rem; }
+</style>
+</head>
+<body><main><h1>%s</h1>%s</main></body>
+</html>
+`
+
+// acceptsHTML returns true for browser navigation requests
+func acceptsHTML(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/html")
+}
+
+// autostartServers returns the names of servers whose .grove.yaml enables
+// autostart
+func autostartServers(servers []*registry.Server) map[string]bool {
+	enabled := make(map[string]bool)
+	for _, server := range servers {
+		if projConfig, err := project.Load(server.Path); err == nil && projConfig.Autostart {
+			enabled[server.Name] = true
+		}
+	}
+	return enabled
+}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -263,17 +264,13 @@ func runPrune(cmd *cobra.Command, args []string) error {
 			fmt.Printf("  Removing %s... ", wt.Name)
 
 			// Remove git worktree
-			gitCmd := exec.Command("git", "worktree", "remove", wt.Path, "--force")
-			gitCmd.Dir = mainRepoPath
-			if err := gitCmd.Run(); err != nil {
+			if err := gitOps().RemoveWorktree(context.Background(), mainRepoPath, wt.Path, true); err != nil {
 				fmt.Printf("FAILED: %v\n", err)
 				continue
 			}
 
 			// Delete the local branch (ignore error - branch might already be deleted)
-			branchCmd := exec.Command("git", "branch", "-D", wt.Branch)
-			branchCmd.Dir = mainRepoPath
-			if err := branchCmd.Run(); err != nil {
+			if err := gitOps().DeleteBranch(context.Background(), mainRepoPath, wt.Branch, true); err != nil {
 				// Not fatal - branch may have been deleted already
 				fmt.Printf("(branch already deleted) ")
 			}
@@ -291,9 +288,7 @@ func runPrune(cmd *cobra.Command, args []string) error {
 		}
 
 		// Clean up git worktree metadata
-		pruneMetadataCmd := exec.Command("git", "worktree", "prune")
-		pruneMetadataCmd.Dir = mainRepoPath
-		if err := pruneMetadataCmd.Run(); err != nil {
+		if err := gitOps().PruneWorktrees(context.Background(), mainRepoPath); err != nil {
 			fmt.Printf("Warning: failed to prune git worktree metadata: %v\n", err)
 		}
 	}
@@ -304,42 +299,15 @@ func runPrune(cmd *cobra.Command, args []string) error {
 
 // listWorktrees returns all worktrees in the repository
 func listWorktrees(mainRepoPath string) ([]worktreeEntry, error) {
-	cmd := exec.Command("git", "worktree", "list", "--porcelain")
-	cmd.Dir = mainRepoPath
-	output, err := cmd.Output()
+	list, err := gitOps().Worktrees(context.Background(), mainRepoPath)
 	if err != nil {
 		return nil, err
 	}
 
-	var worktrees []worktreeEntry
-	var current worktreeEntry
-
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-
-		if strings.HasPrefix(line, "worktree ") {
-			if current.Path != "" {
-				current.Name = filepath.Base(current.Path)
-				worktrees = append(worktrees, current)
-			}
-			current = worktreeEntry{
-				Path: strings.TrimPrefix(line, "worktree "),
-			}
-		} else if strings.HasPrefix(line, "branch ") {
-			branchRef := strings.TrimPrefix(line, "branch ")
-			parts := strings.Split(branchRef, "/")
-			if len(parts) >= 3 {
-				current.Branch = strings.Join(parts[2:], "/")
-			}
-		}
-	}
-
-	if current.Path != "" {
-		current.Name = filepath.Base(current.Path)
-		worktrees = append(worktrees, current)
+	worktrees := make([]worktreeEntry, 0, len(list))
+	for _, wt := range list {
+		worktrees = append(worktrees, worktreeEntry{Path: wt.Path, Branch: wt.Branch, Name: filepath.Base(wt.Path)})
 	}
-
 	return worktrees, nil
 }
 
@@ -354,7 +322,8 @@ func isBranchMerged(repoPath, branch, baseBranch string) (bool, error) {
 
 	lines := strings.Split(string(output), "\n")
 	for _, line := range lines {
-		branchName := strings.TrimSpace(strings.TrimPrefix(line, "*"))
+		// "*" marks the current branch, "+" a branch checked out in another worktree
+		branchName := strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "*+"))
 		if branchName == branch {
 			return true, nil
 		}
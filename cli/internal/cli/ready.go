@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/iheanyi/grove/internal/execx"
+	"github.com/iheanyi/grove/internal/health"
+	"github.com/iheanyi/grove/internal/project"
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+// readyPattern compiles the project's ready_log_pattern, returning nil when
+// it doesn't set one
+func readyPattern(projConfig *project.Config) (*regexp.Regexp, error) {
+	if projConfig == nil || projConfig.ReadyLogPattern == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile(projConfig.ReadyLogPattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ready_log_pattern: %w", err)
+	}
+	return re, nil
+}
+
+// readyTimeout is how long a server may take to print its ready line
+func readyTimeout(projConfig *project.Config) time.Duration {
+	if projConfig != nil && projConfig.ReadyTimeout > 0 {
+		return projConfig.ReadyTimeout
+	}
+	return cfg.HealthCheckTimeout
+}
+
+// markStarted records a starting server as running: healthy when its ready
+// line appeared, or with its health unknown when waiting for it timed out.
+// It leaves the server alone if it was stopped or restarted meanwhile.
+func markStarted(name string, pid int, ready bool) error {
+	reg, err := registry.Load()
+	if err != nil {
+		return err
+	}
+	server, ok := reg.Get(name)
+	if !ok || server.PID != pid || server.Status != registry.StatusStarting {
+		return nil
+	}
+
+	server.Status = registry.StatusRunning
+	if ready {
+		server.Health = registry.HealthHealthy
+		server.LastHealthCheck = time.Now()
+	}
+	return reg.Set(server)
+}
+
+// waitForReadyLog follows a daemonized server's log from offset until a
+// line matches its ready pattern, then marks it running and healthy. If no
+// line matches within timeout it's marked running anyway; if it exits
+// first, it's marked crashed.
+func waitForReadyLog(server *registry.Server, proc execx.Process, ready *regexp.Regexp, offset int64, timeout time.Duration) error {
+	fmt.Printf("Waiting for output matching '%s'...\n", ready)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	matched := make(chan error, 1)
+	go func() {
+		matched <- health.WaitForLog(ctx, server.LogFile, offset, ready, 100*time.Millisecond)
+	}()
+	exited := make(chan error, 1)
+	go func() {
+		exited <- proc.Wait()
+	}()
+
+	select {
+	case err := <-matched:
+		if err == nil {
+			server.Status = registry.StatusRunning
+			server.Health = registry.HealthHealthy
+			return markStarted(server.Name, server.PID, true)
+		}
+		fmt.Printf("Warning: no output matched ready_log_pattern within %s; marking '%s' running\n", timeout, server.Name)
+	case err := <-exited:
+		reg, loadErr := registry.Load()
+		if loadErr == nil {
+			if s, ok := reg.Get(server.Name); ok && s.PID == server.PID {
+				s.Status = registry.StatusCrashed
+				s.PID = 0
+				s.StoppedAt = time.Now()
+				reg.Set(s) //nolint:errcheck // Best effort status update
+			}
+		}
+		if err == nil {
+			err = errors.New("exit status 0")
+		}
+		return fmt.Errorf("server exited before it was ready (%v)\nCheck logs: %s", err, server.LogFile)
+	}
+
+	server.Status = registry.StatusRunning
+	return markStarted(server.Name, server.PID, false)
+}
@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"strings"
+	"time"
+
+	"github.com/iheanyi/grove/internal/project"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var reloadCmd = &cobra.Command{
+	Use:   "reload [name]",
+	Short: "Apply .grove.yaml changes to a running server",
+	Long: `Re-read .grove.yaml for a running server and apply what changed.
+
+Subdomain routes are updated in the registry and the proxy straight away.
+Hooks and health checks are read from .grove.yaml each time they run, so
+they need nothing more than a reload's check that the file is valid.
+
+A changed command, processes or environment (env, env_files, database,
+depends_on) only takes effect when the process starts again. grove reports
+those changes; with --restart it restarts the server, but only if one of
+them changed.
+
+Examples:
+  grove reload                  # Reload the current worktree's server
+  grove reload feature-auth     # Reload a server by name
+  grove reload --restart        # Also restart if the command or env changed`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runReload,
+}
+
+func init() {
+	reloadCmd.Flags().Bool("restart", false, "Restart the server if its command or environment changed")
+	reloadCmd.Flags().DurationP("timeout", "t", 10*time.Second, "Timeout for graceful shutdown when restarting")
+}
+
+// reloadChanges is what differs between a running server and its
+// .grove.yaml
+type reloadChanges struct {
+	Command    bool
+	Env        bool
+	Subdomains bool
+}
+
+// NeedsRestart reports whether the changes only apply to a new process
+func (c reloadChanges) NeedsRestart() bool {
+	return c.Command || c.Env
+}
+
+func runReload(cmd *cobra.Command, args []string) error {
+	restart, _ := cmd.Flags().GetBool("restart")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	var name string
+	if len(args) > 0 {
+		name = args[0]
+	} else {
+		wt, err := worktree.Detect()
+		if err != nil {
+			return fmt.Errorf("failed to detect worktree: %w", err)
+		}
+		name = wt.Name
+	}
+
+	reg, err := registry.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+	server, ok := reg.Get(name)
+	if !ok {
+		return fmt.Errorf("no server registered for '%s'", name)
+	}
+	if !server.IsRunning() {
+		return fmt.Errorf("server '%s' is not running\nUse 'grove start' to start it", name)
+	}
+
+	projConfig, err := project.Load(server.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		projConfig = nil
+	} else if err != nil {
+		return err
+	}
+	projConfig = applyDatabaseEnv(server.Path, projConfig)
+	applyDependencyEnv(projConfig, reg)
+
+	changes, updated, err := diffReload(reg, server, projConfig)
+	if err != nil {
+		return err
+	}
+
+	if changes.Subdomains {
+		if err := reg.Set(updated); err != nil {
+			return fmt.Errorf("failed to save to registry: %w", err)
+		}
+		if cfg.UsesProxy() {
+			if err := ReloadProxy(); err != nil {
+				fmt.Printf("Warning: failed to reload proxy: %v\n", err)
+			}
+		}
+		fmt.Println("Updated subdomain routes")
+	}
+	if changes.Command {
+		fmt.Println("Command changed")
+	}
+	if changes.Env {
+		fmt.Println("Environment changed")
+	}
+	if !changes.NeedsRestart() {
+		if !changes.Subdomains {
+			fmt.Printf("'%s' is up to date with %s\n", name, project.ConfigFileName)
+		}
+		return nil
+	}
+	if !restart {
+		fmt.Printf("Run 'grove reload --restart' or 'grove restart %s' to apply\n", name)
+		return nil
+	}
+	return restartServer(cmd, reg, updated, nil, timeout)
+}
+
+// diffReload compares a running server with its project config. It returns
+// what changed and a copy of the server with the config's subdomain routes.
+func diffReload(reg *registry.Registry, server *registry.Server, projConfig *project.Config) (reloadChanges, *registry.Server, error) {
+	var changes reloadChanges
+	updated := *server
+
+	changes.Command = commandChanged(server, projConfig)
+	if server.EnvHash != "" {
+		changes.Env = envHash(serverEnv(server, projConfig, server.Port)) != server.EnvHash
+	}
+
+	// Ports the server already holds are free to reuse
+	used := make(map[int]bool)
+	for _, s := range reg.ListRunning() {
+		if s.Name == server.Name {
+			continue
+		}
+		used[s.Port] = true
+		for _, p := range s.Subdomains {
+			used[p] = true
+		}
+	}
+	if _, err := assignPorts(&updated, projConfig, used); err != nil {
+		return changes, nil, err
+	}
+
+	// Routes to a process that's already running go to its current port
+	if projConfig != nil {
+		for sub, target := range projConfig.Subdomains {
+			for _, proc := range server.Processes {
+				if proc.Name == strings.TrimSpace(target) && proc.Port > 0 {
+					updated.Subdomains[sub] = proc.Port
+				}
+			}
+		}
+	}
+	changes.Subdomains = !maps.Equal(server.Subdomains, updated.Subdomains)
+
+	return changes, &updated, nil
+}
+
+// commandChanged reports whether .grove.yaml would start a server with a
+// different command or processes than it's running. Compose servers and
+// configs without a command can't tell.
+func commandChanged(server *registry.Server, projConfig *project.Config) bool {
+	if projConfig == nil || server.IsCompose() || projConfig.IsCompose() {
+		return false
+	}
+
+	if projConfig.HasProcesses() {
+		running := make(map[string]string, len(server.Processes))
+		for _, proc := range server.Processes {
+			running[proc.Name] = proc.Command
+		}
+		return !maps.Equal(running, projConfig.Processes)
+	}
+
+	if projConfig.Command == "" {
+		return false
+	}
+	return len(server.Processes) > 0 || strings.Join(server.Command, " ") != projConfig.Command
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/iheanyi/grove/internal/project"
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+func TestEnvHash(t *testing.T) {
+	a := envHash([]string{"A=1", "B=2", "A=3"})
+	if b := envHash([]string{"B=2", "A=3"}); a != b {
+		t.Errorf("envHash() should only depend on resolved vars: %s != %s", a, b)
+	}
+	if c := envHash([]string{"A=1", "B=2"}); a == c {
+		t.Error("envHash() should change when a value changes")
+	}
+}
+
+func TestDiffReload(t *testing.T) {
+	reg := useTestEnv(t)
+	dir := t.TempDir()
+
+	projConfig := &project.Config{Command: "npm run dev", Env: map[string]string{"MODE": "dev"}}
+	server := &registry.Server{
+		Name:    "feature",
+		Port:    3100,
+		Path:    dir,
+		Command: []string{"npm run dev"},
+		Status:  registry.StatusRunning,
+	}
+	server.EnvHash = envHash(serverEnv(server, projConfig, server.Port))
+	if err := reg.Set(server); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, _, err := diffReload(reg, server, projConfig)
+	if err != nil {
+		t.Fatalf("diffReload() failed: %v", err)
+	}
+	if changes != (reloadChanges{}) {
+		t.Errorf("expected no changes, got %+v", changes)
+	}
+
+	projConfig.Env["MODE"] = "test"
+	projConfig.Subdomains = map[string]string{"api": ":3101"}
+	changes, updated, err := diffReload(reg, server, projConfig)
+	if err != nil {
+		t.Fatalf("diffReload() failed: %v", err)
+	}
+	if !changes.Env || !changes.Subdomains || changes.Command {
+		t.Errorf("expected env and subdomain changes, got %+v", changes)
+	}
+	if updated.Subdomains["api"] != 3101 {
+		t.Errorf("expected api -> 3101, got %v", updated.Subdomains)
+	}
+	if server.Subdomains != nil {
+		t.Error("diffReload() should not modify the running server")
+	}
+
+	projConfig.Command = "bin/dev"
+	if changes, _, _ := diffReload(reg, server, projConfig); !changes.Command || !changes.NeedsRestart() {
+		t.Errorf("expected a command change, got %+v", changes)
+	}
+}
+
+func TestCommandChanged_Processes(t *testing.T) {
+	server := &registry.Server{
+		Name:      "feature",
+		Processes: []registry.Process{{Name: "web", Command: "bin/rails s"}, {Name: "jobs", Command: "bin/sidekiq"}},
+	}
+
+	projConfig := &project.Config{Processes: map[string]string{"web": "bin/rails s", "jobs": "bin/sidekiq"}}
+	if commandChanged(server, projConfig) {
+		t.Error("expected unchanged processes")
+	}
+
+	projConfig.Processes["assets"] = "bin/vite dev"
+	if !commandChanged(server, projConfig) {
+		t.Error("expected an added process to count as a change")
+	}
+
+	if commandChanged(server, &project.Config{}) {
+		t.Error("a config without a command can't tell")
+	}
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/iheanyi/grove/internal/execx"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// remoteGrove returns a command running grove with args on host over SSH.
+// The remote grove must be on the PATH of host's non-interactive shell.
+func remoteGrove(host string, args ...string) *execx.Cmd {
+	// ssh joins its arguments into one command line for the remote shell,
+	// so they're quoted here
+	return execx.Command("ssh", "-o", "BatchMode=yes", host, "grove "+shellQuoteArgs(args))
+}
+
+// runRemote runs the same command on host, with the flags set here other
+// than --remote, and passes its output through
+func runRemote(cmd *cobra.Command, host string, args []string) error {
+	path := strings.Fields(cmd.CommandPath())[1:]
+	remoteArgs := append(append(path, args...), changedFlags(cmd, "remote")...)
+
+	c := remoteGrove(host, remoteArgs...)
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := runner.Run(cmd.Context(), c); err != nil {
+		return fmt.Errorf("grove %s on %s failed: %w", strings.Join(path, " "), host, err)
+	}
+	return nil
+}
+
+// changedFlags returns the flags set on the command line as --name=value
+// arguments, leaving out the excluded ones
+func changedFlags(cmd *cobra.Command, exclude ...string) []string {
+	var args []string
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		for _, name := range exclude {
+			if f.Name == name {
+				return
+			}
+		}
+		if slice, ok := f.Value.(pflag.SliceValue); ok {
+			for _, v := range slice.GetSlice() {
+				args = append(args, fmt.Sprintf("--%s=%s", f.Name, v))
+			}
+			return
+		}
+		args = append(args, fmt.Sprintf("--%s=%s", f.Name, f.Value.String()))
+	})
+	return args
+}
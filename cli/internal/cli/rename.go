@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var renameCmd = &cobra.Command{
+	Use:   "rename <old> <new>",
+	Short: "Rename a registered worktree",
+	Long: `Rename a worktree's registry entry, e.g. to fix a badly sanitized name,
+without deleting and re-registering it.
+
+The rename updates the entry's URL and proxy route, and moves the server's
+log, share log, access log and crash reports to the new name. Tags, review
+state and the rest of the entry are kept.
+
+With --branch, the worktree's git branch is renamed too. With --move-dir,
+the worktree directory is moved next to where it is now, named <new>.
+
+The server must be stopped first. Groups in the global config that list the
+old name aren't changed; grove prints which ones to update.
+
+Examples:
+  grove rename feature-auth-v2-2 auth-v2
+  grove rename feature-auth auth --branch feature/auth-v2
+  grove rename feature-auth auth --move-dir`,
+	Args: cobra.ExactArgs(2),
+	RunE: runRename,
+}
+
+func init() {
+	renameCmd.Flags().String("branch", "", "Also rename the worktree's git branch to this")
+	renameCmd.Flags().Bool("move-dir", false, "Also move the worktree directory to a sibling named <new>")
+}
+
+// renameOptions are the optional parts of a rename
+type renameOptions struct {
+	// Branch is the git branch's new name; empty leaves it alone
+	Branch string
+	// MoveDir moves the worktree to a sibling directory named after the
+	// new name
+	MoveDir bool
+}
+
+func runRename(cmd *cobra.Command, args []string) error {
+	from, to := args[0], args[1]
+	var opts renameOptions
+	opts.Branch, _ = cmd.Flags().GetString("branch")
+	opts.MoveDir, _ = cmd.Flags().GetBool("move-dir")
+
+	reg, err := registry.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+
+	if err := renameEntry(reg, from, to, opts); err != nil {
+		return err
+	}
+	fmt.Printf("Renamed %s → %s\n", from, to)
+
+	for _, group := range groupsListing(cfg.Groups, from) {
+		fmt.Printf("Note: group '%s' lists '%s'; update it in %s\n", group, from, config.ConfigPath())
+	}
+	if cfg.UsesProxy() {
+		if err := ReloadProxy(); err != nil {
+			fmt.Printf("Warning: failed to reload proxy: %v\n", err)
+		}
+	}
+	return nil
+}
+
+// renameEntry renames a registered worktree, and its branch and directory
+// when opts asks. Everything that can be is checked before anything
+// changes; if a step fails anyway, the branch rename and move before it are
+// undone, so git and the registry still agree.
+func renameEntry(reg *registry.Registry, from, to string, opts renameOptions) error {
+	ws, ok := reg.GetWorkspace(from)
+	if !ok {
+		return fmt.Errorf("worktree '%s' not found in registry", from)
+	}
+	if to == from {
+		return fmt.Errorf("'%s' already has that name", from)
+	}
+	if !worktree.IsValidName(to) {
+		return fmt.Errorf("invalid name '%s': use lowercase letters, numbers and single hyphens, starting with a letter (e.g. '%s')", to, worktree.Sanitize(to))
+	}
+	if _, taken := reg.GetWorkspace(to); taken {
+		return fmt.Errorf("a worktree named '%s' is already registered", to)
+	}
+	if ws.IsRunning() || (ws.Server != nil && ws.Server.Status == registry.StatusStarting) {
+		return fmt.Errorf("server '%s' is running; stop it first with 'grove stop %s'", from, from)
+	}
+	if ws.Share != nil {
+		return fmt.Errorf("'%s' is shared; stop sharing first with 'grove share stop %s'", from, from)
+	}
+
+	var newPath, mainRepo string
+	if opts.MoveDir {
+		mainRepo = ws.MainRepo
+		if info, err := worktree.DetectAt(ws.Path); err == nil && info.IsWorktree && info.MainWorktreePath != "" {
+			mainRepo = info.MainWorktreePath
+		}
+		if mainRepo == "" || mainRepo == ws.Path {
+			return fmt.Errorf("cannot move the main worktree")
+		}
+		newPath = filepath.Join(filepath.Dir(ws.Path), to)
+		if fileExists(newPath) {
+			return fmt.Errorf("cannot move the worktree: %s exists", newPath)
+		}
+	}
+
+	oldBranch, oldPath := ws.Branch, ws.Path
+	var undo []func() error
+	rollback := func(err error) error {
+		for i := len(undo) - 1; i >= 0; i-- {
+			if undoErr := undo[i](); undoErr != nil {
+				fmt.Printf("Warning: failed to undo: %v\n", undoErr)
+			}
+		}
+		ws.Branch, ws.Path = oldBranch, oldPath
+		return err
+	}
+
+	if opts.Branch != "" {
+		fmt.Printf("Renaming branch %s → %s... ", ws.Branch, opts.Branch)
+		if err := runGit(ws.Path, "branch", "-m", opts.Branch); err != nil {
+			fmt.Println("failed")
+			return fmt.Errorf("failed to rename branch: %w", err)
+		}
+		fmt.Println("done")
+		ws.Branch = opts.Branch
+		if oldBranch != "" {
+			// Runs after the move is undone, so in the old directory
+			undo = append(undo, func() error {
+				return runGit(oldPath, "branch", "-m", opts.Branch, oldBranch)
+			})
+		}
+	}
+
+	if opts.MoveDir {
+		fmt.Printf("Moving worktree to %s... ", newPath)
+		if err := gitOps().MoveWorktree(context.Background(), mainRepo, ws.Path, newPath); err != nil {
+			fmt.Println("failed")
+			return rollback(fmt.Errorf("failed to move worktree: %w", err))
+		}
+		fmt.Println("done")
+		ws.Path = newPath
+		undo = append(undo, func() error {
+			return gitOps().MoveWorktree(context.Background(), mainRepo, newPath, oldPath)
+		})
+	}
+
+	if err := applyNameMigrations(reg, []nameMigration{{From: from, To: to, Path: ws.Path}}, cfg.LogDir, config.CrashesDir()); err != nil {
+		return rollback(err)
+	}
+	return nil
+}
+
+// runGit runs a git command in dir, returning its output as the error when it
+// fails
+func runGit(dir string, args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(output)); msg != "" {
+			return fmt.Errorf("%s", msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// groupsListing returns the groups with name as a member, sorted
+func groupsListing(groups map[string][]string, name string) []string {
+	var listing []string
+	for group, members := range groups {
+		for _, member := range members {
+			if member == name {
+				listing = append(listing, group)
+				break
+			}
+		}
+	}
+	sort.Strings(listing)
+	return listing
+}
@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+func gitRun(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=grove", "GIT_AUTHOR_EMAIL=grove@example.com",
+		"GIT_COMMITTER_NAME=grove", "GIT_COMMITTER_EMAIL=grove@example.com")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestRenameEntry(t *testing.T) {
+	reg := useTestEnv(t)
+
+	logFile := filepath.Join(cfg.LogDir, "feature-auth-2.log")
+	if err := os.WriteFile(logFile, []byte("log"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	reg.SetWorkspaceWithoutSave(&registry.Workspace{
+		Name: "feature-auth-2",
+		Path: t.TempDir(),
+		Tags: []string{"frontend"},
+		Server: &registry.ServerState{
+			Port:    3100,
+			Status:  registry.StatusStopped,
+			URL:     cfg.ServerURL("feature-auth-2", 3100),
+			LogFile: logFile,
+		},
+	})
+	reg.SetWorkspaceWithoutSave(&registry.Workspace{Name: "api", Path: t.TempDir()})
+	reg.SetWorkspaceWithoutSave(&registry.Workspace{
+		Name:   "web",
+		Path:   t.TempDir(),
+		Server: &registry.ServerState{Port: 3200, PID: os.Getpid(), Status: registry.StatusRunning},
+	})
+
+	for _, tc := range []struct{ from, to, want string }{
+		{"missing", "other", "not found"},
+		{"feature-auth-2", "Feature_Auth", "invalid name"},
+		{"feature-auth-2", "api", "already registered"},
+		{"web", "web-app", "stop it first"},
+	} {
+		err := renameEntry(reg, tc.from, tc.to, renameOptions{})
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("rename %s → %s: got %v, want an error containing %q", tc.from, tc.to, err, tc.want)
+		}
+	}
+
+	if err := renameEntry(reg, "feature-auth-2", "auth", renameOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := reg.GetWorkspace("feature-auth-2"); ok {
+		t.Error("old entry should be removed")
+	}
+	ws, ok := reg.GetWorkspace("auth")
+	if !ok {
+		t.Fatal("renamed entry missing")
+	}
+	if ws.Server.URL != cfg.ServerURL("auth", 3100) || !ws.HasTag("frontend") {
+		t.Errorf("renamed entry = %+v, %+v", ws, ws.Server)
+	}
+	if want := filepath.Join(cfg.LogDir, "auth.log"); ws.Server.LogFile != want || !fileExists(want) {
+		t.Errorf("log file = %q, want %q moved", ws.Server.LogFile, want)
+	}
+}
+
+func TestRenameEntryBranchAndDir(t *testing.T) {
+	reg := useTestEnv(t)
+
+	root := t.TempDir()
+	repo := filepath.Join(root, "app")
+	if err := os.Mkdir(repo, 0755); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, repo, "init", "-q", "-b", "main")
+	gitRun(t, repo, "commit", "-q", "--allow-empty", "-m", "init")
+	wtPath := filepath.Join(root, "app-feature-auth")
+	gitRun(t, repo, "worktree", "add", "-q", "-b", "feature/auth", wtPath)
+
+	reg.SetWorkspaceWithoutSave(&registry.Workspace{Name: "app-feature-auth", Path: wtPath, Branch: "feature/auth", MainRepo: repo})
+
+	opts := renameOptions{Branch: "feature/auth-v2", MoveDir: true}
+	if err := renameEntry(reg, "app-feature-auth", "auth-v2", opts); err != nil {
+		t.Fatal(err)
+	}
+
+	ws, ok := reg.GetWorkspace("auth-v2")
+	if !ok {
+		t.Fatal("renamed entry missing")
+	}
+	wantPath := filepath.Join(root, "auth-v2")
+	if ws.Path != wantPath || ws.Branch != "feature/auth-v2" {
+		t.Errorf("path, branch = %q, %q, want %q, feature/auth-v2", ws.Path, ws.Branch, wantPath)
+	}
+	if got := gitRun(t, wantPath, "branch", "--show-current"); got != "feature/auth-v2" {
+		t.Errorf("moved worktree is on %q", got)
+	}
+
+	// The main worktree can't be moved
+	reg.SetWorkspaceWithoutSave(&registry.Workspace{Name: "app", Path: repo})
+	if err := renameEntry(reg, "app", "application", renameOptions{MoveDir: true}); err == nil {
+		t.Error("moving the main worktree should fail")
+	}
+}
+
+func TestRenameEntryUndoesOnFailure(t *testing.T) {
+	reg := useTestEnv(t)
+
+	root := t.TempDir()
+	repo := filepath.Join(root, "app")
+	if err := os.Mkdir(repo, 0755); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, repo, "init", "-q", "-b", "main")
+	gitRun(t, repo, "commit", "-q", "--allow-empty", "-m", "init")
+	wtPath := filepath.Join(root, "app-feature-auth")
+	gitRun(t, repo, "worktree", "add", "-q", "-b", "feature/auth", wtPath)
+	// A locked worktree can't be moved, so the move fails after the branch
+	// rename
+	gitRun(t, repo, "worktree", "lock", wtPath)
+
+	reg.SetWorkspaceWithoutSave(&registry.Workspace{Name: "app-feature-auth", Path: wtPath, Branch: "feature/auth", MainRepo: repo})
+
+	opts := renameOptions{Branch: "feature/auth-v2", MoveDir: true}
+	if err := renameEntry(reg, "app-feature-auth", "auth-v2", opts); err == nil {
+		t.Fatal("moving a locked worktree should fail")
+	}
+
+	ws, ok := reg.GetWorkspace("app-feature-auth")
+	if !ok {
+		t.Fatal("entry should keep its old name")
+	}
+	if ws.Path != wtPath || ws.Branch != "feature/auth" {
+		t.Errorf("path, branch = %q, %q, want %q, feature/auth", ws.Path, ws.Branch, wtPath)
+	}
+	if got := gitRun(t, wtPath, "branch", "--show-current"); got != "feature/auth" {
+		t.Errorf("branch rename not undone: worktree is on %q", got)
+	}
+}
+
+func TestGroupsListing(t *testing.T) {
+	groups := map[string][]string{
+		"shop":  {"web", "api"},
+		"admin": {"api"},
+		"docs":  {"docs"},
+	}
+	if got := strings.Join(groupsListing(groups, "api"), ","); got != "admin,shop" {
+		t.Errorf("groupsListing = %q, want admin,shop", got)
+	}
+}
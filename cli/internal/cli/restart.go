@@ -5,6 +5,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/iheanyi/grove/internal/metrics"
 	"github.com/iheanyi/grove/internal/registry"
 	"github.com/iheanyi/grove/internal/worktree"
 	"github.com/spf13/cobra"
@@ -59,8 +60,19 @@ func runRestart(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("server '%s' is not running\nUse 'grove start' to start it", name)
 	}
 
-	// Remember the command and path for restart
+	// Restart with the same command. Compose servers are started from
+	// .grove.yaml again.
 	command := server.Command
+	if server.IsCompose() {
+		command = nil
+	}
+	return restartServer(cmd, reg, server, command, timeout)
+}
+
+// restartServer stops a running server and starts it again from its
+// directory with command (nil to use .grove.yaml)
+func restartServer(cmd *cobra.Command, reg *registry.Registry, server *registry.Server, command []string, timeout time.Duration) error {
+	name := server.Name
 	serverPath := server.Path
 
 	// Stop the server
@@ -69,6 +81,8 @@ func runRestart(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to stop server: %w", err)
 	}
 
+	metrics.RecordRestart(name)
+
 	// Wait a moment for port to be released
 	time.Sleep(500 * time.Millisecond)
 
@@ -83,7 +97,6 @@ func runRestart(cmd *cobra.Command, args []string) error {
 	}
 	defer os.Chdir(originalDir) //nolint:errcheck
 
-	// Start the server with the same command
 	fmt.Println("Starting server...")
 	return runStart(cmd, command)
 }
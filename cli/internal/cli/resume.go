@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/events"
+	"github.com/iheanyi/grove/internal/health"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/resume"
+)
+
+// resumeCheckTimeout bounds re-checking everything after a wake
+const resumeCheckTimeout = time.Minute
+
+// watchResume re-checks servers and proxy routes each time the machine
+// wakes from sleep, until ctx is done. It runs in grove's long-lived
+// processes (the foreground proxy and the API).
+func watchResume(ctx context.Context) {
+	resume.Watch(ctx, func(slept time.Duration) {
+		checkCtx, cancel := context.WithTimeout(ctx, resumeCheckTimeout)
+		defer cancel()
+		recheckAfterResume(checkCtx, slept)
+	})
+}
+
+// resumeState records when servers were last re-checked after a wake
+type resumeState struct {
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// claimResume returns true if this process should handle a wake. Every
+// long-lived grove process notices it; the first to claim it handles it.
+func claimResume(now time.Time) bool {
+	path := config.ResumeStatePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return true
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return true
+	}
+	defer f.Close()
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		return true
+	}
+	defer syscall.Flock(int(f.Fd()), syscall.LOCK_UN) //nolint:errcheck
+
+	var state resumeState
+	json.NewDecoder(f).Decode(&state) //nolint:errcheck // A missing or bad record claims it
+	if now.Sub(state.CheckedAt) < 2*resume.Interval {
+		return false
+	}
+
+	data, _ := json.Marshal(resumeState{CheckedAt: now})
+	if err := f.Truncate(0); err == nil {
+		f.WriteAt(data, 0) //nolint:errcheck
+	}
+	return true
+}
+
+// recheckAfterResume re-checks the health of every running server,
+// flagging those that became unreachable, and reloads the proxy's routes.
+// PIDs survive sleep, but servers and proxies sometimes wedge.
+func recheckAfterResume(ctx context.Context, slept time.Duration) {
+	if !claimResume(time.Now()) {
+		return
+	}
+	log.Printf("Woke from sleep (%s); re-checking servers and proxy routes", slept.Round(time.Second))
+
+	reg, err := registry.LoadContext(ctx)
+	if err != nil {
+		log.Printf("Failed to load registry: %v", err)
+		return
+	}
+	// Servers that exited while asleep are marked stopped first
+	reg.Cleanup() //nolint:errcheck // Best effort
+
+	for _, server := range reg.ListRunning() {
+		if server.IsPaused() {
+			continue
+		}
+		checkCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		ok, err := health.CheckServer(checkCtx, server)
+		cancel()
+		if !ok {
+			continue
+		}
+
+		previous := server.Health
+		server.Health = registry.HealthHealthy
+		if err != nil {
+			server.Health = registry.HealthUnhealthy
+			log.Printf("%s is unreachable after sleep: %v", server.Name, err)
+		}
+		server.LastHealthCheck = time.Now()
+		if err := reg.Set(server); err != nil {
+			log.Printf("Failed to update %s: %v", server.Name, err)
+		}
+
+		if server.Health != previous {
+			e := server.Event(events.HealthChanged)
+			if err != nil {
+				e.Message = fmt.Sprintf("unreachable after sleep: %v", err)
+			}
+			events.Publish(e)
+		}
+	}
+
+	if reg.Proxy != nil && reg.Proxy.IsRunning() {
+		if err := ReloadProxyContext(ctx); err != nil {
+			log.Printf("Proxy didn't take its routes after sleep: %v (restart it with 'grove proxy stop && grove proxy start')", err)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package cli
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClaimResume(t *testing.T) {
+	useTestEnv(t)
+	now := time.Now()
+
+	if !claimResume(now) {
+		t.Fatal("the first process to notice a wake should handle it")
+	}
+	if claimResume(now.Add(3 * time.Second)) {
+		t.Error("another process noticing the same wake shouldn't handle it again")
+	}
+	if !claimResume(now.Add(time.Hour)) {
+		t.Error("a later wake should be handled")
+	}
+}
@@ -1,22 +1,21 @@
 package cli
 
 import (
-	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
-	"path/filepath"
-	"regexp"
 	"sort"
-	"strconv"
 	"strings"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/x/ansi"
+	"github.com/iheanyi/grove/internal/checks"
 	"github.com/iheanyi/grove/internal/discovery"
+	"github.com/iheanyi/grove/internal/github"
 	"github.com/iheanyi/grove/internal/registry"
 	"github.com/iheanyi/grove/internal/styles"
-	"github.com/iheanyi/grove/pkg/browser"
+	"github.com/iheanyi/grove/internal/tasks"
 	"github.com/spf13/cobra"
 )
 
@@ -30,17 +29,36 @@ Displays a review queue with:
 - Task summary (from Tasuku, Beads, or last commit)
 - File changes (+/- lines, file count)
 - Server URL (if running)
+- PR number, CI, and review status (via gh or glab, cached; with --json
+  only when --pr-status is given)
 
-Interactive menu allows opening workspaces in browser or viewing diffs.
+The interactive view lists the queue next to a scrollable diff of the
+selected workspace. Keys: j/k select, J/K or space scroll the diff, o open
+in browser, c copy the URL, r mark as reviewed (until new commits land),
+p create a pull request (pushes the branch and runs 'gh pr create', or
+'glab mr create' for GitLab remotes). The PR title comes from the active
+Tasuku/Beads task and the body includes the grove preview URL.
+
+With --screenshots, each running item's pages are captured with headless
+Chrome (the paths under 'screenshots:' in .grove.yaml, default /) and saved
+as PNGs under ~/.config/grove/screenshots/<name>/, where the web dashboard
+shows them.
 
 Examples:
-  grove review              # Interactive review queue
-  grove review --json       # Output as JSON (for tooling)`,
+  grove review                 # Interactive review queue
+  grove review --json          # Output as JSON (for tooling)
+  grove review --json --pr-status  # Include PR and CI status in the JSON
+  grove review --create-pr 2   # Create a PR for the 2nd item in the queue
+  grove review --screenshots --json`,
 	RunE: runReview,
 }
 
 func init() {
 	reviewCmd.Flags().Bool("json", false, "Output as JSON")
+	reviewCmd.Flags().Int("create-pr", 0, "Push and create a pull request for the given queue item number")
+	reviewCmd.Flags().Bool("draft", false, "Create pull requests as drafts")
+	reviewCmd.Flags().Bool("pr-status", false, "Include PR, CI, and review status in --json output (runs gh or glab per item)")
+	reviewCmd.Flags().Bool("screenshots", false, "Capture screenshots of running items with headless Chrome")
 	reviewCmd.GroupID = "worktree"
 	rootCmd.AddCommand(reviewCmd)
 }
@@ -58,10 +76,28 @@ type ReviewItem struct {
 	IsRunning    bool   `json:"is_running"`
 	HasUnpushed  bool   `json:"has_unpushed"`
 	IsDirty      bool   `json:"is_dirty"`
+	DirtyFiles   int    `json:"dirty_files,omitempty"`
+	Head         string `json:"head,omitempty"`
+	Reviewed     bool   `json:"reviewed"`
+
+	// Checks are the results of the last 'grove ci', which ran against
+	// Head unless ChecksOutdated
+	Checks         *checks.Record `json:"checks,omitempty"`
+	ChecksOutdated bool           `json:"checks_outdated,omitempty"`
+
+	// Screenshots are the PNGs captured with --screenshots
+	Screenshots []string `json:"screenshots,omitempty"`
+
+	// GitHub holds PR, CI, and review status (GitHub or GitLab)
+	GitHub *github.BranchInfo `json:"github,omitempty"`
 }
 
 func runReview(cmd *cobra.Command, args []string) error {
 	jsonOutput, _ := cmd.Flags().GetBool("json")
+	createPR, _ := cmd.Flags().GetInt("create-pr")
+	draft, _ := cmd.Flags().GetBool("draft")
+	screenshots, _ := cmd.Flags().GetBool("screenshots")
+	prStatus, _ := cmd.Flags().GetBool("pr-status")
 
 	// Load registry
 	reg, err := registry.Load()
@@ -75,7 +111,10 @@ func runReview(cmd *cobra.Command, args []string) error {
 	}
 
 	// Get all workspaces with changes
-	items := collectReviewItems(reg)
+	items := collectReviewItems(cmd.Context(), reg)
+	if screenshots {
+		captureReviewScreenshots(cmd.Context(), reg, items)
+	}
 
 	if len(items) == 0 {
 		if jsonOutput {
@@ -87,15 +126,33 @@ func runReview(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	if createPR != 0 {
+		if createPR < 1 || createPR > len(items) {
+			return fmt.Errorf("invalid item number %d (queue has %d items)", createPR, len(items))
+		}
+		url, err := createReviewPR(items[createPR-1], draft)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Pull request created: %s\n", url)
+		return nil
+	}
+
+	// Looking up PRs runs gh or glab per item, so JSON output only includes
+	// them when asked for; the interactive view always shows them
+	if !jsonOutput || prStatus {
+		enrichReviewItems(items)
+	}
+
 	if jsonOutput {
 		return outputReviewJSON(items)
 	}
 
-	return runReviewInteractive(items)
+	return runReviewInteractive(items, draft)
 }
 
 // collectReviewItems gathers all workspaces that have changes
-func collectReviewItems(reg *registry.Registry) []*ReviewItem {
+func collectReviewItems(ctx context.Context, reg *registry.Registry) []*ReviewItem {
 	var items []*ReviewItem
 
 	workspaces := reg.ListWorkspaces()
@@ -107,8 +164,9 @@ func collectReviewItems(reg *registry.Registry) []*ReviewItem {
 		}
 
 		// Check if workspace has changes worth reviewing
-		isDirty := checkGitDirty(ws.Path)
-		hasUnpushed := checkUnpushedCommits(ws.Path)
+		dirtyFiles, _ := discovery.DirtyFiles(ctx, ws.Path)
+		isDirty := dirtyFiles > 0
+		hasUnpushed := checkUnpushedCommits(ctx, ws.Path)
 
 		if !isDirty && !hasUnpushed {
 			continue
@@ -119,11 +177,12 @@ func collectReviewItems(reg *registry.Registry) []*ReviewItem {
 			Path:        ws.Path,
 			Branch:      ws.Branch,
 			IsDirty:     isDirty,
+			DirtyFiles:  dirtyFiles,
 			HasUnpushed: hasUnpushed,
 		}
 
 		// Get diff stats
-		added, removed, files := getGitDiffStats(ws.Path)
+		added, removed, files := getGitDiffStats(ctx, ws.Path)
 		item.LinesAdded = added
 		item.LinesRemoved = removed
 		item.FilesChanged = files
@@ -131,6 +190,14 @@ func collectReviewItems(reg *registry.Registry) []*ReviewItem {
 		// Get task summary from beads if available
 		item.TaskSummary = getTaskSummary(ws.Path)
 
+		// An item stays reviewed until new commits land
+		item.Head = getGitHead(ws.Path)
+		item.Reviewed = ws.ReviewedHead != "" && ws.ReviewedHead == item.Head
+		if ws.Checks != nil {
+			item.Checks = ws.Checks
+			item.ChecksOutdated = !ws.Checks.Current(item.Head)
+		}
+
 		// Get server info
 		if ws.Server != nil && ws.IsRunning() {
 			item.ServerURL = ws.GetURL()
@@ -148,166 +215,73 @@ func collectReviewItems(reg *registry.Registry) []*ReviewItem {
 	return items
 }
 
-// checkGitDirty checks if the worktree has uncommitted changes
-func checkGitDirty(path string) bool {
-	cmd := exec.Command("git", "-C", path, "status", "--porcelain")
-	output, err := cmd.Output()
-	if err != nil {
-		return false
+// enrichReviewItems annotates items with their PR, CI, and review status
+func enrichReviewItems(items []*ReviewItem) {
+	lookups := make([]github.Lookup, 0, len(items))
+	for _, item := range items {
+		lookups = append(lookups, github.Lookup{Dir: item.Path, Branch: item.Branch})
 	}
-	return len(strings.TrimSpace(string(output))) > 0
-}
 
-// checkUnpushedCommits checks if there are commits not on the remote
-func checkUnpushedCommits(path string) bool {
-	// Check if we have an upstream branch
-	cmd := exec.Command("git", "-C", path, "rev-parse", "--abbrev-ref", "@{upstream}")
-	if err := cmd.Run(); err != nil {
-		// No upstream branch, check for commits ahead of main/master
-		for _, base := range []string{"origin/main", "origin/master"} {
-			cmd := exec.Command("git", "-C", path, "rev-list", "--count", base+"..HEAD")
-			output, err := cmd.Output()
-			if err == nil {
-				count, _ := strconv.Atoi(strings.TrimSpace(string(output)))
-				if count > 0 {
-					return true
-				}
-			}
+	infos := fetchPRStatus(lookups)
+	for _, item := range items {
+		if info := infos[item.Path]; info != nil && (info.PR != nil || info.CI != nil) {
+			item.GitHub = info
 		}
-		return false
-	}
-
-	// Has upstream, check for commits ahead
-	cmd = exec.Command("git", "-C", path, "rev-list", "--count", "@{upstream}..HEAD")
-	output, err := cmd.Output()
-	if err != nil {
-		return false
 	}
-
-	count, _ := strconv.Atoi(strings.TrimSpace(string(output)))
-	return count > 0
 }
 
-// getGitDiffStats returns lines added, removed, and file count
-func getGitDiffStats(path string) (added, removed, files int) {
-	// Get stats for staged and unstaged changes combined
-	cmd := exec.Command("git", "-C", path, "diff", "--stat", "HEAD")
-	output, err := cmd.Output()
-	if err != nil {
-		// Try without HEAD (for new repos)
-		cmd = exec.Command("git", "-C", path, "diff", "--stat")
-		output, _ = cmd.Output()
-	}
-
-	return parseDiffStats(string(output))
+// getGitHead returns the commit SHA of the worktree's HEAD
+func getGitHead(path string) string {
+	head, _ := gitOps().Head(context.Background(), path)
+	return head
 }
 
-// parseDiffStats parses the output of git diff --stat
-func parseDiffStats(output string) (added, removed, files int) {
-	lines := strings.Split(output, "\n")
-	if len(lines) == 0 {
-		return 0, 0, 0
-	}
-
-	// The summary line is typically the last non-empty line
-	// Format: " N files changed, M insertions(+), P deletions(-)"
-	for i := len(lines) - 1; i >= 0; i-- {
-		line := strings.TrimSpace(lines[i])
-		if line == "" {
-			continue
-		}
-
-		// Check if this is a summary line
-		if strings.Contains(line, "changed") {
-			// Parse file count
-			if match := regexp.MustCompile(`(\d+) files? changed`).FindStringSubmatch(line); len(match) > 1 {
-				files, _ = strconv.Atoi(match[1])
-			}
-			// Parse insertions
-			if match := regexp.MustCompile(`(\d+) insertions?`).FindStringSubmatch(line); len(match) > 1 {
-				added, _ = strconv.Atoi(match[1])
-			}
-			// Parse deletions
-			if match := regexp.MustCompile(`(\d+) deletions?`).FindStringSubmatch(line); len(match) > 1 {
-				removed, _ = strconv.Atoi(match[1])
-			}
-			break
-		}
-	}
-
-	return added, removed, files
+// checkGitDirty checks if the worktree has uncommitted changes
+func checkGitDirty(path string) bool {
+	n, _ := gitOps().DirtyFiles(context.Background(), path)
+	return n > 0
 }
 
-// getTaskSummary tries to get a task summary from Tasuku, Beads, or recent commit
-func getTaskSummary(path string) string {
-	// Try Tasuku first (.tasuku/tasks/)
-	if taskID, taskDesc := discovery.GetActiveTask(path); taskID != "" {
-		summary := taskID
-		if taskDesc != "" {
-			summary = taskDesc
-		}
-		return ansi.Truncate(summary, styles.TruncateDefault, styles.TruncateTail)
+// checkUnpushedCommits checks if there are commits not on the remote: ahead
+// of the branch's upstream, or of origin's main branch without one
+func checkUnpushedCommits(ctx context.Context, path string) bool {
+	g := gitOps()
+	bases := []string{"origin/main", "origin/master"}
+	if upstream, _ := g.Upstream(ctx, path); upstream != "" {
+		bases = []string{upstream}
 	}
-
-	// Fall back to Beads (.beads/issues/) for backwards compatibility
-	beadsPath := filepath.Join(path, ".beads", "issues")
-	if info, err := os.Stat(beadsPath); err == nil && info.IsDir() {
-		if summary := findBeadsTask(beadsPath); summary != "" {
-			return summary
+	for _, base := range bases {
+		if count, err := g.CountCommits(ctx, path, base+"..HEAD"); err == nil && count > 0 {
+			return true
 		}
 	}
-
-	// Fall back to last commit message
-	cmd := exec.Command("git", "-C", path, "log", "-1", "--format=%s")
-	output, err := cmd.Output()
-	if err != nil {
-		return ""
-	}
-
-	msg := strings.TrimSpace(string(output))
-	return ansi.Truncate(msg, styles.TruncateDefault, styles.TruncateTail)
+	return false
 }
 
-// findBeadsTask looks for an in_progress issue in beads
-func findBeadsTask(beadsPath string) string {
-	entries, err := os.ReadDir(beadsPath)
+// getGitDiffStats returns lines added, removed, and file count of the
+// worktree's uncommitted changes
+func getGitDiffStats(ctx context.Context, path string) (added, removed, files int) {
+	g := gitOps()
+	stat, err := g.DiffStat(ctx, path, "HEAD")
 	if err != nil {
-		return ""
+		// Try without HEAD (for new repos)
+		stat, _ = g.DiffStat(ctx, path)
 	}
+	return stat.Added, stat.Removed, stat.Files
+}
 
-	for _, entry := range entries {
-		if !strings.HasSuffix(entry.Name(), ".md") {
-			continue
-		}
-
-		content, err := os.ReadFile(filepath.Join(beadsPath, entry.Name()))
+// getTaskSummary returns the active task's summary, falling back to the
+// last commit message
+func getTaskSummary(path string) string {
+	summary := tasks.Summary(path)
+	if summary == "" {
+		commit, err := gitOps().LastCommit(context.Background(), path)
 		if err != nil {
-			continue
-		}
-
-		// Check if issue is in_progress
-		if !strings.Contains(string(content), "status: in_progress") {
-			continue
-		}
-
-		// Extract title from first heading
-		lines := strings.Split(string(content), "\n")
-		for _, line := range lines {
-			line = strings.TrimSpace(line)
-			if strings.HasPrefix(line, "# ") {
-				title := strings.TrimPrefix(line, "# ")
-				return ansi.Truncate(title, styles.TruncateTitle, styles.TruncateTail)
-			}
-			// Also try title from frontmatter
-			if strings.HasPrefix(line, "title:") {
-				title := strings.TrimSpace(strings.TrimPrefix(line, "title:"))
-				title = strings.Trim(title, "\"'")
-				return ansi.Truncate(title, styles.TruncateTitle, styles.TruncateTail)
-			}
+			return ""
 		}
+		summary = commit.Subject
 	}
-
-	return ""
+	return ansi.Truncate(summary, styles.TruncateDefault, styles.TruncateTail)
 }
 
 func outputReviewJSON(items []*ReviewItem) error {
@@ -316,139 +290,25 @@ func outputReviewJSON(items []*ReviewItem) error {
 	return enc.Encode(items)
 }
 
-func runReviewInteractive(items []*ReviewItem) error {
-	// Use shared styles
-	headerStyle := styles.LinkHeader
-	nameStyle := styles.NameStyle
-	urlStyle := styles.URLStyle
-	statsStyle := styles.StatsStyle
-	dimStyle := styles.DimStyle
-
-	// Print header
-	fmt.Println()
-	fmt.Println(headerStyle.Render("Review Queue"))
-	fmt.Println()
-
-	// Print items
-	for i, item := range items {
-		// Number and name
-		fmt.Printf("%s. %s\n",
-			dimStyle.Render(fmt.Sprintf("%d", i+1)),
-			nameStyle.Render(item.Name))
-
-		// Task summary
-		if item.TaskSummary != "" {
-			fmt.Printf("   Task: %s\n", item.TaskSummary)
-		}
-
-		// Changes
-		changeStr := formatChanges(item.LinesAdded, item.LinesRemoved, item.FilesChanged)
-		if changeStr != "" {
-			fmt.Printf("   Changes: %s\n", statsStyle.Render(changeStr))
-		}
-
-		// Status indicators
-		var statusParts []string
-		if item.IsDirty {
-			statusParts = append(statusParts, "uncommitted changes")
-		}
-		if item.HasUnpushed {
-			statusParts = append(statusParts, "unpushed commits")
-		}
-		if len(statusParts) > 0 {
-			fmt.Printf("   Status: %s\n", dimStyle.Render(strings.Join(statusParts, ", ")))
-		}
-
-		// Server URL
-		if item.IsRunning {
-			fmt.Printf("   URL: %s\n", urlStyle.Render(item.ServerURL))
-		} else {
-			fmt.Printf("   URL: %s\n", dimStyle.Render("(server not running)"))
-		}
-
-		fmt.Println()
+func runReviewInteractive(items []*ReviewItem, draft bool) error {
+	p := tea.NewProgram(newReviewModel(items), tea.WithAltScreen())
+	result, err := p.Run()
+	if err != nil {
+		return err
 	}
 
-	// Print actions
-	fmt.Println(dimStyle.Render("─────────────────────────────────────────────────────────"))
-	fmt.Println()
-	fmt.Println("Actions:")
-	fmt.Printf("  [1-%d] Open in browser\n", len(items))
-	fmt.Println("  [a]   Open all")
-	fmt.Println("  [d]   Show diff (enter number after)")
-	fmt.Println("  [q]   Quit")
-	fmt.Println()
-
-	// Interactive loop
-	reader := bufio.NewReader(os.Stdin)
-	for {
-		fmt.Print("Choice: ")
-		input, err := reader.ReadString('\n')
-		if err != nil {
-			return err
-		}
-		input = strings.TrimSpace(strings.ToLower(input))
-
-		if input == "q" || input == "quit" {
-			return nil
-		}
-
-		if input == "a" || input == "all" {
-			// Open all running servers
-			opened := 0
-			for _, item := range items {
-				if item.IsRunning && item.ServerURL != "" {
-					if err := browser.Open(item.ServerURL); err == nil {
-						opened++
-					}
-				}
-			}
-			if opened == 0 {
-				fmt.Println("No running servers to open.")
-			} else {
-				fmt.Printf("Opened %d servers in browser.\n", opened)
-			}
-			continue
-		}
-
-		if strings.HasPrefix(input, "d") || strings.HasPrefix(input, "diff") {
-			// Show diff for specified item
-			numStr := strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(input, "diff"), "d"))
-			if numStr == "" {
-				fmt.Print("Enter number to show diff: ")
-				numStr, _ = reader.ReadString('\n')
-				numStr = strings.TrimSpace(numStr)
-			}
-
-			num, err := strconv.Atoi(numStr)
-			if err != nil || num < 1 || num > len(items) {
-				fmt.Printf("Invalid number. Enter 1-%d\n", len(items))
-				continue
-			}
-
-			item := items[num-1]
-			showDiff(item.Path)
-			continue
-		}
-
-		// Try to parse as number
-		num, err := strconv.Atoi(input)
-		if err != nil || num < 1 || num > len(items) {
-			fmt.Printf("Invalid choice. Enter 1-%d, 'a', 'd', or 'q'\n", len(items))
-			continue
-		}
-
-		item := items[num-1]
-		if !item.IsRunning {
-			fmt.Printf("Server for '%s' is not running. Start it with 'grove start' in that directory.\n", item.Name)
-			continue
-		}
+	// PR creation runs outside the TUI so git/gh output and prompts are visible
+	m := result.(reviewModel)
+	if m.createPR == nil {
+		return nil
+	}
 
-		fmt.Printf("Opening %s...\n", item.ServerURL)
-		if err := browser.Open(item.ServerURL); err != nil {
-			fmt.Printf("Failed to open browser: %v\n", err)
-		}
+	url, err := createReviewPR(m.createPR, draft)
+	if err != nil {
+		return fmt.Errorf("failed to create pull request: %w", err)
 	}
+	fmt.Printf("Pull request created: %s\n", url)
+	return nil
 }
 
 // formatChanges formats the change statistics
@@ -474,11 +334,3 @@ func formatChanges(added, removed, files int) string {
 
 	return strings.Join(parts, " ")
 }
-
-// showDiff displays the git diff for a workspace
-func showDiff(path string) {
-	cmd := exec.Command("git", "-C", path, "diff", "--color=always", "HEAD")
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	_ = cmd.Run()
-}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/iheanyi/grove/internal/github"
+	"github.com/iheanyi/grove/internal/tasks"
+)
+
+// createReviewPR pushes a review item's branch and opens a pull request
+// (GitHub via gh, or a merge request via glab for GitLab remotes). It
+// returns the URL of the new pull request.
+func createReviewPR(item *ReviewItem, draft bool) (string, error) {
+	if item.Branch == "" {
+		return "", fmt.Errorf("'%s' has no branch (detached HEAD?)", item.Name)
+	}
+
+	if item.IsDirty {
+		fmt.Printf("Warning: '%s' has uncommitted changes that won't be included in the PR\n", item.Name)
+	}
+
+	title := prTitle(item.Path)
+	body := prBody(item)
+
+	fmt.Printf("Pushing %s...\n", item.Branch)
+	push := exec.Command("git", "-C", item.Path, "push", "-u", "origin", item.Branch)
+	if output, err := push.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("git push failed: %w\n%s", err, strings.TrimSpace(string(output)))
+	}
+
+	fmt.Printf("Creating pull request: %s\n", title)
+	if github.IsGitLabRemote(item.Path) {
+		return createGitLabMR(item, title, body, draft)
+	}
+
+	return github.CreatePR(github.CreatePROptions{
+		Dir:    item.Path,
+		Branch: item.Branch,
+		Title:  title,
+		Body:   body,
+		Draft:  draft,
+	})
+}
+
+// prTitle returns the PR title: the active task, falling back to the last
+// commit subject
+func prTitle(path string) string {
+	if summary := tasks.Summary(path); summary != "" {
+		return summary
+	}
+
+	output, err := exec.Command("git", "-C", path, "log", "-1", "--format=%s").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// prBody builds the PR description with the task and grove preview URL
+func prBody(item *ReviewItem) string {
+	var sb strings.Builder
+
+	if task := tasks.Active(item.Path); task != nil {
+		sb.WriteString(fmt.Sprintf("Task: %s\n\n", task))
+	}
+
+	if item.IsRunning && item.ServerURL != "" {
+		sb.WriteString(fmt.Sprintf("Preview: %s\n\n", item.ServerURL))
+	}
+
+	if changes := formatChanges(item.LinesAdded, item.LinesRemoved, item.FilesChanged); changes != "" {
+		sb.WriteString(fmt.Sprintf("Changes: %s\n\n", changes))
+	}
+
+	sb.WriteString("_Created from `grove review`_\n")
+	return sb.String()
+}
+
+// createGitLabMR opens a merge request with the GitLab CLI
+func createGitLabMR(item *ReviewItem, title, body string, draft bool) (string, error) {
+	if _, err := exec.LookPath("glab"); err != nil {
+		return "", fmt.Errorf("glab not found in PATH. Install with: brew install glab")
+	}
+
+	args := []string{"mr", "create",
+		"--source-branch", item.Branch,
+		"--title", title,
+		"--description", body,
+		"--yes",
+	}
+	if draft {
+		args = append(args, "--draft")
+	}
+
+	cmd := exec.Command("glab", args...)
+	cmd.Dir = item.Path
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("glab mr create failed: %w\n%s", err, strings.TrimSpace(string(output)))
+	}
+
+	// Return the merge request URL if glab printed one
+	for _, field := range strings.Fields(string(output)) {
+		if strings.HasPrefix(field, "https://") {
+			return field, nil
+		}
+	}
+	return strings.TrimSpace(string(output)), nil
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPRBody(t *testing.T) {
+	dir := t.TempDir()
+	tasksDir := filepath.Join(dir, ".tasuku", "tasks")
+	if err := os.MkdirAll(tasksDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	task := `{"id": "auth-flow", "status": "in_progress", "description": "Add OAuth login"}`
+	if err := os.WriteFile(filepath.Join(tasksDir, "auth-flow.json"), []byte(task), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	item := &ReviewItem{
+		Name:         "feature-auth",
+		Path:         dir,
+		Branch:       "feature/auth",
+		ServerURL:    "https://feature-auth.localhost",
+		IsRunning:    true,
+		LinesAdded:   10,
+		LinesRemoved: 2,
+		FilesChanged: 3,
+	}
+
+	body := prBody(item)
+	for _, exp := range []string{
+		"Task: auth-flow - Add OAuth login",
+		"Preview: https://feature-auth.localhost",
+		"Changes: +10 -2 (3 files)",
+	} {
+		if !strings.Contains(body, exp) {
+			t.Errorf("expected body to contain %q, got:\n%s", exp, body)
+		}
+	}
+
+	if title := prTitle(dir); title != "Add OAuth login" {
+		t.Errorf("prTitle() = %q, want %q", title, "Add OAuth login")
+	}
+
+	// Stopped servers have no preview link
+	item.IsRunning = false
+	if body := prBody(item); strings.Contains(body, "Preview:") {
+		t.Errorf("expected no preview for stopped server, got:\n%s", body)
+	}
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/project"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/screenshot"
+)
+
+// screenshotTimeout bounds capturing one item's pages
+const screenshotTimeout = 30 * time.Second
+
+// captureReviewScreenshots captures the configured pages of each running
+// item and records the files on it. Failures are warnings: the queue is
+// still useful without pictures.
+func captureReviewScreenshots(ctx context.Context, reg *registry.Registry, items []*ReviewItem) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	for _, item := range items {
+		server, ok := reg.Get(item.Name)
+		if !ok || !item.IsRunning {
+			continue
+		}
+
+		routes := screenshot.DefaultRoutes
+		if projConfig, err := project.Load(item.Path); err == nil && len(projConfig.Screenshots) > 0 {
+			routes = projConfig.Screenshots
+		}
+
+		// Progress goes to stderr so --json output stays a document
+		fmt.Fprintf(os.Stderr, "Capturing %s (%d pages)...\n", item.Name, len(routes))
+		itemCtx, cancel := context.WithTimeout(ctx, screenshotTimeout)
+		dir := screenshot.Dir(config.ScreenshotsDir(), item.Name)
+		files, err := screenshot.Capture(itemCtx, fmt.Sprintf("http://localhost:%d", server.Port), dir, routes)
+		cancel()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s: %v\n", item.Name, err)
+		}
+		item.Screenshots = files
+	}
+}
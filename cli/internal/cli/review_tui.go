@@ -0,0 +1,466 @@
+package cli
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/styles"
+	"github.com/iheanyi/grove/pkg/browser"
+)
+
+// reviewKeys defines key bindings for the review queue
+var reviewKeys = struct {
+	Up         key.Binding
+	Down       key.Binding
+	ScrollUp   key.Binding
+	ScrollDown key.Binding
+	PageUp     key.Binding
+	PageDown   key.Binding
+	Open       key.Binding
+	Copy       key.Binding
+	Reviewed   key.Binding
+	CreatePR   key.Binding
+	Quit       key.Binding
+}{
+	Up:         key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "prev")),
+	Down:       key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "next")),
+	ScrollUp:   key.NewBinding(key.WithKeys("K", "shift+up"), key.WithHelp("K", "scroll up")),
+	ScrollDown: key.NewBinding(key.WithKeys("J", "shift+down"), key.WithHelp("J", "scroll down")),
+	PageUp:     key.NewBinding(key.WithKeys("pgup", "ctrl+u", "b")),
+	PageDown:   key.NewBinding(key.WithKeys("pgdown", "ctrl+d", " ")),
+	Open:       key.NewBinding(key.WithKeys("o", "enter"), key.WithHelp("o", "open")),
+	Copy:       key.NewBinding(key.WithKeys("c", "y"), key.WithHelp("c", "copy url")),
+	Reviewed:   key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "mark reviewed")),
+	CreatePR:   key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "create PR")),
+	Quit:       key.NewBinding(key.WithKeys("q", "esc", "ctrl+c"), key.WithHelp("q", "quit")),
+}
+
+// reviewListWidth is the maximum width of the review item list pane
+const reviewListWidth = 44
+
+// Diff styles (delta-style coloring)
+var (
+	diffAddStyle    lipgloss.Style
+	diffDelStyle    lipgloss.Style
+	diffHunkStyle   lipgloss.Style
+	diffFileStyle   lipgloss.Style
+	diffMetaStyle   lipgloss.Style
+	reviewPaneStyle lipgloss.Style
+)
+
+func init() {
+	buildDiffStyles()
+	styles.OnChange(buildDiffStyles)
+}
+
+// buildDiffStyles builds the diff styles from the theme's colors
+func buildDiffStyles() {
+	diffAddStyle = lipgloss.NewStyle().Foreground(styles.Secondary)
+	diffDelStyle = lipgloss.NewStyle().Foreground(styles.Error)
+	diffHunkStyle = lipgloss.NewStyle().Foreground(styles.Cyan)
+	diffFileStyle = lipgloss.NewStyle().Bold(true).Foreground(styles.Accent)
+	diffMetaStyle = lipgloss.NewStyle().Foreground(styles.Muted)
+	reviewPaneStyle = lipgloss.NewStyle().Border(lipgloss.NormalBorder(), false, false, false, true).BorderForeground(styles.Dim).PaddingLeft(1)
+}
+
+// reviewDiffMsg carries a loaded diff for a review item
+type reviewDiffMsg struct {
+	path    string
+	content string
+}
+
+// reviewStatusMsg clears the status line after a delay
+type reviewStatusMsg struct{ id int }
+
+// reviewModel is the bubbletea model for the review queue
+type reviewModel struct {
+	items    []*ReviewItem
+	cursor   int
+	diff     viewport.Model
+	diffs    map[string]string
+	width    int
+	height   int
+	ready    bool
+	status   string
+	statusID int
+
+	// createPR is set when the user asked to create a PR; the TUI exits
+	// so gh/glab can use the terminal
+	createPR *ReviewItem
+}
+
+func newReviewModel(items []*ReviewItem) reviewModel {
+	return reviewModel{
+		items: items,
+		diffs: make(map[string]string),
+	}
+}
+
+func (m reviewModel) Init() tea.Cmd {
+	return m.loadDiff()
+}
+
+// selected returns the currently highlighted review item
+func (m reviewModel) selected() *ReviewItem {
+	if len(m.items) == 0 {
+		return nil
+	}
+	return m.items[m.cursor]
+}
+
+// loadDiff loads the selected item's diff in the background
+func (m reviewModel) loadDiff() tea.Cmd {
+	item := m.selected()
+	if item == nil {
+		return nil
+	}
+	if _, ok := m.diffs[item.Path]; ok {
+		return nil
+	}
+	return func() tea.Msg {
+		return reviewDiffMsg{path: item.Path, content: colorizeDiff(reviewDiff(item.Path))}
+	}
+}
+
+// flash shows a status message that clears after a few seconds
+func (m *reviewModel) flash(msg string) tea.Cmd {
+	m.status = msg
+	m.statusID++
+	id := m.statusID
+	return tea.Tick(3*time.Second, func(time.Time) tea.Msg {
+		return reviewStatusMsg{id: id}
+	})
+}
+
+// showSelectedDiff puts the selected item's diff (if loaded) in the viewport
+func (m *reviewModel) showSelectedDiff() {
+	item := m.selected()
+	if item == nil || !m.ready {
+		return
+	}
+	content, ok := m.diffs[item.Path]
+	if !ok {
+		content = styles.DimStyle.Render("Loading diff...")
+	} else if strings.TrimSpace(content) == "" {
+		content = styles.DimStyle.Render("No changes")
+	}
+	m.diff.SetContent(content)
+	m.diff.GotoTop()
+}
+
+func (m reviewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		diffWidth, diffHeight := m.diffSize()
+		if !m.ready {
+			m.diff = viewport.New(diffWidth, diffHeight)
+			m.ready = true
+			m.showSelectedDiff()
+		} else {
+			m.diff.Width = diffWidth
+			m.diff.Height = diffHeight
+		}
+		return m, nil
+
+	case reviewDiffMsg:
+		m.diffs[msg.path] = msg.content
+		if item := m.selected(); item != nil && item.Path == msg.path {
+			m.showSelectedDiff()
+		}
+		return m, nil
+
+	case reviewStatusMsg:
+		if msg.id == m.statusID {
+			m.status = ""
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		item := m.selected()
+		switch {
+		case key.Matches(msg, reviewKeys.Quit):
+			return m, tea.Quit
+
+		case key.Matches(msg, reviewKeys.Up):
+			if m.cursor > 0 {
+				m.cursor--
+				m.showSelectedDiff()
+			}
+			return m, m.loadDiff()
+
+		case key.Matches(msg, reviewKeys.Down):
+			if m.cursor < len(m.items)-1 {
+				m.cursor++
+				m.showSelectedDiff()
+			}
+			return m, m.loadDiff()
+
+		case key.Matches(msg, reviewKeys.ScrollUp):
+			m.diff.ScrollUp(1)
+			return m, nil
+
+		case key.Matches(msg, reviewKeys.ScrollDown):
+			m.diff.ScrollDown(1)
+			return m, nil
+
+		case key.Matches(msg, reviewKeys.PageUp):
+			m.diff.PageUp()
+			return m, nil
+
+		case key.Matches(msg, reviewKeys.PageDown):
+			m.diff.PageDown()
+			return m, nil
+
+		case key.Matches(msg, reviewKeys.Open):
+			if item == nil {
+				return m, nil
+			}
+			if !item.IsRunning {
+				return m, m.flash(fmt.Sprintf("Server for '%s' is not running", item.Name))
+			}
+			if err := browser.Open(item.ServerURL); err != nil {
+				return m, m.flash(fmt.Sprintf("Failed to open browser: %v", err))
+			}
+			return m, m.flash("Opened " + item.ServerURL)
+
+		case key.Matches(msg, reviewKeys.Copy):
+			if item == nil {
+				return m, nil
+			}
+			if !item.IsRunning {
+				return m, m.flash(fmt.Sprintf("Server for '%s' is not running", item.Name))
+			}
+			if err := copyToClipboard(item.ServerURL); err != nil {
+				return m, m.flash(fmt.Sprintf("Failed to copy: %v", err))
+			}
+			return m, m.flash("Copied " + item.ServerURL)
+
+		case key.Matches(msg, reviewKeys.Reviewed):
+			if item == nil {
+				return m, nil
+			}
+			if err := setReviewed(item, !item.Reviewed); err != nil {
+				return m, m.flash(fmt.Sprintf("Failed to update registry: %v", err))
+			}
+			if item.Reviewed {
+				return m, m.flash(fmt.Sprintf("Marked '%s' as reviewed", item.Name))
+			}
+			return m, m.flash(fmt.Sprintf("Unmarked '%s'", item.Name))
+
+		case key.Matches(msg, reviewKeys.CreatePR):
+			if item == nil {
+				return m, nil
+			}
+			m.createPR = item
+			return m, tea.Quit
+		}
+	}
+
+	var cmd tea.Cmd
+	m.diff, cmd = m.diff.Update(msg)
+	return m, cmd
+}
+
+// listWidth returns the width of the item list pane
+func (m reviewModel) listWidth() int {
+	return min(reviewListWidth, m.width/3)
+}
+
+// diffSize returns the dimensions of the diff viewport
+func (m reviewModel) diffSize() (int, int) {
+	// Header, status and help take three lines; pane border and padding two columns
+	return max(m.width-m.listWidth()-2, 10), max(m.height-3, 3)
+}
+
+func (m reviewModel) View() string {
+	if !m.ready {
+		return "Loading..."
+	}
+
+	header := styles.LinkHeader.Render(fmt.Sprintf("Review Queue (%d)", len(m.items)))
+	body := lipgloss.JoinHorizontal(lipgloss.Top,
+		lipgloss.NewStyle().Width(m.listWidth()).Height(m.diff.Height).Render(m.renderList()),
+		reviewPaneStyle.Render(m.diff.View()),
+	)
+
+	status := m.status
+	if status == "" {
+		if item := m.selected(); item != nil && item.IsRunning {
+			status = styles.URLStyle.Render(item.ServerURL)
+		}
+	}
+
+	help := styles.DimStyle.Render("↑/↓ select • J/K/space scroll diff • o open • c copy url • r reviewed • p create PR • q quit")
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, body, status, help)
+}
+
+// renderList renders the review items, keeping the cursor in view
+func (m reviewModel) renderList() string {
+	width := m.listWidth()
+	var lines []string
+	for i, item := range m.items {
+		marker := "  "
+		name := styles.NameStyle.Render(item.Name)
+		if i == m.cursor {
+			marker = styles.AccentStyle.Render("▸ ")
+			name = styles.SelectedTitle.Render(item.Name)
+		}
+		if item.Reviewed {
+			name += styles.RunningStyle.Render(" ✓")
+		}
+		lines = append(lines, ansi.Truncate(marker+name, width, styles.TruncateTail))
+
+		var details []string
+		if changes := formatChanges(item.LinesAdded, item.LinesRemoved, item.FilesChanged); changes != "" {
+			details = append(details, styles.StatsStyle.Render(changes))
+		}
+		if item.IsDirty {
+			details = append(details, fmt.Sprintf("%d uncommitted", item.DirtyFiles))
+		}
+		if item.HasUnpushed {
+			details = append(details, "unpushed")
+		}
+		if item.Checks != nil {
+			checks := "checks " + formatChecks(item.Checks)
+			if item.ChecksOutdated {
+				checks += " (outdated)"
+			}
+			details = append(details, checks)
+		}
+		lines = append(lines, ansi.Truncate("  "+styles.DimStyle.Render(strings.Join(details, " · ")), width, styles.TruncateTail))
+
+		if item.TaskSummary != "" {
+			lines = append(lines, ansi.Truncate("  "+item.TaskSummary, width, styles.TruncateTail))
+		}
+		if pr := formatPRSummary(item.GitHub); pr != "" {
+			lines = append(lines, ansi.Truncate("  "+styles.AccentStyle.Render(pr), width, styles.TruncateTail))
+		}
+		lines = append(lines, "")
+	}
+
+	// Scroll the list so the selected item stays visible
+	height := m.diff.Height
+	if len(lines) > height {
+		offset := 0
+		for i := 0; i < m.cursor; i++ {
+			offset += 3
+			if m.items[i].TaskSummary != "" {
+				offset++
+			}
+			if formatPRSummary(m.items[i].GitHub) != "" {
+				offset++
+			}
+		}
+		offset = min(max(offset-height/2, 0), len(lines)-height)
+		lines = lines[offset : offset+height]
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// reviewDiff returns the diff of a worktree against the point where it
+// diverged from its upstream (or origin/main), including uncommitted changes
+func reviewDiff(path string) string {
+	base := "HEAD"
+	for _, ref := range []string{"@{upstream}", "origin/main", "origin/master"} {
+		output, err := exec.Command("git", "-C", path, "merge-base", "HEAD", ref).Output()
+		if err == nil {
+			base = strings.TrimSpace(string(output))
+			break
+		}
+	}
+
+	output, err := exec.Command("git", "-C", path, "diff", base).Output()
+	if err != nil {
+		return ""
+	}
+	return string(output)
+}
+
+// colorizeDiff applies delta-style coloring to a plain unified diff
+func colorizeDiff(diff string) string {
+	lines := strings.Split(strings.TrimRight(diff, "\n"), "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			// "diff --git a/path b/path" -> "━━ path"
+			file := line
+			if idx := strings.LastIndex(line, " b/"); idx >= 0 {
+				file = line[idx+3:]
+			}
+			lines[i] = diffFileStyle.Render("━━ " + file)
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"),
+			strings.HasPrefix(line, "index "), strings.HasPrefix(line, "new file"),
+			strings.HasPrefix(line, "deleted file"), strings.HasPrefix(line, "similarity"),
+			strings.HasPrefix(line, "rename "):
+			lines[i] = diffMetaStyle.Render(line)
+		case strings.HasPrefix(line, "@@"):
+			lines[i] = diffHunkStyle.Render(line)
+		case strings.HasPrefix(line, "+"):
+			lines[i] = diffAddStyle.Render(line)
+		case strings.HasPrefix(line, "-"):
+			lines[i] = diffDelStyle.Render(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// setReviewed records (or clears) the reviewed state of an item in the
+// registry. The review is tied to the current HEAD so new commits reset it.
+func setReviewed(item *ReviewItem, reviewed bool) error {
+	reg, err := registry.Load()
+	if err != nil {
+		return err
+	}
+
+	ws, ok := reg.GetWorkspace(item.Name)
+	if !ok {
+		return fmt.Errorf("workspace '%s' not found", item.Name)
+	}
+
+	if reviewed {
+		ws.ReviewedAt = time.Now()
+		ws.ReviewedHead = item.Head
+	} else {
+		ws.ReviewedAt = time.Time{}
+		ws.ReviewedHead = ""
+	}
+
+	if err := reg.SetWorkspace(ws); err != nil {
+		return err
+	}
+	item.Reviewed = reviewed
+	return nil
+}
+
+// copyToClipboard copies text using the platform clipboard tool
+func copyToClipboard(text string) error {
+	var candidates [][]string
+	if runtime.GOOS == "darwin" {
+		candidates = [][]string{{"pbcopy"}}
+	} else {
+		candidates = [][]string{{"wl-copy"}, {"xclip", "-selection", "clipboard"}, {"xsel", "--clipboard", "--input"}}
+	}
+
+	for _, c := range candidates {
+		if _, err := exec.LookPath(c[0]); err != nil {
+			continue
+		}
+		cmd := exec.Command(c[0], c[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		return cmd.Run()
+	}
+	return fmt.Errorf("no clipboard tool found")
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestColorizeDiff(t *testing.T) {
+	diff := `diff --git a/main.go b/main.go
+index 1234567..89abcde 100644
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,3 @@
+ package main
+-var x = 1
++var x = 2
+`
+	got := ansi.Strip(colorizeDiff(diff))
+	lines := strings.Split(got, "\n")
+
+	if lines[0] != "━━ main.go" {
+		t.Errorf("file header = %q, want %q", lines[0], "━━ main.go")
+	}
+	for _, want := range []string{"@@ -1,3 +1,3 @@", " package main", "-var x = 1", "+var x = 2"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("colorized diff missing %q:\n%s", want, got)
+		}
+	}
+	if len(lines) != 8 {
+		t.Errorf("got %d lines, want 8", len(lines))
+	}
+}
+
+func TestReviewModelNavigation(t *testing.T) {
+	items := []*ReviewItem{
+		{Name: "feature-a", Path: "/tmp/a"},
+		{Name: "feature-b", Path: "/tmp/b"},
+	}
+	m := newReviewModel(items)
+	m.diffs["/tmp/a"] = "diff a"
+	m.diffs["/tmp/b"] = "diff b"
+
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	m = updated.(reviewModel)
+	if !strings.Contains(m.diff.View(), "diff a") {
+		t.Errorf("expected first item's diff, got %q", m.diff.View())
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	m = updated.(reviewModel)
+	if m.cursor != 1 {
+		t.Fatalf("cursor = %d, want 1", m.cursor)
+	}
+	if !strings.Contains(m.diff.View(), "diff b") {
+		t.Errorf("expected second item's diff, got %q", m.diff.View())
+	}
+
+	// Moving past the end keeps the cursor on the last item
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	m = updated.(reviewModel)
+	if m.cursor != 1 {
+		t.Errorf("cursor = %d, want 1", m.cursor)
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	m = updated.(reviewModel)
+	if m.createPR != items[1] || cmd == nil {
+		t.Errorf("expected p to select item for PR creation and quit")
+	}
+}
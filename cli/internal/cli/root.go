@@ -5,13 +5,22 @@ import (
 	"os"
 
 	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/discovery"
+	"github.com/iheanyi/grove/internal/events"
+	"github.com/iheanyi/grove/internal/metrics"
+	"github.com/iheanyi/grove/internal/notify"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/styles"
 	"github.com/iheanyi/grove/internal/tui"
+	"github.com/iheanyi/grove/internal/worktree"
 	"github.com/spf13/cobra"
 )
 
 var (
-	cfgFile string
-	cfg     *config.Config
+	cfgFile     string
+	stateDir    string
+	contextName string
+	cfg         *config.Config
 )
 
 var rootCmd = &cobra.Command{
@@ -25,7 +34,9 @@ When run without arguments, it launches an interactive TUI dashboard.`,
 		// Default behavior: launch TUI
 		return runTUI()
 	},
-	SilenceUsage: true,
+	// Record each command run for 'grove stats'
+	PersistentPreRun: recordCommand,
+	SilenceUsage:     true,
 }
 
 func Execute() error {
@@ -36,6 +47,11 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $XDG_CONFIG_HOME/grove/config.yaml)")
+	rootCmd.PersistentFlags().StringVar(&contextName, "context", "", "context to run in (default is the one selected with 'grove context use')")
+	_ = rootCmd.RegisterFlagCompletionFunc("context", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeContexts(cmd, nil, toComplete)
+	})
+	rootCmd.PersistentFlags().StringVar(&stateDir, "state-dir", "", "directory for grove's config, registry and logs (default is $GROVE_HOME or $XDG_CONFIG_HOME/grove)")
 
 	// Define command groups
 	rootCmd.AddGroup(
@@ -51,24 +67,46 @@ func init() {
 	startCmd.GroupID = "server"
 	stopCmd.GroupID = "server"
 	restartCmd.GroupID = "server"
+	reloadCmd.GroupID = "server"
 	lsCmd.GroupID = "server"
 	statusCmd.GroupID = "server"
 	urlCmd.GroupID = "server"
 	openCmd.GroupID = "server"
+	shareCmd.GroupID = "server"
+	tunnelCmd.GroupID = "server"
 	attachCmd.GroupID = "server"
 	detachCmd.GroupID = "server"
 	tagCmd.GroupID = "server"
+	runCmd.GroupID = "server"
+	groupCmd.GroupID = "server"
+	portsCmd.GroupID = "server"
+	lanCmd.GroupID = "server"
+	compareCmd.GroupID = "server"
+	vrtCmd.GroupID = "server"
+	freezeCmd.GroupID = "server"
+	thawCmd.GroupID = "server"
 
 	rootCmd.AddCommand(startCmd)
 	rootCmd.AddCommand(stopCmd)
 	rootCmd.AddCommand(restartCmd)
+	rootCmd.AddCommand(reloadCmd)
 	rootCmd.AddCommand(lsCmd)
 	rootCmd.AddCommand(statusCmd)
 	rootCmd.AddCommand(urlCmd)
 	rootCmd.AddCommand(openCmd)
+	rootCmd.AddCommand(shareCmd)
+	rootCmd.AddCommand(tunnelCmd)
 	rootCmd.AddCommand(attachCmd)
 	rootCmd.AddCommand(detachCmd)
 	rootCmd.AddCommand(tagCmd)
+	rootCmd.AddCommand(runCmd)
+	rootCmd.AddCommand(groupCmd)
+	rootCmd.AddCommand(portsCmd)
+	rootCmd.AddCommand(lanCmd)
+	rootCmd.AddCommand(compareCmd)
+	rootCmd.AddCommand(vrtCmd)
+	rootCmd.AddCommand(freezeCmd)
+	rootCmd.AddCommand(thawCmd)
 
 	// Worktree Management
 	newCmd.GroupID = "worktree"
@@ -78,6 +116,15 @@ func init() {
 	deleteCmd.GroupID = "worktree"
 	infoCmd.GroupID = "worktree"
 	pruneCmd.GroupID = "worktree"
+	tmuxCmd.GroupID = "worktree"
+	codeCmd.GroupID = "worktree"
+	archiveCmd.GroupID = "worktree"
+	restoreCmd.GroupID = "worktree"
+	trashCmd.GroupID = "worktree"
+	moveChangesCmd.GroupID = "worktree"
+	migrateNamesCmd.GroupID = "worktree"
+	renameCmd.GroupID = "worktree"
+	ciCmd.GroupID = "worktree"
 
 	rootCmd.AddCommand(newCmd)
 	rootCmd.AddCommand(switchCmd)
@@ -86,47 +133,154 @@ func init() {
 	rootCmd.AddCommand(deleteCmd)
 	rootCmd.AddCommand(infoCmd)
 	rootCmd.AddCommand(pruneCmd)
+	rootCmd.AddCommand(tmuxCmd)
+	rootCmd.AddCommand(codeCmd)
+	rootCmd.AddCommand(archiveCmd)
+	rootCmd.AddCommand(restoreCmd)
+	rootCmd.AddCommand(trashCmd)
+	rootCmd.AddCommand(moveChangesCmd)
+	rootCmd.AddCommand(migrateNamesCmd)
+	rootCmd.AddCommand(renameCmd)
+	rootCmd.AddCommand(ciCmd)
 
 	// Logs & Monitoring
 	logsCmd.GroupID = "monitoring"
+	searchCmd.GroupID = "monitoring"
+	crashesCmd.GroupID = "monitoring"
+	activityCmd.GroupID = "monitoring"
+	statsCmd.GroupID = "monitoring"
+	tasksCmd.GroupID = "monitoring"
+	diffEnvCmd.GroupID = "monitoring"
+	envCmd.GroupID = "monitoring"
+	smokeCmd.GroupID = "monitoring"
+	benchCmd.GroupID = "monitoring"
+	topCmd.GroupID = "monitoring"
 
 	rootCmd.AddCommand(logsCmd)
+	rootCmd.AddCommand(searchCmd)
+	rootCmd.AddCommand(crashesCmd)
+	rootCmd.AddCommand(activityCmd)
+	rootCmd.AddCommand(statsCmd)
+	rootCmd.AddCommand(tasksCmd)
+	rootCmd.AddCommand(diffEnvCmd)
+	rootCmd.AddCommand(envCmd)
+	rootCmd.AddCommand(smokeCmd)
+	rootCmd.AddCommand(benchCmd)
+	rootCmd.AddCommand(topCmd)
 
 	// Configuration
 	initCmd.GroupID = "config"
 	setupCmd.GroupID = "config"
+	templatesCmd.GroupID = "config"
+	schemaCmd.GroupID = "config"
+	exportCmd.GroupID = "config"
+	importCmd.GroupID = "config"
+	contextCmd.GroupID = "config"
 
 	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(setupCmd)
+	rootCmd.AddCommand(templatesCmd)
+	rootCmd.AddCommand(schemaCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(contextCmd)
 
 	// Proxy
 	proxyCmd.GroupID = "proxy"
+	serviceCmd.GroupID = "proxy"
 
 	rootCmd.AddCommand(proxyCmd)
+	rootCmd.AddCommand(serviceCmd)
 
 	// Maintenance
 	doctorCmd.GroupID = "maintenance"
 	cleanupCmd.GroupID = "maintenance"
+	gcCmd.GroupID = "maintenance"
+	duCmd.GroupID = "maintenance"
 	uiCmd.GroupID = "maintenance"
 	versionCmd.GroupID = "maintenance"
 	completionCmd.GroupID = "maintenance"
 	menubarCmd.GroupID = "maintenance"
+	apiCmd.GroupID = "maintenance"
 
 	rootCmd.AddCommand(doctorCmd)
 	rootCmd.AddCommand(cleanupCmd)
+	rootCmd.AddCommand(gcCmd)
+	rootCmd.AddCommand(duCmd)
 	rootCmd.AddCommand(uiCmd)
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(completionCmd)
 	rootCmd.AddCommand(menubarCmd)
+	rootCmd.AddCommand(apiCmd)
 }
 
 func initConfig() {
+	// Keep everything in another state directory, and have the grove
+	// processes this one starts (and its services) do the same
+	home := stateDir
+	if home == "" {
+		home = os.Getenv(config.HomeEnv)
+	}
+	if home != "" {
+		if err := config.SetHome(home); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: invalid state directory %s: %v\n", home, err)
+		}
+	} else {
+		// Run in the --context context, and keep the grove processes this
+		// one starts in the context it runs in, even if another is
+		// selected meanwhile
+		name := contextName
+		if name == "" {
+			name = config.CurrentContext()
+		}
+		if err := config.SetContext(name); err != nil {
+			// Running in the wrong context could act on the wrong servers
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	var err error
 	cfg, err = config.Load(cfgFile)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: could not load config: %v\n", err)
 		cfg = config.Default()
 	}
+
+	// Store the registry in registry.json or registry.db
+	if err := registry.SetBackend(cfg.RegistryBackend); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	// Color output, the TUI and the dashboard with the configured theme
+	if err := styles.Apply(cfg.ThemeName(), cfg.Palette); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	// Name worktrees with worktree_name, suffixing names taken by others
+	if err := worktree.SetNameTemplate(cfg.WorktreeName); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	worktree.SetNameResolver(resolveWorktreeName)
+
+	// Report agents started with 'grove agent launch' as soon as they run
+	discovery.SetLaunchedAgentsPath(config.AgentSessionsPath())
+
+	// Deliver lifecycle events to configured webhooks, hooks, and notifications
+	notify.Setup(cfg.Notifications)
+
+	// Run .grove.yaml's on_crash, on_healthy and on_unhealthy hooks
+	notify.SetupProjectHooks(cfg.LogDir)
+
+	// Log lifecycle events to the activity timeline (grove activity)
+	if registry.Backend() == registry.BackendSQLite {
+		events.Subscribe(registry.EventLogger())
+	} else {
+		events.Subscribe(events.Logger(config.ActivityLogPath()))
+	}
+
+	// Count lifecycle events for the dashboard's /metrics endpoint
+	metrics.Setup(config.MetricsPath())
 }
 
 func runTUI() error {
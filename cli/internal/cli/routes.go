@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/iheanyi/grove/internal/port"
+	"github.com/iheanyi/grove/internal/project"
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+// subdomainPattern matches a single lowercase DNS label
+var subdomainPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// assignPorts resolves the subdomain routes from .grove.yaml and records them
+// on the server. Targets are either a port (3101 or :3101) or a process name;
+// processes other than the web process get their own allocated port. It
+// returns the port to export as PORT for each process.
+func assignPorts(server *registry.Server, projConfig *project.Config, usedPorts map[int]bool) (map[string]int, error) {
+	processPorts := make(map[string]int)
+	if projConfig == nil {
+		return processPorts, nil
+	}
+
+	if projConfig.HasProcesses() {
+		processPorts[projConfig.GetWebProcess()] = server.Port
+	}
+
+	used := make(map[int]bool, len(usedPorts)+1)
+	for p := range usedPorts {
+		used[p] = true
+	}
+	used[server.Port] = true
+
+	subdomains := make([]string, 0, len(projConfig.Subdomains))
+	for sub := range projConfig.Subdomains {
+		subdomains = append(subdomains, sub)
+	}
+	sort.Strings(subdomains)
+
+	routes := make(map[string]int, len(subdomains))
+	for _, sub := range subdomains {
+		if !subdomainPattern.MatchString(sub) {
+			return nil, fmt.Errorf("invalid subdomain %q in %s", sub, project.ConfigFileName)
+		}
+
+		target := strings.TrimSpace(projConfig.Subdomains[sub])
+		if p, err := strconv.Atoi(strings.TrimPrefix(target, ":")); err == nil {
+			if p < 1 || p > 65535 {
+				return nil, fmt.Errorf("invalid port %d for subdomain %q", p, sub)
+			}
+			routes[sub] = p
+			continue
+		}
+
+		if _, ok := projConfig.Processes[target]; !ok {
+			return nil, fmt.Errorf("subdomain %q targets unknown process %q (expected a port or a name from processes)", sub, target)
+		}
+
+		if p, ok := processPorts[target]; ok {
+			routes[sub] = p
+			continue
+		}
+
+		allocator := port.NewAllocator(cfg.PortMin, cfg.PortMax)
+		p, err := allocator.AllocateWithFallback(fmt.Sprintf("%s-%s", server.Name, target), used)
+		if err != nil {
+			return nil, fmt.Errorf("failed to allocate port for process '%s': %w", target, err)
+		}
+		used[p] = true
+		processPorts[target] = p
+		routes[sub] = p
+	}
+
+	if len(routes) > 0 {
+		server.Subdomains = routes
+	} else {
+		server.Subdomains = nil
+	}
+
+	return processPorts, nil
+}
+
+// sortedSubdomains returns the server's routed subdomains in a stable order
+func sortedSubdomains(server *registry.Server) []string {
+	subs := make([]string, 0, len(server.Subdomains))
+	for sub := range server.Subdomains {
+		subs = append(subs, sub)
+	}
+	sort.Strings(subs)
+	return subs
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/project"
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+func TestAssignPorts(t *testing.T) {
+	origCfg := cfg
+	cfg = config.Default()
+	defer func() { cfg = origCfg }()
+
+	server := &registry.Server{Name: "feature", Port: 3100}
+	projConfig := &project.Config{
+		Processes: map[string]string{
+			"web":   "bin/rails s",
+			"admin": "bin/admin",
+			"jobs":  "bin/sidekiq",
+		},
+		Subdomains: map[string]string{
+			"app":   "web",
+			"api":   ":3101",
+			"admin": "admin",
+		},
+	}
+
+	processPorts, err := assignPorts(server, projConfig, map[int]bool{})
+	if err != nil {
+		t.Fatalf("assignPorts() failed: %v", err)
+	}
+
+	if processPorts["web"] != 3100 {
+		t.Errorf("expected web process on 3100, got %d", processPorts["web"])
+	}
+	if _, ok := processPorts["jobs"]; ok {
+		t.Error("expected jobs process to get no port")
+	}
+	adminPort := processPorts["admin"]
+	if adminPort == 0 || adminPort == 3100 {
+		t.Errorf("expected admin process to get its own port, got %d", adminPort)
+	}
+
+	if server.Subdomains["app"] != 3100 {
+		t.Errorf("expected app -> 3100, got %d", server.Subdomains["app"])
+	}
+	if server.Subdomains["api"] != 3101 {
+		t.Errorf("expected api -> 3101, got %d", server.Subdomains["api"])
+	}
+	if server.Subdomains["admin"] != adminPort {
+		t.Errorf("expected admin -> %d, got %d", adminPort, server.Subdomains["admin"])
+	}
+}
+
+func TestAssignPorts_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		subdomains map[string]string
+	}{
+		{"unknown process", map[string]string{"api": "worker"}},
+		{"invalid subdomain", map[string]string{"API_v1": "3101"}},
+		{"port out of range", map[string]string{"api": "70000"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &registry.Server{Name: "feature", Port: 3100}
+			projConfig := &project.Config{Subdomains: tt.subdomains}
+			if _, err := assignPorts(server, projConfig, nil); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
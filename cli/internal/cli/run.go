@@ -0,0 +1,216 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/iheanyi/grove/internal/port"
+	"github.com/iheanyi/grove/internal/project"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var runCmd = &cobra.Command{
+	Use:   "run [flags] -- <command...>",
+	Short: "Run a one-off command with a grove port and URL",
+	Long: `Run a command in the foreground with a temporary grove port.
+
+The command gets PORT, GROVE_URL (or url_var) and the project env from
+.grove.yaml, like 'grove start'. While it runs, it's registered as
+<worktree>-run (or --name) so it shows up in 'grove ls' and, in subdomain
+mode, is routed by the proxy. The entry is removed when the command exits,
+and grove exits with the command's status.
+
+Examples:
+  grove run -- npm run storybook     # Storybook on a grove port
+  grove run --name e2e -- npm test   # Tests against e2e.localhost
+  grove run -p 6006 -- npm run storybook`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runRun,
+}
+
+func init() {
+	runCmd.Flags().String("name", "", "Name for the temporary entry (default: <worktree>-run)")
+	runCmd.Flags().IntP("port", "p", 0, "Use this port instead of allocating one")
+}
+
+// ExitError carries a command's exit status out of grove
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("command exited with status %d", e.Code)
+}
+
+func runRun(cmd *cobra.Command, args []string) error {
+	name, _ := cmd.Flags().GetString("name")
+	portFlag, _ := cmd.Flags().GetInt("port")
+
+	wt, err := worktree.Detect()
+	if err != nil {
+		return fmt.Errorf("failed to detect worktree: %w", err)
+	}
+	if name == "" {
+		name = wt.Name + "-run"
+	}
+
+	reg, err := registry.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+	if existing, ok := reg.Get(name); ok && existing.IsRunning() {
+		return fmt.Errorf("'%s' is already running at %s; use --name to pick another name", name, existing.URL)
+	}
+
+	serverPort := portFlag
+	if serverPort == 0 {
+		serverPort, err = allocatePort(name, wt.Path, reg.GetUsedPorts())
+		if err != nil {
+			return fmt.Errorf("failed to allocate port: %w", err)
+		}
+	}
+	if !port.IsAvailable(serverPort) {
+		return fmt.Errorf("port %d is already in use", serverPort)
+	}
+
+	projConfig, _ := project.Load(wt.Path)
+	projConfig = applyDatabaseEnv(wt.Path, projConfig)
+
+	server := &registry.Server{
+		Name:      name,
+		Port:      serverPort,
+		Command:   args,
+		Path:      wt.Path,
+		URL:       cfg.ServerURL(name, serverPort),
+		Status:    registry.StatusRunning,
+		Health:    registry.HealthUnknown,
+		StartedAt: time.Now(),
+		Branch:    wt.Branch,
+		Ephemeral: true,
+	}
+
+	execCmd := exec.Command(args[0], args[1:]...)
+	execCmd.Dir = wt.Path
+	execCmd.Stdin = os.Stdin
+	execCmd.Stdout = os.Stdout
+	execCmd.Stderr = os.Stderr
+	execCmd.Env = append(os.Environ(), serverEnv(server, projConfig, server.Port)...)
+
+	// Forward interrupts to the command rather than dying before cleanup
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	if err := execCmd.Start(); err != nil {
+		return fmt.Errorf("failed to start command: %w", err)
+	}
+
+	server.PID = execCmd.Process.Pid
+	if err := reg.Set(server); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to register %s: %v\n", name, err)
+	}
+	defer removeEphemeral(name)
+
+	if cfg.UsesProxy() {
+		if err := ReloadProxy(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to reload proxy: %v\n", err)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "grove: %s running at %s\n", name, server.URL)
+
+	done := make(chan error, 1)
+	go func() { done <- execCmd.Wait() }()
+
+	for {
+		select {
+		case sig := <-sigChan:
+			execCmd.Process.Signal(sig) //nolint:errcheck // Best effort forward
+		case err := <-done:
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				// The command already reported its failure
+				cmd.SilenceErrors = true
+				return &ExitError{Code: exitErr.ExitCode()}
+			}
+			return err
+		}
+	}
+}
+
+// serverEnv returns the variables grove gives a server, to append to the
+// parent environment. Later ones win, so the precedence is: PORT (when port
+// is set) and the URL variable, then the project env (with database and
+// depends_on URLs), then env_files, then the parent environment.
+func serverEnv(server *registry.Server, projConfig *project.Config, port int) []string {
+	var env []string
+	urlVarName := "GROVE_URL"
+	if projConfig != nil {
+		vars, _, err := projConfig.EnvFileVars(server.Path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		for k, v := range vars {
+			env = append(env, fmt.Sprintf("%s=%s", k, v))
+		}
+		for k, v := range projConfig.Env {
+			env = append(env, fmt.Sprintf("%s=%s", k, v))
+		}
+		if projConfig.URLVar != "" {
+			urlVarName = projConfig.URLVar
+		}
+	}
+
+	if port > 0 {
+		env = append(env, fmt.Sprintf("PORT=%d", port))
+	}
+	return append(env, fmt.Sprintf("%s=%s", urlVarName, server.URL))
+}
+
+// envHash fingerprints an environment built by serverEnv: its resolved
+// variables, whatever order they were added in
+func envHash(env []string) string {
+	vars := make(map[string]string, len(env))
+	for _, kv := range env {
+		k, v, _ := strings.Cut(kv, "=")
+		vars[k] = v
+	}
+	names := make([]string, 0, len(vars))
+	for k := range vars {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, k := range names {
+		fmt.Fprintf(h, "%s=%s\x00", k, vars[k])
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// removeEphemeral removes a 'grove run' entry and its proxy route
+func removeEphemeral(name string) {
+	reg, err := registry.Load()
+	if err != nil {
+		return
+	}
+	if err := reg.Remove(name); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to remove %s from registry: %v\n", name, err)
+		return
+	}
+	if cfg.UsesProxy() {
+		if err := ReloadProxy(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to reload proxy: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/iheanyi/grove/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema [command]",
+	Short: "Print the JSON Schema of a command's --json output",
+	Long: `Print the JSON Schema of a command's --json/--format output.
+
+Commands with --format json|yaml return the typed responses listed by
+'grove schema'. Their progress messages go to stderr, so stdout is only the
+document. 'grove logs --json' writes one document per line.
+
+Examples:
+  grove schema                # List commands with a schema
+  grove schema stop           # Schema for 'grove stop --json'
+  grove schema proxy status   # Schema for 'grove proxy status --json'`,
+	RunE: runSchema,
+}
+
+// outputSchemas maps commands to the response they write with --json
+var outputSchemas = map[string]any{
+	"start":        output.Server{},
+	"stop":         output.StopResult{},
+	"group stop":   output.StopResult{},
+	"group status": output.GroupStatus{},
+	"delete":       output.DeleteResult{},
+	"adopt":        output.AdoptResult{},
+	"discover":     output.DiscoverResult{},
+	"proxy status": output.ProxyStatus{},
+	"proxy routes": output.ProxyRoutes{},
+	"ports":        output.PortMap{},
+	"lan":          output.LANAccess{},
+	"doctor":       output.DoctorResult{},
+	"diff-env":     output.EnvDiff{},
+	"env":          output.EnvResult{},
+	"crashes":      output.CrashList{},
+	"activity":     output.ActivityLog{},
+	"stats":        output.UsageStats{},
+	"tasks":        output.TaskList{},
+	"logs":         output.LogLine{},
+	"smoke":        output.SmokeResult{},
+	"bench":        output.BenchReport{},
+	"vrt":          output.VRTReport{},
+	"ci":           output.CIResult{},
+	"du":           output.DiskUsageReport{},
+	"freeze":       output.FreezeResult{},
+	"thaw":         output.ThawResult{},
+	"trash":        output.TrashList{},
+	"context":      output.ContextList{},
+}
+
+func runSchema(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		names := make([]string, 0, len(outputSchemas))
+		for name := range outputSchemas {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Println(name)
+		}
+		return nil
+	}
+
+	name := strings.Join(args, " ")
+	v, ok := outputSchemas[name]
+	if !ok {
+		return fmt.Errorf("no schema for '%s' (run 'grove schema' to list commands)", name)
+	}
+
+	data, err := json.MarshalIndent(output.Schema(v), "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
@@ -0,0 +1,244 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iheanyi/grove/internal/logsearch"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/styles"
+	"github.com/spf13/cobra"
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <pattern> [server...]",
+	Short: "Search the logs of all servers",
+	Long: `Search the logs of all servers (or the named ones) for a regular
+expression, including rotated logs (app.log.1, app.log.2.gz, ...).
+
+Matches are grouped by server, with the lines around them. --since and
+--until match lines by the last timestamp seen in the log, so continuation
+lines like stack traces go with the line before them.
+
+Examples:
+  grove search 'ERROR|panic'                 # Every server
+  grove search -i timeout api worker         # Only api and worker
+  grove search 'status=5\d\d' --since 1h -C 0`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSearch,
+}
+
+func init() {
+	searchCmd.Flags().IntP("context", "C", 2, "Lines of context around each match")
+	searchCmd.Flags().BoolP("ignore-case", "i", false, "Match case-insensitively")
+	searchCmd.Flags().String("since", "", "Only lines since a duration ago (e.g. 10m, 2h, 1d) or an RFC 3339 time")
+	searchCmd.Flags().String("until", "", "Only lines until a duration ago or an RFC 3339 time")
+	searchCmd.Flags().Bool("no-color", false, "Disable highlighting")
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	context, _ := cmd.Flags().GetInt("context")
+	ignoreCase, _ := cmd.Flags().GetBool("ignore-case")
+	sinceFlag, _ := cmd.Flags().GetString("since")
+	untilFlag, _ := cmd.Flags().GetString("until")
+	noColor, _ := cmd.Flags().GetBool("no-color")
+
+	pattern := args[0]
+	if ignoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid pattern: %w", err)
+	}
+
+	opts := logsearch.Options{Context: max(context, 0)}
+	now := time.Now()
+	if sinceFlag != "" {
+		if opts.Since, err = parseSince(sinceFlag, now); err != nil {
+			return err
+		}
+	}
+	if untilFlag != "" {
+		if opts.Until, err = parseSince(untilFlag, now); err != nil {
+			return fmt.Errorf("invalid --until %q (use e.g. 10m, 2h, 1d or 2006-01-02T15:04:05Z)", untilFlag)
+		}
+	}
+
+	reg, err := registry.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+	servers, err := searchServers(reg, args[1:])
+	if err != nil {
+		return err
+	}
+
+	printer := newSearchPrinter(os.Stdout, re, !noColor)
+	total, serversMatched := 0, 0
+	for _, server := range servers {
+		matches, err := logsearch.Search(server.Name, logsearch.Files(serverLogFile(server)), re, opts)
+		if err != nil {
+			fmt.Printf("Warning: failed to search %s: %v\n", server.Name, err)
+			continue
+		}
+		if len(matches) == 0 {
+			continue
+		}
+		if serversMatched > 0 {
+			fmt.Println()
+		}
+		printer.print(server.Name, matches)
+		total += len(matches)
+		serversMatched++
+	}
+
+	if total == 0 {
+		fmt.Printf("No matches in %d servers' logs\n", len(servers))
+		return nil
+	}
+	noun := "matches"
+	if total == 1 {
+		noun = "match"
+	}
+	fmt.Printf("\n%d %s in %d of %d servers\n", total, noun, serversMatched, len(servers))
+	return nil
+}
+
+// searchServers returns the named servers, or all of them, by name
+func searchServers(reg *registry.Registry, names []string) ([]*registry.Server, error) {
+	if len(names) == 0 {
+		servers := reg.List()
+		sort.Slice(servers, func(i, j int) bool { return servers[i].Name < servers[j].Name })
+		return servers, nil
+	}
+
+	var servers []*registry.Server
+	for _, name := range names {
+		server, ok := reg.Get(name)
+		if !ok {
+			return nil, fmt.Errorf("no server registered for '%s'", name)
+		}
+		servers = append(servers, server)
+	}
+	return servers, nil
+}
+
+// serverLogFile returns a server's log file, or where it would be
+func serverLogFile(server *registry.Server) string {
+	if server.LogFile != "" {
+		return server.LogFile
+	}
+	return filepath.Join(cfg.LogDir, server.Name+".log")
+}
+
+// searchPrinter prints a server's matches with their context, merging
+// overlapping context like grep
+type searchPrinter struct {
+	out   io.Writer
+	re    *regexp.Regexp
+	color bool
+
+	nameStyle  lipgloss.Style
+	fileStyle  lipgloss.Style
+	dimStyle   lipgloss.Style
+	matchStyle lipgloss.Style
+}
+
+func newSearchPrinter(out io.Writer, re *regexp.Regexp, color bool) *searchPrinter {
+	return &searchPrinter{
+		out:        out,
+		re:         re,
+		color:      color,
+		nameStyle:  styles.NameStyle,
+		fileStyle:  lipgloss.NewStyle().Foreground(styles.Muted),
+		dimStyle:   lipgloss.NewStyle().Foreground(styles.Muted),
+		matchStyle: lipgloss.NewStyle().Foreground(styles.Warning).Bold(true).Underline(true),
+	}
+}
+
+func (p *searchPrinter) render(style lipgloss.Style, s string) string {
+	if !p.color {
+		return s
+	}
+	return style.Render(s)
+}
+
+// searchLine is a line of output: a match or context
+type searchLine struct {
+	no    int
+	text  string
+	match bool
+}
+
+func (p *searchPrinter) print(server string, matches []logsearch.Match) {
+	header := fmt.Sprintf("%s (%d matches)", server, len(matches))
+	if len(matches) == 1 {
+		header = fmt.Sprintf("%s (1 match)", server)
+	}
+	fmt.Fprintln(p.out, p.render(p.nameStyle, header))
+
+	// Group lines by file, in the order the files were searched
+	var files []string
+	byFile := make(map[string]map[int]searchLine)
+	for _, m := range matches {
+		lines, ok := byFile[m.File]
+		if !ok {
+			lines = make(map[int]searchLine)
+			byFile[m.File] = lines
+			files = append(files, m.File)
+		}
+		for i, text := range m.Before {
+			no := m.LineNo - len(m.Before) + i
+			if _, ok := lines[no]; !ok {
+				lines[no] = searchLine{no: no, text: text}
+			}
+		}
+		lines[m.LineNo] = searchLine{no: m.LineNo, text: m.Line, match: true}
+		for i, text := range m.After {
+			no := m.LineNo + 1 + i
+			if _, ok := lines[no]; !ok {
+				lines[no] = searchLine{no: no, text: text}
+			}
+		}
+	}
+
+	for _, file := range files {
+		fmt.Fprintln(p.out, p.render(p.fileStyle, "  "+shortenPath(file)))
+		var nos []int
+		for no := range byFile[file] {
+			nos = append(nos, no)
+		}
+		sort.Ints(nos)
+
+		width := len(fmt.Sprint(nos[len(nos)-1]))
+		for i, no := range nos {
+			if i > 0 && no > nos[i-1]+1 {
+				fmt.Fprintln(p.out, p.render(p.dimStyle, "  --"))
+			}
+			line := byFile[file][no]
+			num := fmt.Sprintf("%*d", width, no)
+			if line.match {
+				fmt.Fprintf(p.out, "> %s  %s\n", num, p.highlight(line.text))
+			} else {
+				fmt.Fprintf(p.out, "  %s  %s\n", p.render(p.dimStyle, num), p.render(p.dimStyle, line.text))
+			}
+		}
+	}
+}
+
+// highlight emphasizes the parts of a line that match the pattern
+func (p *searchPrinter) highlight(line string) string {
+	if !p.color {
+		return line
+	}
+	return p.re.ReplaceAllStringFunc(line, func(s string) string {
+		return p.matchStyle.Render(s)
+	})
+}
@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/iheanyi/grove/internal/logsearch"
+)
+
+func TestSearchPrinter_MergesContext(t *testing.T) {
+	var buf bytes.Buffer
+	p := newSearchPrinter(&buf, regexp.MustCompile("ERROR"), false)
+	p.print("api", []logsearch.Match{
+		{File: "/logs/api.log", LineNo: 2, Line: "ERROR one", Before: []string{"a"}, After: []string{"ERROR two"}},
+		{File: "/logs/api.log", LineNo: 3, Line: "ERROR two", Before: []string{"ERROR one"}, After: []string{"b"}},
+		{File: "/logs/api.log", LineNo: 10, Line: "ERROR three", Before: []string{"c"}},
+	})
+
+	want := `api (3 matches)
+  /logs/api.log
+   1  a
+>  2  ERROR one
+>  3  ERROR two
+   4  b
+  --
+   9  c
+> 10  ERROR three
+`
+	if got := buf.String(); got != want {
+		t.Errorf("output =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -46,7 +46,9 @@ type selectItem struct {
 // Title returns plain text with status icon prefix
 func (i selectItem) Title() string {
 	statusIcon := "○"
-	if i.server.IsRunning() {
+	if i.server.IsPaused() {
+		statusIcon = "⏸"
+	} else if i.server.IsRunning() {
 		statusIcon = "●"
 	} else if i.server.Status == registry.StatusCrashed {
 		statusIcon = "✗"
@@ -65,7 +67,9 @@ func (i selectItem) FilterValue() string {
 
 // StatusIcon returns the status icon for display
 func (i selectItem) StatusIcon() string {
-	if i.server.IsRunning() {
+	if i.server.IsPaused() {
+		return "⏸"
+	} else if i.server.IsRunning() {
 		return "●"
 	} else if i.server.Status == registry.StatusCrashed {
 		return "✗"
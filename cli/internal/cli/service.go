@@ -0,0 +1,268 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/execx"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/service"
+	"github.com/spf13/cobra"
+)
+
+var serviceCmd = &cobra.Command{
+	Use:   "service",
+	Short: "Run the proxy and API at login (launchd/systemd)",
+	Long: `Install grove's long-running commands as login services, so they start at
+login and restart if they exit: launchd agents on macOS, systemd user units
+on Linux.
+
+Services:
+  proxy  The reverse proxy ('grove proxy start --foreground')
+  api    The local API used by the menubar app ('grove api')
+
+Examples:
+  grove service install            # Install and start both
+  grove service install proxy      # Only the proxy
+  grove service install --dry-run  # Print the units without installing
+  grove service status
+  grove service uninstall`,
+}
+
+var serviceInstallCmd = &cobra.Command{
+	Use:       "install [service...]",
+	Short:     "Install and start services (default: all)",
+	ValidArgs: serviceNames(),
+	RunE:      runServiceInstall,
+}
+
+var serviceUninstallCmd = &cobra.Command{
+	Use:       "uninstall [service...]",
+	Short:     "Stop and remove services (default: all)",
+	ValidArgs: serviceNames(),
+	RunE:      runServiceUninstall,
+}
+
+var serviceStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether services are installed and running",
+	RunE:  runServiceStatus,
+}
+
+func init() {
+	serviceCmd.AddCommand(serviceInstallCmd)
+	serviceCmd.AddCommand(serviceUninstallCmd)
+	serviceCmd.AddCommand(serviceStatusCmd)
+
+	serviceInstallCmd.Flags().Bool("dry-run", false, "Print the units that would be installed")
+}
+
+func serviceNames() []string {
+	var names []string
+	for _, s := range service.All() {
+		names = append(names, s.Name)
+	}
+	return names
+}
+
+// selectServices returns the named services, or all of them
+func selectServices(args []string) ([]service.Service, error) {
+	if len(args) == 0 {
+		return service.All(), nil
+	}
+	var services []service.Service
+	for _, name := range args {
+		s, ok := service.Find(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown service '%s' (available: %s)", name, strings.Join(serviceNames(), ", "))
+		}
+		services = append(services, s)
+	}
+	return services, nil
+}
+
+func runServiceInstall(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	services, err := selectServices(args)
+	if err != nil {
+		return err
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to get home directory: %w", err)
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to get executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(executable); err == nil {
+		executable = resolved
+	}
+
+	for _, s := range services {
+		path, err := service.UnitPath(runtime.GOOS, home, s)
+		if err != nil {
+			return err
+		}
+		unit, err := service.Unit(runtime.GOOS, s, service.Options{
+			Executable: executable,
+			LogFile:    filepath.Join(cfg.LogDir, "service-"+s.Name+".log"),
+			Path:       os.Getenv("PATH"),
+			Env:        serviceEnv(),
+		})
+		if err != nil {
+			return err
+		}
+
+		if dryRun {
+			fmt.Printf("# %s\n%s\n", path, unit)
+			continue
+		}
+
+		if s.Name == service.Proxy.Name {
+			warnProxyRunning()
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+		}
+		if err := os.MkdirAll(cfg.LogDir, 0755); err != nil {
+			return fmt.Errorf("failed to create log directory: %w", err)
+		}
+		// A changed unit is only picked up after unloading the old one
+		if _, err := os.Stat(path); err == nil {
+			runServiceCommands(cmd, service.UninstallCommands(runtime.GOOS, os.Getuid(), s)) //nolint:errcheck
+		}
+		if err := os.WriteFile(path, []byte(unit), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		if err := runServiceCommands(cmd, service.InstallCommands(runtime.GOOS, os.Getuid(), s, path)); err != nil {
+			return fmt.Errorf("failed to load %s: %w", s.Name, err)
+		}
+		fmt.Printf("Installed %s service (%s)\n", s.Name, path)
+	}
+	return nil
+}
+
+// warnProxyRunning warns that a proxy started with 'grove proxy start'
+// keeps the service's proxy from binding its ports
+func warnProxyRunning() {
+	reg, err := registry.Load()
+	if err != nil {
+		return
+	}
+	if proxy := reg.GetProxy(); proxy.IsRunning() && isProcessRunning(proxy.PID) {
+		fmt.Printf("Warning: the proxy is already running (PID: %d); run 'grove proxy stop' so the service can start it\n", proxy.PID)
+	}
+}
+
+func runServiceUninstall(cmd *cobra.Command, args []string) error {
+	services, err := selectServices(args)
+	if err != nil {
+		return err
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	for _, s := range services {
+		path, err := service.UnitPath(runtime.GOOS, home, s)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			if len(args) > 0 {
+				fmt.Printf("%s service is not installed\n", s.Name)
+			}
+			continue
+		}
+
+		if err := runServiceCommands(cmd, service.UninstallCommands(runtime.GOOS, os.Getuid(), s)); err != nil {
+			fmt.Printf("Warning: failed to stop %s: %v\n", s.Name, err)
+		}
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", path, err)
+		}
+		if runtime.GOOS == "linux" {
+			runServiceCommands(cmd, [][]string{{"systemctl", "--user", "daemon-reload"}}) //nolint:errcheck
+		}
+		fmt.Printf("Uninstalled %s service\n", s.Name)
+	}
+	return nil
+}
+
+func runServiceStatus(cmd *cobra.Command, args []string) error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	for _, s := range service.All() {
+		path, err := service.UnitPath(runtime.GOOS, home, s)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			fmt.Printf("%-6s not installed\n", s.Name)
+			continue
+		}
+
+		status := "installed, not running"
+		argv := service.StatusCommand(runtime.GOOS, os.Getuid(), s)
+		if err := runner.Run(cmd.Context(), execx.Query(argv[0], argv[1:]...)); err == nil {
+			status = "running"
+		}
+		fmt.Printf("%-6s %s (%s)\n", s.Name, status, path)
+	}
+	return nil
+}
+
+// runServiceCommands runs launchctl or systemctl commands in order,
+// returning the first failure with its output
+func runServiceCommands(cmd *cobra.Command, commands [][]string) error {
+	for _, argv := range commands {
+		var stderr bytes.Buffer
+		c := execx.Command(argv[0], argv[1:]...)
+		c.Stderr = &stderr
+		if err := runner.Run(cmd.Context(), c); err != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return fmt.Errorf("%s: %s", c, msg)
+			}
+			return fmt.Errorf("%s: %w", c, err)
+		}
+	}
+	return nil
+}
+
+// serviceInstalled returns true if s's unit is installed
+func serviceInstalled(s service.Service) bool {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return false
+	}
+	path, err := service.UnitPath(runtime.GOOS, home, s)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// serviceEnv keeps services in the state directory or context they were
+// installed from
+func serviceEnv() []string {
+	if home := os.Getenv(config.HomeEnv); home != "" {
+		return []string{config.HomeEnv + "=" + home}
+	}
+	if name := config.NamedContext(); name != "" {
+		return []string{config.ContextEnv + "=" + name}
+	}
+	return nil
+}
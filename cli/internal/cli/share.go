@@ -0,0 +1,253 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/tunnel"
+	"github.com/iheanyi/grove/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+// shareTimeout is how long to wait for a tunnel's public URL
+const shareTimeout = 30 * time.Second
+
+var shareCmd = &cobra.Command{
+	Use:   "share [name]",
+	Short: "Share a server publicly or on your tailnet through a tunnel",
+	Long: `Open a temporary public tunnel to a worktree's server, e.g. to show a
+preview to someone off your network. The URL is printed, copied to the
+clipboard and shown by 'grove ls', 'grove status' and the MCP tools.
+
+The tunnel uses cloudflared, Tailscale Funnel or ngrok: --provider, then
+share.provider in the config, then the first one installed. Set
+share.command to use another tool, with {port} for the server's port.
+
+With --tailscale, the server is shared only with your tailnet through
+'tailscale serve', at your machine's MagicDNS name on the server's port
+(e.g. https://laptop.tail1234.ts.net:3042), so teammates can open your
+branch without it being public.
+
+The tunnel closes with 'grove share stop' or when the server stops. With no
+name and outside a worktree, lists open shares.
+
+Examples:
+  grove share                     # Share the current worktree's server
+  grove share feature-auth        # Share a named server
+  grove share feature-auth -p ngrok
+  grove share --tailscale feature-auth   # Tailnet only
+  grove share --list              # List open shares
+  grove share stop feature-auth   # Close the tunnel`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runShare,
+}
+
+var shareStopCmd = &cobra.Command{
+	Use:   "stop [name]",
+	Short: "Close a server's public tunnel",
+	Long: `Close the tunnel opened by 'grove share'.
+
+Examples:
+  grove share stop                # Current worktree
+  grove share stop feature-auth
+  grove share stop --all`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runShareStop,
+}
+
+func init() {
+	shareCmd.Flags().StringP("provider", "p", "", "Tunnel provider: cloudflared, tailscale or ngrok")
+	shareCmd.Flags().Bool("tailscale", false, "Share only with your tailnet, through tailscale serve")
+	shareCmd.Flags().Bool("list", false, "List open shares")
+	shareStopCmd.Flags().Bool("all", false, "Close every open share")
+	shareCmd.AddCommand(shareStopCmd)
+}
+
+func runShare(cmd *cobra.Command, args []string) error {
+	providerName, _ := cmd.Flags().GetString("provider")
+	tailnet, _ := cmd.Flags().GetBool("tailscale")
+	if tailnet {
+		if providerName != "" {
+			return fmt.Errorf("--tailscale and --provider can't be combined")
+		}
+		providerName = tunnel.TailscaleServe.Name
+	}
+	listOnly, _ := cmd.Flags().GetBool("list")
+
+	reg, err := registry.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+	// Forget tunnels that exited or whose server stopped
+	if _, err := reg.Cleanup(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: cleanup failed: %v\n", err)
+	}
+
+	var name string
+	if len(args) > 0 {
+		name = args[0]
+	} else if !listOnly {
+		if wt, err := worktree.Detect(); err == nil {
+			name = wt.Name
+		}
+	}
+	if name == "" {
+		return listShares(reg)
+	}
+
+	ws, ok := reg.GetWorkspace(name)
+	if !ok || ws.Server == nil {
+		return fmt.Errorf("no server registered for '%s'", name)
+	}
+	if !ws.IsRunning() {
+		return fmt.Errorf("server '%s' is not running (start it with 'grove start')", name)
+	}
+	if ws.Share != nil {
+		fmt.Printf("'%s' is already shared at %s\n", name, describeShare(ws.Share))
+		return nil
+	}
+
+	var provider *tunnel.Provider
+	switch {
+	case providerName != "":
+		provider, err = tunnel.Find(providerName)
+	case cfg.Share.Command != "":
+		provider = tunnel.Custom(cfg.Share.Command)
+	default:
+		provider, err = tunnel.Find(cfg.Share.Provider)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(cfg.LogDir, 0755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+	logFile := filepath.Join(cfg.LogDir, fmt.Sprintf("%s-share.log", name))
+
+	fmt.Printf("Opening a %s tunnel to %s (port %d)...\n", provider.Name, name, ws.Server.Port)
+	t, err := tunnel.Start(provider, ws.Server.Port, logFile, shareTimeout)
+	if err != nil {
+		return err
+	}
+
+	// Reload in case the server changed while the tunnel was starting
+	if reg, err = registry.Load(); err == nil {
+		ws, ok = reg.GetWorkspace(name)
+	}
+	if err != nil || !ok {
+		tunnel.Stop(t.PID) //nolint:errcheck // Best effort
+		return fmt.Errorf("failed to record the share for '%s'", name)
+	}
+	ws.Share = &registry.Share{
+		Provider:  provider.Name,
+		URL:       t.URL,
+		PID:       t.PID,
+		LogFile:   logFile,
+		StartedAt: time.Now(),
+	}
+	if err := reg.SetWorkspace(ws); err != nil {
+		tunnel.Stop(t.PID) //nolint:errcheck // Best effort
+		return fmt.Errorf("failed to save to registry: %w", err)
+	}
+
+	fmt.Printf("\nShared '%s' at %s\n", name, t.URL)
+	if err := copyToClipboard(t.URL); err == nil {
+		fmt.Println("(copied to clipboard)")
+	}
+	if provider == tunnel.TailscaleServe {
+		fmt.Print("Anyone on your tailnet can open it.")
+	} else {
+		fmt.Print("Anyone with the URL can reach the server.")
+	}
+	fmt.Printf(" Close it with: grove share stop %s\n", name)
+	return nil
+}
+
+// describeShare returns a share's URL and who can open it
+func describeShare(share *registry.Share) string {
+	if share.Provider == tunnel.TailscaleServe.Name {
+		return share.URL + " (tailnet only)"
+	}
+	return share.URL + " (public, " + share.Provider + ")"
+}
+
+func listShares(reg *registry.Registry) error {
+	var shared []*registry.Workspace
+	for _, ws := range reg.ListWorkspaces() {
+		if ws.Share != nil {
+			shared = append(shared, ws)
+		}
+	}
+	if len(shared) == 0 {
+		fmt.Println("No open shares")
+		return nil
+	}
+
+	sort.Slice(shared, func(i, j int) bool { return shared[i].Name < shared[j].Name })
+	for _, ws := range shared {
+		fmt.Printf("%-24s %s, %s\n", ws.Name, describeShare(ws.Share), formatDuration(time.Since(ws.Share.StartedAt)))
+	}
+	return nil
+}
+
+func runShareStop(cmd *cobra.Command, args []string) error {
+	all, _ := cmd.Flags().GetBool("all")
+
+	reg, err := registry.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+
+	var names []string
+	switch {
+	case all:
+		for _, ws := range reg.ListWorkspaces() {
+			if ws.Share != nil {
+				names = append(names, ws.Name)
+			}
+		}
+		if len(names) == 0 {
+			fmt.Println("No open shares")
+			return nil
+		}
+	case len(args) > 0:
+		names = args
+	default:
+		wt, err := worktree.Detect()
+		if err != nil {
+			return fmt.Errorf("failed to detect worktree: %w", err)
+		}
+		names = []string{wt.Name}
+	}
+
+	for _, name := range names {
+		ws, ok := reg.GetWorkspace(name)
+		if !ok || ws.Share == nil {
+			return fmt.Errorf("'%s' is not shared", name)
+		}
+		url := ws.Share.URL
+		if err := stopShare(reg, name); err != nil {
+			return err
+		}
+		fmt.Printf("Closed %s\n", url)
+	}
+	return nil
+}
+
+// stopShare closes a workspace's tunnel, if it has one
+func stopShare(reg *registry.Registry, name string) error {
+	ws, ok := reg.GetWorkspace(name)
+	if !ok || ws.Share == nil {
+		return nil
+	}
+	if err := tunnel.Stop(ws.Share.PID); err != nil {
+		return fmt.Errorf("failed to close tunnel: %w", err)
+	}
+	ws.Share = nil
+	return reg.SetWorkspace(ws)
+}
@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"time"
+
+	"github.com/iheanyi/grove/internal/output"
+	"github.com/iheanyi/grove/internal/project"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/smoke"
+	"github.com/iheanyi/grove/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var smokeCmd = &cobra.Command{
+	Use:   "smoke [name]",
+	Short: "Run a server's HTTP smoke checks",
+	Long: `Run the HTTP checks defined under 'smoke:' in .grove.yaml against a
+running server and report which pass.
+
+Each check requests a path and can expect a status code and substrings of
+the response body. Without an expected status, any status below 400 passes.
+Projects without checks get one: the health check path, or /, must respond.
+
+Example .grove.yaml:
+  smoke:
+    - path: /
+      contains: ["<title>My App</title>"]
+    - name: login page
+      path: /login
+      status: 200
+    - path: /api/items
+      method: POST
+      headers: {Content-Type: application/json}
+      body: '{"name": "test"}'
+      status: 201
+
+Examples:
+  grove smoke                  # Check the current worktree's server
+  grove smoke feature-auth     # Check a named server
+  grove smoke --json           # Results for scripts and CI`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSmoke,
+}
+
+func init() {
+	smokeCmd.Flags().Duration("timeout", 10*time.Second, "Timeout for each check")
+	addOutputFlags(smokeCmd)
+}
+
+func runSmoke(cmd *cobra.Command, args []string) error {
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	var name string
+	if len(args) > 0 {
+		name = args[0]
+	}
+
+	return runWithOutput(cmd, func() (any, error) {
+		result, err := smokeServer(cmd.Context(), name, timeout)
+		if err != nil {
+			return nil, err
+		}
+
+		fmt.Printf("Smoke checks for %s (%s)\n\n", result.Server, result.URL)
+		failed := 0
+		for _, c := range result.Checks {
+			if c.Passed {
+				fmt.Printf("  ✓ %-30s %d (%dms)\n", c.Name, c.Status, c.DurationMs)
+				continue
+			}
+			failed++
+			fmt.Printf("  ✗ %-30s %s\n", c.Name, c.Error)
+		}
+		fmt.Println()
+
+		if failed > 0 {
+			return result, fmt.Errorf("%d of %d checks failed", failed, len(result.Checks))
+		}
+		fmt.Printf("All %d checks passed\n", len(result.Checks))
+		return result, nil
+	})
+}
+
+// smokeServer runs the smoke checks of a running server, or the current
+// worktree's when name is empty. The checks go to the server's port
+// directly, so they don't depend on the proxy.
+func smokeServer(ctx context.Context, name string, timeout time.Duration) (*output.SmokeResult, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if name == "" {
+		wt, err := worktree.Detect()
+		if err != nil {
+			return nil, fmt.Errorf("failed to detect worktree: %w", err)
+		}
+		name = wt.Name
+	}
+
+	reg, err := registry.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load registry: %w", err)
+	}
+	server, ok := reg.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("no server registered for '%s'\nUse 'grove start' to start a server first", name)
+	}
+	if !server.IsRunning() {
+		return nil, fmt.Errorf("server '%s' is not running\nUse 'grove start' to start it", name)
+	}
+
+	projConfig, err := project.Load(server.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		projConfig, err = &project.Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load .grove.yaml: %w", err)
+	}
+	checks := projConfig.Smoke
+	if len(checks) == 0 {
+		checks = smoke.DefaultChecks(projConfig)
+	}
+
+	url := fmt.Sprintf("http://localhost:%d", server.Port)
+	client := &http.Client{Timeout: timeout}
+	results := smoke.Run(ctx, client, url, checks)
+
+	return &output.SmokeResult{
+		Server: server.Name,
+		URL:    url,
+		Passed: smoke.Passed(results),
+		Checks: results,
+	}, nil
+}
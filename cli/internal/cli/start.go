@@ -1,16 +1,23 @@
 package cli
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
 	"os/signal"
 	"path/filepath"
 	"strings"
 	"syscall"
 	"time"
 
+	"github.com/iheanyi/grove/internal/crash"
 	"github.com/iheanyi/grove/internal/discovery"
+	"github.com/iheanyi/grove/internal/events"
+	"github.com/iheanyi/grove/internal/execx"
+	"github.com/iheanyi/grove/internal/git"
+	"github.com/iheanyi/grove/internal/health"
+	"github.com/iheanyi/grove/internal/output"
 	"github.com/iheanyi/grove/internal/port"
 	"github.com/iheanyi/grove/internal/project"
 	"github.com/iheanyi/grove/internal/registry"
@@ -19,13 +26,34 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// runner runs server commands and finds their processes; tests swap in an
+// execx.Fake
+var runner execx.Runner = execx.OS
+
+// gitOps returns the Git that runs git with runner
+func gitOps() git.Git {
+	return git.New(runner)
+}
+
 var startCmd = &cobra.Command{
 	Use:   "start [command...]",
 	Short: "Start a dev server for the current worktree",
 	Long: `Start a dev server for the current worktree.
 
 If a .grove.yaml file exists and defines a command, it will be used by default.
-Otherwise, you must provide a command.
+Otherwise, you must provide a command; in a terminal, grove offers the
+commands it detects (package.json scripts, bin/dev, manage.py, Makefile
+targets, ...) and can save your pick to .grove.yaml. With 'backend: compose' in .grove.yaml,
+the worktree runs as its own Docker Compose project.
+
+If .grove.yaml defines processes (Procfile-style), they all run under one
+server entry with prefixed, combined logs. Only the web process (named "web",
+or set with web_process) receives PORT:
+
+  processes:
+    web: bin/rails s
+    jobs: bin/sidekiq
+    assets: bin/vite dev
 
 Examples:
   grove start                  # Use command from .grove.yaml
@@ -39,40 +67,106 @@ func init() {
 	startCmd.Flags().IntP("port", "p", 0, "Override port allocation")
 	startCmd.Flags().BoolP("foreground", "f", false, "Run in foreground (don't daemonize)")
 	startCmd.Flags().BoolP("open", "o", false, "Open browser after server starts")
+	startCmd.Flags().Bool("supervise", false, "Run as the process supervisor for a daemonized multi-process server")
+	startCmd.Flags().MarkHidden("supervise") //nolint:errcheck
+	addOutputFlags(startCmd)
 }
 
 func runStart(cmd *cobra.Command, args []string) error {
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	if foreground, _ := cmd.Flags().GetBool("foreground"); foreground && format.IsMachine() {
+		return fmt.Errorf("--format %s can't be used with --foreground", format)
+	}
+
+	return runWithOutput(cmd, func() (any, error) {
+		server, err := startWorktree(cmd, args)
+		if err != nil {
+			return nil, err
+		}
+		// Daemonized servers are updated by their own process; report the
+		// registry's view
+		if reg, err := registry.Load(); err == nil {
+			if s, ok := reg.Get(server.Name); ok {
+				server = s
+			}
+		}
+		return output.NewServer(server), nil
+	})
+}
+
+// startWorktree starts the current worktree's server and returns it
+func startWorktree(cmd *cobra.Command, args []string) (*registry.Server, error) {
 	// Detect worktree
 	wt, err := worktree.Detect()
 	if err != nil {
-		return fmt.Errorf("failed to detect worktree: %w", err)
+		return nil, fmt.Errorf("failed to detect worktree: %w", err)
 	}
 
 	// Load project config if exists
 	projConfig, _ := project.Load(wt.Path)
 
+	// With nothing to start, offer the commands the project looks like it
+	// runs with
+	if len(args) == 0 && !hasStartCommand(projConfig) && isInteractive() {
+		if format, _ := outputFormat(cmd); !format.IsMachine() {
+			picked, err := pickStartCommand(wt.Name, wt.Path)
+			if err != nil {
+				return nil, err
+			}
+			if picked != nil {
+				projConfig = picked
+			}
+		}
+	}
+	projConfig = applyDatabaseEnv(wt.Path, projConfig)
+
 	// Determine command to run
 	var command []string
+	multiProcess := false
+	useCompose := false
 	if len(args) > 0 {
 		command = args
+	} else if projConfig != nil && projConfig.IsCompose() {
+		useCompose = true
+	} else if projConfig != nil && projConfig.HasProcesses() {
+		multiProcess = true
 	} else if projConfig != nil && projConfig.Command != "" {
 		command = []string{projConfig.Command}
 	} else {
-		return fmt.Errorf("no command specified and no .grove.yaml found\nUsage: grove start <command>")
+		return nil, fmt.Errorf("no command specified and no .grove.yaml found\nUsage: grove start <command>")
 	}
 
 	// Load registry
 	reg, err := registry.Load()
 	if err != nil {
-		return fmt.Errorf("failed to load registry: %w", err)
+		return nil, fmt.Errorf("failed to load registry: %w", err)
 	}
 
 	// Check if already running
 	if existing, ok := reg.Get(wt.Name); ok && existing.IsRunning() {
-		return fmt.Errorf("server '%s' is already running at %s (port %d)\nUse 'grove stop' to stop it first, or 'grove restart' to restart",
+		return nil, fmt.Errorf("server '%s' is already running at %s (port %d)\nUse 'grove stop' to stop it first, or 'grove restart' to restart",
 			wt.Name, existing.URL, existing.Port)
 	}
 
+	// The supervisor is started by a daemonized 'grove start', which has
+	// already run the before_start hooks and started dependencies
+	supervise, _ := cmd.Flags().GetBool("supervise")
+
+	// Bring up depends_on servers first. They're started by their own
+	// 'grove start', so reload the registry they update.
+	if !supervise && projConfig != nil && len(projConfig.DependsOn) > 0 {
+		if err := startDependencies(wt.Name, projConfig); err != nil {
+			return nil, err
+		}
+		if reg, err = registry.Load(); err != nil {
+			return nil, fmt.Errorf("failed to load registry: %w", err)
+		}
+	}
+	applyDependencyEnv(projConfig, reg)
+
 	// Allocate port
 	portFlag, _ := cmd.Flags().GetInt("port")
 	var serverPort int
@@ -81,31 +175,41 @@ func runStart(cmd *cobra.Command, args []string) error {
 		serverPort = portFlag
 	} else if projConfig != nil && projConfig.Port > 0 {
 		serverPort = projConfig.Port
-	} else if existing, ok := reg.Get(wt.Name); ok && existing.Port > 0 {
+	} else if existing, ok := reg.Get(wt.Name); ok && existing.Port > 0 && inPortBlock(existing.Port, wt.Path) {
 		// Reuse existing port from stopped server
 		serverPort = existing.Port
 	} else {
-		allocator := port.NewAllocator(cfg.PortMin, cfg.PortMax)
-		serverPort, err = allocator.AllocateWithFallback(wt.Name, reg.GetUsedPorts())
+		used := reg.GetUsedPorts()
+		if existing, ok := reg.Get(wt.Name); ok {
+			// Moving into the repository's port_bases block frees the old port
+			delete(used, existing.Port)
+		}
+		serverPort, err = allocatePort(wt.Name, wt.Path, used)
 		if err != nil {
-			return fmt.Errorf("failed to allocate port: %w", err)
+			return nil, fmt.Errorf("failed to allocate port: %w", err)
 		}
 	}
 
 	// Check if port is available
 	if !port.IsAvailable(serverPort) {
-		return fmt.Errorf("port %d is already in use", serverPort)
+		return nil, fmt.Errorf("port %d is already in use", serverPort)
 	}
 
 	// Build URL based on configured mode
 	url := cfg.ServerURL(wt.Name, serverPort)
 
-	// Run before_start hooks
-	if projConfig != nil && len(projConfig.Hooks.BeforeStart) > 0 {
+	// Run global pre-start hooks, then the project's before_start hooks
+	if !supervise {
+		target := hookTarget{Name: wt.Name, Path: wt.Path, Branch: wt.Branch, Port: serverPort, URL: url}
+		if err := runGlobalHooks(hookPreStart, target, os.Stdout); err != nil {
+			return nil, fmt.Errorf("pre-start hook failed: %w", err)
+		}
+	}
+	if !supervise && projConfig != nil && len(projConfig.Hooks.BeforeStart) > 0 {
 		fmt.Println("Running before_start hooks...")
 		for _, hook := range projConfig.Hooks.BeforeStart {
 			if err := runHook(hook, wt.Path); err != nil {
-				return fmt.Errorf("before_start hook failed: %w", err)
+				return nil, fmt.Errorf("before_start hook failed: %w", err)
 			}
 		}
 	}
@@ -113,7 +217,7 @@ func runStart(cmd *cobra.Command, args []string) error {
 	// Create log file
 	logDir := filepath.Join(cfg.LogDir)
 	if err := os.MkdirAll(logDir, 0755); err != nil {
-		return fmt.Errorf("failed to create log directory: %w", err)
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
 	}
 	logFile := filepath.Join(logDir, fmt.Sprintf("%s.log", wt.Name))
 
@@ -136,68 +240,113 @@ func runStart(cmd *cobra.Command, args []string) error {
 		LogFile:   logFile,
 	}
 
+	// Resolve subdomain routes and per-process ports from .grove.yaml
+	processPorts, err := assignPorts(server, projConfig, reg.GetUsedPorts())
+	if err != nil {
+		return nil, err
+	}
+	server.EnvHash = envHash(serverEnv(server, projConfig, server.Port))
+
+	if useCompose {
+		return server, runCompose(server, reg, projConfig, foreground, openBrowser)
+	}
+
+	if multiProcess {
+		if foreground || supervise {
+			return server, runProcesses(server, reg, projConfig, processPorts, openBrowser && !supervise, supervise)
+		}
+		return server, runProcessesDaemon(server, projConfig, openBrowser)
+	}
+
 	if foreground {
 		// Run in foreground
-		return runForeground(server, reg, projConfig, openBrowser)
+		return server, runForeground(server, reg, projConfig, openBrowser)
 	}
 
 	// Run as daemon
-	return runDaemon(server, reg, projConfig, openBrowser)
+	return server, runDaemon(server, reg, projConfig, openBrowser)
 }
 
 func runForeground(server *registry.Server, reg *registry.Registry, projConfig *project.Config, openBrowser bool) error {
+	ready, err := readyPattern(projConfig)
+	if err != nil {
+		return err
+	}
+	fatal, err := fatalPatterns(projConfig)
+	if err != nil {
+		return err
+	}
+
 	// Build command
 	cmdName := server.Command[0]
 	cmdArgs := server.Command[1:]
 
-	execCmd := exec.Command(cmdName, cmdArgs...)
-	execCmd.Dir = server.Path
-	execCmd.Stdout = os.Stdout
-	execCmd.Stderr = os.Stderr
-	execCmd.Stdin = os.Stdin
-
-	// Set environment
-	execCmd.Env = append(os.Environ(),
-		fmt.Sprintf("PORT=%d", server.Port),
-	)
+	// Keep the last lines of output for a crash report, and flag the
+	// server as soon as it logs a fatal error
+	tail := crash.NewTail(crash.LogLines)
+	fatalLines := fatalWatcher(server, "", fatal)
+	stdout := io.MultiWriter(os.Stdout, tail, fatalLines)
+	stderr := io.MultiWriter(os.Stderr, tail, fatalLines)
 
-	// Inject GROVE_URL (or custom var name from config)
-	urlVarName := "GROVE_URL"
-	if projConfig != nil && projConfig.URLVar != "" {
-		urlVarName = projConfig.URLVar
+	// Watch the output for the ready line
+	var matcher *health.LogMatcher
+	if ready != nil {
+		matcher = health.NewLogMatcher(ready)
+		stdout = io.MultiWriter(stdout, matcher)
+		stderr = io.MultiWriter(stderr, matcher)
 	}
-	execCmd.Env = append(execCmd.Env, fmt.Sprintf("%s=%s", urlVarName, server.URL))
 
-	// Add project-specific env vars
-	if projConfig != nil {
-		for k, v := range projConfig.Env {
-			execCmd.Env = append(execCmd.Env, fmt.Sprintf("%s=%s", k, v))
-		}
-	}
+	execCmd := execx.Command(cmdName, cmdArgs...)
+	execCmd.Dir = server.Path
+	execCmd.Stdout = stdout
+	execCmd.Stderr = stderr
+	execCmd.Stdin = os.Stdin
+
+	// Set environment: PORT, GROVE_URL (or url_var), env and env_files
+	execCmd.Env = append(os.Environ(), serverEnv(server, projConfig, server.Port)...)
 
 	// Handle signals
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
 	// Start process
-	if err := execCmd.Start(); err != nil {
+	proc, err := runner.Start(execCmd)
+	if err != nil {
 		return fmt.Errorf("failed to start server: %w", err)
 	}
 
-	server.PID = execCmd.Process.Pid
-	server.Status = registry.StatusRunning
+	server.PID = proc.Pid()
+	if ready == nil {
+		server.Status = registry.StatusRunning
+	}
 
 	// Save to registry
 	if err := reg.Set(server); err != nil {
-		execCmd.Process.Kill() //nolint:errcheck // Cleanup on error path
+		proc.Kill() //nolint:errcheck // Cleanup on error path
 		return fmt.Errorf("failed to save to registry: %w", err)
 	}
 
+	// It stays starting until it prints its ready line
+	stopped := make(chan struct{})
+	defer close(stopped)
+	if matcher != nil {
+		go func(name string, pid int, timeout time.Duration) {
+			select {
+			case <-matcher.Matched():
+				markStarted(name, pid, true) //nolint:errcheck // Best effort status update
+			case <-time.After(timeout):
+				fmt.Fprintf(os.Stderr, "Warning: no output matched ready_log_pattern within %s; marking '%s' running\n", timeout, name)
+				markStarted(name, pid, false) //nolint:errcheck // Best effort status update
+			case <-stopped:
+			}
+		}(server.Name, server.PID, readyTimeout(projConfig))
+	}
+
 	// Auto-register worktree with main_repo for proper grouping
 	registerWorktree(reg, server)
 
-	// Reload proxy to pick up new route (only in subdomain mode)
-	if cfg.IsSubdomainMode() {
+	// Reload proxy to pick up new route (only when proxied)
+	if cfg.UsesProxy() {
 		if err := ReloadProxy(); err != nil {
 			fmt.Printf("Warning: failed to reload proxy: %v\n", err)
 			fmt.Println("Run 'grove proxy stop && grove proxy start' to update routes manually")
@@ -211,6 +360,9 @@ func runForeground(server *registry.Server, reg *registry.Registry, projConfig *
 	fmt.Printf("PID: %d\n", server.PID)
 	fmt.Println("Press Ctrl+C to stop...")
 
+	events.Publish(server.Event(events.ServerStarted))
+	runPostStartHooks(server)
+
 	// Open browser if requested
 	if openBrowser {
 		fmt.Printf("Opening %s in browser...\n", server.URL)
@@ -222,13 +374,16 @@ func runForeground(server *registry.Server, reg *registry.Registry, projConfig *
 	// Wait for signal or process exit
 	done := make(chan error, 1)
 	go func() {
-		done <- execCmd.Wait()
+		done <- proc.Wait()
 	}()
 
+	var exitErr error
+	var exitCode *int
+
 	select {
 	case <-sigChan:
 		fmt.Println("\nStopping server...")
-		if err := execCmd.Process.Signal(syscall.SIGTERM); err != nil {
+		if err := proc.Signal(syscall.SIGTERM); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to send SIGTERM: %v\n", err)
 		}
 
@@ -236,13 +391,20 @@ func runForeground(server *registry.Server, reg *registry.Registry, projConfig *
 		select {
 		case <-done:
 		case <-time.After(5 * time.Second):
-			if err := execCmd.Process.Kill(); err != nil {
+			if err := proc.Kill(); err != nil {
 				fmt.Fprintf(os.Stderr, "Warning: failed to kill process: %v\n", err)
 			}
 		}
 	case err := <-done:
 		if err != nil {
 			server.Status = registry.StatusCrashed
+			exitErr = err
+
+			report := crash.New(server.Name, "", server.StartedAt, err)
+			report.Log = tail.Lines()
+			report.Fatal = fatalLines.Matches()
+			saveCrashReport(report)
+			exitCode = report.ExitCode
 		} else {
 			server.Status = registry.StatusStopped
 		}
@@ -256,8 +418,17 @@ func runForeground(server *registry.Server, reg *registry.Registry, projConfig *
 		fmt.Fprintf(os.Stderr, "Warning: failed to update registry: %v\n", err)
 	}
 
-	// Reload proxy to remove route (only in subdomain mode)
-	if cfg.IsSubdomainMode() {
+	if exitErr != nil {
+		e := server.Event(events.ServerCrashed)
+		e.Message = exitErr.Error()
+		e.ExitCode = exitCode
+		events.Publish(e)
+	} else {
+		events.Publish(server.Event(events.ServerStopped))
+	}
+
+	// Reload proxy to remove route (only when proxied)
+	if cfg.UsesProxy() {
 		if err := ReloadProxy(); err != nil {
 			fmt.Printf("Warning: failed to reload proxy: %v\n", err)
 		}
@@ -276,11 +447,21 @@ func runForeground(server *registry.Server, reg *registry.Registry, projConfig *
 }
 
 func runDaemon(server *registry.Server, reg *registry.Registry, projConfig *project.Config, openBrowser bool) error {
+	ready, err := readyPattern(projConfig)
+	if err != nil {
+		return err
+	}
+
 	// Open log file
 	logFile, err := os.OpenFile(server.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 	if err != nil {
 		return fmt.Errorf("failed to open log file: %w", err)
 	}
+	// The ready line is looked for in what this run writes
+	var logOffset int64
+	if info, err := logFile.Stat(); err == nil {
+		logOffset = info.Size()
+	}
 
 	// Use nohup approach: wrap the command in a shell that uses tail -f /dev/null
 	// to keep stdin open forever. This prevents processes like esbuild --watch
@@ -288,47 +469,32 @@ func runDaemon(server *registry.Server, reg *registry.Registry, projConfig *proj
 	// so the recorded PID becomes the actual server process PID.
 	shellCmd := fmt.Sprintf("tail -f /dev/null | exec %s", shellQuoteArgs(server.Command))
 
-	execCmd := exec.Command("/bin/sh", "-c", shellCmd)
+	execCmd := execx.Command("/bin/sh", "-c", shellCmd)
 	execCmd.Dir = server.Path
 	execCmd.Stdout = logFile
 	execCmd.Stderr = logFile
 
-	// Set environment
-	execCmd.Env = append(os.Environ(),
-		fmt.Sprintf("PORT=%d", server.Port),
-	)
-
-	// Inject GROVE_URL (or custom var name from config)
-	urlVarName := "GROVE_URL"
-	if projConfig != nil && projConfig.URLVar != "" {
-		urlVarName = projConfig.URLVar
-	}
-	execCmd.Env = append(execCmd.Env, fmt.Sprintf("%s=%s", urlVarName, server.URL))
-
-	// Add project-specific env vars
-	if projConfig != nil {
-		for k, v := range projConfig.Env {
-			execCmd.Env = append(execCmd.Env, fmt.Sprintf("%s=%s", k, v))
-		}
-	}
+	// Set environment: PORT, GROVE_URL (or url_var), env and env_files
+	execCmd.Env = append(os.Environ(), serverEnv(server, projConfig, server.Port)...)
 
 	// Start as a new process group so it survives parent exit
-	execCmd.SysProcAttr = &syscall.SysProcAttr{
-		Setpgid: true,
-	}
+	execCmd.Setpgid = true
 
 	// Start process
-	if err := execCmd.Start(); err != nil {
+	proc, err := runner.Start(execCmd)
+	if err != nil {
 		logFile.Close()
 		return fmt.Errorf("failed to start server: %w", err)
 	}
 
-	server.PID = execCmd.Process.Pid
-	server.Status = registry.StatusRunning
+	server.PID = proc.Pid()
+	if ready == nil {
+		server.Status = registry.StatusRunning
+	}
 
 	// Save to registry
 	if err := reg.Set(server); err != nil {
-		execCmd.Process.Kill() //nolint:errcheck // Cleanup on error path
+		proc.Kill() //nolint:errcheck // Cleanup on error path
 		logFile.Close()
 		return fmt.Errorf("failed to save to registry: %w", err)
 	}
@@ -336,14 +502,23 @@ func runDaemon(server *registry.Server, reg *registry.Registry, projConfig *proj
 	// Auto-register worktree with main_repo for proper grouping
 	registerWorktree(reg, server)
 
-	// Detach from process - the process will continue running
-	if err := execCmd.Process.Release(); err != nil {
+	autoGC(reg)
+
+	if ready != nil {
+		// Waiting on the process notices it exiting before it's ready; it
+		// keeps running after grove exits all the same
+		if err := waitForReadyLog(server, proc, ready, logOffset, readyTimeout(projConfig)); err != nil {
+			logFile.Close()
+			return err
+		}
+	} else if err := proc.Release(); err != nil {
+		// Detach from process - the process will continue running
 		fmt.Fprintf(os.Stderr, "Warning: failed to release process: %v\n", err)
 	}
 	logFile.Close()
 
-	// Reload proxy to pick up new route (only in subdomain mode)
-	if cfg.IsSubdomainMode() {
+	// Reload proxy to pick up new route (only when proxied)
+	if cfg.UsesProxy() {
 		if err := ReloadProxy(); err != nil {
 			fmt.Printf("Warning: failed to reload proxy: %v\n", err)
 			fmt.Println("Run 'grove proxy stop && grove proxy start' to update routes manually")
@@ -357,6 +532,8 @@ func runDaemon(server *registry.Server, reg *registry.Registry, projConfig *proj
 	fmt.Printf("PID: %d\n", server.PID)
 	fmt.Printf("Logs: %s\n", server.LogFile)
 
+	events.Publish(server.Event(events.ServerStarted))
+
 	// Run after_start hooks
 	if projConfig != nil && len(projConfig.Hooks.AfterStart) > 0 {
 		fmt.Println("Running after_start hooks...")
@@ -366,6 +543,7 @@ func runDaemon(server *registry.Server, reg *registry.Registry, projConfig *proj
 			}
 		}
 	}
+	runPostStartHooks(server)
 
 	// Open browser if requested
 	if openBrowser {
@@ -390,11 +568,11 @@ func shellQuoteArgs(args []string) string {
 }
 
 func runHook(hook string, dir string) error {
-	cmd := exec.Command("sh", "-c", hook)
+	cmd := execx.Command("sh", "-c", hook)
 	cmd.Dir = dir
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	return runner.Run(context.Background(), cmd)
 }
 
 // registerWorktree ensures the worktree is registered with main_repo for proper grouping.
@@ -408,8 +586,8 @@ func registerWorktree(reg *registry.Registry, server *registry.Server) {
 
 	// Check if worktree already exists with correct main_repo
 	existing, ok := reg.GetWorktree(server.Name)
-	if ok && existing.MainRepo != "" {
-		return // Already has main_repo, no update needed
+	if ok && existing.MainRepo != "" && existing.Repo != "" {
+		return // Already has main_repo and repo, no update needed
 	}
 
 	// Create or update worktree entry
@@ -419,6 +597,7 @@ func registerWorktree(reg *registry.Registry, server *registry.Server) {
 		Path:         server.Path,
 		Branch:       server.Branch,
 		MainRepo:     wt.MainWorktreePath,
+		Repo:         wt.Repo,
 		DiscoveredAt: now,
 		LastActivity: now,
 		HasServer:    true,
@@ -430,6 +609,7 @@ func registerWorktree(reg *registry.Registry, server *registry.Server) {
 		wtEntry.HasClaude = existing.HasClaude
 		wtEntry.HasVSCode = existing.HasVSCode
 		wtEntry.GitDirty = existing.GitDirty
+		wtEntry.DirtyFiles = existing.DirtyFiles
 	}
 
 	// Save worktree (ignore errors - this is best-effort)
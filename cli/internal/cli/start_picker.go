@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iheanyi/grove/internal/project"
+	"github.com/iheanyi/grove/internal/styles"
+)
+
+// commandPickerModel lets the user pick one of the commands detected in a
+// project that has nothing configured to start
+type commandPickerModel struct {
+	name   string
+	stacks []*project.DetectedStack
+	cursor int
+	chosen *project.DetectedStack
+	done   bool
+}
+
+func newCommandPickerModel(name string, stacks []*project.DetectedStack) commandPickerModel {
+	return commandPickerModel{name: name, stacks: stacks}
+}
+
+func (m commandPickerModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m commandPickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.stacks)-1 {
+			m.cursor++
+		}
+	case "enter":
+		m.chosen = m.stacks[m.cursor]
+		m.done = true
+		return m, tea.Quit
+	case "q", "esc", "ctrl+c":
+		m.done = true
+		return m, tea.Quit
+	default:
+		// 1-9 pick a command directly
+		if k := keyMsg.String(); len(k) == 1 && k[0] >= '1' && int(k[0]-'0') <= len(m.stacks) {
+			n := int(k[0] - '0')
+			m.cursor = n - 1
+			m.chosen = m.stacks[m.cursor]
+			m.done = true
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+func (m commandPickerModel) View() string {
+	if m.done {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true)
+	selectedStyle := lipgloss.NewStyle().Foreground(styles.Accent).Bold(true)
+	mutedStyle := lipgloss.NewStyle().Foreground(styles.Muted)
+
+	width := 0
+	for _, s := range m.stacks {
+		width = max(width, len(describeStack(s)))
+	}
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(fmt.Sprintf("No command configured for %s. Start it with:", m.name)))
+	b.WriteString("\n\n")
+	for i, s := range m.stacks {
+		line := fmt.Sprintf("%d. %-*s  ", i+1, width, describeStack(s))
+		source := mutedStyle.Render(fmt.Sprintf("%s (%s)", s.Stack, s.Source))
+		if i == m.cursor {
+			b.WriteString(selectedStyle.Render("> "+line) + source)
+		} else {
+			b.WriteString("  " + line + source)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(mutedStyle.Render("↑/↓ move · enter start · q cancel"))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// pickStartCommand offers the commands detected in dir when there's nothing
+// configured to start, and saves the choice to .grove.yaml if asked. It
+// returns nil if nothing was detected or the picker was cancelled.
+func pickStartCommand(name, dir string) (*project.Config, error) {
+	stacks := project.DetectStacks(dir)
+	if len(stacks) == 0 {
+		return nil, nil
+	}
+
+	final, err := tea.NewProgram(newCommandPickerModel(name, stacks)).Run()
+	if err != nil {
+		return nil, err
+	}
+	chosen := final.(commandPickerModel).chosen
+	if chosen == nil {
+		return nil, nil
+	}
+
+	fmt.Printf("Starting with: %s\n", describeStack(chosen))
+	if !promptYesNo(fmt.Sprintf("Save it to %s?", project.ConfigFileName), true) {
+		chosen.Config.SetDefaults()
+		return chosen.Config, nil
+	}
+
+	// Keep the name of an existing .grove.yaml (e.g. one 'grove init'
+	// created next to a team config without a command)
+	path := filepath.Join(dir, project.ConfigFileName)
+	if existing, err := project.LoadFile(path); err == nil {
+		chosen.Config.Name = existing.Name
+	}
+	if err := chosen.Config.Save(dir); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", project.ConfigFileName, err)
+	}
+	fmt.Printf("Saved to %s\n", path)
+	return project.Load(dir)
+}
+
+// hasStartCommand returns true if cfg says how to start the server
+func hasStartCommand(cfg *project.Config) bool {
+	return cfg != nil && (cfg.IsCompose() || cfg.HasProcesses() || cfg.Command != "")
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/iheanyi/grove/internal/project"
+)
+
+func TestCommandPickerModel(t *testing.T) {
+	stacks := []*project.DetectedStack{
+		{Stack: "node", Source: "package.json scripts.dev", Config: &project.Config{Command: "npm run dev"}},
+		{Stack: "node", Source: "package.json scripts.start", Config: &project.Config{Command: "npm run start"}},
+		{Stack: "make", Source: "Makefile", Config: &project.Config{Command: "make dev"}},
+	}
+	key := func(m commandPickerModel, k string) commandPickerModel {
+		t.Helper()
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		switch k {
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		case "up":
+			msg = tea.KeyMsg{Type: tea.KeyUp}
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		}
+		updated, _ := m.Update(msg)
+		return updated.(commandPickerModel)
+	}
+
+	m := newCommandPickerModel("app", stacks)
+	view := ansi.Strip(m.View())
+	for _, want := range []string{"No command configured for app", "> 1. npm run dev", "make (Makefile)"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+
+	m = key(key(key(key(m, "down"), "down"), "down"), "up")
+	if m.cursor != 1 {
+		t.Errorf("cursor = %d, want 1", m.cursor)
+	}
+	if m = key(m, "enter"); m.chosen != stacks[1] || !m.done {
+		t.Errorf("enter chose %v", m.chosen)
+	}
+
+	if m = key(newCommandPickerModel("app", stacks), "3"); m.chosen != stacks[2] {
+		t.Errorf("3 chose %v", m.chosen)
+	}
+	if m = key(newCommandPickerModel("app", stacks), "9"); m.chosen != nil || m.done {
+		t.Errorf("9 chose %v", m.chosen)
+	}
+	if m = key(newCommandPickerModel("app", stacks), "q"); m.chosen != nil || !m.done {
+		t.Errorf("q chose %v", m.chosen)
+	}
+}
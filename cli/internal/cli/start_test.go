@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/adrg/xdg"
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/project"
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+// useTestEnv points the config, config dir and registry at temporary
+// directories, so starting and stopping servers doesn't touch the user's
+func useTestEnv(t *testing.T) *registry.Registry {
+	t.Helper()
+
+	origCfg, origHome := cfg, xdg.ConfigHome
+	t.Cleanup(func() { cfg, xdg.ConfigHome = origCfg, origHome })
+	t.Setenv(config.HomeEnv, "")
+	t.Setenv(config.ContextEnv, "")
+	xdg.ConfigHome = t.TempDir()
+	cfg = config.Default()
+	cfg.LogDir = t.TempDir()
+
+	reg, err := registry.LoadFrom(filepath.Join(t.TempDir(), "registry.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reg
+}
+
+func TestRunDaemon(t *testing.T) {
+	fake := useFakeRunner(t)
+	reg := useTestEnv(t)
+	fake.On("sh -c echo started", "started\n", nil)
+
+	dir := t.TempDir()
+	server := &registry.Server{
+		Name:    "api",
+		Path:    dir,
+		Port:    3001,
+		URL:     "http://localhost:3001",
+		Command: []string{"npm", "run", "dev", "--", "--host", "it's"},
+		LogFile: filepath.Join(cfg.LogDir, "api.log"),
+		Status:  registry.StatusStarting,
+	}
+	projConfig := &project.Config{
+		URLVar: "APP_URL",
+		Env:    map[string]string{"RAILS_ENV": "development"},
+		Hooks:  project.HooksConfig{AfterStart: []string{"echo started"}},
+	}
+
+	if err := runDaemon(server, reg, projConfig, false); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("commands = %q, want the server and its hook", fake.Commands())
+	}
+	cmd := calls[0]
+	if want := []string{"-c", `tail -f /dev/null | exec 'npm' 'run' 'dev' '--' '--host' 'it'\''s'`}; cmd.Name != "/bin/sh" || !slices.Equal(cmd.Args, want) {
+		t.Errorf("command = %s %q, want /bin/sh %q", cmd.Name, cmd.Args, want)
+	}
+	if cmd.Dir != dir || !cmd.Setpgid {
+		t.Errorf("Dir = %q, Setpgid = %v; want %q in its own process group", cmd.Dir, cmd.Setpgid, dir)
+	}
+	for _, env := range []string{"PORT=3001", "APP_URL=http://localhost:3001", "RAILS_ENV=development"} {
+		if !slices.Contains(cmd.Env, env) {
+			t.Errorf("Env is missing %s", env)
+		}
+	}
+	if f, ok := cmd.Stdout.(*os.File); !ok || f.Name() != server.LogFile || cmd.Stderr != cmd.Stdout {
+		t.Errorf("output isn't sent to the log file: %v, %v", cmd.Stdout, cmd.Stderr)
+	}
+	if hook := calls[1]; hook.String() != "sh -c echo started" || hook.Dir != dir {
+		t.Errorf("hook = %s in %q", hook.String(), hook.Dir)
+	}
+
+	proc, ok := fake.Process(server.PID)
+	if !ok || !proc.Released() || proc.Exited() {
+		t.Errorf("server process %d wasn't started and released", server.PID)
+	}
+	saved, ok := reg.Get("api")
+	if !ok || saved.Status != registry.StatusRunning || saved.PID != server.PID {
+		t.Errorf("registry server = %+v, want running with PID %d", saved, server.PID)
+	}
+}
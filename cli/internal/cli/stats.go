@@ -0,0 +1,243 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/events"
+	"github.com/iheanyi/grove/internal/output"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/stats"
+	"github.com/iheanyi/grove/internal/styles"
+	"github.com/iheanyi/grove/internal/usage"
+	"github.com/spf13/cobra"
+)
+
+// agentUsageInterval is how often the long-lived processes record the
+// agents at work, so agent time is accurate to about this much
+const agentUsageInterval = 5 * time.Minute
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show how you've used grove: commands, servers and agent time",
+	Long: `Show local usage stats: the grove commands run, the servers started and
+how long they ran, the agent time spent in each worktree and the most
+active worktrees, with a sparkline per day.
+
+Nothing is sent anywhere. Commands are recorded as they run, agents every
+5 minutes by the proxy and API ('grove proxy start', 'grove api') while
+they run, to ~/.config/grove/usage.jsonl, or to registry.db with
+registry_backend: sqlite. Servers come from the activity timeline ('grove
+activity'). Set usage_stats: false in ~/.config/grove/config.yaml to stop
+recording.
+
+Examples:
+  grove stats             # The last 7 days
+  grove stats --since 30d
+  grove stats --json`,
+	Args: cobra.NoArgs,
+	RunE: runStats,
+}
+
+func init() {
+	statsCmd.Flags().String("since", "7d", "Period to cover: a duration before now (e.g. 24h, 30d) or a time")
+	statsCmd.Flags().IntP("limit", "n", 10, "Worktrees to show (0 for all)")
+	addOutputFlags(statsCmd)
+}
+
+// recordCommand records a grove command run for 'grove stats'
+func recordCommand(cmd *cobra.Command, args []string) {
+	if cfg == nil || !cfg.UsageStats || strings.HasPrefix(cmd.Name(), "__") {
+		return // completions run on every tab
+	}
+	name := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name())
+	if name = strings.TrimSpace(name); name == "" {
+		name = "tui"
+	}
+	dir, _ := os.Getwd()
+	recordUsage(stats.Record{Kind: stats.KindCommand, Time: time.Now(), Name: name, Dir: dir})
+}
+
+// recordUsage adds a usage record to the registry backend's store
+func recordUsage(r stats.Record) {
+	if registry.Backend() == registry.BackendSQLite {
+		_ = registry.RecordUsage(r)
+		return
+	}
+	_ = stats.AppendFile(config.UsageLogPath(), r)
+}
+
+// watchAgentUsage records the agents working in worktrees for 'grove
+// stats' until ctx is done. It runs in grove's long-lived processes (the
+// foreground proxy and the API).
+func watchAgentUsage(ctx context.Context) {
+	if !cfg.UsageStats {
+		return
+	}
+	ticker := time.NewTicker(agentUsageInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		now := time.Now()
+		for _, s := range activeAgentSessions() {
+			recordUsage(stats.Record{
+				Kind:     stats.KindAgent,
+				Time:     now,
+				Name:     s.Agent.Type,
+				Worktree: s.Worktree,
+				PID:      s.Agent.PID,
+				Started:  s.Agent.StartTime,
+			})
+		}
+	}
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	sinceFlag, _ := cmd.Flags().GetString("since")
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	now := time.Now()
+	since, err := parseSince(sinceFlag, now)
+	if err != nil {
+		return err
+	}
+
+	return runWithOutput(cmd, func() (any, error) {
+		summary, err := summarizeUsage(cmd.Context(), since, now)
+		if err != nil {
+			return nil, err
+		}
+		result := output.UsageStats{Recording: cfg.UsageStats, Summary: *summary}
+
+		if !cfg.UsageStats {
+			fmt.Println("Usage stats are off (usage_stats: false); showing what was recorded before")
+		}
+		printUsageStats(summary, sinceFlag, limit)
+		return result, nil
+	})
+}
+
+// summarizeUsage reads the usage records and server events since a time
+// from the registry backend and sums them up
+func summarizeUsage(ctx context.Context, since, now time.Time) (*stats.Summary, error) {
+	reg, err := registry.LoadContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load registry: %w", err)
+	}
+
+	q := events.Query{Since: since}
+	var records []stats.Record
+	var timeline []events.Event
+	if registry.Backend() == registry.BackendSQLite {
+		if records, err = registry.QueryUsage(ctx, since); err == nil {
+			timeline, err = registry.QueryEvents(ctx, q)
+		}
+	} else {
+		if records, err = stats.ReadFile(config.UsageLogPath(), since); err == nil {
+			timeline, err = events.ReadLog(config.ActivityLogPath(), q)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read usage: %w", err)
+	}
+
+	in := stats.Input{
+		Since:     since,
+		Now:       now,
+		Records:   records,
+		Events:    timeline,
+		Running:   make(map[string]bool),
+		Worktrees: make(map[string]string),
+	}
+	for _, ws := range reg.ListWorkspaces() {
+		in.Worktrees[ws.Path] = ws.Name
+	}
+	for _, server := range reg.ListRunning() {
+		in.Running[server.Name] = true
+	}
+	return stats.Summarize(in), nil
+}
+
+func printUsageStats(s *stats.Summary, period string, limit int) {
+	var commands, starts, agent []float64
+	for _, d := range s.Days {
+		commands = append(commands, float64(d.Commands))
+		starts = append(starts, float64(d.ServerStarts))
+		agent = append(agent, float64(d.AgentSeconds))
+	}
+
+	fmt.Printf("Since %s (%s), recorded on this machine only\n\n", s.Since.Local().Format("2006-01-02 15:04"), period)
+	fmt.Printf("  Commands run       %-8d %s\n", s.Commands, usage.Sparkline(commands))
+	fmt.Printf("  Servers started    %-8d %s\n", s.ServerStarts, usage.Sparkline(starts))
+	fmt.Printf("  Avg server uptime  %s\n", formatHours(s.AvgServerUptimeSeconds))
+	fmt.Printf("  Agent time         %-8s %s\n", formatHours(s.AgentSeconds), usage.Sparkline(agent))
+
+	if len(s.TopCommands) > 0 {
+		var top []string
+		for _, c := range s.TopCommands[:min(len(s.TopCommands), 5)] {
+			top = append(top, fmt.Sprintf("%s (%d)", c.Name, c.Count))
+		}
+		fmt.Printf("  Top commands       %s\n", strings.Join(top, ", "))
+	}
+
+	worktrees := s.Worktrees
+	if limit > 0 && len(worktrees) > limit {
+		worktrees = worktrees[:limit]
+	}
+	if len(worktrees) == 0 {
+		return
+	}
+	rows := make([][]string, 0, len(worktrees))
+	for _, w := range worktrees {
+		rows = append(rows, []string{
+			w.Name,
+			formatHours(w.AgentSeconds),
+			formatHours(w.ServerUptimeSeconds),
+			strconv.Itoa(w.ServerStarts),
+			strconv.Itoa(w.Commands),
+		})
+	}
+	t := table.New().
+		Border(lipgloss.NormalBorder()).
+		BorderRow(false).
+		BorderColumn(false).
+		BorderTop(false).
+		BorderBottom(false).
+		BorderLeft(false).
+		BorderRight(false).
+		Headers("WORKTREE", "AGENT TIME", "SERVER UPTIME", "STARTS", "COMMANDS").
+		Rows(rows...).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			if row == table.HeaderRow {
+				return styles.HeaderStyle
+			}
+			return styles.CellStyle
+		})
+	fmt.Println()
+	fmt.Println(t)
+}
+
+// formatHours formats seconds as hours and minutes, e.g. "12h05m"
+func formatHours(seconds int64) string {
+	d := time.Duration(seconds) * time.Second
+	switch {
+	case d == 0:
+		return "-"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
@@ -1,10 +1,16 @@
 package cli
 
 import (
+	"context"
 	"fmt"
+	"time"
 
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/crash"
+	"github.com/iheanyi/grove/internal/discovery"
 	"github.com/iheanyi/grove/internal/port"
 	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/usage"
 	"github.com/iheanyi/grove/internal/worktree"
 	"github.com/spf13/cobra"
 )
@@ -16,16 +22,20 @@ var statusCmd = &cobra.Command{
 
 Examples:
   grove status              # Show status for current worktree
-  grove status feature-auth # Show status for named server`,
+  grove status feature-auth # Show status for named server
+  grove status --watch      # Refresh every 2s, with agent activity (handy over SSH or in tmux)
+  grove status -w -n 5s     # Refresh every 5s`,
 	RunE: runStatus,
 }
 
+func init() {
+	statusCmd.Flags().BoolP("watch", "w", false, "Continuously refresh the status")
+	statusCmd.Flags().DurationP("interval", "n", 2*time.Second, "Refresh interval for --watch")
+}
+
 func runStatus(cmd *cobra.Command, args []string) error {
-	// Load registry
-	reg, err := registry.Load()
-	if err != nil {
-		return fmt.Errorf("failed to load registry: %w", err)
-	}
+	watch, _ := cmd.Flags().GetBool("watch")
+	interval, _ := cmd.Flags().GetDuration("interval")
 
 	// Determine which server
 	var name string
@@ -40,6 +50,24 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		name = wt.Name
 	}
 
+	// Kept across refreshes so --watch shows recent CPU and a sparkline
+	sampler := usage.NewSampler(30)
+
+	if watch {
+		return runWatch(interval, func() error { return printStatus(name, sampler, true) })
+	}
+	return printStatus(name, sampler, false)
+}
+
+// printStatus prints the detailed status of the named server, and the
+// coding agent working in it if showAgent. Finding agents scans every
+// process, so only the live view does.
+func printStatus(name string, sampler *usage.Sampler, showAgent bool) error {
+	reg, err := registry.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+
 	server, ok := reg.Get(name)
 	if !ok {
 		fmt.Printf("Server '%s' is not registered\n", name)
@@ -54,6 +82,9 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	if cfg.IsSubdomainMode() {
 		fmt.Printf("Subdomains:  %s\n", cfg.SubdomainURL(server.Name))
 	}
+	if ws, ok := reg.GetWorkspace(name); ok && ws.Share != nil {
+		fmt.Printf("Shared:      %s\n", describeShare(ws.Share))
+	}
 	fmt.Printf("Port:        %d\n", server.Port)
 	fmt.Printf("Path:        %s\n", server.Path)
 
@@ -71,16 +102,68 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		} else {
 			fmt.Printf("Port Status: not listening (server may still be starting)\n")
 		}
+
+		if sampler.Sample(map[string]int{name: server.PID}) == nil {
+			if u, ok := sampler.Latest(name); ok {
+				resources := u.String()
+				if history := sampler.History(name); len(history) > 1 {
+					resources += "  " + usage.Sparkline(usage.CPUHistory(history))
+				}
+				fmt.Printf("Resources:   %s\n", resources)
+			}
+		}
+	}
+
+	if server.IsMultiProcess() {
+		fmt.Println("Processes:")
+		for _, proc := range server.Processes {
+			status := proc.Status
+			if !server.IsRunning() {
+				status = registry.StatusStopped
+			}
+			details := formatStatus(status)
+			if status == registry.StatusRunning && proc.PID > 0 {
+				details += fmt.Sprintf(", pid %d", proc.PID)
+			}
+			if proc.Port > 0 {
+				details += fmt.Sprintf(", PORT=%d", proc.Port)
+			}
+			fmt.Printf("  %-12s %s (%s)\n", proc.Name, proc.Command, details)
+		}
+	}
+
+	if cfg.IsSubdomainMode() && len(server.Subdomains) > 0 {
+		fmt.Println("Routes:")
+		for _, sub := range sortedSubdomains(server) {
+			fmt.Printf("  %s.%s.%s -> localhost:%d\n", sub, server.Name, cfg.TLD, server.Subdomains[sub])
+		}
 	}
 
 	if server.Health != "" && server.Health != registry.HealthUnknown {
 		fmt.Printf("Health:      %s\n", server.Health)
 	}
+	if server.FatalLog != "" {
+		fmt.Printf("Fatal Log:   %s\n", server.FatalLog)
+	}
+
+	if showAgent {
+		if agent, ok := discovery.DetectAllAgents(context.Background())[server.Path]; ok {
+			details := fmt.Sprintf("%s (pid %d", agent.Type, agent.PID)
+			if !agent.StartTime.IsZero() {
+				details += ", " + formatDuration(time.Since(agent.StartTime))
+			}
+			fmt.Printf("Agent:       %s)\n", details)
+		}
+	}
 
 	if server.LogFile != "" {
 		fmt.Printf("Log File:    %s\n", server.LogFile)
 	}
 
+	if r, ok := crash.Latest(config.CrashesDir(), server.Name); ok {
+		fmt.Printf("Last Crash:  %s ago, %s (grove crashes %s)\n", formatDuration(time.Since(r.Time)), r.Summary(), server.Name)
+	}
+
 	if !server.StartedAt.IsZero() {
 		fmt.Printf("Started At:  %s\n", server.StartedAt.Format("2006-01-02 15:04:05"))
 	}
@@ -6,6 +6,8 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/iheanyi/grove/internal/events"
+	"github.com/iheanyi/grove/internal/output"
 	"github.com/iheanyi/grove/internal/project"
 	"github.com/iheanyi/grove/internal/registry"
 	"github.com/iheanyi/grove/internal/worktree"
@@ -19,20 +21,32 @@ var stopCmd = &cobra.Command{
 
 If no name is provided, stops the server for the current worktree.
 
+With --worktree, AI agents working in the worktree are stopped too, and
+you're asked whether to close editors that have it open. With --json or
+--format, they're left open.
+
 Examples:
-  grove stop              # Stop server for current worktree
-  grove stop feature-auth # Stop server by name`,
+  grove stop                         # Stop server for current worktree
+  grove stop feature-auth            # Stop server by name
+  grove stop feature-auth --worktree # Also stop its agents and editors`,
 	RunE: runStop,
 }
 
 func init() {
 	stopCmd.Flags().Bool("all", false, "Stop all running servers")
+	stopCmd.Flags().Bool("worktree", false, "Also stop AI agents working in the worktree and offer to close its editors")
 	stopCmd.Flags().DurationP("timeout", "t", 10*time.Second, "Timeout for graceful shutdown")
+	addOutputFlags(stopCmd)
 }
 
 func runStop(cmd *cobra.Command, args []string) error {
 	stopAll, _ := cmd.Flags().GetBool("all")
 	timeout, _ := cmd.Flags().GetDuration("timeout")
+	cascade, _ := cmd.Flags().GetBool("worktree")
+
+	if stopAll && cascade {
+		return fmt.Errorf("--worktree cannot be used with --all")
+	}
 
 	// Load registry
 	reg, err := registry.Load()
@@ -40,24 +54,56 @@ func runStop(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load registry: %w", err)
 	}
 
-	if stopAll {
-		return stopAllServers(reg, timeout)
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
 	}
+	confirmEditor := editorConfirm(false, format.IsMachine())
 
-	// Determine which server to stop
-	var name string
-	if len(args) > 0 {
-		name = args[0]
-	} else {
-		// Use current worktree
-		wt, err := worktree.Detect()
-		if err != nil {
-			return fmt.Errorf("failed to detect worktree: %w", err)
+	return runWithOutput(cmd, func() (any, error) {
+		if stopAll {
+			return stopAllServers(reg, timeout)
 		}
-		name = wt.Name
-	}
 
-	return stopServer(reg, name, timeout)
+		// Determine which server to stop
+		var name, path string
+		if len(args) > 0 {
+			name = args[0]
+		} else {
+			// Use current worktree
+			wt, err := worktree.Detect()
+			if err != nil {
+				return nil, fmt.Errorf("failed to detect worktree: %w", err)
+			}
+			name, path = wt.Name, wt.Path
+		}
+
+		result := output.StopResult{Stopped: []output.Server{}}
+		if cascade {
+			// The worktree may have agents and editors without a server
+			if path == "" {
+				path = worktreePath(reg, name)
+			}
+			if path == "" {
+				return nil, fmt.Errorf("no worktree registered for '%s'", name)
+			}
+			if server, ok := reg.Get(name); !ok || !server.IsRunning() {
+				result.Processes = stopWorktreeProcesses(cmd.Context(), path, confirmEditor)
+				return result, nil
+			}
+		}
+
+		if err := stopServer(reg, name, timeout); err != nil {
+			return nil, err
+		}
+		if server, ok := reg.Get(name); ok {
+			result.Stopped = append(result.Stopped, output.NewServer(server))
+		}
+		if cascade {
+			result.Processes = stopWorktreeProcesses(cmd.Context(), path, confirmEditor)
+		}
+		return result, nil
+	})
 }
 
 func stopServer(reg *registry.Registry, name string, timeout time.Duration) error {
@@ -72,6 +118,11 @@ func stopServer(reg *registry.Registry, name string, timeout time.Duration) erro
 
 	fmt.Printf("Stopping server '%s' (PID: %d)...\n", name, server.PID)
 
+	// A public tunnel goes with its server
+	if err := stopShare(reg, name); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+
 	// Load project config for hooks
 	projConfig, _ := project.Load(server.Path)
 
@@ -85,8 +136,21 @@ func stopServer(reg *registry.Registry, name string, timeout time.Duration) erro
 		}
 	}
 
+	if server.IsCompose() {
+		if err := stopComposeServer(reg, server, projConfig); err != nil {
+			return err
+		}
+		if cfg.UsesProxy() {
+			if err := ReloadProxy(); err != nil {
+				fmt.Printf("Warning: failed to reload proxy: %v\n", err)
+			}
+		}
+		fmt.Println("Server stopped")
+		return nil
+	}
+
 	// Find the process
-	process, err := os.FindProcess(server.PID)
+	process, err := runner.FindProcess(server.PID)
 	if err != nil {
 		// Process doesn't exist, just update registry
 		server.Status = registry.StatusStopped
@@ -95,16 +159,22 @@ func stopServer(reg *registry.Registry, name string, timeout time.Duration) erro
 		if err := reg.Set(server); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to update registry: %v\n", err)
 		}
-		// Reload proxy to remove stale route (only in subdomain mode)
-		if cfg.IsSubdomainMode() {
+		// Reload proxy to remove stale route (only when proxied)
+		if cfg.UsesProxy() {
 			if err := ReloadProxy(); err != nil {
 				fmt.Printf("Warning: failed to reload proxy: %v\n", err)
 			}
 		}
 		fmt.Println("Server process not found, marking as stopped")
+		events.Publish(server.Event(events.ServerStopped))
 		return nil
 	}
 
+	// Paused processes can't handle SIGTERM until they're continued
+	if server.IsPaused() {
+		signalServer(server, syscall.SIGCONT)
+	}
+
 	// Send SIGTERM for graceful shutdown
 	server.Status = registry.StatusStopping
 	if err := reg.Set(server); err != nil {
@@ -119,21 +189,21 @@ func stopServer(reg *registry.Registry, name string, timeout time.Duration) erro
 		if err := reg.Set(server); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to update registry: %v\n", err)
 		}
-		// Reload proxy to remove stale route (only in subdomain mode)
-		if cfg.IsSubdomainMode() {
+		// Reload proxy to remove stale route (only when proxied)
+		if cfg.UsesProxy() {
 			if err := ReloadProxy(); err != nil {
 				fmt.Printf("Warning: failed to reload proxy: %v\n", err)
 			}
 		}
 		fmt.Println("Server stopped")
+		events.Publish(server.Event(events.ServerStopped))
 		return nil
 	}
 
 	// Wait for process to exit
 	done := make(chan error, 1)
 	go func() {
-		_, err := process.Wait()
-		done <- err
+		done <- process.Wait()
 	}()
 
 	select {
@@ -156,22 +226,24 @@ func stopServer(reg *registry.Registry, name string, timeout time.Duration) erro
 		fmt.Fprintf(os.Stderr, "Warning: failed to update registry: %v\n", err)
 	}
 
-	// Reload proxy to remove route (only in subdomain mode)
-	if cfg.IsSubdomainMode() {
+	// Reload proxy to remove route (only when proxied)
+	if cfg.UsesProxy() {
 		if err := ReloadProxy(); err != nil {
 			fmt.Printf("Warning: failed to reload proxy: %v\n", err)
 		}
 	}
 
 	fmt.Println("Server stopped")
+	events.Publish(server.Event(events.ServerStopped))
 	return nil
 }
 
-func stopAllServers(reg *registry.Registry, timeout time.Duration) error {
+func stopAllServers(reg *registry.Registry, timeout time.Duration) (output.StopResult, error) {
+	result := output.StopResult{Stopped: []output.Server{}}
 	running := reg.ListRunning()
 	if len(running) == 0 {
 		fmt.Println("No servers running")
-		return nil
+		return result, nil
 	}
 
 	fmt.Printf("Stopping %d server(s)...\n", len(running))
@@ -180,18 +252,23 @@ func stopAllServers(reg *registry.Registry, timeout time.Duration) error {
 	for _, server := range running {
 		if err := stopServerNoReload(reg, server.Name, timeout); err != nil {
 			fmt.Printf("Error stopping '%s': %v\n", server.Name, err)
+			result.Errors = append(result.Errors, output.ItemError{Name: server.Name, Error: err.Error()})
 			lastErr = err
+			continue
+		}
+		if stopped, ok := reg.Get(server.Name); ok {
+			result.Stopped = append(result.Stopped, output.NewServer(stopped))
 		}
 	}
 
-	// Reload proxy once after all servers are stopped (only in subdomain mode)
-	if cfg.IsSubdomainMode() {
+	// Reload proxy once after all servers are stopped (only when proxied)
+	if cfg.UsesProxy() {
 		if err := ReloadProxy(); err != nil {
 			fmt.Printf("Warning: failed to reload proxy: %v\n", err)
 		}
 	}
 
-	return lastErr
+	return result, lastErr
 }
 
 // stopServerNoReload stops a server without reloading the proxy (used by stopAllServers)
@@ -207,6 +284,11 @@ func stopServerNoReload(reg *registry.Registry, name string, timeout time.Durati
 
 	fmt.Printf("Stopping server '%s' (PID: %d)...\n", name, server.PID)
 
+	// A public tunnel goes with its server
+	if err := stopShare(reg, name); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+
 	// Load project config for hooks
 	projConfig, _ := project.Load(server.Path)
 
@@ -220,8 +302,12 @@ func stopServerNoReload(reg *registry.Registry, name string, timeout time.Durati
 		}
 	}
 
+	if server.IsCompose() {
+		return stopComposeServer(reg, server, projConfig)
+	}
+
 	// Find the process
-	process, err := os.FindProcess(server.PID)
+	process, err := runner.FindProcess(server.PID)
 	if err != nil {
 		// Process doesn't exist, just update registry
 		server.Status = registry.StatusStopped
@@ -231,9 +317,15 @@ func stopServerNoReload(reg *registry.Registry, name string, timeout time.Durati
 			fmt.Fprintf(os.Stderr, "Warning: failed to update registry: %v\n", err)
 		}
 		fmt.Printf("Server '%s' process not found, marking as stopped\n", name)
+		events.Publish(server.Event(events.ServerStopped))
 		return nil
 	}
 
+	// Paused processes can't handle SIGTERM until they're continued
+	if server.IsPaused() {
+		signalServer(server, syscall.SIGCONT)
+	}
+
 	// Send SIGTERM for graceful shutdown
 	server.Status = registry.StatusStopping
 	if err := reg.Set(server); err != nil {
@@ -249,14 +341,14 @@ func stopServerNoReload(reg *registry.Registry, name string, timeout time.Durati
 			fmt.Fprintf(os.Stderr, "Warning: failed to update registry: %v\n", err)
 		}
 		fmt.Printf("Server '%s' stopped\n", name)
+		events.Publish(server.Event(events.ServerStopped))
 		return nil
 	}
 
 	// Wait for process to exit
 	done := make(chan error, 1)
 	go func() {
-		_, err := process.Wait()
-		done <- err
+		done <- process.Wait()
 	}()
 
 	select {
@@ -280,5 +372,6 @@ func stopServerNoReload(reg *registry.Registry, name string, timeout time.Durati
 	}
 
 	fmt.Printf("Server '%s' stopped\n", name)
+	events.Publish(server.Event(events.ServerStopped))
 	return nil
 }
@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"os"
+	"slices"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+func registerRunning(t *testing.T, reg *registry.Registry, name string, pid int) {
+	t.Helper()
+	err := reg.Set(&registry.Server{
+		Name:   name,
+		Path:   t.TempDir(),
+		Port:   3001,
+		PID:    pid,
+		Status: registry.StatusRunning,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertStopped(t *testing.T, reg *registry.Registry, name string) {
+	t.Helper()
+	server, ok := reg.Get(name)
+	if !ok || server.Status != registry.StatusStopped || server.PID != 0 {
+		t.Errorf("registry server = %+v, want stopped", server)
+	}
+}
+
+func TestStopServer_SIGTERM(t *testing.T) {
+	fake := useFakeRunner(t)
+	reg := useTestEnv(t)
+	registerRunning(t, reg, "api", 4242)
+	proc := fake.AddProcess(4242)
+
+	if err := stopServer(reg, "api", time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := proc.Signals(); !slices.Equal(got, []os.Signal{syscall.SIGTERM}) {
+		t.Errorf("signals = %v, want [SIGTERM]", got)
+	}
+	assertStopped(t, reg, "api")
+}
+
+func TestStopServer_KillsAfterTimeout(t *testing.T) {
+	fake := useFakeRunner(t)
+	reg := useTestEnv(t)
+	registerRunning(t, reg, "api", 4242)
+	registerRunning(t, reg, "web", 4343)
+	for _, pid := range []int{4242, 4343} {
+		fake.AddProcess(pid).Ignore(syscall.SIGTERM)
+	}
+
+	if err := stopServer(reg, "api", 10*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stopAllServers(reg, 10*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, pid := range []int{4242, 4343} {
+		proc, _ := fake.Process(pid)
+		if got := proc.Signals(); !slices.Equal(got, []os.Signal{syscall.SIGTERM, syscall.SIGKILL}) {
+			t.Errorf("PID %d signals = %v, want [SIGTERM SIGKILL]", pid, got)
+		}
+	}
+	assertStopped(t, reg, "api")
+	assertStopped(t, reg, "web")
+}
+
+func TestStopServer_ProcessGone(t *testing.T) {
+	useFakeRunner(t)
+	reg := useTestEnv(t)
+	registerRunning(t, reg, "api", 4242)
+
+	if err := stopServer(reg, "api", time.Second); err != nil {
+		t.Fatal(err)
+	}
+	assertStopped(t, reg, "api")
+}
+
+func TestStopServer_Paused(t *testing.T) {
+	fake := useFakeRunner(t)
+	reg := useTestEnv(t)
+	registerRunning(t, reg, "api", 4242)
+	server, _ := reg.Get("api")
+	server.Status = registry.StatusPaused
+	if err := reg.Set(server); err != nil {
+		t.Fatal(err)
+	}
+	proc := fake.AddProcess(4242)
+
+	if err := stopServer(reg, "api", time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	// A paused server is continued so it can handle SIGTERM
+	if got := proc.Signals(); !slices.Equal(got, []os.Signal{syscall.SIGCONT, syscall.SIGTERM}) {
+		t.Errorf("signals = %v, want [SIGCONT SIGTERM]", got)
+	}
+	assertStopped(t, reg, "api")
+}
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -99,41 +100,22 @@ func runSwitch(cmd *cobra.Command, args []string) error {
 
 // findWorktree finds the path to a worktree given its name
 func findWorktree(mainRepoPath, worktreeName string) (string, error) {
-	// List all worktrees
-	cmd := exec.Command("git", "worktree", "list", "--porcelain")
-	cmd.Dir = mainRepoPath
-	output, err := cmd.Output()
+	worktrees, err := gitOps().Worktrees(context.Background(), mainRepoPath)
 	if err != nil {
 		return "", fmt.Errorf("failed to list worktrees: %w", err)
 	}
 
-	// Parse the worktree list
-	lines := strings.Split(string(output), "\n")
-	var currentPath string
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "worktree ") {
-			currentPath = strings.TrimPrefix(line, "worktree ")
-		} else if strings.HasPrefix(line, "branch ") && currentPath != "" {
-			// Check if this worktree path matches the name
-			baseName := filepath.Base(currentPath)
-			if baseName == worktreeName {
-				return currentPath, nil
-			}
+	// Match a worktree with a branch checked out by directory name
+	for _, wt := range worktrees {
+		if wt.Branch != "" && filepath.Base(wt.Path) == worktreeName {
+			return wt.Path, nil
 		}
 	}
 
 	// If not found by exact match, try parent directory + name
-	parentDir := filepath.Dir(mainRepoPath)
-	candidatePath := filepath.Join(parentDir, worktreeName)
-
-	// Verify this is actually a worktree
-	cmd = exec.Command("git", "worktree", "list", "--porcelain")
-	cmd.Dir = mainRepoPath
-	output, err = cmd.Output()
-	if err == nil {
-		if strings.Contains(string(output), candidatePath) {
+	candidatePath := filepath.Join(filepath.Dir(mainRepoPath), worktreeName)
+	for _, wt := range worktrees {
+		if wt.Path == candidatePath {
 			return candidatePath, nil
 		}
 	}
@@ -9,19 +9,23 @@ import (
 )
 
 var tagCmd = &cobra.Command{
-	Use:   "tag <name> [tags...]",
+	Use:   "tag <name> [+tag...]",
 	Short: "Manage tags for a worktree/server",
 	Long: `Add, remove, or list tags for a worktree/server.
 
 Tags help you organize and filter worktrees. You can use tags to group
-related projects, mark priority, or any other categorization.
+related projects, mark priority, or any other categorization. Tags are
+stored on the worktree's registry entry, so worktrees without a server
+can be tagged too.
 
 Examples:
-  grove tag my-feature frontend api      # Add 'frontend' and 'api' tags
+  grove tag my-feature +frontend +urgent # Add 'frontend' and 'urgent' tags
+  grove tag my-feature frontend api      # The + is optional
   grove tag my-feature --remove api      # Remove 'api' tag
   grove tag my-feature --list            # List all tags for my-feature
   grove ls --tag frontend                # List worktrees with 'frontend' tag
-  grove ls --tag frontend --tag api      # List with 'frontend' OR 'api' tag`,
+  grove ls --tag frontend --tag api      # List with 'frontend' OR 'api' tag
+  grove ls --group tag                   # Group worktrees by tag`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runTag,
 }
@@ -44,65 +48,53 @@ func runTag(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load registry: %w", err)
 	}
 
-	// Get the server
-	server, exists := reg.Get(name)
+	// Tags live on the workspace, whether or not it has a server
+	ws, exists := reg.GetWorkspace(name)
 	if !exists {
-		return fmt.Errorf("server '%s' not found in registry", name)
+		return fmt.Errorf("worktree '%s' not found in registry", name)
 	}
 
-	// Handle --list flag
-	if listFlag {
-		if len(server.Tags) == 0 {
+	// Handle --list flag, or no tags and no flags
+	if listFlag || (len(tagsToAdd) == 0 && len(removeFlag) == 0) {
+		if len(ws.Tags) == 0 {
 			fmt.Printf("%s has no tags\n", name)
 		} else {
-			fmt.Printf("Tags for %s: %s\n", name, strings.Join(server.Tags, ", "))
+			fmt.Printf("Tags for %s: %s\n", name, strings.Join(ws.Tags, ", "))
 		}
 		return nil
 	}
 
 	// Handle --remove flag
-	if len(removeFlag) > 0 {
-		for _, tag := range removeFlag {
-			if server.RemoveTag(tag) {
-				fmt.Printf("Removed tag '%s' from %s\n", tag, name)
-			} else {
-				fmt.Printf("Tag '%s' not found on %s\n", tag, name)
-			}
-		}
-		return reg.Set(server)
-	}
-
-	// Add new tags
-	if len(tagsToAdd) == 0 {
-		// No tags specified and no flags, show current tags
-		if len(server.Tags) == 0 {
-			fmt.Printf("%s has no tags\n", name)
+	for _, tag := range removeFlag {
+		tag = normalizeTag(tag)
+		if ws.RemoveTag(tag) {
+			fmt.Printf("Removed tag '%s' from %s\n", tag, name)
 		} else {
-			fmt.Printf("Tags for %s: %s\n", name, strings.Join(server.Tags, ", "))
+			fmt.Printf("Tag '%s' not found on %s\n", tag, name)
 		}
-		return nil
 	}
 
 	for _, tag := range tagsToAdd {
-		// Normalize tag (lowercase, no spaces)
+		// Normalize tag (lowercase, no spaces, no leading +)
 		tag = normalizeTag(tag)
 		if tag == "" {
 			continue
 		}
 
-		if server.AddTag(tag) {
+		if ws.AddTag(tag) {
 			fmt.Printf("Added tag '%s' to %s\n", tag, name)
 		} else {
 			fmt.Printf("Tag '%s' already exists on %s\n", tag, name)
 		}
 	}
 
-	return reg.Set(server)
+	return reg.SetWorkspace(ws)
 }
 
 // normalizeTag normalizes a tag string (lowercase, alphanumeric and hyphens only)
 func normalizeTag(tag string) string {
 	tag = strings.TrimSpace(tag)
+	tag = strings.TrimPrefix(tag, "+")
 	tag = strings.ToLower(tag)
 
 	// Replace spaces and underscores with hyphens
@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	for in, want := range map[string]string{
+		"+frontend":    "frontend",
+		"Urgent":       "urgent",
+		"needs review": "needs-review",
+		"api_v2":       "api-v2",
+		"+":            "",
+	} {
+		if got := normalizeTag(in); got != want {
+			t.Errorf("normalizeTag(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestGroupViewsByTag(t *testing.T) {
+	views := []*WorktreeView{
+		{Name: "a", Tags: []string{"frontend", "urgent"}},
+		{Name: "b", Tags: []string{"frontend"}},
+		{Name: "c"},
+	}
+
+	groups := groupViewsMap(views, "tag")
+	if got := getGroupOrder("tag", groups); !reflect.DeepEqual(got, []string{"frontend", "urgent", "untagged"}) {
+		t.Errorf("group order = %v", got)
+	}
+	if len(groups["frontend"]) != 2 || len(groups["urgent"]) != 1 || len(groups["untagged"]) != 1 {
+		t.Errorf("unexpected groups: frontend=%d urgent=%d untagged=%d",
+			len(groups["frontend"]), len(groups["urgent"]), len(groups["untagged"]))
+	}
+}
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/iheanyi/grove/internal/output"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/styles"
+	"github.com/iheanyi/grove/internal/tasks"
+	"github.com/spf13/cobra"
+)
+
+var tasksCmd = &cobra.Command{
+	Use:   "tasks [name]",
+	Short: "List the tasks being worked on across worktrees",
+	Long: `List the task in progress in each worktree, read from the task tracker
+it uses:
+
+  Tasuku   .tasuku/tasks/*.json (in the worktree or a parent directory)
+  Beads    .beads/issues.jsonl or .beads/issues/*.md
+  TODO.md  "- [/]" or "- [~]" marks an item in progress, "- [ ]" open
+
+The same task is shown by 'grove ls --wide', 'grove review', 'grove agents',
+the TUI and the dashboard.
+
+Examples:
+  grove tasks                   # Tasks in progress across worktrees
+  grove tasks --all             # Include open and blocked tasks
+  grove tasks feature-auth      # Tasks of one worktree
+  grove tasks --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTasks,
+}
+
+func init() {
+	tasksCmd.Flags().Bool("all", false, "List open and blocked tasks too, not only those in progress")
+	addOutputFlags(tasksCmd)
+}
+
+func runTasks(cmd *cobra.Command, args []string) error {
+	all, _ := cmd.Flags().GetBool("all")
+
+	reg, err := registry.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+
+	var workspaces []*registry.Workspace
+	if len(args) > 0 {
+		ws, ok := reg.GetWorkspace(args[0])
+		if !ok {
+			return fmt.Errorf("worktree '%s' not found", args[0])
+		}
+		workspaces = []*registry.Workspace{ws}
+		// A single worktree lists everything that isn't done
+		all = true
+	} else {
+		workspaces = reg.ListWorkspaces()
+		sort.Slice(workspaces, func(i, j int) bool { return workspaces[i].Name < workspaces[j].Name })
+	}
+
+	result := output.TaskList{Worktrees: []output.WorktreeTasks{}}
+	for _, ws := range workspaces {
+		wt := output.WorktreeTasks{Name: ws.Name, Path: ws.Path, Tasks: []tasks.Task{}}
+		for _, t := range tasks.List(ws.Path) {
+			if t.Status == tasks.StatusInProgress || (all && t.Status != tasks.StatusDone) {
+				wt.Tasks = append(wt.Tasks, *t)
+			}
+		}
+		if len(wt.Tasks) > 0 {
+			// In-progress tasks first, keeping each tracker's order
+			sort.SliceStable(wt.Tasks, func(i, j int) bool {
+				return wt.Tasks[i].Status == tasks.StatusInProgress && wt.Tasks[j].Status != tasks.StatusInProgress
+			})
+			result.Worktrees = append(result.Worktrees, wt)
+		}
+	}
+
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	if format.IsMachine() {
+		return output.Write(os.Stdout, format, result)
+	}
+
+	if len(result.Worktrees) == 0 {
+		if all {
+			fmt.Println("No open tasks")
+		} else {
+			fmt.Println("No tasks in progress (use --all to include open tasks)")
+		}
+		return nil
+	}
+
+	for _, wt := range result.Worktrees {
+		for _, t := range wt.Tasks {
+			status := ""
+			if t.Status != tasks.StatusInProgress {
+				status = " (" + string(t.Status) + ")"
+			}
+			fmt.Printf("%-24s %-7s %s%s\n", wt.Name, t.Source, ansi.Truncate(t.String(), styles.TruncateDefault, styles.TruncateTail), status)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/project"
+	"github.com/iheanyi/grove/internal/styles"
+	"github.com/iheanyi/grove/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var templatesCmd = &cobra.Command{
+	Use:     "templates",
+	Aliases: []string{"template"},
+	Short:   "Manage reusable project templates",
+	Long: `Manage templates that 'grove init --template' turns into a .grove.yaml.
+
+Templates define the command, env, health checks, and hooks for a stack.
+grove ships templates for common stacks (rails, nextjs, django, ...); your
+own live in the config directory's templates/ folder as <name>.yaml and
+take precedence over a built-in of the same name. Templates a team commits
+to the project's .grove/templates/ are available in its worktrees too.
+
+Examples:
+  grove templates                          # List templates
+  grove templates show rails               # Print a template's .grove.yaml
+  grove templates add myrails              # Save ./.grove.yaml as a template
+  grove templates add api --from api.yaml  # Save another file as a template
+  grove init --template myrails            # Use it in a new project`,
+	RunE: runTemplatesList,
+}
+
+var templatesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List built-in, user and team templates",
+	Args:  cobra.NoArgs,
+	RunE:  runTemplatesList,
+}
+
+var templatesShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Print the .grove.yaml a template generates",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTemplatesShow,
+}
+
+var templatesAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Save a .grove.yaml as a user template",
+	Long: `Save a .grove.yaml as a user template.
+
+The project-specific name and port are dropped, so the template can be
+reused across projects.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTemplatesAdd,
+}
+
+var templatesRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Delete a user template",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTemplatesRemove,
+}
+
+func init() {
+	templatesAddCmd.Flags().String("from", "", "File to save (default: .grove.yaml in the current directory)")
+	templatesAddCmd.Flags().StringP("description", "d", "", "Short description shown in 'grove templates list'")
+	templatesAddCmd.Flags().BoolP("force", "f", false, "Overwrite an existing user template")
+
+	templatesCmd.AddCommand(templatesListCmd)
+	templatesCmd.AddCommand(templatesShowCmd)
+	templatesCmd.AddCommand(templatesAddCmd)
+	templatesCmd.AddCommand(templatesRemoveCmd)
+}
+
+func runTemplatesList(cmd *cobra.Command, args []string) error {
+	dirs := templateDirs()
+	templates, err := project.ListTemplates(dirs...)
+	if err != nil {
+		return fmt.Errorf("failed to load templates: %w", err)
+	}
+
+	rows := make([][]string, 0, len(templates))
+	for _, t := range templates {
+		source := "user"
+		if t.Builtin {
+			source = "built-in"
+		} else if filepath.Dir(t.Path) != dirs[0] {
+			source = "team"
+		}
+		command := t.Config.Command
+		if command == "" && len(t.Config.Processes) > 0 {
+			command = fmt.Sprintf("(%d processes)", len(t.Config.Processes))
+		}
+		rows = append(rows, []string{t.Name, source, t.Description, command})
+	}
+
+	tbl := table.New().
+		Border(lipgloss.NormalBorder()).
+		BorderRow(false).
+		BorderColumn(false).
+		BorderTop(false).
+		BorderBottom(false).
+		BorderLeft(false).
+		BorderRight(false).
+		Headers("NAME", "SOURCE", "DESCRIPTION", "COMMAND").
+		Rows(rows...).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			if row == table.HeaderRow {
+				return styles.HeaderStyle
+			}
+			if col == 1 && rows[row][1] != "built-in" {
+				return styles.CellStyle.Foreground(styles.Primary)
+			}
+			return styles.CellStyle
+		})
+
+	fmt.Println(tbl)
+	fmt.Printf("\nUser templates: %s\n", dirs[0])
+	if len(dirs) > 1 && fileExists(dirs[1]) {
+		fmt.Printf("Team templates: %s\n", dirs[1])
+	}
+	return nil
+}
+
+// templateDirs returns the directories templates are looked up in: the
+// user's, then the team's in the current project's .grove/templates
+func templateDirs() []string {
+	dirs := []string{config.TemplatesDir()}
+	if wt, err := worktree.Detect(); err == nil {
+		dirs = append(dirs, project.TeamTemplatesDir(wt.Path))
+	}
+	return dirs
+}
+
+func runTemplatesShow(cmd *cobra.Command, args []string) error {
+	t, err := project.FindTemplateIn(templateDirs(), args[0])
+	if err != nil {
+		return err
+	}
+
+	content, err := t.YAML()
+	if err != nil {
+		return err
+	}
+
+	if t.Description != "" {
+		fmt.Printf("# %s\n", t.Description)
+	}
+	if t.Path != "" {
+		fmt.Printf("# %s\n", t.Path)
+	}
+	fmt.Print(content)
+	return nil
+}
+
+func runTemplatesAdd(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	from, _ := cmd.Flags().GetString("from")
+	description, _ := cmd.Flags().GetString("description")
+	force, _ := cmd.Flags().GetBool("force")
+
+	if from == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+		from = filepath.Join(cwd, project.ConfigFileName)
+	}
+
+	dir := config.TemplatesDir()
+	if existing, err := project.FindTemplate(dir, name); err == nil && !existing.Builtin && !force {
+		return fmt.Errorf("template '%s' already exists\nUse --force to overwrite", name)
+	}
+
+	t, err := project.SaveTemplate(dir, name, description, from)
+	if err != nil {
+		return fmt.Errorf("failed to save template: %w", err)
+	}
+
+	fmt.Printf("Saved template '%s' to %s\n", t.Name, t.Path)
+	fmt.Printf("Use it with: grove init --template %s\n", t.Name)
+	return nil
+}
+
+func runTemplatesRemove(cmd *cobra.Command, args []string) error {
+	if err := project.RemoveTemplate(config.TemplatesDir(), args[0]); err != nil {
+		return err
+	}
+	fmt.Printf("Removed template '%s'\n", args[0])
+	return nil
+}
@@ -0,0 +1,203 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var tmuxCmd = &cobra.Command{
+	Use:   "tmux [name]",
+	Short: "Open a tmux session for a worktree",
+	Long: `Create or attach to a tmux session named after the worktree.
+
+New sessions get a window tailing the server log and a shell in the
+worktree; --agent adds a window running the configured AI agent (tmux.agent,
+default "claude"). Inside tmux, the client switches to the session instead
+of nesting.
+
+The layout can be replaced in ~/.config/grove/config.yaml:
+
+  tmux:
+    agent: claude
+    windows:
+      - name: editor
+        command: nvim
+      - name: logs
+        command: tail -F {log}
+      - name: shell
+
+Window commands may use {name}, {path}, {log}, {url} and {port}.
+
+Examples:
+  grove tmux                 # Session for the current worktree
+  grove tmux feature-auth    # Session for a named worktree
+  grove tmux --agent         # Also open an agent window
+  grove tmux -d              # Create the session without attaching`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTmux,
+}
+
+func init() {
+	tmuxCmd.Flags().Bool("agent", false, "Add a window running the configured AI agent")
+	tmuxCmd.Flags().BoolP("detach", "d", false, "Create the session without attaching")
+	tmuxCmd.Flags().Bool("kill", false, "Kill the worktree's session")
+}
+
+func runTmux(cmd *cobra.Command, args []string) error {
+	withAgent, _ := cmd.Flags().GetBool("agent")
+	detach, _ := cmd.Flags().GetBool("detach")
+	kill, _ := cmd.Flags().GetBool("kill")
+
+	if _, err := exec.LookPath("tmux"); err != nil {
+		return fmt.Errorf("tmux not found in PATH")
+	}
+
+	var name, path string
+	if len(args) > 0 {
+		name = args[0]
+		p, err := resolveWorktreePath(name)
+		if err != nil {
+			return err
+		}
+		path = p
+	} else {
+		wt, err := worktree.Detect()
+		if err != nil {
+			return fmt.Errorf("failed to detect worktree: %w", err)
+		}
+		name, path = wt.Name, wt.Path
+	}
+
+	session := tmuxSessionName(name)
+	exists := exec.Command("tmux", "has-session", "-t", "="+session).Run() == nil
+
+	if kill {
+		if !exists {
+			return fmt.Errorf("no tmux session '%s'", session)
+		}
+		if output, err := exec.Command("tmux", "kill-session", "-t", "="+session).CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to kill session: %s", strings.TrimSpace(string(output)))
+		}
+		fmt.Printf("Killed tmux session '%s'\n", session)
+		return nil
+	}
+
+	if !exists {
+		windows := tmuxWindows(cfg.Tmux, withAgent)
+		if err := createTmuxSession(session, path, windows, tmuxVars(name, path)); err != nil {
+			return err
+		}
+		fmt.Printf("Created tmux session '%s'\n", session)
+	}
+
+	if detach {
+		return nil
+	}
+
+	// Switch instead of nesting when already inside tmux
+	var attach *exec.Cmd
+	if os.Getenv("TMUX") != "" {
+		attach = exec.Command("tmux", "switch-client", "-t", "="+session)
+	} else {
+		attach = exec.Command("tmux", "attach-session", "-t", "="+session)
+	}
+	attach.Stdin = os.Stdin
+	attach.Stdout = os.Stdout
+	attach.Stderr = os.Stderr
+	return attach.Run()
+}
+
+// tmuxSessionName returns a session name tmux accepts ('.' and ':' are
+// target separators)
+func tmuxSessionName(name string) string {
+	return strings.NewReplacer(".", "-", ":", "-").Replace(name)
+}
+
+// tmuxWindows returns the configured layout, or the default one
+func tmuxWindows(tc config.TmuxConfig, withAgent bool) []config.TmuxWindow {
+	windows := tc.Windows
+	if len(windows) == 0 {
+		windows = []config.TmuxWindow{
+			{Name: "server", Command: "tail -n 100 -F {log}"},
+			{Name: "shell"},
+		}
+	}
+	if withAgent && tc.Agent != "" {
+		windows = append(windows, config.TmuxWindow{Name: "agent", Command: tc.Agent})
+	}
+	return windows
+}
+
+// tmuxVars returns the placeholder values for window commands
+func tmuxVars(name, path string) map[string]string {
+	vars := map[string]string{
+		"name": name,
+		"path": path,
+		"log":  filepath.Join(cfg.LogDir, name+".log"),
+	}
+	if reg, err := registry.Load(); err == nil {
+		if server, ok := reg.Get(name); ok {
+			if server.LogFile != "" {
+				vars["log"] = server.LogFile
+			}
+			vars["url"] = server.URL
+			vars["port"] = strconv.Itoa(server.Port)
+		}
+	}
+	return vars
+}
+
+// expandTmuxCommand substitutes {placeholders} in a window command
+func expandTmuxCommand(command string, vars map[string]string) string {
+	pairs := make([]string, 0, len(vars)*2)
+	for k, v := range vars {
+		pairs = append(pairs, "{"+k+"}", v)
+	}
+	return strings.NewReplacer(pairs...).Replace(command)
+}
+
+// createTmuxSession creates a detached session with one window per entry,
+// all starting in the worktree, and selects the first shell window
+func createTmuxSession(session, path string, windows []config.TmuxWindow, vars map[string]string) error {
+	focus := 0
+	for i, w := range windows {
+		var args []string
+		if i == 0 {
+			args = []string{"new-session", "-d", "-s", session, "-c", path}
+		} else {
+			args = []string{"new-window", "-t", "=" + session + ":", "-c", path}
+		}
+		if w.Name != "" {
+			args = append(args, "-n", w.Name)
+		}
+		if w.Command != "" {
+			args = append(args, expandTmuxCommand(w.Command, vars))
+		} else if focus == 0 {
+			focus = i
+		}
+
+		if output, err := exec.Command("tmux", args...).CombinedOutput(); err != nil {
+			return fmt.Errorf("failed to create tmux window %q: %s", w.Name, strings.TrimSpace(string(output)))
+		}
+	}
+
+	// Window indexes follow tmux's base-index, so select by position
+	output, err := exec.Command("tmux", "list-windows", "-t", "="+session, "-F", "#{window_index}").Output()
+	if err != nil {
+		return nil
+	}
+	indexes := strings.Fields(string(output))
+	if focus < len(indexes) {
+		_ = exec.Command("tmux", "select-window", "-t", "="+session+":"+indexes[focus]).Run()
+	}
+	return nil
+}
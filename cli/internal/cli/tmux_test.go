@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/iheanyi/grove/internal/config"
+)
+
+func TestTmuxWindows(t *testing.T) {
+	windows := tmuxWindows(config.TmuxConfig{Agent: "claude"}, false)
+	if len(windows) != 2 || windows[0].Name != "server" || windows[1].Name != "shell" {
+		t.Errorf("default windows = %+v", windows)
+	}
+
+	windows = tmuxWindows(config.TmuxConfig{Agent: "claude"}, true)
+	if len(windows) != 3 || windows[2].Command != "claude" {
+		t.Errorf("expected agent window, got %+v", windows)
+	}
+
+	custom := []config.TmuxWindow{{Name: "editor", Command: "nvim"}}
+	windows = tmuxWindows(config.TmuxConfig{Windows: custom}, true)
+	if len(windows) != 1 || windows[0].Name != "editor" {
+		t.Errorf("expected custom layout without agent command, got %+v", windows)
+	}
+}
+
+func TestExpandTmuxCommand(t *testing.T) {
+	vars := map[string]string{"log": "/logs/feature.log", "port": "3001", "url": ""}
+	got := expandTmuxCommand("tail -F {log} # {port} {url}{unknown}", vars)
+	if want := "tail -F /logs/feature.log # 3001 {unknown}"; got != want {
+		t.Errorf("expandTmuxCommand() = %q, want %q", got, want)
+	}
+
+	if got := tmuxSessionName("app.v2:main"); got != "app-v2-main" {
+		t.Errorf("tmuxSessionName() = %q", got)
+	}
+}
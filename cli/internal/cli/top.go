@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+var topCmd = &cobra.Command{
+	Use:   "top",
+	Short: "Live CPU, memory, connections and requests of running servers",
+	Long: `Show a live, top-style view of the running servers: CPU, memory (of each
+server and the processes it spawned), open connections to its port, and
+requests per second through the proxy.
+
+Request rates come from the proxy's access logs (proxy_access_log), averaged
+over the last 5 seconds.
+
+Keys: c cpu, m memory, o connections, r req/s, n name (press again to
+reverse), q quit.
+
+Examples:
+  grove top
+  grove top --sort memory
+  grove top --interval 2s`,
+	Args: cobra.NoArgs,
+	RunE: runTop,
+}
+
+func init() {
+	topCmd.Flags().Duration("interval", 0, "How often to refresh (default 1s)")
+	topCmd.Flags().String("sort", tui.TopSortCPU, "Column to sort by: "+strings.Join(tui.TopSorts, ", "))
+}
+
+func runTop(cmd *cobra.Command, args []string) error {
+	interval, _ := cmd.Flags().GetDuration("interval")
+	sortBy, _ := cmd.Flags().GetString("sort")
+	if !slices.Contains(tui.TopSorts, sortBy) {
+		return fmt.Errorf("unknown --sort %q (use %s)", sortBy, strings.Join(tui.TopSorts, ", "))
+	}
+	if !isInteractive() {
+		return fmt.Errorf("grove top needs a terminal; use 'grove ls --json' for scripts")
+	}
+
+	opts := tui.TopOptions{Interval: interval, Sort: sortBy}
+	if cfg.ProxyAccessLog {
+		opts.AccessLogDir = cfg.AccessLogDir()
+	}
+
+	// Refreshing every second, read the registry through a cache that
+	// reloads it only when it changes
+	load := registry.LoadContext
+	if cache, err := registry.NewCache(cmd.Context()); err == nil {
+		defer cache.Close()
+		load = cache.Get
+	}
+	return tui.RunTop(opts, load)
+}
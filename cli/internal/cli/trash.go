@@ -0,0 +1,172 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/output"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/trash"
+	"github.com/spf13/cobra"
+)
+
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "List and restore worktrees deleted with uncommitted changes",
+	Long: `When 'grove delete --force' (or 'grove clean --force') deletes a worktree
+with uncommitted changes, it first saves them to ~/.config/grove/trash: a
+patch of the changes, the untracked files and a bundle of the branch's
+commits that aren't on the default branch. 'grove trash restore' brings
+the worktree back at its old path, even if its branch was deleted too.
+
+Entries are kept for trash_retention (default 14d) in
+~/.config/grove/config.yaml; "0" turns the trash off.
+
+Examples:
+  grove trash                       # List the trash
+  grove trash restore feature-auth  # Restore the last deletion of a worktree
+  grove trash restore feature-auth-20260101-120000`,
+	Args: cobra.NoArgs,
+	RunE: runTrashList,
+}
+
+var trashListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List worktrees in the trash",
+	Args:  cobra.NoArgs,
+	RunE:  runTrashList,
+}
+
+var trashRestoreCmd = &cobra.Command{
+	Use:   "restore <id|name>",
+	Short: "Restore a worktree from the trash",
+	Long: `Recreate a deleted worktree at its old path, check out its branch and
+reapply its uncommitted changes and untracked files. With a worktree name,
+its most recent deletion is restored.
+
+Examples:
+  grove trash restore feature-auth`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTrashRestore,
+}
+
+func init() {
+	trashCmd.AddCommand(trashListCmd)
+	trashCmd.AddCommand(trashRestoreCmd)
+	addOutputFlags(trashCmd)
+	addOutputFlags(trashListCmd)
+}
+
+// trashRetention is how long the trash keeps entries (trash_retention),
+// or 0 when it's off
+func trashRetention() (time.Duration, error) {
+	if cfg.TrashRetention == "0" {
+		return 0, nil
+	}
+	retention, err := parseAge(cfg.TrashRetention)
+	if err != nil {
+		return 0, fmt.Errorf("invalid trash_retention: %w", err)
+	}
+	return retention, nil
+}
+
+// saveToTrash puts a worktree about to be deleted in the trash, and
+// empties entries past retention. It does nothing when the trash is off.
+func saveToTrash(name, worktreePath, mainRepoPath string) (*trash.Entry, error) {
+	retention, err := trashRetention()
+	if err != nil || retention == 0 {
+		return nil, err
+	}
+
+	fmt.Print("Saving uncommitted changes to the trash... ")
+	base, _ := detectDefaultBranch(mainRepoPath)
+	entry, err := trash.Save(config.TrashDir(), name, worktreePath, mainRepoPath, base)
+	if err != nil {
+		fmt.Println("failed")
+		return nil, err
+	}
+	fmt.Printf("done (%s)\n", entry.ID)
+
+	trash.Purge(config.TrashDir(), retention) //nolint:errcheck // Best effort
+	return entry, nil
+}
+
+func runTrashList(cmd *cobra.Command, args []string) error {
+	return runWithOutput(cmd, func() (any, error) {
+		retention, err := trashRetention()
+		if err != nil {
+			return nil, err
+		}
+		if retention > 0 {
+			if _, err := trash.Purge(config.TrashDir(), retention); err != nil {
+				return nil, fmt.Errorf("failed to empty old trash: %w", err)
+			}
+		}
+		entries, err := trash.List(config.TrashDir())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the trash: %w", err)
+		}
+
+		result := output.TrashList{Retention: cfg.TrashRetention, Entries: []trash.Entry{}}
+		for _, e := range entries {
+			result.Entries = append(result.Entries, *e)
+		}
+
+		if retention == 0 {
+			fmt.Println("The trash is off (trash_retention: \"0\")")
+		}
+		if len(entries) == 0 {
+			fmt.Println("The trash is empty")
+			return result, nil
+		}
+		for _, e := range entries {
+			branch := e.Branch
+			if branch == "" {
+				branch = "detached at " + e.Commit[:min(len(e.Commit), 7)]
+			}
+			fmt.Printf("%-40s %s, deleted %s ago, %d file(s) saved\n", e.ID, branch, formatAge(time.Since(e.DeletedAt)), e.Files)
+		}
+		fmt.Printf("\nEntries are kept for %s. Restore with: grove trash restore <id|name>\n", cfg.TrashRetention)
+		return result, nil
+	})
+}
+
+func runTrashRestore(cmd *cobra.Command, args []string) error {
+	entry, err := trash.Find(config.TrashDir(), args[0])
+	if err != nil {
+		return fmt.Errorf("%w (run 'grove trash' to list it)", err)
+	}
+
+	reg, err := registry.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+	if _, exists := reg.GetWorkspace(entry.Name); exists {
+		return fmt.Errorf("a worktree named '%s' is already registered", entry.Name)
+	}
+
+	fmt.Printf("Restoring %s at %s...\n", entry.Name, entry.Path)
+	restoreErr := entry.Restore()
+	if restoreErr != nil {
+		if _, err := os.Stat(entry.Path); err != nil {
+			return restoreErr
+		}
+		// The worktree is back, only its changes failed to apply
+		fmt.Printf("Warning: %v\n", restoreErr)
+	}
+
+	if err := registerRestored(reg, entry.Name, entry.Path, entry.Branch, entry.MainRepo); err != nil {
+		fmt.Printf("Warning: failed to register worktree: %v\n", err)
+	}
+	if restoreErr == nil {
+		if err := entry.Remove(); err != nil {
+			fmt.Printf("Warning: failed to remove it from the trash: %v\n", err)
+		}
+	}
+
+	fmt.Printf("Restored '%s' (%d file(s) of changes)\n", entry.Name, entry.Files)
+	fmt.Printf("cd %s\n", entry.Path)
+	return nil
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/trash"
+)
+
+func TestForceDeleteGoesToTrash(t *testing.T) {
+	useTestEnv(t)
+	dir := t.TempDir()
+	repo, wt := filepath.Join(dir, "repo"), filepath.Join(dir, "feature")
+	if err := os.MkdirAll(repo, 0755); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, repo, "init", "-b", "main")
+	gitRun(t, repo, "commit", "--allow-empty", "-m", "initial")
+	gitRun(t, repo, "worktree", "add", "-b", "feature", wt)
+	if err := os.WriteFile(filepath.Join(wt, "agent.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	trashed, err := removeWorktree(mustReload(t), "feature", wt, repo, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trashed == nil || trashed.Files != 1 {
+		t.Fatalf("trashed = %+v, want the untracked file saved", trashed)
+	}
+	if _, err := os.Stat(wt); err == nil {
+		t.Fatal("worktree wasn't removed")
+	}
+
+	if err := runTrashRestore(trashRestoreCmd, []string{"feature"}); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(filepath.Join(wt, "agent.go")); err != nil || string(data) != "package main\n" {
+		t.Errorf("agent.go = %q, %v", data, err)
+	}
+	if _, ok := mustReload(t).GetWorktree("feature"); !ok {
+		t.Error("restored worktree isn't registered")
+	}
+	if entries, _ := trash.List(config.TrashDir()); len(entries) != 0 {
+		t.Errorf("trash = %v, want it emptied by the restore", entries)
+	}
+
+	// With the trash off, nothing is saved
+	cfg.TrashRetention = "0"
+	if entry, err := saveToTrash("feature", wt, repo); entry != nil || err != nil {
+		t.Errorf("saveToTrash with the trash off = %v, %v", entry, err)
+	}
+}
@@ -0,0 +1,197 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/iheanyi/grove/internal/execx"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/spf13/cobra"
+)
+
+var tunnelCmd = &cobra.Command{
+	Use:   "tunnel <name>",
+	Short: "Forward a remote server's ports over SSH",
+	Long: `Forward the ports of a server running on a remote machine (a devbox) to
+this one with SSH local forwarding (-L), so its URL works in a local browser.
+
+With --remote, grove asks the remote grove for the server's port and runs
+ssh -N with the forwardings until interrupted. Without it, run on the
+remote machine, grove prints the ssh command to run locally.
+
+--proxy also forwards the proxy's ports for subdomain mode. Privileged
+ports are forwarded to local ports 8000 higher (80 -> 8080, 443 -> 8443),
+which don't need root.
+
+Examples:
+  grove tunnel feature-auth --remote devbox          # Forward until Ctrl+C
+  grove tunnel feature-auth --remote devbox --proxy  # Also forward the proxy
+  grove tunnel feature-auth --remote devbox --print  # Print the ssh command
+  grove tunnel feature-auth --host devbox            # On the devbox: print it`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTunnel,
+}
+
+func init() {
+	tunnelCmd.Flags().String("remote", "", "SSH host running grove to forward from")
+	tunnelCmd.Flags().String("host", "", "SSH host of this machine, for the printed command (default: hostname)")
+	tunnelCmd.Flags().Bool("proxy", false, "Also forward the proxy's HTTP and HTTPS ports")
+	tunnelCmd.Flags().Bool("print", false, "Print the ssh command instead of running it")
+	tunnelCmd.Flags().Int("local-port", 0, "Local port for the server (default: the same as the remote port)")
+}
+
+// sshForward forwards a local port to a port on the remote's localhost
+type sshForward struct {
+	Local  int
+	Remote int
+}
+
+// remoteServer is what a tunnel needs to know about the remote server
+type remoteServer struct {
+	Port       int
+	URL        string
+	ProxyHTTP  int
+	ProxyHTTPS int
+}
+
+func runTunnel(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	remote, _ := cmd.Flags().GetString("remote")
+	host, _ := cmd.Flags().GetString("host")
+	withProxy, _ := cmd.Flags().GetBool("proxy")
+	printOnly, _ := cmd.Flags().GetBool("print")
+	localPort, _ := cmd.Flags().GetInt("local-port")
+
+	var server *remoteServer
+	var err error
+	if remote != "" {
+		host = remote
+		server, err = fetchRemoteServer(cmd.Context(), remote, name)
+	} else {
+		printOnly = true
+		if host == "" {
+			if host, err = os.Hostname(); err != nil {
+				return fmt.Errorf("failed to get hostname (use --host): %w", err)
+			}
+		}
+		server, err = localTunnelServer(name)
+	}
+	if err != nil {
+		return err
+	}
+
+	forwards := tunnelForwards(server, localPort, withProxy)
+	sshArgs := sshTunnelArgs(host, forwards)
+
+	if printOnly {
+		fmt.Println("ssh " + strings.Join(sshArgs, " "))
+		return nil
+	}
+
+	for _, f := range forwards {
+		fmt.Printf("Forwarding localhost:%d -> %s:%d\n", f.Local, host, f.Remote)
+	}
+	if server.URL != "" {
+		fmt.Printf("Server URL: %s\n", server.URL)
+	}
+	fmt.Println("Press Ctrl+C to stop")
+
+	ssh := execx.Command("ssh", sshArgs...)
+	ssh.Stdin = os.Stdin
+	ssh.Stdout = os.Stdout
+	ssh.Stderr = os.Stderr
+	return runner.Run(cmd.Context(), ssh)
+}
+
+// fetchRemoteServer looks up name with 'grove ls --json' on host
+func fetchRemoteServer(ctx context.Context, host, name string) (*remoteServer, error) {
+	out, err := runner.Output(ctx, remoteGrove(host, "ls", "--json", "--servers"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list servers on %s: %w", host, err)
+	}
+
+	var list struct {
+		Worktrees []struct {
+			Name string `json:"name"`
+			Port int    `json:"port"`
+			URL  string `json:"url"`
+		} `json:"worktrees"`
+		Proxy *jsonProxy `json:"proxy"`
+	}
+	if err := json.Unmarshal(out, &list); err != nil {
+		return nil, fmt.Errorf("unexpected output from grove on %s: %w", host, err)
+	}
+
+	for _, wt := range list.Worktrees {
+		if wt.Name != name {
+			continue
+		}
+		if wt.Port == 0 {
+			return nil, fmt.Errorf("'%s' on %s has no port", name, host)
+		}
+		server := &remoteServer{Port: wt.Port, URL: wt.URL}
+		if list.Proxy != nil {
+			server.ProxyHTTP = list.Proxy.HTTPPort
+			server.ProxyHTTPS = list.Proxy.HTTPSPort
+		}
+		return server, nil
+	}
+	return nil, fmt.Errorf("no server registered for '%s' on %s", name, host)
+}
+
+// localTunnelServer looks up name in this machine's registry
+func localTunnelServer(name string) (*remoteServer, error) {
+	reg, err := registry.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load registry: %w", err)
+	}
+	server, ok := reg.Get(name)
+	if !ok || server.Port == 0 {
+		return nil, fmt.Errorf("no server registered for '%s'", name)
+	}
+	result := &remoteServer{Port: server.Port, URL: server.URL}
+	if cfg.UsesProxy() {
+		result.ProxyHTTP = cfg.ProxyHTTPPort
+		result.ProxyHTTPS = cfg.ProxyHTTPSPort
+	}
+	return result, nil
+}
+
+// tunnelForwards returns the forwardings for server: its port (to
+// localPort if set) and, with withProxy, the proxy's ports
+func tunnelForwards(server *remoteServer, localPort int, withProxy bool) []sshForward {
+	if localPort == 0 {
+		localPort = server.Port
+	}
+	forwards := []sshForward{{Local: localPort, Remote: server.Port}}
+	if withProxy {
+		for _, port := range []int{server.ProxyHTTP, server.ProxyHTTPS} {
+			if port > 0 {
+				forwards = append(forwards, sshForward{Local: unprivilegedPort(port), Remote: port})
+			}
+		}
+	}
+	return forwards
+}
+
+// unprivilegedPort maps ports below 1024, which need root to listen on,
+// 8000 higher
+func unprivilegedPort(port int) int {
+	if port < 1024 {
+		return port + 8000
+	}
+	return port
+}
+
+// sshTunnelArgs returns the arguments to ssh that forward ports without
+// running a remote command
+func sshTunnelArgs(host string, forwards []sshForward) []string {
+	args := []string{"-N", "-o", "ExitOnForwardFailure=yes"}
+	for _, f := range forwards {
+		args = append(args, "-L", fmt.Sprintf("%d:localhost:%d", f.Local, f.Remote))
+	}
+	return append(args, host)
+}
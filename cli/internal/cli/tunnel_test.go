@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTunnelForwards(t *testing.T) {
+	server := &remoteServer{Port: 3001, ProxyHTTP: 80, ProxyHTTPS: 443}
+
+	forwards := tunnelForwards(server, 0, true)
+	want := []sshForward{{3001, 3001}, {8080, 80}, {8443, 443}}
+	if !reflect.DeepEqual(forwards, want) {
+		t.Errorf("tunnelForwards() = %v, want %v", forwards, want)
+	}
+
+	args := sshTunnelArgs("devbox", tunnelForwards(server, 4000, false))
+	if got := strings.Join(args, " "); got != "-N -o ExitOnForwardFailure=yes -L 4000:localhost:3001 devbox" {
+		t.Errorf("sshTunnelArgs() = %q", got)
+	}
+}
+
+func TestFetchRemoteServer(t *testing.T) {
+	fake := useFakeRunner(t)
+	fake.On("ssh -o BatchMode=yes devbox grove 'ls' '--json' '--servers'", `{
+  "worktrees": [{"name": "feature-auth", "port": 3042, "url": "https://feature-auth.localhost"}],
+  "proxy": {"status": "running", "http_port": 80, "https_port": 443}
+}`, nil)
+
+	server, err := fetchRemoteServer(context.Background(), "devbox", "feature-auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (remoteServer{Port: 3042, URL: "https://feature-auth.localhost", ProxyHTTP: 80, ProxyHTTPS: 443}); *server != want {
+		t.Errorf("fetchRemoteServer() = %+v, want %+v", *server, want)
+	}
+
+	if _, err := fetchRemoteServer(context.Background(), "devbox", "missing"); err == nil {
+		t.Error("expected an error for an unknown server")
+	}
+}
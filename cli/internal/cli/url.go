@@ -51,7 +51,7 @@ func runURL(cmd *cobra.Command, args []string) error {
 	server, ok := reg.Get(name)
 	if !ok {
 		// Server not registered - in port mode we can't know the URL without a port
-		if !cfg.IsSubdomainMode() {
+		if !cfg.UsesProxy() {
 			return fmt.Errorf("server '%s' is not registered (port unknown)", name)
 		}
 		url := cfg.ServerURL(name, 0)
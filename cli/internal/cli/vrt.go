@@ -0,0 +1,251 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/output"
+	"github.com/iheanyi/grove/internal/project"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/screenshot"
+	"github.com/iheanyi/grove/pkg/browser"
+	"github.com/spf13/cobra"
+)
+
+var vrtCmd = &cobra.Command{
+	Use:   "vrt <a> <b>",
+	Short: "Screenshot two worktrees and report the pixel differences",
+	Long: `Visual regression check between two worktrees: screenshot the same pages
+from both servers with headless Chrome, compare them pixel by pixel, and
+write an HTML report with each page's screenshots and a diff image that
+marks the changed pixels in red. Servers that aren't running are started
+first, as with 'grove compare'.
+
+Pages come from --routes, else the 'screenshots' list in the worktrees'
+.grove.yaml, else /. A page counts as changed when more than --threshold
+percent of its pixels differ. Reports are kept in ~/.config/grove/vrt.
+
+Examples:
+  grove vrt main feature-auth
+  grove vrt main feature-auth --routes /,/pricing
+  grove vrt main feature-auth --threshold 1 --print
+  grove vrt main feature-auth --json`,
+	Args: cobra.ExactArgs(2),
+	RunE: runVRT,
+}
+
+func init() {
+	vrtCmd.Flags().StringSlice("routes", nil, "Pages to compare (default: the .grove.yaml screenshots, or /)")
+	vrtCmd.Flags().Float64("threshold", 0.1, "Percent of pixels that must differ for a page to count as changed")
+	vrtCmd.Flags().Duration("timeout", screenshotTimeout, "Timeout for capturing each worktree's pages")
+	vrtCmd.Flags().Bool("no-start", false, "Fail instead of starting servers that aren't running")
+	vrtCmd.Flags().BoolP("print", "p", false, "Print the report's path instead of opening it")
+	addOutputFlags(vrtCmd)
+}
+
+// vrtPage is the data of the report
+type vrtPage struct {
+	A, B      *registry.Server
+	Routes    []vrtPageRoute
+	Changed   int
+	Threshold float64
+	Generated string
+}
+
+// vrtPageRoute is a route in the report, with its images relative to the
+// report
+type vrtPageRoute struct {
+	output.VRTRoute
+	ImgA, ImgB, ImgDiff string
+}
+
+func runVRT(cmd *cobra.Command, args []string) error {
+	routes, _ := cmd.Flags().GetStringSlice("routes")
+	threshold, _ := cmd.Flags().GetFloat64("threshold")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	noStart, _ := cmd.Flags().GetBool("no-start")
+	printOnly, _ := cmd.Flags().GetBool("print")
+	if args[0] == args[1] {
+		return fmt.Errorf("compare two different worktrees")
+	}
+	format, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	return runWithOutput(cmd, func() (any, error) {
+		servers := make([]*registry.Server, 2)
+		for i, name := range args {
+			server, err := ensureRunning(name, noStart)
+			if err != nil {
+				return nil, err
+			}
+			servers[i] = server
+		}
+		if len(routes) == 0 {
+			routes = vrtRoutes(servers)
+		}
+
+		// Each run replaces the pair's last report
+		dir := filepath.Join(config.VRTDir(), args[0]+"-vs-"+args[1])
+		if err := os.RemoveAll(dir); err != nil {
+			return nil, fmt.Errorf("failed to clear the last report: %w", err)
+		}
+		for i, side := range []string{"a", "b"} {
+			fmt.Printf("Capturing %s (%d pages)...\n", servers[i].Name, len(routes))
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			_, err := screenshot.Capture(ctx, fmt.Sprintf("http://localhost:%d", servers[i].Port), filepath.Join(dir, side), routes)
+			cancel()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %s: %v\n", servers[i].Name, err)
+			}
+		}
+		if err := os.MkdirAll(filepath.Join(dir, "diff"), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create report directory: %w", err)
+		}
+
+		report := output.VRTReport{A: args[0], B: args[1], Report: filepath.Join(dir, "index.html"), Routes: []output.VRTRoute{}}
+		page := vrtPage{
+			A:         servers[0],
+			B:         servers[1],
+			Threshold: threshold,
+			Generated: time.Now().Format("2006-01-02 15:04"),
+		}
+		for _, route := range routes {
+			r := diffRoute(dir, route, threshold)
+			report.Routes = append(report.Routes, r)
+			if r.Changed {
+				page.Changed++
+			}
+			name := screenshot.FileName(route)
+			page.Routes = append(page.Routes, vrtPageRoute{
+				VRTRoute: r,
+				ImgA:     "a/" + name,
+				ImgB:     "b/" + name,
+				ImgDiff:  "diff/" + name,
+			})
+		}
+		if err := writeVRTReport(report.Report, page); err != nil {
+			return nil, err
+		}
+
+		fmt.Println()
+		printVRTRoutes(report.Routes)
+		fmt.Printf("\n%d of %d pages changed\n", page.Changed, len(routes))
+
+		if printOnly || format.IsMachine() {
+			fmt.Println(report.Report)
+			return report, nil
+		}
+		fmt.Printf("Opening %s...\n", report.Report)
+		return report, browser.Open("file://" + report.Report)
+	})
+}
+
+// vrtRoutes returns the screenshots configured in the first worktree's
+// .grove.yaml that has them, or the default routes
+func vrtRoutes(servers []*registry.Server) []string {
+	for _, server := range servers {
+		if projConfig, err := project.Load(server.Path); err == nil && len(projConfig.Screenshots) > 0 {
+			return projConfig.Screenshots
+		}
+	}
+	return screenshot.DefaultRoutes
+}
+
+// diffRoute compares a route's screenshots in dir/a and dir/b, writing
+// the diff image to dir/diff
+func diffRoute(dir, route string, threshold float64) output.VRTRoute {
+	if !strings.HasPrefix(route, "/") {
+		route = "/" + route
+	}
+	name := screenshot.FileName(route)
+	r := output.VRTRoute{
+		Route: route,
+		A:     filepath.Join(dir, "a", name),
+		B:     filepath.Join(dir, "b", name),
+		Diff:  filepath.Join(dir, "diff", name),
+	}
+	result, err := screenshot.DiffFiles(r.A, r.B, r.Diff)
+	if err != nil {
+		r.A, r.B, r.Diff = "", "", ""
+		r.Error = err.Error()
+		return r
+	}
+	r.ChangedPercent = result.Percent()
+	r.Changed = r.ChangedPercent > threshold
+	return r
+}
+
+func printVRTRoutes(routes []output.VRTRoute) {
+	width := len("ROUTE")
+	for _, r := range routes {
+		width = max(width, len(r.Route))
+	}
+	fmt.Printf("%-*s  %8s\n", width, "ROUTE", "CHANGED")
+	for _, r := range routes {
+		switch {
+		case r.Error != "":
+			fmt.Printf("%-*s  %8s  %s\n", width, r.Route, "-", r.Error)
+		case r.Changed:
+			fmt.Printf("%-*s  %7.2f%%  changed\n", width, r.Route, r.ChangedPercent)
+		default:
+			fmt.Printf("%-*s  %7.2f%%\n", width, r.Route, r.ChangedPercent)
+		}
+	}
+}
+
+func writeVRTReport(file string, page vrtPage) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	if err := vrtTemplate.Execute(f, page); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+var vrtTemplate = template.Must(template.New("vrt").Parse(`<!doctype html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.A.Name}} vs {{.B.Name}} · grove vrt</title>
+<style>
+body { font-family: -apple-system, system-ui, sans-serif; background: #0f172a; color: #e2e8f0; margin: 0; padding: 1rem 1.5rem; }
+h1 { font-size: 1.25rem; margin: 0 0 0.25rem; }
+h2 { font-size: 1rem; margin: 0; font-family: ui-monospace, monospace; }
+.muted { color: #94a3b8; }
+.changed { color: #f87171; }
+.same { color: #4ade80; }
+section { margin-top: 1.5rem; border-top: 1px solid #1e293b; padding-top: 1rem; }
+.row { display: grid; grid-template-columns: repeat(3, 1fr); gap: 1rem; margin-top: 0.5rem; }
+figure { margin: 0; min-width: 0; }
+figcaption { font-size: 0.85rem; margin-bottom: 0.25rem; }
+img { width: 100%; border: 1px solid #334155; background: #fff; }
+</style>
+</head>
+<body>
+<h1>{{.A.Name}} vs {{.B.Name}}</h1>
+<div class="muted">{{.Changed}} of {{len .Routes}} pages changed (more than {{.Threshold}}% of pixels) · {{.Generated}}</div>
+{{range .Routes}}<section>
+<h2>{{.Route}} {{if .Error}}<span class="changed">not compared</span>{{else if .Changed}}<span class="changed">{{printf "%.2f" .ChangedPercent}}% changed</span>{{else}}<span class="same">{{printf "%.2f" .ChangedPercent}}% changed</span>{{end}}</h2>
+{{if .Error}}<p class="muted">{{.Error}}</p>{{else}}<div class="row">
+<figure><figcaption>{{$.A.Name}}{{if $.A.Branch}} <span class="muted">on {{$.A.Branch}}</span>{{end}}</figcaption><a href="{{.ImgA}}"><img src="{{.ImgA}}" alt="{{$.A.Name}} {{.Route}}"></a></figure>
+<figure><figcaption>{{$.B.Name}}{{if $.B.Branch}} <span class="muted">on {{$.B.Branch}}</span>{{end}}</figcaption><a href="{{.ImgB}}"><img src="{{.ImgB}}" alt="{{$.B.Name}} {{.Route}}"></a></figure>
+<figure><figcaption>Diff <span class="muted">changed pixels in red</span></figcaption><a href="{{.ImgDiff}}"><img src="{{.ImgDiff}}" alt="diff {{.Route}}"></a></figure>
+</div>{{end}}
+</section>
+{{end}}</body>
+</html>
+`))
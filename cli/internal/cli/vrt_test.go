@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+func writeTestPNG(t *testing.T, file string, changed int) {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+	for i := 0; i < 100; i++ {
+		c := color.NRGBA{255, 255, 255, 255}
+		if i < changed {
+			c = color.NRGBA{0, 0, 0, 255}
+		}
+		img.SetNRGBA(i%10, i/10, c)
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, img); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDiffRoute(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "diff"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestPNG(t, filepath.Join(dir, "a", "index.png"), 0)
+	writeTestPNG(t, filepath.Join(dir, "b", "index.png"), 5)
+	writeTestPNG(t, filepath.Join(dir, "a", "pricing.png"), 0)
+
+	r := diffRoute(dir, "/", 1)
+	if r.Error != "" || r.ChangedPercent != 5 || !r.Changed {
+		t.Errorf("diffRoute(/) = %+v, want 5%% changed", r)
+	}
+	if _, err := os.Stat(r.Diff); err != nil {
+		t.Errorf("diff image not written: %v", err)
+	}
+	if r := diffRoute(dir, "/", 10); r.Changed {
+		t.Error("5% changed should be under a 10% threshold")
+	}
+
+	// b's screenshot is missing
+	r = diffRoute(dir, "pricing", 1)
+	if r.Route != "/pricing" || r.Error == "" || r.Diff != "" {
+		t.Errorf("diffRoute(pricing) = %+v, want an error", r)
+	}
+}
+
+func TestVRTTemplate(t *testing.T) {
+	dir := t.TempDir()
+	writeTestPNG(t, filepath.Join(dir, "a", "index.png"), 0)
+	writeTestPNG(t, filepath.Join(dir, "b", "index.png"), 5)
+	if err := os.MkdirAll(filepath.Join(dir, "diff"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	route := diffRoute(dir, "/", 0.1)
+	page := vrtPage{
+		A:      &registry.Server{Name: "main", Branch: "main"},
+		B:      &registry.Server{Name: "feature", Branch: "feature/<auth>"},
+		Routes: []vrtPageRoute{{VRTRoute: route, ImgA: "a/index.png", ImgB: "b/index.png", ImgDiff: "diff/index.png"}},
+	}
+	file := filepath.Join(dir, "index.html")
+	if err := writeVRTReport(file, page); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(data)
+	for _, want := range []string{`src="diff/index.png"`, "5.00% changed", "feature/&lt;auth&gt;"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("report missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// runWatch redraws the output of render every interval until interrupted.
+// It uses plain ANSI escapes rather than a full-screen TUI so it works well
+// over SSH and in small tmux panes.
+func runWatch(interval time.Duration, render func() error) error {
+	if interval < 500*time.Millisecond {
+		return fmt.Errorf("--interval must be at least 500ms")
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	// Hide the cursor while redrawing, and restore it on exit
+	fmt.Print("\033[?25l")
+	defer fmt.Print("\033[?25h")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// Move to the top-left and clear the screen
+		fmt.Print("\033[H\033[2J")
+
+		if err := render(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+
+		fmt.Printf("\nEvery %s · updated %s (press Ctrl+C to exit)\n", interval, time.Now().Format("15:04:05"))
+
+		select {
+		case <-ticker.C:
+		case <-sigChan:
+			fmt.Println()
+			return nil
+		}
+	}
+}
@@ -0,0 +1,163 @@
+// Package compose runs worktree servers as Docker Compose projects via the
+// docker compose CLI
+package compose
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// Project is a compose project for one worktree
+type Project struct {
+	// Name is the compose project name (docker compose -p)
+	Name string
+
+	// Dir is the worktree the compose files are in
+	Dir string
+
+	// Files are explicit compose files (docker compose -f)
+	Files []string
+
+	// Env is added to the environment for variable interpolation
+	Env []string
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// ProjectName returns the compose project name for a worktree. Compose
+// requires lowercase letters, digits, dashes and underscores.
+func ProjectName(worktree string) string {
+	name := strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(worktree), "-"), "-_")
+	if name == "" {
+		return "grove"
+	}
+	return name
+}
+
+// Args returns the docker arguments for a compose subcommand
+func (p *Project) Args(args ...string) []string {
+	full := []string{"compose", "-p", p.Name}
+	for _, f := range p.Files {
+		full = append(full, "-f", f)
+	}
+	return append(full, args...)
+}
+
+// Command builds a docker compose command for the project
+func (p *Project) Command(args ...string) *exec.Cmd {
+	cmd := exec.Command("docker", p.Args(args...)...)
+	cmd.Dir = p.Dir
+	cmd.Env = append(os.Environ(), p.Env...)
+	return cmd
+}
+
+// run runs a compose subcommand, returning its output in the error
+func (p *Project) run(args ...string) error {
+	output, err := p.Command(args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("docker compose %s: %w: %s", args[0], err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// Stop stops the project's containers
+func (p *Project) Stop() error {
+	return p.run("stop")
+}
+
+// Down removes the project's containers and networks
+func (p *Project) Down() error {
+	return p.run("down", "--remove-orphans")
+}
+
+// Pause freezes the project's containers
+func (p *Project) Pause() error {
+	return p.run("pause")
+}
+
+// Unpause resumes paused containers
+func (p *Project) Unpause() error {
+	return p.run("unpause")
+}
+
+// Publisher is a port published by a container
+type Publisher struct {
+	URL           string `json:"URL"`
+	TargetPort    int    `json:"TargetPort"`
+	PublishedPort int    `json:"PublishedPort"`
+	Protocol      string `json:"Protocol"`
+}
+
+// Container is a container from 'docker compose ps'
+type Container struct {
+	Name       string      `json:"Name"`
+	Service    string      `json:"Service"`
+	State      string      `json:"State"`
+	Publishers []Publisher `json:"Publishers"`
+}
+
+// Ps lists the project's containers
+func (p *Project) Ps() ([]Container, error) {
+	output, err := p.Command("ps", "--format", "json").Output()
+	if err != nil {
+		return nil, fmt.Errorf("docker compose ps: %w", err)
+	}
+	return ParsePs(output)
+}
+
+// ParsePs parses 'docker compose ps --format json', which is a JSON array
+// in older Compose versions and one object per line in newer ones
+func ParsePs(output []byte) ([]Container, error) {
+	output = bytes.TrimSpace(output)
+	if len(output) == 0 {
+		return nil, nil
+	}
+
+	var containers []Container
+	if output[0] == '[' {
+		if err := json.Unmarshal(output, &containers); err != nil {
+			return nil, err
+		}
+		return containers, nil
+	}
+
+	for _, line := range bytes.Split(output, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		var c Container
+		if err := json.Unmarshal(line, &c); err != nil {
+			return nil, err
+		}
+		containers = append(containers, c)
+	}
+	return containers, nil
+}
+
+// PublishedPort returns the host port published by service, or by the
+// first container (by service name) that publishes a TCP port when service
+// is empty. It returns 0 if nothing is published.
+func PublishedPort(containers []Container, service string) int {
+	best, bestService := 0, ""
+	for _, c := range containers {
+		if service != "" && c.Service != service {
+			continue
+		}
+		for _, pub := range c.Publishers {
+			if pub.PublishedPort == 0 || (pub.Protocol != "" && pub.Protocol != "tcp") {
+				continue
+			}
+			if best == 0 || c.Service < bestService {
+				best, bestService = pub.PublishedPort, c.Service
+			}
+			break
+		}
+	}
+	return best
+}
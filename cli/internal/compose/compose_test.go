@@ -0,0 +1,74 @@
+package compose
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProjectName(t *testing.T) {
+	tests := map[string]string{
+		"feature-auth":   "feature-auth",
+		"Feature/Auth":   "feature-auth",
+		"myapp_fix.1":    "myapp_fix-1",
+		"--weird--name-": "weird--name",
+		"!!!":            "grove",
+	}
+	for in, want := range tests {
+		if got := ProjectName(in); got != want {
+			t.Errorf("ProjectName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestArgs(t *testing.T) {
+	p := &Project{Name: "feature", Files: []string{"compose.yml", "compose.dev.yml"}}
+	got := p.Args("up", "-d")
+	want := []string{"compose", "-p", "feature", "-f", "compose.yml", "-f", "compose.dev.yml", "up", "-d"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Args() = %v, want %v", got, want)
+	}
+}
+
+func TestParsePs(t *testing.T) {
+	lines := `{"Name":"feature-web-1","Service":"web","State":"running","Publishers":[{"URL":"0.0.0.0","TargetPort":3000,"PublishedPort":3105,"Protocol":"tcp"}]}
+{"Name":"feature-db-1","Service":"db","State":"running","Publishers":[{"URL":"","TargetPort":5432,"PublishedPort":0,"Protocol":"tcp"}]}
+`
+	array := `[{"Name":"feature-web-1","Service":"web","State":"running","Publishers":[{"URL":"0.0.0.0","TargetPort":3000,"PublishedPort":3105,"Protocol":"tcp"}]},
+{"Name":"feature-db-1","Service":"db","State":"running","Publishers":null}]`
+
+	for name, output := range map[string]string{"ndjson": lines, "array": array} {
+		t.Run(name, func(t *testing.T) {
+			containers, err := ParsePs([]byte(output))
+			if err != nil {
+				t.Fatalf("ParsePs() error = %v", err)
+			}
+			if len(containers) != 2 || containers[0].Service != "web" || containers[1].Service != "db" {
+				t.Fatalf("ParsePs() = %+v", containers)
+			}
+			if got := PublishedPort(containers, ""); got != 3105 {
+				t.Errorf("PublishedPort() = %d, want 3105", got)
+			}
+			if got := PublishedPort(containers, "db"); got != 0 {
+				t.Errorf("PublishedPort(db) = %d, want 0", got)
+			}
+		})
+	}
+
+	if containers, err := ParsePs([]byte("  \n")); err != nil || containers != nil {
+		t.Errorf("ParsePs(empty) = %v, %v", containers, err)
+	}
+}
+
+func TestPublishedPort_PrefersServiceOrder(t *testing.T) {
+	containers := []Container{
+		{Service: "worker", Publishers: []Publisher{{PublishedPort: 9000, Protocol: "tcp"}}},
+		{Service: "api", Publishers: []Publisher{{PublishedPort: 53, Protocol: "udp"}, {PublishedPort: 8080, Protocol: "tcp"}}},
+	}
+
+	if got := PublishedPort(containers, ""); got != 8080 {
+		t.Errorf("PublishedPort() = %d, want 8080 (api sorts first, udp skipped)", got)
+	}
+	if got := PublishedPort(containers, "worker"); got != 9000 {
+		t.Errorf("PublishedPort(worker) = %d, want 9000", got)
+	}
+}
@@ -1,12 +1,14 @@
 package config
 
 import (
+	"crypto/sha256"
+	"fmt"
+	"net"
 	"os"
 	"path/filepath"
 	"strconv"
 	"time"
 
-	"github.com/adrg/xdg"
 	"gopkg.in/yaml.v3"
 )
 
@@ -18,6 +20,10 @@ const (
 	URLModePort URLMode = "port"
 	// URLModeSubdomain uses subdomain-based routing (https://name.localhost)
 	URLModeSubdomain URLMode = "subdomain"
+	// URLModePath uses path-based routing on one host
+	// (https://grove.localhost/name/), for networks that don't allow
+	// arbitrary subdomains
+	URLModePath URLMode = "path"
 )
 
 // Config holds the global configuration for grove
@@ -26,38 +32,293 @@ type Config struct {
 	PortMin int `yaml:"port_min"`
 	PortMax int `yaml:"port_max"`
 
+	// PortBases gives repositories, by name (e.g. "myapp"), a block of
+	// port_block_size ports of their own starting at a base, e.g.
+	// myapp: 3100. A repository's worktrees get the block's ports in
+	// order, so its ports are easy to remember. Other repositories get
+	// ports between port_min and port_max.
+	PortBases map[string]int `yaml:"port_bases,omitempty"`
+	// PortBlockSize is how many ports each port_bases block has (default 100)
+	PortBlockSize int `yaml:"port_block_size,omitempty"`
+
 	// Worktree management
 	// WorktreesDir is the centralized directory for worktrees.
 	// When set, new worktrees are created in: <worktrees_dir>/<project>/<branch>
 	// When empty (default), worktrees are created as siblings to the main repo.
 	WorktreesDir string `yaml:"worktrees_dir"`
 
-	// URL mode: "port" (default) or "subdomain"
+	// WorktreeName is the template linked worktrees are named with, using
+	// {repo}, {branch} and {dir} (default "{branch}"). Main worktrees are
+	// named after their directory. A name registered to another worktree
+	// gets a suffix derived from the worktree's path.
+	WorktreeName string `yaml:"worktree_name"`
+
+	// ScanIgnore are gitignore-style patterns of directories 'grove discover'
+	// skips, on top of hidden directories, node_modules, vendor, __pycache__
+	// and venv ("!vendor" scans vendor again)
+	ScanIgnore []string `yaml:"scan_ignore,omitempty"`
+
+	// URL mode: "port" (default), "subdomain" or "path"
 	// - port: http://localhost:PORT (simpler, no proxy needed)
 	// - subdomain: https://name.localhost (requires proxy, may conflict with app subdomains)
+	// - path: https://grove.localhost/name/ (requires proxy, apps must tolerate a path prefix)
 	URLMode URLMode `yaml:"url_mode"`
 
-	// Domain settings (only used in subdomain mode)
+	// Domain settings (only used in subdomain and path mode)
 	TLD string `yaml:"tld"`
 
-	// Proxy ports (only used in subdomain mode)
+	// Proxy ports (only used in subdomain and path mode)
 	ProxyHTTPPort  int `yaml:"proxy_http_port"`
 	ProxyHTTPSPort int `yaml:"proxy_https_port"`
 
+	// ProxyListenAddress is the address the proxy binds to. Empty keeps
+	// Caddy's default of every interface, and 127.0.0.1 keeps the proxy on
+	// this machine. Any other address, e.g. 0.0.0.0, also serves each
+	// server to other devices on the network, on its port plus
+	// LANPortOffset (see 'grove lan').
+	ProxyListenAddress string `yaml:"proxy_listen_address,omitempty"`
+
+	// LANPortOffset is added to a server's port for its LAN address
+	LANPortOffset int `yaml:"lan_port_offset,omitempty"`
+
+	// MDNS advertises servers served to the network over mDNS
+	MDNS MDNSConfig `yaml:"mdns"`
+
+	// ProxyAccessLog writes a JSON access log per worktree, which backs
+	// 'grove proxy stats' and the dashboard's traffic stats
+	ProxyAccessLog bool `yaml:"proxy_access_log"`
+
+	// ProxyCookies rewrites the cookies servers set through the proxy, so
+	// sessions of different worktrees don't clobber each other
+	ProxyCookies ProxyCookiesConfig `yaml:"proxy_cookies"`
+
+	// ProxySecurity limits the hosts the proxy answers for and hardens its
+	// responses
+	ProxySecurity ProxySecurityConfig `yaml:"proxy_security"`
+
+	// DNSPort is the local port for grove's DNS responder, which resolves
+	// the TLD to 127.0.0.1 (see 'grove dns setup')
+	DNSPort int `yaml:"dns_port"`
+
+	// RegistryBackend stores the registry in "json" (the default,
+	// registry.json) or "sqlite" (registry.db), which saves workspaces
+	// individually so concurrent writers don't overwrite each other, and
+	// keeps the activity timeline queryable
+	RegistryBackend string `yaml:"registry_backend,omitempty"`
+
 	// Log settings
 	LogDir       string `yaml:"log_dir"`
 	LogMaxSize   string `yaml:"log_max_size"`
 	LogRetention string `yaml:"log_retention"`
 
+	// TrashRetention is how long the uncommitted changes and branches of
+	// worktrees deleted with --force are kept for 'grove trash restore',
+	// e.g. 14d. "0" turns the trash off.
+	TrashRetention string `yaml:"trash_retention"`
+
+	// UsageStats records the commands run and agent time per worktree for
+	// 'grove stats'. The records stay on this machine.
+	UsageStats bool `yaml:"usage_stats"`
+
 	// Server behavior
 	IdleTimeout        time.Duration `yaml:"idle_timeout"`
 	HealthCheckTimeout time.Duration `yaml:"health_check_timeout"`
 
+	// PauseAfter pauses (SIGSTOP) servers whose proxied hosts have had no
+	// traffic for this long; the next request resumes them. Requires
+	// subdomain mode and proxy_access_log. 0 disables.
+	PauseAfter time.Duration `yaml:"pause_after"`
+
+	// GitHub/GitLab PR status (ls --prs, review, dashboard)
+	// GitHubToken is passed to gh as GH_TOKEN; when empty, gh's own auth is used.
+	GitHubToken string        `yaml:"github_token,omitempty"`
+	PRCacheTTL  time.Duration `yaml:"pr_cache_ttl"`
+
 	// TUI settings
 	TUI TUIConfig `yaml:"tui"`
 
+	// Theme colors the CLI, TUI and dashboard: dark (the default), light,
+	// or custom, which starts from dark. Palette overrides colors by name,
+	// e.g. primary: "#0EA5E9". GROVE_THEME overrides Theme.
+	Theme   string            `yaml:"theme,omitempty"`
+	Palette map[string]string `yaml:"palette,omitempty"`
+
 	// Notifications
 	Notifications NotificationConfig `yaml:"notifications"`
+
+	// Tmux configures the sessions opened by 'grove tmux'
+	Tmux TmuxConfig `yaml:"tmux"`
+
+	// AgentLimits caps each worktree's AI agent session
+	// (enforced by 'grove agents --notify')
+	AgentLimits AgentLimitsConfig `yaml:"agent_limits,omitempty"`
+
+	// Editor is the editor 'grove code' opens worktrees in: vscode, cursor,
+	// zed, a JetBrains IDE (idea, goland, ...) or any launcher that takes a
+	// path. When empty, the first known editor on PATH is used.
+	Editor string `yaml:"editor,omitempty"`
+
+	// Groups are named sets of worktrees that run together, e.g. a frontend
+	// and its backend in separate repos (see 'grove group')
+	Groups map[string][]string `yaml:"groups,omitempty"`
+
+	// Share configures the public tunnels opened by 'grove share'
+	Share ShareConfig `yaml:"share"`
+
+	// ClaudeHooks configures the PreToolUse hooks written by
+	// 'grove hooks install'
+	ClaudeHooks ClaudeHooksConfig `yaml:"claude_hooks"`
+
+	// RestoreOnLogin has the proxy and API services keep a snapshot of the
+	// running servers and start them again at login, like 'grove thaw'
+	RestoreOnLogin bool `yaml:"restore_on_login,omitempty"`
+}
+
+// ClaudeHooksConfig configures the Claude Code hooks that steer agents to
+// 'grove start' and 'grove new'
+type ClaudeHooksConfig struct {
+	// Mode is "warn" (the default), which prints a suggestion, or "block",
+	// which denies matching commands
+	Mode string `yaml:"mode"`
+
+	// DevServerPatterns are extended regexps of commands that should run
+	// through 'grove start', in addition to the built-in ones
+	DevServerPatterns []string `yaml:"dev_server_patterns,omitempty"`
+
+	// WorktreePatterns are extended regexps of commands that should use
+	// 'grove new', in addition to 'git worktree add'
+	WorktreePatterns []string `yaml:"worktree_patterns,omitempty"`
+}
+
+// ProxyCookiesConfig configures how the proxy rewrites Set-Cookie headers
+type ProxyCookiesConfig struct {
+	// Isolate scopes cookies to the worktree's domain: a Domain attribute
+	// such as .localhost, which every worktree would share, becomes the
+	// worktree's host (e.g. feature.localhost), still covering its subdomains
+	Isolate bool `yaml:"isolate"`
+
+	// Secure marks every cookie Secure, which browsers require with
+	// SameSite=None; the proxy always serves HTTPS
+	Secure bool `yaml:"secure"`
+}
+
+// MDNSConfig configures mDNS (Bonjour) advertisement. While the proxy runs
+// and serves the network (proxy_listen_address), each running server is
+// advertised as an _http._tcp service named after its worktree, at
+// <name>.<hostname>.local, so other devices can find it and 'grove ls
+// --network' lists it.
+type MDNSConfig struct {
+	// Enabled turns advertisement on
+	Enabled bool `yaml:"enabled"`
+
+	// Hostname names this machine in advertised names; defaults to the
+	// system hostname
+	Hostname string `yaml:"hostname,omitempty"`
+}
+
+// Security header modes (proxy_security.headers)
+const (
+	// SecurityHeadersRemote adds them to responses to other machines
+	SecurityHeadersRemote = "remote"
+	// SecurityHeadersAlways adds them to every response
+	SecurityHeadersAlways = "always"
+	// SecurityHeadersOff never adds them
+	SecurityHeadersOff = "off"
+)
+
+// ProxySecurityConfig configures which hosts the proxy serves and the
+// limits and headers it applies. Only registered worktrees' domains are
+// served; requests for other hosts get UnknownHostStatus.
+type ProxySecurityConfig struct {
+	// AllowedHosts are extra hostnames the proxy serves, each routed to a
+	// registered worktree, e.g. app.test: feature-auth
+	AllowedHosts map[string]string `yaml:"allowed_hosts,omitempty"`
+
+	// UnknownHostStatus is the response to hosts that aren't served: 421
+	// Misdirected Request (the default) or 403 Forbidden
+	UnknownHostStatus int `yaml:"unknown_host_status,omitempty"`
+
+	// Headers adds X-Frame-Options, X-Content-Type-Options and
+	// Referrer-Policy: "remote" to responses to other machines, "always",
+	// or "off" (the default)
+	Headers string `yaml:"headers,omitempty"`
+
+	// MaxRequestBody rejects larger request bodies with 413, e.g. "50MB".
+	// Empty means no limit.
+	MaxRequestBody string `yaml:"max_request_body,omitempty"`
+}
+
+// UnknownHostResponse returns the status unknown hosts get
+func (c ProxySecurityConfig) UnknownHostResponse() int {
+	if c.UnknownHostStatus == 403 {
+		return 403
+	}
+	return 421
+}
+
+// HeadersMode returns the security header mode, defaulting to off
+func (c ProxySecurityConfig) HeadersMode() string {
+	switch c.Headers {
+	case SecurityHeadersRemote, SecurityHeadersAlways:
+		return c.Headers
+	default:
+		return SecurityHeadersOff
+	}
+}
+
+// ShareConfig configures 'grove share'
+type ShareConfig struct {
+	// Provider is cloudflared, tailscale or ngrok. When empty, the first
+	// one installed is used.
+	Provider string `yaml:"provider,omitempty"`
+
+	// Command runs a custom tunnel instead, with {port} replaced by the
+	// server's port; the first public https URL it prints is shared
+	Command string `yaml:"command,omitempty"`
+}
+
+// Actions taken on an agent session over its limits
+const (
+	AgentLimitNotify    = "notify"
+	AgentLimitInterrupt = "interrupt"
+)
+
+// AgentLimitsConfig caps the runtime and token use of an AI agent session.
+// Crossing a limit is logged to the activity timeline and published as an
+// agent_limit event once per session.
+type AgentLimitsConfig struct {
+	// MaxRuntime is how long a session may run. Zero disables.
+	MaxRuntime time.Duration `yaml:"max_runtime,omitempty"`
+
+	// MaxTokens is how many tokens a Claude Code session may use, read from
+	// its transcript (cache reads aren't counted). Zero disables.
+	MaxTokens int `yaml:"max_tokens,omitempty"`
+
+	// Action is "notify" (the default), which also pings the agent chat
+	// webhooks, or "interrupt", which sends the agent SIGINT as well
+	Action string `yaml:"action,omitempty"`
+}
+
+// Enabled returns true if any limit is set
+func (c AgentLimitsConfig) Enabled() bool {
+	return c.MaxRuntime > 0 || c.MaxTokens > 0
+}
+
+// TmuxConfig configures 'grove tmux' sessions
+type TmuxConfig struct {
+	// Agent is the command run in the agent window (grove tmux --agent)
+	Agent string `yaml:"agent"`
+
+	// Windows replaces the default layout (server logs, shell, agent).
+	// Commands may use {name}, {path}, {log}, {url} and {port}; an empty
+	// command opens a shell.
+	Windows []TmuxWindow `yaml:"windows,omitempty"`
+}
+
+// TmuxWindow is one window of a 'grove tmux' session
+type TmuxWindow struct {
+	Name    string `yaml:"name"`
+	Command string `yaml:"command,omitempty"`
 }
 
 // TUIConfig holds TUI-specific settings
@@ -66,13 +327,83 @@ type TUIConfig struct {
 	LogLines int  `yaml:"log_lines"`
 }
 
-// NotificationConfig holds notification settings
+// NotificationConfig holds notification settings. The on_* flags select
+// which lifecycle events are sent; each webhook and hook can narrow that
+// further with its own events list.
 type NotificationConfig struct {
-	Enabled    bool `yaml:"enabled"`
-	OnStart    bool `yaml:"on_start"`
-	OnStop     bool `yaml:"on_stop"`
-	OnCrash    bool `yaml:"on_crash"`
-	OnIdleStop bool `yaml:"on_idle_stop"`
+	Enabled        bool `yaml:"enabled"`
+	OnStart        bool `yaml:"on_start"`
+	OnStop         bool `yaml:"on_stop"`
+	OnCrash        bool `yaml:"on_crash"`
+	OnIdleStop     bool `yaml:"on_idle_stop"`
+	OnHealthChange bool `yaml:"on_health_change"`
+
+	// Native sends desktop notifications (Notification Center on macOS,
+	// notify-send on Linux)
+	Native bool `yaml:"native"`
+
+	// Webhooks receive a JSON POST for each event
+	Webhooks []WebhookConfig `yaml:"webhooks,omitempty"`
+
+	// Hooks are shell commands run for each event, with the event as JSON on
+	// stdin and GROVE_EVENT, GROVE_SERVER, GROVE_URL, etc. in the environment
+	Hooks []NotificationHook `yaml:"hooks,omitempty"`
+
+	// Agents pings Slack/Discord about AI agent sessions (opt-in)
+	Agents AgentNotifyConfig `yaml:"agents"`
+}
+
+// AgentNotifyConfig configures Slack/Discord pings for AI agent sessions.
+// Nothing is sent unless a webhook URL is set.
+type AgentNotifyConfig struct {
+	SlackWebhook   string `yaml:"slack_webhook,omitempty"`
+	DiscordWebhook string `yaml:"discord_webhook,omitempty"`
+
+	// LongRunning pings once when an agent has been running longer than this
+	// (checked by 'grove agents --notify'). Zero disables.
+	LongRunning time.Duration `yaml:"long_running"`
+
+	// OnCrash pings when a dev server crashes while an agent is active in
+	// its worktree
+	OnCrash bool `yaml:"on_crash"`
+}
+
+// Configured returns true if at least one chat webhook is set
+func (c AgentNotifyConfig) Configured() bool {
+	return c.SlackWebhook != "" || c.DiscordWebhook != ""
+}
+
+// WebhookConfig configures a webhook notification target
+type WebhookConfig struct {
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers,omitempty"`
+	// Events limits the webhook to these events (start, stop, crash,
+	// idle_stop, health). Empty means all enabled events.
+	Events []string `yaml:"events,omitempty"`
+}
+
+// NotificationHook configures a shell command run on events
+type NotificationHook struct {
+	Command string `yaml:"command"`
+	// Events limits the hook to these events. Empty means all enabled events.
+	Events []string `yaml:"events,omitempty"`
+}
+
+// defaultPortBlockSize is how many ports a port_bases block has by default
+const defaultPortBlockSize = 100
+
+// PortBlock returns the first and last port of the block port_bases gives
+// a repository, and false when it has none
+func (c *Config) PortBlock(repo string) (first, last int, ok bool) {
+	base, ok := c.PortBases[repo]
+	if !ok || base <= 0 {
+		return 0, 0, false
+	}
+	size := c.PortBlockSize
+	if size <= 0 {
+		size = defaultPortBlockSize
+	}
+	return base, base + size - 1, true
 }
 
 // Default returns a Config with default values
@@ -84,28 +415,64 @@ func Default() *Config {
 		TLD:                "localhost",
 		ProxyHTTPPort:      80,
 		ProxyHTTPSPort:     443,
-		LogDir:             filepath.Join(xdg.ConfigHome, "grove", "logs"),
+		LANPortOffset:      10000,
+		ProxyAccessLog:     true,
+		DNSPort:            5354,
+		LogDir:             filepath.Join(ConfigDir(), "logs"),
 		LogMaxSize:         "10MB",
 		LogRetention:       "7d",
+		TrashRetention:     "14d",
+		UsageStats:         true,
 		IdleTimeout:        30 * time.Minute,
 		HealthCheckTimeout: 60 * time.Second,
+		PRCacheTTL:         5 * time.Minute,
 		TUI: TUIConfig{
 			ShowLogs: true,
 			LogLines: 10,
 		},
 		Notifications: NotificationConfig{
-			Enabled:    true,
-			OnStart:    true,
-			OnStop:     true,
-			OnCrash:    true,
-			OnIdleStop: true,
+			Enabled:        true,
+			OnStart:        true,
+			OnStop:         true,
+			OnCrash:        true,
+			OnIdleStop:     true,
+			OnHealthChange: true,
+			Agents: AgentNotifyConfig{
+				LongRunning: 2 * time.Hour,
+				OnCrash:     true,
+			},
+		},
+		Tmux: TmuxConfig{
+			Agent: "claude",
 		},
 	}
 }
 
-// ConfigDir returns the grove configuration directory
+// HomeEnv is the environment variable pointing grove at a state directory
+// other than $XDG_CONFIG_HOME/grove, for isolated instances
+const HomeEnv = "GROVE_HOME"
+
+// ConfigDir returns the grove configuration directory, which holds the
+// config, the registry and the logs: GROVE_HOME if set, else the current
+// context's directory ($XDG_CONFIG_HOME/grove for the default context)
 func ConfigDir() string {
-	return filepath.Join(xdg.ConfigHome, "grove")
+	if dir := os.Getenv(HomeEnv); dir != "" {
+		if abs, err := filepath.Abs(dir); err == nil {
+			return abs
+		}
+		return dir
+	}
+	return ContextDir(CurrentContext())
+}
+
+// SetHome points grove at a state directory, like GROVE_HOME. It sets
+// GROVE_HOME, so the grove processes this one starts use it too.
+func SetHome(dir string) error {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	return os.Setenv(HomeEnv, abs)
 }
 
 // ConfigPath returns the path to the config file
@@ -118,9 +485,133 @@ func RegistryPath() string {
 	return filepath.Join(ConfigDir(), "registry.json")
 }
 
-// SocketPath returns the path to the Unix socket
+// RegistryDBPath returns the path to the SQLite registry
+// (registry_backend: sqlite)
+func RegistryDBPath() string {
+	return filepath.Join(ConfigDir(), "registry.db")
+}
+
+// PRCachePath returns the path to the PR status cache
+func PRCachePath() string {
+	return filepath.Join(ConfigDir(), "pr-cache.json")
+}
+
+// AgentNotifyStatePath returns the path to the record of agent sessions
+// already reported as long-running
+func AgentNotifyStatePath() string {
+	return filepath.Join(ConfigDir(), "agent-notify.json")
+}
+
+// AgentSessionsPath returns the path to the record of agent sessions
+// started by 'grove agent launch'
+func AgentSessionsPath() string {
+	return filepath.Join(ConfigDir(), "agent-sessions.json")
+}
+
+// AgentLimitsStatePath returns the path to the record of agent sessions
+// already over their limits
+func AgentLimitsStatePath() string {
+	return filepath.Join(ConfigDir(), "agent-limits.json")
+}
+
+// ActivityLogPath returns the path to the activity timeline, the log of
+// lifecycle events
+func ActivityLogPath() string {
+	return filepath.Join(ConfigDir(), "activity.jsonl")
+}
+
+// ResumeStatePath returns the path to the record of the last wake from
+// sleep that servers were re-checked after
+func ResumeStatePath() string {
+	return filepath.Join(ConfigDir(), "resume.json")
+}
+
+// MetricsPath returns the path to the persisted metrics counters
+func MetricsPath() string {
+	return filepath.Join(ConfigDir(), "metrics.json")
+}
+
+// UsageLogPath returns the path of the local usage records behind 'grove
+// stats', with the JSON registry backend
+func UsageLogPath() string {
+	return filepath.Join(ConfigDir(), "usage.jsonl")
+}
+
+// DatabasesPath returns the path to the record of provisioned worktree
+// databases
+func DatabasesPath() string {
+	return filepath.Join(ConfigDir(), "databases.json")
+}
+
+// DepCachePath returns the path to the record of how long each
+// repository's dependencies take to install from scratch
+func DepCachePath() string {
+	return filepath.Join(ConfigDir(), "depcache.json")
+}
+
+// FrozenPath returns the path to the snapshot of running servers written
+// by 'grove freeze'
+func FrozenPath() string {
+	return filepath.Join(ConfigDir(), "frozen.json")
+}
+
+// ArchivesPath returns the path to the record of archived worktrees
+func ArchivesPath() string {
+	return filepath.Join(ConfigDir(), "archives.json")
+}
+
+// TrashDir returns the directory holding deleted worktrees' changes, one
+// subdirectory per deletion
+func TrashDir() string {
+	return filepath.Join(ConfigDir(), "trash")
+}
+
+// CrashesDir returns the directory holding crash reports, one
+// subdirectory per server
+func CrashesDir() string {
+	return filepath.Join(ConfigDir(), "crashes")
+}
+
+// AgentTerminalsDir returns the directory holding the terminal output of
+// agents started with 'grove agent launch', one file per running session
+func AgentTerminalsDir() string {
+	return filepath.Join(ConfigDir(), "agent-terminals")
+}
+
+// ScreenshotsDir returns the directory holding review screenshots, one
+// subdirectory per worktree
+func ScreenshotsDir() string {
+	return filepath.Join(ConfigDir(), "screenshots")
+}
+
+// VRTDir returns the directory holding 'grove vrt' reports
+func VRTDir() string {
+	return filepath.Join(ConfigDir(), "vrt")
+}
+
+// TemplatesDir returns the directory holding user project templates
+func TemplatesDir() string {
+	return filepath.Join(ConfigDir(), "templates")
+}
+
+// HooksDir returns the directory holding global hooks, which run for
+// every worktree
+func HooksDir() string {
+	return filepath.Join(ConfigDir(), "hooks")
+}
+
+// SocketPath returns the path to the API's Unix socket. It's in the temp
+// directory, as a socket path can only be about 100 bytes long. Outside
+// the default context, and with GROVE_HOME, its name is derived from the
+// state directory, so each context and isolated instance has its own and
+// a client can't reach another's registry.
 func SocketPath() string {
-	return filepath.Join(os.TempDir(), "grove.sock")
+	name := "grove.sock"
+	if dir := ConfigDir(); dir != ContextDir(DefaultContext) {
+		sum := sha256.Sum256([]byte(dir))
+		name = fmt.Sprintf("grove-%x.sock", sum[:6])
+	}
+	return filepath.Join(os.TempDir(), name)
 }
 
 // Load loads configuration from the specified file, or the default location
@@ -182,15 +673,36 @@ func EnsureDirectories() error {
 	return nil
 }
 
+// ThemeName returns the theme to use: GROVE_THEME if set, else Theme
+func (c *Config) ThemeName() string {
+	if theme := os.Getenv("GROVE_THEME"); theme != "" {
+		return theme
+	}
+	return c.Theme
+}
+
 // ServerURL returns the URL for a server based on the configured URL mode
 func (c *Config) ServerURL(name string, port int) string {
-	if c.URLMode == URLModeSubdomain {
+	switch c.URLMode {
+	case URLModeSubdomain:
 		return "https://" + name + "." + c.TLD
+	case URLModePath:
+		return "https://" + c.PathHost() + "/" + name + "/"
 	}
 	// Default to port mode
 	return "http://localhost:" + strconv.Itoa(port)
 }
 
+// SubdomainServerURL returns the URL for a specific subdomain of a server.
+// In port and path mode it uses <subdomain>.localhost, which browsers
+// resolve to loopback.
+func (c *Config) SubdomainServerURL(name string, port int, subdomain string) string {
+	if c.URLMode == URLModeSubdomain {
+		return "https://" + subdomain + "." + name + "." + c.TLD
+	}
+	return "http://" + subdomain + ".localhost:" + strconv.Itoa(port)
+}
+
 // SubdomainURL returns the wildcard subdomain URL (only meaningful in subdomain mode)
 func (c *Config) SubdomainURL(name string) string {
 	if c.URLMode == URLModeSubdomain {
@@ -199,7 +711,52 @@ func (c *Config) SubdomainURL(name string) string {
 	return ""
 }
 
+// AccessLogDir returns the directory holding the proxy's per-worktree access logs
+func (c *Config) AccessLogDir() string {
+	return filepath.Join(c.LogDir, "access")
+}
+
 // IsSubdomainMode returns true if using subdomain-based URLs
 func (c *Config) IsSubdomainMode() bool {
 	return c.URLMode == URLModeSubdomain
 }
+
+// IsPathMode returns true if using path-based URLs
+func (c *Config) IsPathMode() bool {
+	return c.URLMode == URLModePath
+}
+
+// UsesProxy returns true if server URLs go through the proxy, which must
+// be reloaded when servers start and stop
+func (c *Config) UsesProxy() bool {
+	return c.IsSubdomainMode() || c.IsPathMode()
+}
+
+// ServesLAN returns true if the proxy serves servers to other devices on
+// the network
+func (c *Config) ServesLAN() bool {
+	if c.ProxyListenAddress == "" || c.ProxyListenAddress == "localhost" {
+		return false
+	}
+	ip := net.ParseIP(c.ProxyListenAddress)
+	return ip == nil || !ip.IsLoopback()
+}
+
+// LANPort returns the port the proxy serves a server on to other devices,
+// or 0 when the offset takes it out of range
+func (c *Config) LANPort(port int) int {
+	offset := c.LANPortOffset
+	if offset <= 0 {
+		offset = Default().LANPortOffset
+	}
+	if port+offset > 65535 {
+		return 0
+	}
+	return port + offset
+}
+
+// PathHost returns the host the proxy serves every worktree on in path
+// mode, e.g. grove.localhost
+func (c *Config) PathHost() string {
+	return "grove." + c.TLD
+}
@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"strconv"
 	"testing"
+
+	"github.com/adrg/xdg"
 )
 
 func TestURLModeConstants(t *testing.T) {
@@ -39,6 +41,98 @@ func TestDefaultConfig(t *testing.T) {
 	}
 }
 
+func TestGroveHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv(HomeEnv, home)
+
+	if got := ConfigDir(); got != home {
+		t.Errorf("ConfigDir() = %q, want GROVE_HOME %q", got, home)
+	}
+	if got := RegistryPath(); got != filepath.Join(home, "registry.json") {
+		t.Errorf("RegistryPath() = %q, want it under GROVE_HOME", got)
+	}
+	if got := Default().LogDir; got != filepath.Join(home, "logs") {
+		t.Errorf("Default().LogDir = %q, want it under GROVE_HOME", got)
+	}
+	if got := SocketPath(); got == filepath.Join(os.TempDir(), "grove.sock") {
+		t.Errorf("SocketPath() = %q, want one of GROVE_HOME's own", got)
+	}
+
+	// A relative directory is resolved once, so processes started
+	// elsewhere agree on it
+	t.Chdir(home)
+	if err := SetHome("client-a"); err != nil {
+		t.Fatal(err)
+	}
+	if got := os.Getenv(HomeEnv); got != filepath.Join(home, "client-a") {
+		t.Errorf("GROVE_HOME = %q, want it absolute", got)
+	}
+}
+
+func TestContexts(t *testing.T) {
+	origHome := xdg.ConfigHome
+	t.Cleanup(func() { xdg.ConfigHome = origHome })
+	xdg.ConfigHome = t.TempDir()
+	t.Setenv(HomeEnv, "")
+	t.Setenv(ContextEnv, "")
+
+	base := filepath.Join(xdg.ConfigHome, "grove")
+	if CurrentContext() != DefaultContext || ConfigDir() != base {
+		t.Fatalf("fresh context = %s in %s, want default in %s", CurrentContext(), ConfigDir(), base)
+	}
+	if err := UseContext("work"); err == nil {
+		t.Error("UseContext of a missing context succeeded")
+	}
+
+	if err := os.MkdirAll(ContextDir("work"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := UseContext("work"); err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(base, "contexts", "work"); ConfigDir() != want || NamedContext() != "work" {
+		t.Errorf("ConfigDir() = %s, NamedContext() = %q; want %s, work", ConfigDir(), NamedContext(), want)
+	}
+	if names, _ := Contexts(); len(names) != 2 || names[0] != DefaultContext || names[1] != "work" {
+		t.Errorf("Contexts() = %v", names)
+	}
+
+	workSocket := SocketPath()
+	if workSocket == filepath.Join(os.TempDir(), "grove.sock") {
+		t.Errorf("SocketPath() in context work = %q, the default context's", workSocket)
+	}
+	if err := os.MkdirAll(ContextDir("personal"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(ContextEnv, "personal")
+	if SocketPath() == workSocket {
+		t.Errorf("contexts work and personal share the socket %q", workSocket)
+	}
+	t.Setenv(ContextEnv, "")
+
+	// GROVE_CONTEXT wins for one process; invalid names can't escape the
+	// contexts directory
+	t.Setenv(ContextEnv, DefaultContext)
+	if ConfigDir() != base {
+		t.Errorf("with GROVE_CONTEXT=default, ConfigDir() = %s", ConfigDir())
+	}
+	if got := SocketPath(); got != filepath.Join(os.TempDir(), "grove.sock") {
+		t.Errorf("with GROVE_CONTEXT=default, SocketPath() = %s", got)
+	}
+	t.Setenv(ContextEnv, "../../etc")
+	if CurrentContext() != DefaultContext {
+		t.Errorf("CurrentContext() = %q for an invalid name", CurrentContext())
+	}
+	t.Setenv(ContextEnv, "")
+
+	if err := UseContext(DefaultContext); err != nil || CurrentContext() != DefaultContext {
+		t.Errorf("back to default: %s, %v", CurrentContext(), err)
+	}
+	if err := ValidateContextName(DefaultContext); err == nil {
+		t.Error("a context can't be named default")
+	}
+}
+
 func TestServerURL_PortMode(t *testing.T) {
 	cfg := Default()
 	cfg.URLMode = URLModePort
@@ -154,6 +248,19 @@ func TestSubdomainURL(t *testing.T) {
 	}
 }
 
+func TestSubdomainServerURL(t *testing.T) {
+	cfg := Default()
+	cfg.URLMode = URLModeSubdomain
+	if got, want := cfg.SubdomainServerURL("myapp", 3000, "tenant1"), "https://tenant1.myapp.localhost"; got != want {
+		t.Errorf("subdomain mode: got %q, want %q", got, want)
+	}
+
+	cfg.URLMode = URLModePort
+	if got, want := cfg.SubdomainServerURL("myapp", 3000, "tenant1"), "http://tenant1.localhost:3000"; got != want {
+		t.Errorf("port mode: got %q, want %q", got, want)
+	}
+}
+
 func TestIsSubdomainMode(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -190,6 +297,72 @@ func TestIsSubdomainMode(t *testing.T) {
 	}
 }
 
+func TestPathMode(t *testing.T) {
+	cfg := Default()
+	cfg.URLMode = URLModePath
+	cfg.TLD = "localhost"
+
+	if got, want := cfg.ServerURL("feature-auth", 3042), "https://grove.localhost/feature-auth/"; got != want {
+		t.Errorf("ServerURL() = %q, want %q", got, want)
+	}
+	if got := cfg.SubdomainURL("feature-auth"); got != "" {
+		t.Errorf("SubdomainURL() = %q, want empty", got)
+	}
+	if got, want := cfg.SubdomainServerURL("myapp", 3000, "tenant1"), "http://tenant1.localhost:3000"; got != want {
+		t.Errorf("SubdomainServerURL() = %q, want %q", got, want)
+	}
+	if cfg.IsSubdomainMode() || !cfg.IsPathMode() || !cfg.UsesProxy() {
+		t.Errorf("IsSubdomainMode() = %v, IsPathMode() = %v, UsesProxy() = %v", cfg.IsSubdomainMode(), cfg.IsPathMode(), cfg.UsesProxy())
+	}
+
+	cfg.URLMode = URLModePort
+	if cfg.UsesProxy() {
+		t.Error("UsesProxy() = true in port mode")
+	}
+}
+
+func TestPortBlock(t *testing.T) {
+	cfg := Default()
+	cfg.PortBases = map[string]int{"myapp": 3100, "api": 4100}
+
+	if first, last, ok := cfg.PortBlock("myapp"); !ok || first != 3100 || last != 3199 {
+		t.Errorf("PortBlock(myapp) = %d, %d, %v, want 3100, 3199, true", first, last, ok)
+	}
+	if _, _, ok := cfg.PortBlock("web"); ok {
+		t.Error("PortBlock(web) should have no block")
+	}
+
+	cfg.PortBlockSize = 10
+	if first, last, _ := cfg.PortBlock("api"); first != 4100 || last != 4109 {
+		t.Errorf("PortBlock(api) = %d, %d, want 4100, 4109", first, last)
+	}
+}
+
+func TestServesLAN(t *testing.T) {
+	cfg := Default()
+	for addr, want := range map[string]bool{
+		"":             false,
+		"127.0.0.1":    false,
+		"::1":          false,
+		"localhost":    false,
+		"0.0.0.0":      true,
+		"192.168.1.20": true,
+		"laptop.local": true,
+	} {
+		cfg.ProxyListenAddress = addr
+		if got := cfg.ServesLAN(); got != want {
+			t.Errorf("ServesLAN() with %q = %v, want %v", addr, got, want)
+		}
+	}
+
+	if got := cfg.LANPort(3042); got != 13042 {
+		t.Errorf("LANPort(3042) = %d, want 13042", got)
+	}
+	if got := cfg.LANPort(60000); got != 0 {
+		t.Errorf("LANPort(60000) = %d, want 0 (out of range)", got)
+	}
+}
+
 func TestStrconvItoa(t *testing.T) {
 	tests := []struct {
 		input    int
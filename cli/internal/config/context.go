@@ -0,0 +1,141 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/adrg/xdg"
+)
+
+// DefaultContext is the context grove uses until another is selected. Its
+// state is in $XDG_CONFIG_HOME/grove itself; other contexts live in
+// $XDG_CONFIG_HOME/grove/contexts/<name>.
+const DefaultContext = "default"
+
+// ContextEnv selects a context for one command, like --context. grove
+// sets it for the processes it starts, so they stay in the context they
+// were started in.
+const ContextEnv = "GROVE_CONTEXT"
+
+var contextNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// baseDir is the directory holding the default context and the others
+func baseDir() string {
+	return filepath.Join(xdg.ConfigHome, "grove")
+}
+
+// ContextDir returns the state directory of a context
+func ContextDir(name string) string {
+	if name == DefaultContext {
+		return baseDir()
+	}
+	return filepath.Join(baseDir(), "contexts", name)
+}
+
+// currentContextPath is the file naming the context selected with 'grove
+// context use'
+func currentContextPath() string {
+	return filepath.Join(baseDir(), "context")
+}
+
+// CurrentContext returns the context in use: GROVE_CONTEXT if set, else
+// the one selected with 'grove context use'
+func CurrentContext() string {
+	name := os.Getenv(ContextEnv)
+	if name == "" {
+		data, _ := os.ReadFile(currentContextPath())
+		name = strings.TrimSpace(string(data))
+	}
+	if name == "" || ValidateContextName(name) != nil {
+		return DefaultContext
+	}
+	return name
+}
+
+// NamedContext returns the current context's name to show alongside
+// grove's state, or "" in the default context and with GROVE_HOME
+func NamedContext() string {
+	if os.Getenv(HomeEnv) != "" {
+		return ""
+	}
+	if name := CurrentContext(); name != DefaultContext {
+		return name
+	}
+	return ""
+}
+
+// UseContext selects the context later commands use
+func UseContext(name string) error {
+	if !ContextExists(name) {
+		return fmt.Errorf("no context named '%s'", name)
+	}
+	if name == DefaultContext {
+		if err := os.Remove(currentContextPath()); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	if err := os.MkdirAll(baseDir(), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(currentContextPath(), []byte(name+"\n"), 0644)
+}
+
+// SetContext selects a context for this process and those it starts, like
+// GROVE_CONTEXT
+func SetContext(name string) error {
+	if !ContextExists(name) {
+		return fmt.Errorf("no context named '%s' (see 'grove context list')", name)
+	}
+	return os.Setenv(ContextEnv, name)
+}
+
+// ValidateContextName checks that a name can be used for a new context
+func ValidateContextName(name string) error {
+	if name == DefaultContext {
+		return fmt.Errorf("'%s' is the built-in context", name)
+	}
+	if !contextNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid context name '%s' (use lowercase letters, digits, '-' and '_')", name)
+	}
+	return nil
+}
+
+// ContextExists reports whether a context was created
+func ContextExists(name string) bool {
+	if name == DefaultContext {
+		return true
+	}
+	if ValidateContextName(name) != nil {
+		return false
+	}
+	info, err := os.Stat(ContextDir(name))
+	return err == nil && info.IsDir()
+}
+
+// Contexts returns the names of the contexts, the default one first
+func Contexts() ([]string, error) {
+	names := []string{DefaultContext}
+	entries, err := os.ReadDir(filepath.Join(baseDir(), "contexts"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return names, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var others []string
+	for _, e := range entries {
+		if e.IsDir() && ValidateContextName(e.Name()) == nil {
+			others = append(others, e.Name())
+		}
+	}
+	sort.Strings(others)
+	return append(names, others...), nil
+}
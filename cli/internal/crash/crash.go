@@ -0,0 +1,270 @@
+// Package crash records post-mortem reports for servers that exit
+// unexpectedly: why they exited, how long they ran and their last log lines.
+package crash
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// LogLines is how many log lines a report keeps
+const LogLines = 200
+
+// maxReports is how many reports are kept per server
+const maxReports = 20
+
+// Report is a post-mortem of one crash
+type Report struct {
+	Server    string    `json:"server"`
+	Process   string    `json:"process,omitempty"`
+	Time      time.Time `json:"time"`
+	Reason    string    `json:"reason"`
+	ExitCode  *int      `json:"exit_code,omitempty"`
+	Signal    string    `json:"signal,omitempty"`
+	StartedAt time.Time `json:"started_at,omitempty"`
+	Uptime    string    `json:"uptime,omitempty"`
+	LogFile   string    `json:"log_file,omitempty"`
+	// Fatal are the log lines that matched a fatal pattern before it exited
+	Fatal []string `json:"fatal,omitempty"`
+	Log   []string `json:"log"`
+}
+
+// New creates a report for a server (or one of its processes) that exited
+// with err. err may be nil when the exit status is unknown, e.g. for a
+// detached server that was found dead.
+func New(server, process string, startedAt time.Time, err error) *Report {
+	r := &Report{
+		Server:    server,
+		Process:   process,
+		Time:      time.Now(),
+		StartedAt: startedAt,
+		Reason:    "process exited unexpectedly",
+		Log:       []string{},
+	}
+	if !startedAt.IsZero() {
+		r.Uptime = r.Time.Sub(startedAt).Round(time.Second).String()
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+			r.Signal = status.Signal().String()
+			r.Reason = "killed by signal: " + r.Signal
+		} else {
+			code := exitErr.ExitCode()
+			r.ExitCode = &code
+			r.Reason = fmt.Sprintf("exited with code %d", code)
+		}
+	} else if err != nil {
+		r.Reason = err.Error()
+	}
+	return r
+}
+
+// Summary is a one-line description, e.g. "exited with code 1 after 5m3s"
+func (r *Report) Summary() string {
+	s := r.Reason
+	if r.Process != "" {
+		s = r.Process + ": " + s
+	}
+	if r.Uptime != "" {
+		s += " after " + r.Uptime
+	}
+	return s
+}
+
+// Save writes the report under dir/<server>/ and prunes the server's oldest
+// reports
+func Save(dir string, r *Report) (string, error) {
+	serverDir := filepath.Join(dir, r.Server)
+	if err := os.MkdirAll(serverDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create crash report directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(serverDir, r.Time.UTC().Format("20060102T150405.000000000")+".json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write crash report: %w", err)
+	}
+
+	if paths, err := reportPaths(serverDir); err == nil && len(paths) > maxReports {
+		for _, old := range paths[maxReports:] {
+			os.Remove(old) //nolint:errcheck // Best-effort pruning
+		}
+	}
+	return path, nil
+}
+
+// reportPaths returns the report files in a server's directory, newest first
+func reportPaths(serverDir string) ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(serverDir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	// Names are UTC timestamps, so they sort chronologically
+	sort.Sort(sort.Reverse(sort.StringSlice(paths)))
+	return paths, nil
+}
+
+// List returns the reports for a server, or for every server if name is
+// empty, newest first
+func List(dir, name string) ([]*Report, error) {
+	var servers []string
+	if name != "" {
+		servers = []string{name}
+	} else {
+		entries, err := os.ReadDir(dir)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		for _, e := range entries {
+			if e.IsDir() {
+				servers = append(servers, e.Name())
+			}
+		}
+	}
+
+	var reports []*Report
+	for _, server := range servers {
+		paths, err := reportPaths(filepath.Join(dir, server))
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			var r Report
+			if err := json.Unmarshal(data, &r); err != nil {
+				continue
+			}
+			reports = append(reports, &r)
+		}
+	}
+
+	sort.SliceStable(reports, func(i, j int) bool { return reports[i].Time.After(reports[j].Time) })
+	return reports, nil
+}
+
+// Expired returns the reports, of every server, saved before cutoff
+func Expired(dir string, cutoff time.Time) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var expired []string
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		paths, err := reportPaths(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			if info, err := os.Stat(path); err == nil && info.ModTime().Before(cutoff) {
+				expired = append(expired, path)
+			}
+		}
+	}
+	return expired, nil
+}
+
+// Latest returns a server's most recent report
+func Latest(dir, name string) (*Report, bool) {
+	paths, err := reportPaths(filepath.Join(dir, name))
+	if err != nil || len(paths) == 0 {
+		return nil, false
+	}
+	data, err := os.ReadFile(paths[0])
+	if err != nil {
+		return nil, false
+	}
+	var r Report
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, false
+	}
+	return &r, true
+}
+
+// TailFile returns the last n lines of a file
+func TailFile(path string, n int) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		return []string{}
+	}
+	defer f.Close()
+
+	t := NewTail(n)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		t.add(scanner.Text())
+	}
+	return t.Lines()
+}
+
+// Tail is a writer that keeps the last lines written to it, for servers
+// whose output isn't going to a log file
+type Tail struct {
+	mu      sync.Mutex
+	size    int
+	lines   []string
+	partial string
+}
+
+// NewTail creates a Tail that keeps n lines
+func NewTail(n int) *Tail {
+	return &Tail{size: n}
+}
+
+// Write implements io.Writer
+func (t *Tail) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	text := t.partial + string(p)
+	lines := strings.Split(text, "\n")
+	t.partial = lines[len(lines)-1]
+	for _, line := range lines[:len(lines)-1] {
+		t.add(line)
+	}
+	return len(p), nil
+}
+
+func (t *Tail) add(line string) {
+	t.lines = append(t.lines, strings.TrimSuffix(line, "\r"))
+	if len(t.lines) > t.size {
+		t.lines = t.lines[len(t.lines)-t.size:]
+	}
+}
+
+// Lines returns the kept lines, including an unterminated last line
+func (t *Tail) Lines() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	lines := append([]string{}, t.lines...)
+	if t.partial != "" {
+		lines = append(lines, t.partial)
+		if len(lines) > t.size {
+			lines = lines[1:]
+		}
+	}
+	return lines
+}
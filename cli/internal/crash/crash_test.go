@@ -0,0 +1,120 @@
+package crash
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNew_ExitCodeAndSignal(t *testing.T) {
+	err := exec.Command("sh", "-c", "exit 3").Run()
+	r := New("feature", "", time.Now().Add(-90*time.Second), err)
+	if r.ExitCode == nil || *r.ExitCode != 3 {
+		t.Fatalf("ExitCode = %v, want 3", r.ExitCode)
+	}
+	if r.Reason != "exited with code 3" || r.Uptime != "1m30s" {
+		t.Errorf("Summary = %q", r.Summary())
+	}
+
+	err = exec.Command("sh", "-c", "kill -9 $$").Run()
+	r = New("feature", "worker", time.Time{}, err)
+	if r.ExitCode != nil || r.Signal != "killed" {
+		t.Errorf("ExitCode = %v, Signal = %q", r.ExitCode, r.Signal)
+	}
+	if got := r.Summary(); got != "worker: killed by signal: killed" {
+		t.Errorf("Summary = %q", got)
+	}
+
+	r = New("feature", "", time.Time{}, nil)
+	if r.Reason != "process exited unexpectedly" {
+		t.Errorf("Reason = %q", r.Reason)
+	}
+}
+
+func TestSaveListLatest(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Now()
+	for i := range maxReports + 2 {
+		r := New("api", "", time.Time{}, fmt.Errorf("crash %d", i))
+		r.Time = start.Add(time.Duration(i) * time.Second)
+		if _, err := Save(dir, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	other := New("web", "", time.Time{}, nil)
+	other.Time = start.Add(time.Hour)
+	if _, err := Save(dir, other); err != nil {
+		t.Fatal(err)
+	}
+
+	reports, err := List(dir, "api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != maxReports {
+		t.Fatalf("expected %d reports after pruning, got %d", maxReports, len(reports))
+	}
+	if reports[0].Reason != fmt.Sprintf("crash %d", maxReports+1) {
+		t.Errorf("newest report = %q", reports[0].Reason)
+	}
+
+	all, _ := List(dir, "")
+	if len(all) != maxReports+1 || all[0].Server != "web" {
+		t.Errorf("List all: %d reports, newest %q", len(all), all[0].Server)
+	}
+
+	if latest, ok := Latest(dir, "api"); !ok || latest.Reason != reports[0].Reason {
+		t.Errorf("Latest = %v, %v", latest, ok)
+	}
+	if _, ok := Latest(dir, "missing"); ok {
+		t.Error("Latest should be false for a server without reports")
+	}
+}
+
+func TestExpired(t *testing.T) {
+	dir := t.TempDir()
+	old, err := Save(dir, New("api", "", time.Time{}, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	week := time.Now().Add(-7 * 24 * time.Hour)
+	if err := os.Chtimes(old, week, week); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Save(dir, New("web", "", time.Time{}, nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	expired, err := Expired(dir, time.Now().Add(-24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(expired) != 1 || expired[0] != old {
+		t.Errorf("Expired = %v, want [%s]", expired, old)
+	}
+
+	if expired, err := Expired(filepath.Join(dir, "missing"), time.Now()); err != nil || len(expired) != 0 {
+		t.Errorf("Expired on a missing directory = %v, %v", expired, err)
+	}
+}
+
+func TestTail(t *testing.T) {
+	tail := NewTail(3)
+	fmt.Fprint(tail, "one\ntwo\nthr")
+	fmt.Fprint(tail, "ee\nfour\nfive")
+	if got := strings.Join(tail.Lines(), ","); got != "three,four,five" {
+		t.Errorf("Lines = %s", got)
+	}
+
+	path := filepath.Join(t.TempDir(), "server.log")
+	if err := os.WriteFile(path, []byte("a\nb\nc\nd\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(TailFile(path, 2), ","); got != "c,d" {
+		t.Errorf("TailFile = %s", got)
+	}
+}
@@ -0,0 +1,132 @@
+package crash
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// DefaultFatalPatterns are the log lines that mean a server is broken even
+// though its process may still be running: a port conflict, a crash of the
+// runtime, or a line logged at fatal or panic level
+var DefaultFatalPatterns = []string{
+	`EADDRINUSE`,
+	`(?i)address already in use`,
+	`Segmentation fault`,
+	`JavaScript heap out of memory`,
+	`^panic: `,
+	`\bFATAL\b`,
+	`(?i)\blevel=(fatal|panic)\b`,
+	`(?i)"level":\s*"(fatal|panic)"`,
+}
+
+// maxFatalLines is how many matching lines a FatalWatcher keeps
+const maxFatalLines = 10
+
+// maxFatalLine is how much of an unterminated line a FatalWatcher keeps
+const maxFatalLine = 64 * 1024
+
+// ansiEscape matches terminal color sequences, which servers put around
+// their log levels
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+
+// CompileFatal compiles fatal log patterns. nil means the defaults; an
+// empty list turns detection off.
+func CompileFatal(patterns []string) ([]*regexp.Regexp, error) {
+	if patterns == nil {
+		patterns = DefaultFatalPatterns
+	}
+	res := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fatal_log_patterns entry %q: %w", p, err)
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+// FindFatal returns the lines that match any of the patterns
+func FindFatal(lines []string, patterns []*regexp.Regexp) []string {
+	var found []string
+	for _, line := range lines {
+		if matchesAny(line, patterns) {
+			found = append(found, line)
+		}
+	}
+	return found
+}
+
+func matchesAny(line string, patterns []*regexp.Regexp) bool {
+	line = ansiEscape.ReplaceAllString(line, "")
+	for _, re := range patterns {
+		if re.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// FatalWatcher is a writer that scans a server's output, line by line, for
+// fatal lines. The first one calls onFatal right away; the rest are only
+// kept, for the crash report.
+type FatalWatcher struct {
+	patterns []*regexp.Regexp
+	onFatal  func(line string)
+
+	mu      sync.Mutex
+	partial []byte
+	matches []string
+}
+
+// NewFatalWatcher returns a FatalWatcher calling onFatal with the first
+// line that matches one of the patterns
+func NewFatalWatcher(patterns []*regexp.Regexp, onFatal func(line string)) *FatalWatcher {
+	return &FatalWatcher{patterns: patterns, onFatal: onFatal}
+}
+
+func (w *FatalWatcher) Write(p []byte) (int, error) {
+	if len(w.patterns) == 0 {
+		return len(p), nil
+	}
+
+	w.mu.Lock()
+	data := append(w.partial, p...)
+	var first string
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		line := string(bytes.TrimRight(data[:i], "\r"))
+		data = data[i+1:]
+		if !matchesAny(line, w.patterns) {
+			continue
+		}
+		if len(w.matches) == 0 {
+			first = line
+		}
+		if len(w.matches) < maxFatalLines {
+			w.matches = append(w.matches, line)
+		}
+	}
+	if len(data) > maxFatalLine {
+		data = data[len(data)-maxFatalLine:]
+	}
+	w.partial = append([]byte(nil), data...)
+	w.mu.Unlock()
+
+	if first != "" && w.onFatal != nil {
+		w.onFatal(first)
+	}
+	return len(p), nil
+}
+
+// Matches returns the fatal lines seen so far
+func (w *FatalWatcher) Matches() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]string(nil), w.matches...)
+}
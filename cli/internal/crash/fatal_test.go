@@ -0,0 +1,45 @@
+package crash
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFatalWatcher(t *testing.T) {
+	patterns, err := CompileFatal(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var flagged []string
+	w := NewFatalWatcher(patterns, func(line string) { flagged = append(flagged, line) })
+
+	w.Write([]byte("Listening on 3000\nError: listen EADDR"))                 //nolint:errcheck
+	w.Write([]byte("INUSE: address already in use :::3000\r\n"))              //nolint:errcheck
+	w.Write([]byte("\x1b[31mFATAL\x1b[0m -- : database is gone\nInfo: ok\n")) //nolint:errcheck
+
+	if want := []string{"Error: listen EADDRINUSE: address already in use :::3000"}; !reflect.DeepEqual(flagged, want) {
+		t.Errorf("flagged %q, want only the first fatal line %q", flagged, want)
+	}
+	if got := w.Matches(); len(got) != 2 {
+		t.Errorf("Matches() = %q, want 2 lines", got)
+	}
+}
+
+func TestCompileFatal(t *testing.T) {
+	patterns, err := CompileFatal([]string{})
+	if err != nil || len(patterns) != 0 {
+		t.Errorf("CompileFatal([]) = %v, %v; want detection off", patterns, err)
+	}
+	if _, err := CompileFatal([]string{"("}); err == nil {
+		t.Error("CompileFatal should reject an invalid pattern")
+	}
+
+	patterns, err = CompileFatal([]string{`Killed`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := []string{"FATAL: not a match now", "Killed"}
+	if got := FindFatal(lines, patterns); !reflect.DeepEqual(got, []string{"Killed"}) {
+		t.Errorf("FindFatal() = %q, want [Killed]", got)
+	}
+}
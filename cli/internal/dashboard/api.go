@@ -2,21 +2,64 @@ package dashboard
 
 import (
 	"encoding/json"
+	"log"
 	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
 	"time"
+
+	"github.com/iheanyi/grove/internal/accesslog"
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/github"
+	"github.com/iheanyi/grove/internal/metrics"
+	"github.com/iheanyi/grove/internal/screenshot"
 )
 
 // WorkspaceResponse represents a workspace in API responses
 type WorkspaceResponse struct {
-	Name      string          `json:"name"`
-	Path      string          `json:"path"`
-	Branch    string          `json:"branch"`
-	MainRepo  string          `json:"main_repo,omitempty"`
-	GitDirty  bool            `json:"git_dirty"`
-	HasClaude bool            `json:"has_claude"`
-	HasVSCode bool            `json:"has_vscode"`
-	Tags      []string        `json:"tags,omitempty"`
-	Server    *ServerResponse `json:"server,omitempty"`
+	Name      string           `json:"name"`
+	Path      string           `json:"path"`
+	Branch    string           `json:"branch"`
+	MainRepo  string           `json:"main_repo,omitempty"`
+	GitDirty  bool             `json:"git_dirty"`
+	HasClaude bool             `json:"has_claude"`
+	HasVSCode bool             `json:"has_vscode"`
+	Tags      []string         `json:"tags,omitempty"`
+	Server    *ServerResponse  `json:"server,omitempty"`
+	PR        *PRResponse      `json:"pr,omitempty"`
+	Traffic   *TrafficResponse `json:"traffic,omitempty"`
+	Task      *TaskResponse    `json:"task,omitempty"`
+
+	// Screenshots are URLs of the PNGs 'grove review --screenshots' saved
+	Screenshots []string `json:"screenshots,omitempty"`
+}
+
+// TaskResponse is the task in progress in a worktree
+type TaskResponse struct {
+	ID     string `json:"id,omitempty"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+	Source string `json:"source"`
+}
+
+// TrafficResponse summarizes proxied requests over the last hour
+type TrafficResponse struct {
+	Host     string         `json:"host,omitempty"`
+	Requests int            `json:"requests"`
+	Status   map[string]int `json:"status"`
+	P50Ms    float64        `json:"p50_ms"`
+	P95Ms    float64        `json:"p95_ms"`
+}
+
+// PRResponse represents pull request and CI status in API responses
+type PRResponse struct {
+	Number       int    `json:"number,omitempty"`
+	Title        string `json:"title,omitempty"`
+	URL          string `json:"url,omitempty"`
+	State        string `json:"state,omitempty"`
+	ReviewStatus string `json:"review_status,omitempty"`
+	CIStatus     string `json:"ci_status,omitempty"`
 }
 
 // ServerResponse represents server state in API responses
@@ -27,6 +70,11 @@ type ServerResponse struct {
 	Health    string    `json:"health,omitempty"`
 	StartedAt time.Time `json:"started_at,omitempty"`
 	Uptime    string    `json:"uptime,omitempty"`
+
+	// CPU and memory of the server's process tree, sampled every few seconds
+	CPUPercent float64   `json:"cpu_percent,omitempty"`
+	RSSBytes   uint64    `json:"rss_bytes,omitempty"`
+	CPUHistory []float64 `json:"cpu_history,omitempty"`
 }
 
 // AgentResponse represents an agent in API responses
@@ -38,6 +86,11 @@ type AgentResponse struct {
 	PID       int       `json:"pid"`
 	StartTime time.Time `json:"start_time,omitempty"`
 	Duration  string    `json:"duration,omitempty"`
+	Task      string    `json:"task,omitempty"`
+
+	// Terminal is true when the agent's output can be streamed from
+	// /api/agents/<worktree>/terminal
+	Terminal bool `json:"terminal,omitempty"`
 }
 
 // HealthResponse represents the API health check response
@@ -46,14 +99,46 @@ type HealthResponse struct {
 	Timestamp string `json:"timestamp"`
 }
 
-// handleWorkspaces handles GET /api/workspaces
+// newPRResponse converts branch info into an API response, or nil if the
+// branch has no PR or CI status
+func newPRResponse(info *github.BranchInfo) *PRResponse {
+	resp := &PRResponse{}
+	if info.PR != nil {
+		resp.Number = info.PR.Number
+		resp.Title = info.PR.Title
+		resp.URL = info.PR.URL
+		resp.State = github.FormatPRStatus(info.PR)
+		resp.ReviewStatus = info.PR.ReviewStatus
+	}
+	if info.CI != nil {
+		resp.CIStatus = info.CI.State
+	}
+	if resp.Number == 0 && resp.CIStatus == "" {
+		return nil
+	}
+	return resp
+}
+
+// newTrafficResponse converts access log stats into an API response
+func newTrafficResponse(stats *accesslog.HostStats) *TrafficResponse {
+	return &TrafficResponse{
+		Host:     stats.Host,
+		Requests: stats.Requests,
+		Status:   stats.Status,
+		P50Ms:    float64(stats.P50.Microseconds()) / 1000,
+		P95Ms:    float64(stats.P95.Microseconds()) / 1000,
+	}
+}
+
+// handleWorkspaces handles GET /api/workspaces. ?tag=frontend (repeatable)
+// limits the response to workspaces with any of the tags.
 func (s *Server) handleWorkspaces(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	workspaces := s.getWorkspacesData()
+	workspaces := filterByTags(s.getWorkspacesData(), r.URL.Query()["tag"])
 
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
@@ -64,6 +149,20 @@ func (s *Server) handleWorkspaces(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// filterByTags returns the workspaces with any of the given tags
+func filterByTags(workspaces []WorkspaceResponse, tags []string) []WorkspaceResponse {
+	if len(tags) == 0 {
+		return workspaces
+	}
+	filtered := make([]WorkspaceResponse, 0, len(workspaces))
+	for _, ws := range workspaces {
+		if slices.ContainsFunc(ws.Tags, func(tag string) bool { return slices.Contains(tags, tag) }) {
+			filtered = append(filtered, ws)
+		}
+	}
+	return filtered
+}
+
 // handleAgents handles GET /api/agents
 func (s *Server) handleAgents(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -71,7 +170,7 @@ func (s *Server) handleAgents(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	agents := s.getAgentsData()
+	agents := s.getAgentsData(r.Context())
 
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Access-Control-Allow-Origin", "*")
@@ -102,3 +201,86 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
+
+// handleMetrics handles GET /metrics in the Prometheus text format
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.mu.RLock()
+	servers := s.registry.List()
+	proxy := s.registry.GetProxy()
+	s.mu.RUnlock()
+
+	counters, err := metrics.Load()
+	if err != nil {
+		log.Printf("Failed to load metrics: %v", err)
+	}
+
+	snapshot := metrics.Snapshot{Servers: servers, Counters: counters}
+	if proxy.IsRunning() {
+		if requests, err := metrics.ProxyRequests(metrics.CaddyAdminURL); err == nil {
+			snapshot.ProxyRequests = requests
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := metrics.Write(w, snapshot); err != nil {
+		log.Printf("Failed to write metrics: %v", err)
+	}
+}
+
+// handleProxyStats handles GET /api/proxy/stats with per-host traffic over
+// the last hour
+func (s *Server) handleProxyStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.mu.RLock()
+	hosts := make([]*TrafficResponse, 0, len(s.hostStats))
+	for _, stats := range s.hostStats {
+		hosts = append(hosts, newTrafficResponse(stats))
+	}
+	s.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	if err := json.NewEncoder(w).Encode(hosts); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// screenshotURLs returns the URLs the dashboard serves a worktree's
+// screenshots at
+func screenshotURLs(name string, files []string) []string {
+	urls := make([]string, 0, len(files))
+	for _, file := range files {
+		urls = append(urls, "/api/screenshots/"+name+"/"+filepath.Base(file))
+	}
+	return urls
+}
+
+// handleScreenshot handles GET /api/screenshots/<worktree>/<file>.png
+func (s *Server) handleScreenshot(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name, file, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/screenshots/"), "/")
+	if !ok || name == "" || name == "." || name == ".." || strings.Contains(file, "/") ||
+		filepath.Ext(file) != ".png" || strings.HasPrefix(file, ".") {
+		http.NotFound(w, r)
+		return
+	}
+
+	// Screenshots are replaced on every capture
+	w.Header().Set("Cache-Control", "no-cache")
+	http.ServeFile(w, r, filepath.Join(screenshot.Dir(config.ScreenshotsDir(), name), file))
+}
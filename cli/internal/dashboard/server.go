@@ -1,7 +1,10 @@
 package dashboard
 
 import (
+	"bytes"
+	"context"
 	"embed"
+	"encoding/json"
 	"fmt"
 	"io/fs"
 	"log"
@@ -13,8 +16,14 @@ import (
 	"sync"
 	"time"
 
+	"github.com/iheanyi/grove/internal/accesslog"
+	"github.com/iheanyi/grove/internal/config"
 	"github.com/iheanyi/grove/internal/discovery"
+	"github.com/iheanyi/grove/internal/github"
 	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/screenshot"
+	"github.com/iheanyi/grove/internal/tasks"
+	"github.com/iheanyi/grove/internal/usage"
 )
 
 //go:embed web/build/*
@@ -28,6 +37,14 @@ type Server struct {
 	mux       *http.ServeMux
 	wsHub     *Hub
 	registry  *registry.Registry
+	prCache   *github.Cache
+	prTTL     time.Duration
+	prInfo    map[string]*github.BranchInfo // keyed by workspace path
+	tld       string
+	accessLog string
+	traffic   map[string]*accesslog.HostStats // keyed by server name
+	hostStats []*accesslog.HostStats
+	usage     *usage.Sampler
 	mu        sync.RWMutex
 	server    *http.Server
 	listeners []net.Listener
@@ -38,6 +55,14 @@ type Config struct {
 	Port    int
 	DevMode bool
 	DevURL  string
+
+	// PRCacheTTL controls how often PR/CI status is refreshed
+	PRCacheTTL time.Duration
+
+	// AccessLogDir holds the proxy's access logs used for traffic stats;
+	// empty disables them. TLD maps proxied hosts back to servers.
+	AccessLogDir string
+	TLD          string
 }
 
 // NewServer creates a new dashboard server
@@ -48,12 +73,19 @@ func NewServer(cfg Config) (*Server, error) {
 	}
 
 	s := &Server{
-		port:     cfg.Port,
-		devMode:  cfg.DevMode,
-		devURL:   cfg.DevURL,
-		mux:      http.NewServeMux(),
-		wsHub:    NewHub(),
-		registry: reg,
+		port:      cfg.Port,
+		devMode:   cfg.DevMode,
+		devURL:    cfg.DevURL,
+		mux:       http.NewServeMux(),
+		wsHub:     NewHub(),
+		registry:  reg,
+		prCache:   github.NewCache(config.PRCachePath(), cfg.PRCacheTTL),
+		prTTL:     cfg.PRCacheTTL,
+		prInfo:    make(map[string]*github.BranchInfo),
+		tld:       cfg.TLD,
+		accessLog: cfg.AccessLogDir,
+		traffic:   make(map[string]*accesslog.HostStats),
+		usage:     usage.NewSampler(usageSamples),
 	}
 
 	s.setupRoutes()
@@ -65,11 +97,20 @@ func (s *Server) setupRoutes() {
 	// API routes
 	s.mux.HandleFunc("/api/workspaces", s.handleWorkspaces)
 	s.mux.HandleFunc("/api/agents", s.handleAgents)
+	s.mux.HandleFunc("/api/agents/", s.handleAgentTerminal)
 	s.mux.HandleFunc("/api/health", s.handleHealth)
+	s.mux.HandleFunc("/api/proxy/stats", s.handleProxyStats)
+	s.mux.HandleFunc("/api/screenshots/", s.handleScreenshot)
+
+	// Prometheus metrics
+	s.mux.HandleFunc("/metrics", s.handleMetrics)
 
 	// WebSocket route
 	s.mux.HandleFunc("/ws", s.wsHub.HandleWebSocket)
 
+	// Colors of the configured theme
+	s.mux.HandleFunc("/theme.css", s.handleTheme)
+
 	// Static files (SvelteKit build)
 	if s.devMode {
 		// In dev mode, proxy to Vite dev server
@@ -149,8 +190,13 @@ func (s *Server) Start() error {
 	// Start WebSocket hub
 	go s.wsHub.Run()
 
-	// Start background update goroutine
+	// Start background update goroutines
 	go s.backgroundUpdates()
+	go s.prUpdates()
+	go s.usageUpdates()
+	if s.accessLog != "" {
+		go s.trafficUpdates()
+	}
 
 	addr := fmt.Sprintf(":%d", s.port)
 	s.server = &http.Server{
@@ -184,32 +230,136 @@ func (s *Server) URL() string {
 	return fmt.Sprintf("http://localhost:%d", s.port)
 }
 
-// backgroundUpdates periodically updates the registry and broadcasts changes
+// backgroundUpdates reloads the registry when it changes and broadcasts
+// the workspaces and agents to WebSocket clients when they change
 func (s *Server) backgroundUpdates() {
+	load := registry.LoadContext
+	var changes <-chan registry.Change
+	if cache, err := registry.NewCache(context.Background()); err == nil {
+		defer cache.Close()
+		load = cache.Get
+		changes, _ = cache.Subscribe()
+	} else {
+		log.Printf("Failed to watch registry, reloading it periodically: %v", err)
+	}
+
+	// Agents and server stats change without the registry changing
 	ticker := time.NewTicker(2 * time.Second)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		// Reload registry
-		s.mu.Lock()
-		reg, err := registry.Load()
+	var lastWorkspaces, lastAgents []byte
+	for {
+		select {
+		case <-changes:
+		case <-ticker.C:
+		}
+
+		reg, err := load(context.Background())
 		if err == nil {
+			s.mu.Lock()
 			s.registry = reg
+			s.mu.Unlock()
 		}
-		s.mu.Unlock()
 
-		// Broadcast update to WebSocket clients
+		// Broadcast only what changed, so clients don't re-render needlessly
 		workspaces := s.getWorkspacesData()
-		s.wsHub.Broadcast(Message{
-			Type:    "workspaces_updated",
-			Payload: workspaces,
-		})
-
-		agents := s.getAgentsData()
-		s.wsHub.Broadcast(Message{
-			Type:    "agents_updated",
-			Payload: agents,
-		})
+		if data, err := json.Marshal(workspaces); err == nil && !bytes.Equal(data, lastWorkspaces) {
+			lastWorkspaces = data
+			s.wsHub.Broadcast(Message{
+				Type:    "workspaces_updated",
+				Payload: workspaces,
+			})
+		}
+
+		agents := s.getAgentsData(context.Background())
+		if data, err := json.Marshal(agents); err == nil && !bytes.Equal(data, lastAgents) {
+			lastAgents = data
+			s.wsHub.Broadcast(Message{
+				Type:    "agents_updated",
+				Payload: agents,
+			})
+		}
+	}
+}
+
+// prUpdates refreshes PR/CI status for all workspaces. It runs separately
+// from backgroundUpdates because gh/glab calls can be slow; the cache keeps
+// requests within the TTL.
+func (s *Server) prUpdates() {
+	interval := max(s.prTTL, time.Minute)
+
+	for {
+		s.mu.RLock()
+		workspaces := s.registry.ListWorkspaces()
+		s.mu.RUnlock()
+
+		lookups := make([]github.Lookup, 0, len(workspaces))
+		for _, ws := range workspaces {
+			lookups = append(lookups, github.Lookup{Dir: ws.Path, Branch: ws.Branch})
+		}
+		info := github.FetchAll(s.prCache, lookups)
+
+		s.mu.Lock()
+		s.prInfo = info
+		s.mu.Unlock()
+
+		time.Sleep(interval)
+	}
+}
+
+// trafficWindow is how far back the dashboard's traffic stats look
+const trafficWindow = time.Hour
+
+// trafficUpdates periodically summarizes the proxy's access logs
+func (s *Server) trafficUpdates() {
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	// Only what the proxy logged since the last tick is parsed
+	logs := accesslog.NewDirReader(s.accessLog)
+	for ; ; <-ticker.C {
+		since := time.Now().Add(-trafficWindow)
+		entries, err := logs.Read(since)
+		if err != nil {
+			log.Printf("Failed to read access logs: %v", err)
+			continue
+		}
+
+		traffic := make(map[string]*accesslog.HostStats)
+		for _, stats := range accesslog.SummarizeServers(entries, since, s.tld) {
+			traffic[stats.Host] = stats
+		}
+		hostStats := accesslog.Summarize(entries, since)
+
+		s.mu.Lock()
+		s.traffic = traffic
+		s.hostStats = hostStats
+		s.mu.Unlock()
+	}
+}
+
+// usageSamples is how many CPU samples each server keeps for its sparkline
+// (two minutes at usageUpdates' interval)
+const usageSamples = 30
+
+// usageUpdates periodically samples the CPU and memory of running servers
+func (s *Server) usageUpdates() {
+	ticker := time.NewTicker(4 * time.Second)
+	defer ticker.Stop()
+
+	for ; ; <-ticker.C {
+		s.mu.RLock()
+		pids := make(map[string]int)
+		for _, server := range s.registry.ListRunning() {
+			if server.PID > 0 {
+				pids[server.Name] = server.PID
+			}
+		}
+		s.mu.RUnlock()
+
+		if err := s.usage.Sample(pids); err != nil {
+			log.Printf("Failed to sample server usage: %v", err)
+		}
 	}
 }
 
@@ -249,6 +399,27 @@ func (s *Server) getWorkspacesData() []WorkspaceResponse {
 			Tags:     ws.Tags,
 		}
 
+		if info := s.prInfo[ws.Path]; info != nil {
+			resp.PR = newPRResponse(info)
+		}
+
+		if stats := s.traffic[ws.Name]; stats != nil {
+			resp.Traffic = newTrafficResponse(stats)
+		}
+
+		if task := tasks.Active(ws.Path); task != nil {
+			resp.Task = &TaskResponse{
+				ID:     task.ID,
+				Title:  task.Title,
+				Status: string(task.Status),
+				Source: task.Source,
+			}
+		}
+
+		if files := screenshot.List(config.ScreenshotsDir(), ws.Name); len(files) > 0 {
+			resp.Screenshots = screenshotURLs(ws.Name, files)
+		}
+
 		if ws.Server != nil {
 			resp.Server = &ServerResponse{
 				Port:      ws.Server.Port,
@@ -257,6 +428,11 @@ func (s *Server) getWorkspacesData() []WorkspaceResponse {
 				Health:    string(ws.Server.Health),
 				StartedAt: ws.Server.StartedAt,
 			}
+			if u, ok := s.usage.Latest(ws.Name); ok {
+				resp.Server.CPUPercent = u.CPU
+				resp.Server.RSSBytes = u.RSS
+				resp.Server.CPUHistory = usage.CPUHistory(s.usage.History(ws.Name))
+			}
 		}
 
 		result = append(result, resp)
@@ -265,13 +441,15 @@ func (s *Server) getWorkspacesData() []WorkspaceResponse {
 	return result
 }
 
-// getAgentsData fetches agent data from worktrees
-func (s *Server) getAgentsData() []AgentResponse {
+// getAgentsData fetches agent data from worktrees, stopping early if ctx is
+// done (a client that went away)
+func (s *Server) getAgentsData(ctx context.Context) []AgentResponse {
 	s.mu.RLock()
 	worktrees := s.registry.ListWorktrees()
 	s.mu.RUnlock()
 
 	var agents []AgentResponse
+	launched := discovery.LaunchedAgents()
 
 	for _, wt := range worktrees {
 		// Create a copy for detection
@@ -281,7 +459,10 @@ func (s *Server) getAgentsData() []AgentResponse {
 			Branch: wt.Branch,
 		}
 
-		if err := discovery.DetectActivity(wtCopy); err != nil {
+		if err := discovery.DetectActivity(ctx, wtCopy); err != nil {
+			if ctx.Err() != nil {
+				break
+			}
 			continue
 		}
 
@@ -294,6 +475,8 @@ func (s *Server) getAgentsData() []AgentResponse {
 				PID:       wtCopy.Agent.PID,
 				StartTime: wtCopy.Agent.StartTime,
 				Duration:  formatDuration(time.Since(wtCopy.Agent.StartTime)),
+				Task:      wtCopy.Agent.TaskSummary,
+				Terminal:  launched[wt.Path] != nil && launched[wt.Path].OutputLog != "",
 			})
 		}
 	}
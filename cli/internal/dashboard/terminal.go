@@ -0,0 +1,156 @@
+package dashboard
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/iheanyi/grove/internal/discovery"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// terminalBacklog is how much earlier output a viewer gets on connecting
+	terminalBacklog = 64 * 1024
+
+	// terminalPoll is how often a transcript is checked for new output
+	terminalPoll = 200 * time.Millisecond
+)
+
+// handleAgentTerminal handles GET /api/agents/<worktree>/terminal: a
+// read-only WebSocket stream of the output of the agent 'grove agent
+// launch' started in a worktree, as raw terminal bytes in binary frames. The
+// stream ends when the session does.
+func (s *Server) handleAgentTerminal(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name, rest, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/agents/"), "/")
+	if !ok || rest != "terminal" {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.mu.RLock()
+	ws, ok := s.registry.GetWorkspace(name)
+	s.mu.RUnlock()
+	if !ok {
+		http.Error(w, "Worktree not found", http.StatusNotFound)
+		return
+	}
+	agent, ok := discovery.LaunchedAgents()[ws.Path]
+	if !ok || agent.OutputLog == "" {
+		http.Error(w, "No agent terminal (start the agent with 'grove agent launch')", http.StatusNotFound)
+		return
+	}
+
+	websocket.Server{
+		Handshake: sameOrigin,
+		Handler: func(conn *websocket.Conn) {
+			defer conn.Close()
+			conn.PayloadType = websocket.BinaryFrame
+
+			// The view is read-only: incoming messages are discarded, and
+			// reading only notices the viewer leaving
+			ctx, cancel := context.WithCancel(r.Context())
+			defer cancel()
+			go func() {
+				_, _ = io.Copy(io.Discard, conn)
+				cancel()
+			}()
+
+			_ = streamTerminal(ctx, conn, agent.OutputLog, terminalPoll)
+		},
+	}.ServeHTTP(w, r)
+}
+
+// sameOrigin rejects WebSocket connections opened by other sites' pages,
+// which browsers allow regardless of CORS
+func sameOrigin(config *websocket.Config, r *http.Request) error {
+	origin, err := websocket.Origin(config, r)
+	if err != nil {
+		return err
+	}
+	if origin == nil || origin.Host != r.Host {
+		return fmt.Errorf("cross-origin connection from %v", origin)
+	}
+	return nil
+}
+
+// streamTerminal copies the end of a transcript to w, then its output as
+// it's written, until ctx is done or the transcript is removed
+func streamTerminal(ctx context.Context, w io.Writer, path string, poll time.Duration) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	offset := info.Size() - terminalBacklog
+	if offset < 0 {
+		offset = 0
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	if offset == 0 {
+		// util-linux script(1) starts the transcript with a header line
+		if err := skipScriptHeader(f); err != nil {
+			return err
+		}
+	}
+
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := f.Read(buf)
+		if n > 0 {
+			if _, err := w.Write(buf[:n]); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		// Caught up: the transcript is removed when the session ends
+		if _, err := os.Stat(path); err != nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(poll):
+		}
+	}
+}
+
+// skipScriptHeader moves past a "Script started on ..." line at the start
+// of a transcript, leaving the offset at the start otherwise
+func skipScriptHeader(f *os.File) error {
+	head := make([]byte, 512)
+	n, err := f.Read(head)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	skip := 0
+	if bytes.HasPrefix(head[:n], []byte("Script started on ")) {
+		if i := bytes.IndexByte(head[:n], '\n'); i >= 0 {
+			skip = i + 1
+		}
+	}
+	_, err = f.Seek(int64(skip), io.SeekStart)
+	return err
+}
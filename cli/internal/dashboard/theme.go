@@ -0,0 +1,75 @@
+package dashboard
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/iheanyi/grove/internal/styles"
+)
+
+// themeVars maps the dashboard's CSS variables to palette colors
+var themeVars = []struct{ name, color string }{
+	{"--grove-bg", "background"},
+	{"--grove-surface", "surface"},
+	{"--grove-border", "border"},
+	{"--grove-text", "text"},
+	{"--grove-muted", "muted"},
+	{"--grove-green", "secondary"},
+	{"--grove-primary", "primary"},
+	{"--grove-warning", "warning"},
+	{"--grove-error", "error"},
+}
+
+// slateScale is Tailwind's slate scale, which the components use for
+// surfaces and text. The light theme reverses it.
+var slateScale = []struct{ shade, color string }{
+	{"50", "#f8fafc"},
+	{"100", "#f1f5f9"},
+	{"200", "#e2e8f0"},
+	{"300", "#cbd5e1"},
+	{"400", "#94a3b8"},
+	{"500", "#64748b"},
+	{"600", "#475569"},
+	{"700", "#334155"},
+	{"800", "#1e293b"},
+	{"900", "#0f172a"},
+	{"950", "#020617"},
+}
+
+// handleTheme handles GET /theme.css with the CSS variables of the
+// configured theme
+func (s *Server) handleTheme(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/css; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	theme, palette := styles.Current()
+	fmt.Fprint(w, themeCSS(theme, palette))
+}
+
+// themeCSS returns a stylesheet setting the dashboard's colors. Palette
+// colors given as ANSI numbers have no CSS equivalent and are left out.
+func themeCSS(theme string, palette styles.Palette) string {
+	var b strings.Builder
+	b.WriteString(":root {\n")
+
+	scheme := "dark"
+	if theme == styles.ThemeLight {
+		scheme = "light"
+	}
+	fmt.Fprintf(&b, "\tcolor-scheme: %s;\n", scheme)
+
+	for _, v := range themeVars {
+		if color := palette[v.color]; strings.HasPrefix(color, "#") {
+			fmt.Fprintf(&b, "\t%s: %s;\n", v.name, color)
+		}
+	}
+
+	if theme == styles.ThemeLight {
+		for i, s := range slateScale {
+			fmt.Fprintf(&b, "\t--color-slate-%s: %s;\n", s.shade, slateScale[len(slateScale)-1-i].color)
+		}
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
@@ -0,0 +1,253 @@
+// A minimal terminal screen for the read-only agent terminal view. It
+// applies the cursor movement and erase sequences agent TUIs redraw with,
+// and drops colors and other styling.
+
+const ESC = '\x1b';
+
+export class TerminalScreen {
+	private normal: string[][] = [[]];
+	private alternate: string[][] | null = null;
+	private row = 0;
+	private col = 0;
+	private savedRow = 0;
+	private savedCol = 0;
+	private pending = '';
+	private rows: number;
+	private scrollback: number;
+
+	constructor(rows = 40, scrollback = 2000) {
+		this.rows = rows;
+		this.scrollback = scrollback;
+	}
+
+	private get lines(): string[][] {
+		return this.alternate ?? this.normal;
+	}
+
+	// top is the first line of the visible screen, which absolute cursor
+	// positions are relative to
+	private get top(): number {
+		return Math.max(0, this.lines.length - this.rows);
+	}
+
+	write(data: string) {
+		data = this.pending + data;
+		this.pending = '';
+
+		let i = 0;
+		while (i < data.length) {
+			const ch = data[i];
+			if (ch === ESC) {
+				const end = this.escapeEnd(data, i);
+				if (end < 0) {
+					// Incomplete sequence: wait for the rest
+					this.pending = data.slice(i);
+					return;
+				}
+				this.escape(data.slice(i, end));
+				i = end;
+				continue;
+			}
+
+			switch (ch) {
+				case '\r':
+					this.col = 0;
+					break;
+				case '\n':
+					this.moveTo(this.row + 1, this.col);
+					break;
+				case '\b':
+					this.col = Math.max(0, this.col - 1);
+					break;
+				case '\t':
+					this.col = (Math.floor(this.col / 8) + 1) * 8;
+					break;
+				default:
+					if (ch >= ' ') {
+						this.put(ch);
+					}
+			}
+			i++;
+		}
+		this.trim();
+	}
+
+	text(): string {
+		return this.lines.map((line) => line.join('').trimEnd()).join('\n');
+	}
+
+	// escapeEnd returns the index after the escape sequence at i, or -1 if
+	// data ends before it does
+	private escapeEnd(data: string, i: number): number {
+		if (i + 1 >= data.length) return -1;
+		const kind = data[i + 1];
+		if (kind === '[') {
+			for (let j = i + 2; j < data.length; j++) {
+				const code = data.charCodeAt(j);
+				if (code >= 0x40 && code <= 0x7e) return j + 1;
+			}
+			return -1;
+		}
+		if (kind === ']' || kind === 'P' || kind === '_') {
+			// OSC and other strings end with BEL or ST
+			for (let j = i + 2; j < data.length; j++) {
+				if (data[j] === '\x07') return j + 1;
+				if (data[j] === ESC && data[j + 1] === '\\') return j + 2;
+			}
+			return -1;
+		}
+		if (kind === '(' || kind === ')') {
+			return i + 3 <= data.length ? i + 3 : -1;
+		}
+		return i + 2;
+	}
+
+	private escape(seq: string) {
+		if (seq === ESC + '7') {
+			this.savedRow = this.row;
+			this.savedCol = this.col;
+			return;
+		}
+		if (seq === ESC + '8') {
+			this.moveTo(this.savedRow, this.savedCol);
+			return;
+		}
+		if (seq[1] !== '[') return;
+
+		const final = seq[seq.length - 1];
+		const body = seq.slice(2, -1);
+		const priv = body.startsWith('?');
+		const params = (priv ? body.slice(1) : body).split(';').map((p) => parseInt(p, 10));
+		const n = (index = 0, fallback = 1) => (Number.isNaN(params[index]) ? fallback : params[index] || fallback);
+
+		if (priv) {
+			if (params.some((p) => p === 1049 || p === 47 || p === 1047)) {
+				if (final === 'h') {
+					this.alternate = [[]];
+					this.savedRow = this.row;
+					this.savedCol = this.col;
+					this.row = 0;
+					this.col = 0;
+				} else if (final === 'l') {
+					this.alternate = null;
+					this.moveTo(this.savedRow, this.savedCol);
+				}
+			}
+			return;
+		}
+
+		switch (final) {
+			case 'A':
+				this.moveTo(Math.max(this.top, this.row - n()), this.col);
+				break;
+			case 'B':
+				this.moveTo(this.row + n(), this.col);
+				break;
+			case 'C':
+				this.col += n();
+				break;
+			case 'D':
+				this.col = Math.max(0, this.col - n());
+				break;
+			case 'E':
+				this.moveTo(this.row + n(), 0);
+				break;
+			case 'F':
+				this.moveTo(Math.max(this.top, this.row - n()), 0);
+				break;
+			case 'G':
+				this.col = n() - 1;
+				break;
+			case 'H':
+			case 'f':
+				this.moveTo(this.top + n(0) - 1, n(1) - 1);
+				break;
+			case 'J':
+				this.eraseDisplay(n(0, 0));
+				break;
+			case 'K':
+				this.eraseLine(n(0, 0));
+				break;
+		}
+	}
+
+	private moveTo(row: number, col: number) {
+		while (this.lines.length <= row) {
+			this.lines.push([]);
+		}
+		this.row = row;
+		this.col = Math.max(0, col);
+	}
+
+	private put(ch: string) {
+		const line = this.lines[this.row];
+		while (line.length < this.col) {
+			line.push(' ');
+		}
+		line[this.col] = ch;
+		this.col++;
+	}
+
+	private eraseLine(mode: number) {
+		const line = this.lines[this.row];
+		if (mode === 0) {
+			line.length = Math.min(line.length, this.col);
+		} else if (mode === 1) {
+			for (let c = 0; c <= this.col && c < line.length; c++) line[c] = ' ';
+		} else {
+			line.length = 0;
+		}
+	}
+
+	private eraseDisplay(mode: number) {
+		if (mode === 0) {
+			this.eraseLine(0);
+			this.lines.length = this.row + 1;
+		} else if (mode === 1) {
+			for (let r = this.top; r < this.row; r++) this.lines[r] = [];
+			this.eraseLine(1);
+		} else {
+			// Keep what scrolled off; clear the visible screen
+			const top = this.top;
+			this.lines.length = top;
+			this.moveTo(top, this.col);
+		}
+	}
+
+	// trim drops scrollback beyond the limit
+	private trim() {
+		const excess = this.lines.length - this.rows - this.scrollback;
+		if (excess > 0) {
+			this.lines.splice(0, excess);
+			this.row = Math.max(0, this.row - excess);
+			this.savedRow = Math.max(0, this.savedRow - excess);
+		}
+	}
+}
+
+// connectTerminal streams an agent's terminal output into a screen,
+// calling onUpdate after each chunk and onClose when the session ends
+export function connectTerminal(
+	worktree: string,
+	screen: TerminalScreen,
+	onUpdate: () => void,
+	onClose: () => void
+): () => void {
+	const protocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:';
+	const ws = new WebSocket(
+		`${protocol}//${window.location.host}/api/agents/${encodeURIComponent(worktree)}/terminal`
+	);
+	ws.binaryType = 'arraybuffer';
+
+	const decoder = new TextDecoder();
+	ws.onmessage = (event) => {
+		screen.write(decoder.decode(event.data as ArrayBuffer, { stream: true }));
+		onUpdate();
+	};
+	ws.onclose = onClose;
+
+	return () => {
+		ws.onclose = null;
+		ws.close();
+	};
+}
@@ -2,11 +2,38 @@
 
 export interface ServerResponse {
 	port: number;
-	status: 'running' | 'stopped' | 'starting' | 'error';
+	status: 'running' | 'stopped' | 'starting' | 'paused' | 'error';
 	url: string;
 	health?: string;
 	started_at?: string;
 	uptime?: string;
+	cpu_percent?: number;
+	rss_bytes?: number;
+	cpu_history?: number[];
+}
+
+export interface PRResponse {
+	number?: number;
+	title?: string;
+	url?: string;
+	state?: 'open' | 'draft' | 'merged' | 'closed';
+	review_status?: 'approved' | 'changes_requested' | 'pending' | 'none';
+	ci_status?: string;
+}
+
+export interface TrafficResponse {
+	host?: string;
+	requests: number;
+	status: Record<string, number>;
+	p50_ms: number;
+	p95_ms: number;
+}
+
+export interface TaskResponse {
+	id?: string;
+	title: string;
+	status: 'open' | 'in_progress' | 'blocked' | 'done';
+	source: 'tasuku' | 'beads' | 'todo';
 }
 
 export interface WorkspaceResponse {
@@ -19,6 +46,10 @@ export interface WorkspaceResponse {
 	has_vscode: boolean;
 	tags?: string[];
 	server?: ServerResponse;
+	pr?: PRResponse;
+	traffic?: TrafficResponse;
+	task?: TaskResponse;
+	screenshots?: string[];
 }
 
 export interface AgentResponse {
@@ -29,6 +60,8 @@ export interface AgentResponse {
 	pid: number;
 	start_time?: string;
 	duration?: string;
+	task?: string;
+	terminal?: boolean;
 }
 
 export interface HealthResponse {
@@ -0,0 +1,286 @@
+// Package database provisions a database per worktree by cloning a template
+// database with the postgres or mysql CLI tools
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/iheanyi/grove/internal/project"
+)
+
+const (
+	Postgres = "postgres"
+	MySQL    = "mysql"
+
+	// DefaultEnv is the variable the database URL is injected as
+	DefaultEnv = "DATABASE_URL"
+
+	// maxNameLength is Postgres's identifier limit (MySQL allows 64)
+	maxNameLength = 63
+)
+
+// Database is a provisioned worktree database
+type Database struct {
+	Provider  string    `json:"provider"`
+	Name      string    `json:"name"`
+	Template  string    `json:"template"`
+	ServerURL string    `json:"server_url"`
+	Env       string    `json:"env"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// New describes the database for a branch, without creating it
+func New(cfg project.DatabaseConfig, branch string) (*Database, error) {
+	serverURL := cfg.URL
+	switch cfg.Provider {
+	case Postgres, "postgresql":
+		cfg.Provider = Postgres
+		if serverURL == "" {
+			serverURL = "postgres://localhost:5432"
+		}
+	case MySQL:
+		if serverURL == "" {
+			serverURL = "mysql://root@localhost:3306"
+		}
+	default:
+		return nil, fmt.Errorf("unsupported database provider %q (use postgres or mysql)", cfg.Provider)
+	}
+	if cfg.Template == "" {
+		return nil, fmt.Errorf("database template is required")
+	}
+
+	env := cfg.Env
+	if env == "" {
+		env = DefaultEnv
+	}
+
+	return &Database{
+		Provider:  cfg.Provider,
+		Name:      Name(cfg.Template, branch),
+		Template:  cfg.Template,
+		ServerURL: serverURL,
+		Env:       env,
+	}, nil
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Name returns the database name for a branch, keeping the template's
+// prefix: template myapp_dev and branch feature/x give myapp_feature_x
+func Name(template, branch string) string {
+	prefix := template
+	if i := strings.LastIndex(template, "_"); i > 0 {
+		prefix = template[:i]
+	}
+
+	suffix := strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(branch), "_"), "_")
+	name := prefix + "_" + suffix
+	if len(name) > maxNameLength {
+		name = strings.TrimRight(name[:maxNameLength], "_")
+	}
+	return name
+}
+
+// URL returns the connection URL for the database
+func (d *Database) URL() string {
+	return d.urlFor(d.Name)
+}
+
+func (d *Database) urlFor(name string) string {
+	u, err := url.Parse(d.ServerURL)
+	if err != nil {
+		return strings.TrimRight(d.ServerURL, "/") + "/" + name
+	}
+	u.Path = "/" + name
+	return u.String()
+}
+
+// Create clones the template into a new database
+func (d *Database) Create() error {
+	switch d.Provider {
+	case Postgres:
+		return d.psql(fmt.Sprintf("CREATE DATABASE %s TEMPLATE %s", quotePostgres(d.Name), quotePostgres(d.Template)))
+	case MySQL:
+		if err := d.mysqlExec(fmt.Sprintf("CREATE DATABASE %s", quoteMySQL(d.Name))); err != nil {
+			return err
+		}
+		if err := d.mysqlClone(); err != nil {
+			_ = d.Drop()
+			return err
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported database provider %q", d.Provider)
+}
+
+// Drop deletes the database if it exists
+func (d *Database) Drop() error {
+	switch d.Provider {
+	case Postgres:
+		return d.psql(fmt.Sprintf("DROP DATABASE IF EXISTS %s", quotePostgres(d.Name)))
+	case MySQL:
+		return d.mysqlExec(fmt.Sprintf("DROP DATABASE IF EXISTS %s", quoteMySQL(d.Name)))
+	}
+	return fmt.Errorf("unsupported database provider %q", d.Provider)
+}
+
+// psql runs a statement against the server's maintenance database.
+// CREATE ... TEMPLATE fails while anything is connected to the template.
+func (d *Database) psql(statement string) error {
+	cmd := exec.Command("psql", "-X", "-q", "-v", "ON_ERROR_STOP=1", "-d", d.urlFor("postgres"), "-c", statement)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("psql: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// mysqlArgs returns connection flags for the mysql tools, and the password
+// to pass as MYSQL_PWD so it stays off the command line
+func (d *Database) mysqlArgs() ([]string, string, error) {
+	u, err := url.Parse(d.ServerURL)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid database url: %w", err)
+	}
+
+	var args []string
+	if host := u.Hostname(); host != "" {
+		args = append(args, "-h", host)
+	}
+	if port := u.Port(); port != "" {
+		args = append(args, "-P", port)
+	}
+	if user := u.User.Username(); user != "" {
+		args = append(args, "-u", user)
+	}
+	password, _ := u.User.Password()
+	return args, password, nil
+}
+
+func (d *Database) mysqlCommand(name string, args ...string) (*exec.Cmd, error) {
+	connArgs, password, err := d.mysqlArgs()
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command(name, append(connArgs, args...)...)
+	if password != "" {
+		cmd.Env = append(os.Environ(), "MYSQL_PWD="+password)
+	}
+	return cmd, nil
+}
+
+func (d *Database) mysqlExec(statement string) error {
+	cmd, err := d.mysqlCommand("mysql", "-e", statement)
+	if err != nil {
+		return err
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("mysql: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// mysqlClone copies the template into the new database with mysqldump
+func (d *Database) mysqlClone() error {
+	dump, err := d.mysqlCommand("mysqldump", "--single-transaction", "--routines", "--triggers", d.Template)
+	if err != nil {
+		return err
+	}
+	load, err := d.mysqlCommand("mysql", d.Name)
+	if err != nil {
+		return err
+	}
+
+	pipe, err := dump.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	var dumpErr, loadErr strings.Builder
+	dump.Stderr = &dumpErr
+	load.Stdin = pipe
+	load.Stderr = &loadErr
+
+	if err := dump.Start(); err != nil {
+		return fmt.Errorf("mysqldump: %w", err)
+	}
+	if err := load.Run(); err != nil {
+		_, _ = io.Copy(io.Discard, pipe)
+		_ = dump.Wait()
+		return fmt.Errorf("mysql: %w: %s", err, strings.TrimSpace(loadErr.String()))
+	}
+	if err := dump.Wait(); err != nil {
+		return fmt.Errorf("mysqldump: %w: %s", err, strings.TrimSpace(dumpErr.String()))
+	}
+	return nil
+}
+
+func quotePostgres(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func quoteMySQL(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// Store records the databases provisioned for each worktree path, so they
+// can be injected on start and dropped on delete
+type Store struct {
+	path      string
+	Databases map[string]*Database `json:"databases"`
+}
+
+// LoadStore reads the store at path; a missing file is an empty store
+func LoadStore(path string) (*Store, error) {
+	s := &Store{path: path, Databases: make(map[string]*Database)}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+	if s.Databases == nil {
+		s.Databases = make(map[string]*Database)
+	}
+	return s, nil
+}
+
+// Get returns the database provisioned for a worktree
+func (s *Store) Get(worktreePath string) (*Database, bool) {
+	d, ok := s.Databases[worktreePath]
+	return d, ok
+}
+
+// Set records the database provisioned for a worktree
+func (s *Store) Set(worktreePath string, d *Database) error {
+	s.Databases[worktreePath] = d
+	return s.save()
+}
+
+// Remove forgets the database for a worktree
+func (s *Store) Remove(worktreePath string) error {
+	delete(s.Databases, worktreePath)
+	return s.save()
+}
+
+func (s *Store) save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0600)
+}
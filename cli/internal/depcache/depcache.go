@@ -0,0 +1,244 @@
+// Package depcache seeds a new worktree's dependency directories from the
+// main worktree, so 'grove new' needn't install them from scratch
+package depcache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/iheanyi/grove/internal/project"
+)
+
+// Modes of copying files
+const (
+	ModeHardlink = "hardlink"
+	ModeCopy     = "copy"
+)
+
+// Plan is what to copy into a new worktree and what to run afterwards,
+// with the preset applied
+type Plan struct {
+	Paths   []string
+	Mode    string
+	Install string
+}
+
+// presets are the defaults for each package manager. pnpm already links
+// packages from its global store and bundler can share one install path, so
+// they need nothing copied.
+var presets = map[string]Plan{
+	"npm":     {Paths: []string{"node_modules"}, Install: "npm install --prefer-offline --no-audit --no-fund"},
+	"yarn":    {Paths: []string{"node_modules"}, Install: "yarn install --prefer-offline"},
+	"pnpm":    {Install: "pnpm install --prefer-offline"},
+	"bundler": {Install: "bundle config set --local path {main}/vendor/bundle && bundle install"},
+}
+
+// Presets returns the names of the presets
+func Presets() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resolve applies the preset to the configured cache, with explicit
+// settings winning, and expands {main} in the install command
+func Resolve(cfg project.CacheConfig, mainPath string) (Plan, error) {
+	var plan Plan
+	if cfg.Preset != "" {
+		preset, ok := presets[cfg.Preset]
+		if !ok {
+			return Plan{}, fmt.Errorf("unknown cache preset %q (use %s)", cfg.Preset, strings.Join(Presets(), ", "))
+		}
+		plan = preset
+	}
+	if len(cfg.Paths) > 0 {
+		plan.Paths = cfg.Paths
+	}
+	if cfg.Install != "" {
+		plan.Install = cfg.Install
+	}
+
+	plan.Mode = cfg.Mode
+	switch plan.Mode {
+	case "":
+		plan.Mode = ModeHardlink
+	case ModeHardlink, ModeCopy:
+	default:
+		return Plan{}, fmt.Errorf("unknown cache mode %q (use hardlink or copy)", cfg.Mode)
+	}
+
+	for _, path := range plan.Paths {
+		if filepath.IsAbs(path) || strings.HasPrefix(filepath.Clean(path), "..") {
+			return Plan{}, fmt.Errorf("cache path %q must be inside the worktree", path)
+		}
+	}
+	plan.Install = strings.ReplaceAll(plan.Install, "{main}", mainPath)
+	return plan, nil
+}
+
+// Stats counts what was copied
+type Stats struct {
+	Files int
+	Bytes uint64
+}
+
+// Seed copies the plan's directories that exist in the main worktree into
+// the new one and returns the ones it copied. Directories the new worktree
+// already has are left alone.
+func Seed(plan Plan, mainPath, worktreePath string) ([]string, Stats, error) {
+	var seeded []string
+	var stats Stats
+	hardlink := plan.Mode == ModeHardlink
+	for _, pattern := range plan.Paths {
+		matches, err := filepath.Glob(filepath.Join(mainPath, pattern))
+		if err != nil {
+			return seeded, stats, fmt.Errorf("bad cache path %q: %w", pattern, err)
+		}
+		for _, src := range matches {
+			info, err := os.Lstat(src)
+			if err != nil || !info.IsDir() {
+				continue
+			}
+			rel, err := filepath.Rel(mainPath, src)
+			if err != nil {
+				continue
+			}
+			dst := filepath.Join(worktreePath, rel)
+			if _, err := os.Lstat(dst); err == nil {
+				continue
+			}
+			if err := copyTree(src, dst, &hardlink, &stats); err != nil {
+				return seeded, stats, fmt.Errorf("failed to copy %s: %w", rel, err)
+			}
+			seeded = append(seeded, rel)
+		}
+	}
+	return seeded, stats, nil
+}
+
+// copyTree copies the directory src to dst, recreating symlinks as they
+// are. Hardlinking falls back to copying for good once it fails, e.g.
+// because the worktree is on another filesystem.
+func copyTree(src, dst string, hardlink *bool, stats *Stats) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case !info.Mode().IsRegular():
+			return nil
+		}
+
+		if *hardlink {
+			err := os.Link(path, target)
+			if err == nil {
+				stats.Files++
+				stats.Bytes += uint64(info.Size())
+				return nil
+			}
+			if !errors.Is(err, syscall.EXDEV) && !errors.Is(err, fs.ErrPermission) && !errors.Is(err, syscall.EMLINK) {
+				return err
+			}
+			*hardlink = false
+		}
+		if err := copyFile(path, target, info.Mode().Perm()); err != nil {
+			return err
+		}
+		stats.Files++
+		stats.Bytes += uint64(info.Size())
+		return nil
+	})
+}
+
+func copyFile(src, dst string, perm fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// Timings records how long each repository's dependencies took to install
+// without anything seeded, to estimate the time seeding saves
+type Timings struct {
+	path     string
+	Installs map[string]int64 `json:"installs_ms"`
+}
+
+// LoadTimings reads the timings at path; a missing file has none
+func LoadTimings(path string) (*Timings, error) {
+	t := &Timings{path: path, Installs: make(map[string]int64)}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return t, nil
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(data, t); err != nil {
+		return nil, err
+	}
+	if t.Installs == nil {
+		t.Installs = make(map[string]int64)
+	}
+	return t, nil
+}
+
+// FullInstall returns how long a repository's full install took
+func (t *Timings) FullInstall(repoPath string) (time.Duration, bool) {
+	ms, ok := t.Installs[repoPath]
+	return time.Duration(ms) * time.Millisecond, ok
+}
+
+// SetFullInstall records how long a repository's full install took
+func (t *Timings) SetFullInstall(repoPath string, d time.Duration) error {
+	t.Installs[repoPath] = d.Milliseconds()
+	if err := os.MkdirAll(filepath.Dir(t.path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(t.path, data, 0644)
+}
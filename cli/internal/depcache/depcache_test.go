@@ -0,0 +1,117 @@
+package depcache
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/iheanyi/grove/internal/project"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	plan, err := Resolve(project.CacheConfig{Preset: "npm"}, "/src/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(plan.Paths, []string{"node_modules"}) || plan.Mode != ModeHardlink || plan.Install == "" {
+		t.Errorf("Resolve(npm) = %+v", plan)
+	}
+
+	plan, err = Resolve(project.CacheConfig{Preset: "bundler", Install: "bundle install --path {main}/vendor", Mode: "copy"}, "/src/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Install != "bundle install --path /src/app/vendor" || plan.Mode != ModeCopy || len(plan.Paths) != 0 {
+		t.Errorf("Resolve(bundler with overrides) = %+v", plan)
+	}
+
+	for _, cfg := range []project.CacheConfig{
+		{Preset: "maven"},
+		{Paths: []string{"node_modules"}, Mode: "reflink"},
+		{Paths: []string{"../other/node_modules"}},
+	} {
+		if _, err := Resolve(cfg, "/src/app"); err == nil {
+			t.Errorf("Resolve(%+v) should fail", cfg)
+		}
+	}
+}
+
+func TestSeed(t *testing.T) {
+	main, wt := t.TempDir(), t.TempDir()
+	writeFile(t, filepath.Join(main, "node_modules", "left-pad", "index.js"), "module.exports = 1\n")
+	writeFile(t, filepath.Join(main, "packages", "web", "node_modules", "react", "index.js"), "react\n")
+	writeFile(t, filepath.Join(main, "packages", "api", "node_modules", "pg", "index.js"), "pg\n")
+	if err := os.Symlink("../left-pad/index.js", filepath.Join(main, "node_modules", "left-pad", "bin")); err != nil {
+		t.Fatal(err)
+	}
+	// The worktree already has api's, so it's left alone
+	writeFile(t, filepath.Join(wt, "packages", "api", "node_modules", "own.js"), "own\n")
+
+	plan := Plan{Paths: []string{"node_modules", "packages/*/node_modules", "missing"}, Mode: ModeHardlink}
+	seeded, stats, err := Seed(plan, main, wt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"node_modules", filepath.Join("packages", "web", "node_modules")}; !reflect.DeepEqual(seeded, want) {
+		t.Errorf("seeded %v, want %v", seeded, want)
+	}
+	if stats.Files != 2 || stats.Bytes != 25 {
+		t.Errorf("stats = %+v, want 2 files, 25 bytes", stats)
+	}
+
+	a, _ := os.Stat(filepath.Join(main, "node_modules", "left-pad", "index.js"))
+	b, err := os.Stat(filepath.Join(wt, "node_modules", "left-pad", "index.js"))
+	if err != nil || !os.SameFile(a, b) {
+		t.Errorf("index.js should be hardlinked")
+	}
+	if link, err := os.Readlink(filepath.Join(wt, "node_modules", "left-pad", "bin")); err != nil || link != "../left-pad/index.js" {
+		t.Errorf("symlink = %q, %v", link, err)
+	}
+	if _, err := os.Stat(filepath.Join(wt, "packages", "api", "node_modules", "pg")); err == nil {
+		t.Error("existing directory should be left alone")
+	}
+
+	// Copies are separate files
+	copied := t.TempDir()
+	if _, _, err := Seed(Plan{Paths: []string{"node_modules"}, Mode: ModeCopy}, main, copied); err != nil {
+		t.Fatal(err)
+	}
+	c, err := os.Stat(filepath.Join(copied, "node_modules", "left-pad", "index.js"))
+	if err != nil || os.SameFile(a, c) {
+		t.Errorf("index.js should be copied")
+	}
+}
+
+func TestTimings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "depcache.json")
+	timings, err := LoadTimings(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := timings.FullInstall("/src/app"); ok {
+		t.Error("no install should be recorded yet")
+	}
+	if err := timings.SetFullInstall("/src/app", 90*time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	timings, err = LoadTimings(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d, ok := timings.FullInstall("/src/app"); !ok || d != 90*time.Second {
+		t.Errorf("FullInstall() = %v, %v, want 1m30s", d, ok)
+	}
+}
@@ -0,0 +1,208 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iheanyi/grove/internal/git"
+)
+
+// cacheVersion is bumped when the cache file's format changes, discarding
+// older caches
+const cacheVersion = 2
+
+const (
+	gitDir  = "dir"
+	gitFile = "file"
+)
+
+// Cache remembers directory listings and repositories' worktrees between
+// scans. Entries are keyed by modification time: a directory's changes when
+// entries are added or removed, and a repository's .git directories change
+// when worktrees are added, removed or switch branches.
+type Cache struct {
+	path string
+
+	mu    sync.Mutex
+	data  cacheData
+	roots []string
+	seen  map[string]bool
+	dirty bool
+}
+
+type cacheData struct {
+	Version   int                        `json:"version"`
+	Dirs      map[string]cachedDir       `json:"dirs"`
+	Worktrees map[string]cachedWorktrees `json:"worktrees"`
+}
+
+type cachedDir struct {
+	ModTime time.Time `json:"mtime"`
+	Subdirs []string  `json:"subdirs,omitempty"`
+	// Git is "dir" for a repository and "file" for a linked worktree
+	Git string `json:"git,omitempty"`
+}
+
+type cachedWorktrees struct {
+	ModTime   time.Time      `json:"mtime"`
+	Worktrees []git.Worktree `json:"worktrees"`
+}
+
+// LoadCache loads the cache at path. A missing or unreadable cache starts
+// empty, since it's rebuilt by scanning.
+func LoadCache(path string) *Cache {
+	c := &Cache{path: path, seen: make(map[string]bool)}
+	if data, err := os.ReadFile(path); err == nil {
+		if json.Unmarshal(data, &c.data) != nil || c.data.Version != cacheVersion {
+			c.data = cacheData{}
+		}
+	}
+	c.data.Version = cacheVersion
+	if c.data.Dirs == nil {
+		c.data.Dirs = make(map[string]cachedDir)
+	}
+	if c.data.Worktrees == nil {
+		c.data.Worktrees = make(map[string]cachedWorktrees)
+	}
+	return c
+}
+
+// Save writes the cache if it changed, dropping directories and
+// repositories under the scanned paths that the scans didn't reach
+func (c *Cache) Save() error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for dir := range c.data.Dirs {
+		if !c.seen[dir] && c.underRoot(dir) {
+			delete(c.data.Dirs, dir)
+			c.dirty = true
+		}
+	}
+	for repo := range c.data.Worktrees {
+		if !c.seen[repo] && c.underRoot(repo) {
+			delete(c.data.Worktrees, repo)
+			c.dirty = true
+		}
+	}
+	if !c.dirty {
+		return nil
+	}
+
+	data, err := json.Marshal(c.data)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), ".scan-cache-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	c.dirty = false
+	return nil
+}
+
+func (c *Cache) underRoot(dir string) bool {
+	for _, root := range c.roots {
+		if isUnder(dir, root) {
+			return true
+		}
+	}
+	return false
+}
+
+// isUnder returns whether path is dir or inside it
+func isUnder(path, dir string) bool {
+	return path == dir || strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+func (c *Cache) startScan(root string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.roots = append(c.roots, root)
+	// The latest scan of a root decides which of its directories are kept
+	for dir := range c.seen {
+		if isUnder(dir, root) {
+			delete(c.seen, dir)
+		}
+	}
+}
+
+func (c *Cache) dir(dir string, modTime time.Time) (cachedDir, bool) {
+	if c == nil {
+		return cachedDir{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	listing, ok := c.data.Dirs[dir]
+	if !ok || !listing.ModTime.Equal(modTime) {
+		return cachedDir{}, false
+	}
+	c.seen[dir] = true
+	return listing, true
+}
+
+func (c *Cache) putDir(dir string, listing cachedDir) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.data.Dirs[dir] = listing
+	c.seen[dir] = true
+	c.dirty = true
+}
+
+// Worktrees lists a repository's worktrees, from the cache when they
+// haven't changed. The cache outlives the process, unlike git.Client's,
+// which a nil Cache falls back to.
+func (c *Cache) Worktrees(ctx context.Context, repoPath string) ([]git.Worktree, error) {
+	modTime := git.WorktreesModTime(git.CommonDir(repoPath))
+	if c != nil && !modTime.IsZero() {
+		c.mu.Lock()
+		cached, ok := c.data.Worktrees[repoPath]
+		c.mu.Unlock()
+		if ok && cached.ModTime.Equal(modTime) {
+			return cached.Worktrees, nil
+		}
+	}
+
+	worktrees, err := gitOps().Worktrees(ctx, repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if c != nil && !modTime.IsZero() {
+		c.mu.Lock()
+		c.data.Worktrees[repoPath] = cachedWorktrees{ModTime: modTime, Worktrees: worktrees}
+		c.dirty = true
+		c.mu.Unlock()
+	}
+	return worktrees, nil
+}
@@ -1,15 +1,30 @@
 package discovery
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/iheanyi/grove/internal/editor"
+	"github.com/iheanyi/grove/internal/execx"
+	"github.com/iheanyi/grove/internal/git"
+	"github.com/iheanyi/grove/internal/tasks"
+	"github.com/iheanyi/grove/internal/worktree"
 )
 
+// runner runs git, ps, pgrep and lsof; tests swap in an execx.Fake
+var runner execx.Runner = execx.OS
+
+// gitOps returns the Git that runs git with runner
+func gitOps() git.Git {
+	return git.New(runner)
+}
+
 // AgentInfo represents an active AI agent/assistant session
 type AgentInfo struct {
 	Type      string    `json:"type"`       // "claude", "cursor", "copilot", etc.
@@ -18,9 +33,21 @@ type AgentInfo struct {
 	StartTime time.Time `json:"start_time"` // When the process started
 	Command   string    `json:"command"`    // Full command line
 
-	// Tasuku integration
-	ActiveTask  string `json:"active_task,omitempty"`  // Current Tasuku task ID (if any)
+	// Task tracker integration (Tasuku, Beads or TODO.md)
+	ActiveTask  string `json:"active_task,omitempty"`  // Current task ID (if any)
 	TaskSummary string `json:"task_summary,omitempty"` // Task description for display
+
+	// OutputLog is the terminal output of a session started with
+	// 'grove agent launch', while it runs
+	OutputLog string `json:"output_log,omitempty"`
+}
+
+// SetAgentTask records the task in progress in a worktree on its agent
+func SetAgentTask(agent *AgentInfo, path string) {
+	if task := tasks.Active(path); task != nil {
+		agent.ActiveTask = task.ID
+		agent.TaskSummary = task.Summary()
+	}
 }
 
 // Worktree represents a discovered git worktree
@@ -28,7 +55,8 @@ type Worktree struct {
 	Name         string    `json:"name"`
 	Path         string    `json:"path"`
 	Branch       string    `json:"branch"`
-	MainRepo     string    `json:"main_repo"` // Path to the main repo this worktree belongs to
+	MainRepo     string    `json:"main_repo"`      // Path to the main repo this worktree belongs to
+	Repo         string    `json:"repo,omitempty"` // Repository ID (see worktree.RepoID)
 	DiscoveredAt time.Time `json:"discovered_at"`
 	LastActivity time.Time `json:"last_activity"`
 
@@ -36,37 +64,54 @@ type Worktree struct {
 	HasServer bool `json:"has_server"` // We have a server registered for this
 	HasClaude bool `json:"has_claude"` // Claude Code is active (detected via socket/process)
 	HasGemini bool `json:"has_gemini"` // Gemini CLI is active
-	HasVSCode bool `json:"has_vscode"` // VS Code is open (detected via process)
+	HasVSCode bool `json:"has_vscode"` // VS Code, or the recorded editor, is open (detected via process)
 	GitDirty  bool `json:"git_dirty"`  // Has uncommitted changes
 
+	// DirtyFiles is how many files have uncommitted changes
+	DirtyFiles int `json:"dirty_files,omitempty"`
+
+	// Editor is the editor 'grove code' last opened this worktree in, so its
+	// process is checked alongside VS Code's
+	Editor string `json:"editor,omitempty"`
+
 	// Detailed agent info (populated when HasClaude is true)
 	Agent *AgentInfo `json:"agent,omitempty"`
 }
 
-// Discover finds all worktrees for a given repo
-func Discover(repoPath string) ([]*Worktree, error) {
+// Discover finds all worktrees for a given repo. Cancelling ctx stops the
+// git and process lookups it runs.
+func Discover(ctx context.Context, repoPath string) ([]*Worktree, error) {
 	absPath, err := filepath.Abs(repoPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get absolute path: %w", err)
 	}
+	return discover(ctx, absPath, nil)
+}
 
-	// Use git worktree list to find all worktrees
-	cmd := exec.Command("git", "worktree", "list", "--porcelain")
-	cmd.Dir = absPath
-	output, err := cmd.Output()
+// discover finds the worktrees of the repo at absPath, listing them through
+// the cache if there is one
+func discover(ctx context.Context, absPath string, cache *Cache) ([]*Worktree, error) {
+	list, err := cache.Worktrees(ctx, absPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list worktrees: %w", err)
 	}
+	worktrees := newWorktrees(list)
 
-	worktrees, err := parseWorktreeList(string(output))
-	if err != nil {
-		return nil, err
+	// Worktrees of one repository share its ID
+	if len(worktrees) > 0 {
+		repo := worktree.RepoID(absPath, worktrees[0].MainRepo)
+		for _, wt := range worktrees {
+			wt.Repo = repo
+		}
 	}
 
 	// Detect activity for each worktree
 	for _, wt := range worktrees {
-		if err := DetectActivity(wt); err != nil {
-			// Log error but continue
+		if err := DetectActivity(ctx, wt); err != nil {
+			// Stop when cancelled; carry on past other errors
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
 			continue
 		}
 	}
@@ -74,107 +119,86 @@ func Discover(repoPath string) ([]*Worktree, error) {
 	return worktrees, nil
 }
 
-// parseWorktreeList parses the output of `git worktree list --porcelain`
-func parseWorktreeList(output string) ([]*Worktree, error) {
+// newWorktrees names the worktrees `git worktree list` found, the first
+// being the main repo
+func newWorktrees(list []git.Worktree) []*Worktree {
 	var worktrees []*Worktree
-	var current *Worktree
-	var mainRepoPath string
-
-	lines := strings.Split(output, "\n")
-	for i, line := range lines {
-		line = strings.TrimSpace(line)
-
-		if strings.HasPrefix(line, "worktree ") {
-			if current != nil {
-				worktrees = append(worktrees, current)
-			}
-
-			path := strings.TrimPrefix(line, "worktree ")
-			current = &Worktree{
-				Path:         path,
-				DiscoveredAt: time.Now(),
-				LastActivity: time.Now(),
-			}
-
-			// First worktree is the main repo
-			if i == 0 || mainRepoPath == "" {
-				mainRepoPath = path
-			}
-			current.MainRepo = mainRepoPath
-
-		} else if strings.HasPrefix(line, "branch ") && current != nil {
-			branch := strings.TrimPrefix(line, "branch ")
-			branch = strings.TrimPrefix(branch, "refs/heads/")
-			current.Branch = branch
-			// For main repo (first worktree), use directory name instead of branch
-			// This makes standalone repos show as "myapp" instead of "main"
-			if current.Path == mainRepoPath {
-				current.Name = sanitizeBranchName(filepath.Base(current.Path))
-			} else {
-				current.Name = sanitizeBranchName(branch)
-			}
-
-		} else if strings.HasPrefix(line, "HEAD ") && current != nil && current.Branch == "" {
-			// Detached HEAD state
-			current.Branch = "HEAD"
-			current.Name = "detached-head"
-
-		} else if strings.HasPrefix(line, "detached") && current != nil {
-			// Mark as detached
-			if current.Branch == "" {
-				current.Branch = "HEAD"
-				current.Name = "detached-head"
-			}
+	for _, entry := range list {
+		wt := &Worktree{
+			Path:         entry.Path,
+			MainRepo:     list[0].Path,
+			DiscoveredAt: time.Now(),
+			LastActivity: time.Now(),
 		}
+		switch {
+		case entry.Detached:
+			wt.Branch = "HEAD"
+			wt.Name = "detached-head"
+		case entry.Branch != "" && entry.Path == wt.MainRepo:
+			// The main repo is named after its directory, not its branch,
+			// so standalone repos show as "myapp" instead of "main"
+			wt.Branch = entry.Branch
+			wt.Name = worktree.MainName(entry.Path)
+		case entry.Branch != "":
+			wt.Branch = entry.Branch
+			wt.Name = worktree.NameFor(wt.MainRepo, entry.Branch, entry.Path)
+		}
+		worktrees = append(worktrees, wt)
 	}
 
-	if current != nil {
-		worktrees = append(worktrees, current)
+	// Branches can sanitize to the same name (user/feature_x, user/feature-x)
+	names := make(map[string]bool)
+	for _, wt := range worktrees {
+		if names[wt.Name] {
+			wt.Name = worktree.CollisionName(wt.Name, wt.Path)
+		}
+		names[wt.Name] = true
 	}
 
-	return worktrees, nil
+	return worktrees
 }
 
 // DetectActivity checks for various activities in a worktree.
 // All checks run in parallel for performance.
-func DetectActivity(wt *Worktree) error {
+func DetectActivity(ctx context.Context, wt *Worktree) error {
 	var wg sync.WaitGroup
 	var agent *AgentInfo
-	var hasVSCode, gitDirty bool
+	var hasVSCode bool
+	var dirtyFiles int
 
 	// Run all detection checks in parallel
 	wg.Add(3)
 
 	go func() {
 		defer wg.Done()
-		agent = detectAgent(wt.Path)
+		agent = detectAgent(ctx, wt.Path)
 	}()
 
 	go func() {
 		defer wg.Done()
-		hasVSCode = detectVSCode(wt.Path)
+		hasVSCode = detectVSCode(ctx, wt.Path)
 	}()
 
 	go func() {
 		defer wg.Done()
-		gitDirty = detectGitDirty(wt.Path)
+		dirtyFiles = detectDirtyFiles(ctx, wt.Path)
 	}()
 
 	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	wt.Agent = agent
 	wt.HasClaude = agent != nil && agent.Type == "claude"
 	wt.HasGemini = agent != nil && agent.Type == "gemini"
 	wt.HasVSCode = hasVSCode
-	wt.GitDirty = gitDirty
+	wt.GitDirty = dirtyFiles > 0
+	wt.DirtyFiles = dirtyFiles
 
-	// If agent detected, check for active Tasuku task
+	// If agent detected, check for the task it's working on
 	if agent != nil {
-		taskID, taskDesc := GetActiveTask(wt.Path)
-		if taskID != "" {
-			agent.ActiveTask = taskID
-			agent.TaskSummary = taskDesc
-		}
+		SetAgentTask(agent, wt.Path)
 	}
 
 	// Update last activity time if any activity detected
@@ -186,14 +210,14 @@ func DetectActivity(wt *Worktree) error {
 }
 
 // detectAgent checks for AI agent activity and returns detailed info
-func detectAgent(path string) *AgentInfo {
+func detectAgent(ctx context.Context, path string) *AgentInfo {
 	// Check for Claude Code first
-	if agent := detectClaudeAgent(path); agent != nil {
+	if agent := detectClaudeAgent(ctx, path); agent != nil {
 		return agent
 	}
 
 	// Check for Gemini CLI
-	if agent := detectGeminiAgent(path); agent != nil {
+	if agent := detectGeminiAgent(ctx, path); agent != nil {
 		return agent
 	}
 
@@ -203,10 +227,9 @@ func detectAgent(path string) *AgentInfo {
 }
 
 // detectGeminiAgent checks for Gemini CLI activity
-func detectGeminiAgent(path string) *AgentInfo {
+func detectGeminiAgent(ctx context.Context, path string) *AgentInfo {
 	// Find Gemini CLI processes using pgrep (single process instead of ps|grep|awk pipeline)
-	cmd := exec.Command("pgrep", "-f", "gemini(-cli)?")
-	output, err := cmd.Output()
+	output, err := runner.Output(ctx, execx.Query("pgrep", "-f", "gemini(-cli)?"))
 	if err != nil {
 		return nil
 	}
@@ -218,14 +241,14 @@ func detectGeminiAgent(path string) *AgentInfo {
 
 	// Check each gemini process's working directory using lsof
 	for _, pidStr := range pids {
-		cwd := getProcessCwd(pidStr)
+		cwd := getProcessCwd(ctx, pidStr)
 		if cwd != "" && cwd == path {
 			pid := 0
 			_, _ = fmt.Sscanf(pidStr, "%d", &pid)
 
 			// Get process start time and command
-			startTime := getProcessStartTime(pidStr)
-			command := getProcessCommand(pidStr)
+			startTime := getProcessStartTime(ctx, pidStr)
+			command := getProcessCommand(ctx, pidStr)
 
 			return &AgentInfo{
 				Type:      "gemini",
@@ -241,10 +264,9 @@ func detectGeminiAgent(path string) *AgentInfo {
 }
 
 // detectClaudeAgent checks for Claude Code activity
-func detectClaudeAgent(path string) *AgentInfo {
+func detectClaudeAgent(ctx context.Context, path string) *AgentInfo {
 	// Find Claude Code processes using pgrep (single process instead of ps|grep|awk pipeline)
-	cmd := exec.Command("pgrep", "-f", "claude")
-	output, err := cmd.Output()
+	output, err := runner.Output(ctx, execx.Query("pgrep", "-f", "claude"))
 	if err != nil {
 		return nil
 	}
@@ -256,14 +278,14 @@ func detectClaudeAgent(path string) *AgentInfo {
 
 	// Check each claude process's working directory using lsof
 	for _, pidStr := range pids {
-		cwd := getProcessCwd(pidStr)
+		cwd := getProcessCwd(ctx, pidStr)
 		if cwd != "" && cwd == path {
 			pid := 0
 			_, _ = fmt.Sscanf(pidStr, "%d", &pid)
 
 			// Get process start time and command
-			startTime := getProcessStartTime(pidStr)
-			command := getProcessCommand(pidStr)
+			startTime := getProcessStartTime(ctx, pidStr)
+			command := getProcessCommand(ctx, pidStr)
 
 			return &AgentInfo{
 				Type:      "claude",
@@ -279,10 +301,9 @@ func detectClaudeAgent(path string) *AgentInfo {
 }
 
 // getProcessStartTime returns the start time of a process
-func getProcessStartTime(pid string) time.Time {
+func getProcessStartTime(ctx context.Context, pid string) time.Time {
 	// Use ps to get process start time
-	cmd := exec.Command("ps", "-p", pid, "-o", "lstart=")
-	output, err := cmd.Output()
+	output, err := runner.Output(ctx, execx.Query("ps", "-p", pid, "-o", "lstart="))
 	if err != nil {
 		return time.Time{}
 	}
@@ -301,9 +322,8 @@ func getProcessStartTime(pid string) time.Time {
 }
 
 // getProcessCommand returns the full command line of a process
-func getProcessCommand(pid string) string {
-	cmd := exec.Command("ps", "-p", pid, "-o", "command=")
-	output, err := cmd.Output()
+func getProcessCommand(ctx context.Context, pid string) string {
+	output, err := runner.Output(ctx, execx.Query("ps", "-p", pid, "-o", "command="))
 	if err != nil {
 		return ""
 	}
@@ -311,9 +331,8 @@ func getProcessCommand(pid string) string {
 }
 
 // getProcessCwd returns the current working directory of a process
-func getProcessCwd(pid string) string {
-	cmd := exec.Command("lsof", "-p", pid)
-	output, err := cmd.Output()
+func getProcessCwd(ctx context.Context, pid string) string {
+	output, err := runner.Output(ctx, execx.Query("lsof", "-p", pid))
 	if err != nil {
 		return ""
 	}
@@ -332,7 +351,7 @@ func getProcessCwd(pid string) string {
 }
 
 // detectVSCode checks for VS Code activity
-func detectVSCode(path string) bool {
+func detectVSCode(ctx context.Context, path string) bool {
 	// Check for .vscode-server directory (remote development)
 	vscodeServerPath := filepath.Join(path, ".vscode-server")
 	if info, err := os.Stat(vscodeServerPath); err == nil && info.IsDir() {
@@ -340,26 +359,23 @@ func detectVSCode(path string) bool {
 	}
 
 	// Check for code process with this path
-	return checkProcessWithPath("code", path)
+	return checkProcessWithPath(ctx, "code", path)
 }
 
-// detectGitDirty checks if the worktree has uncommitted changes
-func detectGitDirty(path string) bool {
-	cmd := exec.Command("git", "-C", path, "status", "--porcelain")
-	output, err := cmd.Output()
+// detectDirtyFiles returns how many files in the worktree have uncommitted
+// changes, from a recent check if there was one
+func detectDirtyFiles(ctx context.Context, path string) int {
+	count, err := cachedDirtyFiles(ctx, path)
 	if err != nil {
-		return false
+		return 0
 	}
-
-	// If output is not empty, there are changes
-	return len(strings.TrimSpace(string(output))) > 0
+	return count
 }
 
 // checkProcessWithPath checks if a process with the given name has the path as an argument
-func checkProcessWithPath(processName, path string) bool {
+func checkProcessWithPath(ctx context.Context, processName, path string) bool {
 	// Use ps to find processes
-	cmd := exec.Command("ps", "aux")
-	output, err := cmd.Output()
+	output, err := runner.Output(ctx, execx.Query("ps", "aux"))
 	if err != nil {
 		return false
 	}
@@ -374,123 +390,100 @@ func checkProcessWithPath(processName, path string) bool {
 	return false
 }
 
-// sanitizeBranchName converts a branch name to a URL-safe name
-func sanitizeBranchName(branch string) string {
-	// Replace / with -
-	name := strings.ReplaceAll(branch, "/", "-")
-
-	// Replace other special characters
-	name = strings.ReplaceAll(name, "_", "-")
-	name = strings.ReplaceAll(name, " ", "-")
+// FindAll discovers the git repositories under basePath and their
+// worktrees, listing repositories' worktrees in parallel
+func FindAll(ctx context.Context, basePath string, opts ScanOptions) ([]*Worktree, error) {
+	repos, err := FindRepos(ctx, basePath, opts)
+	if err != nil {
+		return nil, err
+	}
 
-	// Convert to lowercase
-	name = strings.ToLower(name)
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = defaultScanWorkers
+	}
 
-	// Remove any remaining special characters
-	var result strings.Builder
-	for _, r := range name {
-		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
-			result.WriteRune(r)
+	// Linked worktrees are listed by their main repository
+	results := make([][]*Worktree, len(repos))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, workers)
+	for i, repo := range repos {
+		if repo.Linked {
+			continue
 		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i], _ = discover(ctx, repo.Path, opts.Cache)
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
-	return result.String()
-}
-
-// FindAll discovers all git repositories in a directory tree
-func FindAll(basePath string, maxDepth int) ([]*Worktree, error) {
 	var allWorktrees []*Worktree
 	seen := make(map[string]bool)
-
-	var scan func(path string, depth int) error
-	scan = func(path string, depth int) error {
-		if maxDepth >= 0 && depth > maxDepth {
-			return nil
-		}
-
-		// Check if this is a git repository
-		gitPath := filepath.Join(path, ".git")
-		if info, err := os.Stat(gitPath); err == nil && info.IsDir() {
-			// Found a main git repo, discover its worktrees
-			worktrees, err := Discover(path)
-			if err == nil {
-				for _, wt := range worktrees {
-					if !seen[wt.Path] {
-						seen[wt.Path] = true
-						allWorktrees = append(allWorktrees, wt)
-					}
-				}
+	add := func(worktrees []*Worktree) {
+		for _, wt := range worktrees {
+			if !seen[wt.Path] {
+				seen[wt.Path] = true
+				allWorktrees = append(allWorktrees, wt)
 			}
-			// Don't descend into git repos
-			return nil
 		}
-
-		// Not a git repo, scan subdirectories
-		entries, err := os.ReadDir(path)
-		if err != nil {
-			return nil //nolint:nilerr // Intentionally skip unreadable directories and continue walk
-		}
-
-		for _, entry := range entries {
-			if !entry.IsDir() {
-				continue
-			}
-
-			// Skip hidden directories and common non-project dirs
-			name := entry.Name()
-			if strings.HasPrefix(name, ".") ||
-				name == "node_modules" ||
-				name == "vendor" ||
-				name == "__pycache__" ||
-				name == "venv" ||
-				name == ".venv" {
-				continue
-			}
-
-			if err := scan(filepath.Join(path, name), depth+1); err != nil {
-				return err
-			}
-		}
-
-		return nil
+	}
+	for _, worktrees := range results {
+		add(worktrees)
 	}
 
-	if err := scan(basePath, 0); err != nil {
-		return nil, err
+	// ...unless it's outside basePath
+	for _, repo := range repos {
+		if repo.Linked && !seen[repo.Path] {
+			worktrees, _ := discover(ctx, repo.Path, opts.Cache)
+			add(worktrees)
+		}
 	}
 
-	return allWorktrees, nil
+	return allWorktrees, ctx.Err()
 }
 
 // DetectAllAgents finds all active AI agents across all directories.
 // This is more efficient than calling DetectActivity for each worktree
 // because it finds all agent processes once and batches the lsof calls.
-func DetectAllAgents() map[string]*AgentInfo {
+func DetectAllAgents(ctx context.Context) map[string]*AgentInfo {
 	agents := make(map[string]*AgentInfo)
 
 	// Find all Claude and Gemini processes at once
-	claudeAgents := detectAllClaudeAgents()
+	claudeAgents := detectAllClaudeAgents(ctx)
 	for path, agent := range claudeAgents {
 		agents[path] = agent
 	}
 
-	geminiAgents := detectAllGeminiAgents()
+	geminiAgents := detectAllGeminiAgents(ctx)
 	for path, agent := range geminiAgents {
 		if _, exists := agents[path]; !exists {
 			agents[path] = agent
 		}
 	}
 
+	// Agents grove launched, which may not be found by process name
+	for path, agent := range LaunchedAgents() {
+		if _, exists := agents[path]; !exists {
+			agents[path] = agent
+		}
+	}
+
 	return agents
 }
 
 // detectAllClaudeAgents finds all Claude Code processes and returns a map of path -> AgentInfo
-func detectAllClaudeAgents() map[string]*AgentInfo {
+func detectAllClaudeAgents(ctx context.Context) map[string]*AgentInfo {
 	agents := make(map[string]*AgentInfo)
 
 	// Find Claude Code processes using pgrep (single process instead of ps|grep|awk pipeline)
-	cmd := exec.Command("pgrep", "-f", "claude")
-	output, err := cmd.Output()
+	output, err := runner.Output(ctx, execx.Query("pgrep", "-f", "claude"))
 	if err != nil {
 		return agents
 	}
@@ -503,11 +496,10 @@ func detectAllClaudeAgents() map[string]*AgentInfo {
 	// Get CWDs for all PIDs at once using a single lsof call
 	// lsof -d cwd -a -p PID1,PID2,... is more efficient
 	pidList := strings.Join(pids, ",")
-	lsofCmd := exec.Command("lsof", "-d", "cwd", "-a", "-p", pidList)
-	lsofOutput, err := lsofCmd.Output()
+	lsofOutput, err := runner.Output(ctx, execx.Query("lsof", "-d", "cwd", "-a", "-p", pidList))
 	if err != nil {
 		// Fall back to individual lookups if batch fails
-		return detectAgentsFallback(pids, "claude")
+		return detectAgentsFallback(ctx, pids, "claude")
 	}
 
 	// Parse lsof output to extract PID -> CWD mapping
@@ -519,8 +511,8 @@ func detectAllClaudeAgents() map[string]*AgentInfo {
 			continue // Already have an agent for this path
 		}
 
-		startTime := getProcessStartTime(pid)
-		command := getProcessCommand(pid)
+		startTime := getProcessStartTime(ctx, pid)
+		command := getProcessCommand(ctx, pid)
 		pidInt := 0
 		_, _ = fmt.Sscanf(pid, "%d", &pidInt)
 
@@ -537,12 +529,11 @@ func detectAllClaudeAgents() map[string]*AgentInfo {
 }
 
 // detectAllGeminiAgents finds all Gemini CLI processes and returns a map of path -> AgentInfo
-func detectAllGeminiAgents() map[string]*AgentInfo {
+func detectAllGeminiAgents(ctx context.Context) map[string]*AgentInfo {
 	agents := make(map[string]*AgentInfo)
 
 	// Find Gemini CLI processes using pgrep (single process instead of ps|grep|awk pipeline)
-	cmd := exec.Command("pgrep", "-f", "gemini(-cli)?")
-	output, err := cmd.Output()
+	output, err := runner.Output(ctx, execx.Query("pgrep", "-f", "gemini(-cli)?"))
 	if err != nil {
 		return agents
 	}
@@ -554,10 +545,9 @@ func detectAllGeminiAgents() map[string]*AgentInfo {
 
 	// Get CWDs for all PIDs at once
 	pidList := strings.Join(pids, ",")
-	lsofCmd := exec.Command("lsof", "-d", "cwd", "-a", "-p", pidList)
-	lsofOutput, err := lsofCmd.Output()
+	lsofOutput, err := runner.Output(ctx, execx.Query("lsof", "-d", "cwd", "-a", "-p", pidList))
 	if err != nil {
-		return detectAgentsFallback(pids, "gemini")
+		return detectAgentsFallback(ctx, pids, "gemini")
 	}
 
 	// Parse lsof output
@@ -568,8 +558,8 @@ func detectAllGeminiAgents() map[string]*AgentInfo {
 			continue
 		}
 
-		startTime := getProcessStartTime(pid)
-		command := getProcessCommand(pid)
+		startTime := getProcessStartTime(ctx, pid)
+		command := getProcessCommand(ctx, pid)
 		pidInt := 0
 		_, _ = fmt.Sscanf(pid, "%d", &pidInt)
 
@@ -609,11 +599,11 @@ func parseLsofOutput(output string) map[string]string {
 }
 
 // detectAgentsFallback is a slower fallback that checks each PID individually
-func detectAgentsFallback(pids []string, agentType string) map[string]*AgentInfo {
+func detectAgentsFallback(ctx context.Context, pids []string, agentType string) map[string]*AgentInfo {
 	agents := make(map[string]*AgentInfo)
 
 	for _, pid := range pids {
-		cwd := getProcessCwd(pid)
+		cwd := getProcessCwd(ctx, pid)
 		if cwd == "" {
 			continue
 		}
@@ -622,8 +612,8 @@ func detectAgentsFallback(pids []string, agentType string) map[string]*AgentInfo
 			continue
 		}
 
-		startTime := getProcessStartTime(pid)
-		command := getProcessCommand(pid)
+		startTime := getProcessStartTime(ctx, pid)
+		command := getProcessCommand(ctx, pid)
 		pidInt := 0
 		_, _ = fmt.Sscanf(pid, "%d", &pidInt)
 
@@ -641,81 +631,96 @@ func detectAgentsFallback(pids []string, agentType string) map[string]*AgentInfo
 
 // DetectAllVSCode finds all VS Code processes and returns a set of paths where VS Code is active.
 // This is more efficient than calling detectVSCode per-worktree since it runs ps aux once.
-func DetectAllVSCode() map[string]bool {
-	vscodePaths := make(map[string]bool)
+func DetectAllVSCode(ctx context.Context) map[string]bool {
+	return DetectAllEditors(ctx, []string{"code"})["code"]
+}
+
+// DetectAllEditors finds processes whose command line contains one of the
+// given process names (case-insensitively) and returns, per name, the set
+// of directory paths they were passed. It runs ps aux once.
+func DetectAllEditors(ctx context.Context, processes []string) map[string]map[string]bool {
+	editorPaths := make(map[string]map[string]bool, len(processes))
+	for _, p := range processes {
+		editorPaths[p] = make(map[string]bool)
+	}
 
-	// Run ps aux once and look for VS Code processes with path arguments
-	cmd := exec.Command("ps", "aux")
-	output, err := cmd.Output()
+	output, err := runner.Output(ctx, execx.Query("ps", "aux"))
 	if err != nil {
-		return vscodePaths
+		return editorPaths
 	}
 
 	lines := strings.Split(string(output), "\n")
 	for _, line := range lines {
-		if !strings.Contains(line, "code") && !strings.Contains(line, "Code") {
-			continue
-		}
-
-		// Extract paths from the command line (look for common path patterns)
-		fields := strings.Fields(line)
-		for _, field := range fields {
-			// Skip if it's not a path
-			if !strings.HasPrefix(field, "/") {
+		lower := strings.ToLower(line)
+		for _, p := range processes {
+			if !strings.Contains(lower, p) {
 				continue
 			}
-			// Check if it looks like a project directory (exists and is a directory)
-			if info, err := os.Stat(field); err == nil && info.IsDir() {
-				vscodePaths[field] = true
+
+			// Extract paths from the command line (look for common path patterns)
+			for _, field := range strings.Fields(line) {
+				// Skip if it's not a path
+				if !strings.HasPrefix(field, "/") {
+					continue
+				}
+				// Check if it looks like a project directory (exists and is a directory)
+				if info, err := os.Stat(field); err == nil && info.IsDir() {
+					editorPaths[p][field] = true
+				}
 			}
 		}
 	}
 
-	return vscodePaths
+	return editorPaths
+}
+
+// openInEditor reports whether path, or a parent directory, is in paths
+func openInEditor(paths map[string]bool, path string) bool {
+	if paths[path] {
+		return true
+	}
+	for p := range paths {
+		if strings.HasPrefix(path, p+"/") {
+			return true
+		}
+	}
+	return false
 }
 
 // DetectActivitiesBatch efficiently detects activities for multiple worktrees.
 // It batches the expensive operations (lsof for agents, ps for VS Code) and
-// parallelizes git status checks.
-func DetectActivitiesBatch(worktrees []*Worktree) {
+// parallelizes git status checks. Cancelling ctx stops the checks and
+// leaves the worktrees unchanged.
+func DetectActivitiesBatch(ctx context.Context, worktrees []*Worktree) error {
 	if len(worktrees) == 0 {
-		return
+		return nil
 	}
 
 	// Batch 1: Get all agents at once (single lsof call)
-	agents := DetectAllAgents()
+	agents := DetectAllAgents(ctx)
 
-	// Batch 2: Get all VS Code paths at once (single ps call)
-	vscodePaths := DetectAllVSCode()
+	// Batch 2: Get all VS Code and recorded editor paths at once (single ps call)
+	processes := []string{"code"}
+	for _, wt := range worktrees {
+		if e, ok := editor.Find(wt.Editor); ok && !slices.Contains(processes, e.Process) {
+			processes = append(processes, e.Process)
+		}
+	}
+	editorPaths := DetectAllEditors(ctx, processes)
 
 	// Parallel: Run git status for each worktree
 	var wg sync.WaitGroup
-	results := make(chan struct {
-		idx      int
-		gitDirty bool
-	}, len(worktrees))
-
+	dirtyFiles := make([]int, len(worktrees))
 	for i, wt := range worktrees {
 		wg.Add(1)
-		go func(idx int, path string) {
+		go func() {
 			defer wg.Done()
-			results <- struct {
-				idx      int
-				gitDirty bool
-			}{idx, detectGitDirty(path)}
-		}(i, wt.Path)
+			dirtyFiles[i] = detectDirtyFiles(ctx, wt.Path)
+		}()
 	}
-
-	// Close results channel when all goroutines complete
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
-
-	// Collect git dirty results
-	gitDirty := make([]bool, len(worktrees))
-	for result := range results {
-		gitDirty[result.idx] = result.gitDirty
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
 	// Apply all results to worktrees
@@ -726,36 +731,28 @@ func DetectActivitiesBatch(worktrees []*Worktree) {
 			wt.HasClaude = agent.Type == "claude"
 			wt.HasGemini = agent.Type == "gemini"
 
-			// Check for active Tasuku task
-			taskID, taskDesc := GetActiveTask(wt.Path)
-			if taskID != "" {
-				agent.ActiveTask = taskID
-				agent.TaskSummary = taskDesc
-			}
+			// Check for the task it's working on
+			SetAgentTask(agent, wt.Path)
 		} else {
 			wt.Agent = nil
 			wt.HasClaude = false
 			wt.HasGemini = false
 		}
 
-		// VS Code detection (check for exact match or parent directory)
-		wt.HasVSCode = vscodePaths[wt.Path]
-		if !wt.HasVSCode {
-			// Check if VS Code is open on a parent directory
-			for vsPath := range vscodePaths {
-				if strings.HasPrefix(wt.Path, vsPath+"/") {
-					wt.HasVSCode = true
-					break
-				}
-			}
+		// Editor detection (check for exact match or parent directory)
+		wt.HasVSCode = openInEditor(editorPaths["code"], wt.Path)
+		if e, ok := editor.Find(wt.Editor); ok && !wt.HasVSCode {
+			wt.HasVSCode = openInEditor(editorPaths[e.Process], wt.Path)
 		}
 
 		// Git dirty
-		wt.GitDirty = gitDirty[i]
+		wt.GitDirty = dirtyFiles[i] > 0
+		wt.DirtyFiles = dirtyFiles[i]
 
 		// Update last activity
 		if wt.Agent != nil || wt.HasVSCode || wt.GitDirty {
 			wt.LastActivity = time.Now()
 		}
 	}
+	return nil
 }
@@ -1,34 +1,17 @@
 package discovery
 
 import (
+	"context"
+	"errors"
 	"testing"
-)
-
-func TestSanitizeBranchName(t *testing.T) {
-	tests := []struct {
-		input    string
-		expected string
-	}{
-		{"feature/auth", "feature-auth"},
-		{"feature/user-management", "feature-user-management"},
-		{"FEATURE/AUTH", "feature-auth"},
-		{"fix_bug_123", "fix-bug-123"},
-		{"release/v1.0.0", "release-v100"},
-		{"main", "main"},
-		{"feature/test space", "feature-test-space"},
-	}
+	"time"
 
-	for _, tt := range tests {
-		t.Run(tt.input, func(t *testing.T) {
-			result := sanitizeBranchName(tt.input)
-			if result != tt.expected {
-				t.Errorf("sanitizeBranchName(%q) = %q; want %q", tt.input, result, tt.expected)
-			}
-		})
-	}
-}
+	"github.com/iheanyi/grove/internal/execx"
+	"github.com/iheanyi/grove/internal/git"
+	"github.com/iheanyi/grove/internal/worktree"
+)
 
-func TestParseWorktreeList(t *testing.T) {
+func TestNewWorktrees(t *testing.T) {
 	// Test parsing git worktree list --porcelain output
 	output := `worktree /Users/test/myproject
 HEAD abc123def456
@@ -43,13 +26,10 @@ HEAD 789abc123def
 branch refs/heads/bugfix/123
 `
 
-	worktrees, err := parseWorktreeList(output)
-	if err != nil {
-		t.Fatalf("parseWorktreeList() error = %v", err)
-	}
+	worktrees := newWorktrees(git.ParseWorktrees(output))
 
 	if len(worktrees) != 3 {
-		t.Errorf("parseWorktreeList() returned %d worktrees; want 3", len(worktrees))
+		t.Errorf("newWorktrees() returned %d worktrees; want 3", len(worktrees))
 	}
 
 	// Check first worktree (main repo)
@@ -88,19 +68,67 @@ branch refs/heads/bugfix/123
 	}
 }
 
+func TestNewWorktrees_Names(t *testing.T) {
+	// Linked worktrees are named after their branch with worktree.Sanitize,
+	// which turns dots into hyphens and drops spaces
+	tests := []struct {
+		branch string
+		want   string
+	}{
+		{"feature/auth", "feature-auth"},
+		{"feature/user-management", "feature-user-management"},
+		{"FEATURE/AUTH", "feature-auth"},
+		{"fix_bug_123", "fix-bug-123"},
+		{"release/v1.0.0", "release-v1-0-0"},
+		{"feature/test space", "feature-testspace"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.branch, func(t *testing.T) {
+			worktrees := newWorktrees([]git.Worktree{
+				{Path: "/Users/test/myproject", Branch: "main"},
+				{Path: "/Users/test/myproject-wt", Branch: tt.branch},
+			})
+			if got := worktrees[1].Name; got != tt.want {
+				t.Errorf("name for branch %q = %q; want %q", tt.branch, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewWorktrees_CollidingNames(t *testing.T) {
+	output := `worktree /Users/test/myproject
+HEAD abc123def456
+branch refs/heads/main
+
+worktree /Users/test/myproject-fx
+HEAD def456abc789
+branch refs/heads/user/feature_x
+
+worktree /Users/test/myproject-fx2
+HEAD 789abc123def
+branch refs/heads/user/feature-x
+`
+
+	worktrees := newWorktrees(git.ParseWorktrees(output))
+	if worktrees[1].Name != "user-feature-x" {
+		t.Errorf("worktrees[1].Name = %q; want %q", worktrees[1].Name, "user-feature-x")
+	}
+	if want := worktree.CollisionName("user-feature-x", "/Users/test/myproject-fx2"); worktrees[2].Name != want {
+		t.Errorf("worktrees[2].Name = %q; want %q", worktrees[2].Name, want)
+	}
+}
+
 func TestDetachedHead(t *testing.T) {
 	output := `worktree /Users/test/myproject
 HEAD abc123def456
 detached
 `
 
-	worktrees, err := parseWorktreeList(output)
-	if err != nil {
-		t.Fatalf("parseWorktreeList() error = %v", err)
-	}
+	worktrees := newWorktrees(git.ParseWorktrees(output))
 
 	if len(worktrees) != 1 {
-		t.Errorf("parseWorktreeList() returned %d worktrees; want 1", len(worktrees))
+		t.Errorf("newWorktrees() returned %d worktrees; want 1", len(worktrees))
 	}
 
 	if worktrees[0].Branch != "HEAD" {
@@ -110,3 +138,58 @@ detached
 		t.Errorf("worktrees[0].Name = %q; want %q", worktrees[0].Name, "detached-head")
 	}
 }
+
+func TestOpenInEditor(t *testing.T) {
+	paths := map[string]bool{"/code/myapp": true}
+
+	tests := map[string]bool{
+		"/code/myapp":          true,
+		"/code/myapp/worktree": true,
+		"/code/myapp-feature":  false,
+		"/other":               false,
+	}
+	for path, want := range tests {
+		if got := openInEditor(paths, path); got != want {
+			t.Errorf("openInEditor(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestDetectAllClaudeAgents(t *testing.T) {
+	fake := execx.NewFake()
+	orig := runner
+	runner = fake
+	defer func() { runner = orig }()
+
+	fake.On("pgrep -f claude", "101\n102\n103\n", nil)
+	fake.On("lsof -d cwd -a -p 101,102,103", `COMMAND PID    USER   FD   TYPE DEVICE SIZE/OFF     NODE NAME
+node     101 iheanyi  cwd    DIR   1,16      640 12345678 /Users/iheanyi/src/app
+node     102 iheanyi  cwd    DIR   1,16      704 12345690 /Users/iheanyi/src/app-feature
+`, nil)
+	fake.On("ps -p 101 -o lstart=", "Tue Mar  4 09:15:02 2025\n", nil)
+	fake.On("ps -p 101 -o command=", "claude --continue\n", nil)
+	fake.On("ps -p 102 -o lstart=", "", errors.New("exit status 1"))
+	fake.On("ps -p 102 -o command=", "claude\n", nil)
+
+	agents := detectAllClaudeAgents(context.Background())
+	if len(agents) != 2 {
+		t.Fatalf("agents = %v, want 2", agents)
+	}
+	app := agents["/Users/iheanyi/src/app"]
+	if app == nil || app.PID != 101 || app.Command != "claude --continue" || app.Type != "claude" {
+		t.Errorf("app agent = %+v", app)
+	}
+	if want := time.Date(2025, 3, 4, 9, 15, 2, 0, time.UTC); app != nil && !app.StartTime.Equal(want) {
+		t.Errorf("StartTime = %v, want %v", app.StartTime, want)
+	}
+	if feature := agents["/Users/iheanyi/src/app-feature"]; feature == nil || feature.PID != 102 || !feature.StartTime.IsZero() {
+		t.Errorf("feature agent = %+v", feature)
+	}
+
+	// Without pgrep there are no agents
+	fake = execx.NewFake()
+	runner = fake
+	if agents := detectAllClaudeAgents(context.Background()); len(agents) != 0 {
+		t.Errorf("agents without pgrep = %v", agents)
+	}
+}
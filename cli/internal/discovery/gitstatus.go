@@ -0,0 +1,50 @@
+package discovery
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// gitStatusTTL is how long a worktree's status is reused. The TUI and
+// dashboard refresh every few seconds; a short TTL keeps them from running
+// git in every worktree on every tick.
+const gitStatusTTL = 5 * time.Second
+
+type gitStatusEntry struct {
+	count   int
+	err     error
+	checked time.Time
+}
+
+var gitStatusCache = struct {
+	mu      sync.Mutex
+	entries map[string]gitStatusEntry
+}{entries: make(map[string]gitStatusEntry)}
+
+// DirtyFiles returns how many files in a worktree have uncommitted changes,
+// untracked files included
+func DirtyFiles(ctx context.Context, path string) (int, error) {
+	return gitOps().DirtyFiles(ctx, path)
+}
+
+// cachedDirtyFiles is DirtyFiles, reusing results younger than gitStatusTTL
+func cachedDirtyFiles(ctx context.Context, path string) (int, error) {
+	gitStatusCache.mu.Lock()
+	entry, ok := gitStatusCache.entries[path]
+	gitStatusCache.mu.Unlock()
+	if ok && time.Since(entry.checked) < gitStatusTTL {
+		return entry.count, entry.err
+	}
+
+	count, err := DirtyFiles(ctx, path)
+	if ctx.Err() != nil {
+		// A cancelled check says nothing about the worktree
+		return count, err
+	}
+
+	gitStatusCache.mu.Lock()
+	gitStatusCache.entries[path] = gitStatusEntry{count: count, err: err, checked: time.Now()}
+	gitStatusCache.mu.Unlock()
+	return count, err
+}
@@ -0,0 +1,41 @@
+package discovery
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iheanyi/grove/internal/execx"
+	"github.com/iheanyi/grove/internal/git"
+)
+
+func TestCachedDirtyFiles(t *testing.T) {
+	fake := execx.NewFake()
+	orig := runner
+	runner = fake
+	defer func() { runner = orig }()
+
+	path := t.TempDir()
+	status := "git -C " + path + " status --porcelain -z"
+	fake.On(status, " M a.go\x00 M b.go\x00", nil)
+
+	for range 3 {
+		if n := detectDirtyFiles(context.Background(), path); n != 2 {
+			t.Fatalf("detectDirtyFiles() = %d, want 2", n)
+		}
+	}
+	if calls := fake.Calls(); len(calls) != 1 || calls[0].Timeout != git.StatusTimeout {
+		t.Errorf("git status ran %d times (timeout %v), want once with a timeout", len(calls), calls[0].Timeout)
+	}
+
+	// An expired entry runs git again
+	gitStatusCache.mu.Lock()
+	entry := gitStatusCache.entries[path]
+	entry.checked = time.Now().Add(-gitStatusTTL)
+	gitStatusCache.entries[path] = entry
+	gitStatusCache.mu.Unlock()
+	fake.On(status, "", nil)
+	if n := detectDirtyFiles(context.Background(), path); n != 0 {
+		t.Errorf("detectDirtyFiles() after the TTL = %d, want 0", n)
+	}
+}
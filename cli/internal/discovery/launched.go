@@ -0,0 +1,104 @@
+package discovery
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+var (
+	launchedMu   sync.Mutex
+	launchedPath string
+)
+
+// SetLaunchedAgentsPath sets the file recording the agent sessions started
+// by 'grove agent launch'. Until it's set, launched agents aren't recorded.
+func SetLaunchedAgentsPath(path string) {
+	launchedMu.Lock()
+	defer launchedMu.Unlock()
+	launchedPath = path
+}
+
+// RecordLaunchedAgent records an agent session grove started, so it's
+// reported by DetectAllAgents right away, even for agents process
+// detection doesn't know (like aider). Sessions whose process has exited
+// are dropped.
+func RecordLaunchedAgent(agent *AgentInfo) error {
+	launchedMu.Lock()
+	defer launchedMu.Unlock()
+	if launchedPath == "" {
+		return nil
+	}
+
+	sessions := append(readLaunchedAgents(), agent)
+	data, err := json.MarshalIndent(sessions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(launchedPath), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(launchedPath, data, 0644)
+}
+
+// LaunchedAgents returns the running agent sessions grove started, by
+// working directory
+func LaunchedAgents() map[string]*AgentInfo {
+	launchedMu.Lock()
+	defer launchedMu.Unlock()
+
+	agents := make(map[string]*AgentInfo)
+	for _, agent := range readLaunchedAgents() {
+		agents[agent.Path] = agent
+	}
+	return agents
+}
+
+// readLaunchedAgents reads the recorded sessions that are still running.
+// The caller holds launchedMu.
+func readLaunchedAgents() []*AgentInfo {
+	if launchedPath == "" {
+		return nil
+	}
+	data, err := os.ReadFile(launchedPath)
+	if err != nil {
+		return nil
+	}
+
+	var sessions []*AgentInfo
+	if err := json.Unmarshal(data, &sessions); err != nil {
+		return nil
+	}
+
+	running := sessions[:0]
+	for _, agent := range sessions {
+		if agent != nil && agent.PID > 0 && processRunning(agent.PID) {
+			running = append(running, agent)
+		}
+	}
+	return running
+}
+
+// launchedAgentsIn returns the running sessions grove started in path or a
+// directory below it
+func launchedAgentsIn(path string) []*AgentInfo {
+	var agents []*AgentInfo
+	for cwd, agent := range LaunchedAgents() {
+		if cwd == path || strings.HasPrefix(cwd, path+string(filepath.Separator)) {
+			agents = append(agents, agent)
+		}
+	}
+	return agents
+}
+
+// processRunning reports whether the process with the given PID exists
+func processRunning(pid int) bool {
+	process, err := runner.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return process.Signal(syscall.Signal(0)) == nil
+}
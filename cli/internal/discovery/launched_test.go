@@ -0,0 +1,38 @@
+package discovery
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/iheanyi/grove/internal/execx"
+)
+
+func TestLaunchedAgents(t *testing.T) {
+	fake := execx.NewFake()
+	orig := runner
+	runner = fake
+	defer func() { runner = orig }()
+	fake.AddProcess(4242)
+
+	SetLaunchedAgentsPath(filepath.Join(t.TempDir(), "agent-sessions.json"))
+	defer SetLaunchedAgentsPath("")
+
+	// A session whose process has exited is dropped
+	if err := RecordLaunchedAgent(&AgentInfo{Type: "aider", PID: 4343, Path: "/src/gone"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RecordLaunchedAgent(&AgentInfo{Type: "aider", PID: 4242, Path: "/src/app-feature/web"}); err != nil {
+		t.Fatal(err)
+	}
+
+	agents := LaunchedAgents()
+	if len(agents) != 1 || agents["/src/app-feature/web"] == nil {
+		t.Fatalf("LaunchedAgents() = %+v, want the running session", agents)
+	}
+	if got := launchedAgentsIn("/src/app-feature"); len(got) != 1 {
+		t.Errorf("launchedAgentsIn() = %+v, want 1 session", got)
+	}
+	if got := launchedAgentsIn("/src/app"); len(got) != 0 {
+		t.Errorf("launchedAgentsIn() of a sibling path = %+v, want none", got)
+	}
+}
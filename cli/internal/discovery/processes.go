@@ -0,0 +1,140 @@
+package discovery
+
+import (
+	"context"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/iheanyi/grove/internal/execx"
+)
+
+// agentPatterns are the pgrep patterns of agent processes, by agent type
+var agentPatterns = []struct {
+	Type    string
+	Pattern string
+}{
+	{"claude", "claude"},
+	{"gemini", "gemini(-cli)?"},
+}
+
+// AgentsIn returns every agent process working in path or a directory
+// below it, sorted by PID. Unlike DetectAllAgents, which keeps one agent
+// per directory, it finds them all, so they can be stopped with the
+// worktree. Grove's own ancestors are left out, so an agent running grove
+// in the worktree isn't stopped by it.
+func AgentsIn(ctx context.Context, path string) []*AgentInfo {
+	own := ancestors(ctx)
+	var agents []*AgentInfo
+	for _, p := range agentPatterns {
+		output, err := runner.Output(ctx, execx.Query("pgrep", "-f", p.Pattern))
+		if err != nil {
+			continue
+		}
+		pids := strings.Fields(string(output))
+		if len(pids) == 0 {
+			continue
+		}
+
+		lsofOutput, err := runner.Output(ctx, execx.Query("lsof", "-d", "cwd", "-a", "-p", strings.Join(pids, ",")))
+		if err != nil {
+			continue
+		}
+		for pid, cwd := range parseLsofOutput(string(lsofOutput)) {
+			if !openInEditor(map[string]bool{path: true}, cwd) {
+				continue
+			}
+			pidInt, _ := strconv.Atoi(pid)
+			if own[pidInt] {
+				continue
+			}
+			agents = append(agents, &AgentInfo{
+				Type:    p.Type,
+				PID:     pidInt,
+				Path:    cwd,
+				Command: getProcessCommand(ctx, pid),
+			})
+		}
+	}
+
+	for _, launched := range launchedAgentsIn(path) {
+		if !own[launched.PID] && !slices.ContainsFunc(agents, func(a *AgentInfo) bool { return a.PID == launched.PID }) {
+			agents = append(agents, launched)
+		}
+	}
+
+	sort.Slice(agents, func(i, j int) bool { return agents[i].PID < agents[j].PID })
+	return agents
+}
+
+// EditorProcess is an editor process with a worktree open
+type EditorProcess struct {
+	Process string // the matched process name, e.g. "code"
+	PID     int
+	Command string
+}
+
+// EditorsIn returns the processes that were passed path, or a directory
+// below it, by an executable containing one of the given process names
+// (case-insensitively), sorted by PID. Grove's own process and its
+// ancestors, like an editor whose terminal runs grove, are left out.
+func EditorsIn(ctx context.Context, path string, processes []string) []EditorProcess {
+	own := ancestors(ctx)
+	output, err := runner.Output(ctx, execx.Query("ps", "-eo", "pid=,command="))
+	if err != nil {
+		return nil
+	}
+
+	// The path as an argument of its own, or a directory below it
+	pathArg := regexp.MustCompile(`(^|\s)` + regexp.QuoteMeta(path) + `(/|\s|$)`)
+
+	var editors []EditorProcess
+	for _, line := range strings.Split(string(output), "\n") {
+		pidStr, command, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok {
+			continue
+		}
+		pid, err := strconv.Atoi(pidStr)
+		if err != nil || own[pid] {
+			continue
+		}
+		command = strings.TrimSpace(command)
+		loc := pathArg.FindStringIndex(command)
+		if loc == nil {
+			continue
+		}
+
+		// Only the executable, before the path, names the editor; a server
+		// run from ~/code/app isn't the code editor
+		executable := strings.ToLower(command[:loc[0]])
+		for _, p := range processes {
+			if strings.Contains(executable, p) {
+				editors = append(editors, EditorProcess{Process: p, PID: pid, Command: command})
+				break
+			}
+		}
+	}
+
+	sort.Slice(editors, func(i, j int) bool { return editors[i].PID < editors[j].PID })
+	return editors
+}
+
+// ancestors returns grove's PID and those of the processes it descends
+// from, e.g. the shell and agent that ran it
+func ancestors(ctx context.Context) map[int]bool {
+	pids := make(map[int]bool)
+	for pid := os.Getpid(); pid > 1 && !pids[pid]; {
+		pids[pid] = true
+		output, err := runner.Output(ctx, execx.Query("ps", "-o", "ppid=", "-p", strconv.Itoa(pid)))
+		if err != nil {
+			break
+		}
+		if pid, err = strconv.Atoi(strings.TrimSpace(string(output))); err != nil {
+			break
+		}
+	}
+	return pids
+}
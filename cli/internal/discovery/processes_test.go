@@ -0,0 +1,77 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/iheanyi/grove/internal/execx"
+)
+
+func TestAgentsIn(t *testing.T) {
+	fake := execx.NewFake()
+	orig := runner
+	runner = fake
+	defer func() { runner = orig }()
+
+	fake.On("pgrep -f claude", "101\n102\n103\n", nil)
+	fake.On("lsof -d cwd -a -p 101,102,103", `COMMAND PID    USER   FD   TYPE DEVICE SIZE/OFF     NODE NAME
+node     101 iheanyi  cwd    DIR   1,16      640 12345678 /src/app-feature
+node     102 iheanyi  cwd    DIR   1,16      704 12345690 /src/app-feature/web
+node     103 iheanyi  cwd    DIR   1,16      704 12345691 /src/app-feature-2
+`, nil)
+	fake.On("ps -p 101 -o command=", "claude\n", nil)
+	fake.On("ps -p 102 -o command=", "claude --continue\n", nil)
+
+	agents := AgentsIn(context.Background(), "/src/app-feature")
+	if len(agents) != 2 || agents[0].PID != 101 || agents[1].PID != 102 || agents[1].Command != "claude --continue" {
+		t.Errorf("AgentsIn() = %+v, want 101 and 102", agents)
+	}
+}
+
+func TestAgentsIn_SkipsAncestors(t *testing.T) {
+	fake := execx.NewFake()
+	orig := runner
+	runner = fake
+	defer func() { runner = orig }()
+
+	// grove was run by a shell the agent in the worktree started
+	fake.On(fmt.Sprintf("ps -o ppid= -p %d", os.Getpid()), "300\n", nil)
+	fake.On("ps -o ppid= -p 300", "101\n", nil)
+	fake.On("ps -o ppid= -p 101", "1\n", nil)
+	fake.On("pgrep -f claude", "101\n102\n", nil)
+	fake.On("lsof -d cwd -a -p 101,102", `COMMAND PID    USER   FD   TYPE DEVICE SIZE/OFF     NODE NAME
+node     101 iheanyi  cwd    DIR   1,16      640 12345678 /src/app-feature
+node     102 iheanyi  cwd    DIR   1,16      640 12345678 /src/app-feature
+`, nil)
+
+	agents := AgentsIn(context.Background(), "/src/app-feature")
+	if len(agents) != 1 || agents[0].PID != 102 {
+		t.Errorf("AgentsIn() = %+v, want only 102", agents)
+	}
+}
+
+func TestEditorsIn(t *testing.T) {
+	fake := execx.NewFake()
+	orig := runner
+	runner = fake
+	defer func() { runner = orig }()
+
+	fake.On("ps -eo pid=,command=", `  201 /usr/local/bin/zed /src/app-feature
+  202 nvim /src/app-feature-2
+  203 node /Users/me/code/app/server.js
+  204 /Applications/Cursor.app/Contents/MacOS/Cursor /src/app-feature/web/index.ts
+  205 /opt/homebrew/bin/zed /src/app
+`, nil)
+
+	editors := EditorsIn(context.Background(), "/src/app-feature", []string{"zed", "cursor", "code"})
+	if len(editors) != 2 || editors[0].PID != 201 || editors[0].Process != "zed" || editors[1].PID != 204 || editors[1].Process != "cursor" {
+		t.Errorf("EditorsIn() = %+v, want zed 201 and cursor 204", editors)
+	}
+
+	// A path containing an editor's name isn't an editor
+	if editors := EditorsIn(context.Background(), "/Users/me/code/app", []string{"code"}); len(editors) != 0 {
+		t.Errorf("EditorsIn() = %+v, want none", editors)
+	}
+}
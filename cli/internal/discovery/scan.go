@@ -0,0 +1,230 @@
+package discovery
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// defaultScanWorkers is how many directories are read at once
+const defaultScanWorkers = 16
+
+// DefaultIgnore are the directories scans always skip, unless a negated
+// pattern ("!vendor") in ScanOptions.Ignore brings them back
+var DefaultIgnore = []string{".*", "node_modules", "vendor", "__pycache__", "venv"}
+
+// ScanOptions configure FindRepos and FindAll
+type ScanOptions struct {
+	// MaxDepth is how many directory levels below the base to scan;
+	// negative means unlimited
+	MaxDepth int
+
+	// Ignore are gitignore-style patterns of directories to skip, on top of
+	// DefaultIgnore. A pattern without a slash matches a directory's name at
+	// any depth ("tmp", "*.bak"); one with a slash matches its path relative
+	// to the base ("archive/*", "**/fixtures"). "!" negates a pattern and the
+	// last matching pattern wins.
+	Ignore []string
+
+	// Workers is how many directories are read at once (default 16)
+	Workers int
+
+	// Cache remembers directory listings between scans; nil disables it
+	Cache *Cache
+}
+
+// Repo is a git repository found by FindRepos
+type Repo struct {
+	Path string
+	// Linked is true for a linked worktree, whose .git is a file
+	Linked bool
+}
+
+// FindRepos finds the git repositories under basePath, reading directories
+// in parallel. It doesn't descend into repositories. Cancelling ctx stops
+// the scan and returns ctx's error.
+func FindRepos(ctx context.Context, basePath string, opts ScanOptions) ([]Repo, error) {
+	absPath, err := filepath.Abs(basePath)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(absPath); err != nil {
+		return nil, err
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = defaultScanWorkers
+	}
+	rules := compileIgnore(append(append([]string(nil), DefaultIgnore...), opts.Ignore...))
+	opts.Cache.startScan(absPath)
+
+	var (
+		mu    sync.Mutex
+		repos []Repo
+		wg    sync.WaitGroup
+		sem   = make(chan struct{}, workers)
+	)
+
+	var visit func(dir string, depth int)
+	visit = func(dir string, depth int) {
+		defer wg.Done()
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+		listing, ok := readDirCached(dir, opts.Cache)
+		<-sem
+		if !ok {
+			return
+		}
+
+		if listing.Git != "" {
+			mu.Lock()
+			repos = append(repos, Repo{Path: dir, Linked: listing.Git == gitFile})
+			mu.Unlock()
+			// Don't descend into git repos
+			return
+		}
+
+		if opts.MaxDepth >= 0 && depth >= opts.MaxDepth {
+			return
+		}
+		for _, name := range listing.Subdirs {
+			sub := filepath.Join(dir, name)
+			rel, _ := filepath.Rel(absPath, sub)
+			if rules.ignored(filepath.ToSlash(rel)) {
+				continue
+			}
+			wg.Add(1)
+			go visit(sub, depth+1)
+		}
+	}
+
+	wg.Add(1)
+	visit(absPath, 0)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	// Order repositories as a depth-first walk would find them
+	sort.Slice(repos, func(i, j int) bool {
+		return strings.ReplaceAll(repos[i].Path, string(filepath.Separator), "\x00") <
+			strings.ReplaceAll(repos[j].Path, string(filepath.Separator), "\x00")
+	})
+	return repos, nil
+}
+
+// readDirCached lists a directory's subdirectories and whether it's a git
+// repository, from the cache when the directory hasn't changed since
+func readDirCached(dir string, cache *Cache) (cachedDir, bool) {
+	info, err := os.Stat(dir)
+	if err != nil || !info.IsDir() {
+		return cachedDir{}, false
+	}
+	if listing, ok := cache.dir(dir, info.ModTime()); ok {
+		return listing, true
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return cachedDir{}, false
+	}
+	listing := cachedDir{ModTime: info.ModTime()}
+	for _, entry := range entries {
+		if entry.Name() == ".git" {
+			listing.Git = gitFile
+			if entry.IsDir() {
+				listing.Git = gitDir
+			}
+			continue
+		}
+		if entry.IsDir() {
+			listing.Subdirs = append(listing.Subdirs, entry.Name())
+		}
+	}
+	cache.putDir(dir, listing)
+	return listing, true
+}
+
+// ignoreRule is a compiled ScanOptions.Ignore pattern
+type ignoreRule struct {
+	segments []string // pattern split on "/"; nil matches the name only
+	name     string
+	negate   bool
+}
+
+type ignoreRules []ignoreRule
+
+func compileIgnore(patterns []string) ignoreRules {
+	var rules ignoreRules
+	for _, p := range patterns {
+		p = strings.TrimSpace(p)
+		if p == "" || strings.HasPrefix(p, "#") {
+			continue
+		}
+		var rule ignoreRule
+		if strings.HasPrefix(p, "!") {
+			rule.negate = true
+			p = p[1:]
+		}
+		// Only directories are scanned, so a trailing slash changes nothing
+		p = strings.TrimSuffix(p, "/")
+		if strings.Contains(p, "/") {
+			rule.segments = strings.Split(strings.TrimPrefix(p, "/"), "/")
+		} else {
+			rule.name = p
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// ignored returns whether the directory at rel (slash-separated, relative
+// to the scan's base) is ignored
+func (rules ignoreRules) ignored(rel string) bool {
+	ignored := false
+	name := path.Base(rel)
+	for _, rule := range rules {
+		var match bool
+		if rule.segments != nil {
+			match = matchSegments(rule.segments, strings.Split(rel, "/"))
+		} else {
+			match, _ = path.Match(rule.name, name)
+		}
+		if match {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// matchSegments matches path segments against pattern segments, where "**"
+// matches any number of segments
+func matchSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(segments); i++ {
+				if matchSegments(pattern[1:], segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
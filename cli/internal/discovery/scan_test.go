@@ -0,0 +1,231 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iheanyi/grove/internal/execx"
+)
+
+// makeTree creates directories under root; names ending in /.git get an
+// empty .git directory, and /.git= a .git file
+func makeTree(t *testing.T, root string, dirs ...string) {
+	t.Helper()
+	for _, dir := range dirs {
+		if base, ok := strings.CutSuffix(dir, "/.git="); ok {
+			if err := os.MkdirAll(filepath.Join(root, base), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(root, base, ".git"), []byte("gitdir: /elsewhere\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func repoPaths(root string, repos []Repo) []string {
+	var paths []string
+	for _, r := range repos {
+		rel, _ := filepath.Rel(root, r.Path)
+		if r.Linked {
+			rel += " (linked)"
+		}
+		paths = append(paths, rel)
+	}
+	return paths
+}
+
+func TestFindRepos(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	makeTree(t, root,
+		"api/.git/objects",
+		"api/nested/.git",
+		"api-feature/.git=",
+		"clients/web/.git",
+		"clients/ios/.git",
+		"clients/deep/er/.git",
+		"node_modules/pkg/.git",
+		".cache/tool/.git",
+		"vendor/lib/.git",
+		"tmp/scratch/.git",
+		"archive/old/.git",
+		"archive/keep/.git",
+		"work/fixtures/repo/.git",
+	)
+
+	repos, err := FindRepos(ctx, root, ScanOptions{
+		MaxDepth: 2,
+		Ignore:   []string{"tmp", "archive/*", "!archive/keep", "**/fixtures", "!vendor"},
+		Workers:  3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"api", "api-feature (linked)", "archive/keep", "clients/ios", "clients/web", "vendor/lib"}
+	if got := repoPaths(root, repos); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindRepos() = %q, want %q", got, want)
+	}
+
+	repos, err = FindRepos(ctx, root, ScanOptions{MaxDepth: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []string{"api", "api-feature (linked)", "archive/keep", "archive/old", "clients/deep/er", "clients/ios", "clients/web", "tmp/scratch", "work/fixtures/repo"}
+	if got := repoPaths(root, repos); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindRepos(unlimited) = %q, want %q", got, want)
+	}
+
+	if _, err := FindRepos(ctx, filepath.Join(root, "missing"), ScanOptions{}); err == nil {
+		t.Error("FindRepos(missing) = nil error")
+	}
+}
+
+func TestFindRepos_Cache(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	makeTree(t, root, "api/.git", "web")
+	cachePath := filepath.Join(t.TempDir(), "scan-cache.json")
+
+	cache := LoadCache(cachePath)
+	if _, err := FindRepos(ctx, root, ScanOptions{MaxDepth: -1, Cache: cache}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// An unchanged directory comes from the cache, even if the cache is wrong
+	web := filepath.Join(root, "web")
+	cache = LoadCache(cachePath)
+	listing, ok := cache.data.Dirs[web]
+	if !ok {
+		t.Fatalf("cache has no entry for %s: %v", web, cache.data.Dirs)
+	}
+	listing.Git = gitDir
+	cache.data.Dirs[web] = listing
+	repos, _ := FindRepos(ctx, root, ScanOptions{MaxDepth: -1, Cache: cache})
+	if got := repoPaths(root, repos); !reflect.DeepEqual(got, []string{"api", "web"}) {
+		t.Errorf("cached FindRepos() = %q, want the cached listing", got)
+	}
+
+	// A changed directory is read again
+	later := listing.ModTime.Add(time.Second)
+	if err := os.Chtimes(web, later, later); err != nil {
+		t.Fatal(err)
+	}
+	repos, _ = FindRepos(ctx, root, ScanOptions{MaxDepth: -1, Cache: cache})
+	if got := repoPaths(root, repos); !reflect.DeepEqual(got, []string{"api"}) {
+		t.Errorf("FindRepos() after a change = %q, want [api]", got)
+	}
+
+	// Directories that are gone are dropped on save
+	if err := os.RemoveAll(web); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FindRepos(ctx, root, ScanOptions{MaxDepth: -1, Cache: cache}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := LoadCache(cachePath).data.Dirs[web]; ok {
+		t.Error("saved cache still has a deleted directory")
+	}
+}
+
+func TestFindRepos_Cancel(t *testing.T) {
+	root := t.TempDir()
+	makeTree(t, root, "api/.git", "clients/web/.git")
+	cache := LoadCache(filepath.Join(t.TempDir(), "scan-cache.json"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if repos, err := FindRepos(ctx, root, ScanOptions{MaxDepth: -1, Cache: cache}); !errors.Is(err, context.Canceled) {
+		t.Errorf("FindRepos(cancelled) = %q, %v; want context.Canceled", repoPaths(root, repos), err)
+	}
+	if _, err := FindAll(ctx, root, ScanOptions{MaxDepth: -1}); !errors.Is(err, context.Canceled) {
+		t.Errorf("FindAll(cancelled) error = %v, want context.Canceled", err)
+	}
+}
+
+func TestCache_Worktrees(t *testing.T) {
+	ctx := context.Background()
+	fake := execx.NewFake()
+	orig := runner
+	runner = fake
+	defer func() { runner = orig }()
+
+	root := t.TempDir()
+	makeTree(t, root, "api/.git/worktrees/feature")
+	repo := filepath.Join(root, "api")
+	fake.On("git worktree list --porcelain", "worktree "+repo+"\nbranch refs/heads/main\n\n", nil)
+
+	cache := LoadCache(filepath.Join(t.TempDir(), "scan-cache.json"))
+	for range 2 {
+		if worktrees, err := cache.Worktrees(ctx, repo); err != nil || len(worktrees) != 1 {
+			t.Fatalf("Worktrees() = %+v, %v", worktrees, err)
+		}
+	}
+	if n := len(fake.Calls()); n != 1 {
+		t.Errorf("git ran %d times, want once", n)
+	}
+
+	// Switching a linked worktree's branch touches its admin directory
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(filepath.Join(repo, ".git", "worktrees", "feature"), later, later); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.Worktrees(ctx, repo); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(fake.Calls()); n != 2 {
+		t.Errorf("git ran %d times after a change, want twice", n)
+	}
+}
+
+func TestFindAll(t *testing.T) {
+	fake := execx.NewFake()
+	orig := runner
+	runner = fake
+	defer func() { runner = orig }()
+
+	root := t.TempDir()
+	makeTree(t, root, "api/.git", "api-feature/.git=", "web/.git")
+	api, feature := filepath.Join(root, "api"), filepath.Join(root, "api-feature")
+	// Every repository gets the same answer from the fake, so web's list
+	// repeats api's worktrees, which FindAll dedupes
+	fake.On("git worktree list --porcelain", "worktree "+api+"\nbranch refs/heads/main\n\nworktree "+feature+"\nbranch refs/heads/feature\n\n", nil)
+
+	worktrees, err := FindAll(context.Background(), root, ScanOptions{MaxDepth: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, wt := range worktrees {
+		paths = append(paths, wt.Path)
+	}
+	if want := []string{api, feature}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("FindAll() = %q, want %q", paths, want)
+	}
+
+	var listed int
+	for _, c := range fake.Calls() {
+		if c.String() == "git worktree list --porcelain" {
+			listed++
+		}
+	}
+	if listed != 2 {
+		t.Errorf("listed worktrees %d times, want once per main repository (2)", listed)
+	}
+}
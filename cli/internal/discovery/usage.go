@@ -0,0 +1,86 @@
+package discovery
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// claudeProjectChars are the characters Claude Code replaces with '-' when
+// naming a project's transcript directory after its path
+var claudeProjectChars = regexp.MustCompile(`[^A-Za-z0-9]`)
+
+// ClaudeProjectsDir returns the directory Claude Code keeps transcripts in
+func ClaudeProjectsDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".claude", "projects")
+}
+
+// claudeTranscriptLine is the part of a Claude Code transcript line that
+// carries token usage
+type claudeTranscriptLine struct {
+	Timestamp time.Time `json:"timestamp"`
+	Message   struct {
+		Usage struct {
+			InputTokens              int `json:"input_tokens"`
+			OutputTokens             int `json:"output_tokens"`
+			CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+		} `json:"usage"`
+	} `json:"message"`
+}
+
+// ClaudeTokens returns the tokens Claude Code sessions in path have used
+// since a time, from the transcripts in projectsDir: input, output and cache
+// writes, but not cache reads
+func ClaudeTokens(projectsDir, path string, since time.Time) int {
+	dir := filepath.Join(projectsDir, claudeProjectChars.ReplaceAllString(path, "-"))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0
+	}
+
+	total := 0
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".jsonl") {
+			continue
+		}
+		// Transcripts untouched since then have nothing to count
+		if info, err := entry.Info(); err != nil || info.ModTime().Before(since) {
+			continue
+		}
+		total += transcriptTokens(filepath.Join(dir, entry.Name()), since)
+	}
+	return total
+}
+
+func transcriptTokens(path string, since time.Time) int {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+
+	total := 0
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if !strings.Contains(string(line), `"usage"`) {
+			continue
+		}
+		var entry claudeTranscriptLine
+		if err := json.Unmarshal(line, &entry); err != nil || entry.Timestamp.Before(since) {
+			continue
+		}
+		usage := entry.Message.Usage
+		total += usage.InputTokens + usage.OutputTokens + usage.CacheCreationInputTokens
+	}
+	return total
+}
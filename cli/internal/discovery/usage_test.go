@@ -0,0 +1,34 @@
+package discovery
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestClaudeTokens(t *testing.T) {
+	projects := t.TempDir()
+	dir := filepath.Join(projects, "-Users-me-src-my-app-feature-auth")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	transcript := `{"type":"user","timestamp":"2026-01-01T10:00:00Z","message":{"role":"user","content":"hi"}}
+{"type":"assistant","timestamp":"2026-01-01T09:00:00Z","message":{"usage":{"input_tokens":1000,"output_tokens":1000}}}
+{"type":"assistant","timestamp":"2026-01-01T10:01:00Z","message":{"usage":{"input_tokens":10,"output_tokens":20,"cache_creation_input_tokens":30,"cache_read_input_tokens":5000}}}
+not json
+{"type":"assistant","timestamp":"2026-01-01T10:02:00Z","message":{"usage":{"input_tokens":1,"output_tokens":2}}}
+`
+	if err := os.WriteFile(filepath.Join(dir, "session.jsonl"), []byte(transcript), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	since := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	if got := ClaudeTokens(projects, "/Users/me/src/my_app/feature.auth", since); got != 63 {
+		t.Errorf("ClaudeTokens = %d, want 63", got)
+	}
+	if got := ClaudeTokens(projects, "/Users/me/src/other", since); got != 0 {
+		t.Errorf("ClaudeTokens for unknown path = %d, want 0", got)
+	}
+}
@@ -0,0 +1,68 @@
+package discovery
+
+import (
+	"context"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Watch calls onChange whenever entries are created, removed or renamed in
+// the directories returned by dirs, once changes have settled for debounce.
+// dirs is called again after each change, so directories that appear (e.g.
+// a new repository's .git/worktrees) are watched too. Watch returns when ctx
+// is done; it's meant for 'grove discover --watch' and long-running grove
+// processes alike.
+func Watch(ctx context.Context, dirs func() []string, debounce time.Duration, onChange func()) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	watched := make(map[string]bool)
+	sync := func() {
+		want := make(map[string]bool)
+		for _, dir := range dirs() {
+			want[dir] = true
+			if !watched[dir] && watcher.Add(dir) == nil {
+				watched[dir] = true
+			}
+		}
+		for dir := range watched {
+			if !want[dir] {
+				watcher.Remove(dir) //nolint:errcheck // Removed directories are dropped anyway
+				delete(watched, dir)
+			}
+		}
+	}
+	sync()
+
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Has(fsnotify.Create) || event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+				timer.Reset(debounce)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return err
+		case <-timer.C:
+			// Watch new directories before reporting, so nothing created
+			// in response to the change is missed
+			sync()
+			onChange()
+		}
+	}
+}
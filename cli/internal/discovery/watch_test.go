@@ -0,0 +1,62 @@
+package discovery
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	root := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := make(chan struct{}, 10)
+	watching := make(chan struct{})
+	dirs := func() []string {
+		select {
+		case <-watching:
+		default:
+			close(watching)
+		}
+		// Watch new subdirectories too
+		entries, _ := os.ReadDir(root)
+		result := []string{root}
+		for _, e := range entries {
+			if e.IsDir() {
+				result = append(result, filepath.Join(root, e.Name()))
+			}
+		}
+		return result
+	}
+	go Watch(ctx, dirs, 50*time.Millisecond, func() { changes <- struct{}{} }) //nolint:errcheck // Test
+	<-watching
+
+	expectChange := func(what string) {
+		t.Helper()
+		select {
+		case <-changes:
+		case <-time.After(3 * time.Second):
+			t.Fatalf("no change reported after %s", what)
+		}
+	}
+
+	sub := filepath.Join(root, "project")
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	expectChange("creating a directory")
+
+	// The new directory is watched after the change
+	if err := os.Mkdir(filepath.Join(sub, "feature"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	expectChange("creating a nested directory")
+
+	if err := os.RemoveAll(sub); err != nil {
+		t.Fatal(err)
+	}
+	expectChange("removing a directory")
+}
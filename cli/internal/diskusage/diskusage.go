@@ -0,0 +1,183 @@
+// Package diskusage measures the disk space a worktree uses, broken down by
+// the dependency directories (node_modules, target, vendor...) that are
+// usually most of it.
+package diskusage
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/iheanyi/grove/internal/usage"
+)
+
+// Dependency directory kinds
+const (
+	NodeModules = "node_modules"
+	Target      = "target"
+	Vendor      = "vendor"
+	Venv        = ".venv"
+)
+
+// Kinds are the dependency directory kinds, in display order
+var Kinds = []string{NodeModules, Target, Vendor, Venv}
+
+// Usage is the disk space used by a directory tree, in bytes
+type Usage struct {
+	Total uint64 `json:"total_bytes"`
+	// Deps is the space used by each kind of dependency directory, at
+	// any depth (e.g. every node_modules of a monorepo)
+	Deps map[string]uint64 `json:"deps"`
+}
+
+// DepsTotal returns the space used by dependency directories
+func (u Usage) DepsTotal() uint64 {
+	var total uint64
+	for _, size := range u.Deps {
+		total += size
+	}
+	return total
+}
+
+// Scan walks dir and adds up the space its files use on disk. Symlinks
+// aren't followed, and a file hardlinked more than once within dir (as
+// pnpm and 'cp -al' do) counts once. Unreadable directories are skipped.
+func Scan(dir string) (Usage, error) {
+	result := Usage{Deps: make(map[string]uint64)}
+	if _, err := os.Stat(dir); err != nil {
+		return result, err
+	}
+
+	type inode struct{ dev, ino uint64 }
+	seen := make(map[inode]bool)
+	// kind is the dependency directory being walked, if any
+	var kind, kindRoot string
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if d != nil && d.IsDir() && path != dir {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if kind != "" && !strings.HasPrefix(path, kindRoot+string(filepath.Separator)) && path != kindRoot {
+			kind, kindRoot = "", ""
+		}
+		if d.IsDir() && kind == "" && path != dir {
+			if k := dependencyKind(path, d.Name()); k != "" {
+				kind, kindRoot = k, path
+			}
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		size := uint64(info.Size())
+		if st, ok := info.Sys().(*syscall.Stat_t); ok {
+			if st.Nlink > 1 && !d.IsDir() {
+				key := inode{uint64(st.Dev), uint64(st.Ino)}
+				if seen[key] {
+					return nil
+				}
+				seen[key] = true
+			}
+			// Blocks are what the file takes on disk, which differs
+			// from its size for small and sparse files
+			size = uint64(st.Blocks) * 512
+		}
+
+		result.Total += size
+		if kind != "" {
+			result.Deps[kind] += size
+		}
+		return nil
+	})
+	return result, err
+}
+
+// dependencyKind returns the kind of dependency directory at path, or ""
+// if it isn't one. target counts only beside a Cargo.toml or pom.xml, as
+// the name is common elsewhere.
+func dependencyKind(path, name string) string {
+	switch name {
+	case NodeModules, Vendor, Venv:
+		return name
+	case Target:
+		parent := filepath.Dir(path)
+		for _, manifest := range []string{"Cargo.toml", "pom.xml"} {
+			if _, err := os.Stat(filepath.Join(parent, manifest)); err == nil {
+				return Target
+			}
+		}
+	}
+	return ""
+}
+
+// ParseSize parses a size like 500M, 2G or 1.5GB (powers of 1024)
+func ParseSize(size string) (uint64, error) {
+	s := strings.TrimSuffix(strings.ToUpper(strings.TrimSpace(size)), "B")
+	unit := uint64(1)
+	for suffix, u := range map[string]uint64{"K": 1 << 10, "M": 1 << 20, "G": 1 << 30, "T": 1 << 40} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			s, unit = n, u
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid size %q (use e.g. 500M, 2G)", size)
+	}
+	return uint64(n * float64(unit)), nil
+}
+
+// Advice suggests ways to share the dependency directories that take space
+// in more than one of a repository's worktrees, based on the lockfiles and
+// manifests at repoPath
+func Advice(repoPath string, worktrees []Usage) []string {
+	totals := make(map[string]uint64)
+	counts := make(map[string]int)
+	for _, u := range worktrees {
+		for kind, size := range u.Deps {
+			if size > 0 {
+				totals[kind] += size
+				counts[kind]++
+			}
+		}
+	}
+	has := func(file string) bool {
+		_, err := os.Stat(filepath.Join(repoPath, file))
+		return err == nil
+	}
+	across := func(kind string) string {
+		return fmt.Sprintf("%s takes %s across %d worktrees", kind, usage.FormatBytes(totals[kind]), counts[kind])
+	}
+
+	var advice []string
+	if counts[NodeModules] > 1 {
+		if has("pnpm-lock.yaml") {
+			advice = append(advice, across(NodeModules)+"; pnpm hardlinks packages from its store, so most of it is shared on disk already")
+		} else {
+			advice = append(advice, across(NodeModules)+"; pnpm ('pnpm import', then 'pnpm install') hardlinks packages from one store, so worktrees share them")
+		}
+	}
+	if counts[Target] > 1 && has("Cargo.toml") {
+		advice = append(advice, across(Target)+"; share one build directory with CARGO_TARGET_DIR or build.target-dir in .cargo/config.toml, or cache builds with sccache")
+	}
+	if counts[Vendor] > 1 {
+		switch {
+		case has("Gemfile"):
+			advice = append(advice, across(Vendor)+"; install gems once with 'bundle config set --global path ~/.bundle' instead of into each worktree")
+		case has("go.mod"):
+			advice = append(advice, across(Vendor)+"; without vendor/, Go builds share the module cache in $GOMODCACHE")
+		}
+	}
+	if counts[Venv] > 1 {
+		advice = append(advice, across(Venv)+"; uv installs from a shared cache, hardlinking packages into each virtualenv (UV_LINK_MODE=hardlink)")
+	}
+	return advice
+}
@@ -0,0 +1,111 @@
+package diskusage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path string, size int) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Repeat("x", size)), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScan(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "src", "main.js"), 10000)
+	writeFile(t, filepath.Join(dir, "node_modules", "react", "index.js"), 40000)
+	writeFile(t, filepath.Join(dir, "packages", "ui", "node_modules", "lodash", "index.js"), 40000)
+	// target without a Cargo.toml is just a directory
+	writeFile(t, filepath.Join(dir, "src", "target", "a.js"), 10000)
+	writeFile(t, filepath.Join(dir, "crate", "Cargo.toml"), 100)
+	writeFile(t, filepath.Join(dir, "crate", "target", "debug", "app"), 80000)
+
+	usage, err := Scan(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage.Deps[NodeModules] < 80000 || usage.Deps[NodeModules] > 110000 {
+		t.Errorf("node_modules = %d, want about 80000", usage.Deps[NodeModules])
+	}
+	if usage.Deps[Target] < 80000 || usage.Deps[Target] > 100000 {
+		t.Errorf("target = %d, want about 80000", usage.Deps[Target])
+	}
+	if usage.Total < usage.DepsTotal()+20000 {
+		t.Errorf("total = %d, want deps (%d) plus the sources", usage.Total, usage.DepsTotal())
+	}
+}
+
+func TestScan_HardlinksCountOnce(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a", "big"), 100000)
+	if err := os.Link(filepath.Join(dir, "a", "big"), filepath.Join(dir, "big-link")); err != nil {
+		t.Skip("hardlinks not supported:", err)
+	}
+
+	usage, err := Scan(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usage.Total >= 200000 {
+		t.Errorf("total = %d, want the hardlinked file counted once", usage.Total)
+	}
+}
+
+func TestScan_Missing(t *testing.T) {
+	if _, err := Scan(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("Scan() of a missing directory should fail")
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	for in, want := range map[string]uint64{
+		"500M":  500 << 20,
+		"2G":    2 << 30,
+		"1.5GB": 3 << 29,
+		"10k":   10 << 10,
+		"4096":  4096,
+	} {
+		got, err := ParseSize(in)
+		if err != nil || got != want {
+			t.Errorf("ParseSize(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "big", "-1G"} {
+		if _, err := ParseSize(in); err == nil {
+			t.Errorf("ParseSize(%q) should fail", in)
+		}
+	}
+}
+
+func TestAdvice(t *testing.T) {
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, "package-lock.json"), 10)
+	writeFile(t, filepath.Join(repo, "Cargo.toml"), 10)
+
+	worktrees := []Usage{
+		{Deps: map[string]uint64{NodeModules: 1 << 30, Target: 1 << 30}},
+		{Deps: map[string]uint64{NodeModules: 1 << 29}},
+	}
+	advice := Advice(repo, worktrees)
+	if len(advice) != 1 || !strings.Contains(advice[0], "node_modules takes 1.5 GB across 2 worktrees") || !strings.Contains(advice[0], "pnpm import") {
+		t.Errorf("Advice() = %q, want pnpm advice for node_modules only", advice)
+	}
+
+	writeFile(t, filepath.Join(repo, "pnpm-lock.yaml"), 10)
+	worktrees[1].Deps[Target] = 1 << 29
+	advice = Advice(repo, worktrees)
+	if len(advice) != 2 || !strings.Contains(advice[0], "shared on disk already") || !strings.Contains(advice[1], "CARGO_TARGET_DIR") {
+		t.Errorf("Advice() = %q, want pnpm and cargo advice", advice)
+	}
+
+	if advice := Advice(repo, worktrees[:1]); len(advice) != 0 {
+		t.Errorf("Advice() for one worktree = %q, want none", advice)
+	}
+}
@@ -0,0 +1,37 @@
+package dns
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// ResolverConfig returns the path and content of the system resolver
+// configuration that sends queries for tld to the responder on port.
+//
+// On macOS this is /etc/resolver/<tld>; on Linux it is a systemd-resolved
+// drop-in routing the ~<tld> domain to 127.0.0.1:<port>.
+func ResolverConfig(goos, tld string, port int) (path, content string, err error) {
+	switch goos {
+	case "darwin":
+		path = filepath.Join("/etc/resolver", tld)
+		content = fmt.Sprintf("# Managed by grove (grove dns setup)\nnameserver 127.0.0.1\nport %d\n", port)
+	case "linux":
+		path = filepath.Join("/etc/systemd/resolved.conf.d", fmt.Sprintf("grove-%s.conf", tld))
+		content = fmt.Sprintf("# Managed by grove (grove dns setup)\n[Resolve]\nDNS=127.0.0.1:%d\nDomains=~%s\n", port, tld)
+	default:
+		return "", "", fmt.Errorf("DNS setup is not supported on %s", goos)
+	}
+	return path, content, nil
+}
+
+// ReloadCommand returns the command that makes the resolver pick up a
+// changed configuration, or nil if none is needed
+func ReloadCommand(goos string) []string {
+	switch goos {
+	case "darwin":
+		return []string{"killall", "-HUP", "mDNSResponder"}
+	case "linux":
+		return []string{"systemctl", "restart", "systemd-resolved"}
+	}
+	return nil
+}
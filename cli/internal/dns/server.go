@@ -0,0 +1,162 @@
+// Package dns implements a minimal DNS responder that resolves every name
+// under a TLD to the loopback address, so *.<tld> works without /etc/hosts.
+package dns
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"strings"
+)
+
+const (
+	typeA    = 1
+	typeAAAA = 28
+	classIN  = 1
+
+	rcodeFormErr  = 1
+	rcodeNXDomain = 3
+	rcodeNotImp   = 4
+
+	// answerTTL is short so changes (e.g., teardown) take effect quickly
+	answerTTL = 60
+)
+
+// Serve answers DNS queries on addr (e.g., 127.0.0.1:5354) until ctx is
+// canceled. Names under tld resolve to 127.0.0.1 and ::1; anything else
+// gets NXDOMAIN.
+func Serve(ctx context.Context, addr, tld string) error {
+	conn, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	buf := make([]byte, 512)
+	for {
+		n, from, err := conn.ReadFrom(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				continue
+			}
+			return err
+		}
+
+		if resp := Answer(buf[:n], tld); resp != nil {
+			conn.WriteTo(resp, from) //nolint:errcheck // Client will retry
+		}
+	}
+}
+
+// Answer builds the response to a single DNS query packet. It returns nil
+// if the packet is too malformed to reply to.
+func Answer(query []byte, tld string) []byte {
+	if len(query) < 12 {
+		return nil
+	}
+
+	// Ignore responses; only answer standard queries
+	flags := binary.BigEndian.Uint16(query[2:4])
+	if flags&0x8000 != 0 {
+		return nil
+	}
+
+	name, qEnd, ok := parseQuestion(query)
+	if !ok || binary.BigEndian.Uint16(query[4:6]) != 1 {
+		return reply(query, 12, rcodeFormErr, nil)
+	}
+	if (flags>>11)&0xF != 0 {
+		return reply(query, qEnd, rcodeNotImp, nil)
+	}
+
+	qtype := binary.BigEndian.Uint16(query[qEnd-4 : qEnd-2])
+	qclass := binary.BigEndian.Uint16(query[qEnd-2 : qEnd])
+
+	tld = strings.ToLower(strings.Trim(tld, "."))
+	if name != tld && !strings.HasSuffix(name, "."+tld) {
+		return reply(query, qEnd, rcodeNXDomain, nil)
+	}
+
+	var rdata []byte
+	switch {
+	case qclass == classIN && qtype == typeA:
+		rdata = net.IPv4(127, 0, 0, 1).To4()
+	case qclass == classIN && qtype == typeAAAA:
+		rdata = net.IPv6loopback
+	}
+
+	return reply(query, qEnd, 0, rdata)
+}
+
+// parseQuestion reads the first question's name and returns it lowercased
+// along with the offset just past the question
+func parseQuestion(msg []byte) (string, int, bool) {
+	var labels []string
+	i := 12
+	for {
+		if i >= len(msg) {
+			return "", 0, false
+		}
+		l := int(msg[i])
+		i++
+		if l == 0 {
+			break
+		}
+		// Compression pointers aren't valid in a query's first name
+		if l&0xC0 != 0 || i+l > len(msg) {
+			return "", 0, false
+		}
+		labels = append(labels, strings.ToLower(string(msg[i:i+l])))
+		i += l
+	}
+	if i+4 > len(msg) {
+		return "", 0, false
+	}
+	return strings.Join(labels, "."), i + 4, true
+}
+
+// reply builds a response echoing the query header and question (up to
+// qEnd), with a single answer record if rdata is set
+func reply(query []byte, qEnd int, rcode uint16, rdata []byte) []byte {
+	resp := make([]byte, qEnd, qEnd+16+len(rdata))
+	copy(resp, query[:qEnd])
+
+	// QR + AA, keep opcode and RD from the query
+	flags := binary.BigEndian.Uint16(query[2:4])
+	flags = 0x8000 | 0x0400 | (flags & 0x7900) | rcode
+	binary.BigEndian.PutUint16(resp[2:4], flags)
+
+	qdcount := uint16(0)
+	if qEnd > 12 {
+		qdcount = 1
+	}
+	binary.BigEndian.PutUint16(resp[4:6], qdcount)
+	binary.BigEndian.PutUint16(resp[8:10], 0)
+	binary.BigEndian.PutUint16(resp[10:12], 0)
+
+	if rdata == nil {
+		binary.BigEndian.PutUint16(resp[6:8], 0)
+		return resp
+	}
+
+	binary.BigEndian.PutUint16(resp[6:8], 1)
+	qtype := query[qEnd-4 : qEnd-2]
+
+	// Name is a pointer to the question name at offset 12
+	resp = append(resp, 0xC0, 0x0C)
+	resp = append(resp, qtype...)
+	resp = binary.BigEndian.AppendUint16(resp, classIN)
+	resp = binary.BigEndian.AppendUint32(resp, answerTTL)
+	resp = binary.BigEndian.AppendUint16(resp, uint16(len(rdata)))
+	resp = append(resp, rdata...)
+	return resp
+}
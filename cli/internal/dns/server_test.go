@@ -0,0 +1,110 @@
+package dns
+
+import (
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+// buildQuery builds a minimal DNS query packet for name and qtype
+func buildQuery(name string, qtype uint16) []byte {
+	msg := []byte{0x12, 0x34, 0x01, 0x00, 0, 1, 0, 0, 0, 0, 0, 0}
+	for _, label := range strings.Split(name, ".") {
+		msg = append(msg, byte(len(label)))
+		msg = append(msg, label...)
+	}
+	msg = append(msg, 0)
+	msg = binary.BigEndian.AppendUint16(msg, qtype)
+	msg = binary.BigEndian.AppendUint16(msg, classIN)
+	return msg
+}
+
+func TestAnswer(t *testing.T) {
+	tests := []struct {
+		name      string
+		host      string
+		qtype     uint16
+		rcode     uint16
+		answers   uint16
+		wantRData []byte
+	}{
+		{"A record", "feature.test", typeA, 0, 1, []byte{127, 0, 0, 1}},
+		{"nested subdomain", "api.Feature.TEST", typeA, 0, 1, []byte{127, 0, 0, 1}},
+		{"AAAA record", "feature.test", typeAAAA, 0, 1, append(make([]byte, 15), 1)},
+		{"other type", "feature.test", 16, 0, 0, nil},
+		{"outside tld", "example.com", typeA, rcodeNXDomain, 0, nil},
+		{"suffix but not tld", "feature.latest", typeA, rcodeNXDomain, 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := buildQuery(tt.host, tt.qtype)
+			resp := Answer(query, "test")
+			if resp == nil {
+				t.Fatal("expected a response")
+			}
+
+			if resp[0] != 0x12 || resp[1] != 0x34 {
+				t.Error("expected response to echo the query ID")
+			}
+			flags := binary.BigEndian.Uint16(resp[2:4])
+			if flags&0x8000 == 0 {
+				t.Error("expected QR bit to be set")
+			}
+			if flags&0x0100 == 0 {
+				t.Error("expected RD bit to be echoed")
+			}
+			if rcode := flags & 0xF; rcode != tt.rcode {
+				t.Errorf("rcode = %d, want %d", rcode, tt.rcode)
+			}
+			if got := binary.BigEndian.Uint16(resp[6:8]); got != tt.answers {
+				t.Fatalf("answers = %d, want %d", got, tt.answers)
+			}
+
+			if tt.wantRData != nil {
+				rdata := resp[len(resp)-len(tt.wantRData):]
+				if string(rdata) != string(tt.wantRData) {
+					t.Errorf("rdata = %v, want %v", rdata, tt.wantRData)
+				}
+			}
+		})
+	}
+}
+
+func TestAnswer_Malformed(t *testing.T) {
+	if resp := Answer([]byte{1, 2, 3}, "test"); resp != nil {
+		t.Error("expected nil for truncated header")
+	}
+
+	// Header claims a question but the name is truncated
+	query := []byte{0, 1, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 5, 'a', 'b'}
+	resp := Answer(query, "test")
+	if resp == nil {
+		t.Fatal("expected FORMERR response")
+	}
+	if rcode := binary.BigEndian.Uint16(resp[2:4]) & 0xF; rcode != rcodeFormErr {
+		t.Errorf("rcode = %d, want %d", rcode, rcodeFormErr)
+	}
+}
+
+func TestResolverConfig(t *testing.T) {
+	path, content, err := ResolverConfig("darwin", "test", 5354)
+	if err != nil {
+		t.Fatalf("ResolverConfig(darwin) failed: %v", err)
+	}
+	if path != "/etc/resolver/test" || !strings.Contains(content, "port 5354") {
+		t.Errorf("unexpected darwin config %s:\n%s", path, content)
+	}
+
+	path, content, err = ResolverConfig("linux", "test", 5354)
+	if err != nil {
+		t.Fatalf("ResolverConfig(linux) failed: %v", err)
+	}
+	if !strings.HasSuffix(path, "grove-test.conf") || !strings.Contains(content, "DNS=127.0.0.1:5354") || !strings.Contains(content, "Domains=~test") {
+		t.Errorf("unexpected linux config %s:\n%s", path, content)
+	}
+
+	if _, _, err := ResolverConfig("windows", "test", 5354); err == nil {
+		t.Error("expected error for unsupported OS")
+	}
+}
@@ -0,0 +1,95 @@
+// Package editor opens worktrees in editors and IDEs via their command-line
+// launchers
+package editor
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Editor is an editor with a command-line launcher
+type Editor struct {
+	// Name identifies the editor in config and the registry
+	Name string
+
+	// Command is the launcher, which is passed the worktree path
+	Command string
+
+	// Process is matched (case-insensitively) against running processes to
+	// detect that the editor has a worktree open
+	Process string
+}
+
+// known editors, in auto-detection order
+var known = []Editor{
+	{Name: "vscode", Command: "code", Process: "code"},
+	{Name: "cursor", Command: "cursor", Process: "cursor"},
+	{Name: "zed", Command: "zed", Process: "zed"},
+	{Name: "idea", Command: "idea", Process: "idea"},
+	{Name: "goland", Command: "goland", Process: "goland"},
+	{Name: "webstorm", Command: "webstorm", Process: "webstorm"},
+	{Name: "pycharm", Command: "pycharm", Process: "pycharm"},
+	{Name: "rubymine", Command: "rubymine", Process: "rubymine"},
+	{Name: "phpstorm", Command: "phpstorm", Process: "phpstorm"},
+	{Name: "rustrover", Command: "rustrover", Process: "rustrover"},
+	{Name: "clion", Command: "clion", Process: "clion"},
+	{Name: "rider", Command: "rider", Process: "rider"},
+}
+
+// Known returns the editors grove knows about
+func Known() []Editor {
+	return append([]Editor(nil), known...)
+}
+
+// Find looks up a known editor by name or launcher command
+func Find(name string) (Editor, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, e := range known {
+		if e.Name == name || e.Command == name {
+			return e, true
+		}
+	}
+	return Editor{}, false
+}
+
+// Resolve returns the editor for the first non-empty candidate (e.g. a
+// flag, the config, then the recorded editor). Unknown names are treated as
+// launcher commands. With no candidates, the first known editor on PATH is
+// used.
+func Resolve(candidates ...string) (Editor, error) {
+	for _, name := range candidates {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		if e, ok := Find(name); ok {
+			return e, nil
+		}
+		return Editor{Name: name, Command: name, Process: name}, nil
+	}
+
+	for _, e := range known {
+		if e.Available() {
+			return e, nil
+		}
+	}
+	return Editor{}, fmt.Errorf("no editor found in PATH; set 'editor' in config.yaml or pass --editor")
+}
+
+// Available reports whether the editor's launcher is in PATH
+func (e Editor) Available() bool {
+	_, err := exec.LookPath(e.Command)
+	return err == nil
+}
+
+// Open opens path in the editor without waiting for it to exit
+func (e Editor) Open(path string) error {
+	if !e.Available() {
+		return fmt.Errorf("%s not found in PATH", e.Command)
+	}
+	cmd := exec.Command(e.Command, path)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start %s: %w", e.Command, err)
+	}
+	return cmd.Process.Release()
+}
@@ -0,0 +1,39 @@
+package editor
+
+import "testing"
+
+func TestFind(t *testing.T) {
+	tests := map[string]string{
+		"vscode": "vscode",
+		"code":   "vscode",
+		"Cursor": "cursor",
+		"goland": "goland",
+	}
+	for in, want := range tests {
+		e, ok := Find(in)
+		if !ok || e.Name != want {
+			t.Errorf("Find(%q) = %+v, %v; want %s", in, e, ok, want)
+		}
+	}
+
+	if _, ok := Find("notepad"); ok {
+		t.Error("Find(notepad) should not match")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	e, err := Resolve("", "zed", "vscode")
+	if err != nil || e.Name != "zed" {
+		t.Errorf("Resolve() = %+v, %v; want first non-empty candidate", e, err)
+	}
+
+	e, err = Resolve("subl")
+	if err != nil || e.Command != "subl" || e.Process != "subl" {
+		t.Errorf("Resolve(subl) = %+v, %v; want a custom launcher", e, err)
+	}
+
+	t.Setenv("PATH", t.TempDir())
+	if _, err := Resolve("", ""); err == nil {
+		t.Error("Resolve() with no editors in PATH should fail")
+	}
+}
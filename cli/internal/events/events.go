@@ -0,0 +1,107 @@
+// Package events provides a process-wide bus for server lifecycle events
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Type identifies a lifecycle event
+type Type string
+
+const (
+	// ServerStarted is published when a server starts
+	ServerStarted Type = "start"
+	// ServerStopped is published when a server is stopped
+	ServerStopped Type = "stop"
+	// ServerCrashed is published when a server (or one of its processes) exits unexpectedly
+	ServerCrashed Type = "crash"
+	// ServerIdleStopped is published when a server is stopped for being idle
+	ServerIdleStopped Type = "idle_stop"
+	// HealthChanged is published when a server's health check result flips
+	HealthChanged Type = "health"
+	// AgentLimitExceeded is published when an AI agent session goes over
+	// agent_limits
+	AgentLimitExceeded Type = "agent_limit"
+)
+
+// Event describes something that happened to a server
+type Event struct {
+	Type    Type      `json:"event"`
+	Server  string    `json:"server"`
+	Path    string    `json:"path,omitempty"`
+	URL     string    `json:"url,omitempty"`
+	Port    int       `json:"port,omitempty"`
+	PID     int       `json:"pid,omitempty"`
+	Process string    `json:"process,omitempty"`
+	Health  string    `json:"health,omitempty"`
+	Message string    `json:"message,omitempty"`
+	Time    time.Time `json:"time"`
+
+	// ExitCode is a crashed process's exit code, when it's known
+	ExitCode *int `json:"exit_code,omitempty"`
+}
+
+// Handler receives published events
+type Handler func(Event)
+
+// Bus fans events out to subscribed handlers
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []Handler
+}
+
+// Subscribe registers a handler for all events
+func (b *Bus) Subscribe(h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, h)
+}
+
+// Reset removes all handlers
+func (b *Bus) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = nil
+}
+
+// Publish delivers an event to every handler concurrently and waits for them
+// to finish, so short-lived CLI commands don't exit before notifications are
+// sent. Handlers are responsible for their own timeouts.
+func (b *Bus) Publish(e Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	b.mu.RLock()
+	handlers := append([]Handler(nil), b.handlers...)
+	b.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, h := range handlers {
+		wg.Add(1)
+		go func(h Handler) {
+			defer wg.Done()
+			h(e)
+		}(h)
+	}
+	wg.Wait()
+}
+
+// defaultBus is the process-wide bus used by the package-level functions
+var defaultBus = &Bus{}
+
+// Subscribe registers a handler on the default bus
+func Subscribe(h Handler) {
+	defaultBus.Subscribe(h)
+}
+
+// Reset removes all handlers from the default bus
+func Reset() {
+	defaultBus.Reset()
+}
+
+// Publish delivers an event on the default bus
+func Publish(e Event) {
+	defaultBus.Publish(e)
+}
@@ -0,0 +1,84 @@
+package events
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBus_PublishDeliversToAllHandlers(t *testing.T) {
+	bus := &Bus{}
+
+	var mu sync.Mutex
+	var got []string
+	for _, name := range []string{"a", "b"} {
+		name := name
+		bus.Subscribe(func(e Event) {
+			mu.Lock()
+			defer mu.Unlock()
+			got = append(got, name+":"+e.Server)
+		})
+	}
+
+	bus.Publish(Event{Type: ServerStarted, Server: "feature-auth"})
+
+	if len(got) != 2 {
+		t.Fatalf("got %d deliveries, want 2: %v", len(got), got)
+	}
+}
+
+func TestBus_PublishSetsTime(t *testing.T) {
+	bus := &Bus{}
+
+	var got Event
+	bus.Subscribe(func(e Event) { got = e })
+	bus.Publish(Event{Type: ServerCrashed, Server: "api"})
+
+	if got.Time.IsZero() {
+		t.Error("expected Publish to set the event time")
+	}
+}
+
+func TestBus_Reset(t *testing.T) {
+	bus := &Bus{}
+
+	called := false
+	bus.Subscribe(func(e Event) { called = true })
+	bus.Reset()
+	bus.Publish(Event{Type: ServerStopped})
+
+	if called {
+		t.Error("handler called after Reset")
+	}
+}
+
+func TestLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "activity.jsonl")
+	if log, err := ReadLog(path, Query{}); err != nil || len(log) != 0 {
+		t.Fatalf("ReadLog() of a missing log = %v, %v", log, err)
+	}
+
+	logger := Logger(path)
+	now := time.Now()
+	for i, name := range []string{"a", "b", "c", "b"} {
+		logger(Event{Type: ServerStarted, Server: name, Time: now.Add(time.Duration(i) * time.Hour)})
+	}
+	logger(Event{Type: ServerCrashed, Server: "b", Time: now.Add(4 * time.Hour)})
+
+	log, err := ReadLog(path, Query{Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(log) != 2 || log[0].Server != "b" || log[1].Type != ServerCrashed {
+		t.Errorf("ReadLog(Limit: 2) = %+v, want the last two events", log)
+	}
+
+	log, err = ReadLog(path, Query{Server: "b", Type: ServerStarted, Since: now.Add(2 * time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(log) != 1 || !log[0].Time.Equal(now.Add(3*time.Hour)) {
+		t.Errorf("ReadLog(b, start, since) = %+v, want b's second start", log)
+	}
+}
@@ -0,0 +1,85 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// logMu serializes appends to activity logs within the process
+var logMu sync.Mutex
+
+// Logger returns a handler that appends each event to the JSON lines file
+// at path, the activity timeline
+func Logger(path string) Handler {
+	return func(e Event) {
+		_ = AppendLog(path, e)
+	}
+}
+
+// AppendLog appends an event to the JSON lines file at path
+func AppendLog(path string, e Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	logMu.Lock()
+	defer logMu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// Query selects events from the activity timeline
+type Query struct {
+	Server string    // only this server's events, when set
+	Type   Type      // only events of this type, when set
+	Since  time.Time // only events at or after this time, when set
+	Limit  int       // only the latest events, when positive
+}
+
+// Match reports whether an event passes the query's filters
+func (q Query) Match(e Event) bool {
+	return (q.Server == "" || e.Server == q.Server) &&
+		(q.Type == "" || e.Type == q.Type) &&
+		!e.Time.Before(q.Since)
+}
+
+// ReadLog returns the events of the log at path matching a query, oldest
+// first. A missing log has no events.
+func ReadLog(path string, q Query) ([]Event, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var log []Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil || !q.Match(e) {
+			continue
+		}
+		log = append(log, e)
+		if q.Limit > 0 && len(log) > q.Limit {
+			log = log[1:]
+		}
+	}
+	return log, scanner.Err()
+}
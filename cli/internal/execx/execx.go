@@ -0,0 +1,171 @@
+// Package execx runs external commands and finds processes behind the
+// Runner interface, so code that starts servers or inspects the system can
+// be tested with a Fake instead of a live system.
+package execx
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Cmd describes a command to run
+type Cmd struct {
+	Name   string
+	Args   []string
+	Dir    string
+	Env    []string // nil inherits grove's environment
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	// Setpgid starts the command in its own process group, so it survives
+	// grove exiting and can be signaled as a group
+	Setpgid bool
+	// WaitDelay bounds how long Wait waits for output to be copied once
+	// the command exits, when processes it spawned hold its output open
+	WaitDelay time.Duration
+	// Timeout kills the command if Output or Run takes longer; zero waits
+	// forever
+	Timeout time.Duration
+}
+
+// QueryTimeout bounds commands that only inspect the system, like ps,
+// lsof and git. They normally finish in milliseconds; one that hangs on a
+// stuck mount or a locked repository shouldn't hang grove with it.
+const QueryTimeout = 5 * time.Second
+
+// Command returns a Cmd running name with args
+func Command(name string, args ...string) *Cmd {
+	return &Cmd{Name: name, Args: args}
+}
+
+// Query returns a Cmd running name with args that times out after
+// QueryTimeout
+func Query(name string, args ...string) *Cmd {
+	return &Cmd{Name: name, Args: args, Timeout: QueryTimeout}
+}
+
+// String returns the command line, e.g. "ps -p 42 -o command="
+func (c *Cmd) String() string {
+	return strings.Join(append([]string{c.Name}, c.Args...), " ")
+}
+
+// Process is a started or found process
+type Process interface {
+	Pid() int
+	Signal(sig os.Signal) error
+	// SignalGroup sends sig to the process group the process leads, like
+	// a process started with Setpgid. It fails if it leads none.
+	SignalGroup(sig syscall.Signal) error
+	Kill() error
+	// Wait waits for the process to exit. Processes grove didn't start
+	// can't be waited on by most systems, so Wait returns an error for them.
+	Wait() error
+	// Release lets a started process outlive grove
+	Release() error
+}
+
+// Runner runs commands and finds processes
+type Runner interface {
+	// Output runs cmd and returns its standard output. Cancelling ctx kills
+	// the command.
+	Output(ctx context.Context, cmd *Cmd) ([]byte, error)
+	// Run runs cmd and waits for it to finish. Cancelling ctx kills the
+	// command.
+	Run(ctx context.Context, cmd *Cmd) error
+	// Start starts cmd without waiting for it
+	Start(cmd *Cmd) (Process, error)
+	// FindProcess returns the process with the given PID
+	FindProcess(pid int) (Process, error)
+}
+
+// OS runs commands with os/exec
+var OS Runner = osRunner{}
+
+type osRunner struct{}
+
+func (osRunner) command(ctx context.Context, c *Cmd) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, c.Name, c.Args...)
+	cmd.Dir = c.Dir
+	cmd.Env = c.Env
+	cmd.Stdin = c.Stdin
+	cmd.Stdout = c.Stdout
+	cmd.Stderr = c.Stderr
+	cmd.WaitDelay = c.WaitDelay
+	if c.Setpgid {
+		cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	}
+	return cmd
+}
+
+func (r osRunner) Output(ctx context.Context, c *Cmd) ([]byte, error) {
+	ctx, cancel := withTimeout(ctx, c.Timeout)
+	defer cancel()
+	return r.command(ctx, c).Output()
+}
+
+func (r osRunner) Run(ctx context.Context, c *Cmd) error {
+	ctx, cancel := withTimeout(ctx, c.Timeout)
+	defer cancel()
+	return r.command(ctx, c).Run()
+}
+
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+func (r osRunner) Start(c *Cmd) (Process, error) {
+	cmd := r.command(context.Background(), c)
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return &startedProcess{cmd: cmd}, nil
+}
+
+func (osRunner) FindProcess(pid int) (Process, error) {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return nil, err
+	}
+	return foundProcess{p}, nil
+}
+
+// startedProcess is a process grove started; Wait also waits for its
+// output to be copied
+type startedProcess struct {
+	cmd *exec.Cmd
+}
+
+func (p *startedProcess) Pid() int                   { return p.cmd.Process.Pid }
+func (p *startedProcess) Signal(sig os.Signal) error { return p.cmd.Process.Signal(sig) }
+func (p *startedProcess) Kill() error                { return p.cmd.Process.Kill() }
+func (p *startedProcess) SignalGroup(sig syscall.Signal) error {
+	return syscall.Kill(-p.cmd.Process.Pid, sig)
+}
+func (p *startedProcess) Wait() error    { return p.cmd.Wait() }
+func (p *startedProcess) Release() error { return p.cmd.Process.Release() }
+
+// foundProcess is a process found by PID
+type foundProcess struct {
+	p *os.Process
+}
+
+func (p foundProcess) Pid() int                   { return p.p.Pid }
+func (p foundProcess) Signal(sig os.Signal) error { return p.p.Signal(sig) }
+func (p foundProcess) Kill() error                { return p.p.Kill() }
+func (p foundProcess) SignalGroup(sig syscall.Signal) error {
+	return syscall.Kill(-p.p.Pid, sig)
+}
+func (p foundProcess) Release() error { return p.p.Release() }
+
+func (p foundProcess) Wait() error {
+	_, err := p.p.Wait()
+	return err
+}
@@ -0,0 +1,112 @@
+package execx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"slices"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestOS(t *testing.T) {
+	out, err := OS.Output(context.Background(), &Cmd{Name: "sh", Args: []string{"-c", "pwd; echo $GREETING"}, Dir: "/", Env: []string{"GREETING=hi"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != "/\nhi\n" {
+		t.Errorf("Output() = %q", out)
+	}
+
+	var stdout bytes.Buffer
+	run := Command("echo", "hello")
+	run.Stdout = &stdout
+	if err := OS.Run(context.Background(), run); err != nil || stdout.String() != "hello\n" {
+		t.Errorf("Run() = %v, stdout %q", err, stdout.String())
+	}
+
+	timeout := Command("sleep", "10")
+	timeout.Timeout = 50 * time.Millisecond
+	if err := OS.Run(context.Background(), timeout); err == nil {
+		t.Error("Run() = nil after its timeout")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	if _, err := OS.Output(ctx, Command("sleep", "10")); err == nil || time.Since(start) > 5*time.Second {
+		t.Errorf("Output() = %v after %v, want killed when its context is cancelled", err, time.Since(start))
+	}
+
+	cmd := Command("sleep", "10")
+	cmd.Setpgid = true
+	proc, err := OS.Start(cmd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pgid, err := syscall.Getpgid(proc.Pid()); err != nil || pgid != proc.Pid() {
+		t.Errorf("process group = %d, %v; want its own (%d)", pgid, err, proc.Pid())
+	}
+	found, err := OS.FindProcess(proc.Pid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := found.Signal(syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+	var exitErr *exec.ExitError
+	if err := proc.Wait(); !errors.As(err, &exitErr) || exitErr.Sys().(syscall.WaitStatus).Signal() != syscall.SIGTERM {
+		t.Errorf("Wait() = %v, want killed by SIGTERM", err)
+	}
+}
+
+func TestFake(t *testing.T) {
+	f := NewFake()
+	f.On("git status --porcelain", " M main.go\n", nil)
+
+	if out, err := f.Output(context.Background(), Command("git", "status", "--porcelain")); err != nil || string(out) != " M main.go\n" {
+		t.Errorf("Output() = %q, %v", out, err)
+	}
+	if _, err := f.Output(context.Background(), Command("lsof", "-p", "1")); !errors.Is(err, exec.ErrNotFound) {
+		t.Errorf("unknown command error = %v, want not found", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := f.Output(ctx, Command("git", "status", "--porcelain")); !errors.Is(err, context.Canceled) {
+		t.Errorf("Output(cancelled) error = %v, want context.Canceled", err)
+	}
+	if got := f.Commands(); !slices.Equal(got, []string{"git status --porcelain", "lsof -p 1"}) {
+		t.Errorf("Commands() = %q", got)
+	}
+
+	proc, err := f.Start(Command("npm", "run", "dev"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found, err := f.FindProcess(proc.Pid()); err != nil || found != proc {
+		t.Errorf("FindProcess(%d) = %v, %v", proc.Pid(), found, err)
+	}
+	if _, err := f.FindProcess(1); err == nil {
+		t.Error("FindProcess found an unknown PID")
+	}
+
+	p := proc.(*FakeProcess)
+	p.Ignore(syscall.SIGTERM)
+	proc.Signal(syscall.SIGTERM) //nolint:errcheck
+	if p.Exited() {
+		t.Error("process exited on an ignored signal")
+	}
+	proc.Kill() //nolint:errcheck
+	if err := proc.Wait(); err != nil || !p.Exited() {
+		t.Errorf("Wait() = %v after Kill", err)
+	}
+	if err := proc.Signal(syscall.SIGTERM); !errors.Is(err, os.ErrProcessDone) {
+		t.Errorf("Signal() after exit = %v, want ErrProcessDone", err)
+	}
+	if got := p.Signals(); !slices.Equal(got, []os.Signal{syscall.SIGTERM, syscall.SIGKILL}) {
+		t.Errorf("Signals() = %v", got)
+	}
+}
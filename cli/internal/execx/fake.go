@@ -0,0 +1,240 @@
+package execx
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"slices"
+	"sync"
+	"syscall"
+)
+
+// Fake is a Runner for tests. It records the commands it's given, answers
+// them with canned output and hands out fake processes.
+type Fake struct {
+	mu      sync.Mutex
+	results map[string]fakeResult
+	procs   map[int]*FakeProcess
+	calls   []Cmd
+	nextPID int
+}
+
+type fakeResult struct {
+	output []byte
+	err    error
+}
+
+// NewFake returns a Fake with no canned output; commands it doesn't know
+// fail as if the executable wasn't found
+func NewFake() *Fake {
+	return &Fake{
+		results: make(map[string]fakeResult),
+		procs:   make(map[int]*FakeProcess),
+		nextPID: 10000,
+	}
+}
+
+// On sets the output and error of a command line, e.g. "ps -p 42 -o command="
+func (f *Fake) On(cmdline, output string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.results[cmdline] = fakeResult{output: []byte(output), err: err}
+}
+
+// AddProcess adds a running process that FindProcess can find
+func (f *Fake) AddProcess(pid int) *FakeProcess {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	p := newFakeProcess(pid)
+	f.procs[pid] = p
+	return p
+}
+
+// Process returns the process with the given PID, if it was added or started
+func (f *Fake) Process(pid int) (*FakeProcess, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	p, ok := f.procs[pid]
+	return p, ok
+}
+
+// Calls returns the commands run so far
+func (f *Fake) Calls() []Cmd {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.calls)
+}
+
+// Commands returns the command lines run so far
+func (f *Fake) Commands() []string {
+	var lines []string
+	for _, c := range f.Calls() {
+		lines = append(lines, c.String())
+	}
+	return lines
+}
+
+func (f *Fake) result(c *Cmd) fakeResult {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, *c)
+	r, ok := f.results[c.String()]
+	if !ok {
+		return fakeResult{err: &exec.Error{Name: c.Name, Err: exec.ErrNotFound}}
+	}
+	return r
+}
+
+// Output returns the command's canned output. Like a real command, it
+// doesn't run once ctx is done.
+func (f *Fake) Output(ctx context.Context, c *Cmd) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r := f.result(c)
+	return r.output, r.err
+}
+
+func (f *Fake) Run(ctx context.Context, c *Cmd) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r := f.result(c)
+	if c.Stdout != nil && len(r.output) > 0 {
+		c.Stdout.Write(r.output) //nolint:errcheck
+	}
+	return r.err
+}
+
+// Start starts a fake process, writing the command's output (if any was
+// set with On) to its stdout. Commands without canned output start fine.
+func (f *Fake) Start(c *Cmd) (Process, error) {
+	r := f.result(c)
+	if r.err != nil {
+		if _, notFound := r.err.(*exec.Error); !notFound {
+			return nil, r.err
+		}
+	}
+	if c.Stdout != nil && len(r.output) > 0 {
+		c.Stdout.Write(r.output) //nolint:errcheck
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	p := newFakeProcess(f.nextPID)
+	f.procs[p.pid] = p
+	f.nextPID++
+	return p, nil
+}
+
+func (f *Fake) FindProcess(pid int) (Process, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if p, ok := f.procs[pid]; ok {
+		return p, nil
+	}
+	return nil, os.ErrProcessDone
+}
+
+// FakeProcess is a process that exits when it's killed or sent a signal
+// it doesn't ignore
+type FakeProcess struct {
+	pid      int
+	mu       sync.Mutex
+	signals  []os.Signal
+	ignored  map[os.Signal]bool
+	exited   chan struct{}
+	released bool
+}
+
+func newFakeProcess(pid int) *FakeProcess {
+	return &FakeProcess{pid: pid, ignored: make(map[os.Signal]bool), exited: make(chan struct{})}
+}
+
+// Ignore makes the process keep running when sent sigs
+func (p *FakeProcess) Ignore(sigs ...os.Signal) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, sig := range sigs {
+		p.ignored[sig] = true
+	}
+}
+
+// Exit makes the process exit
+func (p *FakeProcess) Exit() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.exit()
+}
+
+func (p *FakeProcess) exit() {
+	select {
+	case <-p.exited:
+	default:
+		close(p.exited)
+	}
+}
+
+// Exited returns whether the process has exited
+func (p *FakeProcess) Exited() bool {
+	select {
+	case <-p.exited:
+		return true
+	default:
+		return false
+	}
+}
+
+// Signals returns the signals sent to the process
+func (p *FakeProcess) Signals() []os.Signal {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Clone(p.signals)
+}
+
+// Released returns whether Release was called
+func (p *FakeProcess) Released() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.released
+}
+
+func (p *FakeProcess) Pid() int { return p.pid }
+
+func (p *FakeProcess) Signal(sig os.Signal) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.Exited() {
+		return os.ErrProcessDone
+	}
+	p.signals = append(p.signals, sig)
+	switch {
+	case sig == syscall.SIGKILL:
+		p.exit()
+	case sig == syscall.Signal(0), sig == syscall.SIGCONT, sig == syscall.SIGSTOP, p.ignored[sig]:
+	default:
+		p.exit()
+	}
+	return nil
+}
+
+// SignalGroup signals the process, as the only member of its group
+func (p *FakeProcess) SignalGroup(sig syscall.Signal) error {
+	return p.Signal(sig)
+}
+
+func (p *FakeProcess) Kill() error {
+	return p.Signal(syscall.SIGKILL)
+}
+
+func (p *FakeProcess) Wait() error {
+	<-p.exited
+	return nil
+}
+
+func (p *FakeProcess) Release() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.released = true
+	return nil
+}
@@ -0,0 +1,89 @@
+// Package freeze records which servers are running, so 'grove thaw' can
+// start the same set again later, e.g. after a reboot
+package freeze
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+// Server is a running server as it was frozen
+type Server struct {
+	Name    string   `json:"name"`
+	Path    string   `json:"path"`
+	Command []string `json:"command,omitempty"`
+	Port    int      `json:"port"`
+}
+
+// Snapshot is the set of running servers, and whether the proxy was
+// running, at a point in time
+type Snapshot struct {
+	FrozenAt time.Time `json:"frozen_at"`
+	Proxy    bool      `json:"proxy"`
+	Servers  []Server  `json:"servers"`
+}
+
+// Take snapshots the servers the registry has running, by name
+func Take(reg *registry.Registry, proxyRunning bool) *Snapshot {
+	s := &Snapshot{FrozenAt: time.Now(), Proxy: proxyRunning, Servers: []Server{}}
+	for _, server := range reg.ListRunning() {
+		s.Servers = append(s.Servers, Server{
+			Name:    server.Name,
+			Path:    server.Path,
+			Command: server.Command,
+			Port:    server.Port,
+		})
+	}
+	sort.Slice(s.Servers, func(i, j int) bool { return s.Servers[i].Name < s.Servers[j].Name })
+	return s
+}
+
+// Load reads the snapshot at path
+func Load(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Save writes the snapshot to path, replacing the last one
+func (s *Snapshot) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Same reports whether two snapshots have the same servers and proxy,
+// ignoring when they were taken
+func (s *Snapshot) Same(other *Snapshot) bool {
+	if other == nil || s.Proxy != other.Proxy || len(s.Servers) != len(other.Servers) {
+		return false
+	}
+	for i, server := range s.Servers {
+		o := other.Servers[i]
+		if server.Name != o.Name || server.Path != o.Path || server.Port != o.Port || !slices.Equal(server.Command, o.Command) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,57 @@
+package freeze
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+func TestTake(t *testing.T) {
+	reg, err := registry.LoadFrom(filepath.Join(t.TempDir(), "registry.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []*registry.Server{
+		{Name: "web", Path: "/src/web", Port: 3001, Command: []string{"npm", "run", "dev"}, Status: registry.StatusRunning},
+		{Name: "api", Path: "/src/api", Port: 3002, Status: registry.StatusPaused},
+		{Name: "docs", Path: "/src/docs", Port: 3003, Status: registry.StatusStopped},
+	} {
+		if err := reg.Set(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := Take(reg, true)
+	if !s.Proxy || len(s.Servers) != 2 || s.Servers[0].Name != "api" || s.Servers[1].Name != "web" {
+		t.Fatalf("Take() = %+v, want api and web with the proxy", s)
+	}
+	if got := s.Servers[1].Command; len(got) != 3 || got[2] != "dev" {
+		t.Errorf("web command = %q", got)
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "frozen.json")
+	if _, err := Load(path); !os.IsNotExist(err) {
+		t.Errorf("Load(missing) error = %v, want not exist", err)
+	}
+
+	s := &Snapshot{Proxy: true, Servers: []Server{{Name: "web", Path: "/src/web", Port: 3001, Command: []string{"npm", "run", "dev"}}}}
+	if err := s.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Same(loaded) {
+		t.Errorf("Load() = %+v, want %+v", loaded, s)
+	}
+
+	loaded.Servers[0].Port = 3009
+	if s.Same(loaded) {
+		t.Error("snapshots with different ports should differ")
+	}
+}
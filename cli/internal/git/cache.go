@@ -0,0 +1,120 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// worktreeCache remembers repositories' worktree lists for the life of the
+// process, so the TUI and dashboard refreshing every few seconds don't run
+// `git worktree list` each time. Entries are keyed by the repository's git
+// directory, shared by its main worktree and linked ones, and are dropped
+// once WorktreesModTime changes.
+var worktreeCache = struct {
+	mu      sync.Mutex
+	entries map[string]cachedWorktrees
+}{entries: make(map[string]cachedWorktrees)}
+
+type cachedWorktrees struct {
+	modTime   time.Time
+	worktrees []Worktree
+}
+
+// cachedList returns the cached worktrees of the repository at gitDir if
+// they haven't changed since
+func cachedList(gitDir string, modTime time.Time) ([]Worktree, bool) {
+	if gitDir == "" || modTime.IsZero() {
+		return nil, false
+	}
+	worktreeCache.mu.Lock()
+	defer worktreeCache.mu.Unlock()
+	cached, ok := worktreeCache.entries[gitDir]
+	if !ok || !cached.modTime.Equal(modTime) {
+		return nil, false
+	}
+	return append([]Worktree(nil), cached.worktrees...), true
+}
+
+func cacheList(gitDir string, modTime time.Time, worktrees []Worktree) {
+	if gitDir == "" || modTime.IsZero() {
+		return
+	}
+	worktreeCache.mu.Lock()
+	defer worktreeCache.mu.Unlock()
+	worktreeCache.entries[gitDir] = cachedWorktrees{modTime: modTime, worktrees: append([]Worktree(nil), worktrees...)}
+}
+
+// forgetList drops the cached worktrees of the repository at repo
+func forgetList(repo string) {
+	worktreeCache.mu.Lock()
+	defer worktreeCache.mu.Unlock()
+	delete(worktreeCache.entries, CommonDir(repo))
+}
+
+// CommonDir returns the git directory of the repository a worktree belongs
+// to: the main worktree's .git directory, which linked worktrees point to
+// through their .git file. It returns "" if path isn't the top of a
+// worktree.
+func CommonDir(path string) string {
+	gitPath := filepath.Join(path, ".git")
+	info, err := os.Stat(gitPath)
+	if err != nil {
+		return ""
+	}
+	if info.IsDir() {
+		return gitPath
+	}
+
+	// A linked worktree's .git file says "gitdir: <main>/.git/worktrees/<name>",
+	// whose commondir file leads back to the main .git directory
+	data, err := os.ReadFile(gitPath)
+	if err != nil {
+		return ""
+	}
+	adminDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: ")
+	if !ok {
+		return ""
+	}
+	if !filepath.IsAbs(adminDir) {
+		adminDir = filepath.Join(path, adminDir)
+	}
+	common, err := os.ReadFile(filepath.Join(adminDir, "commondir"))
+	if err != nil {
+		return ""
+	}
+	dir := strings.TrimSpace(string(common))
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(adminDir, dir)
+	}
+	return filepath.Clean(dir)
+}
+
+// WorktreesModTime returns the latest modification time of a repository's
+// git directory and its linked worktrees' admin directories, which change
+// when worktrees are added, removed or switch branches. It's zero if
+// gitDir doesn't exist. Commits don't change it, so a worktree's Head can
+// lag behind; Git.Head is always current.
+func WorktreesModTime(gitDir string) time.Time {
+	info, err := os.Stat(gitDir)
+	if err != nil || !info.IsDir() {
+		return time.Time{}
+	}
+	latest := info.ModTime()
+
+	worktrees := filepath.Join(gitDir, "worktrees")
+	paths := []string{worktrees}
+	if entries, err := os.ReadDir(worktrees); err == nil {
+		for _, entry := range entries {
+			paths = append(paths, filepath.Join(worktrees, entry.Name()))
+		}
+	}
+	for _, p := range paths {
+		if info, err := os.Stat(p); err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest
+}
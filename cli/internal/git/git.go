@@ -0,0 +1,304 @@
+// Package git runs the git operations grove needs on repositories and
+// worktrees behind the Git interface, so commands and discovery can be
+// tested without a repository. Read-only queries that only read refs and
+// commits are answered by go-git in process, saving a git process per
+// worktree on every refresh; the rest run the git binary with a timeout.
+package git
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iheanyi/grove/internal/execx"
+)
+
+const (
+	// StatusTimeout bounds git status in a worktree with a huge or locked
+	// index, which would otherwise hold up whatever is listing worktrees
+	StatusTimeout = 3 * time.Second
+
+	// ChangeTimeout bounds commands that change a repository, like
+	// removing a worktree full of dependencies
+	ChangeTimeout = 5 * time.Minute
+)
+
+// Worktree is an entry of `git worktree list`
+type Worktree struct {
+	Path string `json:"path"`
+	// Head is the commit checked out
+	Head string `json:"head,omitempty"`
+	// Branch is the branch checked out without refs/heads/, or "" when
+	// HEAD is detached or the repository is bare
+	Branch   string `json:"branch,omitempty"`
+	Detached bool   `json:"detached,omitempty"`
+	Bare     bool   `json:"bare,omitempty"`
+	Locked   bool   `json:"locked,omitempty"`
+	// Prunable is set when the worktree's directory is gone
+	Prunable bool `json:"prunable,omitempty"`
+}
+
+// Commit is a commit's hash and summary
+type Commit struct {
+	Hash    string
+	Subject string
+	Author  string
+	Time    time.Time
+}
+
+// DiffStat is the size of a diff
+type DiffStat struct {
+	Files   int
+	Added   int
+	Removed int
+}
+
+// AddOptions says what a new worktree checks out
+type AddOptions struct {
+	// Commit is the commit-ish to check out, e.g. a branch; "" is HEAD
+	Commit string
+	// NewBranch creates a branch of this name at Commit and checks it out
+	NewBranch string
+	// Detach checks Commit out with a detached HEAD
+	Detach bool
+	// NoCheckout leaves the worktree's files unpopulated
+	NoCheckout bool
+}
+
+// Git runs git operations. Paths are worktree directories, or any
+// directory inside one, except where a main repository is asked for.
+type Git interface {
+	// Head returns the hash of the commit checked out
+	Head(ctx context.Context, dir string) (string, error)
+	// LastCommit returns the commit checked out
+	LastCommit(ctx context.Context, dir string) (*Commit, error)
+	// Upstream returns the upstream of the branch checked out, e.g.
+	// "origin/main", or "" if it has none
+	Upstream(ctx context.Context, dir string) (string, error)
+	// CountCommits counts the commits in a revision range, e.g.
+	// "origin/main..HEAD"
+	CountCommits(ctx context.Context, dir, revRange string) (int, error)
+	// DirtyFiles returns how many files have uncommitted changes,
+	// untracked files included
+	DirtyFiles(ctx context.Context, dir string) (int, error)
+	// DiffStat returns the size of `git diff` with revs: the uncommitted
+	// changes against revs[0], or between two commits
+	DiffStat(ctx context.Context, dir string, revs ...string) (DiffStat, error)
+	// Worktrees lists the worktrees of the repository whose main or
+	// linked worktree is at repo, the main one first
+	Worktrees(ctx context.Context, repo string) ([]Worktree, error)
+	// AddWorktree adds a linked worktree at path to the repository at repo
+	AddWorktree(ctx context.Context, repo, path string, opts AddOptions) error
+	// MoveWorktree moves a linked worktree of the repository at repo
+	MoveWorktree(ctx context.Context, repo, from, to string) error
+	// RemoveWorktree removes a linked worktree of the main repository at
+	// repo. Without force, git refuses if it has uncommitted changes.
+	RemoveWorktree(ctx context.Context, repo, path string, force bool) error
+	// PruneWorktrees drops the metadata of worktrees whose directories
+	// are gone
+	PruneWorktrees(ctx context.Context, repo string) error
+	// DeleteBranch deletes a local branch. Without force, git refuses if
+	// it isn't merged.
+	DeleteBranch(ctx context.Context, repo, branch string, force bool) error
+}
+
+// Client is a Git that answers read-only queries with go-git and runs the
+// git binary with its runner for the rest, and for repositories go-git
+// can't open. Worktree lists are shared by all Clients in the process
+// until the repository's worktrees change.
+type Client struct {
+	runner execx.Runner
+}
+
+// New returns a Client running git with r
+func New(r execx.Runner) *Client {
+	return &Client{runner: r}
+}
+
+// Error is a git command's failure, with what it printed on stderr
+type Error struct {
+	Args   []string
+	Stderr string
+	Err    error
+}
+
+func (e *Error) Error() string {
+	if e.Stderr != "" {
+		return e.Stderr
+	}
+	return "git " + strings.Join(e.Args, " ") + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// query returns a Cmd running git in the worktree at dir, timing out after
+// timeout
+func query(dir string, timeout time.Duration, args ...string) *execx.Cmd {
+	cmd := execx.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Timeout = timeout
+	return cmd
+}
+
+// inRepo returns a Cmd running git in the main repository at repo, timing
+// out after timeout
+func inRepo(repo string, timeout time.Duration, args ...string) *execx.Cmd {
+	cmd := execx.Command("git", args...)
+	cmd.Dir = repo
+	cmd.Timeout = timeout
+	return cmd
+}
+
+// output runs a git command and returns its standard output
+func (c *Client) output(ctx context.Context, cmd *execx.Cmd) ([]byte, error) {
+	out, err := c.runner.Output(ctx, cmd)
+	if err != nil {
+		gitErr := &Error{Args: cmd.Args, Err: err}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			gitErr.Stderr = strings.TrimSpace(string(exitErr.Stderr))
+		}
+		return nil, gitErr
+	}
+	return out, nil
+}
+
+func (c *Client) Head(ctx context.Context, dir string) (string, error) {
+	if commit, err := nativeLastCommit(dir); err == nil {
+		return commit.Hash, nil
+	}
+	out, err := c.output(ctx, query(dir, execx.QueryTimeout, "rev-parse", "HEAD"))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func (c *Client) LastCommit(ctx context.Context, dir string) (*Commit, error) {
+	if commit, err := nativeLastCommit(dir); err == nil {
+		return commit, nil
+	}
+	out, err := c.output(ctx, query(dir, execx.QueryTimeout, "log", "-1", "--format=%H%x00%s%x00%an%x00%ct"))
+	if err != nil {
+		return nil, err
+	}
+	fields := strings.SplitN(strings.TrimSpace(string(out)), "\x00", 4)
+	if len(fields) != 4 {
+		return nil, &Error{Args: []string{"log", "-1"}, Err: errors.New("unexpected output")}
+	}
+	commit := &Commit{Hash: fields[0], Subject: fields[1], Author: fields[2]}
+	if secs, err := strconv.ParseInt(fields[3], 10, 64); err == nil {
+		commit.Time = time.Unix(secs, 0)
+	}
+	return commit, nil
+}
+
+func (c *Client) Upstream(ctx context.Context, dir string) (string, error) {
+	if upstream, err := nativeUpstream(dir); err == nil {
+		return upstream, nil
+	}
+	out, err := c.output(ctx, query(dir, execx.QueryTimeout, "rev-parse", "--abbrev-ref", "@{upstream}"))
+	var gitErr *Error
+	if errors.As(err, &gitErr) && gitErr.Stderr != "" {
+		return "", nil // git explains there's no upstream
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func (c *Client) CountCommits(ctx context.Context, dir, revRange string) (int, error) {
+	out, err := c.output(ctx, query(dir, execx.QueryTimeout, "rev-list", "--count", revRange))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(out)))
+}
+
+func (c *Client) DirtyFiles(ctx context.Context, dir string) (int, error) {
+	// go-git's status hashes every file and ignores global excludes, so
+	// git's own is both faster and right
+	out, err := c.output(ctx, query(dir, StatusTimeout, "status", "--porcelain", "-z"))
+	if err != nil {
+		return 0, err
+	}
+	return CountPorcelainZ(out), nil
+}
+
+func (c *Client) DiffStat(ctx context.Context, dir string, revs ...string) (DiffStat, error) {
+	out, err := c.output(ctx, query(dir, execx.QueryTimeout, append([]string{"diff", "--shortstat"}, revs...)...))
+	if err != nil {
+		return DiffStat{}, err
+	}
+	return ParseDiffStat(string(out)), nil
+}
+
+func (c *Client) Worktrees(ctx context.Context, repo string) ([]Worktree, error) {
+	gitDir := CommonDir(repo)
+	modTime := WorktreesModTime(gitDir)
+	if worktrees, ok := cachedList(gitDir, modTime); ok {
+		return worktrees, nil
+	}
+
+	out, err := c.output(ctx, inRepo(repo, execx.QueryTimeout, "worktree", "list", "--porcelain"))
+	if err != nil {
+		return nil, err
+	}
+	worktrees := ParseWorktrees(string(out))
+	cacheList(gitDir, modTime, worktrees)
+	return worktrees, nil
+}
+
+func (c *Client) AddWorktree(ctx context.Context, repo, path string, opts AddOptions) error {
+	args := []string{"worktree", "add"}
+	if opts.NewBranch != "" {
+		args = append(args, "-b", opts.NewBranch)
+	}
+	if opts.Detach {
+		args = append(args, "--detach")
+	}
+	if opts.NoCheckout {
+		args = append(args, "--no-checkout")
+	}
+	args = append(args, path)
+	if opts.Commit != "" {
+		args = append(args, opts.Commit)
+	}
+	_, err := c.output(ctx, inRepo(repo, ChangeTimeout, args...))
+	forgetList(repo)
+	return err
+}
+
+func (c *Client) MoveWorktree(ctx context.Context, repo, from, to string) error {
+	_, err := c.output(ctx, inRepo(repo, ChangeTimeout, "worktree", "move", from, to))
+	forgetList(repo)
+	return err
+}
+
+func (c *Client) RemoveWorktree(ctx context.Context, repo, path string, force bool) error {
+	args := []string{"worktree", "remove", path}
+	if force {
+		args = append(args, "--force")
+	}
+	_, err := c.output(ctx, inRepo(repo, ChangeTimeout, args...))
+	forgetList(repo)
+	return err
+}
+
+func (c *Client) PruneWorktrees(ctx context.Context, repo string) error {
+	_, err := c.output(ctx, inRepo(repo, execx.QueryTimeout, "worktree", "prune"))
+	forgetList(repo)
+	return err
+}
+
+func (c *Client) DeleteBranch(ctx context.Context, repo, branch string, force bool) error {
+	flag := "-d"
+	if force {
+		flag = "-D"
+	}
+	_, err := c.output(ctx, inRepo(repo, execx.QueryTimeout, "branch", flag, branch))
+	return err
+}
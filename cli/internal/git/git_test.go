@@ -0,0 +1,309 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iheanyi/grove/internal/execx"
+)
+
+func gitRun(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=grove", "GIT_AUTHOR_EMAIL=grove@example.com",
+		"GIT_COMMITTER_NAME=grove", "GIT_COMMITTER_EMAIL=grove@example.com")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	origin, repo, feature := filepath.Join(root, "origin"), filepath.Join(root, "app"), filepath.Join(root, "app-feature")
+	gitRun(t, root, "init", "-q", "--bare", "-b", "main", origin)
+	gitRun(t, root, "clone", "-q", origin, repo)
+	if err := os.WriteFile(filepath.Join(repo, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, repo, "add", ".")
+	gitRun(t, repo, "commit", "-q", "-m", "Initial commit\n\nWith a body")
+	gitRun(t, repo, "push", "-q", "-u", "origin", "main")
+	c := New(execx.OS)
+	if err := c.AddWorktree(ctx, repo, feature, AddOptions{NewBranch: "feature", Commit: "main"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(feature, "feature.go"), []byte("package main\n\nfunc f() {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitRun(t, feature, "add", ".")
+	gitRun(t, feature, "commit", "-q", "-m", "Add feature")
+
+	// go-git answers for linked worktrees too, without falling back to git
+	if _, err := nativeLastCommit(feature); err != nil {
+		t.Errorf("nativeLastCommit() = %v", err)
+	}
+	if upstream, err := nativeUpstream(repo); err != nil || upstream != "origin/main" {
+		t.Errorf("nativeUpstream() = %q, %v", upstream, err)
+	}
+
+	head, err := c.Head(ctx, feature)
+	if want := gitRun(t, feature, "rev-parse", "HEAD"); err != nil || head != want {
+		t.Errorf("Head() = %q, %v; want %q", head, err, want)
+	}
+	if commit, err := c.LastCommit(ctx, filepath.Join(feature, ".")); err != nil || commit.Subject != "Add feature" || commit.Author != "grove" {
+		t.Errorf("LastCommit() = %+v, %v", commit, err)
+	}
+	if commit, err := c.LastCommit(ctx, repo); err != nil || commit.Subject != "Initial commit" {
+		t.Errorf("LastCommit(main) = %+v, %v", commit, err)
+	}
+
+	if upstream, err := c.Upstream(ctx, repo); err != nil || upstream != "origin/main" {
+		t.Errorf("Upstream() = %q, %v; want origin/main", upstream, err)
+	}
+	if upstream, err := c.Upstream(ctx, feature); err != nil || upstream != "" {
+		t.Errorf("Upstream(no upstream) = %q, %v; want none", upstream, err)
+	}
+	if n, err := c.CountCommits(ctx, feature, "origin/main..HEAD"); err != nil || n != 1 {
+		t.Errorf("CountCommits() = %d, %v; want 1", n, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(feature, "main.go"), []byte("package app\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(feature, "new.go"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := c.DirtyFiles(ctx, feature); err != nil || n != 2 {
+		t.Errorf("DirtyFiles() = %d, %v; want 2", n, err)
+	}
+	if stat, err := c.DiffStat(ctx, feature, "HEAD"); err != nil || stat != (DiffStat{Files: 1, Added: 1, Removed: 1}) {
+		t.Errorf("DiffStat(HEAD) = %+v, %v", stat, err)
+	}
+	if stat, err := c.DiffStat(ctx, feature, "main", "HEAD"); err != nil || stat != (DiffStat{Files: 1, Added: 3}) {
+		t.Errorf("DiffStat(main, HEAD) = %+v, %v", stat, err)
+	}
+
+	worktrees, err := c.Worktrees(ctx, repo)
+	if err != nil || len(worktrees) != 2 {
+		t.Fatalf("Worktrees() = %+v, %v", worktrees, err)
+	}
+	if worktrees[1].Branch != "feature" || worktrees[1].Head != head {
+		t.Errorf("Worktrees()[1] = %+v", worktrees[1])
+	}
+
+	moved := filepath.Join(root, "app-moved")
+	if err := c.MoveWorktree(ctx, repo, feature, moved); err != nil {
+		t.Fatal(err)
+	}
+	if worktrees, _ := c.Worktrees(ctx, repo); len(worktrees) != 2 || worktrees[1].Path != moved {
+		t.Errorf("Worktrees() after moving = %+v", worktrees)
+	}
+	feature = moved
+
+	if err := c.RemoveWorktree(ctx, repo, feature, false); err == nil || !strings.Contains(err.Error(), "modified or untracked files") {
+		t.Errorf("RemoveWorktree() = %v, want git's refusal", err)
+	}
+	if err := c.RemoveWorktree(ctx, repo, feature, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.PruneWorktrees(ctx, repo); err != nil {
+		t.Fatal(err)
+	}
+	if worktrees, _ := c.Worktrees(ctx, repo); len(worktrees) != 1 {
+		t.Errorf("Worktrees() after removing = %+v", worktrees)
+	}
+
+	// The unmerged branch needs force to delete
+	if err := c.DeleteBranch(ctx, repo, "feature", false); err == nil {
+		t.Error("DeleteBranch() of an unmerged branch = nil, want git's refusal")
+	}
+	if err := c.DeleteBranch(ctx, repo, "feature", true); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClient_AddWorktreeArgs(t *testing.T) {
+	ctx := context.Background()
+	fake := execx.NewFake()
+	c := New(fake)
+	tests := []struct {
+		opts AddOptions
+		want string
+	}{
+		{AddOptions{Commit: "origin/feature"}, "git worktree add /wt origin/feature"},
+		{AddOptions{NewBranch: "feature", Commit: "main"}, "git worktree add -b feature /wt main"},
+		{AddOptions{Detach: true, Commit: "abc123"}, "git worktree add --detach /wt abc123"},
+		{AddOptions{Commit: "main", NoCheckout: true}, "git worktree add --no-checkout /wt main"},
+	}
+	for _, tt := range tests {
+		fake.On(tt.want, "", nil)
+		if err := c.AddWorktree(ctx, "/repo", "/wt", tt.opts); err != nil {
+			t.Errorf("AddWorktree(%+v) = %v, want %q", tt.opts, err, tt.want)
+		}
+	}
+}
+
+func TestClient_Fake(t *testing.T) {
+	// go-git can't open a directory that isn't a repository, so every
+	// query reaches the runner
+	ctx := context.Background()
+	dir := t.TempDir()
+	fake := execx.NewFake()
+	fake.On("git -C "+dir+" rev-parse HEAD", "abc123\n", nil)
+	fake.On("git -C "+dir+" status --porcelain -z", " M a.go\x00?? b.go\x00", nil)
+	c := New(fake)
+
+	if head, err := c.Head(ctx, dir); err != nil || head != "abc123" {
+		t.Errorf("Head() = %q, %v", head, err)
+	}
+	if n, err := c.DirtyFiles(ctx, dir); err != nil || n != 2 {
+		t.Errorf("DirtyFiles() = %d, %v", n, err)
+	}
+	if calls := fake.Calls(); calls[1].Timeout != StatusTimeout {
+		t.Errorf("git status timeout = %v, want %v", calls[1].Timeout, StatusTimeout)
+	}
+	if _, err := c.Worktrees(ctx, dir); err == nil {
+		t.Error("Worktrees() = nil error for a command the fake doesn't know")
+	}
+}
+
+func TestParseWorktrees(t *testing.T) {
+	output := `worktree /code/app
+HEAD abc123
+branch refs/heads/main
+
+worktree /code/app-feature
+HEAD def456
+branch refs/heads/feature/auth
+locked
+
+worktree /code/app-review
+HEAD 789abc
+detached
+prunable gitdir file points to non-existent location
+`
+	worktrees := ParseWorktrees(output)
+	want := []Worktree{
+		{Path: "/code/app", Head: "abc123", Branch: "main"},
+		{Path: "/code/app-feature", Head: "def456", Branch: "feature/auth", Locked: true},
+		{Path: "/code/app-review", Head: "789abc", Detached: true, Prunable: true},
+	}
+	if len(worktrees) != len(want) {
+		t.Fatalf("ParseWorktrees() = %+v", worktrees)
+	}
+	for i := range want {
+		if worktrees[i] != want[i] {
+			t.Errorf("worktrees[%d] = %+v, want %+v", i, worktrees[i], want[i])
+		}
+	}
+}
+
+func TestCountPorcelainZ(t *testing.T) {
+	tests := []struct {
+		output string
+		want   int
+	}{
+		{"", 0},
+		{" M main.go\x00", 1},
+		{" M main.go\x00?? new file.txt\x00A  cmd/a.go\x00", 3},
+		// A rename is followed by its original path
+		{"R  new.go\x00old.go\x00 D gone.go\x00", 2},
+		{"C  copy.go\x00orig.go\x00", 1},
+	}
+	for _, tt := range tests {
+		if got := CountPorcelainZ([]byte(tt.output)); got != tt.want {
+			t.Errorf("CountPorcelainZ(%q) = %d, want %d", tt.output, got, tt.want)
+		}
+	}
+}
+
+func TestParseDiffStat(t *testing.T) {
+	tests := map[string]DiffStat{
+		"":                                    {},
+		" 1 file changed, 1 insertion(+)\n":   {Files: 1, Added: 1},
+		" 3 files changed, 10 deletions(-)\n": {Files: 3, Removed: 10},
+		" a.go | 4 ++--\n b.go | 1 +\n 2 files changed, 3 insertions(+), 2 deletions(-)\n": {Files: 2, Added: 3, Removed: 2},
+	}
+	for output, want := range tests {
+		if got := ParseDiffStat(output); got != want {
+			t.Errorf("ParseDiffStat(%q) = %+v, want %+v", output, got, want)
+		}
+	}
+}
+
+func TestClient_WorktreesCache(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	repo, feature := filepath.Join(root, "app"), filepath.Join(root, "app-feature")
+	admin := filepath.Join(repo, ".git", "worktrees", "app-feature")
+	if err := os.MkdirAll(admin, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(feature, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(admin, "commondir"), []byte("../..\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(feature, ".git"), []byte("gitdir: "+admin+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := CommonDir(feature), filepath.Join(repo, ".git"); got != want {
+		t.Fatalf("CommonDir(linked worktree) = %q, want %q", got, want)
+	}
+
+	fake := execx.NewFake()
+	fake.On("git worktree list --porcelain", "worktree "+repo+"\nbranch refs/heads/main\n\nworktree "+feature+"\nbranch refs/heads/feature\n\n", nil)
+	fake.On("git worktree prune", "", nil)
+	listed := func() int {
+		n := 0
+		for _, c := range fake.Calls() {
+			if c.String() == "git worktree list --porcelain" {
+				n++
+			}
+		}
+		return n
+	}
+
+	// The main and linked worktrees share the repository's list
+	for _, dir := range []string{repo, feature, repo} {
+		if worktrees, err := New(fake).Worktrees(ctx, dir); err != nil || len(worktrees) != 2 {
+			t.Fatalf("Worktrees(%s) = %+v, %v", dir, worktrees, err)
+		}
+	}
+	if n := listed(); n != 1 {
+		t.Errorf("git worktree list ran %d times, want once", n)
+	}
+
+	// Switching a linked worktree's branch touches its admin directory
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(admin, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New(fake).Worktrees(ctx, repo); err != nil {
+		t.Fatal(err)
+	}
+	if n := listed(); n != 2 {
+		t.Errorf("git worktree list ran %d times after a change, want twice", n)
+	}
+
+	// Changes made through a Client drop the list right away
+	if err := New(fake).PruneWorktrees(ctx, repo); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New(fake).Worktrees(ctx, repo); err != nil {
+		t.Fatal(err)
+	}
+	if n := listed(); n != 3 {
+		t.Errorf("git worktree list ran %d times after a prune, want 3", n)
+	}
+}
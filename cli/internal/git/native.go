@@ -0,0 +1,77 @@
+package git
+
+import (
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// open opens the repository of the worktree containing dir with go-git.
+// Linked worktrees keep their HEAD in their own admin directory and
+// everything else in the main repository's.
+func open(dir string) (*gogit.Repository, error) {
+	return gogit.PlainOpenWithOptions(dir, &gogit.PlainOpenOptions{
+		DetectDotGit:          true,
+		EnableDotGitCommonDir: true,
+	})
+}
+
+// nativeLastCommit returns the commit checked out in dir, read by go-git
+func nativeLastCommit(dir string) (*Commit, error) {
+	repo, err := open(dir)
+	if err != nil {
+		return nil, err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, err
+	}
+	c, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, err
+	}
+	subject, _, _ := strings.Cut(strings.TrimSpace(c.Message), "\n")
+	return &Commit{
+		Hash:    c.Hash.String(),
+		Subject: strings.TrimSpace(subject),
+		Author:  c.Author.Name,
+		Time:    c.Committer.When,
+	}, nil
+}
+
+// nativeUpstream returns the upstream of the branch checked out in dir from
+// the repository's config, read by go-git. A detached HEAD has none.
+func nativeUpstream(dir string) (string, error) {
+	repo, err := open(dir)
+	if err != nil {
+		return "", err
+	}
+	head, err := repo.Reference(plumbing.HEAD, false)
+	if err != nil {
+		return "", err
+	}
+	if head.Type() != plumbing.SymbolicReference || !head.Target().IsBranch() {
+		return "", nil
+	}
+	cfg, err := repo.Config()
+	if err != nil {
+		return "", err
+	}
+	branch := cfg.Branches[head.Target().Short()]
+	if branch == nil || branch.Remote == "" || branch.Merge == "" {
+		return "", nil
+	}
+
+	// Like git, only an upstream that exists counts
+	upstream := branch.Merge.Short()
+	ref := branch.Merge
+	if branch.Remote != "." {
+		upstream = branch.Remote + "/" + upstream
+		ref = plumbing.NewRemoteReferenceName(branch.Remote, branch.Merge.Short())
+	}
+	if _, err := repo.Reference(ref, true); err != nil {
+		return "", nil
+	}
+	return upstream, nil
+}
@@ -0,0 +1,90 @@
+package git
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ParseWorktrees parses the output of `git worktree list --porcelain`
+func ParseWorktrees(output string) []Worktree {
+	var worktrees []Worktree
+	var current *Worktree
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		key, value, _ := strings.Cut(line, " ")
+		if key == "worktree" {
+			worktrees = append(worktrees, Worktree{Path: value})
+			current = &worktrees[len(worktrees)-1]
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		switch key {
+		case "HEAD":
+			current.Head = value
+		case "branch":
+			current.Branch = strings.TrimPrefix(value, "refs/heads/")
+		case "detached":
+			current.Detached = true
+		case "bare":
+			current.Bare = true
+		case "locked":
+			current.Locked = true
+		case "prunable":
+			current.Prunable = true
+		}
+	}
+	return worktrees
+}
+
+// CountPorcelainZ counts the entries of `git status --porcelain -z`. Each is
+// "XY path" terminated by NUL; renames and copies are followed by their
+// original path as a separate field.
+func CountPorcelainZ(output []byte) int {
+	count := 0
+	fields := bytes.Split(output, []byte{0})
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		if len(field) < 4 {
+			continue
+		}
+		count++
+		if x, y := field[0], field[1]; x == 'R' || x == 'C' || y == 'R' || y == 'C' {
+			i++
+		}
+	}
+	return count
+}
+
+var (
+	filesChangedPattern = regexp.MustCompile(`(\d+) files? changed`)
+	insertionsPattern   = regexp.MustCompile(`(\d+) insertions?`)
+	deletionsPattern    = regexp.MustCompile(`(\d+) deletions?`)
+)
+
+// ParseDiffStat parses the summary line of `git diff --stat` or
+// `--shortstat`: " N files changed, M insertions(+), P deletions(-)"
+func ParseDiffStat(output string) DiffStat {
+	var stat DiffStat
+	lines := strings.Split(output, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if !strings.Contains(line, "changed") {
+			continue
+		}
+		if match := filesChangedPattern.FindStringSubmatch(line); match != nil {
+			stat.Files, _ = strconv.Atoi(match[1])
+		}
+		if match := insertionsPattern.FindStringSubmatch(line); match != nil {
+			stat.Added, _ = strconv.Atoi(match[1])
+		}
+		if match := deletionsPattern.FindStringSubmatch(line); match != nil {
+			stat.Removed, _ = strconv.Atoi(match[1])
+		}
+		break
+	}
+	return stat
+}
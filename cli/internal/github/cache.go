@@ -0,0 +1,144 @@
+package github
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// maxConcurrentFetches bounds the number of gh/glab processes run at once
+const maxConcurrentFetches = 4
+
+// Cache is a file-backed cache of branch info with a TTL, so repeated
+// listings don't hit the GitHub/GitLab API rate limits
+type Cache struct {
+	path    string
+	ttl     time.Duration
+	now     func() time.Time
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	Info      *BranchInfo `json:"info"`
+	FetchedAt time.Time   `json:"fetched_at"`
+}
+
+// NewCache loads the cache at path. A missing or unreadable file starts an
+// empty cache. A ttl of zero disables caching.
+func NewCache(path string, ttl time.Duration) *Cache {
+	c := &Cache{
+		path:    path,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]cacheEntry),
+	}
+
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &c.entries)
+	}
+
+	return c
+}
+
+// cacheKey identifies a branch of a repository checkout
+func cacheKey(dir, branch string) string {
+	return dir + "\x00" + branch
+}
+
+// Get returns the cached info for a branch if it hasn't expired
+func (c *Cache) Get(dir, branch string) (*BranchInfo, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[cacheKey(dir, branch)]
+	if !ok || c.now().Sub(entry.FetchedAt) > c.ttl {
+		return nil, false
+	}
+	return entry.Info, true
+}
+
+// Set records the info for a branch
+func (c *Cache) Set(dir, branch string, info *BranchInfo) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[cacheKey(dir, branch)] = cacheEntry{Info: info, FetchedAt: c.now()}
+}
+
+// Save writes the cache to disk, dropping expired entries
+func (c *Cache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, entry := range c.entries {
+		if c.now().Sub(entry.FetchedAt) > c.ttl {
+			delete(c.entries, key)
+		}
+	}
+
+	data, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, data, 0644)
+}
+
+// Lookup identifies a branch to fetch info for
+type Lookup struct {
+	Dir    string
+	Branch string
+}
+
+// FetchAll returns branch info for each lookup, keyed by directory. Cached
+// entries are used when fresh; the rest are fetched concurrently and cached.
+func FetchAll(cache *Cache, lookups []Lookup) map[string]*BranchInfo {
+	result := make(map[string]*BranchInfo, len(lookups))
+
+	var misses []Lookup
+	for _, l := range lookups {
+		if l.Branch == "" {
+			continue
+		}
+		if info, ok := cache.Get(l.Dir, l.Branch); ok {
+			result[l.Dir] = info
+			continue
+		}
+		misses = append(misses, l)
+	}
+
+	if len(misses) == 0 {
+		return result
+	}
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, maxConcurrentFetches)
+	)
+	for _, l := range misses {
+		wg.Add(1)
+		go func(l Lookup) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			info := GetBranchInfoForDir(l.Dir, l.Branch)
+			cache.Set(l.Dir, l.Branch, info)
+
+			mu.Lock()
+			result[l.Dir] = info
+			mu.Unlock()
+		}(l)
+	}
+	wg.Wait()
+
+	_ = cache.Save()
+	return result
+}
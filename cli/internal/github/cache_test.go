@@ -0,0 +1,74 @@
+package github
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCache_TTL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pr-cache.json")
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	c := NewCache(path, 5*time.Minute)
+	c.now = func() time.Time { return now }
+
+	info := &BranchInfo{PR: &PRInfo{Number: 42, State: "OPEN"}}
+	c.Set("/repo", "feature", info)
+
+	if got, ok := c.Get("/repo", "feature"); !ok || got.PR.Number != 42 {
+		t.Fatalf("Get() = %v, %v; want cached PR #42", got, ok)
+	}
+	if _, ok := c.Get("/other", "feature"); ok {
+		t.Error("Get() for a different directory should miss")
+	}
+
+	now = now.Add(6 * time.Minute)
+	if _, ok := c.Get("/repo", "feature"); ok {
+		t.Error("Get() should miss after the TTL expires")
+	}
+}
+
+func TestCache_Persists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pr-cache.json")
+
+	c := NewCache(path, time.Hour)
+	c.Set("/repo", "feature", &BranchInfo{
+		PR: &PRInfo{Number: 7, State: "MERGED"},
+		CI: &CIStatus{State: "success"},
+	})
+	if err := c.Save(); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	loaded := NewCache(path, time.Hour)
+	got, ok := loaded.Get("/repo", "feature")
+	if !ok {
+		t.Fatal("expected entry to survive a reload")
+	}
+	if got.PR.Number != 7 || got.PR.State != "MERGED" || got.CI.State != "success" {
+		t.Errorf("reloaded entry = %+v", got)
+	}
+}
+
+func TestFetchAll_UsesCache(t *testing.T) {
+	c := NewCache(filepath.Join(t.TempDir(), "pr-cache.json"), time.Hour)
+	c.Set("/a", "feature-a", &BranchInfo{PR: &PRInfo{Number: 1}})
+	c.Set("/b", "feature-b", nil)
+
+	got := FetchAll(c, []Lookup{
+		{Dir: "/a", Branch: "feature-a"},
+		{Dir: "/b", Branch: "feature-b"},
+		{Dir: "/c"}, // no branch: skipped
+	})
+
+	if len(got) != 2 {
+		t.Fatalf("FetchAll() returned %d entries, want 2", len(got))
+	}
+	if got["/a"] == nil || got["/a"].PR.Number != 1 {
+		t.Errorf("got[/a] = %+v, want PR #1", got["/a"])
+	}
+	if got["/b"] != nil {
+		t.Errorf("got[/b] = %+v, want cached nil (no PR)", got["/b"])
+	}
+}
@@ -1,13 +1,37 @@
-// Package github provides GitHub integration via the gh CLI
+// Package github provides GitHub integration via the gh CLI, and GitLab
+// merge request status via the glab CLI
 package github
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
+
+	"github.com/iheanyi/grove/internal/execx"
 )
 
+// token is an optional API token passed to gh as GH_TOKEN. When empty, gh
+// uses its own authentication (gh auth login).
+var token string
+
+// SetToken sets the API token used for gh commands
+func SetToken(t string) {
+	token = t
+}
+
+// ghCommand builds a gh command that runs in dir (if set) with the configured token
+func ghCommand(dir string, args ...string) *exec.Cmd {
+	cmd := exec.Command("gh", args...)
+	cmd.Dir = dir
+	if token != "" {
+		cmd.Env = append(os.Environ(), "GH_TOKEN="+token)
+	}
+	return cmd
+}
+
 // PRInfo contains pull request information
 type PRInfo struct {
 	Number       int    `json:"number"`
@@ -20,21 +44,20 @@ type PRInfo struct {
 
 // CIStatus represents CI status for a branch
 type CIStatus struct {
-	State      string // success, failure, pending, none
-	Conclusion string // success, failure, canceled, skipped, etc.
-	URL        string
+	State      string `json:"state"`                // success, failure, pending, none
+	Conclusion string `json:"conclusion,omitempty"` // success, failure, canceled, skipped, etc.
+	URL        string `json:"url,omitempty"`
 }
 
 // BranchInfo contains GitHub info for a branch
 type BranchInfo struct {
-	PR *PRInfo
-	CI *CIStatus
+	PR *PRInfo   `json:"pr,omitempty"`
+	CI *CIStatus `json:"ci,omitempty"`
 }
 
 // ghCLIAvailable checks if gh CLI is installed and authenticated
 func ghCLIAvailable() bool {
-	cmd := exec.Command("gh", "auth", "status")
-	return cmd.Run() == nil
+	return ghCommand("", "auth", "status").Run() == nil
 }
 
 // GetBranchInfo fetches PR and CI info for a branch
@@ -42,14 +65,29 @@ func GetBranchInfo(branch string) *BranchInfo {
 	if !ghCLIAvailable() {
 		return nil
 	}
+	return getBranchInfo("", branch)
+}
 
+// GetBranchInfoForDir fetches PR (or GitLab MR) and CI info for a branch of
+// the repository checked out at dir
+func GetBranchInfoForDir(dir, branch string) *BranchInfo {
+	if IsGitLabRemote(dir) {
+		return getGitLabBranchInfo(dir, branch)
+	}
+	if !ghCLIAvailable() {
+		return nil
+	}
+	return getBranchInfo(dir, branch)
+}
+
+func getBranchInfo(dir, branch string) *BranchInfo {
 	info := &BranchInfo{}
 
 	// Get PR info
-	info.PR = getPRForBranch(branch)
+	info.PR = getPRForBranch(dir, branch)
 
 	// Get CI status
-	info.CI = getCIStatus(branch)
+	info.CI = getCIStatus(dir, branch)
 
 	return info
 }
@@ -69,10 +107,11 @@ func GetBranchInfoBatch(branches []string) map[string]*BranchInfo {
 	return result
 }
 
-func getPRForBranch(branch string) *PRInfo {
-	// Use gh pr list to find PR for this branch
-	cmd := exec.Command("gh", "pr", "list",
+func getPRForBranch(dir, branch string) *PRInfo {
+	// Use gh pr list to find PR for this branch (including merged/closed)
+	cmd := ghCommand(dir, "pr", "list",
 		"--head", branch,
+		"--state", "all",
 		"--json", "number,title,url,state,isDraft,reviewDecision",
 		"--limit", "1")
 
@@ -120,24 +159,25 @@ func getPRForBranch(branch string) *PRInfo {
 	return pr
 }
 
-func getCIStatus(branch string) *CIStatus {
+func getCIStatus(dir, branch string) *CIStatus {
 	// Get the latest commit SHA for the branch
-	cmd := exec.Command("git", "rev-parse", branch)
-	shaOutput, err := cmd.Output()
+	revParse := execx.Query("git", "rev-parse", branch)
+	revParse.Dir = dir
+	shaOutput, err := execx.OS.Output(context.Background(), revParse)
 	if err != nil {
 		return nil
 	}
 	sha := strings.TrimSpace(string(shaOutput))
 
 	// Use gh api to get check runs for the commit
-	cmd = exec.Command("gh", "api",
+	cmd := ghCommand(dir, "api",
 		"repos/{owner}/{repo}/commits/"+sha+"/check-runs",
 		"--jq", ".check_runs | map({name, status, conclusion}) | first")
 
 	output, err := cmd.Output()
 	if err != nil {
 		// Try status API instead (for older status checks)
-		return getCIStatusFromStatus(sha)
+		return getCIStatusFromStatus(dir, sha)
 	}
 
 	var checkRun struct {
@@ -161,9 +201,9 @@ func getCIStatus(branch string) *CIStatus {
 	return status
 }
 
-func getCIStatusFromStatus(sha string) *CIStatus {
+func getCIStatusFromStatus(dir, sha string) *CIStatus {
 	// Fallback to combined status API
-	cmd := exec.Command("gh", "api",
+	cmd := ghCommand(dir, "api",
 		"repos/{owner}/{repo}/commits/"+sha+"/status",
 		"--jq", ".state")
 
@@ -182,6 +222,77 @@ func getCIStatusFromStatus(sha string) *CIStatus {
 	}
 }
 
+// CreatePROptions configures a new pull request
+// PullRequest is the pull request checked out by 'grove new --pr'
+type PullRequest struct {
+	Number      int    `json:"number"`
+	Title       string `json:"title"`
+	URL         string `json:"url"`
+	State       string `json:"state"`
+	HeadRefName string `json:"headRefName"`
+}
+
+// GetPullRequest looks up a pull request of the repository at dir by number
+func GetPullRequest(dir string, number int) (*PullRequest, error) {
+	if !ghCLIAvailable() {
+		return nil, fmt.Errorf("gh CLI not available or not authenticated (run 'gh auth login')")
+	}
+
+	cmd := ghCommand(dir, "pr", "view", fmt.Sprint(number),
+		"--json", "number,title,url,state,headRefName")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("gh pr view %d failed: %w", number, err)
+	}
+
+	var pr PullRequest
+	if err := json.Unmarshal(output, &pr); err != nil {
+		return nil, fmt.Errorf("failed to parse gh output: %w", err)
+	}
+	return &pr, nil
+}
+
+type CreatePROptions struct {
+	// Dir is the worktree directory to run gh in
+	Dir string
+
+	// Branch is the head branch of the pull request
+	Branch string
+
+	// Title and Body are the pull request title and description
+	Title string
+	Body  string
+
+	// Draft creates the pull request as a draft
+	Draft bool
+}
+
+// CreatePR opens a pull request with gh and returns its URL
+func CreatePR(opts CreatePROptions) (string, error) {
+	if !ghCLIAvailable() {
+		return "", fmt.Errorf("gh CLI not available or not authenticated (run 'gh auth login')")
+	}
+
+	args := []string{"pr", "create",
+		"--head", opts.Branch,
+		"--title", opts.Title,
+		"--body", opts.Body,
+	}
+	if opts.Draft {
+		args = append(args, "--draft")
+	}
+
+	cmd := ghCommand(opts.Dir, args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("gh pr create failed: %w\n%s", err, strings.TrimSpace(string(output)))
+	}
+
+	// gh prints the PR URL as the last line of output
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1]), nil
+}
+
 // FormatCIStatus returns a colored status indicator
 func FormatCIStatus(ci *CIStatus) string {
 	if ci == nil {
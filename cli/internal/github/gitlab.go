@@ -0,0 +1,135 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"os/exec"
+	"strings"
+
+	"github.com/iheanyi/grove/internal/execx"
+)
+
+// IsGitLabRemote reports whether the origin remote of the repository at dir
+// points at a GitLab instance
+func IsGitLabRemote(dir string) bool {
+	output, err := execx.OS.Output(context.Background(), execx.Query("git", "-C", dir, "remote", "get-url", "origin"))
+	if err != nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(string(output)), "gitlab")
+}
+
+// glabAvailable checks if the glab CLI is installed
+func glabAvailable() bool {
+	_, err := exec.LookPath("glab")
+	return err == nil
+}
+
+// getGitLabBranchInfo fetches merge request and pipeline status for a branch
+// using the glab CLI, mapped onto the same types as GitHub
+func getGitLabBranchInfo(dir, branch string) *BranchInfo {
+	if !glabAvailable() {
+		return nil
+	}
+
+	return &BranchInfo{
+		PR: getMRForBranch(dir, branch),
+		CI: getGitLabPipeline(dir, branch),
+	}
+}
+
+func getMRForBranch(dir, branch string) *PRInfo {
+	cmd := exec.Command("glab", "api",
+		"projects/:id/merge_requests?state=all&per_page=1&source_branch="+url.QueryEscape(branch))
+	cmd.Dir = dir
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+
+	var mrs []struct {
+		IID                 int    `json:"iid"`
+		Title               string `json:"title"`
+		WebURL              string `json:"web_url"`
+		State               string `json:"state"`
+		Draft               bool   `json:"draft"`
+		DetailedMergeStatus string `json:"detailed_merge_status"`
+	}
+	if err := json.Unmarshal(output, &mrs); err != nil || len(mrs) == 0 {
+		return nil
+	}
+
+	mr := mrs[0]
+	pr := &PRInfo{
+		Number:  mr.IID,
+		Title:   mr.Title,
+		URL:     mr.WebURL,
+		State:   gitLabState(mr.State),
+		IsDraft: mr.Draft,
+	}
+
+	switch mr.DetailedMergeStatus {
+	case "mergeable":
+		pr.ReviewStatus = "approved"
+	case "requested_changes":
+		pr.ReviewStatus = "changes_requested"
+	case "not_approved":
+		pr.ReviewStatus = "pending"
+	default:
+		pr.ReviewStatus = "none"
+	}
+
+	return pr
+}
+
+// gitLabState maps GitLab merge request states onto GitHub's PR states
+func gitLabState(state string) string {
+	switch state {
+	case "opened":
+		return "OPEN"
+	case "merged":
+		return "MERGED"
+	case "closed", "locked":
+		return "CLOSED"
+	default:
+		return strings.ToUpper(state)
+	}
+}
+
+func getGitLabPipeline(dir, branch string) *CIStatus {
+	cmd := exec.Command("glab", "api",
+		"projects/:id/pipelines?per_page=1&ref="+url.QueryEscape(branch))
+	cmd.Dir = dir
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+
+	var pipelines []struct {
+		Status string `json:"status"`
+		WebURL string `json:"web_url"`
+	}
+	if err := json.Unmarshal(output, &pipelines); err != nil || len(pipelines) == 0 {
+		return nil
+	}
+
+	status := &CIStatus{URL: pipelines[0].WebURL, Conclusion: pipelines[0].Status}
+	switch pipelines[0].Status {
+	case "success":
+		status.State = "success"
+	case "failed":
+		status.State = "failure"
+	case "canceled":
+		status.State = "cancelled" //nolint:misspell // match GitHub's conclusion
+	case "skipped":
+		status.State = "skipped"
+	default:
+		// created, waiting_for_resource, preparing, pending, running, manual, scheduled
+		status.State = "pending"
+	}
+
+	return status
+}
@@ -0,0 +1,285 @@
+// Package health probes whether a running server is healthy the way its
+// .grove.yaml health_check says to: an HTTP request, a TCP connection, the
+// gRPC health protocol or a command.
+package health
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iheanyi/grove/internal/project"
+	"github.com/iheanyi/grove/internal/registry"
+	"golang.org/x/net/http2"
+)
+
+// Target is the server a check probes
+type Target struct {
+	Name string
+	Port int
+	// URL is requested by HTTP checks without a path
+	URL string
+	// Dir is the worktree command checks run in
+	Dir string
+}
+
+// httpClient is shared so HTTP checks reuse connections
+var httpClient = &http.Client{
+	Transport: &http.Transport{
+		MaxIdleConns:        10,
+		IdleConnTimeout:     30 * time.Second,
+		MaxIdleConnsPerHost: 2,
+		DialContext: (&net.Dialer{
+			Timeout: 3 * time.Second,
+		}).DialContext,
+	},
+}
+
+// Check probes a target once, returning nil if it's healthy and why it
+// isn't otherwise. ctx bounds how long the probe may take.
+func Check(ctx context.Context, cfg project.HealthCheckConfig, t Target) error {
+	switch probe := cfg.Probe(); probe {
+	case project.HealthCheckHTTP:
+		return checkHTTP(ctx, httpURL(cfg, t))
+	case project.HealthCheckTCP:
+		return checkTCP(ctx, t.Port)
+	case project.HealthCheckGRPC:
+		return checkGRPC(ctx, t.Port, cfg.GRPCService)
+	case project.HealthCheckCommand:
+		return checkCommand(ctx, cfg.Command, t)
+	default:
+		return fmt.Errorf("unknown health check %q (use http, tcp, grpc or command)", probe)
+	}
+}
+
+// CheckServer probes a registered server with the health check its
+// .grove.yaml configures, or an HTTP request to its URL. ok is false when
+// there's nothing to probe.
+func CheckServer(ctx context.Context, server *registry.Server) (ok bool, err error) {
+	var check project.HealthCheckConfig
+	if cfg, err := project.Load(server.Path); err == nil {
+		check = cfg.HealthCheck
+	}
+	if check.Probe() != project.HealthCheckCommand && server.Port == 0 && server.URL == "" {
+		return false, nil
+	}
+	target := Target{Name: server.Name, Port: server.Port, URL: server.URL, Dir: server.Path}
+	return true, Check(ctx, check, target)
+}
+
+// Describe returns what a check probes, e.g. "tcp port 3000"
+func Describe(cfg project.HealthCheckConfig, t Target) string {
+	switch probe := cfg.Probe(); probe {
+	case project.HealthCheckHTTP:
+		return httpURL(cfg, t)
+	case project.HealthCheckTCP:
+		return fmt.Sprintf("tcp port %d", t.Port)
+	case project.HealthCheckGRPC:
+		if cfg.GRPCService != "" {
+			return fmt.Sprintf("grpc health of %s on port %d", cfg.GRPCService, t.Port)
+		}
+		return fmt.Sprintf("grpc health on port %d", t.Port)
+	default:
+		return probe + " " + cfg.Command
+	}
+}
+
+// httpURL is the health check path on the server's port, or its URL when
+// there's no path
+func httpURL(cfg project.HealthCheckConfig, t Target) string {
+	if cfg.Path == "" && t.URL != "" {
+		return t.URL
+	}
+	return fmt.Sprintf("http://localhost:%d/%s", t.Port, strings.TrimPrefix(cfg.Path, "/"))
+}
+
+// checkHTTP passes on any response that isn't a server error
+func checkHTTP(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024)) //nolint:errcheck // Drained to reuse the connection
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 500 {
+		return nil
+	}
+	return fmt.Errorf("%s returned %s", url, resp.Status)
+}
+
+func checkTCP(ctx context.Context, port int) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort("localhost", strconv.Itoa(port)))
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// checkCommand runs a command from the worktree with PORT and GROVE_NAME
+// set, passing when it exits 0
+func checkCommand(ctx context.Context, command string, t Target) error {
+	if command == "" {
+		return errors.New("command health check has no command")
+	}
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = t.Dir
+	cmd.Env = append(os.Environ(), "PORT="+strconv.Itoa(t.Port), "GROVE_NAME="+t.Name)
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	if msg := lastLine(out); msg != "" {
+		return fmt.Errorf("%s: %w: %s", command, err, msg)
+	}
+	return fmt.Errorf("%s: %w", command, err)
+}
+
+func lastLine(out []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// gRPC health protocol (grpc.health.v1) serving statuses
+const (
+	grpcServing        = 1
+	grpcNotServing     = 2
+	grpcServiceUnknown = 3
+)
+
+// checkGRPC calls grpc.health.v1.Health/Check over plaintext HTTP/2, passing
+// when the server (or service) reports SERVING
+func checkGRPC(ctx context.Context, port int, service string) error {
+	transport := &http2.Transport{
+		AllowHTTP: true,
+		// Plaintext (h2c): dial without TLS
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		},
+	}
+	defer transport.CloseIdleConnections()
+
+	// HealthCheckRequest{service = 1}, length-prefixed
+	var msg []byte
+	if service != "" {
+		msg = append([]byte{0x0a}, binary.AppendUvarint(nil, uint64(len(service)))...)
+		msg = append(msg, service...)
+	}
+	body := make([]byte, 5, 5+len(msg))
+	binary.BigEndian.PutUint32(body[1:], uint32(len(msg)))
+	body = append(body, msg...)
+
+	url := fmt.Sprintf("http://localhost:%d/grpc.health.v1.Health/Check", port)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("TE", "trailers")
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("grpc health check returned %s", resp.Status)
+	}
+
+	// Errors come in the trailers, or the headers when there's no body
+	code := resp.Trailer.Get("Grpc-Status")
+	message := resp.Trailer.Get("Grpc-Message")
+	if code == "" {
+		code, message = resp.Header.Get("Grpc-Status"), resp.Header.Get("Grpc-Message")
+	}
+	if code != "" && code != "0" {
+		if code == "12" {
+			return errors.New("server doesn't implement the gRPC health protocol")
+		}
+		return fmt.Errorf("grpc health check failed with status %s: %s", code, message)
+	}
+
+	status, err := grpcHealthStatus(data)
+	if err != nil {
+		return err
+	}
+	switch status {
+	case grpcServing:
+		return nil
+	case grpcNotServing:
+		return errors.New("NOT_SERVING")
+	case grpcServiceUnknown:
+		return fmt.Errorf("unknown service %q", service)
+	default:
+		return fmt.Errorf("serving status %d", status)
+	}
+}
+
+// grpcHealthStatus reads the status (field 1) of a length-prefixed
+// HealthCheckResponse
+func grpcHealthStatus(data []byte) (uint64, error) {
+	if len(data) < 5 {
+		return 0, errors.New("empty grpc health response")
+	}
+	if data[0] != 0 {
+		return 0, errors.New("compressed grpc health response")
+	}
+	n := binary.BigEndian.Uint32(data[1:5])
+	if int(n) > len(data)-5 {
+		return 0, errors.New("truncated grpc health response")
+	}
+	msg := data[5 : 5+n]
+
+	// An empty message is the default status, UNKNOWN
+	var status uint64
+	for len(msg) > 0 {
+		tag, size := binary.Uvarint(msg)
+		if size <= 0 {
+			return 0, errors.New("invalid grpc health response")
+		}
+		msg = msg[size:]
+		field, wireType := tag>>3, tag&7
+
+		switch wireType {
+		case 0: // varint
+			v, size := binary.Uvarint(msg)
+			if size <= 0 {
+				return 0, errors.New("invalid grpc health response")
+			}
+			msg = msg[size:]
+			if field == 1 {
+				status = v
+			}
+		case 2: // length-delimited: skipped
+			l, size := binary.Uvarint(msg)
+			if size <= 0 || uint64(len(msg)-size) < l {
+				return 0, errors.New("invalid grpc health response")
+			}
+			msg = msg[size+int(l):]
+		default:
+			return 0, fmt.Errorf("unexpected wire type %d in grpc health response", wireType)
+		}
+	}
+	return status, nil
+}
@@ -0,0 +1,141 @@
+package health
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iheanyi/grove/internal/project"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+func check(t *testing.T, cfg project.HealthCheckConfig, target Target) error {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return Check(ctx, cfg, target)
+}
+
+func listenerPort(t *testing.T, addr string) int {
+	t.Helper()
+	_, p, err := net.SplitHostPort(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, _ := strconv.Atoi(p)
+	return port
+}
+
+func TestCheck_HTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+	target := Target{Port: listenerPort(t, server.Listener.Addr().String())}
+
+	if err := check(t, project.HealthCheckConfig{Path: "/health"}, target); err != nil {
+		t.Errorf("Check(/health) = %v, want healthy", err)
+	}
+	if err := check(t, project.HealthCheckConfig{Path: "/broken"}, target); err == nil {
+		t.Error("Check(/broken) should fail on a 503")
+	}
+}
+
+func TestCheck_TCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := listenerPort(t, ln.Addr().String())
+
+	cfg := project.HealthCheckConfig{Check: project.HealthCheckTCP}
+	if err := check(t, cfg, Target{Port: port}); err != nil {
+		t.Errorf("Check(tcp) = %v, want healthy", err)
+	}
+	ln.Close()
+	if err := check(t, cfg, Target{Port: port}); err == nil {
+		t.Error("Check(tcp) should fail once nothing listens")
+	}
+}
+
+func TestCheck_Command(t *testing.T) {
+	dir := t.TempDir()
+
+	// check: command is implied by command
+	cfg := project.HealthCheckConfig{Command: `test "$PORT" = 4000 && test "$GROVE_NAME" = api`}
+	if err := check(t, cfg, Target{Name: "api", Port: 4000, Dir: dir}); err != nil {
+		t.Errorf("Check(command) = %v, want healthy", err)
+	}
+
+	cfg = project.HealthCheckConfig{Check: project.HealthCheckCommand, Command: "echo database down; exit 1"}
+	err := check(t, cfg, Target{Dir: dir})
+	if err == nil || !strings.Contains(err.Error(), "database down") {
+		t.Errorf("Check(failing command) = %v, want its output", err)
+	}
+}
+
+// grpcHealthServer answers grpc.health.v1.Health/Check over h2c with the
+// status of the requested service
+func grpcHealthServer(t *testing.T, statuses map[string]uint64) *httptest.Server {
+	t.Helper()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/grpc.health.v1.Health/Check" || r.Header.Get("Content-Type") != "application/grpc" {
+			http.NotFound(w, r)
+			return
+		}
+		var body [512]byte
+		n, _ := r.Body.Read(body[:])
+		service := ""
+		if n > 7 {
+			service = string(body[7:n])
+		}
+
+		w.Header().Set("Content-Type", "application/grpc")
+		w.Header().Set("Trailer", "Grpc-Status")
+		status, ok := statuses[service]
+		if !ok {
+			w.Header().Set("Grpc-Status", "5") // NOT_FOUND, trailers-only
+			return
+		}
+		msg := append([]byte{0x08}, binary.AppendUvarint(nil, status)...)
+		frame := make([]byte, 5, 5+len(msg))
+		binary.BigEndian.PutUint32(frame[1:], uint32(len(msg)))
+		w.Write(append(frame, msg...)) //nolint:errcheck
+		w.Header().Set("Grpc-Status", "0")
+	})
+	server := httptest.NewServer(h2c.NewHandler(handler, &http2.Server{}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCheck_GRPC(t *testing.T) {
+	server := grpcHealthServer(t, map[string]uint64{"": grpcServing, "billing": grpcNotServing})
+	target := Target{Port: listenerPort(t, server.Listener.Addr().String())}
+
+	if err := check(t, project.HealthCheckConfig{Check: project.HealthCheckGRPC}, target); err != nil {
+		t.Errorf("Check(grpc) = %v, want healthy", err)
+	}
+	err := check(t, project.HealthCheckConfig{Check: project.HealthCheckGRPC, GRPCService: "billing"}, target)
+	if err == nil || !strings.Contains(err.Error(), "NOT_SERVING") {
+		t.Errorf("Check(grpc billing) = %v, want NOT_SERVING", err)
+	}
+	err = check(t, project.HealthCheckConfig{Check: project.HealthCheckGRPC, GRPCService: "search"}, target)
+	if err == nil || !strings.Contains(err.Error(), "status 5") {
+		t.Errorf("Check(grpc search) = %v, want the grpc error status", err)
+	}
+}
+
+func TestCheck_Unknown(t *testing.T) {
+	if err := check(t, project.HealthCheckConfig{Check: "ping"}, Target{Port: 1}); err == nil {
+		t.Error("Check(ping) should fail")
+	}
+}
@@ -0,0 +1,118 @@
+package health
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// ansiEscape matches terminal color and cursor sequences, which dev servers
+// put around the URLs they print and which a ready_log_pattern shouldn't
+// have to allow for
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+
+// maxLine is how much of an unterminated line a LogMatcher keeps
+const maxLine = 64 * 1024
+
+// LogMatcher is a writer that watches a server's output, line by line, for
+// the line its ready_log_pattern matches
+type LogMatcher struct {
+	re      *regexp.Regexp
+	matched chan struct{}
+
+	mu      sync.Mutex
+	partial []byte
+	done    bool
+}
+
+// NewLogMatcher returns a LogMatcher for a pattern
+func NewLogMatcher(re *regexp.Regexp) *LogMatcher {
+	return &LogMatcher{re: re, matched: make(chan struct{})}
+}
+
+// Matched is closed once a line has matched
+func (m *LogMatcher) Matched() <-chan struct{} {
+	return m.matched
+}
+
+func (m *LogMatcher) Write(p []byte) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.done {
+		return len(p), nil
+	}
+
+	data := append(m.partial, p...)
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		if m.match(data[:i]) {
+			return len(p), nil
+		}
+		data = data[i+1:]
+	}
+	// Some servers print their ready line without a newline, then redraw it
+	if m.match(data) {
+		return len(p), nil
+	}
+	if len(data) > maxLine {
+		data = data[len(data)-maxLine:]
+	}
+	m.partial = append([]byte(nil), data...)
+	return len(p), nil
+}
+
+func (m *LogMatcher) match(line []byte) bool {
+	line = ansiEscape.ReplaceAll(bytes.TrimRight(line, "\r"), nil)
+	if !m.re.Match(line) {
+		return false
+	}
+	m.done = true
+	m.partial = nil
+	close(m.matched)
+	return true
+}
+
+// WaitForLog follows a log file from offset until a line matches re,
+// returning an error if ctx is done first
+func WaitForLog(ctx context.Context, path string, offset int64, re *regexp.Regexp, poll time.Duration) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	matcher := NewLogMatcher(re)
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := f.Read(buf)
+		if n > 0 {
+			matcher.Write(buf[:n]) //nolint:errcheck // Never fails
+			select {
+			case <-matcher.Matched():
+				return nil
+			default:
+			}
+			continue
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(poll):
+		}
+	}
+}
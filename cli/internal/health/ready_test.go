@@ -0,0 +1,65 @@
+package health
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func matched(m *LogMatcher) bool {
+	select {
+	case <-m.Matched():
+		return true
+	default:
+		return false
+	}
+}
+
+func TestLogMatcher(t *testing.T) {
+	m := NewLogMatcher(regexp.MustCompile(`Local:\s+http://localhost:\d+`))
+
+	m.Write([]byte("  VITE v5.0.0  ready in 300 ms\n\n  \x1b[32m➜\x1b[39m  \x1b[1mLocal\x1b[22m")) //nolint:errcheck
+	if matched(m) {
+		t.Fatal("matched before the line was written")
+	}
+	// The rest of the line arrives in another write, colored
+	m.Write([]byte(":   \x1b[36mhttp://localhost:\x1b[1m5173\x1b[22m/\x1b[39m\n")) //nolint:errcheck
+	if !matched(m) {
+		t.Error("should match the line once it's complete, ignoring colors")
+	}
+
+	// Later output is ignored
+	if n, err := m.Write([]byte("more\n")); n != 5 || err != nil {
+		t.Errorf("Write after matching = %d, %v", n, err)
+	}
+}
+
+func TestWaitForLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api.log")
+	// A previous run's ready line is before the offset
+	if err := os.WriteFile(path, []byte("Listening on :3000\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	re := regexp.MustCompile(`Listening on`)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := WaitForLog(ctx, path, 19, re, 10*time.Millisecond); err == nil {
+		t.Fatal("WaitForLog should time out without a new ready line")
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		f, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+		f.WriteString("Compiling...\nListening on :3000\n") //nolint:errcheck
+		f.Close()
+	}()
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := WaitForLog(ctx, path, 19, re, 10*time.Millisecond); err != nil {
+		t.Errorf("WaitForLog = %v, want the new ready line found", err)
+	}
+}
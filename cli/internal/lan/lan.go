@@ -0,0 +1,62 @@
+// Package lan finds the address other devices on the local network reach
+// this machine at.
+package lan
+
+import (
+	"fmt"
+	"net"
+)
+
+// IP returns this machine's address on the local network: the first
+// private IPv4 address of an interface that's up, preferring it to other
+// non-loopback addresses
+func IP() (net.IP, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list network interfaces: %w", err)
+	}
+
+	var addrs []net.IP
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		ifaddrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, a := range ifaddrs {
+			if ipnet, ok := a.(*net.IPNet); ok {
+				addrs = append(addrs, ipnet.IP)
+			}
+		}
+	}
+
+	if ip := pick(addrs); ip != nil {
+		return ip, nil
+	}
+	return nil, fmt.Errorf("no network address found; is this machine connected to a network?")
+}
+
+// pick chooses the LAN address among an interface's addresses
+func pick(addrs []net.IP) net.IP {
+	var fallback net.IP
+	for _, ip := range addrs {
+		ip4 := ip.To4()
+		if ip4 == nil || ip4.IsLoopback() || ip4.IsLinkLocalUnicast() {
+			continue
+		}
+		if ip4.IsPrivate() {
+			return ip4
+		}
+		if fallback == nil {
+			fallback = ip4
+		}
+	}
+	return fallback
+}
+
+// URL returns the http URL of a port at ip
+func URL(ip net.IP, port int) string {
+	return fmt.Sprintf("http://%s", net.JoinHostPort(ip.String(), fmt.Sprint(port)))
+}
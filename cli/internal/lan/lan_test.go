@@ -0,0 +1,34 @@
+package lan
+
+import (
+	"net"
+	"testing"
+)
+
+func TestPick(t *testing.T) {
+	ips := func(addrs ...string) []net.IP {
+		var out []net.IP
+		for _, a := range addrs {
+			out = append(out, net.ParseIP(a))
+		}
+		return out
+	}
+
+	tests := []struct {
+		addrs []string
+		want  string
+	}{
+		{[]string{"fe80::1", "169.254.3.4", "203.0.113.9", "192.168.1.20"}, "192.168.1.20"},
+		{[]string{"127.0.0.1", "203.0.113.9"}, "203.0.113.9"},
+		{[]string{"fe80::1", "2001:db8::1"}, "<nil>"},
+	}
+	for _, tt := range tests {
+		if got := pick(ips(tt.addrs...)).String(); got != tt.want {
+			t.Errorf("pick(%v) = %s, want %s", tt.addrs, got, tt.want)
+		}
+	}
+
+	if got := URL(net.ParseIP("192.168.1.20"), 13042); got != "http://192.168.1.20:13042" {
+		t.Errorf("URL = %s", got)
+	}
+}
@@ -9,19 +9,56 @@ import (
 	"github.com/iheanyi/grove/internal/styles"
 )
 
-// Colors for different log elements - using shared styles
+// Colors for different log elements - using shared styles, rebuilt when
+// the theme changes
 var (
+	// Log levels
+	ErrorStyle lipgloss.Style
+	WarnStyle  lipgloss.Style
+	InfoStyle  lipgloss.Style
+	DebugStyle lipgloss.Style
+
+	// HTTP Methods
+	GetStyle    lipgloss.Style
+	PostStyle   lipgloss.Style
+	PutStyle    lipgloss.Style
+	PatchStyle  lipgloss.Style
+	DeleteStyle lipgloss.Style
+
+	// Status codes
+	Status2xxStyle lipgloss.Style
+	Status3xxStyle lipgloss.Style
+	Status4xxStyle lipgloss.Style
+	Status5xxStyle lipgloss.Style
+
+	// Other elements
+	TimestampStyle  lipgloss.Style
+	DurationStyle   lipgloss.Style
+	NumberStyle     lipgloss.Style
+	StringStyle     lipgloss.Style
+	KeyStyle        lipgloss.Style
+	ControllerStyle lipgloss.Style
+	PathStyle       lipgloss.Style
+)
+
+func init() {
+	buildStyles()
+	styles.OnChange(buildStyles)
+}
+
+// buildStyles builds the highlighting styles from the theme's colors
+func buildStyles() {
 	// Log levels
 	ErrorStyle = lipgloss.NewStyle().Foreground(styles.Error).Bold(true)
-	WarnStyle  = lipgloss.NewStyle().Foreground(styles.Warning).Bold(true)
-	InfoStyle  = lipgloss.NewStyle().Foreground(styles.Info)
+	WarnStyle = lipgloss.NewStyle().Foreground(styles.Warning).Bold(true)
+	InfoStyle = lipgloss.NewStyle().Foreground(styles.Info)
 	DebugStyle = lipgloss.NewStyle().Foreground(styles.Muted)
 
 	// HTTP Methods
-	GetStyle    = lipgloss.NewStyle().Foreground(styles.Secondary).Bold(true)
-	PostStyle   = lipgloss.NewStyle().Foreground(styles.Info).Bold(true)
-	PutStyle    = lipgloss.NewStyle().Foreground(styles.Warning).Bold(true)
-	PatchStyle  = lipgloss.NewStyle().Foreground(styles.Yellow).Bold(true)
+	GetStyle = lipgloss.NewStyle().Foreground(styles.Secondary).Bold(true)
+	PostStyle = lipgloss.NewStyle().Foreground(styles.Info).Bold(true)
+	PutStyle = lipgloss.NewStyle().Foreground(styles.Warning).Bold(true)
+	PatchStyle = lipgloss.NewStyle().Foreground(styles.Yellow).Bold(true)
 	DeleteStyle = lipgloss.NewStyle().Foreground(styles.Error).Bold(true)
 
 	// Status codes
@@ -31,14 +68,14 @@ var (
 	Status5xxStyle = lipgloss.NewStyle().Foreground(styles.Error).Bold(true)
 
 	// Other elements
-	TimestampStyle  = lipgloss.NewStyle().Foreground(styles.Muted)
-	DurationStyle   = lipgloss.NewStyle().Foreground(styles.PurpleLight)
-	NumberStyle     = lipgloss.NewStyle().Foreground(styles.Cyan)
-	StringStyle     = lipgloss.NewStyle().Foreground(styles.Secondary)
-	KeyStyle        = lipgloss.NewStyle().Foreground(styles.Info)
+	TimestampStyle = lipgloss.NewStyle().Foreground(styles.Muted)
+	DurationStyle = lipgloss.NewStyle().Foreground(styles.PurpleLight)
+	NumberStyle = lipgloss.NewStyle().Foreground(styles.Cyan)
+	StringStyle = lipgloss.NewStyle().Foreground(styles.Secondary)
+	KeyStyle = lipgloss.NewStyle().Foreground(styles.Info)
 	ControllerStyle = lipgloss.NewStyle().Foreground(styles.Yellow).Bold(true)
-	PathStyle       = lipgloss.NewStyle().Foreground(styles.Purple)
-)
+	PathStyle = lipgloss.NewStyle().Foreground(styles.Purple)
+}
 
 // Compiled regex patterns
 var (
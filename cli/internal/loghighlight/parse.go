@@ -0,0 +1,52 @@
+package loghighlight
+
+import "time"
+
+// Log levels returned by Level
+const (
+	LevelError = "error"
+	LevelWarn  = "warn"
+	LevelInfo  = "info"
+	LevelDebug = "debug"
+)
+
+// Level returns the level of a log line, from the level names Highlight
+// colors: "error", "warn", "info" or "debug", or "" if it names none. The
+// most severe level wins.
+func Level(line string) string {
+	switch {
+	case levelError.MatchString(line):
+		return LevelError
+	case levelWarn.MatchString(line):
+		return LevelWarn
+	case levelInfo.MatchString(line):
+		return LevelInfo
+	case levelDebug.MatchString(line):
+		return LevelDebug
+	}
+	return ""
+}
+
+// timestampLayouts are the layouts of timestamps matched by timestampISO,
+// after normalizing the date/time separator to "T"
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02T15:04:05.999999999",
+}
+
+// Timestamp returns the first date and time in a log line. Timestamps
+// without a zone are in local time.
+func Timestamp(line string) (time.Time, bool) {
+	match := timestampISO.FindString(line)
+	if match == "" {
+		return time.Time{}, false
+	}
+	match = match[:10] + "T" + match[11:]
+	for _, layout := range timestampLayouts {
+		if t, err := time.ParseInLocation(layout, match, time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,49 @@
+package loghighlight
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLevel(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"ERROR: something failed", LevelError},
+		{"[fatal] out of memory", LevelError},
+		{"level=warning msg=slow", LevelWarn},
+		{"I, [2024-01-15T10:30:00] INFO -- : started", LevelInfo},
+		{"DEBUG: verbose", LevelDebug},
+		{"INFO retrying after ERROR", LevelError},
+		{"Started GET \"/\" for 127.0.0.1", ""},
+		{"TERRORS and INFORMATION", ""},
+	}
+	for _, tt := range tests {
+		if got := Level(tt.line); got != tt.want {
+			t.Errorf("Level(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestTimestamp(t *testing.T) {
+	tests := []struct {
+		line string
+		want time.Time
+	}{
+		{"2024-01-15T10:30:00Z INFO started", time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)},
+		{"2024-01-15T10:30:00.250+02:00 boom", time.Date(2024, 1, 15, 8, 30, 0, 250e6, time.UTC)},
+		{"[2024-01-15 10:30:00] local", time.Date(2024, 1, 15, 10, 30, 0, 0, time.Local)},
+		{"time=2024-01-15T10:30:00.123456-0500 msg=x", time.Date(2024, 1, 15, 15, 30, 0, 123456e3, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, ok := Timestamp(tt.line)
+		if !ok || !got.Equal(tt.want) {
+			t.Errorf("Timestamp(%q) = %v, %v, want %v", tt.line, got, ok, tt.want)
+		}
+	}
+
+	if _, ok := Timestamp("10:30:00.123 no date"); ok {
+		t.Error("expected no timestamp without a date")
+	}
+}
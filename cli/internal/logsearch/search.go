@@ -0,0 +1,179 @@
+// Package logsearch searches server logs, including rotated ones
+package logsearch
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iheanyi/grove/internal/loghighlight"
+)
+
+// Options filter a search
+type Options struct {
+	// Context is the number of lines shown before and after each match
+	Context int
+
+	// Since and Until limit matches to lines timestamped in the range.
+	// Lines without a timestamp take the last one before them.
+	Since time.Time
+	Until time.Time
+}
+
+// Match is a matching log line with its context
+type Match struct {
+	Server string
+	File   string
+	LineNo int // 1-based, within File
+	Line   string
+	Time   time.Time // Zero if no timestamp precedes the line
+
+	Before []string
+	After  []string
+}
+
+// Files returns a log file and its rotated copies (app.log.1,
+// app.log.2.gz, app.log-20240115, ...), oldest first
+func Files(path string) []string {
+	rotated, _ := filepath.Glob(path + ".*")
+	dated, _ := filepath.Glob(path + "-*")
+	rotated = append(rotated, dated...)
+
+	// Numbered copies are older the higher the number; dated ones the
+	// earlier the date
+	sort.Slice(rotated, func(i, j int) bool {
+		ni, di := rotationSuffix(path, rotated[i])
+		nj, dj := rotationSuffix(path, rotated[j])
+		if ni != nj {
+			return ni > nj
+		}
+		return di < dj
+	})
+
+	var files []string
+	for _, f := range rotated {
+		if info, err := os.Stat(f); err == nil && !info.IsDir() {
+			files = append(files, f)
+		}
+	}
+	if _, err := os.Stat(path); err == nil {
+		files = append(files, path)
+	}
+	return files
+}
+
+// rotationSuffix returns what identifies a rotated copy of path: its
+// number for app.log.1 and app.log.1.gz (-1 if it has none), and otherwise
+// the rest of its name, e.g. "20240115" for app.log-20240115
+func rotationSuffix(path, rotated string) (int, string) {
+	suffix := strings.TrimSuffix(strings.TrimPrefix(rotated, path), ".gz")
+	if n, err := strconv.Atoi(strings.TrimPrefix(suffix, ".")); err == nil && strings.HasPrefix(suffix, ".") {
+		return n, ""
+	}
+	return -1, suffix
+}
+
+// Search returns the lines of server's log files (see Files) that match re
+func Search(server string, files []string, re *regexp.Regexp, opts Options) ([]Match, error) {
+	var matches []Match
+	var last time.Time
+	for _, file := range files {
+		fileMatches, err := searchFile(server, file, re, opts, &last)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, fileMatches...)
+	}
+	return matches, nil
+}
+
+func searchFile(server, path string, re *regexp.Regexp, opts Options, last *time.Time) ([]Match, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			// Not actually gzipped; skip it rather than fail the search
+			return nil, nil
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	var matches []Match
+	var before []string
+	// open are the matches still collecting lines after them
+	var open []int
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		line := scanner.Text()
+		lineNo++
+
+		kept := open[:0]
+		for _, i := range open {
+			matches[i].After = append(matches[i].After, line)
+			if len(matches[i].After) < opts.Context {
+				kept = append(kept, i)
+			}
+		}
+		open = kept
+
+		if ts, ok := loghighlight.Timestamp(line); ok {
+			*last = ts
+		}
+		if inRange(*last, opts) && re.MatchString(line) {
+			matches = append(matches, Match{
+				Server: server,
+				File:   path,
+				LineNo: lineNo,
+				Line:   line,
+				Time:   *last,
+				Before: append([]string(nil), before...),
+			})
+			if opts.Context > 0 {
+				open = append(open, len(matches)-1)
+			}
+		}
+
+		if opts.Context > 0 {
+			before = append(before, line)
+			if len(before) > opts.Context {
+				before = before[1:]
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// inRange returns true if a line timestamped t is within the options'
+// time range
+func inRange(t time.Time, opts Options) bool {
+	if !opts.Since.IsZero() && t.Before(opts.Since) {
+		return false
+	}
+	if !opts.Until.IsZero() && (t.IsZero() || t.After(opts.Until)) {
+		return false
+	}
+	return true
+}
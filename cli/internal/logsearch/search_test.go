@@ -0,0 +1,105 @@
+package logsearch
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func writeLog(t *testing.T, path, content string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if filepath.Ext(path) == ".gz" {
+		gz := gzip.NewWriter(f)
+		defer gz.Close()
+		if _, err := gz.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	for _, name := range []string{"app.log", "app.log.1", "app.log.2.gz", "app.log.10", "app-share.log", "app.log-20240101", "app.log-20231231.gz", "other.log"} {
+		writeLog(t, filepath.Join(dir, name), "")
+	}
+
+	var got []string
+	for _, f := range Files(path) {
+		got = append(got, filepath.Base(f))
+	}
+	want := []string{"app.log.10", "app.log.2.gz", "app.log.1", "app.log-20231231.gz", "app.log-20240101", "app.log"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Files() = %v, want %v", got, want)
+	}
+
+	if got := Files(filepath.Join(dir, "missing.log")); len(got) != 0 {
+		t.Errorf("Files(missing) = %v", got)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	dir := t.TempDir()
+	rotated := filepath.Join(dir, "app.log.1.gz")
+	current := filepath.Join(dir, "app.log")
+	writeLog(t, rotated, "2024-01-15T09:00:00Z ERROR old failure\ntrace 1\n")
+	writeLog(t, current, `2024-01-15T10:00:00Z INFO start
+2024-01-15T10:01:00Z ERROR db timeout
+  at db.go:10
+  at main.go:5
+2024-01-15T10:02:00Z ERROR db timeout again
+2024-01-15T10:03:00Z INFO done
+`)
+	re := regexp.MustCompile(`ERROR`)
+
+	matches, err := Search("app", []string{rotated, current}, re, Options{Context: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 3 {
+		t.Fatalf("got %d matches, want 3: %+v", len(matches), matches)
+	}
+	if matches[0].File != rotated || matches[0].LineNo != 1 || !reflect.DeepEqual(matches[0].After, []string{"trace 1"}) {
+		t.Errorf("rotated match = %+v", matches[0])
+	}
+	m := matches[1]
+	if m.Server != "app" || m.LineNo != 2 || !m.Time.Equal(time.Date(2024, 1, 15, 10, 1, 0, 0, time.UTC)) {
+		t.Errorf("match = %+v", m)
+	}
+	if !reflect.DeepEqual(m.Before, []string{"2024-01-15T10:00:00Z INFO start"}) || !reflect.DeepEqual(m.After, []string{"  at db.go:10"}) {
+		t.Errorf("context = %q / %q", m.Before, m.After)
+	}
+	if !reflect.DeepEqual(matches[2].After, []string{"2024-01-15T10:03:00Z INFO done"}) {
+		t.Errorf("last match after = %q", matches[2].After)
+	}
+
+	// Continuation lines carry the time of the line before them
+	matches, err = Search("app", []string{rotated, current}, regexp.MustCompile(`at main`), Options{
+		Since: time.Date(2024, 1, 15, 10, 0, 30, 0, time.UTC),
+		Until: time.Date(2024, 1, 15, 10, 1, 30, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 || matches[0].LineNo != 4 {
+		t.Errorf("time-filtered matches = %+v", matches)
+	}
+
+	matches, _ = Search("app", []string{rotated, current}, re, Options{Since: time.Date(2024, 1, 15, 10, 1, 30, 0, time.UTC)})
+	if len(matches) != 1 || matches[0].Line != "2024-01-15T10:02:00Z ERROR db timeout again" {
+		t.Errorf("since matches = %+v", matches)
+	}
+}
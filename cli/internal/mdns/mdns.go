@@ -0,0 +1,381 @@
+// Package mdns advertises HTTP servers on the local network with multicast
+// DNS (Bonjour), and browses for the servers others advertise.
+//
+// It implements the small part of RFC 6762 and RFC 6763 needed for that:
+// answering queries for _http._tcp services and their hosts, announcing
+// them when they change, and saying goodbye when they stop. It doesn't
+// probe for name conflicts, so instance and host names include this
+// machine's hostname.
+package mdns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	// ServiceType is the DNS-SD type servers are advertised as
+	ServiceType = "_http._tcp.local."
+
+	// servicesEnumeration lists the service types advertised
+	servicesEnumeration = "_services._dns-sd._udp.local."
+
+	// ttl is how long answers are cached, in seconds
+	ttl = 120
+
+	// pollInterval is how often the advertised services are checked for
+	// changes to announce
+	pollInterval = 5 * time.Second
+)
+
+// group is the mDNS multicast address
+var group = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}
+
+// Service is an HTTP server to advertise
+type Service struct {
+	// Instance names the service, e.g. "feature-auth (laptop)"
+	Instance string
+	// Host is the name the service's address is published under, e.g.
+	// feature-auth.laptop.local.
+	Host string
+	Port int
+	// TXT are key=value attributes
+	TXT []string
+}
+
+// Entry is a service found by Browse
+type Entry struct {
+	Instance string
+	Host     string
+	IP       net.IP
+	Port     int
+	TXT      map[string]string
+}
+
+// Responder answers mDNS queries for services at IP
+type Responder struct {
+	IP net.IP
+	// Services returns the services to advertise. It's called for every
+	// query and announcement, so it can change while the responder runs.
+	Services func() []Service
+}
+
+// Serve answers queries until ctx is canceled, announcing services when
+// they appear and saying goodbye when they go
+func (r *Responder) Serve(ctx context.Context) error {
+	ip4 := r.IP.To4()
+	if ip4 == nil {
+		return fmt.Errorf("mDNS needs an IPv4 address, got %s", r.IP)
+	}
+
+	conn, err := net.ListenMulticastUDP("udp4", nil, group)
+	if err != nil {
+		return fmt.Errorf("failed to join the mDNS group: %w", err)
+	}
+	defer conn.Close()
+
+	// Closing the connection after the goodbye ends the loop below
+	go func() {
+		r.announce(ctx, conn, ip4)
+		conn.Close()
+	}()
+
+	buf := make([]byte, 9000)
+	for {
+		n, from, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		query, unicast, ok := parseQuery(buf[:n])
+		if !ok {
+			continue
+		}
+		msg, ok := answer(query, r.Services(), ip4)
+		if !ok {
+			continue
+		}
+		to := group
+		if unicast || from.Port != group.Port {
+			// Legacy unicast queries (RFC 6762 6.7) get the query's ID
+			// and question back
+			to = from
+			msg.ID = query.ID
+			msg.Questions = query.Questions
+		}
+		if packed, err := msg.Pack(); err == nil {
+			_, _ = conn.WriteToUDP(packed, to)
+		}
+	}
+}
+
+// announce sends every service's records whenever the set changes, and
+// their goodbye (TTL 0) when they go or ctx is canceled
+func (r *Responder) announce(ctx context.Context, conn *net.UDPConn, ip net.IP) {
+	var current []Service
+	send := func(services []Service, recordTTL uint32) {
+		if len(services) == 0 {
+			return
+		}
+		msg := response()
+		for _, s := range services {
+			msg.Answers = append(msg.Answers, serviceRecords(s, ip, recordTTL)...)
+		}
+		if packed, err := msg.Pack(); err == nil {
+			_, _ = conn.WriteToUDP(packed, group)
+		}
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		next := r.Services()
+		var gone []Service
+		for _, s := range current {
+			if !slices.ContainsFunc(next, func(n Service) bool { return sameService(n, s) }) {
+				gone = append(gone, s)
+			}
+		}
+		var added []Service
+		for _, s := range next {
+			if !slices.ContainsFunc(current, func(c Service) bool { return sameService(c, s) }) {
+				added = append(added, s)
+			}
+		}
+		send(gone, 0)
+		send(added, ttl)
+		current = next
+
+		select {
+		case <-ctx.Done():
+			send(current, 0)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func sameService(a, b Service) bool {
+	return a.Instance == b.Instance && a.Host == b.Host && a.Port == b.Port && slices.Equal(a.TXT, b.TXT)
+}
+
+// parseQuery parses a query, reporting whether any question asks for a
+// unicast response
+func parseQuery(packet []byte) (dnsmessage.Message, bool, bool) {
+	var msg dnsmessage.Message
+	if err := msg.Unpack(packet); err != nil || msg.Response || len(msg.Questions) == 0 {
+		return msg, false, false
+	}
+	unicast := false
+	for _, q := range msg.Questions {
+		if q.Class&(1<<15) != 0 {
+			unicast = true
+		}
+	}
+	return msg, unicast, true
+}
+
+// answer builds the response to a query, if it asks about any service
+func answer(query dnsmessage.Message, services []Service, ip net.IP) (dnsmessage.Message, bool) {
+	msg := response()
+	for _, q := range query.Questions {
+		name := strings.ToLower(q.Name.String())
+		all := q.Type == dnsmessage.TypeALL
+		switch {
+		case name == servicesEnumeration && (all || q.Type == dnsmessage.TypePTR) && len(services) > 0:
+			msg.Answers = append(msg.Answers, ptrRecord(servicesEnumeration, ServiceType, ttl))
+		case name == ServiceType && (all || q.Type == dnsmessage.TypePTR):
+			for _, s := range services {
+				records := serviceRecords(s, ip, ttl)
+				msg.Answers = append(msg.Answers, records[0])
+				msg.Additionals = append(msg.Additionals, records[1:]...)
+			}
+		default:
+			for _, s := range services {
+				records := serviceRecords(s, ip, ttl)
+				switch {
+				case name == strings.ToLower(instanceName(s)) && (all || q.Type == dnsmessage.TypeSRV || q.Type == dnsmessage.TypeTXT):
+					msg.Answers = append(msg.Answers, records[1], records[2])
+					msg.Additionals = append(msg.Additionals, records[3])
+				case name == strings.ToLower(s.Host) && (all || q.Type == dnsmessage.TypeA):
+					msg.Answers = append(msg.Answers, records[3])
+				}
+			}
+		}
+	}
+	return msg, len(msg.Answers) > 0
+}
+
+// Label makes s usable as one label of a name: dots become dashes, and it's
+// cut to the 63 bytes a label can hold
+func Label(s string) string {
+	s = strings.ReplaceAll(s, ".", "-")
+	if len(s) > 63 {
+		s = s[:63]
+	}
+	return s
+}
+
+func response() dnsmessage.Message {
+	return dnsmessage.Message{Header: dnsmessage.Header{Response: true, Authoritative: true}}
+}
+
+// instanceName returns a service's full instance name, e.g.
+// feature-auth (laptop)._http._tcp.local.
+func instanceName(s Service) string {
+	return Label(s.Instance) + "." + ServiceType
+}
+
+// serviceRecords returns a service's PTR, SRV, TXT and A records
+func serviceRecords(s Service, ip net.IP, recordTTL uint32) []dnsmessage.Resource {
+	instance := instanceName(s)
+	txt := s.TXT
+	if len(txt) == 0 {
+		txt = []string{""}
+	}
+	var a [4]byte
+	copy(a[:], ip.To4())
+
+	header := func(name string, t dnsmessage.Type) dnsmessage.ResourceHeader {
+		// The cache-flush bit: these records replace any cached ones
+		return dnsmessage.ResourceHeader{Name: dnsmessage.MustNewName(name), Type: t, Class: dnsmessage.ClassINET | 1<<15, TTL: recordTTL}
+	}
+	return []dnsmessage.Resource{
+		ptrRecord(ServiceType, instance, recordTTL),
+		{Header: header(instance, dnsmessage.TypeSRV), Body: &dnsmessage.SRVResource{Port: uint16(s.Port), Target: dnsmessage.MustNewName(s.Host)}},
+		{Header: header(instance, dnsmessage.TypeTXT), Body: &dnsmessage.TXTResource{TXT: txt}},
+		{Header: header(s.Host, dnsmessage.TypeA), Body: &dnsmessage.AResource{A: a}},
+	}
+}
+
+// ptrRecord points name at target. PTR records are shared between
+// responders, so they don't set the cache-flush bit.
+func ptrRecord(name, target string, recordTTL uint32) dnsmessage.Resource {
+	return dnsmessage.Resource{
+		Header: dnsmessage.ResourceHeader{Name: dnsmessage.MustNewName(name), Type: dnsmessage.TypePTR, Class: dnsmessage.ClassINET, TTL: recordTTL},
+		Body:   &dnsmessage.PTRResource{PTR: dnsmessage.MustNewName(target)},
+	}
+}
+
+// Browse asks the network for _http._tcp services and returns those that
+// answer within wait, sorted by instance name
+func Browse(ctx context.Context, wait time.Duration) ([]Entry, error) {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4zero})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open a socket: %w", err)
+	}
+	defer conn.Close()
+
+	query := dnsmessage.Message{Questions: []dnsmessage.Question{{
+		Name:  dnsmessage.MustNewName(ServiceType),
+		Type:  dnsmessage.TypePTR,
+		Class: dnsmessage.ClassINET,
+	}}}
+	packed, err := query.Pack()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.WriteToUDP(packed, group); err != nil {
+		return nil, fmt.Errorf("failed to send the mDNS query: %w", err)
+	}
+
+	deadline := time.Now().Add(wait)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := conn.SetReadDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	c := newCollector()
+	buf := make([]byte, 9000)
+	for {
+		n, _, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				break
+			}
+			return nil, err
+		}
+		var msg dnsmessage.Message
+		if err := msg.Unpack(buf[:n]); err != nil || !msg.Response {
+			continue
+		}
+		c.add(msg)
+	}
+	return c.entries(), nil
+}
+
+// collector gathers the records of browse responses into entries
+type collector struct {
+	instances map[string]bool
+	srv       map[string]dnsmessage.SRVResource
+	txt       map[string][]string
+	hosts     map[string]net.IP
+}
+
+func newCollector() *collector {
+	return &collector{
+		instances: make(map[string]bool),
+		srv:       make(map[string]dnsmessage.SRVResource),
+		txt:       make(map[string][]string),
+		hosts:     make(map[string]net.IP),
+	}
+}
+
+func (c *collector) add(msg dnsmessage.Message) {
+	for _, r := range append(msg.Answers, msg.Additionals...) {
+		name := r.Header.Name.String()
+		switch body := r.Body.(type) {
+		case *dnsmessage.PTRResource:
+			if strings.EqualFold(name, ServiceType) {
+				c.instances[body.PTR.String()] = r.Header.TTL > 0
+			}
+		case *dnsmessage.SRVResource:
+			c.srv[name] = *body
+		case *dnsmessage.TXTResource:
+			c.txt[name] = body.TXT
+		case *dnsmessage.AResource:
+			c.hosts[strings.ToLower(name)] = net.IP(body.A[:])
+		}
+	}
+}
+
+func (c *collector) entries() []Entry {
+	var entries []Entry
+	for instance, alive := range c.instances {
+		srv, ok := c.srv[instance]
+		if !alive || !ok {
+			continue
+		}
+		host := srv.Target.String()
+		entry := Entry{
+			Instance: strings.TrimSuffix(instance, "."+ServiceType),
+			Host:     host,
+			IP:       c.hosts[strings.ToLower(host)],
+			Port:     int(srv.Port),
+			TXT:      make(map[string]string),
+		}
+		for _, kv := range c.txt[instance] {
+			if k, v, ok := strings.Cut(kv, "="); ok {
+				entry.TXT[k] = v
+			} else if kv != "" {
+				entry.TXT[kv] = ""
+			}
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Instance < entries[j].Instance })
+	return entries
+}
@@ -0,0 +1,78 @@
+package mdns
+
+import (
+	"net"
+	"testing"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func TestAnswerAndBrowse(t *testing.T) {
+	ip := net.ParseIP("192.168.1.20")
+	services := []Service{
+		{Instance: "feature-auth (laptop)", Host: "feature-auth.laptop.local.", Port: 13042, TXT: []string{"grove=1", "branch=feature/auth"}},
+		{Instance: "main (laptop)", Host: "main.laptop.local.", Port: 13001},
+	}
+
+	query := dnsmessage.Message{Questions: []dnsmessage.Question{{
+		Name:  dnsmessage.MustNewName(ServiceType),
+		Type:  dnsmessage.TypePTR,
+		Class: dnsmessage.ClassINET,
+	}}}
+	msg, ok := answer(query, services, ip)
+	if !ok || len(msg.Answers) != 2 || len(msg.Additionals) != 6 {
+		t.Fatalf("answer = %d answers, %d additionals, want 2 PTR records and their SRV, TXT and A records", len(msg.Answers), len(msg.Additionals))
+	}
+
+	// Round trip through the wire format, as Browse would receive it
+	packed, err := msg.Pack()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got dnsmessage.Message
+	if err := got.Unpack(packed); err != nil {
+		t.Fatal(err)
+	}
+	c := newCollector()
+	c.add(got)
+	entries := c.entries()
+	if len(entries) != 2 {
+		t.Fatalf("entries = %+v, want 2", entries)
+	}
+	e := entries[0]
+	if e.Instance != "feature-auth (laptop)" || e.Host != "feature-auth.laptop.local." || e.Port != 13042 || !e.IP.Equal(ip) {
+		t.Errorf("entry = %+v", e)
+	}
+	if e.TXT["grove"] != "1" || e.TXT["branch"] != "feature/auth" {
+		t.Errorf("TXT = %v", e.TXT)
+	}
+
+	// A goodbye removes the service
+	goodbye := response()
+	goodbye.Answers = serviceRecords(services[1], ip, 0)
+	c.add(goodbye)
+	if entries := c.entries(); len(entries) != 1 {
+		t.Errorf("entries after goodbye = %+v, want only feature-auth", entries)
+	}
+}
+
+func TestAnswerHost(t *testing.T) {
+	services := []Service{{Instance: "main (laptop)", Host: "main.laptop.local.", Port: 13001}}
+	query := dnsmessage.Message{Questions: []dnsmessage.Question{{
+		Name:  dnsmessage.MustNewName("Main.Laptop.local."),
+		Type:  dnsmessage.TypeA,
+		Class: dnsmessage.ClassINET,
+	}}}
+	msg, ok := answer(query, services, net.ParseIP("10.0.0.5"))
+	if !ok || len(msg.Answers) != 1 {
+		t.Fatalf("answer = %+v, want the A record", msg.Answers)
+	}
+	if a := msg.Answers[0].Body.(*dnsmessage.AResource).A; net.IP(a[:]).String() != "10.0.0.5" {
+		t.Errorf("A = %v", a)
+	}
+
+	query.Questions[0].Name = dnsmessage.MustNewName("other.local.")
+	if _, ok := answer(query, services, net.ParseIP("10.0.0.5")); ok {
+		t.Error("queries for other names shouldn't be answered")
+	}
+}
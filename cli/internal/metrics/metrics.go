@@ -0,0 +1,149 @@
+// Package metrics tracks grove counters across processes and renders them,
+// together with live registry state, in the Prometheus text format.
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/iheanyi/grove/internal/events"
+)
+
+// Counters are monotonically increasing totals persisted between runs.
+// Each grove command is a short-lived process, so counts are kept on disk
+// rather than in memory.
+type Counters struct {
+	Starts         map[string]int64 `json:"starts,omitempty"`
+	Crashes        map[string]int64 `json:"crashes,omitempty"`
+	Restarts       map[string]int64 `json:"restarts,omitempty"`
+	HealthFailures map[string]int64 `json:"health_failures,omitempty"`
+
+	// DiscoveryScans and DiscoveryScanSeconds are the count and total
+	// duration of worktree discovery scans
+	DiscoveryScans       int64   `json:"discovery_scans,omitempty"`
+	DiscoveryScanSeconds float64 `json:"discovery_scan_seconds,omitempty"`
+}
+
+// path is where counters are stored; recording is disabled until Setup
+var path string
+
+// Setup enables recording to the counters file at p and subscribes to
+// server lifecycle events
+func Setup(p string) {
+	path = p
+	events.Subscribe(handleEvent)
+}
+
+func handleEvent(e events.Event) {
+	switch e.Type {
+	case events.ServerStarted:
+		inc(func(c *Counters) map[string]int64 { return c.Starts }, e.Server)
+	case events.ServerCrashed:
+		inc(func(c *Counters) map[string]int64 { return c.Crashes }, e.Server)
+	case events.HealthChanged:
+		if e.Health == "unhealthy" {
+			inc(func(c *Counters) map[string]int64 { return c.HealthFailures }, e.Server)
+		}
+	}
+}
+
+// RecordRestart counts a server restart
+func RecordRestart(server string) {
+	inc(func(c *Counters) map[string]int64 { return c.Restarts }, server)
+}
+
+// RecordDiscoveryScan records how long a worktree discovery scan took
+func RecordDiscoveryScan(d time.Duration) {
+	_ = Update(func(c *Counters) {
+		c.DiscoveryScans++
+		c.DiscoveryScanSeconds += d.Seconds()
+	})
+}
+
+func inc(counter func(*Counters) map[string]int64, server string) {
+	_ = Update(func(c *Counters) {
+		counter(c)[server]++
+	})
+}
+
+// Load reads the counters file. A missing file yields empty counters.
+func Load() (*Counters, error) {
+	c := newCounters()
+	if path == "" {
+		return c, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return c, nil
+		}
+		return nil, fmt.Errorf("failed to read metrics: %w", err)
+	}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("failed to parse metrics: %w", err)
+	}
+	c.init()
+	return c, nil
+}
+
+// Update applies fn to the counters under an exclusive file lock, so
+// concurrent grove processes don't lose increments
+func Update(fn func(*Counters)) error {
+	if path == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create metrics directory: %w", err)
+	}
+
+	lockFile, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create lock file: %w", err)
+	}
+	defer lockFile.Close()
+
+	if err := syscall.Flock(int(lockFile.Fd()), syscall.LOCK_EX); err != nil {
+		return fmt.Errorf("failed to acquire file lock: %w", err)
+	}
+	defer syscall.Flock(int(lockFile.Fd()), syscall.LOCK_UN) //nolint:errcheck
+
+	c, err := Load()
+	if err != nil {
+		// Start over rather than failing forever on a corrupt file
+		c = newCounters()
+	}
+	fn(c)
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal metrics: %w", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func newCounters() *Counters {
+	c := &Counters{}
+	c.init()
+	return c
+}
+
+func (c *Counters) init() {
+	if c.Starts == nil {
+		c.Starts = make(map[string]int64)
+	}
+	if c.Crashes == nil {
+		c.Crashes = make(map[string]int64)
+	}
+	if c.Restarts == nil {
+		c.Restarts = make(map[string]int64)
+	}
+	if c.HealthFailures == nil {
+		c.HealthFailures = make(map[string]int64)
+	}
+}
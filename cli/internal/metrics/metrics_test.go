@@ -0,0 +1,123 @@
+package metrics
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/iheanyi/grove/internal/events"
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+func setupTest(t *testing.T) {
+	t.Helper()
+	t.Cleanup(events.Reset)
+	t.Cleanup(func() { path = "" })
+	Setup(filepath.Join(t.TempDir(), "metrics.json"))
+}
+
+func TestEventsUpdateCounters(t *testing.T) {
+	setupTest(t)
+
+	events.Publish(events.Event{Type: events.ServerStarted, Server: "api"})
+	events.Publish(events.Event{Type: events.ServerCrashed, Server: "api"})
+	events.Publish(events.Event{Type: events.HealthChanged, Server: "api", Health: "unhealthy"})
+	events.Publish(events.Event{Type: events.HealthChanged, Server: "api", Health: "healthy"})
+	RecordRestart("web")
+	RecordDiscoveryScan(1500 * time.Millisecond)
+
+	c, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if c.Starts["api"] != 1 || c.Crashes["api"] != 1 || c.HealthFailures["api"] != 1 {
+		t.Errorf("unexpected counters: %+v", c)
+	}
+	if c.Restarts["web"] != 1 {
+		t.Errorf("restarts = %d, want 1", c.Restarts["web"])
+	}
+	if c.DiscoveryScans != 1 || c.DiscoveryScanSeconds != 1.5 {
+		t.Errorf("discovery = %d/%v, want 1/1.5", c.DiscoveryScans, c.DiscoveryScanSeconds)
+	}
+}
+
+func TestUpdate_Concurrent(t *testing.T) {
+	setupTest(t)
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			RecordRestart("api")
+		}()
+	}
+	wg.Wait()
+
+	c, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if c.Restarts["api"] != 20 {
+		t.Errorf("restarts = %d, want 20", c.Restarts["api"])
+	}
+}
+
+func TestWrite(t *testing.T) {
+	servers := []*registry.Server{
+		{Name: "web", Port: 3001, Status: registry.StatusStopped},
+		{Name: "api", Port: 3000, Status: registry.StatusRunning, PID: 1, Health: registry.HealthHealthy, StartedAt: time.Now()},
+	}
+	counters := newCounters()
+	counters.Crashes["api"] = 2
+
+	var sb strings.Builder
+	err := Write(&sb, Snapshot{
+		Servers:       servers,
+		Counters:      counters,
+		ProxyRequests: map[string]float64{"api.localhost": 42},
+	})
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	out := sb.String()
+
+	for _, want := range []string{
+		"# TYPE grove_servers_running gauge",
+		"grove_servers_running 1\n",
+		`grove_servers{status="stopped"} 1`,
+		`grove_server_up{server="api",port="3000"} 1`,
+		`grove_server_up{server="web",port="3001"} 0`,
+		`grove_server_healthy{server="api"} 1`,
+		`grove_server_crashes_total{server="api"} 2`,
+		"grove_discovery_scan_duration_seconds_count 0",
+		`grove_proxy_requests_total{host="api.localhost"} 42`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, `server="api"`) > strings.Index(out, `server="web"`) {
+		t.Error("servers should be sorted by name")
+	}
+}
+
+func TestParseCaddyRequests(t *testing.T) {
+	input := `# HELP caddy_http_requests_total Counter of HTTP(S) requests made.
+# TYPE caddy_http_requests_total counter
+caddy_http_requests_total{handler="reverse_proxy",host="api.localhost",server="srv0"} 10
+caddy_http_requests_total{handler="subroute",host="api.localhost",server="srv0"} 5
+caddy_http_requests_total{handler="reverse_proxy",host="web.localhost",server="srv0"} 3
+caddy_http_requests_total{handler="reverse_proxy",server="srv1"} 7
+caddy_http_request_duration_seconds_count{host="api.localhost"} 99
+`
+	counts, err := parseCaddyRequests(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("parseCaddyRequests: %v", err)
+	}
+	if counts["api.localhost"] != 15 || counts["web.localhost"] != 3 || len(counts) != 2 {
+		t.Errorf("unexpected counts: %v", counts)
+	}
+}
@@ -0,0 +1,173 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+// CaddyAdminURL is the default address of Caddy's admin API, which serves
+// the proxy's own metrics
+const CaddyAdminURL = "http://localhost:2019"
+
+// Snapshot is everything rendered on a /metrics scrape
+type Snapshot struct {
+	Servers  []*registry.Server
+	Counters *Counters
+
+	// ProxyRequests maps proxied hosts to their request counts
+	ProxyRequests map[string]float64
+}
+
+// Write renders the snapshot in the Prometheus text exposition format
+func Write(w io.Writer, s Snapshot) error {
+	bw := bufio.NewWriter(w)
+
+	servers := make([]*registry.Server, len(s.Servers))
+	copy(servers, s.Servers)
+	sort.Slice(servers, func(i, j int) bool { return servers[i].Name < servers[j].Name })
+
+	byStatus := make(map[string]int)
+	running := 0
+	for _, server := range servers {
+		byStatus[string(server.Status)]++
+		if server.IsRunning() {
+			running++
+		}
+	}
+
+	header(bw, "grove_servers_running", "gauge", "Number of running dev servers.")
+	fmt.Fprintf(bw, "grove_servers_running %d\n", running)
+
+	header(bw, "grove_servers", "gauge", "Number of registered dev servers by status.")
+	for _, status := range sortedKeys(byStatus) {
+		fmt.Fprintf(bw, "grove_servers{status=%q} %d\n", status, byStatus[status])
+	}
+
+	header(bw, "grove_server_up", "gauge", "Whether the dev server is running (1) or not (0).")
+	for _, server := range servers {
+		fmt.Fprintf(bw, "grove_server_up{server=%q,port=\"%d\"} %d\n", server.Name, server.Port, boolInt(server.IsRunning()))
+	}
+
+	header(bw, "grove_server_healthy", "gauge", "Whether the last health check passed (1) or failed (0).")
+	for _, server := range servers {
+		switch server.Health {
+		case registry.HealthHealthy, registry.HealthUnhealthy:
+			fmt.Fprintf(bw, "grove_server_healthy{server=%q} %d\n", server.Name, boolInt(server.Health == registry.HealthHealthy))
+		}
+	}
+
+	header(bw, "grove_server_uptime_seconds", "gauge", "Seconds since the dev server started.")
+	for _, server := range servers {
+		if server.IsRunning() && !server.StartedAt.IsZero() {
+			fmt.Fprintf(bw, "grove_server_uptime_seconds{server=%q} %.0f\n", server.Name, time.Since(server.StartedAt).Seconds())
+		}
+	}
+
+	c := s.Counters
+	if c == nil {
+		c = newCounters()
+	}
+	counter(bw, "grove_server_starts_total", "Dev server starts.", c.Starts)
+	counter(bw, "grove_server_crashes_total", "Dev server crashes.", c.Crashes)
+	counter(bw, "grove_server_restarts_total", "Dev server restarts.", c.Restarts)
+	counter(bw, "grove_health_check_failures_total", "Health checks that flipped a server to unhealthy.", c.HealthFailures)
+
+	header(bw, "grove_discovery_scan_duration_seconds", "summary", "Duration of worktree discovery scans.")
+	fmt.Fprintf(bw, "grove_discovery_scan_duration_seconds_sum %s\n", formatFloat(c.DiscoveryScanSeconds))
+	fmt.Fprintf(bw, "grove_discovery_scan_duration_seconds_count %d\n", c.DiscoveryScans)
+
+	if s.ProxyRequests != nil {
+		header(bw, "grove_proxy_requests_total", "counter", "Requests served by the proxy, by host.")
+		for _, host := range sortedKeys(s.ProxyRequests) {
+			fmt.Fprintf(bw, "grove_proxy_requests_total{host=%q} %s\n", host, formatFloat(s.ProxyRequests[host]))
+		}
+	}
+
+	return bw.Flush()
+}
+
+// hostLabel matches the host label on Caddy's request counter
+var hostLabel = regexp.MustCompile(`\bhost="([^"]*)"`)
+
+// ProxyRequests fetches request counts per host from Caddy's metrics
+// endpoint. Caddy only labels requests by host when per_host metrics are
+// enabled, which grove's generated Caddyfile does.
+func ProxyRequests(adminURL string) (map[string]float64, error) {
+	client := &http.Client{Timeout: 2 * time.Second}
+	resp, err := client.Get(adminURL + "/metrics")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch proxy metrics: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("proxy metrics returned %s", resp.Status)
+	}
+	return parseCaddyRequests(resp.Body)
+}
+
+// parseCaddyRequests sums caddy_http_requests_total samples by host
+func parseCaddyRequests(r io.Reader) (map[string]float64, error) {
+	counts := make(map[string]float64)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "caddy_http_requests_total{") {
+			continue
+		}
+
+		m := hostLabel.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		fields := strings.Fields(line)
+		v, err := strconv.ParseFloat(fields[len(fields)-1], 64)
+		if err != nil {
+			continue
+		}
+		counts[m[1]] += v
+	}
+
+	return counts, scanner.Err()
+}
+
+func header(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func counter(w io.Writer, name, help string, values map[string]int64) {
+	header(w, name, "counter", help)
+	for _, server := range sortedKeys(values) {
+		fmt.Fprintf(w, "%s{server=%q} %d\n", name, server, values[server])
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
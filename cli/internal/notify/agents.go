@@ -0,0 +1,167 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/discovery"
+	"github.com/iheanyi/grove/internal/events"
+)
+
+// AgentSession is an active agent and the worktree it's running in
+type AgentSession struct {
+	Worktree string
+	Agent    *discovery.AgentInfo
+}
+
+// AgentWatcher pings chat webhooks once per agent session that runs longer
+// than the configured threshold. Sessions already pinged are remembered on
+// disk so restarting the watcher doesn't ping again.
+type AgentWatcher struct {
+	cfg    config.AgentNotifyConfig
+	path   string
+	send   func(string) error
+	mu     sync.Mutex
+	pinged map[string]time.Time
+}
+
+// NewAgentWatcher creates a watcher that records pinged sessions at statePath
+func NewAgentWatcher(cfg config.AgentNotifyConfig, statePath string) *AgentWatcher {
+	w := &AgentWatcher{
+		cfg:    cfg,
+		path:   statePath,
+		pinged: make(map[string]time.Time),
+	}
+	w.send = func(text string) error { return SendChat(cfg, text) }
+
+	if data, err := os.ReadFile(statePath); err == nil {
+		_ = json.Unmarshal(data, &w.pinged)
+	}
+	return w
+}
+
+// sessionKey identifies an agent session; the start time guards against PID reuse
+func sessionKey(a *discovery.AgentInfo) string {
+	return fmt.Sprintf("%d:%d", a.PID, a.StartTime.Unix())
+}
+
+// Check pings for sessions that crossed the threshold and returns the
+// messages that were sent
+func (w *AgentWatcher) Check(sessions []AgentSession, now time.Time) ([]string, error) {
+	if !w.cfg.Configured() || w.cfg.LongRunning <= 0 {
+		return nil, nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	active := make(map[string]bool, len(sessions))
+	var sent []string
+	var errs []string
+	for _, s := range sessions {
+		if s.Agent == nil || s.Agent.StartTime.IsZero() {
+			continue
+		}
+		key := sessionKey(s.Agent)
+		active[key] = true
+
+		runtime := now.Sub(s.Agent.StartTime)
+		if runtime < w.cfg.LongRunning {
+			continue
+		}
+		if _, ok := w.pinged[key]; ok {
+			continue
+		}
+
+		msg := LongRunningMessage(s, runtime)
+		if err := w.send(msg); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		w.pinged[key] = now
+		sent = append(sent, msg)
+	}
+
+	// Forget sessions that have ended
+	for key := range w.pinged {
+		if !active[key] {
+			delete(w.pinged, key)
+		}
+	}
+	w.save()
+
+	if len(errs) > 0 {
+		return sent, fmt.Errorf("failed to send: %s", strings.Join(errs, "; "))
+	}
+	return sent, nil
+}
+
+func (w *AgentWatcher) save() {
+	data, err := json.MarshalIndent(w.pinged, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(w.path), 0755); err != nil {
+		return
+	}
+	_ = os.WriteFile(w.path, data, 0644)
+}
+
+// LongRunningMessage describes an agent that has been running a long time
+func LongRunningMessage(s AgentSession, runtime time.Duration) string {
+	msg := fmt.Sprintf(":hourglass: %s agent in *%s* has been running for %s",
+		agentName(s.Agent.Type), s.Worktree, runtime.Round(time.Minute))
+	if s.Agent.TaskSummary != "" {
+		msg += fmt.Sprintf(" (task: %s)", s.Agent.TaskSummary)
+	}
+	return msg + " - it may be stuck"
+}
+
+// CrashMessage describes a dev server crash in a worktree with an active agent
+func CrashMessage(e events.Event, agent *discovery.AgentInfo) string {
+	msg := fmt.Sprintf(":rotating_light: %s while a %s agent is active", Summary(e), agentName(agent.Type))
+	if !agent.StartTime.IsZero() {
+		msg += fmt.Sprintf(" (running for %s)", time.Since(agent.StartTime).Round(time.Minute))
+	}
+	return msg
+}
+
+// agentName returns a display name for an agent type
+func agentName(agentType string) string {
+	switch agentType {
+	case "claude":
+		return "Claude"
+	case "gemini":
+		return "Gemini"
+	case "":
+		return "AI"
+	default:
+		return strings.ToUpper(agentType[:1]) + agentType[1:]
+	}
+}
+
+// agentCrashHandler pings chat when a server crashes while an agent is
+// active in its worktree
+func agentCrashHandler(cfg config.AgentNotifyConfig) events.Handler {
+	return func(e events.Event) {
+		if e.Type != events.ServerCrashed || e.Path == "" {
+			return
+		}
+
+		agent, ok := discovery.DetectAllAgents(context.Background())[e.Path]
+		if !ok {
+			return
+		}
+
+		if err := SendChat(cfg, CrashMessage(e, agent)); err != nil {
+			logError("agent crash notification: %v", err)
+		}
+	}
+}
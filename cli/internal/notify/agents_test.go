@@ -0,0 +1,100 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/discovery"
+)
+
+func TestSendChat_PayloadShapes(t *testing.T) {
+	var slack, discord map[string]string
+	slackSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&slack)
+	}))
+	defer slackSrv.Close()
+	discordSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&discord)
+	}))
+	defer discordSrv.Close()
+
+	cfg := config.AgentNotifyConfig{SlackWebhook: slackSrv.URL, DiscordWebhook: discordSrv.URL}
+	if err := SendChat(cfg, "hello"); err != nil {
+		t.Fatalf("SendChat: %v", err)
+	}
+
+	if slack["text"] != "hello" {
+		t.Errorf("slack payload = %v, want text=hello", slack)
+	}
+	if discord["content"] != "hello" {
+		t.Errorf("discord payload = %v, want content=hello", discord)
+	}
+}
+
+func TestAgentWatcher_PingsOncePerSession(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), "agent-notify.json")
+	cfg := config.AgentNotifyConfig{SlackWebhook: "http://example.invalid", LongRunning: time.Hour}
+
+	var sent []string
+	newWatcher := func() *AgentWatcher {
+		w := NewAgentWatcher(cfg, statePath)
+		w.send = func(text string) error {
+			sent = append(sent, text)
+			return nil
+		}
+		return w
+	}
+
+	now := time.Now()
+	sessions := []AgentSession{
+		{Worktree: "feature-auth", Agent: &discovery.AgentInfo{Type: "claude", PID: 100, StartTime: now.Add(-2 * time.Hour), TaskSummary: "Add login"}},
+		{Worktree: "feature-ui", Agent: &discovery.AgentInfo{Type: "claude", PID: 200, StartTime: now.Add(-10 * time.Minute)}},
+	}
+
+	w := newWatcher()
+	if _, err := w.Check(sessions, now); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if len(sent) != 1 {
+		t.Fatalf("sent %d messages, want 1: %v", len(sent), sent)
+	}
+	if !strings.Contains(sent[0], "feature-auth") || !strings.Contains(sent[0], "Add login") {
+		t.Errorf("unexpected message: %q", sent[0])
+	}
+
+	// A restarted watcher remembers the session
+	w = newWatcher()
+	if _, err := w.Check(sessions, now.Add(time.Minute)); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if len(sent) != 1 {
+		t.Errorf("session pinged again after restart: %v", sent)
+	}
+
+	// The second agent crosses the threshold later
+	if _, err := w.Check(sessions, now.Add(time.Hour)); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if len(sent) != 2 || !strings.Contains(sent[1], "feature-ui") {
+		t.Errorf("expected ping for feature-ui, got %v", sent)
+	}
+}
+
+func TestAgentWatcher_NotConfigured(t *testing.T) {
+	w := NewAgentWatcher(config.AgentNotifyConfig{LongRunning: time.Hour}, filepath.Join(t.TempDir(), "state.json"))
+	w.send = func(string) error {
+		t.Fatal("send called without webhooks configured")
+		return nil
+	}
+
+	sessions := []AgentSession{{Worktree: "x", Agent: &discovery.AgentInfo{PID: 1, StartTime: time.Now().Add(-5 * time.Hour)}}}
+	if _, err := w.Check(sessions, time.Now()); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+}
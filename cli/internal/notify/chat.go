@@ -0,0 +1,29 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/iheanyi/grove/internal/config"
+)
+
+// SendChat posts a message to the configured Slack and Discord webhooks
+func SendChat(cfg config.AgentNotifyConfig, text string) error {
+	var errs []error
+
+	if cfg.SlackWebhook != "" {
+		body, _ := json.Marshal(map[string]string{"text": text})
+		if err := post(cfg.SlackWebhook, nil, body); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if cfg.DiscordWebhook != "" {
+		body, _ := json.Marshal(map[string]string{"content": text})
+		if err := post(cfg.DiscordWebhook, nil, body); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
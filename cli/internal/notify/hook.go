@@ -0,0 +1,67 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/iheanyi/grove/internal/events"
+)
+
+// Hook returns a handler that runs a shell command for each event. The event
+// is passed as JSON on stdin and as GROVE_* environment variables.
+func Hook(command string) events.Handler {
+	return func(e events.Event) {
+		payload, err := json.Marshal(e)
+		if err != nil {
+			logError("hook %q: %v", command, err)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		cmd := exec.CommandContext(ctx, "sh", "-c", command)
+		cmd.Stdin = bytes.NewReader(payload)
+		cmd.Env = append(os.Environ(), hookEnv(e)...)
+		if e.Path != "" {
+			cmd.Dir = e.Path
+		}
+
+		if output, err := cmd.CombinedOutput(); err != nil {
+			logError("hook %q: %v: %s", command, err, strings.TrimSpace(string(output)))
+		}
+	}
+}
+
+// hookEnv returns the GROVE_* environment variables describing an event
+func hookEnv(e events.Event) []string {
+	env := []string{
+		"GROVE_EVENT=" + string(e.Type),
+		"GROVE_SERVER=" + e.Server,
+		"GROVE_MESSAGE=" + Summary(e),
+	}
+	if e.Path != "" {
+		env = append(env, "GROVE_PATH="+e.Path)
+	}
+	if e.URL != "" {
+		env = append(env, "GROVE_URL="+e.URL)
+	}
+	if e.Port > 0 {
+		env = append(env, fmt.Sprintf("GROVE_PORT=%d", e.Port))
+	}
+	if e.Process != "" {
+		env = append(env, "GROVE_PROCESS="+e.Process)
+	}
+	if e.Health != "" {
+		env = append(env, "GROVE_HEALTH="+e.Health)
+	}
+	if e.ExitCode != nil {
+		env = append(env, fmt.Sprintf("GROVE_EXIT_CODE=%d", *e.ExitCode))
+	}
+	return env
+}
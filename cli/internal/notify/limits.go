@@ -0,0 +1,167 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/discovery"
+	"github.com/iheanyi/grove/internal/events"
+)
+
+// LimitWatcher acts once per agent session that goes over agent_limits: it
+// publishes an agent_limit event, pings the agent chat webhooks if any are
+// configured, and sends the agent SIGINT when the action is "interrupt".
+// Sessions already acted on are remembered on disk, like AgentWatcher's.
+type LimitWatcher struct {
+	limits config.AgentLimitsConfig
+	path   string
+	chat   bool
+	send   func(string) error
+	signal func(pid int) error
+	tokens func(path string, since time.Time) int
+	mu     sync.Mutex
+	over   map[string]time.Time
+}
+
+// NewLimitWatcher creates a watcher that records sessions over their limits
+// at statePath
+func NewLimitWatcher(limits config.AgentLimitsConfig, chat config.AgentNotifyConfig, statePath string) *LimitWatcher {
+	w := &LimitWatcher{
+		limits: limits,
+		path:   statePath,
+		chat:   chat.Configured(),
+		over:   make(map[string]time.Time),
+	}
+	w.send = func(text string) error { return SendChat(chat, text) }
+	w.signal = interruptAgent
+	w.tokens = func(path string, since time.Time) int {
+		return discovery.ClaudeTokens(discovery.ClaudeProjectsDir(), path, since)
+	}
+
+	if data, err := os.ReadFile(statePath); err == nil {
+		_ = json.Unmarshal(data, &w.over)
+	}
+	return w
+}
+
+// interruptAgent sends SIGINT to an agent's process group, as Ctrl+C in its
+// terminal would, or to the agent alone when it doesn't lead one
+func interruptAgent(pid int) error {
+	if err := syscall.Kill(-pid, syscall.SIGINT); err == nil {
+		return nil
+	}
+	return syscall.Kill(pid, syscall.SIGINT)
+}
+
+// exceeded returns what limit a session is over, or "" if it's within them
+func (w *LimitWatcher) exceeded(s AgentSession, now time.Time) string {
+	runtime := now.Sub(s.Agent.StartTime)
+	if w.limits.MaxRuntime > 0 && runtime >= w.limits.MaxRuntime {
+		return fmt.Sprintf("ran for %s, limit %s", runtime.Round(time.Minute), w.limits.MaxRuntime)
+	}
+	// Token use is only known for Claude Code, from its transcripts
+	if w.limits.MaxTokens > 0 && s.Agent.Type == "claude" {
+		if used := w.tokens(s.Agent.Path, s.Agent.StartTime); used >= w.limits.MaxTokens {
+			return fmt.Sprintf("used %d tokens, limit %d", used, w.limits.MaxTokens)
+		}
+	}
+	return ""
+}
+
+// Check acts on sessions that went over a limit and returns a description
+// of each
+func (w *LimitWatcher) Check(sessions []AgentSession, now time.Time) ([]string, error) {
+	if !w.limits.Enabled() {
+		return nil, nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	active := make(map[string]bool, len(sessions))
+	var acted []string
+	var errs []string
+	for _, s := range sessions {
+		if s.Agent == nil || s.Agent.StartTime.IsZero() {
+			continue
+		}
+		key := sessionKey(s.Agent)
+		active[key] = true
+		if _, ok := w.over[key]; ok {
+			continue
+		}
+
+		reason := w.exceeded(s, now)
+		if reason == "" {
+			continue
+		}
+		w.over[key] = now
+
+		if w.limits.Action == config.AgentLimitInterrupt {
+			if err := w.signal(s.Agent.PID); err != nil {
+				errs = append(errs, fmt.Sprintf("failed to interrupt agent in %s: %v", s.Worktree, err))
+			} else {
+				reason += ", interrupted"
+			}
+		}
+
+		events.Publish(events.Event{
+			Type:    events.AgentLimitExceeded,
+			Server:  s.Worktree,
+			Path:    s.Agent.Path,
+			PID:     s.Agent.PID,
+			Process: s.Agent.Type,
+			Message: reason,
+			Time:    now,
+		})
+
+		msg := LimitMessage(s, reason)
+		if w.chat {
+			if err := w.send(msg); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+		acted = append(acted, msg)
+	}
+
+	// Forget sessions that have ended
+	for key := range w.over {
+		if !active[key] {
+			delete(w.over, key)
+		}
+	}
+	w.save()
+
+	if len(errs) > 0 {
+		return acted, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return acted, nil
+}
+
+func (w *LimitWatcher) save() {
+	data, err := json.MarshalIndent(w.over, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(w.path), 0755); err != nil {
+		return
+	}
+	_ = os.WriteFile(w.path, data, 0644)
+}
+
+// LimitMessage describes an agent session that went over its limits
+func LimitMessage(s AgentSession, reason string) string {
+	msg := fmt.Sprintf(":octagonal_sign: %s agent in *%s* went over its limit: %s",
+		agentName(s.Agent.Type), s.Worktree, reason)
+	if s.Agent.TaskSummary != "" {
+		msg += fmt.Sprintf(" (task: %s)", s.Agent.TaskSummary)
+	}
+	return msg
+}
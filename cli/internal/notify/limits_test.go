@@ -0,0 +1,93 @@
+package notify
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/discovery"
+	"github.com/iheanyi/grove/internal/events"
+)
+
+func TestLimitWatcher_ActsOncePerSession(t *testing.T) {
+	t.Cleanup(events.Reset)
+	var mu sync.Mutex
+	var published []events.Event
+	events.Subscribe(func(e events.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		published = append(published, e)
+	})
+
+	statePath := filepath.Join(t.TempDir(), "agent-limits.json")
+	limits := config.AgentLimitsConfig{MaxRuntime: time.Hour, MaxTokens: 1000, Action: config.AgentLimitInterrupt}
+
+	var interrupted []int
+	newWatcher := func() *LimitWatcher {
+		w := NewLimitWatcher(limits, config.AgentNotifyConfig{}, statePath)
+		w.send = func(string) error {
+			t.Fatal("send called without webhooks configured")
+			return nil
+		}
+		w.signal = func(pid int) error {
+			interrupted = append(interrupted, pid)
+			return nil
+		}
+		w.tokens = func(path string, since time.Time) int {
+			if path == "/tmp/feature-ui" {
+				return 5000
+			}
+			return 10
+		}
+		return w
+	}
+
+	now := time.Now()
+	sessions := []AgentSession{
+		{Worktree: "feature-auth", Agent: &discovery.AgentInfo{Type: "aider", PID: 100, Path: "/tmp/feature-auth", StartTime: now.Add(-2 * time.Hour)}},
+		{Worktree: "feature-ui", Agent: &discovery.AgentInfo{Type: "claude", PID: 200, Path: "/tmp/feature-ui", StartTime: now.Add(-10 * time.Minute)}},
+		{Worktree: "feature-api", Agent: &discovery.AgentInfo{Type: "claude", PID: 300, Path: "/tmp/feature-api", StartTime: now.Add(-10 * time.Minute)}},
+	}
+
+	acted, err := newWatcher().Check(sessions, now)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if len(acted) != 2 {
+		t.Fatalf("acted on %d sessions, want 2: %v", len(acted), acted)
+	}
+	if !strings.Contains(acted[0], "ran for 2h0m0s") || !strings.Contains(acted[1], "used 5000 tokens") {
+		t.Errorf("unexpected messages: %v", acted)
+	}
+	if len(interrupted) != 2 || interrupted[0] != 100 || interrupted[1] != 200 {
+		t.Errorf("interrupted %v, want [100 200]", interrupted)
+	}
+	if len(published) != 2 || published[0].Type != events.AgentLimitExceeded || published[0].Server != "feature-auth" {
+		t.Errorf("unexpected events: %+v", published)
+	}
+
+	// A restarted watcher remembers the sessions
+	acted, err = newWatcher().Check(sessions, now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if len(acted) != 0 || len(interrupted) != 2 {
+		t.Errorf("sessions acted on again after restart: %v", acted)
+	}
+}
+
+func TestLimitWatcher_Disabled(t *testing.T) {
+	w := NewLimitWatcher(config.AgentLimitsConfig{}, config.AgentNotifyConfig{}, filepath.Join(t.TempDir(), "state.json"))
+	w.signal = func(int) error {
+		t.Fatal("signal called without limits")
+		return nil
+	}
+
+	sessions := []AgentSession{{Worktree: "x", Agent: &discovery.AgentInfo{PID: 1, StartTime: time.Now().Add(-5 * time.Hour)}}}
+	if _, err := w.Check(sessions, time.Now()); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+package notify
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strconv"
+
+	"github.com/iheanyi/grove/internal/events"
+)
+
+// Native shows a desktop notification for an event
+func Native(e events.Event) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s",
+			strconv.Quote(Summary(e)), strconv.Quote("grove"))
+		cmd = exec.Command("osascript", "-e", script)
+	case "linux":
+		if _, err := exec.LookPath("notify-send"); err != nil {
+			return
+		}
+		cmd = exec.Command("notify-send", "grove", Summary(e))
+	default:
+		return
+	}
+
+	if err := cmd.Run(); err != nil {
+		logError("native notification: %v", err)
+	}
+}
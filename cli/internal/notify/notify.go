@@ -0,0 +1,118 @@
+// Package notify delivers server lifecycle events to webhooks, shell hooks,
+// and desktop notifications
+package notify
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/events"
+)
+
+// timeout bounds each webhook request and hook command
+const timeout = 10 * time.Second
+
+// Setup subscribes the configured notifiers to the events bus
+func Setup(cfg config.NotificationConfig) {
+	if !cfg.Enabled {
+		return
+	}
+
+	for _, wh := range cfg.Webhooks {
+		if wh.URL != "" {
+			subscribe(cfg, wh.Events, Webhook(wh))
+		}
+	}
+	for _, hook := range cfg.Hooks {
+		if hook.Command != "" {
+			subscribe(cfg, hook.Events, Hook(hook.Command))
+		}
+	}
+	if cfg.Native {
+		subscribe(cfg, nil, Native)
+	}
+	if cfg.Agents.Configured() && cfg.Agents.OnCrash {
+		events.Subscribe(agentCrashHandler(cfg.Agents))
+	}
+}
+
+// subscribe registers a handler that only sees events enabled in cfg and
+// listed in filter (if non-empty)
+func subscribe(cfg config.NotificationConfig, filter []string, h events.Handler) {
+	events.Subscribe(func(e events.Event) {
+		if Enabled(cfg, e.Type) && (len(filter) == 0 || slices.Contains(filter, string(e.Type))) {
+			h(e)
+		}
+	})
+}
+
+// Enabled reports whether the config's on_* flags allow an event type
+func Enabled(cfg config.NotificationConfig, t events.Type) bool {
+	switch t {
+	case events.ServerStarted:
+		return cfg.OnStart
+	case events.ServerStopped:
+		return cfg.OnStop
+	case events.ServerCrashed:
+		return cfg.OnCrash
+	case events.ServerIdleStopped:
+		return cfg.OnIdleStop
+	case events.HealthChanged:
+		return cfg.OnHealthChange
+	default:
+		return true
+	}
+}
+
+// Summary returns a short human-readable description of an event
+func Summary(e events.Event) string {
+	name := e.Server
+	if e.Process != "" {
+		name += " (" + e.Process + ")"
+	}
+
+	var s string
+	switch e.Type {
+	case events.ServerStarted:
+		s = name + " started"
+		if e.URL != "" {
+			s += " at " + e.URL
+		}
+	case events.ServerStopped:
+		s = name + " stopped"
+	case events.ServerCrashed:
+		s = name + " crashed"
+	case events.ServerIdleStopped:
+		s = name + " stopped after being idle"
+	case events.HealthChanged:
+		s = name + " is " + e.Health
+	case events.AgentLimitExceeded:
+		s = "agent in " + name + " went over its limit"
+	default:
+		s = name + ": " + string(e.Type)
+	}
+
+	if e.Message != "" {
+		s += " (" + e.Message + ")"
+	}
+	return s
+}
+
+// logError records a failed delivery. Notifications run inside other
+// commands (and the TUI), so failures go to a log file instead of stderr.
+func logError(format string, args ...any) {
+	path := filepath.Join(config.ConfigDir(), "logs", "notifications.log")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	fmt.Fprintf(f, "%s "+format+"\n", append([]any{time.Now().Format(time.RFC3339)}, args...)...)
+}
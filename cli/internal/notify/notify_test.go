@@ -0,0 +1,158 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/events"
+	"github.com/iheanyi/grove/internal/project"
+)
+
+func TestSetup_WebhookFilters(t *testing.T) {
+	t.Cleanup(events.Reset)
+
+	var received []events.Event
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("missing configured header")
+		}
+		var e events.Event
+		if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		received = append(received, e)
+	}))
+	defer srv.Close()
+
+	cfg := config.Default().Notifications
+	cfg.OnStop = false
+	cfg.Webhooks = []config.WebhookConfig{{
+		URL:     srv.URL,
+		Headers: map[string]string{"Authorization": "Bearer secret"},
+		Events:  []string{"crash", "stop"},
+	}}
+	Setup(cfg)
+
+	events.Publish(events.Event{Type: events.ServerStarted, Server: "api"}) // not in webhook filter
+	events.Publish(events.Event{Type: events.ServerStopped, Server: "api"}) // disabled by on_stop
+	events.Publish(events.Event{Type: events.ServerCrashed, Server: "api", Message: "exit status 1"})
+
+	if len(received) != 1 {
+		t.Fatalf("webhook received %d events, want 1: %+v", len(received), received)
+	}
+	if received[0].Type != events.ServerCrashed || received[0].Server != "api" {
+		t.Errorf("received %+v, want crash for api", received[0])
+	}
+}
+
+func TestSetup_Disabled(t *testing.T) {
+	t.Cleanup(events.Reset)
+
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	cfg := config.Default().Notifications
+	cfg.Enabled = false
+	cfg.Webhooks = []config.WebhookConfig{{URL: srv.URL}}
+	Setup(cfg)
+
+	events.Publish(events.Event{Type: events.ServerCrashed, Server: "api"})
+	if called {
+		t.Error("webhook called with notifications disabled")
+	}
+}
+
+func TestHook_ReceivesEvent(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out")
+
+	Hook(`printf '%s %s ' "$GROVE_EVENT" "$GROVE_SERVER" > ` + out + ` && cat >> ` + out)(events.Event{
+		Type:   events.HealthChanged,
+		Server: "web",
+		Health: "unhealthy",
+	})
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("hook did not run: %v", err)
+	}
+	got := string(data)
+	if !strings.HasPrefix(got, "health web ") {
+		t.Errorf("hook env = %q, want prefix %q", got, "health web ")
+	}
+	if !strings.Contains(got, `"health":"unhealthy"`) {
+		t.Errorf("hook stdin missing event JSON: %q", got)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	tests := []struct {
+		event    events.Event
+		expected string
+	}{
+		{events.Event{Type: events.ServerStarted, Server: "api", URL: "http://localhost:3000"}, "api started at http://localhost:3000"},
+		{events.Event{Type: events.ServerCrashed, Server: "api", Process: "worker", Message: "exit status 1"}, "api (worker) crashed (exit status 1)"},
+		{events.Event{Type: events.HealthChanged, Server: "api", Health: "unhealthy"}, "api is unhealthy"},
+	}
+
+	for _, tt := range tests {
+		if got := Summary(tt.event); got != tt.expected {
+			t.Errorf("Summary() = %q, want %q", got, tt.expected)
+		}
+	}
+}
+
+func TestProjectHooks_OnCrash(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out")
+	grove := "hooks:\n  on_crash:\n    - echo \"$GROVE_NAME $GROVE_EVENT $GROVE_EXIT_CODE\" > out\n  on_healthy:\n    - echo healthy > out\n"
+	if err := os.WriteFile(filepath.Join(dir, ".grove.yaml"), []byte(grove), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	code := 3
+	ProjectHooks(dir)(events.Event{Type: events.ServerCrashed, Server: "web", Path: dir, ExitCode: &code})
+
+	// Hooks run detached
+	var data []byte
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if data, _ = os.ReadFile(out); len(data) > 0 {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if got := strings.TrimSpace(string(data)); got != "web crash 3" {
+		t.Errorf("on_crash hook wrote %q, want %q", got, "web crash 3")
+	}
+}
+
+func TestEventHooks(t *testing.T) {
+	hooks := project.HooksConfig{OnCrash: []string{"crash"}, OnHealthy: []string{"up"}, OnUnhealthy: []string{"down"}}
+
+	tests := []struct {
+		event events.Event
+		want  string
+	}{
+		{events.Event{Type: events.ServerCrashed}, "crash"},
+		{events.Event{Type: events.HealthChanged, Health: "healthy"}, "up"},
+		{events.Event{Type: events.HealthChanged, Health: "unhealthy"}, "down"},
+		{events.Event{Type: events.HealthChanged, Health: "unknown"}, ""},
+		{events.Event{Type: events.ServerStopped}, ""},
+	}
+	for _, tt := range tests {
+		got := strings.Join(eventHooks(hooks, tt.event), "")
+		if got != tt.want {
+			t.Errorf("eventHooks(%s %s) = %q, want %q", tt.event.Type, tt.event.Health, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package notify
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/iheanyi/grove/internal/events"
+	"github.com/iheanyi/grove/internal/project"
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+// SetupProjectHooks subscribes the runner of .grove.yaml's event hooks to
+// the events bus. Unlike the notifiers, they don't depend on the
+// notifications config.
+func SetupProjectHooks(logDir string) {
+	events.Subscribe(ProjectHooks(logDir))
+}
+
+// ProjectHooks returns a handler that runs the on_crash, on_healthy and
+// on_unhealthy hooks of the event's worktree. They run in the background,
+// one after another, with GROVE_NAME and the GROVE_* event variables, and
+// their output is appended to the server's log in logDir.
+func ProjectHooks(logDir string) events.Handler {
+	return func(e events.Event) {
+		if e.Path == "" {
+			return
+		}
+		cfg, err := project.Load(e.Path)
+		if err != nil {
+			return
+		}
+		hooks := eventHooks(cfg.Hooks, e)
+		if len(hooks) == 0 {
+			return
+		}
+
+		cmd := exec.Command("sh", "-c", strings.Join(hooks, "\n"))
+		cmd.Dir = e.Path
+		cmd.Env = append(os.Environ(), hookEnv(e)...)
+		cmd.Env = append(cmd.Env, "GROVE_NAME="+e.Server)
+		// Its own process group, so a slow hook (e.g. db:reset) outlives the
+		// command that published the event
+		cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+		if log, err := os.OpenFile(filepath.Join(logDir, e.Server+".log"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644); err == nil {
+			defer log.Close()
+			cmd.Stdout = log
+			cmd.Stderr = log
+		}
+
+		if err := cmd.Start(); err != nil {
+			logError("%s hook for %s: %v", e.Type, e.Server, err)
+			return
+		}
+		cmd.Process.Release() //nolint:errcheck // Runs detached
+	}
+}
+
+// eventHooks returns the hooks configured for an event
+func eventHooks(hooks project.HooksConfig, e events.Event) []string {
+	switch e.Type {
+	case events.ServerCrashed:
+		return hooks.OnCrash
+	case events.HealthChanged:
+		switch registry.HealthStatus(e.Health) {
+		case registry.HealthHealthy:
+			return hooks.OnHealthy
+		case registry.HealthUnhealthy:
+			return hooks.OnUnhealthy
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/events"
+)
+
+// Webhook returns a handler that POSTs each event as JSON to the webhook URL
+func Webhook(wh config.WebhookConfig) events.Handler {
+	return func(e events.Event) {
+		body, err := json.Marshal(e)
+		if err != nil {
+			logError("webhook %s: %v", wh.URL, err)
+			return
+		}
+		if err := post(wh.URL, wh.Headers, body); err != nil {
+			logError("webhook %s: %v", wh.URL, err)
+		}
+	}
+}
+
+// post sends a JSON body and treats non-2xx responses as errors
+func post(url string, headers map[string]string, body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "grove")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+// Package output writes machine-readable command results. Commands with
+// --json/--format return one of the typed responses in this package, so
+// scripts and the menubar app see the same shapes across commands.
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format is an output format
+type Format string
+
+const (
+	// FormatTable is the human-readable output each command prints
+	FormatTable Format = "table"
+	// FormatJSON writes the response as indented JSON
+	FormatJSON Format = "json"
+	// FormatYAML writes the response as YAML with the same keys as JSON
+	FormatYAML Format = "yaml"
+)
+
+// ParseFormat parses a --format value
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case "", FormatTable:
+		return FormatTable, nil
+	case FormatJSON, FormatYAML:
+		return Format(s), nil
+	}
+	return "", fmt.Errorf("invalid format %q (expected table, json or yaml)", s)
+}
+
+// IsMachine reports whether the format is machine-readable
+func (f Format) IsMachine() bool {
+	return f == FormatJSON || f == FormatYAML
+}
+
+// Write encodes v to w in the given format
+func Write(w io.Writer, format Format, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case FormatJSON:
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	case FormatYAML:
+		return writeYAML(w, data)
+	}
+	return fmt.Errorf("format %q is not machine-readable", format)
+}
+
+// writeYAML re-encodes JSON as YAML. Going through JSON keeps the json tags
+// and field order, so both formats have the same shape.
+func writeYAML(w io.Writer, data []byte) error {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	blockStyle(&node)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// blockStyle clears the flow and quoting styles parsed from JSON so the
+// encoder picks idiomatic YAML
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+// Redirect points os.Stdout at stderr until restore is called and returns
+// the real stdout. Commands run with it in machine formats so their progress
+// messages don't end up in the document.
+func Redirect() (stdout *os.File, restore func()) {
+	stdout = os.Stdout
+	os.Stdout = os.Stderr
+	return stdout, func() { os.Stdout = stdout }
+}
@@ -0,0 +1,195 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/iheanyi/grove/internal/bench"
+	"github.com/iheanyi/grove/internal/checks"
+	"github.com/iheanyi/grove/internal/crash"
+	"github.com/iheanyi/grove/internal/diskusage"
+	"github.com/iheanyi/grove/internal/events"
+	"github.com/iheanyi/grove/internal/freeze"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/stats"
+	"github.com/iheanyi/grove/internal/tasks"
+	"github.com/iheanyi/grove/internal/trash"
+)
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]Format{"": FormatTable, "table": FormatTable, "json": FormatJSON, "yaml": FormatYAML} {
+		got, err := ParseFormat(in)
+		if err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("ParseFormat(xml) should fail")
+	}
+}
+
+func TestWrite_YAMLMatchesJSONKeys(t *testing.T) {
+	result := DeleteResult{Name: "feature", Path: "/code/feature", Warnings: []string{"true", "Server is running"}, Deleted: true}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatYAML, result); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	want := `name: feature
+path: /code/feature
+warnings:
+  - "true"
+  - Server is running
+dry_run: false
+deleted: true
+stopped_server: false
+`
+	if buf.String() != want {
+		t.Errorf("YAML output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+// responses are sample values of every response type, which must validate
+// against their own schema
+var responses = map[string]any{
+	"server": NewServer(&registry.Server{Name: "feature", Port: 3001, Status: registry.StatusRunning, StartedAt: time.Now()}),
+	"stop":   StopResult{Stopped: []Server{{Name: "feature"}}, Errors: []ItemError{{Name: "other", Error: "not running"}}},
+	"delete": DeleteResult{Name: "feature", Warnings: []string{}},
+	"adopt":  AdoptResult{Matched: []AdoptedServer{{Worktree: "feature", State: "new"}}, Unmatched: []DetectedServer{}},
+	"discover": DiscoverResult{
+		Worktrees:  []DiscoveredWorktree{{Name: "feature", Port: 3001}},
+		Registered: []string{"feature"},
+	},
+	"group":    GroupStatus{Group: "shop", Members: []Server{{Name: "api"}}, Missing: []string{"web"}},
+	"routes":   ProxyRoutes{TLD: "localhost", Routes: []Route{{Host: "feature.localhost", Server: "feature", Port: 3001, Kind: "main"}}},
+	"ports":    PortMap{Blocks: []PortBlock{{Repo: "myapp", First: 3100, Last: 3199, Worktrees: []PortAssignment{{Name: "myapp", Port: 3100, Status: "running"}}}}},
+	"lan":      LANAccess{IP: "192.168.1.20", Servers: []LANServer{{Name: "feature", URL: "http://192.168.1.20:13001", LocalURL: "https://feature.localhost", Status: "running"}}},
+	"bench":    BenchReport{Runs: []BenchRun{{Server: "feature", Result: bench.Result{URL: "http://localhost:3001/", Requests: 200, Statuses: map[string]int{"200": 200}}}}},
+	"ci":       CIResult{Runs: []CIRun{{Name: "feature", Path: "/src/feature", Head: "abc123", Checks: []checks.Result{{Name: "lint", Command: "npm run lint", Passed: true, DurationMs: 1200}}}}},
+	"du":       DiskUsageReport{Repos: []RepoDiskUsage{{Repo: "myapp", TotalBytes: 2048, Worktrees: []WorktreeDiskUsage{{Name: "feature", Path: "/src/feature", Usage: diskusage.Usage{Total: 2048, Deps: map[string]uint64{"node_modules": 1024}}, LastActivity: TimePtr(time.Now())}}}}, TotalBytes: 2048, Advice: []string{}},
+	"freeze":   FreezeResult{Path: "/tmp/frozen.json", FrozenAt: time.Now(), Proxy: true, Servers: []freeze.Server{{Name: "feature", Path: "/src/feature", Command: []string{"npm", "run", "dev"}, Port: 3001}}},
+	"stats":    UsageStats{Recording: true, Summary: stats.Summary{Commands: 12, TopCommands: []stats.Count{{Name: "start", Count: 5}}, ServerStarts: 3, AgentSeconds: 5400, Worktrees: []stats.WorktreeUsage{{Name: "feature", Commands: 4, AgentSeconds: 5400}}, Days: []stats.Day{{Date: "2026-01-01", Commands: 12}}}},
+	"context":  ContextList{Current: "work", Contexts: []Context{{Name: "default", Dir: "/home/me/.config/grove", PortMin: 3000, PortMax: 3999}, {Name: "work", Dir: "/home/me/.config/grove/contexts/work", PortMin: 4000, PortMax: 4999, Current: true}}},
+	"trash":    TrashList{Retention: "14d", Entries: []trash.Entry{{ID: "feature-20260101-120000", Name: "feature", Branch: "feature", Commit: "abc123", Files: 3, Bundle: true}}},
+	"thaw":     ThawResult{Proxy: "started", Servers: []ThawedServer{{Name: "feature", Port: 3001, Status: "failed", Error: "exit status 1"}}},
+	"vrt":      VRTReport{A: "main", B: "feature", Routes: []VRTRoute{{Route: "/", A: "/tmp/a/index.png", B: "/tmp/b/index.png", Diff: "/tmp/diff/index.png", ChangedPercent: 1.5, Changed: true}}},
+	"proxy":    ProxyStatus{Cert: CertStatus{Source: "mkcert", Uncovered: []string{"feature.localhost"}}},
+	"activity": ActivityLog{Events: []events.Event{{Type: events.AgentLimitExceeded, Server: "feature", PID: 42, Message: "ran for 3h0m0s, limit 3h0m0s", Time: time.Now()}}},
+	"crashes":  CrashList{Crashes: []crash.Report{*crash.New("feature", "", time.Now(), nil)}},
+	"diff-env": EnvDiff{From: "main", To: "feature", Vars: []EnvDiffVar{{Name: "PORT", From: &EnvValue{Value: "3000", Source: "grove"}, To: &EnvValue{Value: "3100", Source: "grove"}}}},
+	"tasks":    TaskList{Worktrees: []WorktreeTasks{{Name: "feature", Path: "/src/feature", Tasks: []tasks.Task{{ID: "auth", Title: "Add OAuth", Status: tasks.StatusInProgress, Source: "tasuku"}}}}},
+	"doctor":   DoctorResult{Checks: []Check{{Name: "Registry", Status: "ok"}}, Servers: []Check{}},
+	"logs":     LogLine{Time: TimePtr(time.Now()), Server: "feature", Line: "ERROR boom", Level: "error"},
+}
+
+func TestResponsesMatchSchema(t *testing.T) {
+	for name, v := range responses {
+		t.Run(name, func(t *testing.T) {
+			data, err := json.Marshal(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var doc any
+			if err := json.Unmarshal(data, &doc); err != nil {
+				t.Fatal(err)
+			}
+			if err := validate(Schema(v), doc, "$"); err != nil {
+				t.Errorf("%s: %v\n%s", name, err, data)
+			}
+		})
+	}
+}
+
+func TestSchema_RejectsWrongShape(t *testing.T) {
+	schema := Schema(StopResult{})
+	for _, doc := range []string{
+		`{}`,
+		`{"stopped": null}`,
+		`{"stopped": [{"name": 1}]}`,
+		`{"stopped": [], "extra": true}`,
+	} {
+		var v any
+		if err := json.Unmarshal([]byte(doc), &v); err != nil {
+			t.Fatal(err)
+		}
+		if validate(schema, v, "$") == nil {
+			t.Errorf("%s should not validate", doc)
+		}
+	}
+}
+
+// validate checks doc against the subset of JSON Schema that Schema emits
+func validate(schema map[string]any, doc any, path string) error {
+	switch schema["type"] {
+	case "object":
+		obj, ok := doc.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: expected object, got %T", path, doc)
+		}
+		// Maps have no required keys
+		required, _ := schema["required"].([]string)
+		for _, name := range required {
+			if _, ok := obj[name]; !ok {
+				return fmt.Errorf("%s: missing required %q", path, name)
+			}
+		}
+		props, _ := schema["properties"].(map[string]any)
+		for key, value := range obj {
+			var sub map[string]any
+			if props != nil {
+				if p, ok := props[key]; ok {
+					sub = p.(map[string]any)
+				}
+			}
+			if sub == nil {
+				additional, ok := schema["additionalProperties"].(map[string]any)
+				if !ok {
+					return fmt.Errorf("%s: unexpected property %q", path, key)
+				}
+				sub = additional
+			}
+			if err := validate(sub, value, path+"."+key); err != nil {
+				return err
+			}
+		}
+	case "array":
+		arr, ok := doc.([]any)
+		if !ok {
+			return fmt.Errorf("%s: expected array, got %T", path, doc)
+		}
+		for i, item := range arr {
+			if err := validate(schema["items"].(map[string]any), item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case "string":
+		s, ok := doc.(string)
+		if !ok {
+			return fmt.Errorf("%s: expected string, got %T", path, doc)
+		}
+		if schema["format"] == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, s); err != nil {
+				return fmt.Errorf("%s: %v", path, err)
+			}
+		}
+	case "integer":
+		n, ok := doc.(float64)
+		if !ok || n != float64(int64(n)) {
+			return fmt.Errorf("%s: expected integer, got %v", path, doc)
+		}
+	case "boolean":
+		if _, ok := doc.(bool); !ok {
+			return fmt.Errorf("%s: expected boolean, got %T", path, doc)
+		}
+	case "number":
+		if _, ok := doc.(float64); !ok {
+			return fmt.Errorf("%s: expected number, got %T", path, doc)
+		}
+	default:
+		return fmt.Errorf("%s: unsupported schema type %v", path, schema["type"])
+	}
+	return nil
+}
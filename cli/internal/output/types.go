@@ -0,0 +1,463 @@
+package output
+
+import (
+	"time"
+
+	"github.com/iheanyi/grove/internal/bench"
+	"github.com/iheanyi/grove/internal/checks"
+	"github.com/iheanyi/grove/internal/crash"
+	"github.com/iheanyi/grove/internal/diskusage"
+	"github.com/iheanyi/grove/internal/events"
+	"github.com/iheanyi/grove/internal/freeze"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/smoke"
+	"github.com/iheanyi/grove/internal/stats"
+	"github.com/iheanyi/grove/internal/tasks"
+	"github.com/iheanyi/grove/internal/trash"
+)
+
+// Server is a dev server (start, stop)
+type Server struct {
+	Name      string     `json:"name"`
+	Status    string     `json:"status"`
+	URL       string     `json:"url"`
+	Port      int        `json:"port"`
+	PID       int        `json:"pid,omitempty"`
+	Path      string     `json:"path"`
+	Branch    string     `json:"branch,omitempty"`
+	Backend   string     `json:"backend,omitempty"`
+	Health    string     `json:"health,omitempty"`
+	Command   []string   `json:"command,omitempty"`
+	LogFile   string     `json:"log_file,omitempty"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	StoppedAt *time.Time `json:"stopped_at,omitempty"`
+}
+
+// NewServer converts a registry server
+func NewServer(s *registry.Server) Server {
+	return Server{
+		Name:      s.Name,
+		Status:    string(s.Status),
+		URL:       s.URL,
+		Port:      s.Port,
+		PID:       s.PID,
+		Path:      s.Path,
+		Branch:    s.Branch,
+		Backend:   s.Backend,
+		Health:    string(s.Health),
+		Command:   s.Command,
+		LogFile:   s.LogFile,
+		StartedAt: TimePtr(s.StartedAt),
+		StoppedAt: TimePtr(s.StoppedAt),
+	}
+}
+
+// StopResult is the result of 'grove stop'
+type StopResult struct {
+	Stopped []Server    `json:"stopped"`
+	Errors  []ItemError `json:"errors,omitempty"`
+	// Processes are the PIDs of agents and editors stopped with --worktree
+	Processes []int `json:"processes,omitempty"`
+}
+
+// ItemError is a failure for one item of a batch operation
+type ItemError struct {
+	Name  string `json:"name"`
+	Error string `json:"error"`
+}
+
+// GroupStatus is the result of 'grove group status'. Missing lists members
+// that aren't registered worktrees.
+type GroupStatus struct {
+	Group   string   `json:"group"`
+	Members []Server `json:"members"`
+	Missing []string `json:"missing,omitempty"`
+}
+
+// CrashList is the result of 'grove crashes', newest first
+type CrashList struct {
+	Crashes []crash.Report `json:"crashes"`
+}
+
+// ActivityLog is the result of 'grove activity', oldest first
+type ActivityLog struct {
+	Events []events.Event `json:"events"`
+}
+
+// UsageStats is the result of 'grove stats'
+type UsageStats struct {
+	// Recording is whether usage is being recorded (usage_stats)
+	Recording bool          `json:"recording"`
+	Summary   stats.Summary `json:"summary"`
+}
+
+// TaskList is the result of 'grove tasks'
+type TaskList struct {
+	Worktrees []WorktreeTasks `json:"worktrees"`
+}
+
+// WorktreeTasks are the tasks of one worktree
+type WorktreeTasks struct {
+	Name  string       `json:"name"`
+	Path  string       `json:"path"`
+	Tasks []tasks.Task `json:"tasks"`
+}
+
+// EnvDiff is the result of 'grove diff-env': the variables whose resolved
+// values differ between two worktrees
+type EnvDiff struct {
+	From string       `json:"from"`
+	To   string       `json:"to"`
+	Vars []EnvDiffVar `json:"vars"`
+}
+
+// EnvDiffVar is one variable in both worktrees; From or To is omitted where
+// it isn't set
+type EnvDiffVar struct {
+	Name string    `json:"name"`
+	From *EnvValue `json:"from,omitempty"`
+	To   *EnvValue `json:"to,omitempty"`
+}
+
+// EnvValue is a resolved variable. Source is where it's set: "grove"
+// (PORT and the URL variable), ".grove.yaml", "database", "depends_on" or
+// an env file's path (from env_files or --env-file).
+type EnvValue struct {
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// EnvResult is the result of 'grove env', sorted by name
+type EnvResult struct {
+	Name string   `json:"name"`
+	Vars []EnvVar `json:"vars"`
+}
+
+// EnvVar is a variable set for a server, with its source as in EnvValue
+type EnvVar struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// DeleteResult is the result of 'grove delete'
+type DeleteResult struct {
+	Name          string   `json:"name"`
+	Path          string   `json:"path"`
+	Warnings      []string `json:"warnings"`
+	DryRun        bool     `json:"dry_run"`
+	Deleted       bool     `json:"deleted"`
+	StoppedServer bool     `json:"stopped_server"`
+	// Processes are the PIDs of agents and editors stopped with --cascade
+	Processes []int  `json:"processes,omitempty"`
+	LogFile   string `json:"log_file,omitempty"`
+	Database  string `json:"database,omitempty"`
+	// Branch is the worktree's branch, and BaseBranch the default branch
+	// it was checked against, with --merge-check
+	Branch     string `json:"branch,omitempty"`
+	BaseBranch string `json:"base_branch,omitempty"`
+	Merged     *bool  `json:"merged,omitempty"`
+	// DeletedBranch is set when --with-branch deleted the local branch,
+	// and DeletedRemoteBranch to the remote branch --with-remote deleted
+	DeletedBranch       bool   `json:"deleted_branch,omitempty"`
+	DeletedRemoteBranch string `json:"deleted_remote_branch,omitempty"`
+	// Trash is the ID of the trash entry holding the uncommitted changes
+	// of a worktree deleted with --force
+	Trash string `json:"trash,omitempty"`
+}
+
+// AdoptResult is the result of 'grove adopt'
+type AdoptResult struct {
+	Matched   []AdoptedServer  `json:"matched"`
+	Unmatched []DetectedServer `json:"unmatched"`
+	DryRun    bool             `json:"dry_run"`
+	Adopted   int              `json:"adopted"`
+}
+
+// AdoptedServer is a running dev server matched to a worktree. State is
+// "new", "port_change" or "already_adopted".
+type AdoptedServer struct {
+	Worktree string `json:"worktree"`
+	Port     int    `json:"port"`
+	OldPort  int    `json:"old_port,omitempty"`
+	PID      int    `json:"pid"`
+	Type     string `json:"type"`
+	State    string `json:"state"`
+	Adopted  bool   `json:"adopted"`
+	Error    string `json:"error,omitempty"`
+}
+
+// DetectedServer is a running dev server that matched no worktree
+type DetectedServer struct {
+	Port    int    `json:"port"`
+	PID     int    `json:"pid"`
+	Type    string `json:"type"`
+	WorkDir string `json:"work_dir"`
+}
+
+// DiscoverResult is the result of 'grove discover'
+type DiscoverResult struct {
+	Path       string               `json:"path"`
+	Worktrees  []DiscoveredWorktree `json:"worktrees"`
+	New        int                  `json:"new"`
+	Registered []string             `json:"registered"`
+}
+
+// DiscoveredWorktree is a git repository or worktree found by discover
+type DiscoveredWorktree struct {
+	Name       string `json:"name"`
+	Path       string `json:"path"`
+	Branch     string `json:"branch"`
+	IsWorktree bool   `json:"is_worktree"`
+	HasConfig  bool   `json:"has_config"`
+	Registered bool   `json:"registered"`
+	Running    bool   `json:"running"`
+	Port       int    `json:"port,omitempty"`
+}
+
+// ProxyStatus is the result of 'grove proxy status'
+type ProxyStatus struct {
+	Running   bool       `json:"running"`
+	PID       int        `json:"pid,omitempty"`
+	HTTPPort  int        `json:"http_port"`
+	HTTPSPort int        `json:"https_port"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	Cert      CertStatus `json:"cert"`
+}
+
+// ProxyRoutes is the result of 'grove proxy routes': the hosts routed to
+// running servers
+type ProxyRoutes struct {
+	TLD    string  `json:"tld"`
+	Routes []Route `json:"routes"`
+}
+
+// Route maps a host to a server's port. Kind is "main", "subdomain" (mapped
+// in .grove.yaml), "wildcard" (every other subdomain, e.g.
+// *.feature.localhost) or "path" (path URL mode, where Host is the host and
+// path, e.g. grove.localhost/feature/).
+type Route struct {
+	Host   string `json:"host"`
+	Server string `json:"server"`
+	Port   int    `json:"port"`
+	Kind   string `json:"kind"`
+}
+
+// PortMap is the result of 'grove ports': the registered worktrees' ports,
+// grouped by their repository's port_bases block
+type PortMap struct {
+	Blocks []PortBlock `json:"blocks"`
+}
+
+// PortBlock is the block of ports of a repository in port_bases. Repo is
+// empty for the worktrees of other repositories, whose ports are between
+// port_min and port_max.
+type PortBlock struct {
+	Repo      string           `json:"repo,omitempty"`
+	First     int              `json:"first"`
+	Last      int              `json:"last"`
+	Worktrees []PortAssignment `json:"worktrees"`
+}
+
+// PortAssignment is a worktree's port. OutsideBlock is set for a port
+// outside its repository's block, which 'grove start' replaces unless it
+// was set with --port or .grove.yaml.
+type PortAssignment struct {
+	Name         string `json:"name"`
+	Port         int    `json:"port"`
+	Status       string `json:"status"`
+	OutsideBlock bool   `json:"outside_block,omitempty"`
+}
+
+// LANAccess is the result of 'grove lan': the URLs devices on the local
+// network reach servers at
+type LANAccess struct {
+	// IP is this machine's address on the network
+	IP      string      `json:"ip"`
+	Servers []LANServer `json:"servers"`
+}
+
+// LANServer is a server's URL on the network. Direct is set when the URL is
+// the server's own port rather than the proxy's, so the server must listen
+// on every interface.
+type LANServer struct {
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+	LocalURL string `json:"local_url"`
+	// MDNSURL is the URL by the name advertised over mDNS, if enabled
+	MDNSURL string `json:"mdns_url,omitempty"`
+	Status  string `json:"status"`
+	Direct  bool   `json:"direct,omitempty"`
+}
+
+// CertStatus describes the proxy's TLS certificates. Source is "caddy" for
+// Caddy's internal CA or "mkcert"; State is "valid", "expiring" or
+// "expired" for mkcert certificates.
+type CertStatus struct {
+	Source    string     `json:"source"`
+	State     string     `json:"state,omitempty"`
+	CertFile  string     `json:"cert_file,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Uncovered []string   `json:"uncovered,omitempty"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// DoctorResult is the result of 'grove doctor'
+type DoctorResult struct {
+	OK      bool    `json:"ok"`
+	Checks  []Check `json:"checks"`
+	Servers []Check `json:"servers"`
+}
+
+// Check is one diagnostic. Status is "ok", "fail", "warn" or "skipped";
+// Result is the short human-readable outcome and Hint how to fix a failure.
+type Check struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Result string `json:"result"`
+	Hint   string `json:"hint,omitempty"`
+}
+
+// LogLine is a line of a server's log, as 'grove logs --json' writes one per
+// line of output. Time is the line's timestamp, or the last one before it
+// for lines without (e.g. stack traces); Level is "error", "warn", "info" or
+// "debug" when the line names one.
+type LogLine struct {
+	Time   *time.Time `json:"ts,omitempty"`
+	Server string     `json:"server"`
+	Line   string     `json:"line"`
+	Level  string     `json:"level,omitempty"`
+}
+
+// SmokeResult is the result of 'grove smoke'
+type SmokeResult struct {
+	Server string         `json:"server"`
+	URL    string         `json:"url"`
+	Passed bool           `json:"passed"`
+	Checks []smoke.Result `json:"checks"`
+}
+
+// BenchReport is the result of 'grove bench': one run per server
+type BenchReport struct {
+	Runs []BenchRun `json:"runs"`
+}
+
+// BenchRun is a server's benchmark
+type BenchRun struct {
+	Server string       `json:"server"`
+	Result bench.Result `json:"result"`
+}
+
+// CIResult is the result of 'grove ci': a run per worktree
+type CIResult struct {
+	Runs []CIRun `json:"runs"`
+}
+
+// CIRun is a worktree's checks
+type CIRun struct {
+	Name   string          `json:"name"`
+	Path   string          `json:"path"`
+	Head   string          `json:"head,omitempty"`
+	Dirty  bool            `json:"dirty"`
+	Passed bool            `json:"passed"`
+	Checks []checks.Result `json:"checks"`
+}
+
+// DiskUsageReport is the result of 'grove du'
+type DiskUsageReport struct {
+	Repos      []RepoDiskUsage `json:"repos"`
+	TotalBytes uint64          `json:"total_bytes"`
+	// Advice suggests ways to share dependencies and worktrees to clean
+	Advice []string `json:"advice"`
+}
+
+// RepoDiskUsage is the disk usage of a repository's worktrees
+type RepoDiskUsage struct {
+	Repo       string              `json:"repo"`
+	TotalBytes uint64              `json:"total_bytes"`
+	Worktrees  []WorktreeDiskUsage `json:"worktrees"`
+}
+
+// WorktreeDiskUsage is a worktree's disk usage
+type WorktreeDiskUsage struct {
+	Name         string          `json:"name"`
+	Path         string          `json:"path"`
+	Usage        diskusage.Usage `json:"usage"`
+	LastActivity *time.Time      `json:"last_activity,omitempty"`
+}
+
+// VRTReport is the result of 'grove vrt'
+type VRTReport struct {
+	A      string     `json:"a"`
+	B      string     `json:"b"`
+	Report string     `json:"report"`
+	Routes []VRTRoute `json:"routes"`
+}
+
+// VRTRoute is one route's screenshots and how much they differ. The files
+// are empty when the route couldn't be captured.
+type VRTRoute struct {
+	Route          string  `json:"route"`
+	A              string  `json:"a"`
+	B              string  `json:"b"`
+	Diff           string  `json:"diff"`
+	ChangedPercent float64 `json:"changed_percent"`
+	Changed        bool    `json:"changed"`
+	Error          string  `json:"error,omitempty"`
+}
+
+// FreezeResult is the result of 'grove freeze': the snapshot it wrote
+type FreezeResult struct {
+	Path     string          `json:"path"`
+	FrozenAt time.Time       `json:"frozen_at"`
+	Proxy    bool            `json:"proxy"`
+	Servers  []freeze.Server `json:"servers"`
+}
+
+// ContextList is the result of 'grove context list'
+type ContextList struct {
+	Current  string    `json:"current"`
+	Contexts []Context `json:"contexts"`
+}
+
+// Context is a named registry with its own config and port range
+type Context struct {
+	Name    string `json:"name"`
+	Dir     string `json:"dir"`
+	PortMin int    `json:"port_min"`
+	PortMax int    `json:"port_max"`
+	Current bool   `json:"current"`
+}
+
+// TrashList is the result of 'grove trash'
+type TrashList struct {
+	// Retention is how long entries are kept, e.g. 14d, or "0" when the
+	// trash is off
+	Retention string        `json:"retention"`
+	Entries   []trash.Entry `json:"entries"`
+}
+
+// ThawResult is the result of 'grove thaw'
+type ThawResult struct {
+	// Proxy is "started", "running" or "failed", or empty if it wasn't
+	// frozen running
+	Proxy   string         `json:"proxy,omitempty"`
+	Servers []ThawedServer `json:"servers"`
+}
+
+// ThawedServer is a frozen server and whether it's running again
+type ThawedServer struct {
+	Name string `json:"name"`
+	Port int    `json:"port"`
+	// Status is "started", "running" (it already was) or "failed"
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// TimePtr returns nil for the zero time so it's omitted
+func TimePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -63,6 +63,17 @@ func (a *Allocator) AllocateWithFallback(name string, usedPorts map[int]bool) (i
 	return 0, fmt.Errorf("no available ports in range %d-%d", a.minPort, a.maxPort)
 }
 
+// AllocateNext returns the lowest port in range that's neither used nor
+// taken, so ports are handed out in order
+func (a *Allocator) AllocateNext(usedPorts map[int]bool) (int, error) {
+	for port := a.minPort; port <= a.maxPort; port++ {
+		if !usedPorts[port] && IsAvailable(port) {
+			return port, nil
+		}
+	}
+	return 0, fmt.Errorf("no available ports in range %d-%d", a.minPort, a.maxPort)
+}
+
 // Range returns the port range
 func (a *Allocator) Range() (int, int) {
 	return a.minPort, a.maxPort
@@ -1,12 +1,14 @@
 package port
 
 import (
+	"context"
 	"fmt"
 	"net"
-	"os/exec"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/iheanyi/grove/internal/execx"
 )
 
 // IsAvailable checks if a port is available for binding.
@@ -96,8 +98,7 @@ func FindAvailablePort(minPort, maxPort int) (int, error) {
 // Returns 0 if no process is found or if the detection fails.
 func GetListenerPID(port int) int {
 	// Use lsof to find the process listening on the port
-	cmd := exec.Command("lsof", "-i", fmt.Sprintf(":%d", port), "-sTCP:LISTEN", "-t")
-	output, err := cmd.Output()
+	output, err := execx.OS.Output(context.Background(), execx.Query("lsof", "-i", fmt.Sprintf(":%d", port), "-sTCP:LISTEN", "-t"))
 	if err != nil {
 		return 0
 	}
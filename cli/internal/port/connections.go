@@ -0,0 +1,108 @@
+package port
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/iheanyi/grove/internal/execx"
+)
+
+// Connections returns how many established TCP connections each of ports
+// is serving. Ports with none are left out.
+func Connections(ports []int) (map[int]int, error) {
+	want := make(map[int]bool, len(ports))
+	for _, p := range ports {
+		want[p] = true
+	}
+
+	if runtime.GOOS == "linux" {
+		counts := make(map[int]int)
+		for _, table := range []string{"/proc/net/tcp", "/proc/net/tcp6"} {
+			f, err := os.Open(table)
+			if err != nil {
+				if os.IsNotExist(err) {
+					continue
+				}
+				return nil, err
+			}
+			err = countProcNetTCP(f, want, counts)
+			f.Close()
+			if err != nil {
+				return nil, err
+			}
+		}
+		return counts, nil
+	}
+
+	output, err := execx.OS.Output(context.Background(), execx.Query("lsof", "-nP", "-iTCP", "-sTCP:ESTABLISHED", "-Fn"))
+	if err != nil {
+		// lsof exits 1 when nothing matches
+		if len(output) == 0 {
+			return map[int]int{}, nil
+		}
+		return nil, err
+	}
+	return countLsof(string(output), want), nil
+}
+
+// tcpEstablished is the ESTABLISHED state in /proc/net/tcp
+const tcpEstablished = "01"
+
+// countProcNetTCP counts the established connections of a /proc/net/tcp
+// table whose local port is one of want
+func countProcNetTCP(r io.Reader, want map[int]bool, counts map[int]int) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Scan() // header
+	for scanner.Scan() {
+		// sl local_address rem_address st ...
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || fields[3] != tcpEstablished {
+			continue
+		}
+		_, hexPort, ok := strings.Cut(fields[1], ":")
+		if !ok {
+			continue
+		}
+		p, err := strconv.ParseInt(hexPort, 16, 32)
+		if err != nil {
+			continue
+		}
+		if want[int(p)] {
+			counts[int(p)]++
+		}
+	}
+	return scanner.Err()
+}
+
+// countLsof counts the connections in lsof -Fn output, whose name lines
+// read "n<local>-><remote>", by local port
+func countLsof(output string, want map[int]bool) map[int]int {
+	counts := make(map[int]int)
+	for _, line := range strings.Split(output, "\n") {
+		name, ok := strings.CutPrefix(line, "n")
+		if !ok {
+			continue
+		}
+		local, _, ok := strings.Cut(name, "->")
+		if !ok {
+			continue
+		}
+		i := strings.LastIndexByte(local, ':')
+		if i < 0 {
+			continue
+		}
+		p, err := strconv.Atoi(local[i+1:])
+		if err != nil {
+			continue
+		}
+		if want[p] {
+			counts[p]++
+		}
+	}
+	return counts
+}
@@ -0,0 +1,32 @@
+package port
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCountProcNetTCP(t *testing.T) {
+	table := `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 0100007F:0BB8 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 1 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:0BB8 0100007F:D431 01 00000000:00000000 00:00000000 00000000  1000        0 2 1 0000000000000000 20 4 30 10 -1
+   2: 0100007F:0BB8 0100007F:D432 01 00000000:00000000 00:00000000 00000000  1000        0 3 1 0000000000000000 20 4 30 10 -1
+   3: 0100007F:D431 0100007F:0BB8 01 00000000:00000000 00:00000000 00000000  1000        0 4 1 0000000000000000 20 4 30 10 -1
+   4: 0100007F:0FA0 0100007F:D433 06 00000000:00000000 00:00000000 00000000  1000        0 5 1 0000000000000000 20 4 30 10 -1
+`
+	counts := make(map[int]int)
+	if err := countProcNetTCP(strings.NewReader(table), map[int]bool{3000: true, 4000: true}, counts); err != nil {
+		t.Fatal(err)
+	}
+	// The listener, the client end and the TIME_WAIT connection don't count
+	if want := map[int]int{3000: 2}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("counts = %v, want %v", counts, want)
+	}
+}
+
+func TestCountLsof(t *testing.T) {
+	output := "p123\nf20\nn127.0.0.1:3000->127.0.0.1:54321\nf21\nn[::1]:3000->[::1]:54322\np456\nf7\nn127.0.0.1:54321->127.0.0.1:3000\n"
+	if got, want := countLsof(output, map[int]bool{3000: true}), map[int]int{3000: 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("countLsof = %v, want %v", got, want)
+	}
+}
@@ -1,8 +1,10 @@
 package project
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -26,24 +28,198 @@ type Config struct {
 	// Env contains environment variables to set
 	Env map[string]string `yaml:"env,omitempty"`
 
+	// EnvFiles are dotenv files loaded into the server's environment, in
+	// order, relative to the worktree (e.g., [.env, .env.local]). Later
+	// files override earlier ones, env overrides them all, and missing
+	// files are skipped.
+	EnvFiles []string `yaml:"env_files,omitempty"`
+
 	// HealthCheck configures health checking
 	HealthCheck HealthCheckConfig `yaml:"health_check,omitempty"`
 
+	// ReadyLogPattern is a regular expression matching the line the server
+	// prints once it's ready (e.g., "Listening on"). 'grove start' keeps the
+	// server starting until its output matches, then marks it running and
+	// healthy.
+	ReadyLogPattern string `yaml:"ready_log_pattern,omitempty"`
+
+	// ReadyTimeout is how long to wait for ReadyLogPattern before marking
+	// the server running anyway (default: health_check_timeout)
+	ReadyTimeout time.Duration `yaml:"ready_timeout,omitempty"`
+
 	// Hooks defines lifecycle hooks
 	Hooks HooksConfig `yaml:"hooks,omitempty"`
 
 	// Services defines multiple services (like docker-compose)
 	Services map[string]ServiceConfig `yaml:"services,omitempty"`
 
-	// DependsOn defines service dependencies
-	DependsOn map[string][]string `yaml:"depends_on,omitempty"`
+	// DependsOn names servers (registered worktrees) that 'grove start'
+	// brings up and waits for before this one. Their URLs are injected as
+	// <NAME>_URL, e.g. API_URL.
+	DependsOn Dependencies `yaml:"depends_on,omitempty"`
+
+	// Processes defines named processes that run together under one server
+	// entry, Procfile-style (e.g., web: bin/rails s, jobs: bin/sidekiq)
+	Processes map[string]string `yaml:"processes,omitempty"`
+
+	// WebProcess names the process that receives the allocated PORT.
+	// Defaults to "web" if present, otherwise the first process by name.
+	WebProcess string `yaml:"web_process,omitempty"`
+
+	// Subdomains routes subdomains to a process name or port in subdomain
+	// URL mode (e.g., api: 3101, app: web). Unmapped subdomains are routed
+	// to the server's main port.
+	Subdomains map[string]string `yaml:"subdomains,omitempty"`
+
+	// Autostart lets the proxy start this worktree's server on the first
+	// request to it while it's stopped (subdomain mode)
+	Autostart bool `yaml:"autostart,omitempty"`
+
+	// Database provisions a database per worktree
+	Database DatabaseConfig `yaml:"database,omitempty"`
+
+	// Cache seeds a new worktree's dependencies from the main worktree
+	Cache CacheConfig `yaml:"cache,omitempty"`
+
+	// Backend runs the server with something other than a local process:
+	// "compose" runs 'docker compose up' with a project per worktree
+	Backend string `yaml:"backend,omitempty"`
+
+	// Compose configures the compose backend
+	Compose ComposeConfig `yaml:"compose,omitempty"`
+
+	// Screenshots are the paths 'grove review --screenshots' captures
+	// (default: /)
+	Screenshots []string `yaml:"screenshots,omitempty"`
+
+	// Smoke are the HTTP checks 'grove smoke' runs against the server
+	Smoke []SmokeCheck `yaml:"smoke,omitempty"`
+
+	// FatalLogPatterns are regular expressions for output lines that mean
+	// the server is broken (e.g., EADDRINUSE); a match marks it unhealthy
+	// and notifies right away. Setting them replaces the defaults (see
+	// crash.DefaultFatalPatterns); an empty list turns detection off.
+	FatalLogPatterns []string `yaml:"fatal_log_patterns,omitempty"`
+
+	// Checks are the commands 'grove ci' runs in the worktree, by name
+	// (e.g., lint: npm run lint, test: npm test). They run in name order.
+	Checks map[string]string `yaml:"checks,omitempty"`
+
+	// AgentPrompt is the first prompt 'grove agent launch' gives the agent.
+	// It may use {name}, {branch}, {path}, {url}, {port}, {task} and
+	// {task_title}.
+	AgentPrompt string `yaml:"agent_prompt,omitempty"`
+}
+
+// SmokeCheck is an HTTP request 'grove smoke' makes and what the response
+// must look like
+type SmokeCheck struct {
+	// Name labels the check in results (default: method and path)
+	Name string `yaml:"name,omitempty"`
+
+	// Method is the HTTP method (default GET)
+	Method string `yaml:"method,omitempty"`
+
+	// Path is requested from the server's URL (e.g., "/login")
+	Path string `yaml:"path"`
+
+	// Headers are sent with the request
+	Headers map[string]string `yaml:"headers,omitempty"`
+
+	// Body is sent with the request
+	Body string `yaml:"body,omitempty"`
+
+	// Status is the expected status code (default: any below 400)
+	Status int `yaml:"status,omitempty"`
+
+	// Contains are substrings the response body must contain
+	Contains []string `yaml:"contains,omitempty"`
+}
+
+// ComposeConfig configures the Docker Compose backend
+type ComposeConfig struct {
+	// Files are the compose files to use (default: compose's own lookup)
+	Files []string `yaml:"files,omitempty"`
+
+	// Service is the service whose published port is the server's port.
+	// Defaults to the first service that publishes a port.
+	Service string `yaml:"service,omitempty"`
+}
+
+// DatabaseConfig provisions a database for each worktree created with
+// 'grove new' by cloning a template database, and drops it on 'grove delete'
+type DatabaseConfig struct {
+	// Provider is the database server: postgres or mysql
+	Provider string `yaml:"provider,omitempty"`
+
+	// Template is the database to clone (e.g., myapp_dev). Worktree
+	// databases are named after it: myapp_dev -> myapp_<branch>.
+	Template string `yaml:"template,omitempty"`
+
+	// URL is the server to connect to, without a database name
+	// (default postgres://localhost:5432 or mysql://root@localhost:3306)
+	URL string `yaml:"url,omitempty"`
+
+	// Env is the variable the worktree database URL is injected as
+	// (default DATABASE_URL)
+	Env string `yaml:"env,omitempty"`
+}
+
+// Enabled returns true if a database provider is configured
+func (d DatabaseConfig) Enabled() bool {
+	return d.Provider != "" && d.Template != ""
 }
 
+// CacheConfig seeds the dependencies of each worktree created with 'grove
+// new' from the main worktree, so they needn't be installed from scratch
+type CacheConfig struct {
+	// Preset fills in paths and install for a package manager: npm, yarn,
+	// pnpm or bundler
+	Preset string `yaml:"preset,omitempty"`
+
+	// Paths are the directories to copy from the main worktree, relative
+	// to its root; globs are allowed (e.g., packages/*/node_modules)
+	Paths []string `yaml:"paths,omitempty"`
+
+	// Mode is how files are copied: hardlink (the default; files are
+	// shared until a package manager replaces them) or copy
+	Mode string `yaml:"mode,omitempty"`
+
+	// Install runs in the new worktree afterwards, to bring what was copied
+	// in line with its lockfile. {main} is the main worktree's path.
+	Install string `yaml:"install,omitempty"`
+}
+
+// Enabled returns true if anything is configured to seed or install
+func (c CacheConfig) Enabled() bool {
+	return c.Preset != "" || len(c.Paths) > 0 || c.Install != ""
+}
+
+// Health check probe types (health_check.check in .grove.yaml)
+const (
+	HealthCheckHTTP    = "http"
+	HealthCheckTCP     = "tcp"
+	HealthCheckGRPC    = "grpc"
+	HealthCheckCommand = "command"
+)
+
 // HealthCheckConfig configures health checking
 type HealthCheckConfig struct {
+	// Check is how health is probed: http (the default), tcp (the port
+	// accepts connections), grpc (the gRPC health protocol) or command
+	Check string `yaml:"check,omitempty"`
+
 	// Path is the HTTP path to check (e.g., "/health")
 	Path string `yaml:"path,omitempty"`
 
+	// Command is run from the worktree for command checks, which pass when
+	// it exits 0
+	Command string `yaml:"command,omitempty"`
+
+	// GRPCService is the service the grpc check asks about; empty asks
+	// about the server as a whole
+	GRPCService string `yaml:"grpc_service,omitempty"`
+
 	// Timeout is how long to wait for the health check
 	Timeout time.Duration `yaml:"timeout,omitempty"`
 
@@ -51,6 +227,19 @@ type HealthCheckConfig struct {
 	Interval time.Duration `yaml:"interval,omitempty"`
 }
 
+// Probe returns the check's probe type: Check, or command when only
+// Command is set, or http
+func (h HealthCheckConfig) Probe() string {
+	switch {
+	case h.Check != "":
+		return h.Check
+	case h.Command != "":
+		return HealthCheckCommand
+	default:
+		return HealthCheckHTTP
+	}
+}
+
 // HooksConfig defines lifecycle hooks
 type HooksConfig struct {
 	// BeforeStart runs before the server starts
@@ -61,6 +250,35 @@ type HooksConfig struct {
 
 	// BeforeStop runs before the server stops
 	BeforeStop []string `yaml:"before_stop,omitempty"`
+
+	// OnCrash runs when the server (or one of its processes) exits
+	// unexpectedly
+	OnCrash []string `yaml:"on_crash,omitempty"`
+
+	// OnHealthy runs when the server's health check starts passing again
+	OnHealthy []string `yaml:"on_healthy,omitempty"`
+
+	// OnUnhealthy runs when the server's health check starts failing
+	OnUnhealthy []string `yaml:"on_unhealthy,omitempty"`
+}
+
+// Dependencies is the list of servers in depends_on
+type Dependencies []string
+
+// UnmarshalYAML reads a list of server names. Older configs may have the
+// map of services to the services they depend on that depends_on used to
+// be; grove never acted on it, so it's accepted and names no servers.
+func (d *Dependencies) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.MappingNode {
+		var services map[string][]string
+		return value.Decode(&services)
+	}
+	var names []string
+	if err := value.Decode(&names); err != nil {
+		return err
+	}
+	*d = names
+	return nil
 }
 
 // ServiceConfig defines a single service in a multi-service project
@@ -84,40 +302,84 @@ type ServiceConfig struct {
 // ConfigFileName is the name of the project config file
 const ConfigFileName = ".grove.yaml"
 
-// Load loads the project configuration from the given directory
+// TeamDir is the directory a team commits shared grove setup to:
+//   - config.yaml: project config that .grove.yaml is overlaid on
+//   - templates/: templates for 'grove init --template'
+//   - hooks/: hooks run like the global hooks, for this project only
+const TeamDir = ".grove"
+
+// TeamConfigPath returns the path of the team config in dir
+func TeamConfigPath(dir string) string {
+	return filepath.Join(dir, TeamDir, "config.yaml")
+}
+
+// TeamTemplatesDir returns the directory of the team templates in dir
+func TeamTemplatesDir(dir string) string {
+	return filepath.Join(dir, TeamDir, "templates")
+}
+
+// TeamHooksDir returns the directory of the team hooks in dir
+func TeamHooksDir(dir string) string {
+	return filepath.Join(dir, TeamDir, "hooks")
+}
+
+// Load loads the project configuration from the given directory: the team
+// config in .grove/config.yaml, if any, overlaid with .grove.yaml
 func Load(dir string) (*Config, error) {
 	path := filepath.Join(dir, ConfigFileName)
-	return LoadFile(path)
+	teamPath := TeamConfigPath(dir)
+	if _, err := os.Stat(teamPath); err != nil {
+		return LoadFile(path)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return LoadFiles(teamPath)
+	}
+	return LoadFiles(teamPath, path)
 }
 
 // LoadFile loads the project configuration from a specific file
 func LoadFile(path string) (*Config, error) {
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
-	}
+	return LoadFiles(path)
+}
 
+// LoadFiles loads the project configuration from files overlaid in order:
+// settings in later files replace those in earlier ones, except maps (env,
+// processes, subdomains, ...), which are merged key by key
+func LoadFiles(paths ...string) (*Config, error) {
 	cfg := &Config{}
-	if err := yaml.Unmarshal(data, cfg); err != nil {
-		return nil, err
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("invalid config %s: %w", path, err)
+		}
 	}
 
-	// Set defaults
-	if cfg.HealthCheck.Timeout == 0 {
-		cfg.HealthCheck.Timeout = 30 * time.Second
+	cfg.SetDefaults()
+	return cfg, nil
+}
+
+// SetDefaults fills in the settings a loaded config gets by default
+func (c *Config) SetDefaults() {
+	if c.HealthCheck.Timeout == 0 {
+		c.HealthCheck.Timeout = 30 * time.Second
 	}
-	if cfg.HealthCheck.Interval == 0 {
-		cfg.HealthCheck.Interval = 2 * time.Second
+	if c.HealthCheck.Interval == 0 {
+		c.HealthCheck.Interval = 2 * time.Second
 	}
-
-	return cfg, nil
 }
 
-// Exists checks if a .grove.yaml file exists in the given directory
+// Exists checks if a .grove.yaml file or a team config exists in the given
+// directory
 func Exists(dir string) bool {
-	path := filepath.Join(dir, ConfigFileName)
-	_, err := os.Stat(path)
-	return err == nil
+	for _, path := range []string{filepath.Join(dir, ConfigFileName), TeamConfigPath(dir)} {
+		if _, err := os.Stat(path); err == nil {
+			return true
+		}
+	}
+	return false
 }
 
 // Save saves the configuration to the given directory
@@ -141,6 +403,42 @@ func (c *Config) IsSingleService() bool {
 	return len(c.Services) == 0
 }
 
+// IsCompose returns true if the project runs with the Docker Compose backend
+func (c *Config) IsCompose() bool {
+	return c.Backend == "compose"
+}
+
+// HasProcesses returns true if the project defines multiple named processes
+func (c *Config) HasProcesses() bool {
+	return len(c.Processes) > 0
+}
+
+// ProcessNames returns the process names in a stable (sorted) order
+func (c *Config) ProcessNames() []string {
+	names := make([]string, 0, len(c.Processes))
+	for name := range c.Processes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetWebProcess returns the name of the process that should receive the PORT
+func (c *Config) GetWebProcess() string {
+	if c.WebProcess != "" {
+		if _, ok := c.Processes[c.WebProcess]; ok {
+			return c.WebProcess
+		}
+	}
+	if _, ok := c.Processes["web"]; ok {
+		return "web"
+	}
+	if names := c.ProcessNames(); len(names) > 0 {
+		return names[0]
+	}
+	return ""
+}
+
 // GetEffectiveName returns the name to use (either explicit or auto-detected)
 func (c *Config) GetEffectiveName(autoDetected string) string {
 	if c.Name != "" {
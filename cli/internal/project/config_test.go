@@ -0,0 +1,104 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLoad_TeamConfig(t *testing.T) {
+	dir := t.TempDir()
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := Load(dir); !os.IsNotExist(err) {
+		t.Fatalf("Load() without config error = %v, want not exist", err)
+	}
+
+	write(TeamConfigPath(dir), `command: bin/dev
+env:
+  APP_ENV: development
+  LOG_LEVEL: info
+health_check:
+  path: /up
+  timeout: 60s
+subdomains:
+  api: "3101"
+hooks:
+  before_start:
+    - bundle install
+`)
+	if !Exists(dir) {
+		t.Error("Exists() = false with only a team config")
+	}
+	cfg, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Command != "bin/dev" || cfg.HealthCheck.Path != "/up" || cfg.HealthCheck.Interval != 2*time.Second {
+		t.Errorf("Load() team config = %+v", cfg)
+	}
+
+	write(filepath.Join(dir, ConfigFileName), `name: mine
+env:
+  LOG_LEVEL: debug
+health_check:
+  interval: 5s
+hooks:
+  before_start:
+    - make deps
+`)
+	cfg, err = Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Name != "mine" || cfg.Command != "bin/dev" {
+		t.Errorf("Load() name, command = %q, %q", cfg.Name, cfg.Command)
+	}
+	if want := map[string]string{"APP_ENV": "development", "LOG_LEVEL": "debug"}; !reflect.DeepEqual(cfg.Env, want) {
+		t.Errorf("Load() env = %v, want %v", cfg.Env, want)
+	}
+	if cfg.HealthCheck.Path != "/up" || cfg.HealthCheck.Timeout != 60*time.Second || cfg.HealthCheck.Interval != 5*time.Second {
+		t.Errorf("Load() health check = %+v", cfg.HealthCheck)
+	}
+	if cfg.Subdomains["api"] != "3101" {
+		t.Errorf("Load() subdomains = %v", cfg.Subdomains)
+	}
+	if want := []string{"make deps"}; !reflect.DeepEqual(cfg.Hooks.BeforeStart, want) {
+		t.Errorf("Load() before_start = %v, want %v", cfg.Hooks.BeforeStart, want)
+	}
+}
+
+func TestLoad_DependsOn(t *testing.T) {
+	tests := []struct {
+		content string
+		want    Dependencies
+	}{
+		{"depends_on:\n  - api\n  - auth\n", Dependencies{"api", "auth"}},
+		// The map of service dependencies older configs have still loads
+		{"depends_on:\n  web:\n    - db\n", nil},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), ConfigFileName)
+		if err := os.WriteFile(path, []byte("command: bin/dev\n"+tt.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := LoadFile(path)
+		if err != nil {
+			t.Errorf("LoadFile(%q) error = %v", tt.content, err)
+			continue
+		}
+		if !reflect.DeepEqual(cfg.DependsOn, tt.want) {
+			t.Errorf("LoadFile(%q) DependsOn = %v, want %v", tt.content, cfg.DependsOn, tt.want)
+		}
+	}
+}
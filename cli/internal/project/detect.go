@@ -0,0 +1,344 @@
+package project
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DetectedStack is a .grove.yaml suggestion inferred from a project's files
+type DetectedStack struct {
+	// Stack names the framework or tool (e.g., rails, nextjs, procfile)
+	Stack string
+
+	// Source is the file the suggestion was derived from
+	Source string
+
+	Config *Config
+}
+
+// DetectStacks inspects dir for package.json scripts, Gemfile, manage.py,
+// go.mod, Procfiles, bin/dev and Makefile targets and returns the configs
+// they suggest, most specific first. Env placeholders from .env.example are
+// added to each.
+func DetectStacks(dir string) []*DetectedStack {
+	var stacks []*DetectedStack
+	commands := make(map[string]bool)
+	for _, detect := range []func(string) []*DetectedStack{
+		detectProcfile,
+		detectRails,
+		detectDjango,
+		detectNode,
+		detectGo,
+		detectBinDev,
+		detectMake,
+	} {
+		for _, s := range detect(dir) {
+			// A stack-specific suggestion beats a generic one for the same
+			// command (e.g. Rails' bin/dev)
+			if commands[s.Config.Command] {
+				continue
+			}
+			if s.Config.Command != "" {
+				commands[s.Config.Command] = true
+			}
+			stacks = append(stacks, s)
+		}
+	}
+
+	placeholders := envPlaceholders(dir)
+	for _, s := range stacks {
+		for k, v := range placeholders {
+			if _, ok := s.Config.Env[k]; !ok {
+				if s.Config.Env == nil {
+					s.Config.Env = make(map[string]string)
+				}
+				s.Config.Env[k] = v
+			}
+		}
+	}
+
+	return stacks
+}
+
+func detectProcfile(dir string) []*DetectedStack {
+	for _, name := range []string{"Procfile.dev", "Procfile"} {
+		processes := parseProcfile(filepath.Join(dir, name))
+		if len(processes) == 0 {
+			continue
+		}
+
+		cfg := &Config{}
+		if len(processes) == 1 {
+			for _, command := range processes {
+				cfg.Command = command
+			}
+		} else {
+			cfg.Processes = processes
+		}
+		return []*DetectedStack{{Stack: "procfile", Source: name, Config: cfg}}
+	}
+	return nil
+}
+
+// parseProcfile reads "name: command" lines from a Procfile
+func parseProcfile(path string) map[string]string {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	processes := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, command, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(command) == "" {
+			continue
+		}
+		processes[strings.TrimSpace(name)] = strings.TrimSpace(command)
+	}
+	return processes
+}
+
+func detectRails(dir string) []*DetectedStack {
+	gemfile, err := os.ReadFile(filepath.Join(dir, "Gemfile"))
+	if err != nil || !strings.Contains(string(gemfile), `"rails"`) && !strings.Contains(string(gemfile), `'rails'`) {
+		return nil
+	}
+
+	cfg := &Config{
+		Command: "bin/rails server -p $PORT",
+		Env: map[string]string{
+			"RAILS_ENV": "development",
+		},
+		Hooks: HooksConfig{
+			BeforeStart: []string{
+				"bundle install",
+				"bin/rails db:prepare",
+			},
+		},
+	}
+	if fileExists(filepath.Join(dir, "bin", "dev")) {
+		cfg.Command = "bin/dev"
+	}
+	// Rails 7.1+ apps mount a health check at /up
+	if routes, err := os.ReadFile(filepath.Join(dir, "config", "routes.rb")); err == nil && strings.Contains(string(routes), "rails/health") {
+		cfg.HealthCheck.Path = "/up"
+	}
+
+	return []*DetectedStack{{Stack: "rails", Source: "Gemfile", Config: cfg}}
+}
+
+func detectDjango(dir string) []*DetectedStack {
+	if !fileExists(filepath.Join(dir, "manage.py")) {
+		return nil
+	}
+
+	cfg := &Config{
+		Command: "python manage.py runserver 0.0.0.0:$PORT",
+		Hooks: HooksConfig{
+			BeforeStart: []string{"python manage.py migrate"},
+		},
+	}
+	switch {
+	case fileExists(filepath.Join(dir, "uv.lock")):
+		cfg.Command = "uv run " + cfg.Command
+		cfg.Hooks.BeforeStart = []string{"uv sync", "uv run python manage.py migrate"}
+	case fileExists(filepath.Join(dir, "poetry.lock")):
+		cfg.Command = "poetry run " + cfg.Command
+		cfg.Hooks.BeforeStart = []string{"poetry install", "poetry run python manage.py migrate"}
+	case fileExists(filepath.Join(dir, "requirements.txt")):
+		cfg.Hooks.BeforeStart = append([]string{"pip install -r requirements.txt"}, cfg.Hooks.BeforeStart...)
+	}
+
+	return []*DetectedStack{{Stack: "django", Source: "manage.py", Config: cfg}}
+}
+
+// nodeDevScripts are package.json scripts that usually run a dev server,
+// in order of preference
+var nodeDevScripts = []string{"dev", "start", "serve"}
+
+func detectNode(dir string) []*DetectedStack {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return nil
+	}
+
+	var pkg struct {
+		Scripts         map[string]string `json:"scripts"`
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil
+	}
+
+	hasDep := func(name string) bool {
+		_, ok := pkg.Dependencies[name]
+		_, dev := pkg.DevDependencies[name]
+		return ok || dev
+	}
+
+	stack, health := "node", ""
+	switch {
+	case hasDep("next"):
+		stack, health = "nextjs", "/"
+	case hasDep("vite"):
+		stack = "vite"
+	}
+
+	manager := nodePackageManager(dir)
+	var stacks []*DetectedStack
+	for _, script := range nodeDevScripts {
+		body, ok := pkg.Scripts[script]
+		if !ok {
+			continue
+		}
+
+		command := manager + " run " + script
+		// Vite ignores PORT, so pass it on the command line
+		if strings.Contains(body, "vite") && !strings.Contains(body, "--port") {
+			if manager == "npm" {
+				command += " --"
+			}
+			command += " --port $PORT"
+		}
+
+		stacks = append(stacks, &DetectedStack{
+			Stack:  stack,
+			Source: "package.json scripts." + script,
+			Config: &Config{
+				Command: command,
+				Env: map[string]string{
+					"NODE_ENV": "development",
+				},
+				HealthCheck: HealthCheckConfig{
+					Path: health,
+				},
+				Hooks: HooksConfig{
+					BeforeStart: []string{manager + " install"},
+				},
+			},
+		})
+	}
+	return stacks
+}
+
+// nodePackageManager picks the package manager from the lockfile present
+func nodePackageManager(dir string) string {
+	for _, lock := range []struct{ file, manager string }{
+		{"pnpm-lock.yaml", "pnpm"},
+		{"yarn.lock", "yarn"},
+		{"bun.lockb", "bun"},
+		{"bun.lock", "bun"},
+	} {
+		if fileExists(filepath.Join(dir, lock.file)) {
+			return lock.manager
+		}
+	}
+	return "npm"
+}
+
+func detectGo(dir string) []*DetectedStack {
+	if !fileExists(filepath.Join(dir, "go.mod")) {
+		return nil
+	}
+
+	newStack := func(pkg string) *DetectedStack {
+		return &DetectedStack{
+			Stack:  "go",
+			Source: "go.mod",
+			Config: &Config{
+				Command: "go run " + pkg,
+				Hooks: HooksConfig{
+					BeforeStart: []string{"go mod download"},
+				},
+			},
+		}
+	}
+
+	var stacks []*DetectedStack
+	if fileExists(filepath.Join(dir, "main.go")) {
+		stacks = append(stacks, newStack("."))
+	}
+
+	// Each cmd/<name> directory is a candidate binary
+	mains, _ := filepath.Glob(filepath.Join(dir, "cmd", "*", "main.go"))
+	sort.Strings(mains)
+	for _, main := range mains {
+		stacks = append(stacks, newStack("./cmd/"+filepath.Base(filepath.Dir(main))))
+	}
+
+	return stacks
+}
+
+func detectBinDev(dir string) []*DetectedStack {
+	if !fileExists(filepath.Join(dir, "bin", "dev")) {
+		return nil
+	}
+	return []*DetectedStack{{Stack: "script", Source: "bin/dev", Config: &Config{Command: "bin/dev"}}}
+}
+
+// makeDevTargets are Makefile targets that usually run a dev server, in
+// order of preference
+var makeDevTargets = []string{"dev", "run", "serve", "start", "server"}
+
+func detectMake(dir string) []*DetectedStack {
+	f, err := os.Open(filepath.Join(dir, "Makefile"))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	targets := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		// Rules start at the beginning of the line: "dev: deps"
+		name, _, ok := strings.Cut(line, ":")
+		if !ok || name == "" || strings.ContainsAny(name, " \t=$") || strings.HasPrefix(line[len(name)+1:], "=") {
+			continue
+		}
+		targets[name] = true
+	}
+
+	var stacks []*DetectedStack
+	for _, target := range makeDevTargets {
+		if targets[target] {
+			stacks = append(stacks, &DetectedStack{
+				Stack:  "make",
+				Source: "Makefile",
+				Config: &Config{Command: "make " + target},
+			})
+		}
+	}
+	return stacks
+}
+
+// envPlaceholders returns the variables listed in an example env file, so
+// the generated config shows what needs to be set
+func envPlaceholders(dir string) map[string]string {
+	for _, name := range []string{".env.example", ".env.sample", ".env.template"} {
+		vars, err := ReadEnvFile(filepath.Join(dir, name))
+		if err != nil {
+			continue
+		}
+		// PORT is allocated by grove
+		delete(vars, "PORT")
+		return vars
+	}
+	return nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
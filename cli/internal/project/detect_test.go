@@ -0,0 +1,172 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDetectStacks(t *testing.T) {
+	tests := []struct {
+		name       string
+		files      map[string]string
+		wantStacks []string
+		wantFirst  string
+		wantHealth string
+	}{
+		{
+			name:       "nothing",
+			files:      map[string]string{"README.md": "hi"},
+			wantStacks: nil,
+		},
+		{
+			name: "rails with bin/dev and health route",
+			files: map[string]string{
+				"Gemfile":          "source 'https://rubygems.org'\ngem \"rails\", \"~> 7.1\"\n",
+				"bin/dev":          "#!/bin/sh\n",
+				"config/routes.rb": `get "up" => "rails/health#show"`,
+			},
+			wantStacks: []string{"rails"},
+			wantFirst:  "bin/dev",
+			wantHealth: "/up",
+		},
+		{
+			name: "django with requirements",
+			files: map[string]string{
+				"manage.py":        "",
+				"requirements.txt": "django\n",
+			},
+			wantStacks: []string{"django"},
+			wantFirst:  "python manage.py runserver 0.0.0.0:$PORT",
+		},
+		{
+			name: "next.js with pnpm and several scripts",
+			files: map[string]string{
+				"package.json":   `{"scripts": {"dev": "next dev", "start": "next start", "lint": "next lint"}, "dependencies": {"next": "14"}}`,
+				"pnpm-lock.yaml": "",
+			},
+			wantStacks: []string{"nextjs", "nextjs"},
+			wantFirst:  "pnpm run dev",
+			wantHealth: "/",
+		},
+		{
+			name: "vite gets an explicit port",
+			files: map[string]string{
+				"package.json": `{"scripts": {"dev": "vite"}, "devDependencies": {"vite": "5"}}`,
+			},
+			wantStacks: []string{"vite"},
+			wantFirst:  "npm run dev -- --port $PORT",
+		},
+		{
+			name: "go with cmd directories",
+			files: map[string]string{
+				"go.mod":             "module example.com/app\n",
+				"cmd/api/main.go":    "package main",
+				"cmd/worker/main.go": "package main",
+			},
+			wantStacks: []string{"go", "go"},
+			wantFirst:  "go run ./cmd/api",
+		},
+		{
+			name: "procfile comes first",
+			files: map[string]string{
+				"Procfile.dev": "web: bin/rails s -p $PORT\n# comment\njs: yarn build --watch\n",
+				"go.mod":       "module example.com/app\n",
+				"main.go":      "package main",
+			},
+			wantStacks: []string{"procfile", "go"},
+		},
+		{
+			name: "bin/dev and make targets",
+			files: map[string]string{
+				"bin/dev":  "#!/bin/sh\n",
+				"Makefile": "GO := go\n.PHONY: dev serve\n\nserve: build\n\t$(GO) run . --addr=:8080\n\ndev:\n\tair\n\nbuild:\n\t$(GO) build\n",
+			},
+			wantStacks: []string{"script", "make", "make"},
+			wantFirst:  "bin/dev",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, tt.files)
+
+			stacks := DetectStacks(dir)
+			var got []string
+			for _, s := range stacks {
+				got = append(got, s.Stack)
+			}
+			if len(got) != len(tt.wantStacks) {
+				t.Fatalf("stacks = %v, want %v", got, tt.wantStacks)
+			}
+			for i := range got {
+				if got[i] != tt.wantStacks[i] {
+					t.Fatalf("stacks = %v, want %v", got, tt.wantStacks)
+				}
+			}
+			if len(stacks) == 0 {
+				return
+			}
+			if tt.wantFirst != "" && stacks[0].Config.Command != tt.wantFirst {
+				t.Errorf("command = %q, want %q", stacks[0].Config.Command, tt.wantFirst)
+			}
+			if stacks[0].Config.HealthCheck.Path != tt.wantHealth {
+				t.Errorf("health path = %q, want %q", stacks[0].Config.HealthCheck.Path, tt.wantHealth)
+			}
+		})
+	}
+}
+
+func TestDetectStacks_Procfile(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"Procfile": "web: bin/server\nworker: bin/worker\n",
+	})
+
+	stacks := DetectStacks(dir)
+	if len(stacks) != 1 {
+		t.Fatalf("expected one stack, got %d", len(stacks))
+	}
+	processes := stacks[0].Config.Processes
+	if processes["web"] != "bin/server" || processes["worker"] != "bin/worker" {
+		t.Errorf("processes = %v", processes)
+	}
+}
+
+func TestDetectStacks_EnvPlaceholders(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":       "module example.com/app\n",
+		"main.go":      "package main",
+		".env.example": "# Database\nDATABASE_URL=\"postgres://localhost/app\"\nexport API_KEY=\nPORT=3000\n",
+	})
+
+	stacks := DetectStacks(dir)
+	if len(stacks) != 1 {
+		t.Fatalf("expected one stack, got %d", len(stacks))
+	}
+	env := stacks[0].Config.Env
+	if env["DATABASE_URL"] != "postgres://localhost/app" {
+		t.Errorf("DATABASE_URL = %q", env["DATABASE_URL"])
+	}
+	if v, ok := env["API_KEY"]; !ok || v != "" {
+		t.Errorf("expected empty API_KEY placeholder, got %q (present=%v)", v, ok)
+	}
+	if _, ok := env["PORT"]; ok {
+		t.Error("PORT should not be copied from .env.example")
+	}
+}
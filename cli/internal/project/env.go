@@ -0,0 +1,65 @@
+package project
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ReadEnvFile reads KEY=VALUE lines from a dotenv file. Comments, blank
+// lines and an "export " prefix are skipped, and quotes around values are
+// removed.
+func ReadEnvFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	vars := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		if key = strings.TrimSpace(key); key != "" {
+			vars[key] = strings.Trim(strings.TrimSpace(value), `"'`)
+		}
+	}
+	return vars, scanner.Err()
+}
+
+// EnvFileVars reads the config's env_files in dir, in order, so later files
+// override earlier ones. Missing files are skipped, since overrides like
+// .env.local usually exist in only some worktrees. origin maps each
+// variable to the file that set it, as written in the config.
+func (c *Config) EnvFileVars(dir string) (vars, origin map[string]string, err error) {
+	vars = make(map[string]string)
+	origin = make(map[string]string)
+	for _, file := range c.EnvFiles {
+		path := file
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		fileVars, err := ReadEnvFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return vars, origin, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		for k, v := range fileVars {
+			vars[k] = v
+			origin[k] = file
+		}
+	}
+	return vars, origin, nil
+}
@@ -0,0 +1,51 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadEnvFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	content := "# comment\n\nexport API_KEY=abc\nDATABASE_URL=\"postgres://localhost/app\"\nNAME='grove'\nnot a var\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	vars, err := ReadEnvFile(path)
+	if err != nil {
+		t.Fatalf("ReadEnvFile() error = %v", err)
+	}
+	want := map[string]string{"API_KEY": "abc", "DATABASE_URL": "postgres://localhost/app", "NAME": "grove"}
+	if len(vars) != len(want) {
+		t.Errorf("ReadEnvFile() = %v, want %v", vars, want)
+	}
+	for k, v := range want {
+		if vars[k] != v {
+			t.Errorf("%s = %q, want %q", k, vars[k], v)
+		}
+	}
+}
+
+func TestEnvFileVars(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("API_URL=http://api\nDEBUG=false\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".env.local"), []byte("DEBUG=true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &Config{EnvFiles: []string{".env", ".env.missing", ".env.local"}}
+	vars, origin, err := cfg.EnvFileVars(dir)
+	if err != nil {
+		t.Fatalf("EnvFileVars() error = %v", err)
+	}
+	if vars["API_URL"] != "http://api" || origin["API_URL"] != ".env" {
+		t.Errorf("API_URL = %q from %q, want http://api from .env", vars["API_URL"], origin["API_URL"])
+	}
+	if vars["DEBUG"] != "true" || origin["DEBUG"] != ".env.local" {
+		t.Errorf("DEBUG = %q from %q, want true from .env.local", vars["DEBUG"], origin["DEBUG"])
+	}
+}
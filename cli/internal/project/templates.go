@@ -0,0 +1,291 @@
+package project
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Template is a reusable project profile that 'grove init' turns into a
+// .grove.yaml. Built-in templates cover common stacks; user and team
+// templates live as <name>.yaml files in a templates directory and take
+// precedence.
+type Template struct {
+	Name        string
+	Description string
+
+	// Builtin is true for templates shipped with grove
+	Builtin bool
+
+	// Path is the file a user or team template was loaded from
+	Path string
+
+	Config *Config
+}
+
+var templateNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ValidateTemplateName checks that a template name is safe to use as a
+// file name in a templates directory
+func ValidateTemplateName(name string) error {
+	if !templateNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid template name '%s' (use lowercase letters, digits, '-' and '_')", name)
+	}
+	return nil
+}
+
+// templateFile is the on-disk form of a user template: a .grove.yaml with
+// an optional description
+type templateFile struct {
+	Description string `yaml:"description,omitempty"`
+	Config      `yaml:",inline"`
+}
+
+// builtinTemplates returns the templates shipped with grove
+func builtinTemplates() []*Template {
+	templates := []*Template{
+		{
+			Name:        "rails",
+			Description: "Ruby on Rails project",
+			Config: &Config{
+				Command: "bin/dev",
+				Env: map[string]string{
+					"RAILS_ENV": "development",
+				},
+				HealthCheck: HealthCheckConfig{
+					Path: "/up",
+				},
+				Hooks: HooksConfig{
+					BeforeStart: []string{
+						"bundle install",
+						"rails db:migrate",
+					},
+				},
+			},
+		},
+		{
+			Name:        "nextjs",
+			Description: "Next.js project",
+			Config: &Config{
+				Command: "npm run dev",
+				Env: map[string]string{
+					"NODE_ENV": "development",
+				},
+				HealthCheck: HealthCheckConfig{
+					Path: "/",
+				},
+				Hooks: HooksConfig{
+					BeforeStart: []string{
+						"npm install",
+					},
+				},
+			},
+		},
+		{
+			Name:        "django",
+			Description: "Django project",
+			Config: &Config{
+				Command: "python manage.py runserver 0.0.0.0:$PORT",
+				Env: map[string]string{
+					"DJANGO_SETTINGS_MODULE": "config.settings.development",
+				},
+				Hooks: HooksConfig{
+					BeforeStart: []string{
+						"pip install -r requirements.txt",
+						"python manage.py migrate",
+					},
+				},
+			},
+		},
+		{
+			Name:        "node",
+			Description: "Node.js project",
+			Config: &Config{
+				Command: "npm run dev",
+				Env: map[string]string{
+					"NODE_ENV": "development",
+				},
+				Hooks: HooksConfig{
+					BeforeStart: []string{
+						"npm install",
+					},
+				},
+			},
+		},
+		{
+			Name:        "go",
+			Description: "Go project",
+			Config: &Config{
+				Command: "go run .",
+				Env: map[string]string{
+					"GO_ENV": "development",
+				},
+				Hooks: HooksConfig{
+					BeforeStart: []string{
+						"go mod download",
+					},
+				},
+			},
+		},
+	}
+	for _, t := range templates {
+		t.Builtin = true
+	}
+	return templates
+}
+
+// ListTemplates returns the built-in templates and the templates in dirs,
+// sorted by name. A template in a directory replaces a built-in or a
+// template in a later directory of the same name.
+func ListTemplates(dirs ...string) ([]*Template, error) {
+	byName := make(map[string]*Template)
+	for _, t := range builtinTemplates() {
+		byName[t.Name] = t
+	}
+
+	for i := len(dirs) - 1; i >= 0; i-- {
+		paths, err := filepath.Glob(filepath.Join(dirs[i], "*.yaml"))
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			t, err := loadTemplateFile(path)
+			if err != nil {
+				return nil, err
+			}
+			byName[t.Name] = t
+		}
+	}
+
+	templates := make([]*Template, 0, len(byName))
+	for _, t := range byName {
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+	return templates, nil
+}
+
+// FindTemplate returns the named template, checking dir before the built-ins
+func FindTemplate(dir, name string) (*Template, error) {
+	return FindTemplateIn([]string{dir}, name)
+}
+
+// FindTemplateIn returns the named template, checking dirs in order before
+// the built-ins
+func FindTemplateIn(dirs []string, name string) (*Template, error) {
+	if err := ValidateTemplateName(name); err != nil {
+		return nil, err
+	}
+	for _, dir := range dirs {
+		path := templatePath(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return loadTemplateFile(path)
+		}
+	}
+
+	for _, t := range builtinTemplates() {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+	return nil, fmt.Errorf("template '%s' not found", name)
+}
+
+// SaveTemplate copies the .grove.yaml at from into dir as a user template.
+// The config's name and port are dropped since they belong to a single
+// project.
+func SaveTemplate(dir, name, description, from string) (*Template, error) {
+	if err := ValidateTemplateName(name); err != nil {
+		return nil, err
+	}
+
+	// Read the file directly rather than with LoadFile so its defaults
+	// aren't baked into the template
+	data, err := os.ReadFile(from)
+	if err != nil {
+		return nil, err
+	}
+	var tf templateFile
+	if err := yaml.Unmarshal(data, &tf.Config); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", from, err)
+	}
+	tf.Description = description
+	tf.Name = ""
+	tf.Port = 0
+
+	data, err = yaml.Marshal(&tf)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	path := templatePath(dir, name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return nil, err
+	}
+	return &Template{Name: name, Description: description, Path: path, Config: &tf.Config}, nil
+}
+
+// RemoveTemplate deletes the named user template from dir
+func RemoveTemplate(dir, name string) error {
+	if err := ValidateTemplateName(name); err != nil {
+		return err
+	}
+	err := os.Remove(templatePath(dir, name))
+	if os.IsNotExist(err) {
+		return fmt.Errorf("user template '%s' not found", name)
+	}
+	return err
+}
+
+// NewConfig returns a copy of the template's config for a project
+func (t *Template) NewConfig(name string) *Config {
+	cfg := *t.Config
+	cfg.Name = name
+	cfg.Env = make(map[string]string, len(t.Config.Env))
+	for k, v := range t.Config.Env {
+		cfg.Env[k] = v
+	}
+	return &cfg
+}
+
+// YAML returns the template's config as it would be written to .grove.yaml
+func (t *Template) YAML() (string, error) {
+	data, err := yaml.Marshal(t.Config)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func templatePath(dir, name string) string {
+	return filepath.Join(dir, name+".yaml")
+}
+
+func loadTemplateFile(path string) (*Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var tf templateFile
+	if err := yaml.Unmarshal(data, &tf); err != nil {
+		return nil, fmt.Errorf("invalid template %s: %w", path, err)
+	}
+
+	return &Template{
+		Name:        strings.TrimSuffix(filepath.Base(path), ".yaml"),
+		Description: tf.Description,
+		Path:        path,
+		Config:      &tf.Config,
+	}, nil
+}
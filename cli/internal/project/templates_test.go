@@ -0,0 +1,150 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindTemplate_Builtin(t *testing.T) {
+	tmpl, err := FindTemplate(t.TempDir(), "rails")
+	if err != nil {
+		t.Fatalf("FindTemplate() error = %v", err)
+	}
+	if !tmpl.Builtin {
+		t.Error("expected rails to be a built-in template")
+	}
+	if tmpl.Config.Command != "bin/dev" {
+		t.Errorf("Command = %q, want %q", tmpl.Config.Command, "bin/dev")
+	}
+
+	if _, err := FindTemplate(t.TempDir(), "missing"); err == nil {
+		t.Error("expected error for unknown template")
+	}
+}
+
+func TestSaveTemplate(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(t.TempDir(), ConfigFileName)
+	content := "name: myapp\nport: 3000\ncommand: bin/server\nenv:\n  APP_ENV: dev\n"
+	if err := os.WriteFile(src, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := SaveTemplate(dir, "custom", "My stack", src); err != nil {
+		t.Fatalf("SaveTemplate() error = %v", err)
+	}
+
+	tmpl, err := FindTemplate(dir, "custom")
+	if err != nil {
+		t.Fatalf("FindTemplate() error = %v", err)
+	}
+	if tmpl.Builtin || tmpl.Description != "My stack" {
+		t.Errorf("got Builtin=%v Description=%q", tmpl.Builtin, tmpl.Description)
+	}
+	if tmpl.Config.Name != "" || tmpl.Config.Port != 0 {
+		t.Errorf("expected name and port to be dropped, got %q %d", tmpl.Config.Name, tmpl.Config.Port)
+	}
+	if tmpl.Config.HealthCheck.Timeout != 0 {
+		t.Errorf("expected no health check defaults, got %v", tmpl.Config.HealthCheck.Timeout)
+	}
+
+	cfg := tmpl.NewConfig("feature")
+	if cfg.Name != "feature" || cfg.Command != "bin/server" || cfg.Env["APP_ENV"] != "dev" {
+		t.Errorf("NewConfig() = %+v", cfg)
+	}
+
+	if _, err := SaveTemplate(dir, "../escape", "", src); err == nil {
+		t.Error("expected error for invalid template name")
+	}
+}
+
+func TestTemplateNames(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "templates")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// A file next to the templates directory, like the config
+	outside := filepath.Join(filepath.Dir(dir), "config.yaml")
+	if err := os.WriteFile(outside, []byte("command: bin/dev\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"../config", "", ".hidden", "a/b", "Rails"} {
+		if err := RemoveTemplate(dir, name); err == nil {
+			t.Errorf("RemoveTemplate(%q) = nil, want invalid name", name)
+		}
+		if _, err := FindTemplate(dir, name); err == nil {
+			t.Errorf("FindTemplate(%q) = nil error, want invalid name", name)
+		}
+		if _, err := SaveTemplate(dir, name, "", outside); err == nil {
+			t.Errorf("SaveTemplate(%q) = nil error, want invalid name", name)
+		}
+	}
+	if _, err := os.Stat(outside); err != nil {
+		t.Errorf("file outside the templates directory: %v", err)
+	}
+
+	for _, name := range []string{"rails", "my-app_2"} {
+		if err := ValidateTemplateName(name); err != nil {
+			t.Errorf("ValidateTemplateName(%q) = %v", name, err)
+		}
+	}
+}
+
+func TestListTemplates_UserOverridesBuiltin(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "rails.yaml"), []byte("description: Our rails\ncommand: bin/rails s\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	templates, err := ListTemplates(dir)
+	if err != nil {
+		t.Fatalf("ListTemplates() error = %v", err)
+	}
+
+	seen := 0
+	for _, tmpl := range templates {
+		if tmpl.Name != "rails" {
+			continue
+		}
+		seen++
+		if tmpl.Builtin || tmpl.Config.Command != "bin/rails s" {
+			t.Errorf("expected user rails template, got %+v", tmpl)
+		}
+	}
+	if seen != 1 {
+		t.Errorf("expected exactly one rails template, got %d", seen)
+	}
+}
+
+func TestListTemplates_DirPrecedence(t *testing.T) {
+	userDir := t.TempDir()
+	teamDir := t.TempDir()
+	for path, content := range map[string]string{
+		filepath.Join(userDir, "api.yaml"):  "command: bin/mine\n",
+		filepath.Join(teamDir, "api.yaml"):  "command: bin/team\n",
+		filepath.Join(teamDir, "jobs.yaml"): "command: bin/jobs\n",
+	} {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	templates, err := ListTemplates(userDir, teamDir)
+	if err != nil {
+		t.Fatalf("ListTemplates() error = %v", err)
+	}
+	commands := make(map[string]string)
+	for _, tmpl := range templates {
+		commands[tmpl.Name] = tmpl.Config.Command
+	}
+	if commands["api"] != "bin/mine" || commands["jobs"] != "bin/jobs" {
+		t.Errorf("ListTemplates() commands = %v", commands)
+	}
+
+	tmpl, err := FindTemplateIn([]string{userDir, teamDir}, "jobs")
+	if err != nil || tmpl.Config.Command != "bin/jobs" {
+		t.Errorf("FindTemplateIn(jobs) = %+v, %v", tmpl, err)
+	}
+}
@@ -0,0 +1,270 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// cacheDebounce is how long a Cache waits after its files change before
+// reloading, so a save's writes are seen together
+const cacheDebounce = 50 * time.Millisecond
+
+// Change is what changed in the registry between two loads
+type Change struct {
+	Added   []string `json:"added,omitempty"`   // workspaces registered
+	Removed []string `json:"removed,omitempty"` // workspaces unregistered
+	Updated []string `json:"updated,omitempty"` // workspaces whose state changed
+	Proxy   bool     `json:"proxy,omitempty"`   // the proxy's state changed
+}
+
+// Empty returns true if nothing changed
+func (c Change) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Updated) == 0 && !c.Proxy
+}
+
+// Cache is a registry handle for long-lived processes (the TUI, the
+// dashboard, the API). It loads the registry once, then again only when its
+// files change, and tells subscribers what changed. The registry it returns
+// is shared: changes made to it should be saved, which reloads it.
+type Cache struct {
+	load func(ctx context.Context) (*Registry, error)
+
+	mu       sync.RWMutex
+	reg      *Registry
+	snapshot registrySnapshot
+	watching bool
+	subs     map[chan Change]struct{}
+
+	watcher *fsnotify.Watcher
+	done    chan struct{}
+}
+
+// registrySnapshot is a registry's workspaces and proxy as JSON, to tell
+// what changed between loads
+type registrySnapshot struct {
+	workspaces map[string]string
+	proxy      string
+}
+
+// NewCache loads the registry and watches it for changes. When its files
+// can't be watched, Get loads the registry on every call instead.
+func NewCache(ctx context.Context) (*Cache, error) {
+	c := &Cache{
+		load: LoadContext,
+		subs: make(map[chan Change]struct{}),
+		done: make(chan struct{}),
+	}
+	if _, err := c.reload(ctx); err != nil {
+		return nil, err
+	}
+
+	// Watch the directory rather than the files, which may not exist yet
+	// and may be replaced rather than written
+	files := Files()
+	dir := filepath.Dir(files[0])
+	watcher, err := fsnotify.NewWatcher()
+	if err == nil {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			watcher.Close()
+		} else if err := watcher.Add(dir); err != nil {
+			watcher.Close()
+		} else {
+			c.watcher = watcher
+			c.watching = true
+			go c.watch(files)
+		}
+	}
+	return c, nil
+}
+
+// Get returns the registry as last loaded
+func (c *Cache) Get(ctx context.Context) (*Registry, error) {
+	c.mu.RLock()
+	reg, watching := c.reg, c.watching
+	c.mu.RUnlock()
+	if watching {
+		return reg, nil
+	}
+
+	if _, err := c.reload(ctx); err != nil {
+		return nil, err
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.reg, nil
+}
+
+// Subscribe returns a channel that receives what changed each time the
+// registry does, and a function to stop receiving. Changes a slow
+// subscriber hasn't received yet are merged rather than dropped.
+func (c *Cache) Subscribe() (<-chan Change, func()) {
+	ch := make(chan Change, 1)
+	c.mu.Lock()
+	c.subs[ch] = struct{}{}
+	c.mu.Unlock()
+
+	return ch, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if _, ok := c.subs[ch]; ok {
+			delete(c.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// Close stops watching the registry and ends all subscriptions
+func (c *Cache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return nil
+	default:
+	}
+	close(c.done)
+	for ch := range c.subs {
+		delete(c.subs, ch)
+		close(ch)
+	}
+	c.watching = false
+	if c.watcher != nil {
+		return c.watcher.Close()
+	}
+	return nil
+}
+
+// watch reloads the registry after its files change, until Close
+func (c *Cache) watch(files []string) {
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-c.done:
+			return
+		case event, ok := <-c.watcher.Events:
+			if !ok {
+				return
+			}
+			if slices.Contains(files, event.Name) && !event.Has(fsnotify.Chmod) {
+				debounce = time.After(cacheDebounce)
+			}
+		case _, ok := <-c.watcher.Errors:
+			if !ok {
+				return
+			}
+		case <-debounce:
+			debounce = nil
+			if change, err := c.reload(context.Background()); err == nil && !change.Empty() {
+				c.publish(change)
+			}
+		}
+	}
+}
+
+// reload loads the registry and returns what changed since the last load
+func (c *Cache) reload(ctx context.Context) (Change, error) {
+	reg, err := c.load(ctx)
+	if err != nil {
+		return Change{}, err
+	}
+	snapshot := snapshotOf(reg)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	change := c.snapshot.diff(snapshot)
+	c.reg = reg
+	c.snapshot = snapshot
+	return change, nil
+}
+
+// publish sends a change to every subscriber, merging it with the one a
+// subscriber hasn't received yet
+func (c *Cache) publish(change Change) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for ch := range c.subs {
+		select {
+		case ch <- change:
+		default:
+			select {
+			case pending := <-ch:
+				ch <- pending.merge(change)
+			default:
+				ch <- change
+			}
+		}
+	}
+}
+
+func snapshotOf(r *Registry) registrySnapshot {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	snapshot := registrySnapshot{workspaces: make(map[string]string, len(r.Workspaces))}
+	for name, ws := range r.Workspaces {
+		data, _ := json.Marshal(ws)
+		snapshot.workspaces[name] = string(data)
+	}
+	if r.Proxy != nil {
+		data, _ := json.Marshal(r.Proxy)
+		snapshot.proxy = string(data)
+	}
+	return snapshot
+}
+
+// diff returns what changed from s to next
+func (s registrySnapshot) diff(next registrySnapshot) Change {
+	var change Change
+	for name, data := range next.workspaces {
+		prev, ok := s.workspaces[name]
+		switch {
+		case !ok:
+			change.Added = append(change.Added, name)
+		case prev != data:
+			change.Updated = append(change.Updated, name)
+		}
+	}
+	for name := range s.workspaces {
+		if _, ok := next.workspaces[name]; !ok {
+			change.Removed = append(change.Removed, name)
+		}
+	}
+	change.Proxy = s.proxy != next.proxy
+	slices.Sort(change.Added)
+	slices.Sort(change.Removed)
+	slices.Sort(change.Updated)
+	return change
+}
+
+// merge combines two changes that happened one after the other
+func (c Change) merge(next Change) Change {
+	merged := Change{Proxy: c.Proxy || next.Proxy}
+	add := func(list []string, name string) []string {
+		if slices.Contains(list, name) {
+			return list
+		}
+		return append(list, name)
+	}
+	for _, name := range append(c.Added, next.Added...) {
+		merged.Added = add(merged.Added, name)
+	}
+	for _, name := range append(c.Updated, next.Updated...) {
+		merged.Updated = add(merged.Updated, name)
+	}
+	for _, name := range append(c.Removed, next.Removed...) {
+		merged.Removed = add(merged.Removed, name)
+	}
+	slices.Sort(merged.Added)
+	slices.Sort(merged.Updated)
+	slices.Sort(merged.Removed)
+	return merged
+}
@@ -0,0 +1,76 @@
+package registry
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/adrg/xdg"
+)
+
+func TestCache_NotifiesChanges(t *testing.T) {
+	origHome := xdg.ConfigHome
+	xdg.ConfigHome = t.TempDir()
+	t.Cleanup(func() { xdg.ConfigHome = origHome })
+
+	cache, err := NewCache(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Close()
+	changes, unsubscribe := cache.Subscribe()
+	defer unsubscribe()
+
+	next := func() Change {
+		t.Helper()
+		select {
+		case change := <-changes:
+			return change
+		case <-time.After(5 * time.Second):
+			t.Fatal("no change notified")
+			return Change{}
+		}
+	}
+
+	// Another process registers a workspace
+	other, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := other.SetWorkspace(&Workspace{Name: "api", Path: "/src/api"}); err != nil {
+		t.Fatal(err)
+	}
+	if change := next(); !reflect.DeepEqual(change, Change{Added: []string{"api"}}) {
+		t.Errorf("change = %+v, want api added", change)
+	}
+	reg, err := cache.Get(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := reg.GetWorkspace("api"); !ok {
+		t.Error("Get() should return the reloaded registry")
+	}
+
+	// Saving without changing anything isn't a change
+	if err := other.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if err := other.SetWorkspace(&Workspace{Name: "api", Path: "/src/api", Branch: "main"}); err != nil {
+		t.Fatal(err)
+	}
+	if change := next(); !reflect.DeepEqual(change, Change{Updated: []string{"api"}}) {
+		t.Errorf("change = %+v, want only api updated", change)
+	}
+}
+
+func TestChange_Merge(t *testing.T) {
+	first := Change{Added: []string{"api"}, Updated: []string{"web"}}
+	second := Change{Updated: []string{"web", "api"}, Removed: []string{"docs"}, Proxy: true}
+
+	got := first.merge(second)
+	want := Change{Added: []string{"api"}, Updated: []string{"api", "web"}, Removed: []string{"docs"}, Proxy: true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("merge = %+v, want %+v", got, want)
+	}
+}
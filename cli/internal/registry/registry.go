@@ -1,24 +1,32 @@
 package registry
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"sync"
 	"syscall"
 	"time"
 
+	"github.com/iheanyi/grove/internal/checks"
 	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/crash"
 	"github.com/iheanyi/grove/internal/discovery"
+	"github.com/iheanyi/grove/internal/events"
+	"github.com/iheanyi/grove/internal/execx"
 	"github.com/iheanyi/grove/internal/port"
+	"github.com/iheanyi/grove/internal/tunnel"
 )
 
 // cleanupInterval is the minimum time between cleanup runs
 const cleanupInterval = 5 * time.Second
 
+// lockPollInterval is how often a busy registry lock is retried while
+// waiting for it
+const lockPollInterval = 10 * time.Millisecond
+
 // Workspace represents a unified view of a git worktree with optional server state.
 // This is the primary data structure for tracking development environments.
 type Workspace struct {
@@ -30,12 +38,22 @@ type Workspace struct {
 	Branch   string `json:"branch"`
 	MainRepo string `json:"main_repo,omitempty"`
 	GitDirty bool   `json:"git_dirty,omitempty"`
+	// DirtyFiles is how many files have uncommitted changes
+	DirtyFiles int `json:"dirty_files,omitempty"`
+
+	// Repo identifies the repository (normalized origin URL, or the main
+	// worktree's path), so same-named branches of different repositories
+	// are told apart
+	Repo string `json:"repo,omitempty"`
 
 	// Activity detection
 	HasClaude    bool      `json:"has_claude,omitempty"`
 	HasVSCode    bool      `json:"has_vscode,omitempty"`
 	LastActivity time.Time `json:"last_activity,omitempty"`
 
+	// Editor is the editor 'grove code' last opened the workspace in
+	Editor string `json:"editor,omitempty"`
+
 	// Server (optional - nil means no server configured)
 	Server *ServerState `json:"server,omitempty"`
 
@@ -43,20 +61,51 @@ type Workspace struct {
 	Tags         []string  `json:"tags,omitempty"`
 	CreatedAt    time.Time `json:"created_at,omitempty"`
 	DiscoveredAt time.Time `json:"discovered_at,omitempty"`
+
+	// Review state (set from the review queue). ReviewedHead records the
+	// commit that was reviewed so new commits put it back in the queue.
+	ReviewedAt   time.Time `json:"reviewed_at,omitempty"`
+	ReviewedHead string    `json:"reviewed_head,omitempty"`
+
+	// Share is the public tunnel to the server opened by 'grove share'
+	Share *Share `json:"share,omitempty"`
+
+	// Task is the ID of the task the worktree was created for
+	// ('grove new --task')
+	Task string `json:"task,omitempty"`
+
+	// Checks are the results of the last 'grove ci' run
+	Checks *checks.Record `json:"checks,omitempty"`
+}
+
+// Share is a public tunnel to a workspace's server
+type Share struct {
+	Provider  string    `json:"provider"`
+	URL       string    `json:"url"`
+	PID       int       `json:"pid"`
+	LogFile   string    `json:"log_file,omitempty"`
+	StartedAt time.Time `json:"started_at"`
 }
 
 // ServerState represents the state of a dev server within a workspace.
 type ServerState struct {
-	Port            int          `json:"port"`
-	PID             int          `json:"pid,omitempty"`
-	Status          ServerStatus `json:"status"`
-	URL             string       `json:"url"`
-	Command         []string     `json:"command,omitempty"`
-	LogFile         string       `json:"log_file,omitempty"`
-	StartedAt       time.Time    `json:"started_at,omitempty"`
-	StoppedAt       time.Time    `json:"stopped_at,omitempty"`
-	Health          HealthStatus `json:"health,omitempty"`
-	LastHealthCheck time.Time    `json:"last_health_check,omitempty"`
+	Port            int            `json:"port"`
+	PID             int            `json:"pid,omitempty"`
+	Status          ServerStatus   `json:"status"`
+	URL             string         `json:"url"`
+	Command         []string       `json:"command,omitempty"`
+	Backend         string         `json:"backend,omitempty"`
+	LogFile         string         `json:"log_file,omitempty"`
+	StartedAt       time.Time      `json:"started_at,omitempty"`
+	StoppedAt       time.Time      `json:"stopped_at,omitempty"`
+	PausedAt        time.Time      `json:"paused_at,omitempty"`
+	ResumedAt       time.Time      `json:"resumed_at,omitempty"`
+	Health          HealthStatus   `json:"health,omitempty"`
+	LastHealthCheck time.Time      `json:"last_health_check,omitempty"`
+	FatalLog        string         `json:"fatal_log,omitempty"`
+	Processes       []Process      `json:"processes,omitempty"`
+	Subdomains      map[string]int `json:"subdomains,omitempty"`
+	Ephemeral       bool           `json:"ephemeral,omitempty"`
 }
 
 // IsRunning returns true if the workspace has a running server
@@ -64,7 +113,7 @@ func (w *Workspace) IsRunning() bool {
 	if w.Server == nil {
 		return false
 	}
-	return w.Server.Status == StatusRunning || w.Server.Status == StatusStarting
+	return w.Server.Status == StatusRunning || w.Server.Status == StatusStarting || w.Server.Status == StatusPaused
 }
 
 // HasServerState returns true if the workspace has server configuration
@@ -167,11 +216,18 @@ func (w *Workspace) ToServer() *Server {
 		server.Status = w.Server.Status
 		server.URL = w.Server.URL
 		server.Command = w.Server.Command
+		server.Backend = w.Server.Backend
+		server.Ephemeral = w.Server.Ephemeral
 		server.LogFile = w.Server.LogFile
 		server.StartedAt = w.Server.StartedAt
 		server.StoppedAt = w.Server.StoppedAt
+		server.PausedAt = w.Server.PausedAt
+		server.ResumedAt = w.Server.ResumedAt
 		server.Health = w.Server.Health
 		server.LastHealthCheck = w.Server.LastHealthCheck
+		server.FatalLog = w.Server.FatalLog
+		server.Processes = w.Server.Processes
+		server.Subdomains = w.Server.Subdomains
 	} else {
 		server.Status = StatusStopped
 	}
@@ -201,11 +257,18 @@ func WorkspaceFromServer(s *Server) *Workspace {
 			Status:          s.Status,
 			URL:             s.URL,
 			Command:         s.Command,
+			Backend:         s.Backend,
+			Ephemeral:       s.Ephemeral,
 			LogFile:         s.LogFile,
 			StartedAt:       s.StartedAt,
 			StoppedAt:       s.StoppedAt,
+			PausedAt:        s.PausedAt,
+			ResumedAt:       s.ResumedAt,
 			Health:          s.Health,
 			LastHealthCheck: s.LastHealthCheck,
+			FatalLog:        s.FatalLog,
+			Processes:       s.Processes,
+			Subdomains:      s.Subdomains,
 		}
 	}
 
@@ -223,18 +286,22 @@ func WorkspaceFromWorktree(wt *discovery.Worktree) *Workspace {
 		Path:         wt.Path,
 		Branch:       wt.Branch,
 		MainRepo:     wt.MainRepo,
+		Repo:         wt.Repo,
 		GitDirty:     wt.GitDirty,
+		DirtyFiles:   wt.DirtyFiles,
 		HasClaude:    wt.HasClaude,
 		HasVSCode:    wt.HasVSCode,
 		LastActivity: wt.LastActivity,
 		DiscoveredAt: wt.DiscoveredAt,
+		Editor:       wt.Editor,
 	}
 }
 
 // Registry manages the server registry
 type Registry struct {
-	path string
-	mu   sync.RWMutex
+	path  string
+	store Store
+	mu    sync.RWMutex
 
 	// New unified model
 	Workspaces map[string]*Workspace `json:"workspaces,omitempty"`
@@ -252,55 +319,53 @@ type Registry struct {
 	lastCleanup time.Time
 }
 
-// New creates a new registry instance
+// New creates a new registry instance, stored with the configured backend
 func New() *Registry {
-	return &Registry{
+	r := &Registry{
 		path:       config.RegistryPath(),
 		Workspaces: make(map[string]*Workspace),
 		Servers:    make(map[string]*Server),
 		Worktrees:  make(map[string]*discovery.Worktree),
 		Proxy:      &ProxyInfo{},
 	}
+	if Backend() == BackendSQLite {
+		r.path = config.RegistryDBPath()
+		r.store = newSQLiteStore(r.path)
+	}
+	return r
 }
 
 // Load loads the registry from disk
 func Load() (*Registry, error) {
+	return LoadContext(context.Background())
+}
+
+// LoadContext loads the registry from disk, giving up with ctx's error if
+// ctx is done while waiting for another process to finish writing it
+func LoadContext(ctx context.Context) (*Registry, error) {
 	r := New()
-	return r, r.load()
+	return r, r.load(ctx)
 }
 
-// load reads the registry from disk with file-level locking for concurrent access safety.
-func (r *Registry) load() error {
+// LoadFrom loads the JSON registry at path rather than the default
+// location
+func LoadFrom(path string) (*Registry, error) {
+	r := New()
+	r.path = path
+	r.store = nil
+	return r, r.load(context.Background())
+}
+
+// load reads the registry from its store
+func (r *Registry) load(ctx context.Context) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	// Use file-level locking for concurrent process safety
-	lockPath := r.path + ".lock"
-	lockFile, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0644)
-	if err != nil {
-		// If we can't create the lock file, proceed without locking
-		// (directory may not exist yet on first run)
-	} else {
-		defer lockFile.Close()
-		if err := syscall.Flock(int(lockFile.Fd()), syscall.LOCK_SH); err == nil {
-			defer syscall.Flock(int(lockFile.Fd()), syscall.LOCK_UN) //nolint:errcheck
-		}
+	if err := r.storage().Load(ctx, r); err != nil {
+		return err
 	}
 
-	data, err := os.ReadFile(r.path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			// No registry file, start fresh
-			return nil
-		}
-		return fmt.Errorf("failed to read registry: %w", err)
-	}
-
-	if err := json.Unmarshal(data, r); err != nil {
-		return fmt.Errorf("failed to parse registry: %w", err)
-	}
-
-	// Ensure maps are initialized after unmarshal
+	// Ensure maps are initialized after loading
 	if r.Workspaces == nil {
 		r.Workspaces = make(map[string]*Workspace)
 	}
@@ -333,6 +398,7 @@ func (r *Registry) migrateToWorkspaces() {
 			// Merge worktree data into existing workspace
 			existing.MainRepo = wt.MainRepo
 			existing.GitDirty = wt.GitDirty
+			existing.DirtyFiles = wt.DirtyFiles
 			existing.HasClaude = wt.HasClaude
 			existing.HasVSCode = wt.HasVSCode
 			existing.LastActivity = wt.LastActivity
@@ -352,42 +418,34 @@ func (r *Registry) migrateToWorkspaces() {
 	r.migrated = true
 }
 
-// Save saves the registry to disk with file-level locking for concurrent access safety.
+// Save saves the registry to its store
 func (r *Registry) Save() error {
+	return r.SaveContext(context.Background())
+}
+
+// SaveContext is Save, giving up with ctx's error if ctx is done while
+// waiting for another process to finish writing
+func (r *Registry) SaveContext(ctx context.Context) error {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	// Ensure directory exists
-	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
-		return fmt.Errorf("failed to create registry directory: %w", err)
-	}
-
-	// Sync workspaces back to legacy maps for backward compatibility
-	r.syncToLegacy()
-
-	data, err := json.MarshalIndent(r, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal registry: %w", err)
-	}
-
-	// Use file-level locking for concurrent process safety
-	lockPath := r.path + ".lock"
-	lockFile, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0644)
-	if err != nil {
-		return fmt.Errorf("failed to create lock file: %w", err)
-	}
-	defer lockFile.Close()
-
-	if err := syscall.Flock(int(lockFile.Fd()), syscall.LOCK_EX); err != nil {
-		return fmt.Errorf("failed to acquire file lock: %w", err)
-	}
-	defer syscall.Flock(int(lockFile.Fd()), syscall.LOCK_UN) //nolint:errcheck
+	return r.storage().Save(ctx, r)
+}
 
-	if err := os.WriteFile(r.path, data, 0644); err != nil {
-		return fmt.Errorf("failed to write registry: %w", err)
+// flock locks f, polling rather than blocking so that waiting for another
+// process to release the lock stops when ctx is done
+func flock(ctx context.Context, f *os.File, how int) error {
+	for {
+		err := syscall.Flock(int(f.Fd()), how|syscall.LOCK_NB)
+		if err != syscall.EWOULDBLOCK {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(lockPollInterval):
+		}
 	}
-
-	return nil
 }
 
 // syncToLegacy updates the legacy Servers and Worktrees maps from Workspaces
@@ -407,12 +465,15 @@ func (r *Registry) syncToLegacy() {
 			Path:         ws.Path,
 			Branch:       ws.Branch,
 			MainRepo:     ws.MainRepo,
+			Repo:         ws.Repo,
 			GitDirty:     ws.GitDirty,
+			DirtyFiles:   ws.DirtyFiles,
 			HasClaude:    ws.HasClaude,
 			HasVSCode:    ws.HasVSCode,
 			LastActivity: ws.LastActivity,
 			DiscoveredAt: ws.DiscoveredAt,
 			HasServer:    ws.HasServerState(),
+			Editor:       ws.Editor,
 		}
 	}
 }
@@ -515,11 +576,18 @@ func (r *Registry) Set(server *Server) error {
 			Status:          server.Status,
 			URL:             server.URL,
 			Command:         server.Command,
+			Backend:         server.Backend,
+			Ephemeral:       server.Ephemeral,
 			LogFile:         server.LogFile,
 			StartedAt:       server.StartedAt,
 			StoppedAt:       server.StoppedAt,
+			PausedAt:        server.PausedAt,
+			ResumedAt:       server.ResumedAt,
 			Health:          server.Health,
 			LastHealthCheck: server.LastHealthCheck,
+			FatalLog:        server.FatalLog,
+			Processes:       server.Processes,
+			Subdomains:      server.Subdomains,
 		}
 	} else {
 		// Create new workspace from server
@@ -581,6 +649,9 @@ func (r *Registry) GetUsedPorts() map[int]bool {
 	for _, ws := range r.Workspaces {
 		if ws.IsRunning() && ws.Server != nil {
 			ports[ws.Server.Port] = true
+			for _, p := range ws.Server.Subdomains {
+				ports[p] = true
+			}
 		}
 	}
 	return ports
@@ -646,7 +717,8 @@ func (r *Registry) Cleanup() (*CleanupResult, error) {
 	// Group workspaces by path
 	pathToNames := make(map[string][]string)
 	for name, ws := range r.Workspaces {
-		if ws.Path != "" {
+		// 'grove run' entries share their worktree's path on purpose
+		if ws.Path != "" && (ws.Server == nil || !ws.Server.Ephemeral) {
 			pathToNames[ws.Path] = append(pathToNames[ws.Path], name)
 		}
 	}
@@ -690,6 +762,9 @@ func (r *Registry) Cleanup() (*CleanupResult, error) {
 	}
 	var cwdRequests []cwdRequest
 
+	// Servers whose process died unexpectedly, reported after unlocking
+	var crashed []*Server
+
 	// Check workspaces
 	for name, ws := range r.Workspaces {
 		// Check if the path still exists
@@ -702,6 +777,13 @@ func (r *Registry) Cleanup() (*CleanupResult, error) {
 			}
 		}
 
+		// One-off 'grove run' entries go away with their process
+		if ws.Server != nil && ws.Server.Ephemeral && (ws.Server.PID <= 0 || !isProcessRunning(ws.Server.PID)) {
+			workspacesToDelete = append(workspacesToDelete, name)
+			result.RemovedServers = append(result.RemovedServers, name)
+			continue
+		}
+
 		// Check server state if present
 		if ws.Server != nil {
 			// Check if PID is still running
@@ -718,6 +800,8 @@ func (r *Registry) Cleanup() (*CleanupResult, error) {
 						continue
 					}
 				}
+				// The process exited without going through grove stop
+				crashed = append(crashed, ws.ToServer())
 				ws.Server.Status = StatusStopped
 				ws.Server.PID = 0
 				result.Stopped = append(result.Stopped, name)
@@ -779,20 +863,56 @@ func (r *Registry) Cleanup() (*CleanupResult, error) {
 
 	// Remove entries with missing paths
 	for _, name := range workspacesToDelete {
+		if ws := r.Workspaces[name]; ws.Share != nil {
+			tunnel.Stop(ws.Share.PID) //nolint:errcheck // Best effort
+		}
 		delete(r.Workspaces, name)
 	}
 
-	needsSave := len(result.Stopped) > 0 || len(result.RemovedServers) > 0 || len(result.RemovedWorktrees) > 0 || len(result.Started) > 0
+	// Shares end with their server, and are forgotten once the tunnel exits
+	sharesClosed := false
+	for _, ws := range r.Workspaces {
+		if ws.Share == nil {
+			continue
+		}
+		if !ws.IsRunning() || !tunnel.IsRunning(ws.Share.PID) {
+			tunnel.Stop(ws.Share.PID) //nolint:errcheck // Best effort
+			ws.Share = nil
+			sharesClosed = true
+		}
+	}
+
+	needsSave := sharesClosed || len(result.Stopped) > 0 || len(result.RemovedServers) > 0 || len(result.RemovedWorktrees) > 0 || len(result.Started) > 0
 
 	// Release the lock before saving to avoid deadlock (Save() acquires RLock)
 	r.mu.Unlock()
 
+	var err error
 	if needsSave {
-		err := r.Save()
-		return result, err
+		err = r.Save()
 	}
 
-	return result, nil
+	for _, server := range crashed {
+		// A detached server's exit status is lost, but its log isn't
+		report := crash.New(server.Name, "", server.StartedAt, nil)
+		report.LogFile = server.LogFile
+		if server.LogFile != "" {
+			report.Log = crash.TailFile(server.LogFile, crash.LogLines)
+		}
+		if server.FatalLog != "" {
+			report.Fatal = []string{server.FatalLog}
+		} else if patterns, err := crash.CompileFatal(nil); err == nil {
+			report.Fatal = crash.FindFatal(report.Log, patterns)
+		}
+		// Next to the registry, i.e. config.CrashesDir()
+		crash.Save(filepath.Join(filepath.Dir(r.path), "crashes"), report) //nolint:errcheck // Best effort
+
+		e := server.Event(events.ServerCrashed)
+		e.Message = report.Reason
+		events.Publish(e)
+	}
+
+	return result, err
 }
 
 // isProcessRunning checks if a process with the given PID is running
@@ -809,8 +929,7 @@ func isProcessRunning(pid int) bool {
 
 // getProcessCwd returns the current working directory of a process
 func getProcessCwd(pid int) string {
-	cmd := exec.Command("lsof", "-p", fmt.Sprintf("%d", pid), "-d", "cwd", "-Fn")
-	output, err := cmd.Output()
+	output, err := execx.OS.Output(context.Background(), execx.Query("lsof", "-p", fmt.Sprintf("%d", pid), "-d", "cwd", "-Fn"))
 	if err != nil {
 		return ""
 	}
@@ -840,8 +959,7 @@ func batchGetProcessCwds(pids map[int]bool) map[int]string {
 	}
 	pidList := strings.Join(pidStrs, ",")
 
-	cmd := exec.Command("lsof", "-d", "cwd", "-a", "-p", pidList, "-Fn")
-	output, err := cmd.Output()
+	output, err := execx.OS.Output(context.Background(), execx.Query("lsof", "-d", "cwd", "-a", "-p", pidList, "-Fn"))
 	if err != nil {
 		// Fall back to individual lookups
 		for pid := range pids {
@@ -879,12 +997,15 @@ func (r *Registry) GetWorktree(name string) (*discovery.Worktree, bool) {
 			Path:         ws.Path,
 			Branch:       ws.Branch,
 			MainRepo:     ws.MainRepo,
+			Repo:         ws.Repo,
 			GitDirty:     ws.GitDirty,
+			DirtyFiles:   ws.DirtyFiles,
 			HasClaude:    ws.HasClaude,
 			HasVSCode:    ws.HasVSCode,
 			LastActivity: ws.LastActivity,
 			DiscoveredAt: ws.DiscoveredAt,
 			HasServer:    ws.HasServerState(),
+			Editor:       ws.Editor,
 		}, true
 	}
 	return nil, false
@@ -929,7 +1050,11 @@ func (r *Registry) SetWorktree(wt *discovery.Worktree) error {
 			ws := r.Workspaces[existingName]
 			ws.Branch = wt.Branch
 			ws.MainRepo = wt.MainRepo
+			if wt.Repo != "" {
+				ws.Repo = wt.Repo
+			}
 			ws.GitDirty = wt.GitDirty
+			ws.DirtyFiles = wt.DirtyFiles
 			ws.HasClaude = wt.HasClaude
 			ws.HasVSCode = wt.HasVSCode
 			ws.LastActivity = wt.LastActivity
@@ -942,7 +1067,11 @@ func (r *Registry) SetWorktree(wt *discovery.Worktree) error {
 		ws.Path = wt.Path
 		ws.Branch = wt.Branch
 		ws.MainRepo = wt.MainRepo
+		if wt.Repo != "" {
+			ws.Repo = wt.Repo
+		}
 		ws.GitDirty = wt.GitDirty
+		ws.DirtyFiles = wt.DirtyFiles
 		ws.HasClaude = wt.HasClaude
 		ws.HasVSCode = wt.HasVSCode
 		ws.LastActivity = wt.LastActivity
@@ -956,6 +1085,50 @@ func (r *Registry) SetWorktree(wt *discovery.Worktree) error {
 	return r.Save()
 }
 
+// RecordEditor records the editor a workspace was opened in, so activity
+// detection also looks for that editor's process
+func (r *Registry) RecordEditor(name, editorName string) error {
+	r.mu.Lock()
+	ws, ok := r.Workspaces[name]
+	if !ok {
+		r.mu.Unlock()
+		return fmt.Errorf("workspace '%s' not found", name)
+	}
+	ws.Editor = editorName
+	r.mu.Unlock()
+
+	return r.Save()
+}
+
+// RecordRepo records the repository a workspace belongs to (see
+// Workspace.Repo)
+func (r *Registry) RecordRepo(name, repo string) error {
+	r.mu.Lock()
+	ws, ok := r.Workspaces[name]
+	if !ok {
+		r.mu.Unlock()
+		return fmt.Errorf("workspace '%s' not found", name)
+	}
+	ws.Repo = repo
+	r.mu.Unlock()
+
+	return r.Save()
+}
+
+// RecordChecks records the results of a workspace's checks
+func (r *Registry) RecordChecks(name string, record *checks.Record) error {
+	r.mu.Lock()
+	ws, ok := r.Workspaces[name]
+	if !ok {
+		r.mu.Unlock()
+		return fmt.Errorf("workspace '%s' not found", name)
+	}
+	ws.Checks = record
+	r.mu.Unlock()
+
+	return r.Save()
+}
+
 // RemoveWorktree removes a worktree from the registry (backward compatible wrapper)
 func (r *Registry) RemoveWorktree(name string) error {
 	r.mu.Lock()
@@ -984,12 +1157,15 @@ func (r *Registry) ListWorktrees() []*discovery.Worktree {
 			Path:         ws.Path,
 			Branch:       ws.Branch,
 			MainRepo:     ws.MainRepo,
+			Repo:         ws.Repo,
 			GitDirty:     ws.GitDirty,
+			DirtyFiles:   ws.DirtyFiles,
 			HasClaude:    ws.HasClaude,
 			HasVSCode:    ws.HasVSCode,
 			LastActivity: ws.LastActivity,
 			DiscoveredAt: ws.DiscoveredAt,
 			HasServer:    ws.HasServerState(),
+			Editor:       ws.Editor,
 		})
 	}
 	return worktrees
@@ -997,8 +1173,9 @@ func (r *Registry) ListWorktrees() []*discovery.Worktree {
 
 // UpdateWorktreeActivities updates all workspaces with their current activity status.
 // Uses batch detection for agents and VS Code (single lsof/ps call each),
-// then parallelizes git status checks.
-func (r *Registry) UpdateWorktreeActivities() error {
+// then parallelizes git status checks. Cancelling ctx stops the checks
+// without changing the registry.
+func (r *Registry) UpdateWorktreeActivities(ctx context.Context) error {
 	r.mu.RLock()
 	workspaces := make([]*Workspace, 0, len(r.Workspaces))
 	for _, ws := range r.Workspaces {
@@ -1018,21 +1195,25 @@ func (r *Registry) UpdateWorktreeActivities() error {
 			Path:     ws.Path,
 			Branch:   ws.Branch,
 			MainRepo: ws.MainRepo,
+			Editor:   ws.Editor,
 		}
 	}
 
 	// Use batch detection (much faster than per-worktree)
-	discovery.DetectActivitiesBatch(worktrees)
+	if err := discovery.DetectActivitiesBatch(ctx, worktrees); err != nil {
+		return err
+	}
 
 	// Copy results back to workspaces
 	r.mu.Lock()
 	for i, wt := range worktrees {
 		workspaces[i].GitDirty = wt.GitDirty
+		workspaces[i].DirtyFiles = wt.DirtyFiles
 		workspaces[i].HasClaude = wt.HasClaude
 		workspaces[i].HasVSCode = wt.HasVSCode
 		workspaces[i].LastActivity = wt.LastActivity
 	}
 	r.mu.Unlock()
 
-	return r.Save()
+	return r.SaveContext(ctx)
 }
@@ -1,12 +1,17 @@
 package registry
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"syscall"
 	"testing"
 	"time"
 
+	"github.com/iheanyi/grove/internal/crash"
 	"github.com/iheanyi/grove/internal/discovery"
 )
 
@@ -42,7 +47,7 @@ func TestLoad_NonExistentFile(t *testing.T) {
 		Proxy:      &ProxyInfo{},
 	}
 
-	err := r.load()
+	err := r.load(context.Background())
 	if err != nil {
 		t.Errorf("load() should succeed for non-existent file, got error: %v", err)
 	}
@@ -65,7 +70,7 @@ func TestLoad_InvalidJSON(t *testing.T) {
 		Proxy:      &ProxyInfo{},
 	}
 
-	err := r.load()
+	err := r.load(context.Background())
 	if err == nil {
 		t.Error("load() should fail for invalid JSON")
 	}
@@ -106,7 +111,7 @@ func TestLoad_ValidJSON_LegacyFormat(t *testing.T) {
 		Proxy:      &ProxyInfo{},
 	}
 
-	err = r.load()
+	err = r.load(context.Background())
 	if err != nil {
 		t.Errorf("load() failed: %v", err)
 	}
@@ -174,7 +179,7 @@ func TestLoad_ValidJSON_NewFormat(t *testing.T) {
 		Proxy:      &ProxyInfo{},
 	}
 
-	err = r.load()
+	err = r.load(context.Background())
 	if err != nil {
 		t.Errorf("load() failed: %v", err)
 	}
@@ -289,6 +294,42 @@ func TestSave_ReadOnlyDirectory(t *testing.T) {
 	}
 }
 
+func TestLoadSave_ContextWhileLocked(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "registry.json")
+	r, err := LoadFrom(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another process is writing the registry
+	lock, err := os.OpenFile(path+".lock", os.O_RDWR, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := syscall.Flock(int(lock.Fd()), syscall.LOCK_EX); err != nil {
+		lock.Close()
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := r.load(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("load() while locked = %v, want context.DeadlineExceeded", err)
+	}
+	if err := r.SaveContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("SaveContext() while locked = %v, want context.DeadlineExceeded", err)
+	}
+
+	// Once the lock is released, waiting succeeds
+	time.AfterFunc(50*time.Millisecond, func() { lock.Close() })
+	if err := r.SaveContext(context.Background()); err != nil {
+		t.Errorf("SaveContext() after unlock = %v", err)
+	}
+}
+
 func TestSet_Success(t *testing.T) {
 	tmpDir := t.TempDir()
 	registryPath := filepath.Join(tmpDir, "registry.json")
@@ -594,6 +635,9 @@ func TestCleanup_RemovesDeadProcesses(t *testing.T) {
 	if deadWs.Server.PID != 0 {
 		t.Errorf("Expected dead server PID to be 0, got %d", deadWs.Server.PID)
 	}
+	if _, ok := crash.Latest(filepath.Join(tmpDir, "crashes"), "dead-server"); !ok {
+		t.Error("Expected a crash report for dead-server")
+	}
 
 	// Verify alive server unchanged
 	aliveWs := r.Workspaces["alive-server"]
@@ -602,6 +646,100 @@ func TestCleanup_RemovesDeadProcesses(t *testing.T) {
 	}
 }
 
+func TestCleanup_RemovesDeadEphemeralEntries(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	r := &Registry{
+		path:       filepath.Join(tmpDir, "registry.json"),
+		Workspaces: make(map[string]*Workspace),
+		Servers:    make(map[string]*Server),
+		Worktrees:  make(map[string]*discovery.Worktree),
+		Proxy:      &ProxyInfo{},
+	}
+
+	// A 'grove run' entry whose process is gone, sharing its worktree's path
+	r.Workspaces["feature-run"] = &Workspace{
+		Name: "feature-run",
+		Path: tmpDir,
+		Server: &ServerState{
+			Port:      3000,
+			Status:    StatusRunning,
+			PID:       999999999,
+			Ephemeral: true,
+		},
+	}
+	r.Workspaces["feature"] = &Workspace{Name: "feature", Path: tmpDir}
+
+	result, err := r.Cleanup()
+	if err != nil {
+		t.Fatalf("Cleanup() failed: %v", err)
+	}
+
+	if _, ok := r.Workspaces["feature-run"]; ok {
+		t.Error("Expected dead ephemeral entry to be removed")
+	}
+	if _, ok := r.Workspaces["feature"]; !ok {
+		t.Error("Expected the worktree sharing its path to be kept")
+	}
+	if len(result.RemovedServers) != 1 || result.RemovedServers[0] != "feature-run" {
+		t.Errorf("Expected [feature-run] in RemovedServers, got %v", result.RemovedServers)
+	}
+}
+
+func TestCleanup_ClosesSharesOfStoppedServers(t *testing.T) {
+	tunnelProcess := func() *exec.Cmd {
+		cmd := exec.Command("sleep", "30")
+		cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+		if err := cmd.Start(); err != nil {
+			t.Skipf("sleep unavailable: %v", err)
+		}
+		t.Cleanup(func() { cmd.Process.Kill() }) //nolint:errcheck
+		return cmd
+	}
+	stoppedTunnel := tunnelProcess()
+	runningTunnel := tunnelProcess()
+
+	r := &Registry{
+		path:       filepath.Join(t.TempDir(), "registry.json"),
+		Workspaces: make(map[string]*Workspace),
+		Servers:    make(map[string]*Server),
+		Worktrees:  make(map[string]*discovery.Worktree),
+		Proxy:      &ProxyInfo{},
+	}
+	r.Workspaces["stopped"] = &Workspace{
+		Name:   "stopped",
+		Server: &ServerState{Status: StatusStopped},
+		Share:  &Share{Provider: "cloudflared", PID: stoppedTunnel.Process.Pid},
+	}
+	r.Workspaces["running"] = &Workspace{
+		Name:   "running",
+		Server: &ServerState{Status: StatusRunning, PID: os.Getpid()},
+		Share:  &Share{Provider: "cloudflared", PID: runningTunnel.Process.Pid},
+	}
+
+	if _, err := r.Cleanup(); err != nil {
+		t.Fatalf("Cleanup() failed: %v", err)
+	}
+
+	if r.Workspaces["stopped"].Share != nil {
+		t.Error("Expected the stopped server's share to be closed")
+	}
+	if r.Workspaces["running"].Share == nil {
+		t.Error("Expected the running server's share to be kept")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		stoppedTunnel.Wait() //nolint:errcheck
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Error("Expected the stopped server's tunnel process to be killed")
+	}
+}
+
 func TestCleanup_NoChangesWhenNoDeadProcesses(t *testing.T) {
 	tmpDir := t.TempDir()
 	registryPath := filepath.Join(tmpDir, "registry.json")
@@ -680,6 +818,20 @@ func TestWorktreeOperations(t *testing.T) {
 		t.Errorf("Expected 1 worktree, got %d", len(worktrees))
 	}
 
+	// Test RecordEditor survives rediscovery
+	if err := r.RecordEditor("feature-branch", "cursor"); err != nil {
+		t.Errorf("RecordEditor() failed: %v", err)
+	}
+	if err := r.SetWorktree(wt); err != nil {
+		t.Errorf("SetWorktree() failed: %v", err)
+	}
+	if got, _ := r.GetWorktree("feature-branch"); got.Editor != "cursor" {
+		t.Errorf("Expected recorded editor cursor, got %q", got.Editor)
+	}
+	if err := r.RecordEditor("missing", "zed"); err == nil {
+		t.Error("RecordEditor() should fail for an unknown workspace")
+	}
+
 	// Test RemoveWorktree
 	err = r.RemoveWorktree("feature-branch")
 	if err != nil {
@@ -951,7 +1103,7 @@ func TestMigration(t *testing.T) {
 		Proxy:      &ProxyInfo{},
 	}
 
-	if err := r.load(); err != nil {
+	if err := r.load(context.Background()); err != nil {
 		t.Fatalf("load() failed: %v", err)
 	}
 
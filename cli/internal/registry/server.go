@@ -2,7 +2,10 @@ package registry
 
 import (
 	"fmt"
+	"strings"
 	"time"
+
+	"github.com/iheanyi/grove/internal/events"
 )
 
 // ServerStatus represents the status of a server
@@ -14,6 +17,7 @@ const (
 	StatusStarting ServerStatus = "starting"
 	StatusStopping ServerStatus = "stopping"
 	StatusCrashed  ServerStatus = "crashed"
+	StatusPaused   ServerStatus = "paused"
 )
 
 // HealthStatus represents the health of a server
@@ -25,6 +29,9 @@ const (
 	HealthUnknown   HealthStatus = "unknown"
 )
 
+// BackendCompose marks servers run as Docker Compose projects
+const BackendCompose = "compose"
+
 // Server represents a registered server
 type Server struct {
 	// Name is the sanitized worktree name (used as key)
@@ -39,6 +46,10 @@ type Server struct {
 	// Command is the command used to start the server
 	Command []string `json:"command"`
 
+	// Backend is how the server is run: empty for a local process, or
+	// "compose" for a Docker Compose project
+	Backend string `json:"backend,omitempty"`
+
 	// Path is the working directory
 	Path string `json:"path"`
 
@@ -57,9 +68,17 @@ type Server struct {
 	// StoppedAt is when the server was stopped
 	StoppedAt time.Time `json:"stopped_at,omitempty"`
 
+	// PausedAt and ResumedAt track the last SIGSTOP/SIGCONT (grove pause)
+	PausedAt  time.Time `json:"paused_at,omitempty"`
+	ResumedAt time.Time `json:"resumed_at,omitempty"`
+
 	// LastHealthCheck is when the last health check was performed
 	LastHealthCheck time.Time `json:"last_health_check,omitempty"`
 
+	// FatalLog is the first line of this run's output that matched a fatal
+	// log pattern (e.g. EADDRINUSE), which marks the server unhealthy
+	FatalLog string `json:"fatal_log,omitempty"`
+
 	// Branch is the git branch name
 	Branch string `json:"branch,omitempty"`
 
@@ -68,11 +87,62 @@ type Server struct {
 
 	// Tags is a list of user-defined tags for categorization
 	Tags []string `json:"tags,omitempty"`
+
+	// Processes holds per-process state for multi-process servers
+	Processes []Process `json:"processes,omitempty"`
+
+	// Subdomains maps subdomains to the port they are routed to
+	Subdomains map[string]int `json:"subdomains,omitempty"`
+
+	// Ephemeral marks one-off 'grove run' entries, which are removed when
+	// their process exits
+	Ephemeral bool `json:"ephemeral,omitempty"`
+
+	// EnvHash fingerprints the environment the server was started with, so
+	// 'grove reload' can tell whether .grove.yaml changes need a restart
+	EnvHash string `json:"env_hash,omitempty"`
+}
+
+// Process represents one named process of a multi-process server
+type Process struct {
+	// Name is the process name from .grove.yaml (e.g., "web", "jobs")
+	Name string `json:"name"`
+
+	// Command is the shell command used to start the process
+	Command string `json:"command"`
+
+	// PID is the process ID of the running process
+	PID int `json:"pid,omitempty"`
+
+	// Port is the port exported as PORT to the process, if any
+	Port int `json:"port,omitempty"`
+
+	// Status is the current process status
+	Status ServerStatus `json:"status"`
+
+	// Web is true for the process that receives the allocated PORT
+	Web bool `json:"web,omitempty"`
 }
 
-// IsRunning returns true if the server is currently running
+// IsRunning returns true if the server is currently running. Paused
+// servers count as running: their processes are alive, just stopped.
 func (s *Server) IsRunning() bool {
-	return s.Status == StatusRunning || s.Status == StatusStarting
+	return s.Status == StatusRunning || s.Status == StatusStarting || s.Status == StatusPaused
+}
+
+// IsPaused returns true if the server's processes are stopped with SIGSTOP
+func (s *Server) IsPaused() bool {
+	return s.Status == StatusPaused
+}
+
+// IsMultiProcess returns true if the server supervises multiple named processes
+func (s *Server) IsMultiProcess() bool {
+	return len(s.Processes) > 0
+}
+
+// IsCompose returns true if the server is a Docker Compose project
+func (s *Server) IsCompose() bool {
+	return s.Backend == BackendCompose
 }
 
 // HasTag returns true if the server has the specified tag
@@ -105,6 +175,15 @@ func (s *Server) RemoveTag(tag string) bool {
 	return false
 }
 
+// FormatTags renders tags for display, e.g. "#frontend #urgent"
+func FormatTags(tags []string) string {
+	parts := make([]string, len(tags))
+	for i, tag := range tags {
+		parts[i] = "#" + tag
+	}
+	return strings.Join(parts, " ")
+}
+
 // Uptime returns the duration the server has been running
 func (s *Server) Uptime() time.Duration {
 	if s.StartedAt.IsZero() {
@@ -148,9 +227,26 @@ type ProxyInfo struct {
 	StartedAt time.Time `json:"started_at,omitempty"`
 	HTTPPort  int       `json:"http_port"`
 	HTTPSPort int       `json:"https_port"`
+
+	// WakePort is where the proxy routes paused servers so the first
+	// request resumes them
+	WakePort int `json:"wake_port,omitempty"`
 }
 
 // IsRunning returns true if the proxy is running
 func (p *ProxyInfo) IsRunning() bool {
 	return p.PID > 0
 }
+
+// Event builds a lifecycle event describing this server
+func (s *Server) Event(t events.Type) events.Event {
+	return events.Event{
+		Type:   t,
+		Server: s.Name,
+		Path:   s.Path,
+		URL:    s.URL,
+		Port:   s.Port,
+		PID:    s.PID,
+		Health: string(s.Health),
+	}
+}
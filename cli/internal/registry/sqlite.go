@@ -0,0 +1,429 @@
+package registry
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/events"
+	"github.com/iheanyi/grove/internal/stats"
+	_ "modernc.org/sqlite" // registers the "sqlite" driver
+)
+
+// sqliteSchema creates the SQLite registry's tables. Workspaces and the
+// proxy are stored as JSON, with the columns worth querying alongside.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS workspaces (
+	name       TEXT PRIMARY KEY,
+	path       TEXT NOT NULL,
+	status     TEXT NOT NULL DEFAULT '',
+	data       TEXT NOT NULL,
+	updated_at INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS meta (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS events (
+	id     INTEGER PRIMARY KEY AUTOINCREMENT,
+	time   INTEGER NOT NULL,
+	type   TEXT NOT NULL,
+	server TEXT NOT NULL,
+	data   TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS events_time ON events (time);
+CREATE INDEX IF NOT EXISTS events_server ON events (server, time);
+CREATE TABLE IF NOT EXISTS usage (
+	id   INTEGER PRIMARY KEY AUTOINCREMENT,
+	time INTEGER NOT NULL,
+	kind TEXT NOT NULL,
+	data TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS usage_time ON usage (time);
+`
+
+var (
+	dbMu sync.Mutex
+	dbs  = make(map[string]*sql.DB)
+)
+
+// openDB returns the SQLite database at path, creating it if needed.
+// Databases stay open for the life of the process.
+func openDB(path string) (*sql.DB, error) {
+	dbMu.Lock()
+	defer dbMu.Unlock()
+	if db, ok := dbs[path]; ok {
+		return db, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create registry directory: %w", err)
+	}
+	// WAL lets readers work while another process writes; writers wait for
+	// each other rather than failing
+	dsn := "file:" + path + "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_txlock=immediate"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open registry database: %w", err)
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create registry tables: %w", err)
+	}
+	dbs[path] = db
+	return db, nil
+}
+
+// sqliteStore keeps the registry in a SQLite database, one row per
+// workspace. Only the workspaces a registry changed are written when it's
+// saved, so processes saving at the same time don't undo each other's
+// changes.
+type sqliteStore struct {
+	path string
+
+	// jsonPath and activityPath are the JSON registry and activity log
+	// imported into a new database
+	jsonPath     string
+	activityPath string
+
+	mu     sync.Mutex
+	loaded map[string]string // workspace data as last loaded or saved
+	proxy  string
+}
+
+func newSQLiteStore(path string) *sqliteStore {
+	return &sqliteStore{
+		path:         path,
+		jsonPath:     config.RegistryPath(),
+		activityPath: config.ActivityLogPath(),
+	}
+}
+
+func (s *sqliteStore) Load(ctx context.Context, r *Registry) error {
+	db, err := openDB(s.path)
+	if err != nil {
+		return err
+	}
+	if err := s.importJSON(ctx, db); err != nil {
+		return err
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT name, data FROM workspaces")
+	if err != nil {
+		return fmt.Errorf("failed to read registry: %w", err)
+	}
+	defer rows.Close()
+
+	if r.Workspaces == nil {
+		r.Workspaces = make(map[string]*Workspace)
+	}
+	loaded := make(map[string]string)
+	for rows.Next() {
+		var name, data string
+		if err := rows.Scan(&name, &data); err != nil {
+			return fmt.Errorf("failed to read registry: %w", err)
+		}
+		var ws Workspace
+		if err := json.Unmarshal([]byte(data), &ws); err != nil {
+			return fmt.Errorf("failed to parse workspace %s: %w", name, err)
+		}
+		r.Workspaces[name] = &ws
+		loaded[name] = data
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read registry: %w", err)
+	}
+
+	var proxy string
+	err = db.QueryRowContext(ctx, "SELECT value FROM meta WHERE key = 'proxy'").Scan(&proxy)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to read registry: %w", err)
+	}
+	if proxy != "" {
+		if err := json.Unmarshal([]byte(proxy), &r.Proxy); err != nil {
+			return fmt.Errorf("failed to parse proxy state: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loaded = loaded
+	s.proxy = proxy
+	return nil
+}
+
+func (s *sqliteStore) Save(ctx context.Context, r *Registry) error {
+	db, err := openDB(s.path)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to write registry: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	saved := make(map[string]string, len(r.Workspaces))
+	for name, ws := range r.Workspaces {
+		data, err := json.Marshal(ws)
+		if err != nil {
+			return fmt.Errorf("failed to marshal workspace %s: %w", name, err)
+		}
+		saved[name] = string(data)
+		if prev, ok := s.loaded[name]; ok && prev == string(data) {
+			continue
+		}
+		if err := upsertWorkspace(ctx, tx, name, ws, string(data)); err != nil {
+			return err
+		}
+	}
+	for name := range s.loaded {
+		if _, ok := saved[name]; ok {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM workspaces WHERE name = ?", name); err != nil {
+			return fmt.Errorf("failed to remove workspace %s: %w", name, err)
+		}
+	}
+
+	proxy := ""
+	if r.Proxy != nil {
+		data, err := json.Marshal(r.Proxy)
+		if err != nil {
+			return fmt.Errorf("failed to marshal proxy state: %w", err)
+		}
+		proxy = string(data)
+	}
+	if proxy != s.proxy {
+		if _, err := tx.ExecContext(ctx, "INSERT OR REPLACE INTO meta (key, value) VALUES ('proxy', ?)", proxy); err != nil {
+			return fmt.Errorf("failed to write proxy state: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to write registry: %w", err)
+	}
+	s.loaded = saved
+	s.proxy = proxy
+	return nil
+}
+
+func upsertWorkspace(ctx context.Context, tx *sql.Tx, name string, ws *Workspace, data string) error {
+	var status string
+	if ws.Server != nil {
+		status = string(ws.Server.Status)
+	}
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO workspaces (name, path, status, data, updated_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET
+			path = excluded.path, status = excluded.status, data = excluded.data, updated_at = excluded.updated_at`,
+		name, ws.Path, status, data, time.Now().UnixNano())
+	if err != nil {
+		return fmt.Errorf("failed to write workspace %s: %w", name, err)
+	}
+	return nil
+}
+
+// importJSON copies the JSON registry and activity log into the database
+// the first time it's used
+func (s *sqliteStore) importJSON(ctx context.Context, db *sql.DB) error {
+	var imported int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM meta WHERE key = 'imported'").Scan(&imported); err != nil {
+		return fmt.Errorf("failed to read registry: %w", err)
+	}
+	if imported > 0 {
+		return nil
+	}
+
+	old := &Registry{path: s.jsonPath}
+	if err := old.load(ctx); err != nil {
+		return fmt.Errorf("failed to import %s: %w", s.jsonPath, err)
+	}
+	history, err := events.ReadLog(s.activityPath, events.Query{})
+	if err != nil {
+		return fmt.Errorf("failed to import %s: %w", s.activityPath, err)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to import registry: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	// Another process may have imported it since
+	res, err := tx.ExecContext(ctx, "INSERT OR IGNORE INTO meta (key, value) VALUES ('imported', ?)", time.Now().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("failed to import registry: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil
+	}
+
+	for name, ws := range old.Workspaces {
+		data, err := json.Marshal(ws)
+		if err != nil {
+			return fmt.Errorf("failed to marshal workspace %s: %w", name, err)
+		}
+		if err := upsertWorkspace(ctx, tx, name, ws, string(data)); err != nil {
+			return err
+		}
+	}
+	if old.Proxy != nil {
+		data, err := json.Marshal(old.Proxy)
+		if err != nil {
+			return fmt.Errorf("failed to marshal proxy state: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, "INSERT OR REPLACE INTO meta (key, value) VALUES ('proxy', ?)", string(data)); err != nil {
+			return fmt.Errorf("failed to import proxy state: %w", err)
+		}
+	}
+	for _, e := range history {
+		if err := insertEvent(ctx, tx, e); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// execer is a database or a transaction
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func insertEvent(ctx context.Context, db execer, e events.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx, "INSERT INTO events (time, type, server, data) VALUES (?, ?, ?, ?)",
+		e.Time.UnixNano(), string(e.Type), e.Server, string(data))
+	if err != nil {
+		return fmt.Errorf("failed to record event: %w", err)
+	}
+	return nil
+}
+
+// RecordEvent adds an event to the activity timeline of the SQLite registry
+func RecordEvent(e events.Event) error {
+	db, err := openDB(config.RegistryDBPath())
+	if err != nil {
+		return err
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	return insertEvent(context.Background(), db, e)
+}
+
+// EventLogger returns a handler that records each event with RecordEvent
+func EventLogger() events.Handler {
+	return func(e events.Event) {
+		_ = RecordEvent(e)
+	}
+}
+
+// QueryEvents returns the events of the SQLite registry's activity timeline
+// matching a query, oldest first
+func QueryEvents(ctx context.Context, q events.Query) ([]events.Event, error) {
+	db, err := openDB(config.RegistryDBPath())
+	if err != nil {
+		return nil, err
+	}
+
+	limit := -1 // no limit
+	if q.Limit > 0 {
+		limit = q.Limit
+	}
+	var since int64
+	if !q.Since.IsZero() {
+		since = q.Since.UnixNano()
+	}
+	rows, err := db.QueryContext(ctx, `
+		SELECT data FROM events
+		WHERE (? = '' OR server = ?) AND (? = '' OR type = ?) AND time >= ?
+		ORDER BY time DESC, id DESC LIMIT ?`,
+		q.Server, q.Server, string(q.Type), string(q.Type), since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	defer rows.Close()
+
+	var log []events.Event
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to query events: %w", err)
+		}
+		var e events.Event
+		if err := json.Unmarshal([]byte(data), &e); err != nil {
+			continue
+		}
+		log = append(log, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query events: %w", err)
+	}
+	slices.Reverse(log)
+	return log, nil
+}
+
+// RecordUsage adds a usage record (see 'grove stats') to the SQLite registry
+func RecordUsage(r stats.Record) error {
+	db, err := openDB(config.RegistryDBPath())
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	_, err = db.Exec("INSERT INTO usage (time, kind, data) VALUES (?, ?, ?)",
+		r.Time.UnixNano(), string(r.Kind), string(data))
+	if err != nil {
+		return fmt.Errorf("failed to record usage: %w", err)
+	}
+	return nil
+}
+
+// QueryUsage returns the SQLite registry's usage records at or after since,
+// oldest first
+func QueryUsage(ctx context.Context, since time.Time) ([]stats.Record, error) {
+	db, err := openDB(config.RegistryDBPath())
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT data FROM usage WHERE time >= ? ORDER BY time, id", since.UnixNano())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query usage: %w", err)
+	}
+	defer rows.Close()
+
+	var records []stats.Record
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to query usage: %w", err)
+		}
+		var r stats.Record
+		if err := json.Unmarshal([]byte(data), &r); err != nil {
+			continue
+		}
+		records = append(records, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query usage: %w", err)
+	}
+	return records, nil
+}
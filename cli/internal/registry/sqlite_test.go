@@ -0,0 +1,197 @@
+package registry
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/adrg/xdg"
+	"github.com/iheanyi/grove/internal/events"
+	"github.com/iheanyi/grove/internal/stats"
+)
+
+// useSQLite stores registries created by New in a temporary SQLite
+// database
+func useSQLite(t *testing.T) string {
+	t.Helper()
+
+	origHome := xdg.ConfigHome
+	xdg.ConfigHome = t.TempDir()
+	if err := SetBackend(BackendSQLite); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		xdg.ConfigHome = origHome
+		_ = SetBackend(BackendJSON)
+	})
+	return xdg.ConfigHome
+}
+
+func TestSetBackend(t *testing.T) {
+	t.Cleanup(func() { _ = SetBackend(BackendJSON) })
+
+	if err := SetBackend("postgres"); err == nil {
+		t.Error("SetBackend(postgres) should fail")
+	}
+	if err := SetBackend(""); err != nil || Backend() != BackendJSON {
+		t.Errorf("SetBackend(\"\") = %v, backend %q; want json", err, Backend())
+	}
+}
+
+func TestSQLiteStore_SaveAndLoad(t *testing.T) {
+	useSQLite(t)
+
+	r, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.SetWorkspace(&Workspace{Name: "api", Path: "/src/api", Branch: "main", Tags: []string{"backend"},
+		Server: &ServerState{Port: 3001, Status: StatusRunning, URL: "http://localhost:3001"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.UpdateProxy(&ProxyInfo{PID: 42, HTTPPort: 80}); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err = Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ws, ok := r.GetWorkspace("api")
+	if !ok || ws.Path != "/src/api" || !ws.HasTag("backend") || ws.GetPort() != 3001 {
+		t.Errorf("workspace = %+v, want api as saved", ws)
+	}
+	if r.GetProxy().PID != 42 {
+		t.Errorf("proxy = %+v, want PID 42", r.GetProxy())
+	}
+
+	if err := r.RemoveWorkspace("api"); err != nil {
+		t.Fatal(err)
+	}
+	r, err = Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := r.GetWorkspace("api"); ok {
+		t.Error("removed workspace still stored")
+	}
+}
+
+func TestSQLiteStore_ConcurrentWriters(t *testing.T) {
+	useSQLite(t)
+
+	setup, _ := Load()
+	_ = setup.SetWorkspace(&Workspace{Name: "a", Path: "/src/a"})
+	_ = setup.SetWorkspace(&Workspace{Name: "b", Path: "/src/b"})
+
+	// Two processes load the registry, then each changes one workspace
+	first, _ := Load()
+	second, _ := Load()
+	first.Workspaces["a"].Branch = "feature-a"
+	if err := first.Save(); err != nil {
+		t.Fatal(err)
+	}
+	second.Workspaces["b"].Branch = "feature-b"
+	if err := second.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, _ := Load()
+	a, _ := r.GetWorkspace("a")
+	b, _ := r.GetWorkspace("b")
+	if a.Branch != "feature-a" || b.Branch != "feature-b" {
+		t.Errorf("branches = %q, %q; want both changes kept", a.Branch, b.Branch)
+	}
+}
+
+func TestSQLiteStore_ImportsJSON(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "registry.json")
+	activityPath := filepath.Join(dir, "activity.jsonl")
+
+	old := &Registry{path: jsonPath, Workspaces: map[string]*Workspace{"api": {Name: "api", Path: "/src/api"}}, Proxy: &ProxyInfo{}}
+	if err := old.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if err := events.AppendLog(activityPath, events.Event{Type: events.ServerCrashed, Server: "api", Time: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+
+	store := &sqliteStore{path: filepath.Join(dir, "registry.db"), jsonPath: jsonPath, activityPath: activityPath}
+	r := &Registry{path: store.path, store: store}
+	if err := r.load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := r.GetWorkspace("api"); !ok {
+		t.Error("workspace not imported from registry.json")
+	}
+
+	// Imported once: later changes to the JSON registry are ignored
+	if err := os.Remove(jsonPath); err != nil {
+		t.Fatal(err)
+	}
+	r = &Registry{path: store.path, store: &sqliteStore{path: store.path, jsonPath: jsonPath, activityPath: activityPath}}
+	if err := r.load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := r.GetWorkspace("api"); !ok {
+		t.Error("workspace lost after the JSON registry was removed")
+	}
+}
+
+func TestQueryEvents(t *testing.T) {
+	useSQLite(t)
+
+	now := time.Now()
+	for _, e := range []events.Event{
+		{Type: events.ServerCrashed, Server: "api", Time: now.Add(-10 * 24 * time.Hour)},
+		{Type: events.ServerStarted, Server: "api", Time: now.Add(-2 * time.Hour)},
+		{Type: events.ServerCrashed, Server: "api", Time: now.Add(-time.Hour)},
+		{Type: events.ServerCrashed, Server: "web", Time: now},
+	} {
+		if err := RecordEvent(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	crashes, err := QueryEvents(context.Background(), events.Query{Type: events.ServerCrashed, Since: now.Add(-7 * 24 * time.Hour)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(crashes) != 2 || crashes[0].Server != "api" || crashes[1].Server != "web" {
+		t.Errorf("crashes this week = %+v, want api then web", crashes)
+	}
+
+	latest, err := QueryEvents(context.Background(), events.Query{Server: "api", Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(latest) != 1 || latest[0].Type != events.ServerCrashed || !latest[0].Time.Equal(now.Add(-time.Hour)) {
+		t.Errorf("latest api event = %+v", latest)
+	}
+}
+
+func TestQueryUsage(t *testing.T) {
+	useSQLite(t)
+
+	now := time.Now()
+	for _, r := range []stats.Record{
+		{Kind: stats.KindCommand, Time: now.Add(-10 * 24 * time.Hour), Name: "ls"},
+		{Kind: stats.KindAgent, Time: now.Add(-time.Hour), Name: "claude", Worktree: "api", PID: 42, Started: now.Add(-2 * time.Hour)},
+		{Kind: stats.KindCommand, Time: now, Name: "start", Dir: "/src/api"},
+	} {
+		if err := RecordUsage(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	records, err := QueryUsage(context.Background(), now.Add(-7*24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].Worktree != "api" || records[1].Name != "start" {
+		t.Errorf("usage this week = %+v, want the agent then start", records)
+	}
+}
@@ -0,0 +1,140 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+
+	"github.com/iheanyi/grove/internal/config"
+)
+
+// Registry storage backends (registry_backend in config.yaml)
+const (
+	BackendJSON   = "json"
+	BackendSQLite = "sqlite"
+)
+
+var (
+	backendMu sync.RWMutex
+	backend   = BackendJSON
+)
+
+// SetBackend sets the backend registries created by New and Load are
+// stored with. An empty name is the JSON backend.
+func SetBackend(name string) error {
+	switch name {
+	case "":
+		name = BackendJSON
+	case BackendJSON, BackendSQLite:
+	default:
+		return fmt.Errorf("unknown registry_backend %q (use json or sqlite)", name)
+	}
+
+	backendMu.Lock()
+	defer backendMu.Unlock()
+	backend = name
+	return nil
+}
+
+// Backend returns the backend registries are stored with
+func Backend() string {
+	backendMu.RLock()
+	defer backendMu.RUnlock()
+	return backend
+}
+
+// Files returns the files the configured backend stores registries in,
+// which change when a registry is saved
+func Files() []string {
+	if Backend() == BackendSQLite {
+		// Writes land in the write-ahead log first
+		return []string{config.RegistryDBPath(), config.RegistryDBPath() + "-wal"}
+	}
+	return []string{config.RegistryPath()}
+}
+
+// Store reads and writes a registry's state. The caller holds the
+// registry's lock: exclusively for Load, shared for Save.
+type Store interface {
+	Load(ctx context.Context, r *Registry) error
+	Save(ctx context.Context, r *Registry) error
+}
+
+// storage returns the registry's store: the JSON file at its path unless
+// another store was set
+func (r *Registry) storage() Store {
+	if r.store != nil {
+		return r.store
+	}
+	return &jsonStore{path: r.path}
+}
+
+// jsonStore keeps the whole registry in one JSON file, locked with flock
+// for concurrent process safety
+type jsonStore struct {
+	path string
+}
+
+func (s *jsonStore) Load(ctx context.Context, r *Registry) error {
+	lockFile, err := os.OpenFile(s.path+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		// If we can't create the lock file, proceed without locking
+		// (directory may not exist yet on first run)
+	} else {
+		defer lockFile.Close()
+		if err := flock(ctx, lockFile, syscall.LOCK_SH); err == nil {
+			defer syscall.Flock(int(lockFile.Fd()), syscall.LOCK_UN) //nolint:errcheck
+		} else if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// No registry file, start fresh
+			return nil
+		}
+		return fmt.Errorf("failed to read registry: %w", err)
+	}
+
+	if err := json.Unmarshal(data, r); err != nil {
+		return fmt.Errorf("failed to parse registry: %w", err)
+	}
+	return nil
+}
+
+func (s *jsonStore) Save(ctx context.Context, r *Registry) error {
+	// Ensure directory exists
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create registry directory: %w", err)
+	}
+
+	// Sync workspaces back to legacy maps for backward compatibility
+	r.syncToLegacy()
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal registry: %w", err)
+	}
+
+	lockFile, err := os.OpenFile(s.path+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create lock file: %w", err)
+	}
+	defer lockFile.Close()
+
+	if err := flock(ctx, lockFile, syscall.LOCK_EX); err != nil {
+		return fmt.Errorf("failed to acquire file lock: %w", err)
+	}
+	defer syscall.Flock(int(lockFile.Fd()), syscall.LOCK_UN) //nolint:errcheck
+
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write registry: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+// Package resume notices the machine waking from sleep. Go's monotonic
+// clock stops while the machine sleeps and the wall clock doesn't, so the
+// wall clock jumping ahead of it between two ticks means the machine slept.
+package resume
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// Interval is how often the clocks are compared
+	Interval = 5 * time.Second
+
+	// Threshold is how far the wall clock must jump ahead to count as
+	// sleep rather than clock adjustment
+	Threshold = 30 * time.Second
+)
+
+// Watch calls onResume with how long the machine slept each time it wakes,
+// until ctx is done
+func Watch(ctx context.Context, onResume func(slept time.Duration)) {
+	ticker := time.NewTicker(Interval)
+	defer ticker.Stop()
+
+	prev := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		now := time.Now()
+		if slept := sleptBetween(prev.Round(0), now.Round(0), now.Sub(prev)); slept >= Threshold {
+			onResume(slept)
+		}
+		prev = now
+	}
+}
+
+// sleptBetween returns how much longer the wall clock advanced between two
+// readings than the monotonic elapsed time between them
+func sleptBetween(prevWall, wall time.Time, elapsed time.Duration) time.Duration {
+	return wall.Sub(prevWall) - elapsed
+}
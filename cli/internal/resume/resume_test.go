@@ -0,0 +1,23 @@
+package resume
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSleptBetween(t *testing.T) {
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	// Awake: both clocks moved 5s
+	if slept := sleptBetween(start, start.Add(5*time.Second), 5*time.Second); slept != 0 {
+		t.Errorf("awake: slept = %s, want 0", slept)
+	}
+	// Asleep for an hour between two 5s ticks
+	if slept := sleptBetween(start, start.Add(time.Hour+5*time.Second), 5*time.Second); slept != time.Hour {
+		t.Errorf("asleep: slept = %s, want 1h", slept)
+	}
+	// A wall clock set back isn't sleep
+	if slept := sleptBetween(start, start.Add(-time.Minute), 5*time.Second); slept >= Threshold {
+		t.Errorf("clock set back: slept = %s, want below the threshold", slept)
+	}
+}
@@ -0,0 +1,110 @@
+package screenshot
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg" // Screenshots taken before they were PNG
+	"image/png"
+	"os"
+)
+
+// channelTolerance is how far apart, out of 255, a pixel's channels can be
+// before it counts as changed, so rendering noise doesn't count
+const channelTolerance = 8
+
+// DiffResult is how much two screenshots differ
+type DiffResult struct {
+	// Changed is the number of pixels that differ
+	Changed int
+	// Total is the number of pixels compared: the larger width by the
+	// larger height, so a page that grew counts its new area as changed
+	Total int
+}
+
+// Percent returns the share of pixels that changed
+func (r DiffResult) Percent() float64 {
+	if r.Total == 0 {
+		return 0
+	}
+	return float64(r.Changed) / float64(r.Total) * 100
+}
+
+// Diff compares two images pixel by pixel. The diff image shows a faded
+// grayscale copy of a with changed pixels in red.
+func Diff(a, b image.Image) (*image.NRGBA, DiffResult) {
+	ab, bb := a.Bounds(), b.Bounds()
+	w := max(ab.Dx(), bb.Dx())
+	h := max(ab.Dy(), bb.Dy())
+	out := image.NewNRGBA(image.Rect(0, 0, w, h))
+	result := DiffResult{Total: w * h}
+
+	red := color.NRGBA{R: 255, A: 255}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			pa := image.Pt(ab.Min.X+x, ab.Min.Y+y)
+			pb := image.Pt(bb.Min.X+x, bb.Min.Y+y)
+			inA, inB := pa.In(ab), pb.In(bb)
+			if !inA || !inB || !samePixel(a.At(pa.X, pa.Y), b.At(pb.X, pb.Y)) {
+				result.Changed++
+				out.SetNRGBA(x, y, red)
+				continue
+			}
+			// Faded toward white, so the red stands out
+			gray := color.GrayModel.Convert(a.At(pa.X, pa.Y)).(color.Gray).Y
+			faded := 255 - (255-gray)/4
+			out.SetNRGBA(x, y, color.NRGBA{R: faded, G: faded, B: faded, A: 255})
+		}
+	}
+	return out, result
+}
+
+func samePixel(a, b color.Color) bool {
+	ar, ag, ab, aa := a.RGBA()
+	br, bg, bb, ba := b.RGBA()
+	for _, d := range [][2]uint32{{ar, br}, {ag, bg}, {ab, bb}, {aa, ba}} {
+		// RGBA returns 16-bit channels
+		diff := int(d[0]>>8) - int(d[1]>>8)
+		if diff > channelTolerance || diff < -channelTolerance {
+			return false
+		}
+	}
+	return true
+}
+
+// DiffFiles compares two screenshots and writes their diff image to out
+func DiffFiles(aFile, bFile, out string) (DiffResult, error) {
+	a, err := readImage(aFile)
+	if err != nil {
+		return DiffResult{}, err
+	}
+	b, err := readImage(bFile)
+	if err != nil {
+		return DiffResult{}, err
+	}
+
+	diff, result := Diff(a, b)
+	f, err := os.Create(out)
+	if err != nil {
+		return DiffResult{}, fmt.Errorf("failed to write diff: %w", err)
+	}
+	if err := png.Encode(f, diff); err != nil {
+		f.Close()
+		return DiffResult{}, fmt.Errorf("failed to write diff: %w", err)
+	}
+	return result, f.Close()
+}
+
+// readImage reads a screenshot
+func readImage(file string) (image.Image, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	return img, nil
+}
@@ -0,0 +1,92 @@
+package screenshot
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func solid(w, h int, c color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestDiff(t *testing.T) {
+	white := color.NRGBA{255, 255, 255, 255}
+
+	a := solid(10, 10, white)
+	b := solid(10, 10, white)
+	// Within tolerance
+	b.SetNRGBA(0, 0, color.NRGBA{250, 250, 250, 255})
+	// Changed
+	b.SetNRGBA(5, 5, color.NRGBA{0, 0, 0, 255})
+	b.SetNRGBA(6, 5, color.NRGBA{255, 0, 0, 255})
+
+	diff, result := Diff(a, b)
+	if result.Changed != 2 || result.Total != 100 {
+		t.Errorf("Diff() = %+v, want 2 of 100 changed", result)
+	}
+	if result.Percent() != 2 {
+		t.Errorf("Percent() = %v, want 2", result.Percent())
+	}
+	if got := diff.NRGBAAt(5, 5); got != (color.NRGBA{R: 255, A: 255}) {
+		t.Errorf("changed pixel = %v, want red", got)
+	}
+	if got := diff.NRGBAAt(0, 0); got.R != got.G {
+		t.Errorf("unchanged pixel = %v, want gray", got)
+	}
+}
+
+func TestDiff_DifferentSizes(t *testing.T) {
+	white := color.NRGBA{255, 255, 255, 255}
+	// b's page is 5 pixels taller
+	diff, result := Diff(solid(10, 10, white), solid(10, 15, white))
+	if result.Changed != 50 || result.Total != 150 {
+		t.Errorf("Diff() = %+v, want 50 of 150 changed", result)
+	}
+	if diff.Bounds().Dy() != 15 {
+		t.Errorf("diff height = %d, want 15", diff.Bounds().Dy())
+	}
+}
+
+func TestDiffFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, img image.Image) string {
+		file := filepath.Join(dir, name)
+		f, err := os.Create(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if err := png.Encode(f, img); err != nil {
+			t.Fatal(err)
+		}
+		return file
+	}
+	a := write("a.png", solid(4, 4, color.NRGBA{0, 0, 0, 255}))
+	b := write("b.png", solid(4, 4, color.NRGBA{0, 0, 0, 255}))
+
+	out := filepath.Join(dir, "diff.png")
+	result, err := DiffFiles(a, b, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Changed != 0 || result.Total != 16 {
+		t.Errorf("DiffFiles() = %+v, want 0 of 16 changed", result)
+	}
+	if _, err := os.Stat(out); err != nil {
+		t.Errorf("diff image not written: %v", err)
+	}
+
+	if _, err := DiffFiles(a, filepath.Join(dir, "missing.png"), out); err == nil {
+		t.Error("DiffFiles() with a missing screenshot should fail")
+	}
+}
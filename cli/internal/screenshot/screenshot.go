@@ -0,0 +1,95 @@
+// Package screenshot captures PNG screenshots of a worktree's pages with
+// headless Chrome, so a review queue can show what each branch looks like.
+package screenshot
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/chromedp/chromedp"
+)
+
+// Width and Height are the browser window size screenshots are taken at
+const (
+	Width  = 1280
+	Height = 800
+)
+
+// DefaultRoutes are captured when a project configures none
+var DefaultRoutes = []string{"/"}
+
+var unsafeChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// FileName returns the file a route's screenshot is saved as, e.g.
+// "admin-users.png" for /admin/users and "index.png" for /
+func FileName(route string) string {
+	name := strings.Trim(unsafeChars.ReplaceAllString(route, "-"), "-")
+	if name == "" {
+		name = "index"
+	}
+	return name + ".png"
+}
+
+// Dir returns the directory holding a worktree's screenshots
+func Dir(root, worktree string) string {
+	return filepath.Join(root, worktree)
+}
+
+// Capture opens baseURL+route for each route in one headless Chrome and
+// saves full-page screenshots in dir, returning the files written. A route
+// that fails doesn't stop the others; the first error is returned with the
+// files that were written.
+func Capture(ctx context.Context, baseURL, dir string, routes []string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create screenshot directory: %w", err)
+	}
+
+	opts := append(chromedp.DefaultExecAllocatorOptions[:],
+		chromedp.WindowSize(Width, Height),
+		chromedp.Flag("hide-scrollbars", true),
+		// Local servers often use grove's self-signed certificates
+		chromedp.IgnoreCertErrors,
+	)
+	allocCtx, cancelAlloc := chromedp.NewExecAllocator(ctx, opts...)
+	defer cancelAlloc()
+	browserCtx, cancelBrowser := chromedp.NewContext(allocCtx)
+	defer cancelBrowser()
+
+	var files []string
+	var firstErr error
+	for _, route := range routes {
+		if !strings.HasPrefix(route, "/") {
+			route = "/" + route
+		}
+		var png []byte
+		err := chromedp.Run(browserCtx,
+			chromedp.Navigate(strings.TrimRight(baseURL, "/")+route),
+			// Quality 100 is PNG; lower is JPEG, whose noise would show up
+			// in pixel diffs
+			chromedp.FullScreenshot(&png, 100),
+		)
+		if err == nil {
+			file := filepath.Join(dir, FileName(route))
+			if err = os.WriteFile(file, png, 0644); err == nil {
+				files = append(files, file)
+				continue
+			}
+		}
+		if firstErr == nil {
+			firstErr = fmt.Errorf("failed to capture %s: %w", route, err)
+		}
+	}
+	return files, firstErr
+}
+
+// List returns the screenshots saved for a worktree, sorted by name
+func List(root, worktree string) []string {
+	matches, _ := filepath.Glob(filepath.Join(Dir(root, worktree), "*.png"))
+	sort.Strings(matches)
+	return matches
+}
@@ -0,0 +1,45 @@
+package screenshot
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFileName(t *testing.T) {
+	tests := map[string]string{
+		"/":              "index.png",
+		"":               "index.png",
+		"/admin/users":   "admin-users.png",
+		"/search?q=shoe": "search-q-shoe.png",
+		"login":          "login.png",
+		"/../../etc":     "etc.png",
+	}
+	for route, want := range tests {
+		if got := FileName(route); got != want {
+			t.Errorf("FileName(%q) = %q, want %q", route, got, want)
+		}
+	}
+}
+
+func TestList(t *testing.T) {
+	root := t.TempDir()
+	dir := Dir(root, "feature-auth")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"login.png", "index.png", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{filepath.Join(dir, "index.png"), filepath.Join(dir, "login.png")}
+	if got := List(root, "feature-auth"); !reflect.DeepEqual(got, want) {
+		t.Errorf("List() = %v, want %v", got, want)
+	}
+	if got := List(root, "other"); len(got) != 0 {
+		t.Errorf("List() of a worktree without screenshots = %v", got)
+	}
+}
@@ -0,0 +1,218 @@
+// Package service installs grove's long-running commands as login
+// services: launchd agents on macOS and systemd user units on Linux.
+package service
+
+import (
+	"fmt"
+	"html"
+	"path/filepath"
+	"strings"
+)
+
+// Service is a grove command that runs for the whole login session
+type Service struct {
+	Name        string
+	Description string
+	// Args are the arguments to grove; the command must stay in the
+	// foreground so launchd and systemd can supervise it
+	Args []string
+}
+
+var (
+	// Proxy is the reverse proxy for subdomain mode
+	Proxy = Service{Name: "proxy", Description: "grove reverse proxy", Args: []string{"proxy", "start", "--foreground"}}
+	// API is the local API used by the menubar app
+	API = Service{Name: "api", Description: "grove local API", Args: []string{"api"}}
+)
+
+// All returns every service grove can install
+func All() []Service {
+	return []Service{Proxy, API}
+}
+
+// Find returns the service with the given name
+func Find(name string) (Service, bool) {
+	for _, s := range All() {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Service{}, false
+}
+
+// Label returns the launchd label or systemd unit name of s
+func Label(goos string, s Service) string {
+	if goos == "darwin" {
+		return "dev.grove." + s.Name
+	}
+	return "grove-" + s.Name + ".service"
+}
+
+// UnitPath returns where the launchd plist or systemd unit of s lives
+func UnitPath(goos, home string, s Service) (string, error) {
+	switch goos {
+	case "darwin":
+		return filepath.Join(home, "Library", "LaunchAgents", Label(goos, s)+".plist"), nil
+	case "linux":
+		return filepath.Join(home, ".config", "systemd", "user", Label(goos, s)), nil
+	}
+	return "", fmt.Errorf("services are not supported on %s", goos)
+}
+
+// Options are what a unit needs beyond the service itself
+type Options struct {
+	// Executable is the grove binary to run
+	Executable string
+	// LogFile receives the service's output
+	LogFile string
+	// Path is the PATH the service runs with, so it finds caddy and
+	// friends; login services otherwise get a minimal one
+	Path string
+	// Env are more variables the service runs with, as NAME=value: the
+	// GROVE_HOME or GROVE_CONTEXT grove was installed from
+	Env []string
+}
+
+// Unit returns the launchd plist or systemd unit running s at login and
+// restarting it if it exits
+func Unit(goos string, s Service, opts Options) (string, error) {
+	switch goos {
+	case "darwin":
+		return launchdPlist(s, opts), nil
+	case "linux":
+		return systemdUnit(s, opts)
+	}
+	return "", fmt.Errorf("services are not supported on %s", goos)
+}
+
+func launchdPlist(s Service, opts Options) string {
+	var args strings.Builder
+	for _, arg := range append([]string{opts.Executable}, s.Args...) {
+		fmt.Fprintf(&args, "\t\t<string>%s</string>\n", html.EscapeString(arg))
+	}
+	var env strings.Builder
+	for _, kv := range opts.Env {
+		name, value, _ := strings.Cut(kv, "=")
+		fmt.Fprintf(&env, "\t\t<key>%s</key>\n\t\t<string>%s</string>\n", html.EscapeString(name), html.EscapeString(value))
+	}
+
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<!-- Managed by grove (grove service install) -->
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>%s</string>
+	<key>ProgramArguments</key>
+	<array>
+%s	</array>
+	<key>EnvironmentVariables</key>
+	<dict>
+		<key>PATH</key>
+		<string>%s</string>
+%s	</dict>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<true/>
+	<key>StandardOutPath</key>
+	<string>%s</string>
+	<key>StandardErrorPath</key>
+	<string>%s</string>
+</dict>
+</plist>
+`, Label("darwin", s), args.String(), html.EscapeString(opts.Path), env.String(),
+		html.EscapeString(opts.LogFile), html.EscapeString(opts.LogFile))
+}
+
+func systemdUnit(s Service, opts Options) (string, error) {
+	logFile, err := systemdPath(opts.LogFile)
+	if err != nil {
+		return "", err
+	}
+
+	args := make([]string, 0, len(s.Args)+1)
+	for _, arg := range append([]string{opts.Executable}, s.Args...) {
+		args = append(args, systemdQuote(arg))
+	}
+
+	env := systemdQuote("PATH=" + opts.Path)
+	for _, kv := range opts.Env {
+		env += " " + systemdQuote(kv)
+	}
+
+	return fmt.Sprintf(`# Managed by grove (grove service install)
+[Unit]
+Description=%s
+
+[Service]
+ExecStart=%s
+Environment=%s
+Restart=on-failure
+RestartSec=2
+StandardOutput=append:%s
+StandardError=append:%s
+
+[Install]
+WantedBy=default.target
+`, s.Description, strings.Join(args, " "), env, logFile, logFile), nil
+}
+
+// systemdPath escapes path for StandardOutput=append:. systemd expands
+// specifiers there but doesn't unquote, so the path goes in as is, spaces
+// and all, with only % doubled; whitespace at either end or a line break
+// can't be written at all.
+func systemdPath(path string) (string, error) {
+	if strings.TrimSpace(path) != path || strings.ContainsAny(path, "\n\r") || strings.HasSuffix(path, `\`) {
+		return "", fmt.Errorf("log file %q can't be written in a systemd unit", path)
+	}
+	return strings.ReplaceAll(path, "%", "%%"), nil
+}
+
+// systemdQuote quotes arg for ExecStart= and Environment= if needed
+func systemdQuote(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t\"'\\$%") {
+		return arg
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `$$`, `%`, `%%`)
+	return `"` + r.Replace(arg) + `"`
+}
+
+// InstallCommands returns the commands that load the installed unit at
+// path and start it now
+func InstallCommands(goos string, uid int, s Service, path string) [][]string {
+	switch goos {
+	case "darwin":
+		return [][]string{{"launchctl", "bootstrap", fmt.Sprintf("gui/%d", uid), path}}
+	case "linux":
+		return [][]string{
+			{"systemctl", "--user", "daemon-reload"},
+			{"systemctl", "--user", "enable", "--now", Label(goos, s)},
+		}
+	}
+	return nil
+}
+
+// UninstallCommands returns the commands that stop s and unload its unit,
+// before the unit file is removed
+func UninstallCommands(goos string, uid int, s Service) [][]string {
+	switch goos {
+	case "darwin":
+		return [][]string{{"launchctl", "bootout", fmt.Sprintf("gui/%d/%s", uid, Label(goos, s))}}
+	case "linux":
+		return [][]string{{"systemctl", "--user", "disable", "--now", Label(goos, s)}}
+	}
+	return nil
+}
+
+// StatusCommand returns a command that succeeds while s is loaded (macOS)
+// or active (Linux)
+func StatusCommand(goos string, uid int, s Service) []string {
+	switch goos {
+	case "darwin":
+		return []string{"launchctl", "print", fmt.Sprintf("gui/%d/%s", uid, Label(goos, s))}
+	case "linux":
+		return []string{"systemctl", "--user", "is-active", "--quiet", Label(goos, s)}
+	}
+	return nil
+}
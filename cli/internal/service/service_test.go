@@ -0,0 +1,98 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+var testOptions = Options{
+	Executable: "/opt/homebrew/bin/grove",
+	LogFile:    "/home/me/.config/grove/logs/service-proxy.log",
+	Path:       "/opt/homebrew/bin:/usr/bin:/bin",
+}
+
+func TestLaunchdPlist(t *testing.T) {
+	unit, err := Unit("darwin", Proxy, testOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"<string>dev.grove.proxy</string>",
+		"<string>/opt/homebrew/bin/grove</string>\n\t\t<string>proxy</string>\n\t\t<string>start</string>\n\t\t<string>--foreground</string>",
+		"<key>KeepAlive</key>\n\t<true/>",
+		"<string>/opt/homebrew/bin:/usr/bin:/bin</string>",
+	} {
+		if !strings.Contains(unit, want) {
+			t.Errorf("plist missing %q:\n%s", want, unit)
+		}
+	}
+
+	path, _ := UnitPath("darwin", "/Users/me", Proxy)
+	if path != "/Users/me/Library/LaunchAgents/dev.grove.proxy.plist" {
+		t.Errorf("UnitPath() = %q", path)
+	}
+}
+
+func TestSystemdUnit(t *testing.T) {
+	opts := testOptions
+	opts.Executable = "/home/me/my tools/grove"
+	unit, err := Unit("linux", API, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`ExecStart="/home/me/my tools/grove" api`,
+		"Environment=PATH=/opt/homebrew/bin:/usr/bin:/bin",
+		"Restart=on-failure",
+		"WantedBy=default.target",
+	} {
+		if !strings.Contains(unit, want) {
+			t.Errorf("unit missing %q:\n%s", want, unit)
+		}
+	}
+
+	// systemd takes the log path verbatim apart from specifiers
+	opts.LogFile = "/home/me/My Logs/100%/grove.log"
+	unit, err = Unit("linux", API, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "StandardOutput=append:/home/me/My Logs/100%%/grove.log\n"; !strings.Contains(unit, want) {
+		t.Errorf("unit missing %q:\n%s", want, unit)
+	}
+	opts.LogFile = "/home/me/logs \n/grove.log"
+	if _, err := Unit("linux", API, opts); err == nil {
+		t.Error("a log path with a line break should be refused")
+	}
+
+	path, _ := UnitPath("linux", "/home/me", API)
+	if path != "/home/me/.config/systemd/user/grove-api.service" {
+		t.Errorf("UnitPath() = %q", path)
+	}
+}
+
+func TestServiceHome(t *testing.T) {
+	opts := testOptions
+	opts.Env = []string{"GROVE_HOME=/home/me/clients/acme"}
+
+	unit, _ := Unit("linux", API, opts)
+	if !strings.Contains(unit, "Environment=PATH=/opt/homebrew/bin:/usr/bin:/bin GROVE_HOME=/home/me/clients/acme\n") {
+		t.Errorf("unit doesn't set GROVE_HOME:\n%s", unit)
+	}
+	plist, _ := Unit("darwin", API, opts)
+	if !strings.Contains(plist, "<key>GROVE_HOME</key>\n\t\t<string>/home/me/clients/acme</string>\n\t</dict>") {
+		t.Errorf("plist doesn't set GROVE_HOME:\n%s", plist)
+	}
+	if unit, _ := Unit("linux", API, testOptions); strings.Contains(unit, "GROVE_HOME") {
+		t.Errorf("unit sets GROVE_HOME without one:\n%s", unit)
+	}
+}
+
+func TestUnsupported(t *testing.T) {
+	if _, err := Unit("windows", Proxy, testOptions); err == nil {
+		t.Error("expected an error on windows")
+	}
+	if _, ok := Find("dns"); ok {
+		t.Error("Find() found an unknown service")
+	}
+}
@@ -0,0 +1,118 @@
+// Package smoke runs the HTTP checks of a project's .grove.yaml against a
+// running server, so a change can be validated before review.
+package smoke
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/iheanyi/grove/internal/project"
+)
+
+// maxBody is how much of a response body is searched for Contains
+const maxBody = 1 << 20
+
+// Result is the outcome of one check
+type Result struct {
+	Name       string `json:"name"`
+	Method     string `json:"method"`
+	Path       string `json:"path"`
+	Status     int    `json:"status,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+	Passed     bool   `json:"passed"`
+	// Error says why a failed check failed
+	Error string `json:"error,omitempty"`
+}
+
+// DefaultChecks are run when a project defines none: the health check
+// path, or / if there's none, must respond without an error status
+func DefaultChecks(cfg *project.Config) []project.SmokeCheck {
+	path := "/"
+	if cfg != nil && cfg.HealthCheck.Path != "" {
+		path = cfg.HealthCheck.Path
+	}
+	return []project.SmokeCheck{{Path: path}}
+}
+
+// Run runs checks against baseURL in order and returns their results
+func Run(ctx context.Context, client *http.Client, baseURL string, checks []project.SmokeCheck) []Result {
+	results := make([]Result, 0, len(checks))
+	for _, check := range checks {
+		results = append(results, runCheck(ctx, client, baseURL, check))
+	}
+	return results
+}
+
+// Passed reports whether every result passed
+func Passed(results []Result) bool {
+	for _, r := range results {
+		if !r.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+func runCheck(ctx context.Context, client *http.Client, baseURL string, check project.SmokeCheck) Result {
+	method := strings.ToUpper(check.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+	path := check.Path
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	result := Result{Name: check.Name, Method: method, Path: path}
+	if result.Name == "" {
+		result.Name = method + " " + path
+	}
+
+	var body io.Reader
+	if check.Body != "" {
+		body = strings.NewReader(check.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(baseURL, "/")+path, body)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	for k, v := range check.Headers {
+		req.Header.Set(k, v)
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		result.DurationMs = time.Since(start).Milliseconds()
+		result.Error = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBody))
+	result.DurationMs = time.Since(start).Milliseconds()
+	result.Status = resp.StatusCode
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to read response: %v", err)
+		return result
+	}
+
+	switch {
+	case check.Status != 0 && resp.StatusCode != check.Status:
+		result.Error = fmt.Sprintf("status %d, want %d", resp.StatusCode, check.Status)
+	case check.Status == 0 && resp.StatusCode >= 400:
+		result.Error = fmt.Sprintf("status %d", resp.StatusCode)
+	default:
+		for _, want := range check.Contains {
+			if !strings.Contains(string(data), want) {
+				result.Error = fmt.Sprintf("body doesn't contain %q", want)
+				break
+			}
+		}
+	}
+	result.Passed = result.Error == ""
+	return result
+}
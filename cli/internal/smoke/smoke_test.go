@@ -0,0 +1,68 @@
+package smoke
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/iheanyi/grove/internal/project"
+)
+
+func TestRun(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "<h1>Welcome</h1>")
+	})
+	mux.HandleFunc("POST /api/echo", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		io.Copy(w, r.Body)
+	})
+	mux.HandleFunc("GET /admin", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	checks := []project.SmokeCheck{
+		{Path: "/", Contains: []string{"Welcome"}},
+		{Name: "echo", Method: "post", Path: "api/echo", Body: "hello", Status: 201, Contains: []string{"hello"}},
+		{Path: "/admin"},
+		{Path: "/", Contains: []string{"Goodbye"}},
+		{Path: "/admin", Status: 403},
+	}
+	results := Run(context.Background(), ts.Client(), ts.URL, checks)
+
+	want := []struct {
+		name   string
+		passed bool
+		err    string
+	}{
+		{"GET /", true, ""},
+		{"echo", true, ""},
+		{"GET /admin", false, "status 403"},
+		{"GET /", false, `body doesn't contain "Goodbye"`},
+		{"GET /admin", true, ""},
+	}
+	for i, w := range want {
+		r := results[i]
+		if r.Name != w.name || r.Passed != w.passed || r.Error != w.err {
+			t.Errorf("result %d = %+v, want name %q passed %v error %q", i, r, w.name, w.passed, w.err)
+		}
+	}
+	if Passed(results) {
+		t.Error("Passed() = true with failing checks")
+	}
+}
+
+func TestRun_Unreachable(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	url := ts.URL
+	ts.Close()
+
+	results := Run(context.Background(), http.DefaultClient, url, DefaultChecks(&project.Config{HealthCheck: project.HealthCheckConfig{Path: "/up"}}))
+	if len(results) != 1 || results[0].Path != "/up" || results[0].Passed || results[0].Error == "" {
+		t.Errorf("results = %+v, want a failed /up check", results)
+	}
+}
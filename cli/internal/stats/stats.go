@@ -0,0 +1,300 @@
+// Package stats keeps usage records that never leave the machine: the grove
+// commands run and how long agents worked in each worktree. Together with
+// the activity timeline's server events they are summarized by 'grove
+// stats'.
+package stats
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iheanyi/grove/internal/events"
+)
+
+// Kind is the kind of a usage record
+type Kind string
+
+const (
+	// KindCommand is a grove command run
+	KindCommand Kind = "command"
+	// KindAgent is an agent seen working in a worktree. An agent session
+	// is recorded repeatedly while it runs; its latest record tells how
+	// long it ran.
+	KindAgent Kind = "agent"
+)
+
+// Record is a usage record
+type Record struct {
+	Kind Kind      `json:"kind"`
+	Time time.Time `json:"time"`
+	// Name is the command run (e.g. "start" or "agent launch"), or the
+	// agent's type (e.g. "claude")
+	Name string `json:"name"`
+	// Dir is the directory a command ran in
+	Dir string `json:"dir,omitempty"`
+	// Worktree, PID and Started identify an agent session
+	Worktree string    `json:"worktree,omitempty"`
+	PID      int       `json:"pid,omitempty"`
+	Started  time.Time `json:"started,omitzero"`
+}
+
+// fileMu serializes appends to usage files within the process
+var fileMu sync.Mutex
+
+// AppendFile appends a record to the JSON lines file at path
+func AppendFile(path string, r Record) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	fileMu.Lock()
+	defer fileMu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// ReadFile returns the records of the file at path at or after since,
+// oldest first. A missing file has no records.
+func ReadFile(path string, since time.Time) ([]Record, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil || r.Time.Before(since) {
+			continue
+		}
+		records = append(records, r)
+	}
+	return records, scanner.Err()
+}
+
+// Input is what a summary is made of
+type Input struct {
+	Since, Now time.Time
+	// Records are the usage records since Since
+	Records []Record
+	// Events are the activity timeline's events since Since
+	Events []events.Event
+	// Running are the servers running now
+	Running map[string]bool
+	// Worktrees maps worktree paths to names, to tell where commands ran
+	Worktrees map[string]string
+}
+
+// Summary is a summary of usage over a period
+type Summary struct {
+	Since    time.Time `json:"since"`
+	Commands int       `json:"commands"`
+	// TopCommands are the commands run most, most first
+	TopCommands  []Count `json:"top_commands"`
+	ServerStarts int     `json:"server_starts"`
+	// AvgServerUptimeSeconds is the average time servers ran for, counting
+	// those still running until now
+	AvgServerUptimeSeconds int64 `json:"avg_server_uptime_seconds"`
+	AgentSeconds           int64 `json:"agent_seconds"`
+	// Worktrees are the worktrees used in the period, most active first
+	Worktrees []WorktreeUsage `json:"worktrees"`
+	// Days are the period's totals per local day, oldest first
+	Days []Day `json:"days"`
+}
+
+// Count is how many times a command ran
+type Count struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// WorktreeUsage is a worktree's share of the usage
+type WorktreeUsage struct {
+	Name                string `json:"name"`
+	Commands            int    `json:"commands"`
+	ServerStarts        int    `json:"server_starts"`
+	ServerUptimeSeconds int64  `json:"server_uptime_seconds"`
+	AgentSeconds        int64  `json:"agent_seconds"`
+}
+
+// Day is a day's usage
+type Day struct {
+	Date         string `json:"date"`
+	Commands     int    `json:"commands"`
+	ServerStarts int    `json:"server_starts"`
+	AgentSeconds int64  `json:"agent_seconds"`
+}
+
+// Summarize sums up usage over a period
+func Summarize(in Input) *Summary {
+	s := &Summary{Since: in.Since, TopCommands: []Count{}, Worktrees: []WorktreeUsage{}}
+	worktrees := make(map[string]*WorktreeUsage)
+	worktree := func(name string) *WorktreeUsage {
+		if worktrees[name] == nil {
+			worktrees[name] = &WorktreeUsage{Name: name}
+		}
+		return worktrees[name]
+	}
+
+	start := in.Since.Local()
+	start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.Local)
+	for d := start; d.Before(in.Now); d = d.AddDate(0, 0, 1) {
+		s.Days = append(s.Days, Day{Date: d.Format("2006-01-02")})
+	}
+	day := func(t time.Time) *Day {
+		t = t.Local()
+		i := int(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local).Sub(start).Hours()+12) / 24
+		if i < 0 || i >= len(s.Days) {
+			return &Day{}
+		}
+		return &s.Days[i]
+	}
+
+	commands := make(map[string]int)
+	type session struct {
+		worktree   string
+		start, end time.Time
+	}
+	sessions := make(map[string]*session)
+	for _, r := range in.Records {
+		switch r.Kind {
+		case KindCommand:
+			s.Commands++
+			commands[r.Name]++
+			day(r.Time).Commands++
+			if name := worktreeAt(in.Worktrees, r.Dir); name != "" {
+				worktree(name).Commands++
+			}
+		case KindAgent:
+			key := r.Worktree + "\x00" + r.Started.String()
+			if sessions[key] == nil {
+				sessions[key] = &session{worktree: r.Worktree, start: r.Started}
+			}
+			if r.Time.After(sessions[key].end) {
+				sessions[key].end = r.Time
+			}
+		}
+	}
+	for name, n := range commands {
+		s.TopCommands = append(s.TopCommands, Count{Name: name, Count: n})
+	}
+	sort.Slice(s.TopCommands, func(i, j int) bool {
+		a, b := s.TopCommands[i], s.TopCommands[j]
+		return a.Count > b.Count || (a.Count == b.Count && a.Name < b.Name)
+	})
+
+	for _, sess := range sessions {
+		from := sess.start
+		if from.Before(in.Since) {
+			from = in.Since
+		}
+		if !sess.end.After(from) {
+			continue
+		}
+		seconds := int64(sess.end.Sub(from).Seconds())
+		s.AgentSeconds += seconds
+		worktree(sess.worktree).AgentSeconds += seconds
+		// Split the session across the days it spans
+		for t := from; t.Before(sess.end); {
+			next := t.Local()
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, time.Local)
+			if next.After(sess.end) {
+				next = sess.end
+			}
+			day(t).AgentSeconds += int64(next.Sub(t).Seconds())
+			t = next
+		}
+	}
+
+	// Pair each server's starts with its next stop. A server that was
+	// already running at Since counts from Since; one still running counts
+	// until now.
+	var uptime time.Duration
+	var runs int
+	started := make(map[string]time.Time)
+	seen := make(map[string]bool)
+	end := func(server string, at time.Time) {
+		from, ok := started[server]
+		if !ok {
+			if seen[server] {
+				return // a stop without a start in between
+			}
+			from = in.Since
+		}
+		seen[server] = true
+		delete(started, server)
+		uptime += at.Sub(from)
+		runs++
+		worktree(server).ServerUptimeSeconds += int64(at.Sub(from).Seconds())
+	}
+	for _, e := range in.Events {
+		switch e.Type {
+		case events.ServerStarted:
+			if _, ok := started[e.Server]; ok {
+				end(e.Server, e.Time)
+			}
+			started[e.Server] = e.Time
+			seen[e.Server] = true
+			s.ServerStarts++
+			day(e.Time).ServerStarts++
+			worktree(e.Server).ServerStarts++
+		case events.ServerStopped, events.ServerCrashed, events.ServerIdleStopped:
+			end(e.Server, e.Time)
+		}
+	}
+	for server := range in.Running {
+		end(server, in.Now)
+	}
+	if runs > 0 {
+		s.AvgServerUptimeSeconds = int64(uptime.Seconds()) / int64(runs)
+	}
+
+	for _, w := range worktrees {
+		s.Worktrees = append(s.Worktrees, *w)
+	}
+	sort.Slice(s.Worktrees, func(i, j int) bool {
+		a, b := s.Worktrees[i], s.Worktrees[j]
+		if a.activity() != b.activity() {
+			return a.activity() > b.activity()
+		}
+		return a.Name < b.Name
+	})
+	return s
+}
+
+// activity ranks worktrees: time spent working in them, then commands
+func (w WorktreeUsage) activity() int64 {
+	return w.AgentSeconds + w.ServerUptimeSeconds + int64(w.Commands)
+}
+
+// worktreeAt returns the name of the worktree containing dir, the deepest
+// one when worktrees are nested
+func worktreeAt(worktrees map[string]string, dir string) string {
+	var best, name string
+	for path, n := range worktrees {
+		if (dir == path || strings.HasPrefix(dir, path+string(filepath.Separator))) && len(path) > len(best) {
+			best, name = path, n
+		}
+	}
+	return name
+}
@@ -0,0 +1,99 @@
+package stats
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/iheanyi/grove/internal/events"
+)
+
+func TestAppendAndReadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.jsonl")
+	now := time.Now()
+	for _, r := range []Record{
+		{Kind: KindCommand, Time: now.Add(-48 * time.Hour), Name: "ls"},
+		{Kind: KindCommand, Time: now, Name: "start"},
+	} {
+		if err := AppendFile(path, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	records, err := ReadFile(path, now.Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Name != "start" {
+		t.Errorf("records = %+v, want only start", records)
+	}
+	if records, err := ReadFile(filepath.Join(t.TempDir(), "missing"), time.Time{}); records != nil || err != nil {
+		t.Errorf("missing file = %v, %v", records, err)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+	since := now.AddDate(0, 0, -2)
+	at := func(hours int) time.Time { return since.Add(time.Duration(hours) * time.Hour) }
+
+	s := Summarize(Input{
+		Since: since,
+		Now:   now,
+		Records: []Record{
+			{Kind: KindCommand, Time: at(1), Name: "start", Dir: "/src/feature/web"},
+			{Kind: KindCommand, Time: at(2), Name: "start", Dir: "/src/feature"},
+			{Kind: KindCommand, Time: at(30), Name: "ls", Dir: "/tmp"},
+			// A session that began before the period, seen twice
+			{Kind: KindAgent, Time: at(1), Name: "claude", Worktree: "feature", PID: 1, Started: at(-5)},
+			{Kind: KindAgent, Time: at(3), Name: "claude", Worktree: "feature", PID: 1, Started: at(-5)},
+			{Kind: KindAgent, Time: at(25), Name: "codex", Worktree: "bugfix", PID: 2, Started: at(24)},
+		},
+		Events: []events.Event{
+			// Running since before the period
+			{Type: events.ServerStopped, Server: "main", Time: at(2)},
+			{Type: events.ServerStarted, Server: "feature", Time: at(4)},
+			{Type: events.ServerCrashed, Server: "feature", Time: at(6)},
+			{Type: events.ServerStopped, Server: "feature", Time: at(7)},
+			{Type: events.ServerStarted, Server: "bugfix", Time: at(46)},
+		},
+		Running:   map[string]bool{"bugfix": true},
+		Worktrees: map[string]string{"/src/feature": "feature", "/src/feature/web": "web"},
+	})
+
+	if s.Commands != 3 || s.TopCommands[0] != (Count{Name: "start", Count: 2}) {
+		t.Errorf("commands = %d, top %v", s.Commands, s.TopCommands)
+	}
+	if s.ServerStarts != 2 {
+		t.Errorf("server starts = %d, want 2", s.ServerStarts)
+	}
+	// main 2h, feature 2h, bugfix 2h
+	if s.AvgServerUptimeSeconds != 2*3600 {
+		t.Errorf("avg uptime = %ds, want 2h", s.AvgServerUptimeSeconds)
+	}
+	if s.AgentSeconds != 4*3600 {
+		t.Errorf("agent time = %ds, want 4h", s.AgentSeconds)
+	}
+
+	want := map[string]WorktreeUsage{
+		"feature": {Name: "feature", Commands: 1, ServerStarts: 1, ServerUptimeSeconds: 2 * 3600, AgentSeconds: 3 * 3600},
+		"web":     {Name: "web", Commands: 1},
+		"main":    {Name: "main", ServerUptimeSeconds: 2 * 3600},
+		"bugfix":  {Name: "bugfix", ServerStarts: 1, ServerUptimeSeconds: 2 * 3600, AgentSeconds: 3600},
+	}
+	for _, w := range s.Worktrees {
+		if w != want[w.Name] {
+			t.Errorf("worktree %s = %+v, want %+v", w.Name, w, want[w.Name])
+		}
+	}
+	if len(s.Worktrees) != 4 || s.Worktrees[0].Name != "feature" {
+		t.Errorf("worktrees = %+v, want feature first of 4", s.Worktrees)
+	}
+
+	if len(s.Days) != 3 {
+		t.Fatalf("days = %+v, want 3", s.Days)
+	}
+	if s.Days[0].Commands != 2 || s.Days[0].AgentSeconds != 3*3600 || s.Days[1].AgentSeconds != 3600 || s.Days[2].ServerStarts != 1 {
+		t.Errorf("days = %+v", s.Days)
+	}
+}
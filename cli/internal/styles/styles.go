@@ -3,7 +3,8 @@ package styles
 
 import "github.com/charmbracelet/lipgloss"
 
-// Colors - semantic color palette for consistent theming
+// Colors - semantic color palette for consistent theming. These are the
+// dark theme's; Apply switches them to another theme's.
 var (
 	// Primary colors
 	Primary   = lipgloss.Color("#7C3AED") // Purple - brand color
@@ -60,33 +61,63 @@ const (
 // Truncation tail
 const TruncateTail = "..."
 
-// Common styles
+// Common styles, rebuilt when the theme changes (see Apply)
 var (
 	// Header styles
-	HeaderStyle = lipgloss.NewStyle().Bold(true).Foreground(Header).PaddingRight(2)
-	LinkHeader  = lipgloss.NewStyle().Bold(true).Foreground(Link)
+	HeaderStyle lipgloss.Style
+	LinkHeader  lipgloss.Style
 
 	// Text styles
-	NameStyle    = lipgloss.NewStyle().Bold(true).Foreground(Name)
-	URLStyle     = lipgloss.NewStyle().Foreground(Success)
-	StatsStyle   = lipgloss.NewStyle().Foreground(Number)
-	DimStyle     = lipgloss.NewStyle().Foreground(Dim)
-	AccentStyle  = lipgloss.NewStyle().Foreground(Accent)
-	MutedStyle   = lipgloss.NewStyle().Foreground(Muted)
-	PrimaryStyle = lipgloss.NewStyle().Foreground(Primary)
+	NameStyle    lipgloss.Style
+	URLStyle     lipgloss.Style
+	StatsStyle   lipgloss.Style
+	DimStyle     lipgloss.Style
+	AccentStyle  lipgloss.Style
+	MutedStyle   lipgloss.Style
+	PrimaryStyle lipgloss.Style
 
 	// Status styles
+	RunningStyle lipgloss.Style
+	StoppedStyle lipgloss.Style
+	ErrorStyle   lipgloss.Style
+	WarningStyle lipgloss.Style
+	SuccessStyle lipgloss.Style
+
+	// Selection styles
+	SelectedTitle lipgloss.Style
+	SelectedDesc  lipgloss.Style
+
+	// Table styles
+	CellStyle   lipgloss.Style
+	BorderStyle lipgloss.Style
+)
+
+func init() {
+	buildStyles()
+}
+
+// buildStyles builds the common styles from the current colors
+func buildStyles() {
+	HeaderStyle = lipgloss.NewStyle().Bold(true).Foreground(Header).PaddingRight(2)
+	LinkHeader = lipgloss.NewStyle().Bold(true).Foreground(Link)
+
+	NameStyle = lipgloss.NewStyle().Bold(true).Foreground(Name)
+	URLStyle = lipgloss.NewStyle().Foreground(Success)
+	StatsStyle = lipgloss.NewStyle().Foreground(Number)
+	DimStyle = lipgloss.NewStyle().Foreground(Dim)
+	AccentStyle = lipgloss.NewStyle().Foreground(Accent)
+	MutedStyle = lipgloss.NewStyle().Foreground(Muted)
+	PrimaryStyle = lipgloss.NewStyle().Foreground(Primary)
+
 	RunningStyle = lipgloss.NewStyle().Foreground(Secondary)
 	StoppedStyle = lipgloss.NewStyle().Foreground(Muted)
-	ErrorStyle   = lipgloss.NewStyle().Foreground(Error).Bold(true)
+	ErrorStyle = lipgloss.NewStyle().Foreground(Error).Bold(true)
 	WarningStyle = lipgloss.NewStyle().Foreground(Warning).Bold(true)
 	SuccessStyle = lipgloss.NewStyle().Foreground(Secondary).Bold(true)
 
-	// Selection styles
 	SelectedTitle = lipgloss.NewStyle().Foreground(Accent).Bold(true)
-	SelectedDesc  = lipgloss.NewStyle().Foreground(Muted)
+	SelectedDesc = lipgloss.NewStyle().Foreground(Muted)
 
-	// Table styles
-	CellStyle   = lipgloss.NewStyle().PaddingRight(2)
+	CellStyle = lipgloss.NewStyle().PaddingRight(2)
 	BorderStyle = lipgloss.NewStyle().Foreground(Dim)
-)
+}
@@ -0,0 +1,179 @@
+package styles
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Themes
+const (
+	ThemeDark  = "dark"
+	ThemeLight = "light"
+	// ThemeCustom starts from the dark theme; its colors come from the
+	// palette given to Apply
+	ThemeCustom = "custom"
+)
+
+// Palette maps color names (primary, error, muted, ...) to hex colors
+// ("#7C3AED") or ANSI color numbers ("240")
+type Palette map[string]string
+
+// colorVars are the palette's terminal colors. Background, surface, border
+// and text are only used by the dashboard.
+var colorVars = map[string]*lipgloss.Color{
+	"primary":      &Primary,
+	"secondary":    &Secondary,
+	"warning":      &Warning,
+	"error":        &Error,
+	"muted":        &Muted,
+	"accent":       &Accent,
+	"info":         &Info,
+	"cyan":         &Cyan,
+	"purple":       &Purple,
+	"purple_light": &PurpleLight,
+	"yellow":       &Yellow,
+	"white":        &White,
+	"dim":          &Dim,
+	"header":       &Header,
+	"link":         &Link,
+	"success":      &Success,
+	"number":       &Number,
+	"name":         &Name,
+}
+
+var darkPalette = Palette{
+	"primary":      "#7C3AED",
+	"secondary":    "#10B981",
+	"warning":      "#F59E0B",
+	"error":        "#EF4444",
+	"muted":        "#6B7280",
+	"accent":       "#A78BFA",
+	"info":         "#3B82F6",
+	"cyan":         "#06B6D4",
+	"purple":       "#8B5CF6",
+	"purple_light": "#A855F7",
+	"yellow":       "#EAB308",
+	"white":        "#FFFFFF",
+	"dim":          "240",
+	"header":       "252",
+	"link":         "12",
+	"success":      "10",
+	"number":       "11",
+	"name":         "14",
+
+	"background": "#0F172A",
+	"surface":    "#1E293B",
+	"border":     "#334155",
+	"text":       "#F1F5F9",
+}
+
+// lightPalette darkens the dark theme's colors to read on a light
+// background
+var lightPalette = Palette{
+	"primary":      "#6D28D9",
+	"secondary":    "#047857",
+	"warning":      "#B45309",
+	"error":        "#DC2626",
+	"muted":        "#4B5563",
+	"accent":       "#7C3AED",
+	"info":         "#1D4ED8",
+	"cyan":         "#0E7490",
+	"purple":       "#6D28D9",
+	"purple_light": "#7E22CE",
+	"yellow":       "#A16207",
+	"white":        "#000000",
+	"dim":          "245",
+	"header":       "236",
+	"link":         "4",
+	"success":      "2",
+	"number":       "3",
+	"name":         "6",
+
+	"background": "#F8FAFC",
+	"surface":    "#FFFFFF",
+	"border":     "#E2E8F0",
+	"text":       "#0F172A",
+}
+
+var (
+	themeMu      sync.Mutex
+	currentTheme = ThemeDark
+	current      = maps.Clone(darkPalette)
+	onChange     []func()
+)
+
+var hexColor = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// Apply switches to a theme (dark if empty), with the palette's colors
+// overriding the theme's, and rebuilds the styles built from them
+func Apply(theme string, palette map[string]string) error {
+	var base Palette
+	switch strings.ToLower(theme) {
+	case "", ThemeDark, ThemeCustom:
+		base = darkPalette
+	case ThemeLight:
+		base = lightPalette
+	default:
+		return fmt.Errorf("unknown theme %q (use dark, light or custom)", theme)
+	}
+
+	colors := maps.Clone(base)
+	for name, value := range palette {
+		if _, ok := base[name]; !ok {
+			return fmt.Errorf("unknown palette color %q (use %s)", name, strings.Join(slices.Sorted(maps.Keys(base)), ", "))
+		}
+		if !validColor(value) {
+			return fmt.Errorf("invalid palette color %s: %q (use a hex color like #7C3AED or an ANSI number 0-255)", name, value)
+		}
+		colors[name] = value
+	}
+
+	themeMu.Lock()
+	if theme == "" {
+		theme = ThemeDark
+	}
+	currentTheme = strings.ToLower(theme)
+	current = colors
+	for name, v := range colorVars {
+		*v = lipgloss.Color(colors[name])
+	}
+	buildStyles()
+	hooks := slices.Clone(onChange)
+	themeMu.Unlock()
+
+	for _, fn := range hooks {
+		fn()
+	}
+	return nil
+}
+
+// OnChange registers fn to run when the theme changes, for packages that
+// build styles from the colors ahead of time
+func OnChange(fn func()) {
+	themeMu.Lock()
+	defer themeMu.Unlock()
+	onChange = append(onChange, fn)
+}
+
+// Current returns the current theme's name and colors
+func Current() (string, Palette) {
+	themeMu.Lock()
+	defer themeMu.Unlock()
+	return currentTheme, maps.Clone(current)
+}
+
+// validColor returns true for a hex color or an ANSI color number
+func validColor(s string) bool {
+	if hexColor.MatchString(s) {
+		return true
+	}
+	n, err := strconv.Atoi(s)
+	return err == nil && n >= 0 && n <= 255
+}
@@ -0,0 +1,54 @@
+package styles
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestApply(t *testing.T) {
+	t.Cleanup(func() { Apply(ThemeDark, nil) }) //nolint:errcheck
+
+	called := 0
+	OnChange(func() { called++ })
+
+	if err := Apply(ThemeLight, map[string]string{"primary": "#0EA5E9"}); err != nil {
+		t.Fatal(err)
+	}
+	if Primary != lipgloss.Color("#0EA5E9") || Error != lipgloss.Color(lightPalette["error"]) {
+		t.Errorf("colors = %v, %v", Primary, Error)
+	}
+	if PrimaryStyle.GetForeground() != lipgloss.Color("#0EA5E9") {
+		t.Errorf("PrimaryStyle wasn't rebuilt")
+	}
+	if called != 1 {
+		t.Errorf("OnChange hooks called %d times, want 1", called)
+	}
+	if theme, palette := Current(); theme != ThemeLight || palette["background"] != lightPalette["background"] {
+		t.Errorf("Current() = %s, %v", theme, palette)
+	}
+
+	if err := Apply("", nil); err != nil {
+		t.Fatal(err)
+	}
+	if Primary != lipgloss.Color(darkPalette["primary"]) {
+		t.Errorf("empty theme didn't apply dark: %v", Primary)
+	}
+
+	for _, tc := range []struct {
+		theme   string
+		palette map[string]string
+	}{
+		{"solarized", nil},
+		{ThemeCustom, map[string]string{"primery": "#fff"}},
+		{ThemeCustom, map[string]string{"primary": "purple"}},
+		{ThemeCustom, map[string]string{"dim": "256"}},
+	} {
+		if err := Apply(tc.theme, tc.palette); err == nil {
+			t.Errorf("Apply(%q, %v) = nil, want error", tc.theme, tc.palette)
+		}
+	}
+	if Primary != lipgloss.Color(darkPalette["primary"]) {
+		t.Errorf("a failed Apply changed colors: %v", Primary)
+	}
+}
@@ -0,0 +1,169 @@
+package tasks
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Beads reads issues from a worktree's .beads directory: the issues.jsonl
+// database, or one markdown file per issue in .beads/issues/
+type Beads struct{}
+
+// beadsIssue is a line of .beads/issues.jsonl
+type beadsIssue struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+}
+
+// Name implements Provider
+func (Beads) Name() string { return "beads" }
+
+// Tasks implements Provider
+func (Beads) Tasks(path string) []*Task {
+	beadsDir := filepath.Join(path, ".beads")
+	if tasks := readBeadsJSONL(filepath.Join(beadsDir, "issues.jsonl")); tasks != nil {
+		return tasks
+	}
+	return readBeadsMarkdown(filepath.Join(beadsDir, "issues"))
+}
+
+// SetStatus implements StatusSetter
+func (Beads) SetStatus(path, id string, status Status) error {
+	beadsDir := filepath.Join(path, ".beads")
+	jsonl := filepath.Join(beadsDir, "issues.jsonl")
+	if _, err := os.Stat(jsonl); err == nil {
+		return setBeadsJSONLStatus(jsonl, id, beadsStatus(status))
+	}
+	return setBeadsMarkdownStatus(filepath.Join(beadsDir, "issues", id+".md"), beadsStatus(status))
+}
+
+// beadsStatus maps a Status to Beads' name for it
+func beadsStatus(status Status) string {
+	if status == StatusDone {
+		return "closed"
+	}
+	return string(status)
+}
+
+// setBeadsJSONLStatus rewrites an issue's lines in issues.jsonl with a new
+// status, keeping their other fields and every other line as they are
+func setBeadsJSONLStatus(path, id, status string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	lines := strings.Split(string(content), "\n")
+	for i, line := range lines {
+		var issue map[string]any
+		if err := json.Unmarshal([]byte(line), &issue); err != nil || issue["id"] != id {
+			continue
+		}
+		issue["status"] = status
+		updated, err := json.Marshal(issue)
+		if err != nil {
+			return err
+		}
+		lines[i] = string(updated)
+		found = true
+	}
+	if !found {
+		return fmt.Errorf("task not found: %s", id)
+	}
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644)
+}
+
+// setBeadsMarkdownStatus sets the status: line of an issue file, adding
+// one if it has none
+func setBeadsMarkdownStatus(path, status string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	lines := strings.Split(string(content), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "status:") {
+			lines[i] = "status: " + status
+			return os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644)
+		}
+	}
+	return os.WriteFile(path, []byte("status: "+status+"\n"+string(content)), 0644)
+}
+
+func readBeadsJSONL(path string) []*Task {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	// Later lines update earlier ones, so keep the last version of each issue
+	var tasks []*Task
+	byID := make(map[string]*Task)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var issue beadsIssue
+		if err := json.Unmarshal(scanner.Bytes(), &issue); err != nil || issue.ID == "" {
+			continue
+		}
+		task := &Task{
+			ID:     issue.ID,
+			Title:  issue.Title,
+			Status: normalizeStatus(issue.Status),
+			Source: "beads",
+		}
+		if existing, ok := byID[issue.ID]; ok {
+			*existing = *task
+			continue
+		}
+		byID[issue.ID] = task
+		tasks = append(tasks, task)
+	}
+	return tasks
+}
+
+func readBeadsMarkdown(dir string) []*Task {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var tasks []*Task
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".md") {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+
+		task := &Task{
+			ID:     strings.TrimSuffix(entry.Name(), ".md"),
+			Status: StatusOpen,
+			Source: "beads",
+		}
+		for _, line := range strings.Split(string(content), "\n") {
+			line = strings.TrimSpace(line)
+			switch {
+			case strings.HasPrefix(line, "status:"):
+				task.Status = normalizeStatus(strings.TrimSpace(strings.TrimPrefix(line, "status:")))
+			case task.Title == "" && strings.HasPrefix(line, "# "):
+				task.Title = strings.TrimPrefix(line, "# ")
+			case task.Title == "" && strings.HasPrefix(line, "title:"):
+				task.Title = strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "title:")), "\"'")
+			}
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks
+}
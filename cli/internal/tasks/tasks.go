@@ -0,0 +1,131 @@
+// Package tasks reads the tasks agents work on from the task trackers a
+// worktree uses: Tasuku (.tasuku/), Beads (.beads/) or a plain TODO.md.
+package tasks
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Status is a task's state, normalized across providers
+type Status string
+
+const (
+	StatusOpen       Status = "open"
+	StatusInProgress Status = "in_progress"
+	StatusBlocked    Status = "blocked"
+	StatusDone       Status = "done"
+)
+
+// Task is a task from one of a worktree's providers
+type Task struct {
+	ID     string `json:"id,omitempty"`
+	Title  string `json:"title"`
+	Status Status `json:"status"`
+	Source string `json:"source"` // Provider name
+}
+
+// Summary returns the title, or the ID of an untitled task
+func (t *Task) Summary() string {
+	if t.Title != "" {
+		return t.Title
+	}
+	return t.ID
+}
+
+// String returns "id - title", or whichever of the two is set
+func (t *Task) String() string {
+	if t.ID != "" && t.Title != "" {
+		return t.ID + " - " + t.Title
+	}
+	return t.Summary()
+}
+
+// Provider reads tasks from one kind of task tracker
+type Provider interface {
+	// Name identifies the provider, e.g. "tasuku"
+	Name() string
+
+	// Tasks returns a worktree's tasks, or nil if it doesn't use this tracker.
+	// Unreadable tasks are skipped.
+	Tasks(path string) []*Task
+}
+
+// StatusSetter is implemented by providers that can update a task's status
+type StatusSetter interface {
+	// SetStatus changes the status of the task with the given ID
+	SetStatus(path, id string, status Status) error
+}
+
+// Providers are checked in order; the first with an in-progress task wins
+var Providers = []Provider{Tasuku{}, Beads{}, TodoFile{}}
+
+// List returns a worktree's tasks from every provider
+func List(path string) []*Task {
+	var all []*Task
+	for _, p := range Providers {
+		all = append(all, p.Tasks(path)...)
+	}
+	return all
+}
+
+// Active returns the task in progress in a worktree, or nil
+func Active(path string) *Task {
+	for _, p := range Providers {
+		for _, t := range p.Tasks(path) {
+			if t.Status == StatusInProgress {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+// Find returns the task with the given ID (matched case-insensitively) from
+// any of a worktree's providers, or nil
+func Find(path, id string) *Task {
+	for _, t := range List(path) {
+		if strings.EqualFold(t.ID, id) {
+			return t
+		}
+	}
+	return nil
+}
+
+// SetStatus updates a task of the worktree at path in the tracker it came
+// from
+func SetStatus(path string, task *Task, status Status) error {
+	for _, p := range Providers {
+		if p.Name() != task.Source {
+			continue
+		}
+		setter, ok := p.(StatusSetter)
+		if !ok {
+			return fmt.Errorf("%s tasks can't be updated", task.Source)
+		}
+		return setter.SetStatus(path, task.ID, status)
+	}
+	return fmt.Errorf("unknown task source %q", task.Source)
+}
+
+// Summary returns the active task's summary, or "" if there's none
+func Summary(path string) string {
+	if t := Active(path); t != nil {
+		return t.Summary()
+	}
+	return ""
+}
+
+// normalizeStatus maps a tracker's status to a Status
+func normalizeStatus(s string) Status {
+	switch s {
+	case "in_progress", "in-progress", "doing", "active":
+		return StatusInProgress
+	case "blocked":
+		return StatusBlocked
+	case "done", "closed", "completed", "complete":
+		return StatusDone
+	default:
+		return StatusOpen
+	}
+}
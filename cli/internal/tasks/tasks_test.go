@@ -0,0 +1,142 @@
+package tasks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTasuku(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".tasuku", "tasks", "a.json"), `{"id": "a", "status": "ready", "description": "Open task"}`)
+	writeFile(t, filepath.Join(dir, ".tasuku", "tasks", "b.json"), `{"id": "b", "status": "in_progress", "description": "Add OAuth login"}`)
+
+	// Found from a subdirectory too
+	sub := filepath.Join(dir, "web")
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	task := Active(sub)
+	if task == nil || task.ID != "b" || task.Title != "Add OAuth login" || task.Source != "tasuku" {
+		t.Fatalf("Active() = %+v, want tasuku task b", task)
+	}
+	if got := len(List(sub)); got != 2 {
+		t.Errorf("List() returned %d tasks, want 2", got)
+	}
+}
+
+func TestBeads(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".beads", "issues.jsonl"),
+		`{"id":"bd-1","title":"Fix login","status":"open"}
+{"id":"bd-2","title":"Old","status":"closed"}
+{"id":"bd-1","title":"Fix login","status":"in_progress"}
+`)
+
+	got := Beads{}.Tasks(dir)
+	if len(got) != 2 {
+		t.Fatalf("Tasks() returned %d tasks, want 2", len(got))
+	}
+	if got[0].ID != "bd-1" || got[0].Status != StatusInProgress {
+		t.Errorf("bd-1 = %+v, want in progress (later lines win)", got[0])
+	}
+	if got[1].Status != StatusDone {
+		t.Errorf("bd-2 status = %q, want done", got[1].Status)
+	}
+}
+
+func TestBeadsMarkdown(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".beads", "issues", "bd-7.md"), "---\nstatus: in_progress\n---\n# Speed up build\n")
+
+	if got := Summary(dir); got != "Speed up build" {
+		t.Errorf("Summary() = %q, want %q", got, "Speed up build")
+	}
+}
+
+func TestTodoFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "TODO.md"), `# Todo
+
+- [x] Set up CI
+- [ ] Write docs
+* [~] Refactor parser
+- not a task
+`)
+
+	got := TodoFile{}.Tasks(dir)
+	if len(got) != 3 {
+		t.Fatalf("Tasks() returned %d tasks, want 3", len(got))
+	}
+	want := []Status{StatusDone, StatusOpen, StatusInProgress}
+	for i, task := range got {
+		if task.Status != want[i] {
+			t.Errorf("task %d (%s) status = %q, want %q", i, task.Title, task.Status, want[i])
+		}
+	}
+	if task := Active(dir); task == nil || task.ID != "TODO.md:5" || task.Title != "Refactor parser" {
+		t.Errorf("Active() = %+v, want TODO.md:5 Refactor parser", task)
+	}
+}
+
+func TestActivePrefersTasuku(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "TODO.md"), "- [/] From TODO\n")
+	writeFile(t, filepath.Join(dir, ".tasuku", "tasks", "a.json"), `{"id": "a", "status": "in_progress", "description": "From Tasuku"}`)
+
+	if got := Summary(dir); got != "From Tasuku" {
+		t.Errorf("Summary() = %q, want %q", got, "From Tasuku")
+	}
+}
+
+func TestNoTasks(t *testing.T) {
+	dir := t.TempDir()
+	if task := Active(dir); task != nil {
+		t.Errorf("Active() = %+v, want nil", task)
+	}
+}
+
+func TestSetStatus(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".tasuku", "tasks", "tk-42.json"), `{"id": "TK-42", "status": "ready", "description": "Add OAuth login"}`)
+	writeFile(t, filepath.Join(dir, ".beads", "issues.jsonl"), `{"id":"bd-1","title":"Fix login","status":"open","priority":2}`+"\n")
+	writeFile(t, filepath.Join(dir, "TODO.md"), "# Todo\n\n- [ ] Write docs\n")
+
+	for _, id := range []string{"tk-42", "bd-1", "TODO.md:3"} {
+		task := Find(dir, id)
+		if task == nil {
+			t.Fatalf("Find(%q) = nil", id)
+		}
+		if err := SetStatus(dir, task, StatusInProgress); err != nil {
+			t.Fatalf("SetStatus(%q) failed: %v", id, err)
+		}
+		if got := Find(dir, id); got.Status != StatusInProgress {
+			t.Errorf("%s status = %q after SetStatus, want in progress", id, got.Status)
+		}
+	}
+
+	// Other fields of a Beads issue are kept
+	content, err := os.ReadFile(filepath.Join(dir, ".beads", "issues.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), `"priority":2`) {
+		t.Errorf("issues.jsonl lost fields: %s", content)
+	}
+
+	if Find(dir, "missing") != nil {
+		t.Error("Find() of an unknown ID should return nil")
+	}
+}
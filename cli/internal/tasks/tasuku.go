@@ -1,4 +1,4 @@
-package discovery
+package tasks
 
 import (
 	"encoding/json"
@@ -40,37 +40,52 @@ func FindTasukuDir(startPath string) string {
 	}
 }
 
-// GetActiveTask finds the current in_progress task for a worktree.
-// Returns the task ID and description, or empty strings if none found.
-func GetActiveTask(worktreePath string) (taskID string, description string) {
-	tasukuDir := FindTasukuDir(worktreePath)
+// Tasuku reads tasks from .tasuku/tasks/*.json, in the worktree or a parent
+// directory
+type Tasuku struct{}
+
+// Name implements Provider
+func (Tasuku) Name() string { return "tasuku" }
+
+// Tasks implements Provider
+func (Tasuku) Tasks(path string) []*Task {
+	tasukuDir := FindTasukuDir(path)
 	if tasukuDir == "" {
-		return "", ""
+		return nil
 	}
 
-	tasksDir := filepath.Join(tasukuDir, "tasks")
-	entries, err := os.ReadDir(tasksDir)
+	tasukuTasks, err := ListTasukuTasks(tasukuDir)
 	if err != nil {
-		return "", ""
+		return nil
 	}
 
-	for _, entry := range entries {
-		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
-			continue
-		}
-
-		taskPath := filepath.Join(tasksDir, entry.Name())
-		task, err := readTasukuTask(taskPath)
-		if err != nil {
-			continue
-		}
+	tasks := make([]*Task, 0, len(tasukuTasks))
+	for _, t := range tasukuTasks {
+		tasks = append(tasks, &Task{
+			ID:     t.ID,
+			Title:  t.Description,
+			Status: normalizeStatus(t.Status),
+			Source: "tasuku",
+		})
+	}
+	return tasks
+}
 
-		if task.Status == "in_progress" {
-			return task.ID, task.Description
-		}
+// SetStatus implements StatusSetter
+func (Tasuku) SetStatus(path, id string, status Status) error {
+	tasukuDir := FindTasukuDir(path)
+	if tasukuDir == "" {
+		return fmt.Errorf("no .tasuku directory found")
 	}
+	return UpdateTasukuStatus(tasukuDir, id, tasukuStatus(status))
+}
 
-	return "", ""
+// tasukuStatus maps a Status to Tasuku's name for it
+func tasukuStatus(status Status) string {
+	if status == StatusOpen {
+		return "ready"
+	}
+	return string(status)
 }
 
 // readTasukuTask reads and parses a single Tasuku task file
@@ -88,8 +103,8 @@ func readTasukuTask(path string) (*TasukuTask, error) {
 	return &task, nil
 }
 
-// ListTasks returns all tasks from a .tasuku directory
-func ListTasks(tasukuDir string) ([]*TasukuTask, error) {
+// ListTasukuTasks returns all tasks from a .tasuku directory
+func ListTasukuTasks(tasukuDir string) ([]*TasukuTask, error) {
 	tasksDir := filepath.Join(tasukuDir, "tasks")
 	entries, err := os.ReadDir(tasksDir)
 	if err != nil {
@@ -114,8 +129,8 @@ func ListTasks(tasukuDir string) ([]*TasukuTask, error) {
 	return tasks, nil
 }
 
-// FindTask finds a task by ID in the .tasuku directory
-func FindTask(tasukuDir string, taskID string) (*TasukuTask, string, error) {
+// FindTasukuTask finds a task by ID in the .tasuku directory
+func FindTasukuTask(tasukuDir string, taskID string) (*TasukuTask, string, error) {
 	tasksDir := filepath.Join(tasukuDir, "tasks")
 	entries, err := os.ReadDir(tasksDir)
 	if err != nil {
@@ -141,9 +156,9 @@ func FindTask(tasukuDir string, taskID string) (*TasukuTask, string, error) {
 	return nil, "", fmt.Errorf("task not found: %s", taskID)
 }
 
-// UpdateTaskStatus updates the status of a task
-func UpdateTaskStatus(tasukuDir string, taskID string, newStatus string) error {
-	task, taskPath, err := FindTask(tasukuDir, taskID)
+// UpdateTasukuStatus updates the status of a task
+func UpdateTasukuStatus(tasukuDir string, taskID string, newStatus string) error {
+	task, taskPath, err := FindTasukuTask(tasukuDir, taskID)
 	if err != nil {
 		return err
 	}
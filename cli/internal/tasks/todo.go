@@ -0,0 +1,106 @@
+package tasks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// TodoFile reads checklist items from a TODO.md at the worktree root:
+// "- [ ]" is open, "- [/]" or "- [~]" in progress and "- [x]" done
+type TodoFile struct{}
+
+// todoFileNames are the names checked, in order
+var todoFileNames = []string{"TODO.md", "todo.md"}
+
+// Name implements Provider
+func (TodoFile) Name() string { return "todo" }
+
+// Tasks implements Provider
+func (TodoFile) Tasks(path string) []*Task {
+	for _, name := range todoFileNames {
+		content, err := os.ReadFile(filepath.Join(path, name))
+		if err != nil {
+			continue
+		}
+		return parseTodo(name, string(content))
+	}
+	return nil
+}
+
+// SetStatus implements StatusSetter. A TODO item's ID is its file and line.
+func (TodoFile) SetStatus(path, id string, status Status) error {
+	name, lineStr, ok := strings.Cut(id, ":")
+	lineNum, err := strconv.Atoi(lineStr)
+	if !ok || err != nil {
+		return fmt.Errorf("invalid TODO item %q", id)
+	}
+
+	var marker byte
+	switch status {
+	case StatusOpen:
+		marker = ' '
+	case StatusInProgress:
+		marker = '/'
+	case StatusDone:
+		marker = 'x'
+	default:
+		return fmt.Errorf("TODO items can't be %s", status)
+	}
+
+	file := filepath.Join(path, name)
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(content), "\n")
+	if lineNum < 1 || lineNum > len(lines) {
+		return fmt.Errorf("task not found: %s", id)
+	}
+
+	line := lines[lineNum-1]
+	i := strings.Index(line, "[")
+	if i < 0 || i+2 >= len(line) || line[i+2] != ']' {
+		return fmt.Errorf("task not found: %s", id)
+	}
+	lines[lineNum-1] = line[:i+1] + string(marker) + line[i+2:]
+	return os.WriteFile(file, []byte(strings.Join(lines, "\n")), 0644)
+}
+
+// parseTodo returns the checklist items of a TODO file, with their line
+// as ID
+func parseTodo(name, content string) []*Task {
+	var tasks []*Task
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) < 6 || (line[0] != '-' && line[0] != '*') || line[1] != ' ' || line[2] != '[' || line[4] != ']' {
+			continue
+		}
+
+		var status Status
+		switch line[3] {
+		case ' ':
+			status = StatusOpen
+		case '/', '~':
+			status = StatusInProgress
+		case 'x', 'X':
+			status = StatusDone
+		default:
+			continue
+		}
+
+		title := strings.TrimSpace(line[5:])
+		if title == "" {
+			continue
+		}
+		tasks = append(tasks, &Task{
+			ID:     fmt.Sprintf("%s:%d", name, i+1),
+			Title:  title,
+			Status: status,
+			Source: "todo",
+		})
+	}
+	return tasks
+}
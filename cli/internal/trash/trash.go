@@ -0,0 +1,339 @@
+// Package trash keeps what deleting a worktree with uncommitted changes
+// would lose: a patch of its changes, its untracked files and a bundle of
+// its branch's commits, so it can be restored until the retention period
+// ends. Unlike an archive, an entry doesn't depend on refs in the main
+// repo surviving.
+package trash
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/iheanyi/grove/internal/execx"
+	"github.com/iheanyi/grove/internal/git"
+)
+
+const (
+	entryFile    = "entry.json"
+	patchFile    = "changes.patch"
+	untrackedDir = "untracked"
+	bundleFile   = "branch.bundle"
+)
+
+// Entry is a deleted worktree in the trash
+type Entry struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Branch   string `json:"branch,omitempty"`
+	Commit   string `json:"commit"`
+	MainRepo string `json:"main_repo"`
+	// Files is how many changed and untracked files were saved
+	Files int `json:"files"`
+	// Bundle is set when the branch's commits that aren't on the default
+	// branch were saved, in case the branch is deleted too
+	Bundle    bool      `json:"bundle,omitempty"`
+	DeletedAt time.Time `json:"deleted_at"`
+
+	dir string
+}
+
+// Save puts a worktree that's about to be deleted in the trash under root.
+// base is the default branch; commits on it aren't bundled.
+func Save(root, name, path, mainRepo, base string) (*Entry, error) {
+	commit, err := runGit(path, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	branch, _ := runGit(path, "symbolic-ref", "--short", "-q", "HEAD")
+
+	now := time.Now()
+	e := &Entry{
+		ID:        name + "-" + now.Format("20060102-150405"),
+		Name:      name,
+		Path:      path,
+		Branch:    branch,
+		Commit:    commit,
+		MainRepo:  mainRepo,
+		DeletedAt: now,
+	}
+	e.dir = filepath.Join(root, e.ID)
+	if err := os.MkdirAll(e.dir, 0700); err != nil {
+		return nil, err
+	}
+	if err := e.save(path, base); err != nil {
+		os.RemoveAll(e.dir)
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *Entry) save(path, base string) error {
+	// Tracked changes, staged or not, as one patch against HEAD
+	patch, err := gitRaw(path, "diff", "--binary", "HEAD")
+	if err != nil {
+		return err
+	}
+	if len(patch) > 0 {
+		if err := os.WriteFile(filepath.Join(e.dir, patchFile), patch, 0600); err != nil {
+			return err
+		}
+		changed, err := runGit(path, "diff", "--name-only", "HEAD")
+		if err != nil {
+			return err
+		}
+		e.Files += len(strings.Split(changed, "\n"))
+	}
+
+	untracked, err := gitRaw(path, "ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return err
+	}
+	for _, rel := range strings.Split(string(untracked), "\x00") {
+		if rel == "" {
+			continue
+		}
+		if err := copyPath(filepath.Join(path, rel), filepath.Join(e.dir, untrackedDir, rel)); err != nil {
+			return err
+		}
+		e.Files++
+	}
+
+	if e.Branch != "" {
+		ref := "refs/heads/" + e.Branch
+		args := []string{"rev-list", "--count", ref}
+		if base != "" && base != e.Branch {
+			args = append(args, "^"+base)
+		}
+		if count, err := runGit(e.MainRepo, args...); err == nil && count != "0" {
+			bundle := append([]string{"bundle", "create", filepath.Join(e.dir, bundleFile)}, args[2:]...)
+			if _, err := runGit(e.MainRepo, bundle...); err != nil {
+				return err
+			}
+			e.Bundle = true
+		}
+	}
+
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(e.dir, entryFile), data, 0600)
+}
+
+// Dir returns the directory holding the entry's files
+func (e *Entry) Dir() string {
+	return e.dir
+}
+
+// Restore recreates the worktree at its old path, on its branch, and
+// reapplies its changes and untracked files. A branch deleted meanwhile is
+// recreated at the saved commit, from the bundle if the main repo lost it.
+func (e *Entry) Restore() error {
+	if _, err := os.Stat(e.Path); err == nil {
+		return fmt.Errorf("%s already exists", e.Path)
+	}
+	if _, err := os.Stat(e.MainRepo); err != nil {
+		return fmt.Errorf("main repo %s no longer exists", e.MainRepo)
+	}
+
+	if _, err := runGit(e.MainRepo, "cat-file", "-e", e.Commit+"^{commit}"); err != nil && e.Bundle {
+		if _, err := runGit(e.MainRepo, "fetch", "-q", filepath.Join(e.dir, bundleFile), "refs/heads/"+e.Branch); err != nil {
+			return err
+		}
+	}
+	if _, err := runGit(e.MainRepo, "cat-file", "-e", e.Commit+"^{commit}"); err != nil {
+		return fmt.Errorf("commit %s is no longer in %s", e.Commit, e.MainRepo)
+	}
+
+	opts := git.AddOptions{Detach: true, Commit: e.Commit}
+	if e.Branch != "" {
+		opts = git.AddOptions{Commit: e.Branch}
+		if _, err := runGit(e.MainRepo, "rev-parse", "--verify", "-q", "refs/heads/"+e.Branch); err != nil {
+			opts = git.AddOptions{NewBranch: e.Branch, Commit: e.Commit}
+		}
+	}
+	if err := git.New(execx.OS).AddWorktree(context.Background(), e.MainRepo, e.Path, opts); err != nil {
+		return fmt.Errorf("git worktree add: %w", err)
+	}
+
+	if err := e.restoreChanges(); err != nil {
+		return fmt.Errorf("worktree restored, but %w (still saved in %s)", err, e.dir)
+	}
+	return nil
+}
+
+func (e *Entry) restoreChanges() error {
+	patch := filepath.Join(e.dir, patchFile)
+	if _, err := os.Stat(patch); err == nil {
+		// A three-way merge copes with a branch that moved on, but stages
+		// the changes, so it's only the fallback
+		if _, err := runGit(e.Path, "apply", patch); err != nil {
+			if _, err := runGit(e.Path, "apply", "--3way", patch); err != nil {
+				return fmt.Errorf("failed to apply uncommitted changes: %w", err)
+			}
+		}
+	}
+
+	src := filepath.Join(e.dir, untrackedDir)
+	if _, err := os.Stat(src); err != nil {
+		return nil
+	}
+	return filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		dst := filepath.Join(e.Path, rel)
+		if _, err := os.Lstat(dst); err == nil {
+			return nil
+		}
+		return copyPath(p, dst)
+	})
+}
+
+// Remove deletes the entry from the trash
+func (e *Entry) Remove() error {
+	return os.RemoveAll(e.dir)
+}
+
+// List returns the entries in the trash under root, most recently deleted
+// first
+func List(root string) ([]*Entry, error) {
+	dirs, err := os.ReadDir(root)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*Entry
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(root, d.Name(), entryFile))
+		if err != nil {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(data, &e); err != nil {
+			continue
+		}
+		e.dir = filepath.Join(root, d.Name())
+		entries = append(entries, &e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].DeletedAt.After(entries[j].DeletedAt) })
+	return entries, nil
+}
+
+// Find returns the entry with an ID, or the most recently deleted one of a
+// worktree name
+func Find(root, idOrName string) (*Entry, error) {
+	entries, err := List(root)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.ID == idOrName {
+			return e, nil
+		}
+	}
+	for _, e := range entries {
+		if e.Name == idOrName {
+			return e, nil
+		}
+	}
+	return nil, fmt.Errorf("nothing named '%s' in the trash", idOrName)
+}
+
+// Purge deletes the entries older than retention, returning them
+func Purge(root string, retention time.Duration) ([]*Entry, error) {
+	entries, err := List(root)
+	if err != nil {
+		return nil, err
+	}
+	var purged []*Entry
+	for _, e := range entries {
+		if time.Since(e.DeletedAt) <= retention {
+			continue
+		}
+		if err := e.Remove(); err != nil {
+			return purged, err
+		}
+		purged = append(purged, e)
+	}
+	return purged, nil
+}
+
+// copyPath copies a file or symlink, creating the destination's parents
+func copyPath(src, dst string) error {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	if info.Mode()&fs.ModeSymlink != 0 {
+		target, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+		return os.Symlink(target, dst)
+	}
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func runGit(dir string, args ...string) (string, error) {
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(out)))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// gitRaw returns a git command's output as is, for patches and NUL
+// separated lists
+func gitRaw(dir string, args ...string) ([]byte, error) {
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, err
+	}
+	return out, nil
+}
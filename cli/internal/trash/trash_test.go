@@ -0,0 +1,114 @@
+package trash
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func run(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=grove", "GIT_AUTHOR_EMAIL=grove@example.com",
+		"GIT_COMMITTER_NAME=grove", "GIT_COMMITTER_EMAIL=grove@example.com")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func TestSaveAndRestore(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	dir := t.TempDir()
+	root := filepath.Join(dir, "trash")
+	repo := filepath.Join(dir, "repo")
+	wt := filepath.Join(dir, "feature")
+	if err := os.MkdirAll(repo, 0755); err != nil {
+		t.Fatal(err)
+	}
+	run(t, repo, "init", "-q", "-b", "main")
+	if err := os.WriteFile(filepath.Join(repo, "app.txt"), []byte("v1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	run(t, repo, "add", "app.txt")
+	run(t, repo, "commit", "-q", "-m", "initial")
+	run(t, repo, "worktree", "add", "-q", "-b", "feature", wt)
+	run(t, wt, "commit", "-q", "--allow-empty", "-m", "agent work")
+
+	// A tracked change and an untracked file
+	if err := os.WriteFile(filepath.Join(wt, "app.txt"), []byte("v2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(wt, "notes"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(wt, "notes", "todo.md"), []byte("wip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	e, err := Save(root, "feature", wt, repo, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Branch != "feature" || e.Files != 2 || !e.Bundle {
+		t.Fatalf("entry = %+v, want branch feature, 2 files and a bundle", e)
+	}
+
+	// The worktree and its branch are deleted, and the branch's commit is
+	// gone from the main repo
+	run(t, repo, "worktree", "remove", "--force", wt)
+	run(t, repo, "branch", "-D", "feature")
+	run(t, repo, "reflog", "expire", "--expire=now", "--all")
+	run(t, repo, "gc", "-q", "--prune=now")
+
+	found, err := Find(root, "feature")
+	if err != nil || found.ID != e.ID {
+		t.Fatalf("Find = %v, %v", found, err)
+	}
+	if err := found.Restore(); err != nil {
+		t.Fatal(err)
+	}
+	for file, want := range map[string]string{"app.txt": "v2\n", "notes/todo.md": "wip"} {
+		if data, err := os.ReadFile(filepath.Join(wt, file)); err != nil || string(data) != want {
+			t.Errorf("%s = %q, %v; want %q", file, data, err, want)
+		}
+	}
+	out, err := exec.Command("git", "-C", wt, "log", "-1", "--format=%s").Output()
+	if err != nil || string(out) != "agent work\n" {
+		t.Errorf("restored HEAD = %q, %v; want the agent's commit", out, err)
+	}
+}
+
+func TestPurge(t *testing.T) {
+	root := t.TempDir()
+	for name, age := range map[string]time.Duration{"old": 30 * 24 * time.Hour, "new": time.Hour} {
+		data, err := json.Marshal(Entry{ID: name, Name: name, DeletedAt: time.Now().Add(-age)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Join(root, name), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, name, entryFile), data, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	purged, err := Purge(root, 14*24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(purged) != 1 || purged[0].Name != "old" {
+		t.Fatalf("purged %v, want old", purged)
+	}
+	entries, _ := List(root)
+	if len(entries) != 1 || entries[0].Name != "new" {
+		t.Errorf("entries = %v, want new", entries)
+	}
+}
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"sort"
@@ -14,9 +15,14 @@ import (
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/editor"
+	"github.com/iheanyi/grove/internal/events"
 	"github.com/iheanyi/grove/internal/registry"
 	"github.com/iheanyi/grove/internal/styles"
+	"github.com/iheanyi/grove/internal/tasks"
+	"github.com/iheanyi/grove/internal/usage"
 	"github.com/iheanyi/grove/pkg/browser"
 )
 
@@ -28,6 +34,7 @@ type EnhancedKeyMap struct {
 	Stop          key.Binding
 	Restart       key.Binding
 	Open          key.Binding
+	Editor        key.Binding
 	CopyURL       key.Binding
 	Logs          key.Binding
 	AllLogs       key.Binding
@@ -63,6 +70,10 @@ var enhancedKeys = EnhancedKeyMap{
 		key.WithKeys("b"),
 		key.WithHelp("b", "browser"),
 	),
+	Editor: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "editor"),
+	),
 	CopyURL: key.NewBinding(
 		key.WithKeys("c"),
 		key.WithHelp("c", "copy URL"),
@@ -99,13 +110,17 @@ var enhancedKeys = EnhancedKeyMap{
 
 // EnhancedServerItem represents a server in the list with health info
 type EnhancedServerItem struct {
-	server *registry.Server
+	server  *registry.Server
+	sampler *usage.Sampler
+	task    *tasks.Task // In progress in the worktree, if any
 }
 
 // Title returns plain text with status icon prefix
 func (i EnhancedServerItem) Title() string {
 	statusIcon := "○"
-	if i.server.IsRunning() {
+	if i.server.IsPaused() {
+		statusIcon = "⏸"
+	} else if i.server.IsRunning() {
 		statusIcon = "●"
 	} else if i.server.Status == registry.StatusCrashed {
 		statusIcon = "✗"
@@ -125,6 +140,14 @@ func (i EnhancedServerItem) Description() string {
 		if uptime != "-" {
 			parts = append(parts, "↑ "+uptime)
 		}
+
+		if u, ok := i.sampler.Latest(i.server.Name); ok {
+			resources := u.String()
+			if history := i.sampler.History(i.server.Name); len(history) > 1 {
+				resources += " " + usage.Sparkline(usage.CPUHistory(history))
+			}
+			parts = append(parts, resources)
+		}
 	}
 
 	// Add last health check time if available
@@ -133,16 +156,30 @@ func (i EnhancedServerItem) Description() string {
 		parts = append(parts, "checked "+lastCheck)
 	}
 
+	if len(i.server.Tags) > 0 {
+		parts = append(parts, registry.FormatTags(i.server.Tags))
+	}
+
+	if i.task != nil {
+		parts = append(parts, "📋 "+ansi.Truncate(i.task.Summary(), styles.TruncateShort, styles.TruncateTail))
+	}
+
 	return strings.Join(parts, "  |  ")
 }
 
+// FilterValue includes tags, so filtering by "#frontend" finds tagged servers
 func (i EnhancedServerItem) FilterValue() string {
-	return i.server.Name
+	if len(i.server.Tags) == 0 {
+		return i.server.Name
+	}
+	return i.server.Name + " " + registry.FormatTags(i.server.Tags)
 }
 
 // StatusIcon returns the status icon for display
 func (i EnhancedServerItem) StatusIcon() string {
-	if i.server.IsRunning() {
+	if i.server.IsPaused() {
+		return "⏸"
+	} else if i.server.IsRunning() {
 		return "●"
 	} else if i.server.Status == registry.StatusCrashed {
 		return "✗"
@@ -152,7 +189,9 @@ func (i EnhancedServerItem) StatusIcon() string {
 
 // StatusStyle returns the lipgloss style for the status
 func (i EnhancedServerItem) StatusStyle() lipgloss.Style {
-	if i.server.IsRunning() {
+	if i.server.IsPaused() {
+		return statusPausedStyle
+	} else if i.server.IsRunning() {
 		return statusRunningStyle
 	} else if i.server.Status == registry.StatusCrashed {
 		return statusCrashedStyle
@@ -162,7 +201,7 @@ func (i EnhancedServerItem) StatusStyle() lipgloss.Style {
 
 // HealthIndicator returns the health indicator string
 func (i EnhancedServerItem) HealthIndicator() string {
-	if !i.server.IsRunning() {
+	if !i.server.IsRunning() || i.server.IsPaused() {
 		return ""
 	}
 	switch i.server.Health {
@@ -202,6 +241,9 @@ type EnhancedModel struct {
 	list           list.Model
 	reg            *registry.Registry
 	cfg            *config.Config
+	deps           Deps
+	ctx            context.Context // Done once the TUI quits
+	cancel         context.CancelFunc
 	width          int
 	height         int
 	showHelp       bool
@@ -211,6 +253,7 @@ type EnhancedModel struct {
 	serverHealth   map[string]registry.HealthStatus
 	starting       map[string]bool // Track servers currently starting
 	healthChecking bool            // True when health checks are in progress
+	sampler        *usage.Sampler  // CPU and memory of running servers
 
 	// View switching
 	viewMode       ViewMode
@@ -220,13 +263,22 @@ type EnhancedModel struct {
 
 // NewEnhanced creates a new enhanced TUI model
 func NewEnhanced(cfg *config.Config) (*EnhancedModel, error) {
-	reg, err := registry.Load()
+	return TestableModel(cfg, defaultDeps())
+}
+
+// TestableModel creates the enhanced TUI model with deps in place of the
+// registry on disk and real processes, so tests can drive it headless
+func TestableModel(cfg *config.Config, deps Deps) (*EnhancedModel, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	reg, err := deps.Registry.Load(ctx)
 	if err != nil {
+		cancel()
 		return nil, fmt.Errorf("failed to load registry: %w", err)
 	}
 
 	// Create list items from servers
-	items := makeEnhancedItems(reg)
+	sampler := usage.NewSampler(usageSamples)
+	items := makeEnhancedItems(reg, sampler)
 
 	// Create default delegate - Title() includes status icon as plain text
 	delegate := list.NewDefaultDelegate()
@@ -237,6 +289,9 @@ func NewEnhanced(cfg *config.Config) (*EnhancedModel, error) {
 
 	l := list.New(items, delegate, 0, 0)
 	l.Title = "grove - Worktree Server Manager"
+	if name := config.NamedContext(); name != "" {
+		l.Title = "grove [" + name + "] - Worktree Server Manager"
+	}
 	l.SetShowStatusBar(false)
 	l.SetFilteringEnabled(true)
 	l.Styles.Title = titleStyle
@@ -250,14 +305,18 @@ func NewEnhanced(cfg *config.Config) (*EnhancedModel, error) {
 		list:         l,
 		reg:          reg,
 		cfg:          cfg,
+		deps:         deps,
+		ctx:          ctx,
+		cancel:       cancel,
 		spinner:      s,
 		actionPanel:  NewActionPanel(),
 		serverHealth: make(map[string]registry.HealthStatus),
 		starting:     make(map[string]bool),
+		sampler:      sampler,
 	}, nil
 }
 
-func makeEnhancedItems(reg *registry.Registry) []list.Item {
+func makeEnhancedItems(reg *registry.Registry, sampler *usage.Sampler) []list.Item {
 	servers := reg.List()
 
 	// Sort: running servers first, then by name
@@ -270,7 +329,7 @@ func makeEnhancedItems(reg *registry.Registry) []list.Item {
 
 	items := make([]list.Item, len(servers))
 	for i, s := range servers {
-		items[i] = EnhancedServerItem{server: s}
+		items[i] = EnhancedServerItem{server: s, sampler: sampler, task: tasks.Active(s.Path)}
 	}
 	return items
 }
@@ -278,9 +337,10 @@ func makeEnhancedItems(reg *registry.Registry) []list.Item {
 // Init initializes the enhanced model
 func (m EnhancedModel) Init() tea.Cmd {
 	return tea.Batch(
-		WatchRegistry(), // Watch for registry file changes instead of polling
+		m.deps.Registry.Watch(), // Watch for registry file changes instead of polling
 		m.spinner.Tick,
 		HealthCheckTicker(10*time.Second),
+		SampleUsageCmd(m.sampler, m.reg.ListRunning()),
 	)
 }
 
@@ -326,8 +386,9 @@ func (m EnhancedModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 
 		case tea.KeyMsg:
-			// Check for quit keys to return to list view
-			if key.Matches(msg, logViewerKeys.Quit) {
+			// Check for quit keys to return to list view, unless they'd
+			// end a search
+			if key.Matches(msg, logViewerKeys.Quit) && !m.multiLogViewer.Searching() {
 				m.viewMode = ViewModeList
 				m.multiLogViewer = nil
 				return m, nil
@@ -349,7 +410,7 @@ func (m EnhancedModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case RegistryChangedMsg:
 		// Registry file changed - refresh if not filtering
-		if reg, err := registry.Load(); err == nil {
+		if reg, err := m.deps.Registry.Load(m.ctx); err == nil {
 			m.reg = reg
 			// Cleanup and check for externally-started servers
 			if cleanupResult, err := m.reg.Cleanup(); err == nil && len(cleanupResult.Started) > 0 {
@@ -361,11 +422,11 @@ func (m EnhancedModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 			if m.list.FilterState() == list.Unfiltered {
-				m.list.SetItems(makeEnhancedItems(m.reg))
+				m.list.SetItems(makeEnhancedItems(m.reg, m.sampler))
 			}
 		}
 		// Continue watching for more changes
-		return m, tea.Batch(append(cmds, WatchRegistry())...)
+		return m, tea.Batch(append(cmds, m.deps.Registry.Watch())...)
 
 	case spinner.TickMsg:
 		var cmd tea.Cmd
@@ -379,21 +440,44 @@ func (m EnhancedModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.healthChecking = true
 		}
 		for _, server := range running {
+			// Paused servers can't respond; checking would mark them unhealthy
+			if server.IsPaused() {
+				continue
+			}
 			cmds = append(cmds, HealthCheckCmd(server))
 		}
 		return m, tea.Batch(append(cmds, HealthCheckTicker(10*time.Second))...)
 
+	case usageTickMsg:
+		return m, SampleUsageCmd(m.sampler, m.reg.ListRunning())
+
+	case UsageSampledMsg:
+		if m.list.FilterState() == list.Unfiltered {
+			m.list.SetItems(makeEnhancedItems(m.reg, m.sampler))
+		}
+		return m, UsageTicker(2 * time.Second)
+
 	case HealthCheckMsg:
 		// Update server health
 		m.healthChecking = false
 		if server, ok := m.reg.Get(msg.ServerName); ok {
+			previous := m.serverHealth[msg.ServerName]
 			server.Health = msg.Health
 			server.LastHealthCheck = msg.CheckTime
 			m.reg.Set(server) //nolint:errcheck // Best effort health update
 			m.serverHealth[msg.ServerName] = msg.Health
 			// Don't update items while filtering as it disrupts the filter state
 			if m.list.FilterState() == list.Unfiltered {
-				m.list.SetItems(makeEnhancedItems(m.reg))
+				m.list.SetItems(makeEnhancedItems(m.reg, m.sampler))
+			}
+
+			// Notify when health flips (not on the first check)
+			if previous != "" && previous != msg.Health && msg.Health != registry.HealthUnknown {
+				event := server.Event(events.HealthChanged)
+				return m, func() tea.Msg {
+					events.Publish(event)
+					return nil
+				}
 			}
 		}
 		return m, nil
@@ -415,6 +499,7 @@ func (m EnhancedModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Handle our custom keys (works in both Unfiltered and FilterApplied states)
 		switch {
 		case key.Matches(msg, enhancedKeys.Quit):
+			m.cancel()
 			return m, tea.Quit
 
 		case key.Matches(msg, enhancedKeys.Help):
@@ -433,6 +518,9 @@ func (m EnhancedModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, enhancedKeys.Open):
 			return m, m.openServer()
 
+		case key.Matches(msg, enhancedKeys.Editor):
+			return m, m.openEditor()
+
 		case key.Matches(msg, enhancedKeys.CopyURL):
 			return m, m.copyURL()
 
@@ -443,12 +531,12 @@ func (m EnhancedModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, m.viewAllLogs()
 
 		case key.Matches(msg, enhancedKeys.Refresh):
-			if reg, err := registry.Load(); err == nil {
+			if reg, err := m.deps.Registry.Load(m.ctx); err == nil {
 				m.reg = reg
 				m.reg.Cleanup() //nolint:errcheck // Best effort cleanup during refresh
 				// Only update items if not filtering
 				if m.list.FilterState() == list.Unfiltered {
-					m.list.SetItems(makeEnhancedItems(m.reg))
+					m.list.SetItems(makeEnhancedItems(m.reg, m.sampler))
 				}
 			}
 			return m, nil
@@ -504,7 +592,7 @@ func (m EnhancedModel) View() string {
 
 	// Proxy status
 	proxy := m.reg.GetProxy()
-	if proxy.IsRunning() && isProcessRunning(proxy.PID) {
+	if proxy.IsRunning() && m.deps.Processes.Running(proxy.PID) {
 		b.WriteString(statusRunningStyle.Render(fmt.Sprintf("  Proxy: running on :%d/:%d", proxy.HTTPPort, proxy.HTTPSPort)))
 	} else {
 		b.WriteString(statusStoppedStyle.Render("  Proxy: not running (p to start)"))
@@ -541,7 +629,7 @@ func (m EnhancedModel) View() string {
 		b.WriteString(m.renderHelp())
 	} else {
 		b.WriteString("\n")
-		b.WriteString(helpStyle.Render("  [s]start [x]stop [r]restart [b]browser [c]copy [l]logs [L]all-logs [a]actions [/]search [?]help [q]quit"))
+		b.WriteString(helpStyle.Render("  [s]start [x]stop [r]restart [b]browser [e]editor [c]copy [l]logs [L]all-logs [a]actions [/]search [?]help [q]quit"))
 	}
 
 	return b.String()
@@ -555,6 +643,7 @@ func (m EnhancedModel) renderHelp() string {
 	b.WriteString("  x             Stop selected server\n")
 	b.WriteString("  r             Restart selected server\n")
 	b.WriteString("  b             Open server in browser\n")
+	b.WriteString("  e             Open worktree in editor\n")
 	b.WriteString("  c             Copy URL to clipboard\n")
 	b.WriteString("  l             View server logs\n")
 	b.WriteString("  L             View all server logs\n")
@@ -616,14 +705,14 @@ func (m *EnhancedModel) stopServer() tea.Cmd {
 	}
 
 	return func() tea.Msg {
-		// Stop server
-		if process, err := os.FindProcess(server.PID); err == nil {
-			process.Signal(syscall.SIGTERM) //nolint:errcheck // Best effort signal
-		}
-		server.Status = registry.StatusStopped
-		server.PID = 0
-		server.StoppedAt = time.Now()
-		if err := m.reg.Set(server); err != nil {
+		// Stop server. Update a copy: the list renders the original
+		// concurrently, until the registry change reloads it.
+		m.deps.Processes.Signal(server.PID, syscall.SIGTERM) //nolint:errcheck // Best effort signal
+		stopped := *server
+		stopped.Status = registry.StatusStopped
+		stopped.PID = 0
+		stopped.StoppedAt = time.Now()
+		if err := m.reg.Set(&stopped); err != nil {
 			return NotificationMsg{
 				Message: fmt.Sprintf("Failed to update registry: %v", err),
 				Type:    NotificationError,
@@ -655,9 +744,7 @@ func (m *EnhancedModel) restartServer() tea.Cmd {
 
 	return func() tea.Msg {
 		// Stop server first
-		if process, err := os.FindProcess(server.PID); err == nil {
-			process.Signal(syscall.SIGTERM) //nolint:errcheck // Best effort signal
-		}
+		m.deps.Processes.Signal(server.PID, syscall.SIGTERM) //nolint:errcheck // Best effort signal
 		return NotificationMsg{
 			Message: fmt.Sprintf("Restart %s with 'grove start %s'", server.Name, server.Name),
 			Type:    NotificationInfo,
@@ -696,6 +783,38 @@ func (m *EnhancedModel) openServer() tea.Cmd {
 	}
 }
 
+func (m *EnhancedModel) openEditor() tea.Cmd {
+	if m.list.SelectedItem() == nil {
+		return nil
+	}
+
+	item := m.list.SelectedItem().(EnhancedServerItem)
+	server := item.server
+
+	var recorded string
+	if ws, ok := m.reg.GetWorkspace(server.Name); ok {
+		recorded = ws.Editor
+	}
+
+	return func() tea.Msg {
+		ed, err := editor.Resolve(m.cfg.Editor, recorded)
+		if err == nil {
+			err = ed.Open(server.Path)
+		}
+		if err != nil {
+			return NotificationMsg{
+				Message: fmt.Sprintf("Failed to open editor: %v", err),
+				Type:    NotificationError,
+			}
+		}
+		m.reg.RecordEditor(server.Name, ed.Name) //nolint:errcheck // Best effort association
+		return NotificationMsg{
+			Message: fmt.Sprintf("Opened %s in %s", server.Name, ed.Name),
+			Type:    NotificationSuccess,
+		}
+	}
+}
+
 func (m *EnhancedModel) copyURL() tea.Cmd {
 	if m.list.SelectedItem() == nil {
 		return nil
@@ -805,11 +924,9 @@ func (m *EnhancedModel) toggleProxy() tea.Cmd {
 	proxy := m.reg.GetProxy()
 
 	return func() tea.Msg {
-		if proxy.IsRunning() && isProcessRunning(proxy.PID) {
+		if proxy.IsRunning() && m.deps.Processes.Running(proxy.PID) {
 			// Stop proxy
-			if process, err := os.FindProcess(proxy.PID); err == nil {
-				process.Signal(syscall.SIGTERM) //nolint:errcheck // Best effort signal
-			}
+			m.deps.Processes.Signal(proxy.PID, syscall.SIGTERM) //nolint:errcheck // Best effort signal
 			proxy.PID = 0
 			if err := m.reg.UpdateProxy(proxy); err != nil {
 				return NotificationMsg{
@@ -836,6 +953,8 @@ func RunEnhanced(cfg *config.Config) error {
 		return err
 	}
 
+	defer m.cancel()
+
 	p := tea.NewProgram(m, tea.WithAltScreen())
 	_, err = p.Run()
 	return err
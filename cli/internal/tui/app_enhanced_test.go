@@ -0,0 +1,212 @@
+package tui
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/exp/teatest"
+	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+// testRegistry loads a registry from a temporary file
+type testRegistry struct {
+	path string
+}
+
+func (r testRegistry) Load(context.Context) (*registry.Registry, error) {
+	return registry.LoadFrom(r.path)
+}
+
+// Watch doesn't watch; tests send RegistryChangedMsg themselves
+func (r testRegistry) Watch() tea.Cmd {
+	return nil
+}
+
+// fakeProcesses treats the given PIDs as running and records signals
+type fakeProcesses struct {
+	mu      sync.Mutex
+	running map[int]bool
+	signals []int
+}
+
+func (p *fakeProcesses) Running(pid int) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.running[pid]
+}
+
+func (p *fakeProcesses) Signal(pid int, sig syscall.Signal) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if sig == syscall.SIGTERM {
+		p.signals = append(p.signals, pid)
+		delete(p.running, pid)
+	}
+	return nil
+}
+
+func (p *fakeProcesses) signaled() []int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]int(nil), p.signals...)
+}
+
+// newTestTUI starts the TUI headless over a registry of servers
+func newTestTUI(t *testing.T, servers ...*registry.Server) (*teatest.TestModel, testRegistry, *fakeProcesses) {
+	t.Helper()
+
+	reg := testRegistry{path: filepath.Join(t.TempDir(), "registry.json")}
+	r, err := reg.Load(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	procs := &fakeProcesses{running: make(map[int]bool)}
+	for _, s := range servers {
+		if err := r.Set(s); err != nil {
+			t.Fatal(err)
+		}
+		if s.PID > 0 {
+			procs.running[s.PID] = true
+		}
+	}
+
+	m, err := TestableModel(config.Default(), Deps{Registry: reg, Processes: procs})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tm := teatest.NewTestModel(t, m, teatest.WithInitialTermSize(120, 40))
+	t.Cleanup(func() { tm.Quit() }) //nolint:errcheck
+	return tm, reg, procs
+}
+
+func waitForOutput(t *testing.T, tm *teatest.TestModel, want string) {
+	t.Helper()
+	teatest.WaitFor(t, tm.Output(), func(out []byte) bool {
+		return bytes.Contains(out, []byte(want))
+	}, teatest.WithDuration(3*time.Second))
+}
+
+func runningServer(name string, port, pid int) *registry.Server {
+	return &registry.Server{
+		Name:      name,
+		Port:      port,
+		PID:       pid,
+		URL:       "http://localhost:1",
+		Status:    registry.StatusRunning,
+		StartedAt: time.Now(),
+	}
+}
+
+func stoppedServer(name string, port int) *registry.Server {
+	return &registry.Server{
+		Name:   name,
+		Port:   port,
+		URL:    "http://localhost:1",
+		Status: registry.StatusStopped,
+	}
+}
+
+func TestTUI_StopKey(t *testing.T) {
+	tm, reg, procs := newTestTUI(t, runningServer("api", 3001, 4242))
+	waitForOutput(t, tm, "api")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	waitForOutput(t, tm, "Stopped api")
+
+	if got := procs.signaled(); len(got) != 1 || got[0] != 4242 {
+		t.Errorf("signaled PIDs = %v, want [4242]", got)
+	}
+	r, err := reg.Load(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server, ok := r.Get("api"); !ok || server.Status != registry.StatusStopped || server.PID != 0 {
+		t.Errorf("registry server = %+v, want stopped", server)
+	}
+}
+
+func TestTUI_StartAndStopKeysWarn(t *testing.T) {
+	tm, _, procs := newTestTUI(t, runningServer("api", 3001, 4242))
+	waitForOutput(t, tm, "api")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	waitForOutput(t, tm, "api is already running")
+
+	tm2, _, _ := newTestTUI(t, stoppedServer("web", 3002))
+	waitForOutput(t, tm2, "web")
+	tm2.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	waitForOutput(t, tm2, "web is not running")
+
+	if got := procs.signaled(); len(got) != 0 {
+		t.Errorf("signaled PIDs = %v, want none", got)
+	}
+}
+
+func TestTUI_Search(t *testing.T) {
+	tm, _, _ := newTestTUI(t, stoppedServer("api", 3001), stoppedServer("web", 3002), stoppedServer("worker", 3003))
+	waitForOutput(t, tm, "worker")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	// Typed as one message: the list filters in a command per keystroke, and
+	// an earlier keystroke's matches ("we" matches worker) can land last
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("web")})
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	// Give the filter command time to finish
+	time.Sleep(200 * time.Millisecond)
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(3*time.Second)).(EnhancedModel)
+	visible := final.list.VisibleItems()
+	if len(visible) != 1 || visible[0].(EnhancedServerItem).server.Name != "web" {
+		t.Errorf("visible items = %v, want only web", visible)
+	}
+}
+
+func TestTUI_HealthUpdate(t *testing.T) {
+	tm, reg, _ := newTestTUI(t, runningServer("api", 3001, 4242))
+	waitForOutput(t, tm, "api")
+
+	tm.Send(HealthCheckMsg{ServerName: "api", Health: registry.HealthUnhealthy, CheckTime: time.Now()})
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+
+	final := tm.FinalModel(t, teatest.WithFinalTimeout(3*time.Second)).(EnhancedModel)
+	if got := final.serverHealth["api"]; got != registry.HealthUnhealthy {
+		t.Errorf("serverHealth[api] = %q, want unhealthy", got)
+	}
+	item := final.list.Items()[0].(EnhancedServerItem)
+	if !strings.Contains(item.HealthIndicator(), "✗") {
+		t.Errorf("health indicator = %q, want ✗", item.HealthIndicator())
+	}
+
+	r, err := reg.Load(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server, _ := r.Get("api"); server.Health != registry.HealthUnhealthy {
+		t.Errorf("registry health = %q, want unhealthy", server.Health)
+	}
+}
+
+func TestTUI_RegistryReload(t *testing.T) {
+	tm, reg, _ := newTestTUI(t, stoppedServer("api", 3001))
+	waitForOutput(t, tm, "api")
+
+	// Another grove process registers a server
+	r, err := reg.Load(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Set(stoppedServer("docs", 3004)); err != nil {
+		t.Fatal(err)
+	}
+	tm.Send(RegistryChangedMsg{})
+	waitForOutput(t, tm, "docs")
+}
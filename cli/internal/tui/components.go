@@ -96,6 +96,7 @@ func NewActionPanel() *ActionPanel {
 			{Key: "r", Description: "restart server", Enabled: true},
 			{Key: "c", Description: "copy URL", Enabled: true},
 			{Key: "b", Description: "open in browser", Enabled: true},
+			{Key: "e", Description: "open in editor", Enabled: true},
 			{Key: "l", Description: "view logs", Enabled: true},
 		},
 		Visible: true,
@@ -132,6 +133,8 @@ func (a *ActionPanel) UpdateActionAvailability(serverRunning bool) {
 			a.Actions[i].Enabled = serverRunning
 		case "b": // browser
 			a.Actions[i].Enabled = serverRunning
+		case "e": // editor
+			a.Actions[i].Enabled = true
 		case "l": // logs
 			a.Actions[i].Enabled = true // Always available if log file exists
 		case "c": // copy URL
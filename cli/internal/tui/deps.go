@@ -0,0 +1,91 @@
+package tui
+
+import (
+	"context"
+	"os"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+// RegistryLoader loads the registry of servers the TUI shows, giving up
+// when ctx is done. Watch returns a command that sends RegistryChangedMsg
+// when it changes, or nil to not watch.
+type RegistryLoader interface {
+	Load(ctx context.Context) (*registry.Registry, error)
+	Watch() tea.Cmd
+}
+
+// ProcessController checks on and signals the processes of servers and
+// the proxy
+type ProcessController interface {
+	Running(pid int) bool
+	Signal(pid int, sig syscall.Signal) error
+}
+
+// Deps are how the TUI reaches the registry and processes; tests replace
+// them to drive the TUI without real servers
+type Deps struct {
+	Registry  RegistryLoader
+	Processes ProcessController
+}
+
+// defaultDeps use the registry on disk and real processes
+func defaultDeps() Deps {
+	return Deps{
+		Registry:  newFileRegistry(),
+		Processes: osProcesses{},
+	}
+}
+
+// fileRegistry loads the registry from its default location, through a
+// cache that reloads it only when it changes when the cache can be created
+type fileRegistry struct {
+	cache   *registry.Cache
+	changes <-chan registry.Change
+}
+
+func newFileRegistry() fileRegistry {
+	cache, err := registry.NewCache(context.Background())
+	if err != nil {
+		return fileRegistry{}
+	}
+	changes, _ := cache.Subscribe()
+	return fileRegistry{cache: cache, changes: changes}
+}
+
+func (f fileRegistry) Load(ctx context.Context) (*registry.Registry, error) {
+	if f.cache != nil {
+		return f.cache.Get(ctx)
+	}
+	return registry.LoadContext(ctx)
+}
+
+func (f fileRegistry) Watch() tea.Cmd {
+	if f.changes == nil {
+		return WatchRegistry()
+	}
+	return func() tea.Msg {
+		change, ok := <-f.changes
+		if !ok {
+			return nil
+		}
+		return RegistryChangedMsg{Change: change}
+	}
+}
+
+// osProcesses controls real processes
+type osProcesses struct{}
+
+func (osProcesses) Running(pid int) bool {
+	return isProcessRunning(pid)
+}
+
+func (osProcesses) Signal(pid int, sig syscall.Signal) error {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return process.Signal(sig)
+}
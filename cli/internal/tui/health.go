@@ -3,28 +3,13 @@ package tui
 import (
 	"context"
 	"fmt"
-	"net"
-	"net/http"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/iheanyi/grove/internal/health"
 	"github.com/iheanyi/grove/internal/registry"
 )
 
-// healthClient is a shared http.Client with connection pooling for health checks.
-var healthClient = &http.Client{
-	Timeout: 5 * time.Second,
-	Transport: &http.Transport{
-		MaxIdleConns:        10,
-		IdleConnTimeout:     30 * time.Second,
-		DisableKeepAlives:   false,
-		MaxIdleConnsPerHost: 2,
-		DialContext: (&net.Dialer{
-			Timeout: 3 * time.Second,
-		}).DialContext,
-	},
-}
-
 // HealthCheckMsg is sent when a health check completes
 type HealthCheckMsg struct {
 	ServerName string
@@ -45,7 +30,7 @@ func StartHealthChecks(reg *registry.Registry) tea.Cmd {
 
 // checkServerHealth performs a health check on a server
 func checkServerHealth(server *registry.Server) tea.Msg {
-	health := performHealthCheck(server.URL)
+	health := performHealthCheck(server)
 	return HealthCheckMsg{
 		ServerName: server.Name,
 		Health:     health,
@@ -53,27 +38,22 @@ func checkServerHealth(server *registry.Server) tea.Msg {
 	}
 }
 
-// performHealthCheck performs an HTTP health check
-func performHealthCheck(url string) registry.HealthStatus {
+// performHealthCheck probes a server with the health check its .grove.yaml
+// configures, or an HTTP request to its URL
+func performHealthCheck(server *registry.Server) registry.HealthStatus {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
+	ok, err := health.CheckServer(ctx, server)
+	switch {
+	case !ok:
+		// Nothing to probe
 		return registry.HealthUnknown
-	}
-
-	resp, err := healthClient.Do(req)
-	if err != nil {
+	case err != nil:
 		return registry.HealthUnhealthy
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode >= 200 && resp.StatusCode < 500 {
+	default:
 		return registry.HealthHealthy
 	}
-
-	return registry.HealthUnhealthy
 }
 
 // HealthCheckCmd creates a command to check health for a specific server
@@ -26,6 +26,7 @@ type LogViewerKeyMap struct {
 	PageDown   key.Binding
 	Top        key.Binding
 	Bottom     key.Binding
+	Search     key.Binding
 }
 
 var logViewerKeys = LogViewerKeyMap{
@@ -61,6 +62,10 @@ var logViewerKeys = LogViewerKeyMap{
 		key.WithKeys("G", "end"),
 		key.WithHelp("G/end", "bottom"),
 	),
+	Search: key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/", "search all logs"),
+	),
 }
 
 // maxLogLines is the maximum number of lines to keep in memory
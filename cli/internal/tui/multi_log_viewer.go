@@ -5,16 +5,19 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"regexp"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/ansi"
 	"github.com/fsnotify/fsnotify"
 	"github.com/iheanyi/grove/internal/loghighlight"
+	"github.com/iheanyi/grove/internal/logsearch"
 	"github.com/iheanyi/grove/internal/registry"
 )
 
@@ -35,6 +38,16 @@ type MultiLogViewerModel struct {
 	width       int
 	height      int
 	fileOffsets map[string]int64 // tracks read position per log file
+
+	// Global search, started with /
+	searchInput   textinput.Model
+	searchEditing bool // typing a query
+	searchQuery   string
+	searchRe      *regexp.Regexp
+	searchResults []logsearch.Match
+	searchRunning bool
+	searchErr     error
+	queryErr      error // the query being typed doesn't compile
 }
 
 // multiLogLinesMsg is sent when log lines are loaded/updated
@@ -47,16 +60,35 @@ type multiLogLinesMsg struct {
 // multiLogFileChangedMsg is sent when any log file changes
 type multiLogFileChangedMsg struct{}
 
+// multiLogSearchMsg is sent when a search of all log files finishes
+type multiLogSearchMsg struct {
+	query   string
+	matches []logsearch.Match
+	err     error
+}
+
 // NewMultiLogViewer creates a new multi-server log viewer
 func NewMultiLogViewer(servers []*registry.Server) *MultiLogViewerModel {
+	input := textinput.New()
+	input.Prompt = "/"
+	input.Placeholder = "regexp (smart case)"
+	input.CharLimit = 256
+
 	return &MultiLogViewerModel{
 		servers:     servers,
 		entries:     []logEntry{},
 		autoScroll:  true,
 		fileOffsets: make(map[string]int64),
+		searchInput: input,
 	}
 }
 
+// Searching returns true while a search is being typed or its results are
+// shown, when q and esc end the search rather than leave the viewer
+func (m *MultiLogViewerModel) Searching() bool {
+	return m.searchEditing || m.searchQuery != ""
+}
+
 // Init initializes the multi-log viewer
 func (m *MultiLogViewerModel) Init() tea.Cmd {
 	return m.loadInitialLogs()
@@ -235,6 +267,90 @@ func (m *MultiLogViewerModel) watchAllLogFiles() tea.Cmd {
 	}
 }
 
+// searchLogs searches every server's log files, including rotated ones
+func (m *MultiLogViewerModel) searchLogs(query string, re *regexp.Regexp) tea.Cmd {
+	servers := m.servers
+	return func() tea.Msg {
+		var matches []logsearch.Match
+		for _, server := range servers {
+			if server.LogFile == "" {
+				continue
+			}
+			found, err := logsearch.Search(server.Name, logsearch.Files(server.LogFile), re, logsearch.Options{})
+			if err != nil {
+				return multiLogSearchMsg{query: query, err: err}
+			}
+			matches = append(matches, found...)
+		}
+		return multiLogSearchMsg{query: query, matches: matches}
+	}
+}
+
+// compileSearch compiles a query, ignoring case unless it has an upper
+// case letter
+func compileSearch(query string) (*regexp.Regexp, error) {
+	if strings.ToLower(query) == query {
+		query = "(?i)" + query
+	}
+	return regexp.Compile(query)
+}
+
+// clearSearch returns to the live logs
+func (m *MultiLogViewerModel) clearSearch() {
+	m.searchEditing = false
+	m.searchInput.Blur()
+	m.searchInput.SetValue("")
+	m.searchQuery = ""
+	m.searchRe = nil
+	m.searchResults = nil
+	m.searchRunning = false
+	m.searchErr = nil
+	m.updateViewport()
+	if m.autoScroll {
+		m.viewport.GotoBottom()
+	}
+}
+
+// updateSearchInput handles keys while a query is being typed
+func (m *MultiLogViewerModel) updateSearchInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+c":
+		m.queryErr = nil
+		m.searchEditing = false
+		m.searchInput.Blur()
+		if m.searchQuery == "" {
+			m.clearSearch()
+		}
+		return m, nil
+
+	case "enter":
+		query := m.searchInput.Value()
+		if query == "" {
+			m.clearSearch()
+			return m, nil
+		}
+		re, err := compileSearch(query)
+		if err != nil {
+			m.queryErr = err
+			return m, nil
+		}
+		m.queryErr = nil
+		m.searchEditing = false
+		m.searchInput.Blur()
+		m.searchQuery = query
+		m.searchRe = re
+		m.searchResults = nil
+		m.searchRunning = true
+		m.searchErr = nil
+		m.updateViewport()
+		return m, m.searchLogs(query, re)
+	}
+
+	var cmd tea.Cmd
+	m.searchInput, cmd = m.searchInput.Update(msg)
+	return m, cmd
+}
+
 // Update handles messages
 func (m *MultiLogViewerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
@@ -268,9 +384,11 @@ func (m *MultiLogViewerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		for k, v := range msg.fileOffsets {
 			m.fileOffsets[k] = v
 		}
-		m.updateViewport()
-		if m.autoScroll {
-			m.viewport.GotoBottom()
+		if m.searchQuery == "" {
+			m.updateViewport()
+			if m.autoScroll {
+				m.viewport.GotoBottom()
+			}
 		}
 		cmds = append(cmds, m.watchAllLogFiles())
 		return m, tea.Batch(cmds...)
@@ -279,9 +397,35 @@ func (m *MultiLogViewerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, m.loadNewLines())
 		return m, tea.Batch(cmds...)
 
+	case multiLogSearchMsg:
+		// Ignore results of a search that's since been replaced or cleared
+		if msg.query != m.searchQuery {
+			return m, nil
+		}
+		m.searchResults = msg.matches
+		m.searchRunning = false
+		m.searchErr = msg.err
+		m.updateViewport()
+		m.viewport.GotoBottom()
+		return m, nil
+
 	case tea.KeyMsg:
+		if m.searchEditing {
+			return m.updateSearchInput(msg)
+		}
+
 		switch {
+		case key.Matches(msg, logViewerKeys.Search):
+			m.searchEditing = true
+			m.searchInput.SetValue(m.searchQuery)
+			m.searchInput.CursorEnd()
+			return m, m.searchInput.Focus()
+
 		case key.Matches(msg, logViewerKeys.Quit):
+			if m.searchQuery != "" {
+				m.clearSearch()
+				return m, nil
+			}
 			return m, tea.Quit
 
 		case key.Matches(msg, logViewerKeys.AutoScroll):
@@ -320,8 +464,34 @@ func (m *MultiLogViewerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // updateViewport updates the viewport content
 func (m *MultiLogViewerModel) updateViewport() {
+	prefix := m.serverPrefixer()
+
 	var b strings.Builder
+	if m.searchQuery != "" {
+		matchStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("0")).Background(warningColor)
+		for _, match := range m.searchResults {
+			b.WriteString(prefix(match.Server))
+			b.WriteString(m.searchRe.ReplaceAllStringFunc(match.Line, func(s string) string {
+				return matchStyle.Render(s)
+			}))
+			b.WriteString("\n")
+		}
+		m.viewport.SetContent(b.String())
+		return
+	}
+
+	for _, entry := range m.entries {
+		b.WriteString(prefix(entry.serverName))
+		b.WriteString(m.formatLogLine(entry.line))
+		b.WriteString("\n")
+	}
 
+	m.viewport.SetContent(b.String())
+}
+
+// serverPrefixer returns a function rendering the colored, aligned server
+// name that starts each line
+func (m *MultiLogViewerModel) serverPrefixer() func(string) string {
 	// Color palette for different servers
 	colors := []lipgloss.Color{
 		lipgloss.Color("39"),  // Blue
@@ -350,29 +520,20 @@ func (m *MultiLogViewerModel) updateViewport() {
 		maxNameLen = 15
 	}
 
-	for _, entry := range m.entries {
+	return func(serverName string) string {
 		// Server name prefix with color
-		color := serverColors[entry.serverName]
+		color := serverColors[serverName]
 		nameStyle := lipgloss.NewStyle().Foreground(color).Bold(true)
 
 		// Truncate and pad server name
-		name := entry.serverName
+		name := serverName
 		if len(name) > maxNameLen {
 			name = ansi.Truncate(name, maxNameLen, "…")
 		}
 		name = fmt.Sprintf("%-*s", maxNameLen, name)
 
-		prefix := nameStyle.Render(name) + " │ "
-
-		// Format the log line
-		line := m.formatLogLine(entry.line)
-
-		b.WriteString(prefix)
-		b.WriteString(line)
-		b.WriteString("\n")
+		return nameStyle.Render(name) + " │ "
 	}
-
-	m.viewport.SetContent(b.String())
 }
 
 // formatLogLine formats a log line with syntax highlighting
@@ -416,6 +577,13 @@ func (m *MultiLogViewerModel) View() string {
 		fmt.Sprintf("%d%%", scrollPercent),
 		fmt.Sprintf("auto-scroll: %s", autoScrollIndicator),
 	}
+	if m.searchQuery != "" {
+		statusParts[1] = fmt.Sprintf("/%s: %d matches", m.searchQuery, len(m.searchResults))
+		if m.searchRunning {
+			statusParts[1] = fmt.Sprintf("/%s: searching...", m.searchQuery)
+		}
+		statusParts = statusParts[:3]
+	}
 	status := lipgloss.NewStyle().
 		Foreground(mutedColor).
 		Render("  " + strings.Join(statusParts, "  │  "))
@@ -435,10 +603,21 @@ func (m *MultiLogViewerModel) View() string {
 	b.WriteString(separator)
 	b.WriteString("\n")
 
-	// Help
+	// Search input, or help
 	helpStyle := lipgloss.NewStyle().Foreground(mutedColor)
-	help := helpStyle.Render("  [a]auto-scroll  [↑↓/jk]scroll  [pgup/b]page up  [pgdn/f/space]page down  [g/G]top/bottom  [q/esc]back")
-	b.WriteString(help)
+	switch {
+	case m.searchEditing:
+		b.WriteString("  " + m.searchInput.View())
+		if m.queryErr != nil {
+			b.WriteString("  " + lipgloss.NewStyle().Foreground(errorColor).Render(m.queryErr.Error()))
+		}
+	case m.searchErr != nil:
+		b.WriteString(lipgloss.NewStyle().Foreground(errorColor).Render("  Search failed: " + m.searchErr.Error()))
+	case m.searchQuery != "":
+		b.WriteString(helpStyle.Render("  [/]new search  [↑↓/jk]scroll  [pgup/b]page up  [pgdn/f/space]page down  [g/G]top/bottom  [q/esc]clear search"))
+	default:
+		b.WriteString(helpStyle.Render("  [/]search  [a]auto-scroll  [↑↓/jk]scroll  [pgup/b]page up  [pgdn/f/space]page down  [g/G]top/bottom  [q/esc]back"))
+	}
 
 	return b.String()
 }
@@ -7,68 +7,119 @@ import (
 
 var (
 	// Colors - use shared styles package
-	primaryColor   = styles.Primary
+	primaryColor   lipgloss.Color
+	secondaryColor lipgloss.Color
+	warningColor   lipgloss.Color
+	errorColor     lipgloss.Color
+	mutedColor     lipgloss.Color
+
+	// Status colors
+	runningColor lipgloss.Color
+	stoppedColor lipgloss.Color
+	crashedColor lipgloss.Color
+	pausedColor  lipgloss.Color
+
+	// Health colors
+	healthyColor   lipgloss.Color
+	unhealthyColor lipgloss.Color
+	unknownColor   lipgloss.Color
+
+	// Styles
+	titleStyle         lipgloss.Style
+	statusRunningStyle lipgloss.Style
+	statusStoppedStyle lipgloss.Style
+	statusCrashedStyle lipgloss.Style
+	statusPausedStyle  lipgloss.Style
+	helpStyle          lipgloss.Style
+
+	// Health styles
+	healthyStyle   lipgloss.Style
+	unhealthyStyle lipgloss.Style
+	unknownStyle   lipgloss.Style
+
+	// Notification styles
+	notificationStyle        lipgloss.Style
+	errorNotificationStyle   lipgloss.Style
+	warningNotificationStyle lipgloss.Style
+
+	// Action panel style
+	actionPanelStyle lipgloss.Style
+)
+
+func init() {
+	buildStyles()
+	styles.OnChange(buildStyles)
+}
+
+// buildStyles builds the TUI's styles from the theme's colors
+func buildStyles() {
+	// Colors - use shared styles package
+	primaryColor = styles.Primary
 	secondaryColor = styles.Secondary
-	warningColor   = styles.Warning
-	errorColor     = styles.Error
-	mutedColor     = styles.Muted
+	warningColor = styles.Warning
+	errorColor = styles.Error
+	mutedColor = styles.Muted
 
 	// Status colors
 	runningColor = styles.Secondary
 	stoppedColor = styles.Muted
 	crashedColor = styles.Error
+	pausedColor = styles.Warning
 
 	// Health colors
-	healthyColor   = styles.Secondary
+	healthyColor = styles.Secondary
 	unhealthyColor = styles.Error
-	unknownColor   = styles.Muted
+	unknownColor = styles.Muted
 
 	// Styles
 	titleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(primaryColor).
-			MarginBottom(1)
+		Bold(true).
+		Foreground(primaryColor).
+		MarginBottom(1)
 
 	statusRunningStyle = lipgloss.NewStyle().
-				Foreground(runningColor)
+		Foreground(runningColor)
 
 	statusStoppedStyle = lipgloss.NewStyle().
-				Foreground(stoppedColor)
+		Foreground(stoppedColor)
 
 	statusCrashedStyle = lipgloss.NewStyle().
-				Foreground(crashedColor)
+		Foreground(crashedColor)
+
+	statusPausedStyle = lipgloss.NewStyle().
+		Foreground(pausedColor)
 
 	helpStyle = lipgloss.NewStyle().
-			Foreground(mutedColor).
-			MarginTop(1)
+		Foreground(mutedColor).
+		MarginTop(1)
 
 	// Health styles
 	healthyStyle = lipgloss.NewStyle().
-			Foreground(healthyColor)
+		Foreground(healthyColor)
 
 	unhealthyStyle = lipgloss.NewStyle().
-			Foreground(unhealthyColor)
+		Foreground(unhealthyColor)
 
 	unknownStyle = lipgloss.NewStyle().
-			Foreground(unknownColor)
+		Foreground(unknownColor)
 
 	// Notification styles
 	notificationStyle = lipgloss.NewStyle().
-				Foreground(secondaryColor).
-				Bold(true)
+		Foreground(secondaryColor).
+		Bold(true)
 
 	errorNotificationStyle = lipgloss.NewStyle().
-				Foreground(errorColor).
-				Bold(true)
+		Foreground(errorColor).
+		Bold(true)
 
 	warningNotificationStyle = lipgloss.NewStyle().
-					Foreground(warningColor).
-					Bold(true)
+		Foreground(warningColor).
+		Bold(true)
 
 	// Action panel style
 	actionPanelStyle = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(primaryColor).
-				Padding(0, 1).
-				MarginTop(1)
-)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(primaryColor).
+		Padding(0, 1).
+		MarginTop(1)
+}
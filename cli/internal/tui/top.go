@@ -0,0 +1,357 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/iheanyi/grove/internal/accesslog"
+	"github.com/iheanyi/grove/internal/port"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/usage"
+)
+
+// topRateWindow is how far back grove top averages request rates
+const topRateWindow = 5 * time.Second
+
+// Columns grove top sorts by
+const (
+	TopSortName     = "name"
+	TopSortCPU      = "cpu"
+	TopSortMemory   = "memory"
+	TopSortConns    = "conns"
+	TopSortRequests = "requests"
+)
+
+// TopSorts are the columns grove top can sort by
+var TopSorts = []string{TopSortCPU, TopSortMemory, TopSortConns, TopSortRequests, TopSortName}
+
+// TopOptions configures grove top
+type TopOptions struct {
+	// Interval is how often the view refreshes
+	Interval time.Duration
+	// Sort is the column sorted by, one of TopSorts
+	Sort string
+	// AccessLogDir holds the proxy's access logs, which request rates are
+	// read from; empty hides them
+	AccessLogDir string
+}
+
+// topRow is one server's line in grove top
+type topRow struct {
+	name     string
+	status   registry.ServerStatus
+	pid      int
+	port     int
+	usage    usage.Usage
+	hasUsage bool
+	conns    int
+	reqRate  float64
+}
+
+// topSampler gathers what grove top shows. Samples are taken one at a
+// time, each after the last is shown, so it isn't shared between them.
+type topSampler struct {
+	load         func(ctx context.Context) (*registry.Registry, error)
+	usage        *usage.Sampler
+	follower     *accesslog.Follower
+	accessLogDir string
+	requests     map[string][]time.Time // recent request times per server
+}
+
+// topSampledMsg carries a sample of the running servers
+type topSampledMsg struct {
+	rows []topRow
+	err  error
+}
+
+// topTickMsg triggers the next sample
+type topTickMsg time.Time
+
+// sample reads the running servers' usage, connections and request rates
+func (s *topSampler) sample() tea.Msg {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	reg, err := s.load(ctx)
+	if err != nil {
+		return topSampledMsg{err: err}
+	}
+	servers := reg.ListRunning()
+
+	pids := make(map[string]int)
+	ports := make([]int, 0, len(servers))
+	for _, server := range servers {
+		if server.PID > 0 {
+			pids[server.Name] = server.PID
+		}
+		ports = append(ports, server.Port)
+	}
+	s.usage.Sample(pids) //nolint:errcheck // Best effort, usage is just hidden
+	conns, _ := port.Connections(ports)
+
+	now := time.Now()
+	rows := make([]topRow, 0, len(servers))
+	for _, server := range servers {
+		row := topRow{
+			name:   server.Name,
+			status: server.Status,
+			pid:    server.PID,
+			port:   server.Port,
+			conns:  conns[server.Port],
+		}
+		row.usage, row.hasUsage = s.usage.Latest(server.Name)
+		if s.accessLogDir != "" {
+			row.reqRate = s.requestRate(server.Name, now)
+		}
+		rows = append(rows, row)
+	}
+	return topSampledMsg{rows: rows}
+}
+
+// requestRate returns a server's requests per second over the last
+// topRateWindow, from what's been appended to its access log
+func (s *topSampler) requestRate(name string, now time.Time) float64 {
+	entries, _ := s.follower.Read(accesslog.Path(s.accessLogDir, name))
+	times := s.requests[name]
+	for _, e := range entries {
+		times = append(times, e.Time)
+	}
+
+	cutoff := now.Add(-topRateWindow)
+	recent := times[:0]
+	for _, t := range times {
+		if t.After(cutoff) {
+			recent = append(recent, t)
+		}
+	}
+	s.requests[name] = recent
+	return float64(len(recent)) / topRateWindow.Seconds()
+}
+
+// TopModel is grove top: a live view of the resources and traffic of the
+// running servers
+type TopModel struct {
+	opts    TopOptions
+	sampler *topSampler
+
+	rows    []topRow
+	sortBy  string
+	reverse bool
+	err     error
+	ready   bool
+}
+
+// NewTop creates grove top, reading the registry with load
+func NewTop(opts TopOptions, load func(ctx context.Context) (*registry.Registry, error)) TopModel {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Second
+	}
+	if opts.Sort == "" {
+		opts.Sort = TopSortCPU
+	}
+	return TopModel{
+		opts:   opts,
+		sortBy: opts.Sort,
+		sampler: &topSampler{
+			load:         load,
+			usage:        usage.NewSampler(2),
+			follower:     accesslog.NewFollower(),
+			accessLogDir: opts.AccessLogDir,
+			requests:     make(map[string][]time.Time),
+		},
+	}
+}
+
+func (m TopModel) Init() tea.Cmd {
+	return m.sampler.sample
+}
+
+func (m TopModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		case "c":
+			m.sortOn(TopSortCPU)
+		case "m":
+			m.sortOn(TopSortMemory)
+		case "o":
+			m.sortOn(TopSortConns)
+		case "r":
+			m.sortOn(TopSortRequests)
+		case "n":
+			m.sortOn(TopSortName)
+		}
+		return m, nil
+
+	case topSampledMsg:
+		m.ready = true
+		m.err = msg.err
+		if msg.err == nil {
+			m.rows = msg.rows
+		}
+		return m, tea.Tick(m.opts.Interval, func(t time.Time) tea.Msg {
+			return topTickMsg(t)
+		})
+
+	case topTickMsg:
+		return m, m.sampler.sample
+	}
+	return m, nil
+}
+
+// sortOn sorts by a column, or flips the order when already sorted by it
+func (m *TopModel) sortOn(column string) {
+	if m.sortBy == column {
+		m.reverse = !m.reverse
+		return
+	}
+	m.sortBy = column
+	m.reverse = false
+}
+
+// sorted returns the rows in display order: names A-Z, everything else
+// highest first, unless reversed
+func (m TopModel) sorted() []topRow {
+	rows := append([]topRow(nil), m.rows...)
+	less := func(a, b topRow) bool {
+		switch m.sortBy {
+		case TopSortCPU:
+			return a.usage.CPU > b.usage.CPU
+		case TopSortMemory:
+			return a.usage.RSS > b.usage.RSS
+		case TopSortConns:
+			return a.conns > b.conns
+		case TopSortRequests:
+			return a.reqRate > b.reqRate
+		default:
+			return false
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if m.reverse {
+			a, b = b, a
+		}
+		if less(a, b) {
+			return true
+		}
+		if less(b, a) {
+			return false
+		}
+		return a.name < b.name
+	})
+	return rows
+}
+
+// topColumns are grove top's columns: title, width and the sort they show
+var topColumns = []struct {
+	title string
+	width int
+	sort  string
+}{
+	{"NAME", 32, TopSortName},
+	{"STATUS", 9, ""},
+	{"PID", 8, ""},
+	{"PORT", 6, ""},
+	{"CPU%", 7, TopSortCPU},
+	{"MEM", 10, TopSortMemory},
+	{"CONNS", 7, TopSortConns},
+	{"REQ/S", 7, TopSortRequests},
+}
+
+func (m TopModel) View() string {
+	if !m.ready {
+		return "\n  Sampling servers..."
+	}
+
+	var b strings.Builder
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(primaryColor)
+	mutedStyle := lipgloss.NewStyle().Foreground(mutedColor)
+
+	order := "↓"
+	if m.reverse {
+		order = "↑"
+	}
+	b.WriteString(headerStyle.Render("  grove top"))
+	b.WriteString(mutedStyle.Render(fmt.Sprintf("  %d running · every %s · sorted by %s %s", len(m.rows), m.opts.Interval, m.sortBy, order)))
+	b.WriteString("\n\n")
+
+	var header strings.Builder
+	for _, col := range topColumns {
+		title := col.title
+		if col.sort != "" && col.sort == m.sortBy {
+			title += order
+		}
+		header.WriteString(padRight(title, col.width))
+	}
+	b.WriteString("  " + lipgloss.NewStyle().Bold(true).Render(header.String()) + "\n")
+
+	if len(m.rows) == 0 {
+		b.WriteString(mutedStyle.Render("  No servers running") + "\n")
+	}
+	for _, row := range m.sorted() {
+		b.WriteString("  " + m.renderRow(row) + "\n")
+	}
+
+	if m.err != nil {
+		b.WriteString("\n  " + errorNotificationStyle.Render("Error: "+m.err.Error()) + "\n")
+	}
+	b.WriteString(helpStyle.Render("  c cpu · m memory · o connections · r req/s · n name (again to reverse) · q quit"))
+	return b.String()
+}
+
+func (m TopModel) renderRow(row topRow) string {
+	name := row.name
+	if len(name) > topColumns[0].width-2 {
+		name = name[:topColumns[0].width-3] + "…"
+	}
+
+	statusStyle := statusRunningStyle
+	if row.status == registry.StatusPaused {
+		statusStyle = statusPausedStyle
+	}
+
+	cpu, mem := "-", "-"
+	if row.hasUsage {
+		cpu = fmt.Sprintf("%.1f", row.usage.CPU)
+		mem = usage.FormatBytes(row.usage.RSS)
+	}
+	rate := "-"
+	if m.opts.AccessLogDir != "" {
+		rate = fmt.Sprintf("%.1f", row.reqRate)
+	}
+	pid := "-"
+	if row.pid > 0 {
+		pid = fmt.Sprint(row.pid)
+	}
+
+	cells := []string{
+		padRight(name, topColumns[0].width),
+		statusStyle.Render(padRight(string(row.status), topColumns[1].width)),
+		padRight(pid, topColumns[2].width),
+		padRight(fmt.Sprint(row.port), topColumns[3].width),
+		padRight(cpu, topColumns[4].width),
+		padRight(mem, topColumns[5].width),
+		padRight(fmt.Sprint(row.conns), topColumns[6].width),
+		padRight(rate, topColumns[7].width),
+	}
+	return strings.Join(cells, "")
+}
+
+// RunTop runs grove top until it's quit
+func RunTop(opts TopOptions, load func(ctx context.Context) (*registry.Registry, error)) error {
+	p := tea.NewProgram(NewTop(opts, load), tea.WithAltScreen())
+	_, err := p.Run()
+	return err
+}
+
+// padRight pads s with spaces to width runes, leaving at least one space
+func padRight(s string, width int) string {
+	return fmt.Sprintf("%-*s", width, s)
+}
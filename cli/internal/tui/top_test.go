@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/usage"
+)
+
+func topNames(rows []topRow) string {
+	names := make([]string, len(rows))
+	for i, row := range rows {
+		names[i] = row.name
+	}
+	return strings.Join(names, ",")
+}
+
+func TestTop_Sorting(t *testing.T) {
+	m := NewTop(TopOptions{}, func(context.Context) (*registry.Registry, error) { return nil, nil })
+	updated, _ := m.Update(topSampledMsg{rows: []topRow{
+		{name: "web", usage: usage.Usage{CPU: 5, RSS: 300}, conns: 1, reqRate: 4},
+		{name: "api", usage: usage.Usage{CPU: 40, RSS: 100}, conns: 9},
+		{name: "docs", usage: usage.Usage{CPU: 5, RSS: 200}, conns: 2, reqRate: 0.2},
+	}})
+	m = updated.(TopModel)
+
+	key := func(k string) {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+		m = updated.(TopModel)
+	}
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"", "api,docs,web"}, // CPU, ties by name
+		{"m", "web,docs,api"},
+		{"o", "api,docs,web"},
+		{"r", "web,docs,api"},
+		{"r", "api,docs,web"}, // again reverses
+		{"n", "api,docs,web"},
+	}
+	for _, tt := range tests {
+		if tt.key != "" {
+			key(tt.key)
+		}
+		if got := topNames(m.sorted()); got != tt.want {
+			t.Errorf("after %q: order = %s, want %s", tt.key, got, tt.want)
+		}
+	}
+
+	if view := m.View(); !strings.Contains(view, "3 running") || !strings.Contains(view, "docs") {
+		t.Errorf("View() = %q, want the servers listed", view)
+	}
+}
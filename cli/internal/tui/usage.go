@@ -0,0 +1,40 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/iheanyi/grove/internal/registry"
+	"github.com/iheanyi/grove/internal/usage"
+)
+
+// usageSamples is how many samples are kept per server for the sparkline
+const usageSamples = 20
+
+// UsageTicker returns a command that periodically triggers usage sampling
+func UsageTicker(interval time.Duration) tea.Cmd {
+	return tea.Tick(interval, func(t time.Time) tea.Msg {
+		return usageTickMsg(t)
+	})
+}
+
+// usageTickMsg is sent periodically to trigger usage sampling
+type usageTickMsg time.Time
+
+// UsageSampledMsg is sent when the running servers have been sampled
+type UsageSampledMsg struct{}
+
+// SampleUsageCmd samples the CPU and memory of the running servers in the
+// background, since it runs ps
+func SampleUsageCmd(sampler *usage.Sampler, servers []*registry.Server) tea.Cmd {
+	pids := make(map[string]int)
+	for _, server := range servers {
+		if server.PID > 0 {
+			pids[server.Name] = server.PID
+		}
+	}
+	return func() tea.Msg {
+		sampler.Sample(pids) //nolint:errcheck // Best effort, usage is just hidden
+		return UsageSampledMsg{}
+	}
+}
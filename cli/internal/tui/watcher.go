@@ -6,10 +6,14 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/fsnotify/fsnotify"
 	"github.com/iheanyi/grove/internal/config"
+	"github.com/iheanyi/grove/internal/registry"
 )
 
 // RegistryChangedMsg is sent when the registry file changes
-type RegistryChangedMsg struct{}
+type RegistryChangedMsg struct {
+	// Change is what changed, when known
+	Change registry.Change
+}
 
 // registryWatcher is a persistent watcher shared across WatchRegistry calls.
 // This avoids the overhead of creating and destroying an fsnotify watcher
@@ -28,8 +32,13 @@ func WatchRegistry() tea.Cmd {
 			}
 			registryWatcher = w
 
-			registryPath := config.RegistryPath()
-			if err := registryWatcher.Add(registryPath); err != nil {
+			watching := false
+			for _, path := range registry.Files() {
+				if registryWatcher.Add(path) == nil {
+					watching = true
+				}
+			}
+			if !watching {
 				// If registry doesn't exist yet, watch the config dir
 				configDir := config.ConfigDir()
 				if err := registryWatcher.Add(configDir); err != nil {
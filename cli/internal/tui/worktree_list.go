@@ -16,13 +16,17 @@ import (
 type WorktreeItem struct {
 	worktree *discovery.Worktree
 	server   *registry.Server
+	// tags are the worktree's tags, which it can have without a server
+	tags []string
 }
 
 // Title returns plain text with status icon prefix
 func (i WorktreeItem) Title() string {
 	statusIcon := "○"
 	if i.server != nil {
-		if i.server.IsRunning() {
+		if i.server.IsPaused() {
+			statusIcon = "⏸"
+		} else if i.server.IsRunning() {
 			statusIcon = "●"
 		} else if i.server.Status == registry.StatusCrashed {
 			statusIcon = "✗"
@@ -67,11 +71,18 @@ func (i WorktreeItem) Description() string {
 		parts = append(parts, "no server")
 	}
 
+	if len(i.tags) > 0 {
+		parts = append(parts, registry.FormatTags(i.tags))
+	}
+
 	return strings.Join(parts, "  |  ")
 }
 
 func (i WorktreeItem) FilterValue() string {
-	return i.worktree.Name
+	if len(i.tags) == 0 {
+		return i.worktree.Name
+	}
+	return i.worktree.Name + " " + registry.FormatTags(i.tags)
 }
 
 // StatusIcon returns the status icon for display
@@ -79,7 +90,9 @@ func (i WorktreeItem) StatusIcon() string {
 	if i.server == nil {
 		return "○"
 	}
-	if i.server.IsRunning() {
+	if i.server.IsPaused() {
+		return "⏸"
+	} else if i.server.IsRunning() {
 		return "●"
 	} else if i.server.Status == registry.StatusCrashed {
 		return "✗"
@@ -92,7 +105,9 @@ func (i WorktreeItem) StatusStyle() lipgloss.Style {
 	if i.server == nil {
 		return statusStoppedStyle
 	}
-	if i.server.IsRunning() {
+	if i.server.IsPaused() {
+		return statusPausedStyle
+	} else if i.server.IsRunning() {
 		return statusRunningStyle
 	} else if i.server.Status == registry.StatusCrashed {
 		return statusCrashedStyle
@@ -169,16 +184,15 @@ func NewWorktreeList(reg *registry.Registry, worktrees []*discovery.Worktree) *W
 func makeWorktreeItems(reg *registry.Registry, worktrees []*discovery.Worktree) []list.Item {
 	items := make([]list.Item, len(worktrees))
 	for i, wt := range worktrees {
-		// Find associated server if exists
-		var server *registry.Server
-		if s, ok := reg.Get(wt.Name); ok {
-			server = s
-		}
-
-		items[i] = WorktreeItem{
-			worktree: wt,
-			server:   server,
+		item := WorktreeItem{worktree: wt}
+		if ws, ok := reg.GetWorkspace(wt.Name); ok {
+			item.tags = ws.Tags
+			// Find associated server if exists
+			if ws.Server != nil {
+				item.server = ws.ToServer()
+			}
 		}
+		items[i] = item
 	}
 	return items
 }
@@ -0,0 +1,30 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iheanyi/grove/internal/discovery"
+	"github.com/iheanyi/grove/internal/registry"
+)
+
+func TestMakeWorktreeItems_Tags(t *testing.T) {
+	reg, err := registry.LoadFrom(filepath.Join(t.TempDir(), "registry.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A worktree tagged without ever running a server
+	if err := reg.SetWorkspace(&registry.Workspace{Name: "feature", Path: "/src/feature", Tags: []string{"billing"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	items := makeWorktreeItems(reg, []*discovery.Worktree{{Name: "feature", Path: "/src/feature"}})
+	item := items[0].(WorktreeItem)
+	if !strings.Contains(item.FilterValue(), "billing") {
+		t.Errorf("FilterValue() = %q, want the worktree's tags", item.FilterValue())
+	}
+	if desc := item.Description(); !strings.Contains(desc, "no server") || !strings.Contains(desc, "billing") {
+		t.Errorf("Description() = %q, want no server and the worktree's tags", desc)
+	}
+}
@@ -0,0 +1,212 @@
+// Package tunnel opens tunnels to local servers for 'grove share': public
+// ones with cloudflared, Tailscale Funnel, ngrok or a custom command, and
+// tailnet-only ones with Tailscale Serve.
+package tunnel
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Provider opens tunnels with one tool. The tunnel runs in the foreground
+// and prints its public URL, which URL finds in its output.
+type Provider struct {
+	Name string
+
+	// Command returns the command that tunnels to a local port
+	Command func(port int) []string
+
+	// URL matches the public URL in the tool's output; its first group, if
+	// any, is the URL
+	URL *regexp.Regexp
+}
+
+// Providers are the built-in providers, in the order they're tried when
+// none is configured
+var Providers = []*Provider{
+	{
+		Name: "cloudflared",
+		Command: func(port int) []string {
+			return []string{"cloudflared", "tunnel", "--no-autoupdate", "--url", "http://localhost:" + strconv.Itoa(port)}
+		},
+		URL: regexp.MustCompile(`https://[a-z0-9-]+\.trycloudflare\.com`),
+	},
+	{
+		Name: "tailscale",
+		Command: func(port int) []string {
+			return []string{"tailscale", "funnel", strconv.Itoa(port)}
+		},
+		URL: tailscaleURL,
+	},
+	{
+		Name: "ngrok",
+		Command: func(port int) []string {
+			return []string{"ngrok", "http", strconv.Itoa(port), "--log", "stdout", "--log-format", "logfmt"}
+		},
+		URL: regexp.MustCompile(`url=(https://\S+)`),
+	},
+}
+
+// TailscaleServe shares a server within the tailnet only, at the
+// machine's MagicDNS name on an HTTPS port matching the server's
+var TailscaleServe = &Provider{
+	Name: "tailscale-serve",
+	Command: func(port int) []string {
+		return []string{"tailscale", "serve", "--https=" + strconv.Itoa(port), strconv.Itoa(port)}
+	},
+	URL: tailscaleURL,
+}
+
+// tailscaleURL matches a MagicDNS URL, e.g. https://laptop.tail1234.ts.net:3000/
+var tailscaleURL = regexp.MustCompile(`https://[^\s/:]+\.ts\.net(?::\d+)?/?`)
+
+// anyURL matches the public URL printed by a custom command
+var anyURL = regexp.MustCompile(`https://[^\s"'<>]+`)
+
+// Custom returns a provider that runs a shell command, with {port} replaced
+// by the local port. The first public https URL it prints is used.
+func Custom(command string) *Provider {
+	return &Provider{
+		Name: "custom",
+		Command: func(port int) []string {
+			return []string{"sh", "-c", strings.ReplaceAll(command, "{port}", strconv.Itoa(port))}
+		},
+		URL: anyURL,
+	}
+}
+
+// Find returns the named provider, or the first installed one when name is
+// empty
+func Find(name string) (*Provider, error) {
+	if name == TailscaleServe.Name {
+		if !TailscaleServe.Available() {
+			return nil, fmt.Errorf("tailscale not found in PATH")
+		}
+		return TailscaleServe, nil
+	}
+	for _, p := range Providers {
+		if name == "" && p.Available() {
+			return p, nil
+		}
+		if p.Name == name {
+			if !p.Available() {
+				return nil, fmt.Errorf("%s not found in PATH", p.Command(0)[0])
+			}
+			return p, nil
+		}
+	}
+	if name != "" {
+		return nil, fmt.Errorf("unknown tunnel provider '%s' (use cloudflared, tailscale, tailscale-serve or ngrok)", name)
+	}
+	return nil, fmt.Errorf("no tunnel tool found: install cloudflared, tailscale or ngrok")
+}
+
+// Available reports whether the provider's tool is installed
+func (p *Provider) Available() bool {
+	_, err := exec.LookPath(p.Command(0)[0])
+	return err == nil
+}
+
+// FindURL returns the public URL in a tool's output, or ""
+func (p *Provider) FindURL(output string) string {
+	for _, m := range p.URL.FindAllStringSubmatch(output, -1) {
+		url := m[0]
+		if len(m) > 1 {
+			url = m[1]
+		}
+		if !strings.Contains(url, "localhost") && !strings.Contains(url, "127.0.0.1") {
+			return url
+		}
+	}
+	return ""
+}
+
+// Tunnel is a running tunnel
+type Tunnel struct {
+	PID int
+	URL string
+}
+
+// Start runs a tunnel to a local port in the background, logging to
+// logFile, and waits up to timeout for its public URL. The tunnel keeps
+// running after grove exits; end it with Stop.
+func Start(p *Provider, port int, logFile string, timeout time.Duration) (*Tunnel, error) {
+	log, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create log file: %w", err)
+	}
+	defer log.Close()
+
+	args := p.Command(port)
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdout = log
+	cmd.Stderr = log
+	// Its own process group, so it survives grove exiting and Stop ends
+	// anything it spawned
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s: %w", p.Name, err)
+	}
+
+	exited := make(chan struct{})
+	go func() {
+		cmd.Wait() //nolint:errcheck // Exit is reported through the log
+		close(exited)
+	}()
+
+	deadline := time.After(timeout)
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		data, _ := os.ReadFile(logFile)
+		if url := p.FindURL(string(data)); url != "" {
+			return &Tunnel{PID: cmd.Process.Pid, URL: url}, nil
+		}
+
+		select {
+		case <-exited:
+			return nil, fmt.Errorf("%s exited: %s", p.Name, lastLines(logFile, 5))
+		case <-deadline:
+			Stop(cmd.Process.Pid)
+			return nil, fmt.Errorf("%s didn't print a public URL within %s (see %s)", p.Name, timeout, logFile)
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop ends a tunnel started by Start
+func Stop(pid int) error {
+	if pid <= 0 {
+		return nil
+	}
+	if err := syscall.Kill(-pid, syscall.SIGTERM); err != nil && err != syscall.ESRCH {
+		return err
+	}
+	return nil
+}
+
+// IsRunning reports whether a tunnel's process is alive
+func IsRunning(pid int) bool {
+	return pid > 0 && syscall.Kill(pid, 0) == nil
+}
+
+func lastLines(path string, n int) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "no output"
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	if len(lines) == 1 && lines[0] == "" {
+		return "no output"
+	}
+	return strings.Join(lines, "\n")
+}
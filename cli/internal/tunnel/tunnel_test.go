@@ -0,0 +1,99 @@
+package tunnel
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFindURL(t *testing.T) {
+	tests := []struct {
+		provider string
+		output   string
+		want     string
+	}{
+		{
+			provider: "cloudflared",
+			output: `2024-01-01T00:00:00Z INF Requesting new quick Tunnel on trycloudflare.com...
+2024-01-01T00:00:01Z INF |  https://quiet-river-example.trycloudflare.com  |`,
+			want: "https://quiet-river-example.trycloudflare.com",
+		},
+		{
+			provider: "tailscale",
+			output:   "Available on the internet:\n\nhttps://laptop.tail1234.ts.net/\n|-- / proxy http://127.0.0.1:3000\n",
+			want:     "https://laptop.tail1234.ts.net/",
+		},
+		{
+			provider: "tailscale-serve",
+			output:   "Available within your tailnet:\n\nhttps://laptop.tail1234.ts.net:3042/\n|-- proxy http://127.0.0.1:3042\n",
+			want:     "https://laptop.tail1234.ts.net:3042/",
+		},
+		{
+			provider: "ngrok",
+			output:   `t=2024-01-01T00:00:00 lvl=info msg="started tunnel" obj=tunnels name=command_line addr=http://localhost:3000 url=https://abcd-1234.ngrok-free.app`,
+			want:     "https://abcd-1234.ngrok-free.app",
+		},
+		{
+			provider: "cloudflared",
+			output:   "INF Starting tunnel",
+			want:     "",
+		},
+	}
+
+	for _, tt := range tests {
+		var p *Provider
+		for _, candidate := range append(Providers, TailscaleServe) {
+			if candidate.Name == tt.provider {
+				p = candidate
+			}
+		}
+		if got := p.FindURL(tt.output); got != tt.want {
+			t.Errorf("%s: FindURL() = %q, want %q", tt.provider, got, tt.want)
+		}
+	}
+}
+
+func TestCustomSkipsLocalURLs(t *testing.T) {
+	p := Custom("bore local {port} --to bore.pub")
+	if got := p.Command(3000); got[2] != "bore local 3000 --to bore.pub" {
+		t.Errorf("Command() = %v", got)
+	}
+	if got := p.FindURL("forwarding https://localhost:3000 to https://abc.bore.pub"); got != "https://abc.bore.pub" {
+		t.Errorf("FindURL() = %q, want https://abc.bore.pub", got)
+	}
+}
+
+func TestStartAndStop(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "share.log")
+	p := Custom("echo listening on port {port}; echo https://demo.example.com; sleep 30")
+
+	tun, err := Start(p, 3000, logFile, 5*time.Second)
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if tun.URL != "https://demo.example.com" {
+		t.Errorf("URL = %q, want https://demo.example.com", tun.URL)
+	}
+	if !IsRunning(tun.PID) {
+		t.Fatal("tunnel should be running")
+	}
+
+	if err := Stop(tun.PID); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	for i := 0; i < 50 && IsRunning(tun.PID); i++ {
+		time.Sleep(100 * time.Millisecond)
+	}
+	if IsRunning(tun.PID) {
+		t.Error("tunnel should have stopped")
+	}
+}
+
+func TestStartFailsWhenToolExits(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "share.log")
+	_, err := Start(Custom("echo authentication failed; exit 1"), 3000, logFile, 5*time.Second)
+	if err == nil || !strings.Contains(err.Error(), "authentication failed") {
+		t.Errorf("Start() error = %v, want the tool's output", err)
+	}
+}
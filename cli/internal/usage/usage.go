@@ -0,0 +1,254 @@
+// Package usage samples the CPU and memory used by dev servers, including
+// the processes they spawn.
+package usage
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iheanyi/grove/internal/execx"
+)
+
+// Usage is the resources used by a process and its descendants
+type Usage struct {
+	// CPU is the percentage of one core
+	CPU float64 `json:"cpu_percent"`
+
+	// RSS is the resident memory in bytes
+	RSS uint64 `json:"rss_bytes"`
+}
+
+// String formats the usage for display, e.g. "3.2% CPU, 152 MB"
+func (u Usage) String() string {
+	return fmt.Sprintf("%.1f%% CPU, %s", u.CPU, FormatBytes(u.RSS))
+}
+
+type process struct {
+	ppid    int
+	rss     uint64
+	cpu     float64
+	cpuTime time.Duration
+}
+
+// Snapshot is the process table at one point in time
+type Snapshot struct {
+	Time     time.Time
+	procs    map[int]process
+	children map[int][]int
+}
+
+// Take reads the process table with ps
+func Take() (*Snapshot, error) {
+	out, err := execx.OS.Output(context.Background(), execx.Query("ps", "-e", "-o", "pid=,ppid=,rss=,pcpu=,time="))
+	if err != nil {
+		return nil, fmt.Errorf("failed to run ps: %w", err)
+	}
+	return parse(string(out), time.Now()), nil
+}
+
+// parse reads ps output with pid, ppid, rss (KB), pcpu and time columns
+func parse(out string, at time.Time) *Snapshot {
+	s := &Snapshot{Time: at, procs: make(map[int]process), children: make(map[int][]int)}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 5 {
+			continue
+		}
+		pid, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		ppid, _ := strconv.Atoi(fields[1])
+		rss, _ := strconv.ParseUint(fields[2], 10, 64)
+		cpu, _ := strconv.ParseFloat(fields[3], 64)
+
+		s.procs[pid] = process{ppid: ppid, rss: rss * 1024, cpu: cpu, cpuTime: parseCPUTime(fields[4])}
+		s.children[ppid] = append(s.children[ppid], pid)
+	}
+	return s
+}
+
+// parseCPUTime parses ps's cumulative CPU time: [DD-]HH:MM:SS on Linux,
+// MM:SS.ss on macOS
+func parseCPUTime(s string) time.Duration {
+	var days int
+	if d, rest, ok := strings.Cut(s, "-"); ok {
+		days, _ = strconv.Atoi(d)
+		s = rest
+	}
+
+	var seconds float64
+	for _, part := range strings.Split(s, ":") {
+		v, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return 0
+		}
+		seconds = seconds*60 + v
+	}
+	return time.Duration((float64(days)*86400 + seconds) * float64(time.Second))
+}
+
+// tree returns pid and its descendants
+func (s *Snapshot) tree(pid int) []int {
+	if _, ok := s.procs[pid]; !ok {
+		return nil
+	}
+	pids := []int{pid}
+	for i := 0; i < len(pids); i++ {
+		pids = append(pids, s.children[pids[i]]...)
+	}
+	return pids
+}
+
+// Tree returns the usage of pid and its descendants, with CPU as ps reports
+// it (averaged over each process's lifetime on Linux)
+func (s *Snapshot) Tree(pid int) (Usage, bool) {
+	pids := s.tree(pid)
+	if len(pids) == 0 {
+		return Usage{}, false
+	}
+	var u Usage
+	for _, p := range pids {
+		u.RSS += s.procs[p].rss
+		u.CPU += s.procs[p].cpu
+	}
+	return u, true
+}
+
+// cpuTime returns the CPU time used by pid and its descendants
+func (s *Snapshot) cpuTime(pid int) time.Duration {
+	var total time.Duration
+	for _, p := range s.tree(pid) {
+		total += s.procs[p].cpuTime
+	}
+	return total
+}
+
+// Sampler records the usage of servers over time, keeping the last few
+// samples of each for sparklines. CPU is measured between samples.
+type Sampler struct {
+	mu      sync.Mutex
+	size    int
+	prev    *Snapshot
+	history map[string][]Usage
+}
+
+// NewSampler creates a sampler that keeps size samples per server
+func NewSampler(size int) *Sampler {
+	return &Sampler{size: size, history: make(map[string][]Usage)}
+}
+
+// Sample takes a snapshot and records the usage of each server, given as
+// name -> PID. Servers that aren't in pids are forgotten.
+func (s *Sampler) Sample(pids map[string]int) error {
+	snap, err := Take()
+	if err != nil {
+		return err
+	}
+	s.record(snap, pids)
+	return nil
+}
+
+func (s *Sampler) record(snap *Snapshot, pids map[string]int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for name := range s.history {
+		if _, ok := pids[name]; !ok {
+			delete(s.history, name)
+		}
+	}
+
+	for name, pid := range pids {
+		u, ok := snap.Tree(pid)
+		if !ok {
+			delete(s.history, name)
+			continue
+		}
+
+		// Measure CPU since the last sample rather than ps's average
+		if s.prev != nil {
+			if elapsed := snap.Time.Sub(s.prev.Time); elapsed > 0 {
+				delta := snap.cpuTime(pid) - s.prev.cpuTime(pid)
+				u.CPU = max(0, float64(delta)/float64(elapsed)*100)
+			}
+		}
+
+		h := append(s.history[name], u)
+		if len(h) > s.size {
+			h = h[len(h)-s.size:]
+		}
+		s.history[name] = h
+	}
+	s.prev = snap
+}
+
+// Latest returns a server's most recent sample
+func (s *Sampler) Latest(name string) (Usage, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h := s.history[name]
+	if len(h) == 0 {
+		return Usage{}, false
+	}
+	return h[len(h)-1], true
+}
+
+// History returns a server's samples, oldest first
+func (s *Sampler) History(name string) []Usage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Usage(nil), s.history[name]...)
+}
+
+// CPUHistory returns the CPU percentages of samples, for Sparkline
+func CPUHistory(samples []Usage) []float64 {
+	values := make([]float64, len(samples))
+	for i, u := range samples {
+		values[i] = u.CPU
+	}
+	return values
+}
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders values as block characters scaled to the largest
+func Sparkline(values []float64) string {
+	var peak float64
+	for _, v := range values {
+		peak = max(peak, v)
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		i := 0
+		if peak > 0 {
+			i = int(v / peak * float64(len(sparkBlocks)-1))
+		}
+		b.WriteRune(sparkBlocks[i])
+	}
+	return b.String()
+}
+
+// FormatBytes formats a byte count, e.g. "152 MB" or "1.2 GB"
+func FormatBytes(b uint64) string {
+	const (
+		kb = 1024
+		mb = 1024 * kb
+		gb = 1024 * mb
+	)
+	switch {
+	case b >= gb:
+		return fmt.Sprintf("%.1f GB", float64(b)/gb)
+	case b >= mb:
+		return fmt.Sprintf("%d MB", b/mb)
+	default:
+		return fmt.Sprintf("%d KB", b/kb)
+	}
+}
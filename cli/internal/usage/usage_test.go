@@ -0,0 +1,83 @@
+package usage
+
+import (
+	"testing"
+	"time"
+)
+
+const psOutput = `    1     0  9216  0.2 00:00:11
+  100     1  2048  1.5 00:01:00
+  101   100  1024  2.5 00:00:30
+  102   101   512  0.0 00:00:01
+  200     1  4096 10.0 1-00:00:00
+`
+
+func TestSnapshotTree(t *testing.T) {
+	snap := parse(psOutput, time.Now())
+
+	u, ok := snap.Tree(100)
+	if !ok {
+		t.Fatal("Tree(100) not found")
+	}
+	if want := uint64((2048 + 1024 + 512) * 1024); u.RSS != want {
+		t.Errorf("RSS = %d, want %d", u.RSS, want)
+	}
+	if u.CPU != 4.0 {
+		t.Errorf("CPU = %v, want 4.0", u.CPU)
+	}
+	if got := snap.cpuTime(100); got != 91*time.Second {
+		t.Errorf("cpuTime(100) = %v, want 1m31s", got)
+	}
+	if _, ok := snap.Tree(999); ok {
+		t.Error("Tree(999) should not be found")
+	}
+}
+
+func TestParseCPUTime(t *testing.T) {
+	for in, want := range map[string]time.Duration{
+		"00:01:30":   90 * time.Second,
+		"1-02:00:00": 26 * time.Hour,
+		"0:01.50":    1500 * time.Millisecond,
+		"12:03.00":   12*time.Minute + 3*time.Second,
+		"bogus":      0,
+	} {
+		if got := parseCPUTime(in); got != want {
+			t.Errorf("parseCPUTime(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestSamplerMeasuresCPUBetweenSamples(t *testing.T) {
+	s := NewSampler(2)
+	start := time.Now()
+	pids := map[string]int{"feature": 100}
+
+	s.record(parse("100 1 1024 50.0 00:00:10\n", start), pids)
+	s.record(parse("100 1 1024 50.0 00:00:11\n", start.Add(2*time.Second)), pids)
+	s.record(parse("100 1 2048 50.0 00:00:11\n", start.Add(4*time.Second)), pids)
+
+	h := s.History("feature")
+	if len(h) != 2 {
+		t.Fatalf("expected ring buffer of 2, got %d samples", len(h))
+	}
+	if h[0].CPU != 50 || h[1].CPU != 0 {
+		t.Errorf("CPU history = %v, want [50 0]", CPUHistory(h))
+	}
+	if latest, _ := s.Latest("feature"); latest.RSS != 2048*1024 {
+		t.Errorf("latest RSS = %d", latest.RSS)
+	}
+
+	s.record(parse("", start.Add(6*time.Second)), map[string]int{})
+	if _, ok := s.Latest("feature"); ok {
+		t.Error("stopped server should be forgotten")
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	if got := Sparkline([]float64{0, 50, 100}); got != "▁▄█" {
+		t.Errorf("Sparkline = %q", got)
+	}
+	if got := Sparkline([]float64{0, 0}); got != "▁▁" {
+		t.Errorf("Sparkline of zeros = %q", got)
+	}
+}
@@ -0,0 +1,138 @@
+package worktree
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Changes returns a worktree's uncommitted changes as a binary patch against
+// HEAD: staged and unstaged edits, deletions and untracked files (except
+// ignored ones). The worktree and its index aren't modified.
+func Changes(path string) ([]byte, error) {
+	// Stage everything in a throwaway index so untracked files are included
+	index, err := os.CreateTemp("", "grove-index-*")
+	if err != nil {
+		return nil, err
+	}
+	index.Close()
+	defer os.Remove(index.Name())
+
+	env := append(os.Environ(), "GIT_INDEX_FILE="+index.Name())
+	for _, args := range [][]string{{"read-tree", "HEAD"}, {"add", "-A"}} {
+		if _, err := gitEnv(path, env, nil, args...); err != nil {
+			return nil, err
+		}
+	}
+	return gitEnv(path, env, nil, "diff", "--cached", "--binary", "HEAD")
+}
+
+// ChangedFiles returns the files a patch touches
+func ChangedFiles(patch []byte) []string {
+	var files []string
+	for _, line := range strings.Split(string(patch), "\n") {
+		if rest, ok := strings.CutPrefix(line, "diff --git a/"); ok {
+			if i := strings.Index(rest, " b/"); i >= 0 {
+				files = append(files, rest[:i])
+			}
+		}
+	}
+	return files
+}
+
+var patchFailed = regexp.MustCompile(`(?m)error: (?:patch failed: (.+):\d+|(.+): (?:already exists in working directory|does not exist in index|No such file or directory))$`)
+
+// CheckPatch returns the files of a patch that don't apply cleanly to a
+// worktree
+func CheckPatch(path string, patch []byte) ([]string, error) {
+	_, err := gitEnv(path, nil, patch, "apply", "--check", "--binary", "-")
+	if err == nil {
+		return nil, nil
+	}
+
+	seen := make(map[string]bool)
+	for _, m := range patchFailed.FindAllStringSubmatch(err.Error(), -1) {
+		seen[m[1]+m[2]] = true
+	}
+	if len(seen) == 0 {
+		return nil, err
+	}
+	files := make([]string, 0, len(seen))
+	for f := range seen {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// ApplyPatch applies a patch to a worktree's files. With threeWay, hunks
+// that don't apply are merged and left with conflict markers, and the
+// conflicted files are returned. A 3-way apply needs the index to match the
+// files, so their local changes are staged first.
+func ApplyPatch(path string, patch []byte, threeWay bool) ([]string, error) {
+	args := []string{"apply", "--binary"}
+	if threeWay {
+		if err := stage(path, ChangedFiles(patch)); err != nil {
+			return nil, err
+		}
+		args = append(args, "--3way")
+	}
+	if _, err := gitEnv(path, nil, patch, append(args, "-")...); err != nil {
+		if !threeWay {
+			return nil, err
+		}
+		out, diffErr := gitEnv(path, nil, nil, "diff", "--name-only", "--diff-filter=U")
+		if diffErr != nil || len(bytes.TrimSpace(out)) == 0 {
+			return nil, err
+		}
+		return strings.Fields(string(out)), nil
+	}
+	return nil, nil
+}
+
+// stage stages the files that exist in a worktree or its index; git add
+// fails on paths that are in neither
+func stage(path string, files []string) error {
+	tracked, err := gitEnv(path, nil, nil, append([]string{"ls-files", "-z", "--"}, files...)...)
+	if err != nil {
+		return err
+	}
+	known := make(map[string]bool)
+	for _, f := range strings.Split(string(tracked), "\x00") {
+		known[f] = true
+	}
+
+	args := []string{"add", "-A", "--"}
+	for _, f := range files {
+		if _, err := os.Lstat(filepath.Join(path, f)); err == nil || known[f] {
+			args = append(args, f)
+		}
+	}
+	if len(args) == 3 {
+		return nil
+	}
+	_, err = gitEnv(path, nil, nil, args...)
+	return err
+}
+
+// gitEnv runs git in dir with an optional environment and stdin, returning
+// stdout. Errors include stderr.
+func gitEnv(dir string, env []string, stdin []byte, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = env
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return out, fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
@@ -0,0 +1,103 @@
+package worktree
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func gitRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	run(t, dir, "init", "-q")
+	writeFile(t, dir, "a.txt", "one\n")
+	writeFile(t, dir, "b.txt", "two\n")
+	run(t, dir, "add", ".")
+	run(t, dir, "-c", "user.name=t", "-c", "user.email=t@t", "commit", "-q", "-m", "init")
+	return dir
+}
+
+func run(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	if out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMoveChanges(t *testing.T) {
+	src := gitRepo(t)
+	dst := filepath.Join(t.TempDir(), "dst")
+	run(t, src, "worktree", "add", "-q", "-b", "other", dst)
+
+	writeFile(t, src, "a.txt", "one changed\n")
+	writeFile(t, src, "new.txt", "untracked\n")
+	os.Remove(filepath.Join(src, "b.txt"))
+
+	patch, err := Changes(src)
+	if err != nil {
+		t.Fatalf("Changes() error = %v", err)
+	}
+	if got, want := ChangedFiles(patch), []string{"a.txt", "b.txt", "new.txt"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ChangedFiles() = %v, want %v", got, want)
+	}
+
+	// The source's index is left alone
+	out, _ := exec.Command("git", "-C", src, "status", "--porcelain").Output()
+	if string(out) != " M a.txt\n D b.txt\n?? new.txt\n" {
+		t.Errorf("source status changed:\n%s", out)
+	}
+
+	if failed, err := CheckPatch(dst, patch); err != nil || len(failed) > 0 {
+		t.Fatalf("CheckPatch() = %v, %v", failed, err)
+	}
+	if _, err := ApplyPatch(dst, patch, false); err != nil {
+		t.Fatalf("ApplyPatch() error = %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dst, "new.txt")); string(data) != "untracked\n" {
+		t.Errorf("new.txt = %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "b.txt")); !os.IsNotExist(err) {
+		t.Errorf("b.txt should be deleted")
+	}
+}
+
+func TestCheckPatchConflict(t *testing.T) {
+	src := gitRepo(t)
+	dst := filepath.Join(t.TempDir(), "dst")
+	run(t, src, "worktree", "add", "-q", "-b", "other", dst)
+
+	writeFile(t, src, "a.txt", "from src\n")
+	writeFile(t, dst, "a.txt", "from dst\n")
+
+	patch, err := Changes(src)
+	if err != nil {
+		t.Fatalf("Changes() error = %v", err)
+	}
+	failed, err := CheckPatch(dst, patch)
+	if err != nil {
+		t.Fatalf("CheckPatch() error = %v", err)
+	}
+	if !reflect.DeepEqual(failed, []string{"a.txt"}) {
+		t.Errorf("CheckPatch() = %v, want [a.txt]", failed)
+	}
+
+	conflicts, err := ApplyPatch(dst, patch, true)
+	if err != nil {
+		t.Fatalf("ApplyPatch(3way) error = %v", err)
+	}
+	if !reflect.DeepEqual(conflicts, []string{"a.txt"}) {
+		t.Errorf("ApplyPatch(3way) conflicts = %v, want [a.txt]", conflicts)
+	}
+}
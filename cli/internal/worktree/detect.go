@@ -1,11 +1,13 @@
 package worktree
 
 import (
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"github.com/iheanyi/grove/internal/execx"
 )
 
 // Info contains information about the current worktree/repository
@@ -24,6 +26,9 @@ type Info struct {
 
 	// MainWorktreePath is the path to the main worktree (if this is a linked worktree)
 	MainWorktreePath string
+
+	// Repo identifies the repository (see RepoID)
+	Repo string
 }
 
 // Detect detects the current git worktree/repository information
@@ -40,18 +45,18 @@ func DetectAt(path string) (*Info, error) {
 
 	// Use git commands for better worktree support
 	// Get the top-level directory of the worktree
-	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
+	cmd := execx.Query("git", "rev-parse", "--show-toplevel")
 	cmd.Dir = absPath
-	output, err := cmd.Output()
+	output, err := execx.OS.Output(context.Background(), cmd)
 	if err != nil {
 		return nil, fmt.Errorf("not a git repository: %w", err)
 	}
 	wtPath := strings.TrimSpace(string(output))
 
 	// Get current branch name
-	cmd = exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
+	cmd = execx.Query("git", "rev-parse", "--abbrev-ref", "HEAD")
 	cmd.Dir = absPath
-	output, err = cmd.Output()
+	output, err = execx.OS.Output(context.Background(), cmd)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get branch: %w", err)
 	}
@@ -60,9 +65,9 @@ func DetectAt(path string) (*Info, error) {
 	// Handle detached HEAD state
 	if branch == "HEAD" {
 		// Try to get a more descriptive name
-		cmd = exec.Command("git", "describe", "--tags", "--always")
+		cmd = execx.Query("git", "describe", "--tags", "--always")
 		cmd.Dir = absPath
-		output, err = cmd.Output()
+		output, err = execx.OS.Output(context.Background(), cmd)
 		if err == nil {
 			branch = strings.TrimSpace(string(output))
 		}
@@ -72,15 +77,19 @@ func DetectAt(path string) (*Info, error) {
 	isWorktree, mainPath := detectLinkedWorktree(wtPath)
 
 	// Determine the name:
-	// - For linked worktrees: use the sanitized branch name
+	// - For linked worktrees: the name template (by default the branch, e.g. "feature-auth")
 	// - For main working tree: use the directory name (more intuitive for standalone repos)
+	repoPath := wtPath
+	if mainPath != "" {
+		repoPath = mainPath
+	}
+	repo := RepoID(wtPath, repoPath)
+
 	var name string
 	if isWorktree {
-		// Linked worktree: use branch name (e.g., "feature-auth")
-		name = Sanitize(branch)
+		name = NameFor(mainPath, branch, wtPath)
 	} else {
-		// Main working tree: use directory name (e.g., "fade-pics", "myapp")
-		name = Sanitize(filepath.Base(wtPath))
+		name = MainName(wtPath)
 	}
 
 	info := &Info{
@@ -89,6 +98,7 @@ func DetectAt(path string) (*Info, error) {
 		Path:             wtPath,
 		IsWorktree:       isWorktree,
 		MainWorktreePath: mainPath,
+		Repo:             repo,
 	}
 
 	return info, nil
@@ -0,0 +1,107 @@
+package worktree
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultNameTemplate names linked worktrees after their branch
+const DefaultNameTemplate = "{branch}"
+
+// namePlaceholders are the placeholders a name template can use
+var namePlaceholders = []string{"{repo}", "{branch}", "{dir}"}
+
+var (
+	nameTemplate = DefaultNameTemplate
+	nameResolver func(name, path, mainPath string) string
+)
+
+// SetNameTemplate sets the template linked worktrees are named with (the
+// worktree_name setting), e.g. "{repo}-{branch}". Placeholders:
+//   - {repo}: the main repository's directory name
+//   - {branch}: the branch
+//   - {dir}: the worktree's directory name
+//
+// An empty template restores DefaultNameTemplate. Main worktrees are always
+// named after their directory.
+func SetNameTemplate(template string) error {
+	if template == "" {
+		template = DefaultNameTemplate
+	}
+	if err := ValidateNameTemplate(template); err != nil {
+		return err
+	}
+	nameTemplate = template
+	return nil
+}
+
+// ValidateNameTemplate checks that a name template uses a placeholder, so
+// worktrees don't all get the same name
+func ValidateNameTemplate(template string) error {
+	for _, p := range namePlaceholders {
+		if strings.Contains(template, p) {
+			return nil
+		}
+	}
+	return fmt.Errorf("worktree name template %q must use {repo}, {branch} or {dir}", template)
+}
+
+// SetNameResolver sets a function that turns a worktree's name into one
+// that's unique, given the worktree's path and its main worktree's; grove's
+// resolves collisions with registered worktrees
+func SetNameResolver(resolve func(name, path, mainPath string) string) {
+	nameResolver = resolve
+}
+
+// NameFor returns the name of the linked worktree at path, checked out on
+// branch. mainPath is the main worktree's path, if known.
+func NameFor(mainPath, branch, path string) string {
+	return resolveName(TemplateName(mainPath, branch, path), path, mainPath)
+}
+
+// TemplateName returns the name the template gives a linked worktree,
+// without resolving collisions
+func TemplateName(mainPath, branch, path string) string {
+	// Without the main worktree, assume <worktrees_dir>/<repo>/<branch>
+	repo := filepath.Base(filepath.Dir(path))
+	if mainPath != "" {
+		repo = filepath.Base(mainPath)
+		if repo == ".bare" {
+			repo = filepath.Base(filepath.Dir(mainPath))
+		}
+	}
+
+	name := strings.NewReplacer(
+		"{repo}", Sanitize(repo),
+		"{branch}", Sanitize(branch),
+		"{dir}", Sanitize(filepath.Base(path)),
+	).Replace(nameTemplate)
+	return Sanitize(name)
+}
+
+// MainName returns the name of the main worktree at path: its directory's
+// name
+func MainName(path string) string {
+	return resolveName(Sanitize(filepath.Base(path)), path, path)
+}
+
+func resolveName(name, path, mainPath string) string {
+	if nameResolver == nil {
+		return name
+	}
+	if mainPath == "" {
+		mainPath = path
+	}
+	return nameResolver(name, path, mainPath)
+}
+
+// CollisionName returns the name given to a worktree whose name is taken:
+// the name with a suffix derived from the worktree's path, so the same
+// worktree always gets the same name
+func CollisionName(name, path string) string {
+	sum := sha256.Sum256([]byte(path))
+	return name + "-" + hex.EncodeToString(sum[:])[:4]
+}
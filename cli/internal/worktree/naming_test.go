@@ -0,0 +1,87 @@
+package worktree
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"feature/auth", "feature-auth"},
+		{"feature/user-management", "feature-user-management"},
+		{"FEATURE/AUTH", "feature-auth"},
+		{"fix_bug_123", "fix-bug-123"},
+		{"release/v1.0.0", "release-v1-0-0"},
+		{"main", "main"},
+		{"feature/test space", "feature-testspace"},
+		{"///", "default"},
+	}
+
+	for _, tt := range tests {
+		if got := Sanitize(tt.input); got != tt.expected {
+			t.Errorf("Sanitize(%q) = %q; want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestTemplateName(t *testing.T) {
+	defer SetNameTemplate("") //nolint:errcheck // Restores the default
+
+	tests := []struct {
+		template string
+		mainPath string
+		expected string
+	}{
+		{"", "/code/api", "user-feature-x"},
+		{"{repo}-{branch}", "/code/api", "api-user-feature-x"},
+		{"{repo}-{branch}", "/code/api/.bare", "api-user-feature-x"},
+		{"{repo}-{branch}", "", "worktrees-user-feature-x"},
+		{"{dir}", "/code/api", "feature-x-wt"},
+	}
+
+	for _, tt := range tests {
+		if err := SetNameTemplate(tt.template); err != nil {
+			t.Fatalf("SetNameTemplate(%q): %v", tt.template, err)
+		}
+		if got := TemplateName(tt.mainPath, "user/feature_x", "/code/worktrees/feature_x.wt"); got != tt.expected {
+			t.Errorf("template %q: TemplateName = %q; want %q", tt.template, got, tt.expected)
+		}
+	}
+}
+
+func TestSetNameTemplate_Invalid(t *testing.T) {
+	defer SetNameTemplate("") //nolint:errcheck // Restores the default
+
+	if err := SetNameTemplate("fixed"); err == nil {
+		t.Error("expected an error for a template without placeholders")
+	}
+	if nameTemplate != DefaultNameTemplate {
+		t.Errorf("invalid template was applied: %q", nameTemplate)
+	}
+}
+
+func TestNameFor_Resolver(t *testing.T) {
+	defer SetNameResolver(nil)
+
+	SetNameResolver(func(name, path, mainPath string) string {
+		if name == "feature-x" && path != "/code/api-feature-x" {
+			return CollisionName(name, path)
+		}
+		return name
+	})
+
+	// The registered worktree keeps the name
+	if got := NameFor("/code/api", "feature-x", "/code/api-feature-x"); got != "feature-x" {
+		t.Errorf("NameFor = %q; want feature-x", got)
+	}
+	got := NameFor("/code/api", "feature_x", "/code/api-feature_x")
+	if !strings.HasPrefix(got, "feature-x-") || got != NameFor("/code/api", "feature_x", "/code/api-feature_x") {
+		t.Errorf("NameFor = %q; want a deterministic suffixed name", got)
+	}
+	if got == CollisionName("feature-x", "/code/other") {
+		t.Error("suffix should depend on the path")
+	}
+}
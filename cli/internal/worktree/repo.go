@@ -0,0 +1,92 @@
+package worktree
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/go-git/go-git/v5/config"
+	"github.com/iheanyi/grove/internal/execx"
+	"github.com/iheanyi/grove/internal/git"
+)
+
+// scpLikeURL matches scp-like git URLs, e.g. git@github.com:org/app.git
+var scpLikeURL = regexp.MustCompile(`^(?:[^@/]+@)?([^:/]+):(.+)$`)
+
+// RepoID identifies the repository the worktree at path belongs to: its
+// origin remote's URL, normalized (e.g. "github.com/org/app"), or mainPath
+// (the main worktree's path) when it has no origin. Worktrees of one
+// repository, and clones of it, share an ID.
+func RepoID(path, mainPath string) string {
+	if id := NormalizeRemoteURL(originURL(path)); id != "" {
+		return id
+	}
+	return mainPath
+}
+
+// originURL returns the URL of the origin remote of the repository the
+// worktree at path belongs to. It's read from the config file in the
+// repository's git directory, shared by all its worktrees, falling back to
+// git when path isn't the top of a worktree (e.g. a bare repository).
+func originURL(path string) string {
+	if gitDir := git.CommonDir(path); gitDir != "" {
+		if f, err := os.Open(filepath.Join(gitDir, "config")); err == nil {
+			defer f.Close()
+			if cfg, err := config.ReadConfig(f); err == nil {
+				if remote := cfg.Remotes["origin"]; remote != nil && len(remote.URLs) > 0 {
+					return remote.URLs[0]
+				}
+				return ""
+			}
+		}
+	}
+
+	cmd := execx.Query("git", "config", "--get", "remote.origin.url")
+	cmd.Dir = path
+	output, err := execx.OS.Output(context.Background(), cmd)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// NormalizeRemoteURL turns a git remote URL into host/path form, e.g.
+// "https://github.com/org/app.git" and "git@github.com:org/app.git" both
+// become "github.com/org/app"
+func NormalizeRemoteURL(url string) string {
+	if scheme, rest, ok := strings.Cut(url, "://"); ok {
+		if scheme == "file" {
+			return rest
+		}
+		// Drop the user, e.g. ssh://git@github.com/org/app
+		if at := strings.Index(rest, "@"); at >= 0 && !strings.Contains(rest[:at], "/") {
+			rest = rest[at+1:]
+		}
+		url = rest
+	} else if m := scpLikeURL.FindStringSubmatch(url); m != nil && !strings.HasPrefix(url, "/") {
+		url = m[1] + "/" + m[2]
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(url, "/"), ".git")
+}
+
+// RepoName returns the short name of a repository ID, e.g. "app"
+func RepoName(id string) string {
+	return strings.TrimSuffix(path.Base(strings.TrimSuffix(id, "/")), ".git")
+}
+
+// DistinctName returns the name for a worktree whose name is taken by a
+// worktree in otherRepo: namespaced by its repository (<repo>-<name>) when
+// that's another repository and the namespaced name is free, otherwise
+// CollisionName
+func DistinctName(name, path, repo, otherRepo string, taken func(string) bool) string {
+	if repo != "" && otherRepo != "" && repo != otherRepo {
+		namespaced := Sanitize(RepoName(repo) + "-" + name)
+		if !taken(namespaced) {
+			return namespaced
+		}
+	}
+	return CollisionName(name, path)
+}
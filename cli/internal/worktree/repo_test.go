@@ -0,0 +1,67 @@
+package worktree
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestNormalizeRemoteURL(t *testing.T) {
+	tests := map[string]string{
+		"https://github.com/org/app.git":        "github.com/org/app",
+		"https://github.com/org/app":            "github.com/org/app",
+		"git@github.com:org/app.git":            "github.com/org/app",
+		"ssh://git@github.com/org/app.git":      "github.com/org/app",
+		"https://user@gitlab.com/group/sub/app": "gitlab.com/group/sub/app",
+		"/srv/git/app.git":                      "/srv/git/app",
+		"file:///srv/git/app.git":               "/srv/git/app.git",
+	}
+	for url, want := range tests {
+		if got := NormalizeRemoteURL(url); got != want {
+			t.Errorf("NormalizeRemoteURL(%q) = %q; want %q", url, got, want)
+		}
+	}
+}
+
+func TestRepoName(t *testing.T) {
+	for id, want := range map[string]string{
+		"github.com/org/app": "app",
+		"/code/app":          "app",
+		"/srv/git/app.git":   "app",
+	} {
+		if got := RepoName(id); got != want {
+			t.Errorf("RepoName(%q) = %q; want %q", id, got, want)
+		}
+	}
+}
+
+func TestDistinctName(t *testing.T) {
+	none := func(string) bool { return false }
+
+	if got := DistinctName("main", "/code/web", "github.com/org/web", "github.com/org/api", none); got != "web-main" {
+		t.Errorf("other repo: got %q, want web-main", got)
+	}
+	if got := DistinctName("feature", "/code/api-feature2", "github.com/org/api", "github.com/org/api", none); got != CollisionName("feature", "/code/api-feature2") {
+		t.Errorf("same repo: got %q, want the suffixed name", got)
+	}
+	taken := func(n string) bool { return n == "web-main" }
+	if got := DistinctName("main", "/code/web", "github.com/org/web", "github.com/org/api", taken); got != CollisionName("main", "/code/web") {
+		t.Errorf("namespaced name taken: got %q, want the suffixed name", got)
+	}
+}
+
+func TestRepoID(t *testing.T) {
+	main := gitRepo(t)
+	linked := filepath.Join(t.TempDir(), "feature")
+	run(t, main, "worktree", "add", "-q", "-b", "feature", linked)
+
+	if got := RepoID(linked, main); got != main {
+		t.Errorf("RepoID without origin = %q, want the main path %q", got, main)
+	}
+
+	run(t, main, "remote", "add", "origin", "git@github.com:org/app.git")
+	for _, path := range []string{main, linked} {
+		if got := RepoID(path, main); got != "github.com/org/app" {
+			t.Errorf("RepoID(%s) = %q, want github.com/org/app", path, got)
+		}
+	}
+}